@@ -9,6 +9,14 @@ func newAuthCmd(globals *globalOptions) *cobra.Command {
 	}
 
 	cmd.AddCommand(newAuthLoginCmd(globals))
+	cmd.AddCommand(newAuthListCmd())
+	cmd.AddCommand(newAuthLogoutCmd(globals))
+	cmd.AddCommand(newAuthRenameCmd())
+	cmd.AddCommand(newAuthDefaultsCmd(globals))
+	cmd.AddCommand(newAuthEndpointCmd(globals))
+	cmd.AddCommand(newAuthLimitsCmd(globals))
+	cmd.AddCommand(newAuthExportCmd(globals))
+	cmd.AddCommand(newAuthImportCmd())
 
 	return cmd
 }
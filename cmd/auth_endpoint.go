@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/render"
+)
+
+func newAuthEndpointCmd(globals *globalOptions) *cobra.Command {
+	var (
+		baseURL  string
+		version  string
+		proxy    string
+		caBundle string
+		headers  []string
+		insecure bool
+		reset    bool
+		format   = formatTable
+	)
+
+	cmd := &cobra.Command{
+		Use:   "endpoint",
+		Short: "Show or set the base URL, headers, proxy, TLS trust, and Notion-Version the active profile's client uses",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			endpoint, err := config.LoadEndpoint(globals.profile)
+			if err != nil {
+				return fmt.Errorf("load endpoint: %w", err)
+			}
+			flags := cmd.Flags()
+			changed := reset || flags.Changed("base-url") || flags.Changed("header") || flags.Changed("proxy") ||
+				flags.Changed("ca-bundle") || flags.Changed("insecure-skip-verify")
+			if reset {
+				endpoint = config.Endpoint{}
+			}
+			if flags.Changed("base-url") {
+				endpoint.BaseURL = strings.TrimSpace(baseURL)
+			}
+			if flags.Changed("proxy") {
+				endpoint.ProxyURL = strings.TrimSpace(proxy)
+			}
+			if flags.Changed("ca-bundle") {
+				endpoint.CABundle = strings.TrimSpace(caBundle)
+			}
+			if flags.Changed("insecure-skip-verify") {
+				endpoint.InsecureSkipVerify = insecure
+			}
+			for _, header := range headers {
+				name, value, ok := strings.Cut(header, "=")
+				if !ok || strings.TrimSpace(name) == "" {
+					return fmt.Errorf("--header %q must be Name=Value", header)
+				}
+				if endpoint.Headers == nil {
+					endpoint.Headers = map[string]string{}
+				}
+				name = strings.TrimSpace(name)
+				deleteHeader(endpoint.Headers, name)
+				if value != "" {
+					endpoint.Headers[name] = value
+				}
+			}
+			if changed {
+				if err := config.SaveEndpoint(globals.profile, endpoint); err != nil {
+					return fmt.Errorf("save endpoint: %w", err)
+				}
+				if endpoint, err = config.LoadEndpoint(globals.profile); err != nil {
+					return fmt.Errorf("load endpoint: %w", err)
+				}
+			}
+			if endpoint.InsecureSkipVerify {
+				safeLog(cmd.ErrOrStderr(), "Warning: profile %q does not verify TLS certificates", globals.profile)
+			}
+			if cmd.Flags().Changed("notion-version") {
+				if err := config.SaveVersion(globals.profile, strings.TrimSpace(version)); err != nil {
+					return fmt.Errorf("save Notion-Version: %w", err)
+				}
+			}
+			if version, err = config.LoadVersion(globals.profile); err != nil {
+				return fmt.Errorf("load Notion-Version: %w", err)
+			}
+
+			switch format {
+			case formatJSON:
+				return writeJSON(cmd.Context(), cmd.OutOrStdout(), map[string]any{
+					"base_url":             endpoint.BaseURL,
+					"headers":              endpoint.Headers,
+					"proxy_url":            endpoint.ProxyURL,
+					"ca_bundle":            endpoint.CABundle,
+					"insecure_skip_verify": endpoint.InsecureSkipVerify,
+					"notion_version":       version,
+				})
+			case formatTable:
+				base := endpoint.BaseURL
+				if base == "" {
+					base = "(default) https://api.notion.com/v1"
+				}
+				proxyURL := endpoint.ProxyURL
+				if proxyURL == "" {
+					proxyURL = "(environment) HTTPS_PROXY"
+				}
+				caBundle := endpoint.CABundle
+				if caBundle == "" {
+					caBundle = "(system roots)"
+				}
+				rows := [][]string{
+					{"base_url", base},
+					{"notion_version", version},
+					{"proxy_url", proxyURL},
+					{"ca_bundle", caBundle},
+					{"insecure_skip_verify", strconv.FormatBool(endpoint.InsecureSkipVerify)},
+				}
+				for _, name := range render.SortedKeys(endpoint.Headers) {
+					rows = append(rows, []string{"header " + name, endpoint.Headers[name]})
+				}
+				return render.Table(cmd.OutOrStdout(), []string{"Setting", "Value"}, rows)
+			default:
+				return fmt.Errorf("unknown format %q (expected json or table)", format)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&baseURL, "base-url", "", "API base URL, e.g. https://gateway.example.com/notion/v1 (empty resets)")
+	cmd.Flags().StringVar(&version, "notion-version", "", "Pin a different Notion-Version (empty resets to the default)")
+	cmd.Flags().StringArrayVar(&headers, "header", nil, "Extra request header Name=Value (repeatable; Name= removes it)")
+	cmd.Flags().StringVar(&proxy, "proxy", "", "Proxy URL (http, https, or socks5) for every request (empty resets to HTTPS_PROXY)")
+	cmd.Flags().StringVar(&caBundle, "ca-bundle", "", "PEM file of extra CAs to trust, e.g. a TLS-intercepting firewall's root")
+	cmd.Flags().BoolVar(&insecure, "insecure-skip-verify", false, "Skip TLS certificate verification (unsafe; prefer --ca-bundle)")
+	cmd.Flags().BoolVar(&reset, "clear", false, "Remove all endpoint settings except Notion-Version before applying other flags")
+	formatFlag(cmd, &format, "Output format", formatJSON, formatTable)
+
+	return cmd
+}
+
+func deleteHeader(headers map[string]string, name string) {
+	for existing := range headers {
+		if strings.EqualFold(existing, name) {
+			delete(headers, existing)
+		}
+	}
+}
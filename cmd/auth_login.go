@@ -14,9 +14,11 @@ import (
 )
 
 type loginOptions struct {
-	notionVersion string
-	token         string
-	oauth         bool
+	oauthOpts       oauthOptions
+	notionVersion   string
+	token           string
+	credentialStore string
+	oauth           bool
 }
 
 const notionVersionFlagHelp = "Override the Notion API version for the profile"
@@ -27,8 +29,13 @@ func newAuthLoginCmd(globals *globalOptions) *cobra.Command {
 	}
 
 	cmd := &cobra.Command{
-		Use:           "login",
-		Short:         "Store a Notion integration token securely",
+		Use:   "login",
+		Short: "Store a Notion integration token securely, or authorize a public integration via OAuth",
+		Long: `Store a Notion integration token securely, or authorize a public integration via OAuth.
+
+Run without flags on a terminal, login is a guided wizard: choose a token or OAuth, paste
+the token (checked against Notion as you enter it), name the profile, and confirm the
+workspace before anything is saved.`,
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, _ []string) error {
@@ -37,20 +44,38 @@ func newAuthLoginCmd(globals *globalOptions) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&opts.token, "token", "", "Notion integration token to store (prompted if omitted)")
-	cmd.Flags().BoolVar(&opts.oauth, "oauth", false, "Use OAuth device flow instead of a manual token")
+	cmd.Flags().BoolVar(&opts.oauth, "oauth", false, "Authorize a public integration in the browser instead of pasting a token")
 	cmd.Flags().StringVar(
 		&opts.notionVersion,
 		"notion-version",
 		opts.notionVersion,
 		notionVersionFlagHelp,
 	)
+	cmd.Flags().StringVar(
+		&opts.credentialStore,
+		"credential-store",
+		"",
+		"Where tokens are kept from now on: keyring|file (file is encrypted with NOTIONCTL_PASSPHRASE or a prompt)",
+	)
+	opts.oauthOpts.register(cmd)
 
 	return cmd
 }
 
 func runAuthLogin(cmd *cobra.Command, globals *globalOptions, opts *loginOptions) error {
+	if wantsLoginWizard(cmd) {
+		return runLoginWizard(cmd, globals, opts)
+	}
+	if opts.credentialStore != "" {
+		if err := config.SetCredentialStore(opts.credentialStore); err != nil {
+			return fmt.Errorf("set credential store: %w", err)
+		}
+	}
 	if opts.oauth {
-		return errors.New("oauth login flow is not implemented yet; supply --token")
+		if opts.token != "" {
+			return errors.New("--oauth and --token cannot be combined")
+		}
+		return runOAuthLogin(cmd, globals, opts)
 	}
 
 	token := strings.TrimSpace(opts.token)
@@ -108,3 +133,28 @@ func promptForToken(cmd *cobra.Command) (string, error) {
 	}
 	return strings.TrimSpace(string(data)), nil
 }
+
+// promptPassphrase asks for the file credential store passphrase on the terminal. Without
+// a terminal it fails, so scripts must set NOTIONCTL_PASSPHRASE.
+func promptPassphrase() (string, error) {
+	return promptSecret("Credential store passphrase", config.PassphraseEnv)
+}
+
+// promptSecret reads a hidden value from the terminal, naming envVar as the alternative
+// when there is no terminal.
+func promptSecret(label, envVar string) (string, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("no terminal to prompt on; set %s", envVar)
+	}
+	if _, err := fmt.Fprintf(os.Stderr, "%s: ", label); err != nil {
+		return "", fmt.Errorf("prompt passphrase: %w", err)
+	}
+	data, err := term.ReadPassword(int(os.Stdin.Fd()))
+	if _, ferr := fmt.Fprintln(os.Stderr); ferr != nil {
+		return "", fmt.Errorf("prompt passphrase: %w", ferr)
+	}
+	if err != nil {
+		return "", fmt.Errorf("read passphrase: %w", err)
+	}
+	return string(data), nil
+}
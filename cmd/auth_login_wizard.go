@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+var errLoginCancelled = errors.New("login cancelled")
+
+// verifyToken checks a token against GET /v1/users/me; tests replace it.
+var verifyToken = func(ctx context.Context, token, version string) (notion.BotUser, error) {
+	client := notion.NewClient(notion.ClientConfig{Token: token, NotionVersion: version, MaxRetries: -1})
+	return client.RetrieveBotUser(ctx)
+}
+
+// wantsLoginWizard reports whether auth login was run bare on a terminal.
+func wantsLoginWizard(cmd *cobra.Command) bool {
+	if cmd.LocalFlags().NFlag() > 0 || !interactiveOutput(cmd) {
+		return false
+	}
+	f, ok := cmd.InOrStdin().(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// runLoginWizard is auth login without flags on a terminal.
+func runLoginWizard(cmd *cobra.Command, globals *globalOptions, opts *loginOptions) error {
+	existing, err := config.ListProfiles()
+	if err != nil {
+		return fmt.Errorf("list profiles: %w", err)
+	}
+	version := config.DefaultNotionVersion()
+	wizard := &loginWizard{
+		in:     bufio.NewReader(cmd.InOrStdin()),
+		out:    cmd.ErrOrStderr(),
+		secret: terminalSecret(cmd),
+		verify: verifyToken,
+		authorize: func(clientID, clientSecret string) (notion.OAuthToken, error) {
+			token, _, err := authorizeOAuth(cmd, opts, clientID, clientSecret)
+			return token, err
+		},
+	}
+	login, err := wizard.run(cmd.Context(), loginWizardInput{
+		profile:      globals.profile,
+		profileSet:   cmd.Flags().Changed("profile"),
+		version:      version,
+		clientID:     os.Getenv(envOAuthClientID),
+		clientSecret: os.Getenv(envOAuthClientSecret),
+		existing:     existing,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := config.SaveToken(login.profile, login.token, version); err != nil {
+		return fmt.Errorf("save credentials: %w", err)
+	}
+	if err := config.SaveWorkspace(login.profile, login.workspace); err != nil {
+		return fmt.Errorf("save workspace: %w", err)
+	}
+	if _, err := fmt.Fprintf(
+		cmd.OutOrStdout(),
+		"Saved credentials for profile %q (workspace %q, Notion-Version %s)\n",
+		login.profile,
+		login.workspace.Name,
+		version,
+	); err != nil {
+		return fmt.Errorf("write confirmation: %w", err)
+	}
+	if login.profile != globals.profile {
+		safeLog(cmd.ErrOrStderr(), "Use it with --profile %s.", login.profile)
+	}
+	return nil
+}
+
+// terminalSecret reads a hidden value from the command's terminal, prompting on stderr.
+func terminalSecret(cmd *cobra.Command) func(label string) (string, error) {
+	return func(label string) (string, error) {
+		f, ok := cmd.InOrStdin().(*os.File)
+		if !ok {
+			return "", errors.New("no terminal to prompt on")
+		}
+		if _, err := fmt.Fprintf(cmd.ErrOrStderr(), "%s: ", label); err != nil {
+			return "", fmt.Errorf("prompt: %w", err)
+		}
+		data, err := term.ReadPassword(int(f.Fd()))
+		safeLog(cmd.ErrOrStderr(), "")
+		if err != nil {
+			return "", fmt.Errorf("read %s: %w", strings.ToLower(label), err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+}
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type loginWizardInput struct {
+	profile      string
+	profileSet   bool
+	version      string
+	clientID     string
+	clientSecret string
+	existing     []config.Profile
+}
+
+type wizardLogin struct {
+	workspace config.Workspace
+	profile   string
+	token     string
+}
+
+// loginWizard asks how to authenticate, gets a token that Notion accepts, names the
+// profile, and confirms the workspace before anything is saved.
+type loginWizard struct {
+	in        *bufio.Reader
+	out       io.Writer
+	secret    func(label string) (string, error)
+	verify    func(ctx context.Context, token, version string) (notion.BotUser, error)
+	authorize func(clientID, clientSecret string) (notion.OAuthToken, error)
+}
+
+func (w *loginWizard) run(ctx context.Context, input loginWizardInput) (wizardLogin, error) {
+	safeLog(w.out, "How do you want to sign in to Notion?")
+	safeLog(w.out, "  1) Paste an internal integration token")
+	safeLog(w.out, "  2) Authorize a public integration in the browser (OAuth)")
+	choice, err := w.prompt("Choice [1]: ")
+	if err != nil {
+		return wizardLogin{}, err
+	}
+
+	var login wizardLogin
+	integration := ""
+	switch choice {
+	case "", "1":
+		me, token, tokenErr := w.askToken(ctx, input.version)
+		if tokenErr != nil {
+			return wizardLogin{}, tokenErr
+		}
+		login.token = token
+		login.workspace = config.Workspace{ID: me.Bot.WorkspaceID, Name: me.Bot.WorkspaceName, BotID: me.ID}
+		integration = me.Name
+	case "2":
+		token, oauthErr := w.oauth(input)
+		if oauthErr != nil {
+			return wizardLogin{}, oauthErr
+		}
+		login.token = token.AccessToken
+		login.workspace = config.Workspace{ID: token.WorkspaceID, Name: token.WorkspaceName, BotID: token.BotID}
+	default:
+		return wizardLogin{}, fmt.Errorf("unknown choice %q", choice)
+	}
+
+	login.profile = input.profile
+	if !input.profileSet {
+		if login.profile, err = w.askProfile(input.profile); err != nil {
+			return wizardLogin{}, err
+		}
+	}
+
+	replacing := false
+	for _, p := range input.existing {
+		if p.Name == login.profile && p.HasToken {
+			replacing = true
+		}
+	}
+	summary := fmt.Sprintf("workspace %q", login.workspace.Name)
+	if integration != "" {
+		summary = fmt.Sprintf("integration %q in %s", integration, summary)
+	}
+	question := fmt.Sprintf("Save %s as profile %q? [Y/n]: ", summary, login.profile)
+	if replacing {
+		question = fmt.Sprintf("Profile %q already has a token. Replace it with %s? [y/N]: ", login.profile, summary)
+	}
+	answer, err := w.prompt(question)
+	if err != nil {
+		return wizardLogin{}, err
+	}
+	if !confirmed(answer, !replacing) {
+		return wizardLogin{}, errLoginCancelled
+	}
+	return login, nil
+}
+
+// askToken asks until Notion accepts the token; a blank token cancels.
+func (w *loginWizard) askToken(ctx context.Context, version string) (notion.BotUser, string, error) {
+	for {
+		token, err := w.secret("Notion token (ntn_… or secret_…, blank to cancel)")
+		if err != nil {
+			return notion.BotUser{}, "", err
+		}
+		if token == "" {
+			return notion.BotUser{}, "", errLoginCancelled
+		}
+		me, err := w.verify(ctx, token, version)
+		if err != nil {
+			safeLog(w.out, "Notion rejected the token: %v", err)
+			continue
+		}
+		workspace := me.Bot.WorkspaceName
+		if workspace == "" {
+			workspace = "(unnamed)"
+		}
+		safeLog(w.out, "Token works: integration %q in workspace %q.", me.Name, workspace)
+		return me, token, nil
+	}
+}
+
+func (w *loginWizard) oauth(input loginWizardInput) (notion.OAuthToken, error) {
+	clientID, clientSecret := input.clientID, input.clientSecret
+	var err error
+	if clientID == "" {
+		if clientID, err = w.prompt("OAuth client ID: "); err != nil {
+			return notion.OAuthToken{}, err
+		}
+	}
+	if clientSecret == "" {
+		if clientSecret, err = w.secret("OAuth client secret"); err != nil {
+			return notion.OAuthToken{}, err
+		}
+	}
+	return w.authorize(clientID, clientSecret)
+}
+
+// askProfile suggests the --profile value and rejects names config.yaml keys cannot hold.
+func (w *loginWizard) askProfile(suggested string) (string, error) {
+	for {
+		name, err := w.prompt(fmt.Sprintf("Profile name [%s]: ", suggested))
+		if err != nil {
+			return "", err
+		}
+		if name == "" {
+			return suggested, nil
+		}
+		if strings.ContainsAny(name, `./\ `) {
+			safeLog(w.out, "Profile names cannot contain spaces, dots, or slashes.")
+			continue
+		}
+		return name, nil
+	}
+}
+
+func (w *loginWizard) prompt(label string) (string, error) {
+	if _, err := fmt.Fprint(w.out, label); err != nil {
+		return "", fmt.Errorf("write prompt: %w", err)
+	}
+	line, err := w.in.ReadString('\n')
+	if errors.Is(err, io.EOF) && line == "" {
+		return "", errLoginCancelled
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read answer: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func confirmed(answer string, defaultYes bool) bool {
+	switch strings.ToLower(answer) {
+	case "":
+		return defaultYes
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func newTestWizard(answers string, secrets ...string) (*loginWizard, *[]string) {
+	var verified []string
+	w := &loginWizard{
+		in:  bufio.NewReader(strings.NewReader(answers)),
+		out: io.Discard,
+		secret: func(string) (string, error) {
+			if len(secrets) == 0 {
+				return "", io.EOF
+			}
+			next := secrets[0]
+			secrets = secrets[1:]
+			return next, nil
+		},
+		verify: func(_ context.Context, token, _ string) (notion.BotUser, error) {
+			verified = append(verified, token)
+			if token != "ntn_good" {
+				return notion.BotUser{}, errors.New("401 unauthorized")
+			}
+			var me notion.BotUser
+			me.ID, me.Name = "bot1", "notionctl"
+			me.Bot.WorkspaceName = "Acme"
+			return me, nil
+		},
+		authorize: func(clientID, clientSecret string) (notion.OAuthToken, error) {
+			if clientID != "cid" || clientSecret != "shh" {
+				return notion.OAuthToken{}, errors.New("bad client")
+			}
+			return notion.OAuthToken{AccessToken: "ntn_oauth", WorkspaceName: "Beta", BotID: "bot2"}, nil
+		},
+	}
+	return w, &verified
+}
+
+func TestLoginWizardToken(t *testing.T) {
+	t.Parallel()
+
+	w, verified := newTestWizard("\nwork space\nwork\n\n", "ntn_bad", "ntn_good")
+	login, err := w.run(context.Background(), loginWizardInput{profile: "default"})
+	if err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if strings.Join(*verified, ",") != "ntn_bad,ntn_good" {
+		t.Fatalf("verified %v, want the rejected token retried", *verified)
+	}
+	want := wizardLogin{profile: "work", token: "ntn_good", workspace: config.Workspace{Name: "Acme", BotID: "bot1"}}
+	if login != want {
+		t.Fatalf("login = %+v, want %+v", login, want)
+	}
+}
+
+func TestLoginWizardOAuthKeepsProfileFlag(t *testing.T) {
+	t.Parallel()
+
+	w, _ := newTestWizard("2\ncid\ny\n", "shh")
+	login, err := w.run(context.Background(), loginWizardInput{
+		profile:    "team",
+		profileSet: true,
+		existing:   []config.Profile{{Name: "team", HasToken: true}},
+	})
+	if err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if login.profile != "team" || login.token != "ntn_oauth" || login.workspace.Name != "Beta" {
+		t.Fatalf("login = %+v", login)
+	}
+}
+
+func TestLoginWizardCancels(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		answers string
+		secrets []string
+		input   loginWizardInput
+	}{
+		"blank token":        {answers: "1\n", secrets: []string{""}},
+		"declined":           {answers: "1\n\nn\n", secrets: []string{"ntn_good"}},
+		"replace by default": {answers: "1\n\n\n", secrets: []string{"ntn_good"}, input: loginWizardInput{existing: []config.Profile{{Name: "default", HasToken: true}}}},
+	} {
+		tc.input.profile = "default"
+		w, _ := newTestWizard(tc.answers, tc.secrets...)
+		if _, err := w.run(context.Background(), tc.input); !errors.Is(err, errLoginCancelled) {
+			t.Errorf("%s: err = %v, want cancelled", name, err)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+const (
+	defaultOAuthRedirect = "http://localhost:8765/callback"
+	defaultOAuthTimeout  = 5 * time.Minute
+	oauthStateBytes      = 16
+
+	envOAuthClientID     = "NOTION_OAUTH_CLIENT_ID"
+	envOAuthClientSecret = "NOTION_OAUTH_CLIENT_SECRET" // #nosec G101 -- environment variable name, not a secret
+)
+
+//nolint:govet // fieldalignment: grouped with the other login options for readability.
+type oauthOptions struct {
+	clientID     string
+	clientSecret string
+	redirectURI  string
+	timeout      time.Duration
+	noBrowser    bool
+}
+
+// oauthClient builds the client used for the code exchange; tests point it at a fake server.
+var oauthClient = func(version string) *notion.Client {
+	return notion.NewClient(notion.ClientConfig{NotionVersion: version})
+}
+
+// openBrowser launches the system browser; tests replace it to follow the redirect directly.
+var openBrowser = func(target string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", target)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", target)
+	default:
+		cmd = exec.Command("xdg-open", target)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("open browser: %w", err)
+	}
+	return nil
+}
+
+func (opts *oauthOptions) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&opts.clientID,
+		"client-id",
+		"",
+		"OAuth client ID of your public integration (default: $"+envOAuthClientID+")",
+	)
+	cmd.Flags().StringVar(
+		&opts.clientSecret,
+		"client-secret",
+		"",
+		"OAuth client secret of your public integration (default: $"+envOAuthClientSecret+")",
+	)
+	cmd.Flags().StringVar(
+		&opts.redirectURI,
+		"redirect-uri",
+		defaultOAuthRedirect,
+		"Redirect URI registered for the integration; notionctl listens on its host and port",
+	)
+	cmd.Flags().DurationVar(&opts.timeout, "oauth-timeout", defaultOAuthTimeout, "How long to wait for the browser approval")
+	cmd.Flags().BoolVar(&opts.noBrowser, "no-browser", false, "Print the authorization URL instead of opening a browser")
+}
+
+func runOAuthLogin(cmd *cobra.Command, globals *globalOptions, opts *loginOptions) error {
+	clientID := firstNonEmpty(opts.oauthOpts.clientID, os.Getenv(envOAuthClientID))
+	clientSecret := firstNonEmpty(opts.oauthOpts.clientSecret, os.Getenv(envOAuthClientSecret))
+	token, version, err := authorizeOAuth(cmd, opts, clientID, clientSecret)
+	if err != nil {
+		return err
+	}
+	return saveOAuthLogin(cmd, globals.profile, token, version)
+}
+
+// authorizeOAuth runs the browser authorization and code exchange without saving anything.
+func authorizeOAuth(
+	cmd *cobra.Command,
+	opts *loginOptions,
+	clientID, clientSecret string,
+) (notion.OAuthToken, string, error) {
+	if clientID == "" || clientSecret == "" {
+		return notion.OAuthToken{}, "", fmt.Errorf(
+			"--oauth needs the integration's client ID and secret (--client-id/--client-secret or $%s/$%s)",
+			envOAuthClientID, envOAuthClientSecret)
+	}
+	redirect, err := parseOAuthRedirect(opts.oauthOpts.redirectURI)
+	if err != nil {
+		return notion.OAuthToken{}, "", err
+	}
+	version := strings.TrimSpace(opts.notionVersion)
+	if version == "" {
+		version = config.DefaultNotionVersion()
+	}
+
+	state, err := randomState()
+	if err != nil {
+		return notion.OAuthToken{}, "", err
+	}
+	ctx, cancel := context.WithTimeout(cmd.Context(), opts.oauthOpts.timeout)
+	defer cancel()
+
+	listener, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return notion.OAuthToken{}, "", fmt.Errorf("listen for oauth redirect on %s: %w", redirect.Host, err)
+	}
+	client := oauthClient(version)
+	authorizeURL := client.OAuthAuthorizeURL(clientID, redirect.String(), state)
+	safeLog(cmd.ErrOrStderr(), "Open this URL to authorize notionctl:\n  %s", authorizeURL)
+	if !opts.oauthOpts.noBrowser {
+		if err := openBrowser(authorizeURL); err != nil {
+			safeLog(cmd.ErrOrStderr(), "could not open a browser (%v); open the URL manually", err)
+		}
+	}
+
+	code, err := awaitOAuthCode(ctx, listener, redirect.Path, state)
+	if err != nil {
+		return notion.OAuthToken{}, "", err
+	}
+	token, err := client.ExchangeOAuthCode(ctx, clientID, clientSecret, code, redirect.String())
+	if err != nil {
+		return notion.OAuthToken{}, "", fmt.Errorf("exchange oauth code: %w", err)
+	}
+	return token, version, nil
+}
+
+// saveOAuthLogin stores an OAuth token and the workspace it was granted for.
+func saveOAuthLogin(cmd *cobra.Command, profile string, token notion.OAuthToken, version string) error {
+	if err := config.SaveToken(profile, token.AccessToken, version); err != nil {
+		return fmt.Errorf("save credentials: %w", err)
+	}
+	workspace := config.Workspace{ID: token.WorkspaceID, Name: token.WorkspaceName, BotID: token.BotID}
+	if err := config.SaveWorkspace(profile, workspace); err != nil {
+		return fmt.Errorf("save workspace: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(
+		cmd.OutOrStdout(),
+		"Saved credentials for profile %q (workspace %q, Notion-Version %s)\n",
+		profile,
+		token.WorkspaceName,
+		version,
+	); err != nil {
+		return fmt.Errorf("write confirmation: %w", err)
+	}
+	return nil
+}
+
+// parseOAuthRedirect accepts only plain-HTTP loopback redirects, which notionctl can serve.
+func parseOAuthRedirect(raw string) (*url.URL, error) {
+	redirect, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("parse --redirect-uri: %w", err)
+	}
+	host := redirect.Hostname()
+	loopback := host == "localhost"
+	if ip := net.ParseIP(host); ip != nil {
+		loopback = ip.IsLoopback()
+	}
+	if redirect.Scheme != "http" || !loopback || redirect.Port() == "" {
+		return nil, fmt.Errorf("--redirect-uri %q must be an http://localhost:<port>/... address", raw)
+	}
+	if redirect.Path == "" {
+		redirect.Path = "/"
+	}
+	return redirect, nil
+}
+
+// awaitOAuthCode serves the redirect on listener until the browser delivers a code for
+// state, the user denies access, or ctx ends.
+func awaitOAuthCode(ctx context.Context, listener net.Listener, path, state string) (string, error) {
+	type result struct {
+		code string
+		err  error
+	}
+	results := make(chan result, 1)
+	deliver := func(r result) {
+		select {
+		case results <- r:
+		default:
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("state") != state {
+			http.Error(w, "state mismatch; restart notionctl auth login --oauth", http.StatusBadRequest)
+			return
+		}
+		if reason := query.Get("error"); reason != "" {
+			http.Error(w, "Authorization was not granted. You can close this tab.", http.StatusForbidden)
+			deliver(result{err: fmt.Errorf("oauth authorization failed: %s", reason)})
+			return
+		}
+		code := query.Get("code")
+		if code == "" {
+			http.Error(w, "missing code", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte("notionctl is authorized. You can close this tab.\n"))
+		deliver(result{code: code})
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: serverReadTimeout}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			deliver(result{err: fmt.Errorf("oauth redirect listener: %w", err)})
+		}
+	}()
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	select {
+	case r := <-results:
+		return r.code, r.err
+	case <-ctx.Done():
+		return "", fmt.Errorf("waiting for oauth approval: %w", ctx.Err())
+	}
+}
+
+func randomState() (string, error) {
+	buf := make([]byte, oauthStateBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate oauth state: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAwaitOAuthCode(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	base := "http://" + listener.Addr().String() + "/callback"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	type result struct {
+		code string
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		code, err := awaitOAuthCode(ctx, listener, "/callback", "good-state")
+		done <- result{code, err}
+	}()
+
+	resp := getURL(t, base+"?state=forged&code=evil")
+	if resp != http.StatusBadRequest {
+		t.Fatalf("expected a forged state to be rejected, got %d", resp)
+	}
+	if resp := getURL(t, base+"?state=good-state&code=the-code"); resp != http.StatusOK {
+		t.Fatalf("expected the callback to succeed, got %d", resp)
+	}
+
+	r := <-done
+	if r.err != nil || r.code != "the-code" {
+		t.Fatalf("awaitOAuthCode = %q, %v", r.code, r.err)
+	}
+}
+
+func TestAwaitOAuthCodeDenied(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	errs := make(chan error, 1)
+	go func() {
+		_, err := awaitOAuthCode(ctx, listener, "/cb", "s")
+		errs <- err
+	}()
+
+	getURL(t, "http://"+listener.Addr().String()+"/cb?state=s&error=access_denied")
+	if err := <-errs; err == nil || !strings.Contains(err.Error(), "access_denied") {
+		t.Fatalf("expected access_denied error, got %v", err)
+	}
+}
+
+func TestParseOAuthRedirect(t *testing.T) {
+	if u, err := parseOAuthRedirect("http://localhost:8765/callback"); err != nil || u.Host != "localhost:8765" {
+		t.Fatalf("unexpected result %v, %v", u, err)
+	}
+	if u, err := parseOAuthRedirect("http://127.0.0.1:9000"); err != nil || u.Path != "/" {
+		t.Fatalf("unexpected result %v, %v", u, err)
+	}
+	for _, raw := range []string{"https://example.com/callback", "http://localhost/callback", "http://10.0.0.1:80/cb"} {
+		if _, err := parseOAuthRedirect(raw); err == nil {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+}
+
+func getURL(t *testing.T, target string) int {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, target, nil)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", target, err)
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/render"
+)
+
+func newAuthListCmd() *cobra.Command {
+	format := formatTable
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List stored profiles with their workspace and Notion-Version",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if format != formatJSON && format != formatTable {
+				return fmt.Errorf("unknown format %q (expected json or table)", format)
+			}
+			profiles, err := config.ListProfiles()
+			if err != nil {
+				return fmt.Errorf("list profiles: %w", err)
+			}
+			if format == formatJSON {
+				return writeJSON(cmd.Context(), cmd.OutOrStdout(), profiles)
+			}
+			rows := make([][]string, 0, len(profiles))
+			for _, p := range profiles {
+				token := "missing"
+				if p.HasToken {
+					token = "stored"
+				}
+				rows = append(rows, []string{p.Name, p.Workspace, p.NotionVersion, p.DefaultDataSource, token})
+			}
+			headers := []string{"Profile", "Workspace", "Notion-Version", "Default Data Source", "Token"}
+			return render.Table(cmd.OutOrStdout(), headers, rows)
+		},
+	}
+
+	formatFlag(cmd, &format, "Output format", formatJSON, formatTable)
+
+	return cmd
+}
+
+func newAuthLogoutCmd(globals *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "logout [profile]",
+		Short: "Delete a profile's stored token, settings, and cached schemas (default: --profile)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile := globals.profile
+			if len(args) == 1 {
+				profile = args[0]
+			}
+			if err := config.DeleteProfile(profile); err != nil {
+				return fmt.Errorf("log out: %w", err)
+			}
+			safeLog(cmd.ErrOrStderr(), "Removed profile %q", profile)
+			return nil
+		},
+	}
+}
+
+func newAuthRenameCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename <old> <new>",
+		Short: "Rename a profile, keeping its token, settings, and cached schemas",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := config.RenameProfile(args[0], args[1]); err != nil {
+				return fmt.Errorf("rename profile: %w", err)
+			}
+			safeLog(cmd.ErrOrStderr(), "Renamed profile %q to %q", args[0], args[1])
+			return nil
+		},
+	}
+}
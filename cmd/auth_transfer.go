@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/config"
+)
+
+const (
+	exportPassphraseEnv   = "NOTIONCTL_EXPORT_PASSPHRASE"
+	profileFilePermission = 0o600
+)
+
+// exportPassphrase is swapped out by tests.
+var exportPassphrase = func(confirm bool) (string, error) {
+	if env := os.Getenv(exportPassphraseEnv); env != "" {
+		return env, nil
+	}
+	pass, err := promptSecret("Export passphrase", exportPassphraseEnv)
+	if err != nil || !confirm {
+		return pass, err
+	}
+	again, err := promptSecret("Repeat passphrase", exportPassphraseEnv)
+	if err != nil {
+		return "", err
+	}
+	if again != pass {
+		return "", errors.New("passphrases do not match")
+	}
+	return pass, nil
+}
+
+func newAuthExportCmd(globals *globalOptions) *cobra.Command {
+	var out string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write the active profile, with its token encrypted by a passphrase, for auth import elsewhere",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			pass, err := exportPassphrase(true)
+			if err != nil {
+				return err
+			}
+			if pass == "" {
+				return errors.New("passphrase cannot be empty")
+			}
+			data, err := config.ExportProfile(globals.profile, pass)
+			if err != nil {
+				return fmt.Errorf("export profile: %w", err)
+			}
+			if out == "" || out == "-" {
+				if _, err := cmd.OutOrStdout().Write(data); err != nil {
+					return fmt.Errorf("write profile: %w", err)
+				}
+				return nil
+			}
+			if err := os.WriteFile(out, data, profileFilePermission); err != nil {
+				return fmt.Errorf("write profile: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&out, "out", "", "File to write instead of stdout")
+
+	return cmd
+}
+
+func newAuthImportCmd() *cobra.Command {
+	var (
+		name  string
+		force bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import [file]",
+		Short: "Restore a profile written by auth export (reads stdin without a file)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				data []byte
+				err  error
+			)
+			if len(args) == 0 || args[0] == "-" {
+				data, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				data, err = os.ReadFile(args[0])
+			}
+			if err != nil {
+				return fmt.Errorf("read profile: %w", err)
+			}
+			pass, err := exportPassphrase(false)
+			if err != nil {
+				return err
+			}
+			imported, err := config.ImportProfile(data, pass, name, force)
+			if err != nil {
+				return fmt.Errorf("import profile: %w", err)
+			}
+			safeLog(cmd.ErrOrStderr(), "Imported profile %q", imported)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "as", "", "Profile name to import as (default: the exported name)")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace an existing profile with the same name")
+
+	return cmd
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+
+	"github.com/yourorg/notionctl/internal/config"
+)
+
+func TestAuthExportImportRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	keyring.MockInit()
+	prev := exportPassphrase
+	exportPassphrase = func(bool) (string, error) { return "hunter2", nil }
+	t.Cleanup(func() { exportPassphrase = prev })
+
+	if err := config.SaveToken("work", "secret_work", "2025-10-01"); err != nil {
+		t.Fatalf("SaveToken returned error: %v", err)
+	}
+
+	var exported bytes.Buffer
+	root := newRootCmd(&globalOptions{profile: "default"})
+	root.SetArgs([]string{"auth", "export", "--profile", "work"})
+	root.SetOut(&exported)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("auth export returned error: %v", err)
+	}
+	if strings.Contains(exported.String(), "secret_work") {
+		t.Fatalf("export contains the plaintext token")
+	}
+
+	var log bytes.Buffer
+	root = newRootCmd(&globalOptions{profile: "default"})
+	root.SetArgs([]string{"auth", "import", "--as", "laptop"})
+	root.SetIn(&exported)
+	root.SetErr(&log)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("auth import returned error: %v", err)
+	}
+	token, version, err := config.LoadAuth("laptop")
+	if err != nil || token != "secret_work" || version != "2025-10-01" {
+		t.Fatalf("LoadAuth(laptop) = %q, %q, %v", token, version, err)
+	}
+}
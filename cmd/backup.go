@@ -0,0 +1,414 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/expand"
+	"github.com/yourorg/notionctl/internal/filelock"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+const (
+	backupFormat          = "notionctl-backup"
+	backupVersion         = 1
+	backupManifestFile    = "manifest.json"
+	backupFilePermissions = 0o600
+)
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type backupOptions struct {
+	databaseID   string
+	outPath      string
+	includeProps []string
+	excludeProps []string
+	exec         executionOptions
+	content      bool
+	skipEmpty    bool
+}
+
+// backupClient is the subset of the Notion client used by backups.
+type backupClient interface {
+	blockReader
+	dataSourceQuerier
+	ListDataSources(ctx context.Context, databaseID string) ([]notion.DataSource, error)
+	GetDataSource(ctx context.Context, dataSourceID string) (notion.DataSource, error)
+	RetrievePageProperty(
+		ctx context.Context,
+		pageID string,
+		propertyID string,
+		startCursor string,
+	) (notion.PropertyItemResponse, error)
+}
+
+// backupManifest describes an archive. It is the last entry, written once every data
+// source has been read.
+type backupManifest struct {
+	CreatedAt   time.Time          `json:"created_at"`
+	Format      string             `json:"format"`
+	DatabaseID  string             `json:"database_id"`
+	DataSources []backupDataSource `json:"data_sources"`
+	Version     int                `json:"version"`
+	Content     bool               `json:"content"`
+}
+
+// backupDataSource lists one data source in the manifest.
+type backupDataSource struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Pages int    `json:"pages"`
+}
+
+func newBackupCmd(globals *globalOptions) *cobra.Command {
+	opts := &backupOptions{exec: defaultExecutionOptions()}
+
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Archive a database's schemas, pages, and optionally page content as .tar.gz",
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(newIDValue(&opts.databaseID), "database-id", "Database ID or URL to back up")
+	cmd.Flags().StringVar(&opts.outPath, "out", "", "Path of the .tar.gz archive to write")
+	cmd.Flags().BoolVar(&opts.content, "content", false, "Include every page's block content")
+	cmd.Flags().StringSliceVar(
+		&opts.includeProps,
+		"include-props",
+		nil,
+		"Glob patterns of property names to back up (default: all)",
+	)
+	cmd.Flags().StringSliceVar(
+		&opts.excludeProps,
+		"exclude-props",
+		nil,
+		"Glob patterns of property names to leave out of the backup",
+	)
+	cmd.Flags().BoolVar(&opts.skipEmpty, "skip-empty", false, "Drop empty property values from backed up pages")
+	opts.exec.register(cmd, "")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("database-id"))
+	cobra.CheckErr(cmd.MarkFlagRequired("out"))
+
+	return cmd
+}
+
+func (opts *backupOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		client, err := opts.exec.buildClient(globals)
+		if err != nil {
+			return err
+		}
+
+		// The archive streams into a temporary file next to --out, which replaces it only
+		// once everything has been read.
+		var manifest backupManifest
+		err = filelock.WriteFileFunc(opts.outPath, backupFilePermissions, func(w io.Writer) error {
+			var err error
+			manifest, err = opts.backup(cmd.Context(), client, w, time.Now().UTC())
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("back up: %w", err)
+		}
+
+		pages := 0
+		for _, ds := range manifest.DataSources {
+			pages += ds.Pages
+		}
+		_, err = fmt.Fprintf(cmd.OutOrStdout(), "Backed up %d data sources and %d pages to %s\n",
+			len(manifest.DataSources), pages, opts.outPath)
+		return err
+	}
+}
+
+// backup writes the archive to w. Each data source gets a directory holding schema.json,
+// pages.jsonl with one page per line, and with --content blocks/<page-id>.json.
+func (opts *backupOptions) backup(
+	ctx context.Context,
+	client backupClient,
+	w io.Writer,
+	now time.Time,
+) (backupManifest, error) {
+	manifest := backupManifest{
+		CreatedAt:   now,
+		Format:      backupFormat,
+		DatabaseID:  opts.databaseID,
+		DataSources: []backupDataSource{},
+		Version:     backupVersion,
+		Content:     opts.content,
+	}
+	sources, err := client.ListDataSources(ctx, opts.databaseID)
+	if err != nil {
+		return manifest, fmt.Errorf("list data sources: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+	for _, ds := range sources {
+		pages, err := opts.backupDataSource(ctx, client, archive, ds.ID, now)
+		if err != nil {
+			return manifest, fmt.Errorf("back up data source %s: %w", ds.ID, err)
+		}
+		manifest.DataSources = append(manifest.DataSources, backupDataSource{ID: ds.ID, Name: ds.Name, Pages: pages})
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return manifest, fmt.Errorf("encode manifest: %w", err)
+	}
+	if err := writeTarFile(archive, backupManifestFile, data, now); err != nil {
+		return manifest, err
+	}
+	if err := archive.Close(); err != nil {
+		return manifest, fmt.Errorf("finish archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return manifest, fmt.Errorf("finish archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// backupDataSource archives one data source and returns how many pages it holds. The
+// listed data source may be abbreviated, so the full schema is fetched again.
+func (opts *backupOptions) backupDataSource(
+	ctx context.Context,
+	client backupClient,
+	archive *tar.Writer,
+	dataSourceID string,
+	now time.Time,
+) (int, error) {
+	dir := path.Join("data_sources", dataSourceID)
+	ds, err := client.GetDataSource(ctx, dataSourceID)
+	if err != nil {
+		return 0, fmt.Errorf("get data source: %w", err)
+	}
+	data, err := json.MarshalIndent(rawDataSource(ds), "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("encode schema: %w", err)
+	}
+	if err := writeTarFile(archive, path.Join(dir, "schema.json"), data, now); err != nil {
+		return 0, err
+	}
+
+	// As in ds export, the property filters are pushed down as filter_properties, so
+	// omitted properties are never fetched. The schema is archived whole.
+	idx := schema.NewIndex(ds)
+	names, err := selectPropertyNames(idx, opts.includeProps, opts.excludeProps)
+	if err != nil {
+		return 0, err
+	}
+	req := notion.QueryDataSourceRequest{PageSize: maxQueryPageSize}
+	if len(opts.includeProps) > 0 || len(opts.excludeProps) > 0 {
+		for _, name := range names {
+			id, _ := idx.IDForName(name)
+			req.FilterProperties = append(req.FilterProperties, id)
+		}
+	}
+
+	var pages []notion.Page
+	for page, err := range notion.QueryDataSourceIterWith(ctx, client.QueryDataSource, dataSourceID, req) {
+		if err != nil {
+			return 0, fmt.Errorf("query data source: %w", err)
+		}
+		pages = append(pages, page)
+	}
+	var relations []notion.PropertyReference
+	for _, name := range names {
+		if ref, _ := idx.ReferenceForName(name); ref.Type == relationType {
+			relations = append(relations, ref)
+		}
+	}
+	if err := expand.CompleteRelations(ctx, client, pages, relations, opts.exec.concurrency); err != nil {
+		return 0, fmt.Errorf("read relations: %w", err)
+	}
+	if opts.skipEmpty {
+		dropEmptyProperties(pages)
+	}
+
+	var lines bytes.Buffer
+	for _, page := range pages {
+		data, err := json.Marshal(rawPage(page))
+		if err != nil {
+			return 0, fmt.Errorf("encode page %s: %w", page.ID, err)
+		}
+		lines.Write(data)
+		lines.WriteByte('\n')
+	}
+	if err := writeTarFile(archive, path.Join(dir, "pages.jsonl"), lines.Bytes(), now); err != nil {
+		return 0, err
+	}
+
+	if opts.content {
+		if err := opts.backupContent(ctx, client, archive, dir, pages, now); err != nil {
+			return 0, err
+		}
+	}
+	return len(pages), nil
+}
+
+// backupContent reads the pages' block trees with up to --concurrency requests in flight and
+// archives them in page order. Pages are read --concurrency at a time, so only that many
+// trees are held in memory.
+func (opts *backupOptions) backupContent(
+	ctx context.Context,
+	client backupClient,
+	archive *tar.Writer,
+	dir string,
+	pages []notion.Page,
+	now time.Time,
+) error {
+	window := max(1, opts.exec.concurrency)
+	for start := 0; start < len(pages); start += window {
+		batch := pages[start:min(start+window, len(pages))]
+		trees := make([][]backupBlock, len(batch))
+		err := opts.exec.forEach(ctx, len(batch), func(ctx context.Context, i int) error {
+			blocks, err := fetchRawBlockTree(ctx, client, batch[i].ID)
+			if err != nil {
+				return fmt.Errorf("read content of page %s: %w", batch[i].ID, err)
+			}
+			trees[i] = blocks
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for i, page := range batch {
+			data, err := json.MarshalIndent(trees[i], "", "  ")
+			if err != nil {
+				return fmt.Errorf("encode content of page %s: %w", page.ID, err)
+			}
+			if err := writeTarFile(archive, path.Join(dir, "blocks", page.ID+".json"), data, now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// backupBlock is a block exactly as Notion returned it, whatever its type, with its children
+// nested under "children".
+type backupBlock struct {
+	raw      json.RawMessage
+	children []backupBlock
+}
+
+func (b backupBlock) MarshalJSON() ([]byte, error) {
+	if b.children == nil {
+		return b.raw, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b.raw, &fields); err != nil {
+		return nil, fmt.Errorf("decode block: %w", err)
+	}
+	children, err := json.Marshal(b.children)
+	if err != nil {
+		return nil, err
+	}
+	fields["children"] = children
+	return json.Marshal(fields)
+}
+
+// fetchRawBlockTree reads blockID's children as raw blocks. Unlike fetchBlockTree it
+// descends into every block with children, such as columns and synced blocks, except child
+// pages and databases, which are pages of their own.
+func fetchRawBlockTree(ctx context.Context, client blockReader, blockID string) ([]backupBlock, error) {
+	blocks, err := listBlockChildren(ctx, client, blockID)
+	if err != nil {
+		return nil, err
+	}
+	tree := make([]backupBlock, 0, len(blocks))
+	for _, block := range blocks {
+		node := backupBlock{raw: block.Raw}
+		if len(node.raw) == 0 {
+			if node.raw, err = json.Marshal(block); err != nil {
+				return nil, fmt.Errorf("encode block %s: %w", block.ID, err)
+			}
+		}
+		if block.HasChildren && block.ID != "" && block.Type != "child_page" && block.Type != "child_database" {
+			if node.children, err = fetchRawBlockTree(ctx, client, block.ID); err != nil {
+				return nil, err
+			}
+		}
+		tree = append(tree, node)
+	}
+	return tree, nil
+}
+
+// rawDataSource keeps each property's schema exactly as Notion returned it.
+func rawDataSource(ds notion.DataSource) map[string]any {
+	properties := make(map[string]any, len(ds.Properties))
+	for name, ref := range ds.Properties {
+		if len(ref.Raw) > 0 {
+			properties[name] = ref.Raw
+		} else {
+			properties[name] = ref
+		}
+	}
+	return map[string]any{
+		"id":               ds.ID,
+		"name":             ds.Name,
+		"database_id":      ds.DatabaseID,
+		"created_time":     ds.CreatedTime,
+		"last_edited_time": ds.LastEdited,
+		"properties":       properties,
+	}
+}
+
+// rawPage keeps each property value exactly as Notion returned it, except relations, whose
+// complete lists may have been read separately.
+func rawPage(page notion.Page) map[string]any {
+	properties := make(map[string]any, len(page.Properties))
+	for name, value := range page.Properties {
+		switch {
+		case value.Type == relationType:
+			relation := value.Relation
+			if relation == nil {
+				relation = []notion.RelationReference{}
+			}
+			properties[name] = map[string]any{"id": value.ID, "type": relationType, relationType: relation, "has_more": false}
+		case len(value.Raw) > 0:
+			properties[name] = value.Raw
+		default:
+			properties[name] = value
+		}
+	}
+	return map[string]any{
+		"object":           "page",
+		"id":               page.ID,
+		"parent":           page.Parent,
+		"icon":             page.Icon,
+		"created_time":     page.CreatedTime,
+		"last_edited_time": page.LastEditedTime,
+		"created_by":       page.CreatedBy,
+		"last_edited_by":   page.LastEditedBy,
+		"url":              page.URL,
+		"archived":         page.Archived,
+		"properties":       properties,
+	}
+}
+
+func writeTarFile(archive *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    backupFilePermissions,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := archive.WriteHeader(header); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	if _, err := archive.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+// fakeBackupClient serves the copy fixture as the only data source of a database.
+type fakeBackupClient struct {
+	*fakeCopyClient
+}
+
+func (f fakeBackupClient) ListDataSources(_ context.Context, _ string) ([]notion.DataSource, error) {
+	return []notion.DataSource{{ID: f.source.ID, Name: f.source.Name}}, nil
+}
+
+func readBackup(t *testing.T, archive []byte) map[string]string {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatalf("open gzip: %v", err)
+	}
+	files := map[string]string{}
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return files
+		}
+		if err != nil {
+			t.Fatalf("read tar: %v", err)
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("read %s: %v", header.Name, err)
+		}
+		files[header.Name] = string(data)
+	}
+}
+
+func TestBackupArchivesSchemaPagesAndContent(t *testing.T) {
+	client := fakeBackupClient{newFakeCopyClient(t)}
+	client.store("p1", []notion.Block{{Type: "divider", Divider: &struct{}{}}})
+	// Blocks notionctl does not model are archived as Notion returned them, children included.
+	for parent, raw := range map[string]string{
+		"p2":   `{"object":"block","id":"cols","type":"column_list","column_list":{},"has_children":true}`,
+		"cols": `{"object":"block","id":"col","type":"column","column":{},"has_children":true}`,
+		"col":  `{"object":"block","id":"eq","type":"equation","equation":{"expression":"e=mc^2"}}`,
+	} {
+		var block notion.Block
+		if err := json.Unmarshal([]byte(raw), &block); err != nil {
+			t.Fatalf("decode block: %v", err)
+		}
+		client.children[parent] = []notion.Block{block}
+	}
+	opts := &backupOptions{databaseID: "db", content: true, exec: defaultExecutionOptions()}
+
+	var archive bytes.Buffer
+	manifest, err := opts.backup(context.Background(), client, &archive, time.Unix(0, 0).UTC())
+	if err != nil {
+		t.Fatalf("backup returned error: %v", err)
+	}
+	if len(manifest.DataSources) != 1 || manifest.DataSources[0].Pages != 2 {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+
+	files := readBackup(t, archive.Bytes())
+	var schema struct {
+		Properties map[string]map[string]any `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(files["data_sources/src/schema.json"]), &schema); err != nil {
+		t.Fatalf("decode schema: %v", err)
+	}
+	if _, ok := schema.Properties["Stage"]["select"].(map[string]any)["options"]; !ok {
+		t.Fatalf("select options missing from schema: %v", schema.Properties["Stage"])
+	}
+
+	lines := strings.Split(strings.TrimSpace(files["data_sources/src/pages.jsonl"]), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"formula":{"type":"number","number":2}`) {
+		t.Fatalf("pages do not keep their raw property values: %v", lines)
+	}
+	if !strings.Contains(files["data_sources/src/blocks/p1.json"], `"divider"`) {
+		t.Fatalf("content missing for p1: %v", files)
+	}
+	if content := files["data_sources/src/blocks/p2.json"]; !strings.Contains(content, `"column_list"`) ||
+		!strings.Contains(content, `"equation"`) || !strings.Contains(content, `"expression": "e=mc^2"`) {
+		t.Fatalf("unmodeled blocks and their children were not archived raw: %s", content)
+	}
+	if !strings.Contains(files[backupManifestFile], `"format": "notionctl-backup"`) {
+		t.Fatalf("manifest missing: %q", files[backupManifestFile])
+	}
+}
+
+// filteringBackupClient records the queries a backup sends and, like Notion, returns only
+// the properties named by filter_properties.
+type filteringBackupClient struct {
+	fakeBackupClient
+	queries []notion.QueryDataSourceRequest
+}
+
+func (f *filteringBackupClient) QueryDataSource(
+	ctx context.Context,
+	dataSourceID string,
+	req notion.QueryDataSourceRequest,
+) (notion.QueryDataSourceResponse, error) {
+	f.queries = append(f.queries, req)
+	resp, err := f.fakeBackupClient.QueryDataSource(ctx, dataSourceID, req)
+	if err != nil || len(req.FilterProperties) == 0 {
+		return resp, err
+	}
+	resp.Results = slices.Clone(resp.Results)
+	for i, page := range resp.Results {
+		properties := map[string]notion.PropertyValue{}
+		for name, value := range page.Properties {
+			if slices.Contains(req.FilterProperties, value.ID) {
+				properties[name] = value
+			}
+		}
+		resp.Results[i].Properties = properties
+	}
+	return resp, nil
+}
+
+func TestBackupFiltersPropertiesAndSkipsEmptyValues(t *testing.T) {
+	client := &filteringBackupClient{fakeBackupClient: fakeBackupClient{newFakeCopyClient(t)}}
+	opts := &backupOptions{
+		databaseID:   "db",
+		excludeProps: []string{"sc*"},
+		skipEmpty:    true,
+		exec:         defaultExecutionOptions(),
+	}
+
+	var archive bytes.Buffer
+	if _, err := opts.backup(context.Background(), client, &archive, time.Unix(0, 0).UTC()); err != nil {
+		t.Fatalf("backup returned error: %v", err)
+	}
+	if len(client.queries) != 1 ||
+		strings.Join(client.queries[0].FilterProperties, ",") != "title,par,prj,stg" {
+		t.Fatalf("property filters were not pushed down: %+v", client.queries)
+	}
+
+	files := readBackup(t, archive.Bytes())
+	lines := strings.Split(strings.TrimSpace(files["data_sources/src/pages.jsonl"]), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("unexpected pages: %v", lines)
+	}
+	if strings.Contains(lines[0], `"Score"`) || strings.Contains(lines[0], `"Parent"`) ||
+		!strings.Contains(lines[0], `"Project"`) {
+		t.Fatalf("excluded or empty properties were archived: %s", lines[0])
+	}
+	if !strings.Contains(files["data_sources/src/schema.json"], `"Score"`) {
+		t.Fatalf("schema should stay whole: %s", files["data_sources/src/schema.json"])
+	}
+
+	opts.excludeProps = []string{"*"}
+	if _, err := opts.backup(context.Background(), client, io.Discard, time.Unix(0, 0).UTC()); err == nil ||
+		!strings.Contains(err.Error(), "exclude every property") {
+		t.Fatalf("expected every property to be excluded, got %v", err)
+	}
+}
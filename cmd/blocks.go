@@ -9,6 +9,8 @@ func newBlocksCmd(globals *globalOptions) *cobra.Command {
 	}
 
 	cmd.AddCommand(newBlocksAppendCmd(globals))
+	cmd.AddCommand(newBlocksReplaceCmd(globals))
+	cmd.AddCommand(newBlocksPruneCmd(globals))
 
 	return cmd
 }
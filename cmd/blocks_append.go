@@ -2,32 +2,48 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
-	"github.com/brittonhayes/notionmd"
 	"github.com/spf13/cobra"
 
+	"github.com/yourorg/notionctl/internal/markdown"
 	"github.com/yourorg/notionctl/internal/notion"
 )
 
+// maxAppendBlocks is the most children Notion accepts in one append request.
+const maxAppendBlocks = 100
+
+// plannedBlockPrefix starts the placeholder IDs a dry run prints for blocks it did not create.
+const plannedBlockPrefix = "new-block"
+
 type blocksAppendOptions struct {
 	markdownPath string
+	dataSource   string
+	dryRun       bool
 }
 
 func newBlocksAppendCmd(globals *globalOptions) *cobra.Command {
 	opts := &blocksAppendOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "append <block-or-page-id>",
+		Use:   "append <block-or-page-id|url>",
 		Short: "Append Markdown content as Notion blocks",
 		Args:  cobra.ExactArgs(1),
 		RunE:  opts.run(globals),
 	}
 
 	cmd.Flags().StringVar(&opts.markdownPath, "md", "", "Path to the Markdown file to append")
+	cmd.Flags().StringVar(
+		&opts.dataSource,
+		"data-source",
+		"",
+		"Data source ID or alias used to resolve unique ID handles such as TASK-123",
+	)
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print the PATCH requests that would be sent without sending them")
 
 	return cmd
 }
@@ -38,17 +54,29 @@ func (opts *blocksAppendOptions) run(globals *globalOptions) func(*cobra.Command
 			return errors.New("--md is required")
 		}
 
-		client, err := buildClient(globals.profile)
+		client, err := buildClient(globals)
 		if err != nil {
 			return err
 		}
+		if opts.dryRun {
+			client = dryRunClient(client, cmd.OutOrStdout())
+		}
 
 		ctx := cmd.Context()
-		count, err := opts.appendMarkdown(ctx, client, args[0])
+		targetID, err := resolveBlockRef(ctx, client, globals.profile, args[0], opts.dataSource)
+		if err != nil {
+			return err
+		}
+
+		count, err := opts.appendMarkdown(ctx, client, targetID)
 		if err != nil {
 			return err
 		}
 
+		if opts.dryRun {
+			safeLog(cmd.ErrOrStderr(), "Dry run: would append %d blocks", count)
+			return nil
+		}
 		if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Appended %d blocks\n", count); err != nil {
 			return fmt.Errorf("write output: %w", err)
 		}
@@ -58,7 +86,7 @@ func (opts *blocksAppendOptions) run(globals *globalOptions) func(*cobra.Command
 
 func (opts *blocksAppendOptions) appendMarkdown(
 	ctx context.Context,
-	client *notion.Client,
+	client pageBlockClient,
 	targetID string,
 ) (int, error) {
 	blocks, err := loadMarkdownBlocks(opts.markdownPath)
@@ -69,32 +97,96 @@ func (opts *blocksAppendOptions) appendMarkdown(
 		return 0, errors.New("no blocks generated from markdown")
 	}
 
-	if err := client.AppendBlockChildren(ctx, targetID, blocks); err != nil {
-		return 0, fmt.Errorf("append blocks: %w", err)
+	if err := appendBlocks(ctx, client, targetID, blocks); err != nil {
+		return 0, err
 	}
 	return len(blocks), nil
 }
 
-func loadMarkdownBlocks(path string) ([]notion.Block, error) {
-	data, err := os.ReadFile(path) // #nosec G304 -- reading user-supplied markdown by design
-	if err != nil {
-		return nil, fmt.Errorf("read markdown: %w", err)
-	}
+// appendBlocks appends blocks to parentID in requests of at most maxAppendBlocks children.
+// Notion accepts two levels of nesting per request, so deeper children are sent in
+// follow-up requests to their parent once it exists. A dry run's empty responses carry no
+// new blocks, so its follow-ups name their parents with plannedBlockID placeholders.
+func appendBlocks(ctx context.Context, client pageBlockClient, parentID string, blocks []notion.Block) error {
+	for start := 0; start < len(blocks); start += maxAppendBlocks {
+		batch := slices.Clone(blocks[start:min(start+maxAppendBlocks, len(blocks))])
+		deferred := make([][][]notion.Block, len(batch))
+		for i, block := range batch {
+			children := block.Children()
+			trimmed := make([]notion.Block, len(children))
+			for j, child := range children {
+				trimmed[j] = child
+				// Table rows must arrive with their table, and cannot nest further.
+				if grandchildren := child.Children(); len(grandchildren) > 0 && child.Table == nil {
+					if deferred[i] == nil {
+						deferred[i] = make([][]notion.Block, len(children))
+					}
+					deferred[i][j] = grandchildren
+					trimmed[j] = child.WithChildren(nil)
+				}
+			}
+			if deferred[i] != nil {
+				batch[i] = block.WithChildren(trimmed)
+			}
+		}
 
-	blocksJSON, err := notionmd.ConvertToJSON(string(data))
-	if err != nil {
-		return nil, fmt.Errorf("convert markdown: %w", err)
+		created, err := client.AppendBlockChildren(ctx, parentID, batch)
+		if err != nil {
+			return fmt.Errorf("append blocks: %w", err)
+		}
+		planned := len(created) == 0
+		for i, nested := range deferred {
+			if nested == nil {
+				continue
+			}
+			if planned {
+				for j, grandchildren := range nested {
+					if len(grandchildren) == 0 {
+						continue
+					}
+					if err := appendBlocks(ctx, client, plannedBlockID(parentID, start+i, j), grandchildren); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if i >= len(created) {
+				return fmt.Errorf("append returned %d blocks, expected %d", len(created), len(batch))
+			}
+			children, err := listBlockChildren(ctx, client, created[i].ID)
+			if err != nil {
+				return err
+			}
+			if len(children) != len(nested) {
+				return fmt.Errorf("block %s has %d children after append, expected %d",
+					created[i].ID, len(children), len(nested))
+			}
+			for j, grandchildren := range nested {
+				if len(grandchildren) == 0 {
+					continue
+				}
+				if err := appendBlocks(ctx, client, children[j].ID, grandchildren); err != nil {
+					return err
+				}
+			}
+		}
 	}
+	return nil
+}
 
-	encoded, err := json.Marshal(blocksJSON)
-	if err != nil {
-		return nil, fmt.Errorf("encode blocks: %w", err)
+// plannedBlockID names, in a dry run, the block that would be created as child j of the
+// i-th block appended to parentID, e.g. new-block-2-1 or, one level deeper, new-block-2-1-3-1.
+func plannedBlockID(parentID string, i, j int) string {
+	if !strings.HasPrefix(parentID, plannedBlockPrefix) {
+		parentID = plannedBlockPrefix
 	}
+	return fmt.Sprintf("%s-%d-%d", parentID, i+1, j+1)
+}
 
-	var blocks []notion.Block
-	if err := json.Unmarshal(encoded, &blocks); err != nil {
-		return nil, fmt.Errorf("decode blocks: %w", err)
+func loadMarkdownBlocks(path string) ([]notion.Block, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- reading user-supplied markdown by design
+	if err != nil {
+		return nil, fmt.Errorf("read markdown: %w", err)
 	}
-
-	return blocks, nil
+	return markdown.ToBlocks(string(data))
 }
@@ -1,9 +1,20 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+
+	"github.com/yourorg/notionctl/internal/markdown"
+	"github.com/yourorg/notionctl/internal/notion"
 )
 
 func TestLoadMarkdownBlocks(t *testing.T) {
@@ -22,3 +33,131 @@ func TestLoadMarkdownBlocks(t *testing.T) {
 		t.Fatalf("expected at least one block")
 	}
 }
+
+// fakeBlockTree stores appended blocks by parent and fails requests nested deeper than
+// Notion allows.
+type fakeBlockTree struct {
+	children map[string][]notion.Block
+	requests int
+	next     int
+}
+
+func (f *fakeBlockTree) RetrieveBlockChildren(
+	_ context.Context,
+	blockID string,
+	_ string,
+	_ int,
+) (notion.BlockChildrenResponse, error) {
+	results := slices.Clone(f.children[blockID])
+	for i := range results {
+		results[i].HasChildren = len(f.children[results[i].ID]) > 0
+	}
+	return notion.BlockChildrenResponse{Results: results}, nil
+}
+
+func (f *fakeBlockTree) AppendBlockChildren(
+	_ context.Context,
+	blockID string,
+	blocks []notion.Block,
+) ([]notion.Block, error) {
+	f.requests++
+	for _, block := range blocks {
+		for _, child := range block.Children() {
+			if len(child.Children()) > 0 {
+				return nil, fmt.Errorf("request nests deeper than two levels")
+			}
+		}
+	}
+	return f.store(blockID, blocks), nil
+}
+
+func (f *fakeBlockTree) store(parentID string, blocks []notion.Block) []notion.Block {
+	stored := make([]notion.Block, len(blocks))
+	for i, block := range blocks {
+		f.next++
+		block.ID = fmt.Sprintf("b%d", f.next)
+		f.store(block.ID, block.Children())
+		stored[i] = block.WithChildren(nil)
+	}
+	f.children[parentID] = append(f.children[parentID], stored...)
+	return stored
+}
+
+func TestAppendBlocksSplitsDeepNesting(t *testing.T) {
+	body := "- one\n  - two\n    - three\n      - four\n- [x] done\n"
+	blocks, err := markdown.ToBlocks(body)
+	if err != nil {
+		t.Fatalf("ToBlocks returned error: %v", err)
+	}
+	client := &fakeBlockTree{children: map[string][]notion.Block{}}
+	if err := appendBlocks(context.Background(), client, "page", blocks); err != nil {
+		t.Fatalf("appendBlocks returned error: %v", err)
+	}
+	if client.requests != 2 {
+		t.Fatalf("expected one follow-up request for the third level, got %d requests", client.requests)
+	}
+
+	tree, err := fetchBlockTree(context.Background(), client, "page")
+	if err != nil {
+		t.Fatalf("fetchBlockTree returned error: %v", err)
+	}
+	if got := markdown.FromBlocks(tree); got != body {
+		t.Fatalf("nesting was not preserved:\n%s\nwant:\n%s", got, body)
+	}
+	if len(blocks[0].Children()[0].Children()) != 1 {
+		t.Fatal("appendBlocks must not modify the blocks it was given")
+	}
+}
+
+func TestAppendBlocksDryRunPrintsDeepFollowUps(t *testing.T) {
+	var sent int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		sent++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	// Five levels of bullets: one request carries two levels, so two follow-ups are planned
+	// under blocks that do not exist.
+	var tree []notion.Block
+	for _, text := range []string{"five", "four", "three", "two", "one"} {
+		item, err := markdown.ToBlocks("- " + text)
+		if err != nil {
+			t.Fatalf("ToBlocks returned error: %v", err)
+		}
+		tree = []notion.Block{item[0].WithChildren(tree)}
+	}
+
+	const pageID = "1234abcd-1234-1234-1234-1234567890ab"
+	var out bytes.Buffer
+	client := dryRunClient(notion.NewClient(notion.ClientConfig{Token: "test", BaseURL: srv.URL}), &out)
+	if err := appendBlocks(context.Background(), client, pageID, tree); err != nil {
+		t.Fatalf("appendBlocks returned error: %v", err)
+	}
+	if sent != 0 {
+		t.Fatalf("expected nothing to reach Notion, got %d requests", sent)
+	}
+
+	var urls, bodies []string
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var planned notion.DryRunRequest
+		if err := dec.Decode(&planned); err != nil {
+			t.Fatalf("decode dry-run output: %v", err)
+		}
+		urls = append(urls, strings.TrimPrefix(planned.URL, srv.URL+"/blocks/"))
+		bodies = append(bodies, string(planned.Body))
+	}
+	want := []string{
+		pageID + "/children",
+		"new-block-1-1/children",
+		"new-block-1-1-1-1/children",
+	}
+	if !slices.Equal(urls, want) {
+		t.Fatalf("expected the follow-up appends to be printed:\ngot  %v\nwant %v", urls, want)
+	}
+	if !strings.Contains(bodies[1], `"three"`) || !strings.Contains(bodies[1], `"four"`) ||
+		strings.Contains(bodies[1], `"five"`) || !strings.Contains(bodies[2], `"five"`) {
+		t.Fatalf("follow-ups carry the wrong blocks: %v", bodies)
+	}
+}
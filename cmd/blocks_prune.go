@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/render"
+)
+
+// emptyParagraphType matches paragraphs with no text and no children.
+const emptyParagraphType = "empty-paragraph"
+
+// prunableTypes are the block types --type accepts: Notion's block types plus
+// emptyParagraphType. Deleting child_page or child_database blocks archives whole pages and
+// databases, so those also need --allow-pages.
+var prunableTypes = map[string]bool{
+	"audio": true, "bookmark": true, "breadcrumb": true, "bulleted_list_item": true, "callout": true,
+	"child_database": true, "child_page": true, "code": true, "column": true, "column_list": true,
+	"divider": true, "embed": true, "equation": true, "file": true, "heading_1": true, "heading_2": true,
+	"heading_3": true, "image": true, "link_preview": true, "link_to_page": true, "numbered_list_item": true,
+	"paragraph": true, "pdf": true, "quote": true, "synced_block": true, "table": true, "table_of_contents": true,
+	"table_row": true, "template": true, "to_do": true, "toggle": true, "unsupported": true, "video": true,
+	emptyParagraphType: true,
+}
+
+type blocksPruneOptions struct {
+	types      []string
+	dataSource string
+	retry      retryOptions
+	dryRun     bool
+	allowPages bool
+}
+
+// blockPruneClient is the subset of the Notion client used to prune blocks.
+type blockPruneClient interface {
+	blockReader
+	DeleteBlock(ctx context.Context, blockID string) error
+}
+
+// prunedBlock is a block selected for deletion and the --type it matched.
+type prunedBlock struct {
+	id    string
+	match string
+}
+
+// prunedBlockPayload is how a block that could not be deleted is kept in the failures file.
+type prunedBlockPayload struct {
+	BlockID string `json:"block_id"`
+	Match   string `json:"match"`
+}
+
+func newBlocksPruneCmd(globals *globalOptions) *cobra.Command {
+	opts := &blocksPruneOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "prune <block-or-page-id|url>",
+		Short: "Delete blocks of the given types anywhere in a page's content",
+		Args:  cobra.ExactArgs(1),
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().StringSliceVar(
+		&opts.types,
+		"type",
+		nil,
+		"Block types to delete, e.g. divider,empty-paragraph (repeatable or comma-separated)",
+	)
+	cmd.Flags().StringVar(
+		&opts.dataSource,
+		"data-source",
+		"",
+		"Data source ID or alias used to resolve unique ID handles such as TASK-123",
+	)
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print the DELETE requests that would be sent without sending them")
+	cmd.Flags().BoolVar(
+		&opts.allowPages,
+		"allow-pages",
+		false,
+		"Allow --type child_page and child_database, which archive whole pages and databases",
+	)
+	opts.retry.register(cmd, "blocks prune", "")
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "retry-from")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("type"))
+
+	return cmd
+}
+
+func (opts *blocksPruneOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		types, err := parsePruneTypes(opts.types, opts.allowPages)
+		if err != nil {
+			return err
+		}
+
+		client, err := buildClient(globals)
+		if err != nil {
+			return err
+		}
+		if opts.dryRun {
+			client = dryRunClient(client, cmd.OutOrStdout())
+		}
+
+		ctx := cmd.Context()
+		targetID, err := resolveBlockRef(ctx, client, globals.profile, args[0], opts.dataSource)
+		if err != nil {
+			return err
+		}
+
+		counts, err := pruneBlocks(ctx, client, targetID, types, &opts.retry, cmd.ErrOrStderr())
+		if opts.dryRun {
+			safeLog(cmd.ErrOrStderr(), "Dry run: would delete %s", pruneCounts(counts))
+			return err
+		}
+		if werr := opts.retry.save(cmd.ErrOrStderr()); werr != nil {
+			return errors.Join(err, werr)
+		}
+		if _, werr := fmt.Fprintf(cmd.OutOrStdout(), "Deleted %s\n", pruneCounts(counts)); werr != nil {
+			return errors.Join(err, fmt.Errorf("write output: %w", werr))
+		}
+		return err
+	}
+}
+
+// parsePruneTypes checks the --type values, so a misspelled type fails instead of quietly
+// deleting nothing.
+func parsePruneTypes(raw []string, allowPages bool) (map[string]bool, error) {
+	types := map[string]bool{}
+	for _, name := range raw {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+			continue
+		case !prunableTypes[name]:
+			problem := fmt.Sprintf("unknown block type %q", name)
+			if suggestion := closestBlockType(name); suggestion != "" {
+				problem += fmt.Sprintf(" (did you mean %q?)", suggestion)
+			}
+			return nil, errors.New(problem)
+		case (name == "child_page" || name == "child_database") && !allowPages:
+			return nil, fmt.Errorf("--type %s archives whole pages or databases; add --allow-pages to delete them", name)
+		}
+		types[name] = true
+	}
+	if len(types) == 0 {
+		return nil, errors.New("--type needs at least one block type")
+	}
+	return types, nil
+}
+
+// closestBlockType suggests a known type for a misspelled one, such as heading-1 or Divider.
+func closestBlockType(name string) string {
+	normalized := strings.ReplaceAll(strings.ToLower(name), "-", "_")
+	if normalized == strings.ReplaceAll(emptyParagraphType, "-", "_") {
+		return emptyParagraphType
+	}
+	if prunableTypes[normalized] {
+		return normalized
+	}
+	return ""
+}
+
+// pruneBlocks deletes every block under targetID matching types, or the blocks of the
+// --retry-from failures file, and returns how many of each type were deleted. A failed delete
+// is logged and recorded, and does not stop the others.
+func pruneBlocks(
+	ctx context.Context,
+	client blockPruneClient,
+	targetID string,
+	types map[string]bool,
+	retry *retryOptions,
+	log io.Writer,
+) (map[string]int, error) {
+	var blocks []prunedBlock
+	var indexes []int
+	if retry.retryFrom != "" {
+		items, err := retry.load()
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			var payload prunedBlockPayload
+			if err := decodePayload(item, &payload); err != nil {
+				return nil, fmt.Errorf("item %d: %w", item.Index, err)
+			}
+			blocks = append(blocks, prunedBlock{id: payload.BlockID, match: payload.Match})
+			indexes = append(indexes, item.Index)
+		}
+	} else {
+		tree, err := fetchBlockTree(ctx, client, targetID)
+		if err != nil {
+			return nil, err
+		}
+		blocks = matchingBlocks(tree, types)
+	}
+
+	counts := map[string]int{}
+	failed := 0
+	for i, block := range blocks {
+		if err := client.DeleteBlock(ctx, block.id); err != nil {
+			failed++
+			safeLog(log, "delete block %s: %v", block.id, err)
+			index := i
+			if indexes != nil {
+				index = indexes[i]
+			}
+			retry.record(index, 0, prunedBlockPayload{BlockID: block.id, Match: block.match}, err)
+			continue
+		}
+		counts[block.match]++
+	}
+	if failed > 0 {
+		return counts, fmt.Errorf("%d blocks could not be deleted", failed)
+	}
+	return counts, nil
+}
+
+// matchingBlocks walks blocks depth first. A matching block's children are deleted with it,
+// so they are not visited.
+func matchingBlocks(blocks []notion.Block, types map[string]bool) []prunedBlock {
+	var matches []prunedBlock
+	for i := range blocks {
+		block := &blocks[i]
+		switch {
+		case types[block.Type]:
+			matches = append(matches, prunedBlock{id: block.ID, match: block.Type})
+		case types[emptyParagraphType] && isEmptyParagraph(block):
+			matches = append(matches, prunedBlock{id: block.ID, match: emptyParagraphType})
+		default:
+			matches = append(matches, matchingBlocks(block.Children(), types)...)
+		}
+	}
+	return matches
+}
+
+func isEmptyParagraph(block *notion.Block) bool {
+	return block.Paragraph != nil &&
+		!block.HasChildren &&
+		strings.TrimSpace(concatRichText(block.Paragraph.RichText)) == ""
+}
+
+// pruneCounts renders counts as "3 blocks (2 divider, 1 empty-paragraph)".
+func pruneCounts(counts map[string]int) string {
+	total := 0
+	parts := make([]string, 0, len(counts))
+	for _, name := range render.SortedKeys(counts) {
+		total += counts[name]
+		parts = append(parts, fmt.Sprintf("%d %s", counts[name], name))
+	}
+	if total == 0 {
+		return "0 blocks"
+	}
+	return fmt.Sprintf("%d blocks (%s)", total, strings.Join(parts, ", "))
+}
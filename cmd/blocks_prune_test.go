@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestPruneBlocksDeletesMatchingTypesAtAnyDepth(t *testing.T) {
+	paragraph := func(id, text string) notion.Block {
+		block := notion.Block{ID: id, Type: "paragraph", Paragraph: &notion.ParagraphBlock{}}
+		if text != "" {
+			block.Paragraph.RichText = []notion.RichText{{PlainText: text}}
+		}
+		return block
+	}
+	toggle := notion.Block{ID: "toggle", Type: "toggle", HasChildren: true, Toggle: &notion.ToggleBlock{}}
+	client := &fakeMarkdownClient{blocks: map[string][]notion.Block{
+		"page-1": {
+			paragraph("keep", "Hello"),
+			{ID: "div-1", Type: "divider", Divider: &struct{}{}},
+			paragraph("blank", " "),
+			toggle,
+			{ID: "sub", Type: "child_page"},
+		},
+		"toggle": {
+			paragraph("nested-blank", ""),
+			{ID: "div-2", Type: "divider", Divider: &struct{}{}},
+			paragraph("nested-keep", "World"),
+		},
+	}}
+
+	types := map[string]bool{"divider": true, emptyParagraphType: true}
+	counts, err := pruneBlocks(context.Background(), client, "page-1", types, &retryOptions{}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("pruneBlocks returned error: %v", err)
+	}
+
+	want := []string{"div-1", "blank", "nested-blank", "div-2"}
+	if !slices.Equal(client.deleted, want) {
+		t.Fatalf("deleted = %v, want %v", client.deleted, want)
+	}
+	if got := pruneCounts(counts); got != "4 blocks (2 divider, 2 empty-paragraph)" {
+		t.Fatalf("pruneCounts = %q", got)
+	}
+}
+
+func TestMatchingBlocksSkipsChildrenOfMatches(t *testing.T) {
+	toggle := notion.Block{ID: "toggle", Type: "toggle", Toggle: &notion.ToggleBlock{
+		Children: []notion.Block{{ID: "inner", Type: "divider", Divider: &struct{}{}}},
+	}}
+	matches := matchingBlocks([]notion.Block{toggle}, map[string]bool{"toggle": true, "divider": true})
+	if len(matches) != 1 || matches[0].id != "toggle" {
+		t.Fatalf("matches = %+v, want only the toggle", matches)
+	}
+}
+
+func TestParsePruneTypes(t *testing.T) {
+	types, err := parsePruneTypes([]string{"divider", " empty-paragraph", ""}, false)
+	if err != nil || len(types) != 2 || !types["divider"] || !types[emptyParagraphType] {
+		t.Fatalf("parsePruneTypes = %v, %v", types, err)
+	}
+	if _, err := parsePruneTypes([]string{"heading-1"}, false); err == nil || !strings.Contains(err.Error(), `did you mean "heading_1"`) {
+		t.Fatalf("expected a misspelled type to be rejected with a suggestion, got %v", err)
+	}
+	if _, err := parsePruneTypes([]string{"child_page"}, false); err == nil || !strings.Contains(err.Error(), "--allow-pages") {
+		t.Fatalf("expected child_page to need --allow-pages, got %v", err)
+	}
+	if types, err := parsePruneTypes([]string{"child_database"}, true); err != nil || !types["child_database"] {
+		t.Fatalf("expected --allow-pages to accept child_database, got %v, %v", types, err)
+	}
+	if _, err := parsePruneTypes([]string{" "}, false); err == nil {
+		t.Fatal("expected an error when no type is given")
+	}
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+type blocksReplaceOptions struct {
+	markdownPath string
+	dataSource   string
+	dryRun       bool
+}
+
+// blockReplaceClient is the subset of the Notion client used to replace a page's content.
+type blockReplaceClient interface {
+	pageBlockClient
+	DeleteBlock(ctx context.Context, blockID string) error
+	RestoreBlock(ctx context.Context, blockID string) error
+}
+
+// replaceSummary counts the blocks a replace removed and added.
+type replaceSummary struct {
+	Deleted  int
+	Appended int
+}
+
+func newBlocksReplaceCmd(globals *globalOptions) *cobra.Command {
+	opts := &blocksReplaceOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "replace <block-or-page-id|url>",
+		Short: "Replace the content of a page or block with Markdown",
+		Args:  cobra.ExactArgs(1),
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().StringVar(&opts.markdownPath, "md", "", "Path to the Markdown file holding the new content")
+	cmd.Flags().StringVar(
+		&opts.dataSource,
+		"data-source",
+		"",
+		"Data source ID or alias used to resolve unique ID handles such as TASK-123",
+	)
+	cmd.Flags().BoolVar(
+		&opts.dryRun,
+		"dry-run",
+		false,
+		"Print the DELETE and PATCH requests that would be sent without sending them",
+	)
+
+	return cmd
+}
+
+func (opts *blocksReplaceOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if opts.markdownPath == "" {
+			return errors.New("--md is required")
+		}
+		blocks, err := loadMarkdownBlocks(opts.markdownPath)
+		if err != nil {
+			return err
+		}
+
+		client, err := buildClient(globals)
+		if err != nil {
+			return err
+		}
+		if opts.dryRun {
+			client = dryRunClient(client, cmd.OutOrStdout())
+		}
+
+		ctx := cmd.Context()
+		targetID, err := resolveBlockRef(ctx, client, globals.profile, args[0], opts.dataSource)
+		if err != nil {
+			return err
+		}
+
+		summary, err := replaceBlocks(ctx, client, targetID, blocks)
+		if err != nil {
+			return err
+		}
+
+		if opts.dryRun {
+			safeLog(cmd.ErrOrStderr(), "Dry run: would delete %d blocks and append %d", summary.Deleted, summary.Appended)
+			return nil
+		}
+		if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Replaced %d blocks with %d\n", summary.Deleted, summary.Appended); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+		return nil
+	}
+}
+
+// replaceBlocks deletes the children of targetID, keeping child pages and databases, then
+// appends blocks. When any step fails the blocks appended so far are deleted and the
+// deleted ones restored, leaving the content as it was.
+func replaceBlocks(
+	ctx context.Context,
+	client blockReplaceClient,
+	targetID string,
+	blocks []notion.Block,
+) (replaceSummary, error) {
+	current, err := listBlockChildren(ctx, client, targetID)
+	if err != nil {
+		return replaceSummary{}, err
+	}
+
+	kept := map[string]bool{}
+	var deleted []string
+	for _, block := range current {
+		if block.Type == "child_page" || block.Type == "child_database" {
+			kept[block.ID] = true
+			continue
+		}
+		if err := client.DeleteBlock(ctx, block.ID); err != nil {
+			err = fmt.Errorf("delete block %s: %w", block.ID, err)
+			return replaceSummary{}, errors.Join(err, restoreBlocks(ctx, client, deleted))
+		}
+		deleted = append(deleted, block.ID)
+	}
+
+	if err := appendBlocks(ctx, client, targetID, blocks); err != nil {
+		return replaceSummary{}, errors.Join(err, rollbackReplace(ctx, client, targetID, kept, deleted))
+	}
+	return replaceSummary{Deleted: len(deleted), Appended: len(blocks)}, nil
+}
+
+// rollbackReplace deletes the children appended by a failed replace, which are the ones
+// that were neither kept nor deleted, then restores the deleted ones.
+func rollbackReplace(
+	ctx context.Context,
+	client blockReplaceClient,
+	targetID string,
+	kept map[string]bool,
+	deleted []string,
+) error {
+	children, err := listBlockChildren(ctx, client, targetID)
+	if err != nil {
+		return fmt.Errorf("roll back: %w", err)
+	}
+	for _, block := range children {
+		if kept[block.ID] {
+			continue
+		}
+		if err := client.DeleteBlock(ctx, block.ID); err != nil {
+			return fmt.Errorf("roll back: delete appended block %s: %w", block.ID, err)
+		}
+	}
+	return restoreBlocks(ctx, client, deleted)
+}
+
+func restoreBlocks(ctx context.Context, client blockReplaceClient, ids []string) error {
+	for _, id := range ids {
+		if err := client.RestoreBlock(ctx, id); err != nil {
+			return fmt.Errorf("roll back: restore block %s: %w", id, err)
+		}
+	}
+	return nil
+}
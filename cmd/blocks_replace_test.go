@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+// fakeReplaceClient keeps deleted blocks so they can be restored, and fails the append
+// request numbered failAppend (1-based) when it is set.
+type fakeReplaceClient struct {
+	fakeMarkdownClient
+	trash      map[string]notion.Block
+	appends    int
+	failAppend int
+}
+
+func (f *fakeReplaceClient) AppendBlockChildren(
+	ctx context.Context,
+	blockID string,
+	blocks []notion.Block,
+) ([]notion.Block, error) {
+	f.appends++
+	if f.appends == f.failAppend {
+		return nil, errors.New("append failed")
+	}
+	return f.fakeMarkdownClient.AppendBlockChildren(ctx, blockID, blocks)
+}
+
+func (f *fakeReplaceClient) DeleteBlock(ctx context.Context, blockID string) error {
+	for _, block := range f.blocks["page-1"] {
+		if block.ID == blockID {
+			f.trash[blockID] = block
+		}
+	}
+	return f.fakeMarkdownClient.DeleteBlock(ctx, blockID)
+}
+
+func (f *fakeReplaceClient) RestoreBlock(_ context.Context, blockID string) error {
+	f.blocks["page-1"] = append(f.blocks["page-1"], f.trash[blockID])
+	return nil
+}
+
+func newFakeReplaceClient() *fakeReplaceClient {
+	return &fakeReplaceClient{
+		fakeMarkdownClient: fakeMarkdownClient{blocks: map[string][]notion.Block{"page-1": {
+			{ID: "old-1", Type: "paragraph"},
+			{ID: "sub", Type: "child_page"},
+			{ID: "old-2", Type: "paragraph"},
+		}}},
+		trash: map[string]notion.Block{},
+	}
+}
+
+func blockIDs(blocks []notion.Block) []string {
+	ids := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		ids = append(ids, block.ID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestReplaceBlocksKeepsChildPages(t *testing.T) {
+	client := newFakeReplaceClient()
+	blocks := []notion.Block{{Type: "paragraph"}, {Type: "paragraph"}}
+
+	summary, err := replaceBlocks(context.Background(), client, "page-1", blocks)
+	if err != nil {
+		t.Fatalf("replaceBlocks returned error: %v", err)
+	}
+	if summary != (replaceSummary{Deleted: 2, Appended: 2}) {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if got := blockIDs(client.blocks["page-1"]); !slices.Equal(got, []string{"page-1-new-0", "page-1-new-1", "sub"}) {
+		t.Fatalf("unexpected content after replace: %v", got)
+	}
+}
+
+func TestReplaceBlocksRollsBackFailedAppend(t *testing.T) {
+	client := newFakeReplaceClient()
+	client.failAppend = 2
+	blocks := make([]notion.Block, maxAppendBlocks+1)
+	for i := range blocks {
+		blocks[i].Type = "paragraph"
+	}
+
+	if _, err := replaceBlocks(context.Background(), client, "page-1", blocks); err == nil {
+		t.Fatal("expected the failed append to be reported")
+	}
+	if got := blockIDs(client.blocks["page-1"]); !slices.Equal(got, []string{"old-1", "old-2", "sub"}) {
+		t.Fatalf("expected the original content to be restored, got %v", got)
+	}
+}
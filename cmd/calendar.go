@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/ical"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+	"github.com/yourorg/notionctl/internal/where"
+)
+
+const (
+	defaultCalendarListen  = "127.0.0.1:8094"
+	defaultCalendarRefresh = 5 * time.Minute
+	calendarContentType    = "text/calendar; charset=utf-8"
+)
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type calendarOptions struct {
+	dataSourceID string
+	dateProp     string
+	titleProp    string
+	whereExpr    string
+	name         string
+}
+
+// calendarClient is the subset of the Notion client used to build a calendar.
+type calendarClient interface {
+	dataSourceQuerier
+	GetDataSource(ctx context.Context, dataSourceID string) (notion.DataSource, error)
+}
+
+func newCalendarCmd(globals *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "calendar",
+		Short: "Publish a data source's date property as an iCalendar feed",
+	}
+
+	cmd.AddCommand(newCalendarExportCmd(globals))
+	cmd.AddCommand(newCalendarServeCmd(globals))
+
+	return cmd
+}
+
+func (opts *calendarOptions) register(cmd *cobra.Command) {
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Data source ID or URL to read events from")
+	cmd.Flags().StringVar(&opts.dateProp, "date-prop", "", "Date property that places each page on the calendar")
+	cmd.Flags().StringVar(&opts.titleProp, "title-prop", "", "Property used as the event title (default: the title property)")
+	cmd.Flags().StringVar(&opts.whereExpr, "where", "", "Only include pages matching this filter expression (see ds query --where)")
+	cmd.Flags().StringVar(&opts.name, "name", "", "Calendar name shown by calendar apps (default: the data source name)")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
+	cobra.CheckErr(cmd.MarkFlagRequired("date-prop"))
+}
+
+func newCalendarExportCmd(globals *globalOptions) *cobra.Command {
+	opts := &calendarOptions{}
+	var outPath string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write pages with a date as an .ics file",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			client, err := buildClient(globals)
+			if err != nil {
+				return err
+			}
+			cal, err := buildCalendar(cmd.Context(), client, opts)
+			if err != nil {
+				return err
+			}
+			if outPath == "" {
+				return ical.Write(cmd.OutOrStdout(), cal)
+			}
+			var buf bytes.Buffer
+			if err := ical.Write(&buf, cal); err != nil {
+				return err
+			}
+			if err := os.WriteFile(outPath, buf.Bytes(), 0o600); err != nil {
+				return fmt.Errorf("write %s: %w", outPath, err)
+			}
+			safeLog(cmd.ErrOrStderr(), "Wrote %d events to %s", len(cal.Events), outPath)
+			return nil
+		},
+	}
+
+	opts.register(cmd)
+	cmd.Flags().StringVar(&outPath, "out", "", "Write the calendar to this file instead of stdout")
+
+	return cmd
+}
+
+func newCalendarServeCmd(globals *globalOptions) *cobra.Command {
+	opts := &calendarOptions{}
+	listenAddr := defaultCalendarListen
+	refresh := defaultCalendarRefresh
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve pages with a date as a live iCalendar feed over HTTP",
+		Long: "Serve GET /calendar.ics for calendar apps to subscribe to. The feed is rebuilt from Notion " +
+			"on the first request after --refresh has passed.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if refresh <= 0 {
+				return errors.New("--refresh must be greater than zero")
+			}
+			client, err := buildClient(globals)
+			if err != nil {
+				return err
+			}
+			feed := &calendarFeed{client: client, opts: opts, refresh: refresh, log: cmd.ErrOrStderr()}
+			// Build once up front so flag and schema mistakes fail before serving.
+			if _, err := feed.current(cmd.Context(), time.Now()); err != nil {
+				return err
+			}
+			server := &http.Server{Addr: listenAddr, Handler: feed.handler(), ReadHeaderTimeout: serverReadTimeout}
+			errCh := make(chan error, 1)
+			go func() {
+				if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					errCh <- fmt.Errorf("calendar server: %w", err)
+				}
+			}()
+			defer func() {
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+				defer cancel()
+				_ = server.Shutdown(shutdownCtx)
+			}()
+			safeLog(cmd.ErrOrStderr(), "Serving calendar on http://%s/calendar.ics (refresh %s)", listenAddr, refresh)
+
+			select {
+			case <-cmd.Context().Done():
+				return nil
+			case err := <-errCh:
+				return err
+			}
+		},
+	}
+
+	opts.register(cmd)
+	cmd.Flags().StringVar(&listenAddr, "listen", listenAddr, "Address to serve HTTP on (host:port)")
+	cmd.Flags().DurationVar(&refresh, "refresh", refresh, "How long a built feed is served before Notion is queried again")
+
+	return cmd
+}
+
+// buildCalendar queries every page whose date property is set and turns each into an event.
+func buildCalendar(ctx context.Context, client calendarClient, opts *calendarOptions) (ical.Calendar, error) {
+	ds, err := client.GetDataSource(ctx, opts.dataSourceID)
+	if err != nil {
+		return ical.Calendar{}, fmt.Errorf("get data source: %w", err)
+	}
+	idx := schema.NewIndex(ds)
+	dateRef, ok := idx.ReferenceForName(opts.dateProp)
+	if !ok {
+		return ical.Calendar{}, fmt.Errorf("unknown --date-prop %q", opts.dateProp)
+	}
+	if dateRef.Type != "date" {
+		return ical.Calendar{}, fmt.Errorf("--date-prop %q is a %s property, not a date", dateRef.Name, dateRef.Type)
+	}
+	titleName := ""
+	if opts.titleProp != "" {
+		titleRef, ok := idx.ReferenceForName(opts.titleProp)
+		if !ok {
+			return ical.Calendar{}, fmt.Errorf("unknown --title-prop %q", opts.titleProp)
+		}
+		titleName = titleRef.Name
+	}
+
+	filter, err := where.Condition(dateRef, "is_not_empty", "")
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+	if opts.whereExpr != "" {
+		extra, err := where.Compile(opts.whereExpr, idx)
+		if err != nil {
+			return ical.Calendar{}, fmt.Errorf("parse --where: %w", err)
+		}
+		filter = map[string]any{"and": []any{filter, extra}}
+	}
+	req := notion.QueryDataSourceRequest{
+		Filter: filter,
+		Sorts:  []any{map[string]any{"property": dateRef.ID, "direction": "ascending"}},
+	}
+
+	cal := ical.Calendar{Name: opts.name}
+	if cal.Name == "" {
+		cal.Name = ds.Name
+	}
+	for page, err := range streamDataSourceQuery(ctx, client, opts.dataSourceID, req, 0) {
+		if err != nil {
+			return ical.Calendar{}, err
+		}
+		if event, ok := calendarEvent(page, dateRef.Name, titleName); ok {
+			cal.Events = append(cal.Events, event)
+		}
+	}
+	return cal, nil
+}
+
+// calendarEvent builds the event for a page. Date-only values become all-day events; a
+// range's end date is inclusive in Notion, so the exclusive iCalendar end is the day after.
+func calendarEvent(page notion.Page, dateProp, titleProp string) (ical.Event, bool) {
+	date := page.Properties[dateProp].Date
+	if date == nil || date.Start == "" {
+		return ical.Event{}, false
+	}
+	event := ical.Event{
+		UID:      page.ID + "@notionctl",
+		Modified: page.LastEditedTime,
+		URL:      page.URL,
+	}
+	if titleProp != "" {
+		event.Summary = summarizeProperty(page.Properties[titleProp])
+	} else {
+		event.Summary = pageTitle(page)
+	}
+	if event.Summary == "" {
+		event.Summary = "Untitled"
+	}
+
+	start, allDay, err := parseNotionDate(date.Start)
+	if err != nil {
+		return ical.Event{}, false
+	}
+	event.Start, event.AllDay = start, allDay
+	if date.End != nil && *date.End != "" {
+		if end, _, err := parseNotionDate(*date.End); err == nil {
+			event.End = end
+			if allDay {
+				event.End = end.AddDate(0, 0, 1)
+			}
+		}
+	}
+	return event, true
+}
+
+// parseNotionDate reads a Notion date value, reporting whether it is a date without a time.
+func parseNotionDate(value string) (time.Time, bool, error) {
+	if day, err := time.Parse(time.DateOnly, value); err == nil {
+		return day, true, nil
+	}
+	moment, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("parse date %q: %w", value, err)
+	}
+	return moment, false, nil
+}
+
+// calendarFeed serves the calendar, rebuilding it at most once per refresh interval.
+type calendarFeed struct {
+	client  calendarClient
+	opts    *calendarOptions
+	log     io.Writer
+	refresh time.Duration
+
+	mu      sync.Mutex
+	body    []byte
+	builtAt time.Time
+}
+
+func (f *calendarFeed) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /calendar.ics", f.serveCalendar)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	return mux
+}
+
+func (f *calendarFeed) serveCalendar(w http.ResponseWriter, r *http.Request) {
+	body, err := f.current(r.Context(), time.Now())
+	if err != nil {
+		safeLog(f.log, "Build calendar: %v", err)
+		http.Error(w, err.Error(), upstreamStatus(err))
+		return
+	}
+	w.Header().Set("Content-Type", calendarContentType)
+	_, _ = w.Write(body)
+}
+
+// current returns the cached feed, rebuilding it when it is older than the refresh interval.
+func (f *calendarFeed) current(ctx context.Context, now time.Time) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.body != nil && now.Sub(f.builtAt) < f.refresh {
+		return f.body, nil
+	}
+	cal, err := buildCalendar(ctx, f.client, f.opts)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := ical.Write(&buf, cal); err != nil {
+		return nil, err
+	}
+	f.body, f.builtAt = buf.Bytes(), now
+	return f.body, nil
+}
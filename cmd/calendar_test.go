@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+// fakeCalendarClient serves a task data source and counts queries.
+type fakeCalendarClient struct {
+	pages    []notion.Page
+	requests []notion.QueryDataSourceRequest
+}
+
+func (f *fakeCalendarClient) GetDataSource(context.Context, string) (notion.DataSource, error) {
+	return notion.DataSource{Name: "Tasks", Properties: map[string]notion.PropertyReference{
+		"Name":   {ID: "title", Name: "Name", Type: "title"},
+		"Due":    {ID: "due", Name: "Due", Type: "date"},
+		"Status": {ID: "st", Name: "Status", Type: "status"},
+	}}, nil
+}
+
+func (f *fakeCalendarClient) QueryDataSource(
+	_ context.Context,
+	_ string,
+	req notion.QueryDataSourceRequest,
+) (notion.QueryDataSourceResponse, error) {
+	f.requests = append(f.requests, req)
+	return notion.QueryDataSourceResponse{Results: f.pages}, nil
+}
+
+func datedPage(id, title, start string, end *string) notion.Page {
+	return notion.Page{ID: id, URL: "https://www.notion.so/" + id, Properties: map[string]notion.PropertyValue{
+		"Name": {Type: "title", Title: []notion.RichText{{PlainText: title}}},
+		"Due":  {Type: "date", Date: &notion.DateValue{Start: start, End: end}},
+	}}
+}
+
+func TestBuildCalendar(t *testing.T) {
+	end := "2025-06-03"
+	client := &fakeCalendarClient{pages: []notion.Page{
+		datedPage("p1", "Launch", "2025-06-01", &end),
+		datedPage("p2", "Standup", "2025-06-02T09:30:00.000-04:00", nil),
+		datedPage("p3", "Bad date", "someday", nil),
+	}}
+	opts := &calendarOptions{dataSourceID: "ds", dateProp: "due", whereExpr: `Status = "Open"`}
+
+	cal, err := buildCalendar(context.Background(), client, opts)
+	if err != nil {
+		t.Fatalf("buildCalendar returned error: %v", err)
+	}
+	if cal.Name != "Tasks" || len(cal.Events) != 2 {
+		t.Fatalf("unexpected calendar: %+v", cal)
+	}
+	launch := cal.Events[0]
+	if !launch.AllDay || launch.Summary != "Launch" || launch.End.Format(time.DateOnly) != "2025-06-04" {
+		t.Fatalf("unexpected all-day event: %+v", launch)
+	}
+	standup := cal.Events[1]
+	if standup.AllDay || standup.Start.UTC().Format(time.RFC3339) != "2025-06-02T13:30:00Z" {
+		t.Fatalf("unexpected timed event: %+v", standup)
+	}
+
+	filter, err := json.Marshal(client.requests[0].Filter)
+	if err != nil {
+		t.Fatalf("marshal filter: %v", err)
+	}
+	want := `{"and":[{"date":{"is_not_empty":true},"property":"due"},{"property":"st","status":{"equals":"Open"}}]}`
+	if string(filter) != want {
+		t.Fatalf("filter = %s, want %s", filter, want)
+	}
+
+	if _, err := buildCalendar(context.Background(), client, &calendarOptions{dataSourceID: "ds", dateProp: "Name"}); err == nil {
+		t.Fatal("expected an error for a non-date --date-prop")
+	}
+}
+
+func TestCalendarFeedCachesUntilRefresh(t *testing.T) {
+	client := &fakeCalendarClient{pages: []notion.Page{datedPage("p1", "Launch", "2025-06-01", nil)}}
+	feed := &calendarFeed{
+		client:  client,
+		opts:    &calendarOptions{dataSourceID: "ds", dateProp: "Due", titleProp: "Name"},
+		refresh: time.Hour,
+	}
+	handler := feed.handler()
+
+	for range 2 {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics", nil))
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "SUMMARY:Launch\r\n") {
+			t.Fatalf("GET /calendar.ics = %d: %s", rec.Code, rec.Body.String())
+		}
+		if got := rec.Header().Get("Content-Type"); got != calendarContentType {
+			t.Fatalf("Content-Type = %q", got)
+		}
+	}
+	if len(client.requests) != 1 {
+		t.Fatalf("expected one query while the feed is fresh, got %d", len(client.requests))
+	}
+	if _, err := feed.current(context.Background(), time.Now().Add(2*time.Hour)); err != nil || len(client.requests) != 2 {
+		t.Fatalf("expected a rebuild after the refresh interval (err %v, queries %d)", err, len(client.requests))
+	}
+}
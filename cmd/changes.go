@@ -29,9 +29,27 @@ func newChangesCmd(globals *globalOptions) *cobra.Command {
 		RunE:  opts.run(globals),
 	}
 
-	cmd.Flags().StringVar(&opts.dataSourceID, "data-source-id", "", "Target data source ID")
-	cmd.Flags().StringVar(&opts.dsOpts.format, "format", opts.dsOpts.format, "Output format: json|table")
-	cmd.Flags().StringSliceVar(&opts.dsOpts.expandRelations, "expand", nil, "Relation property names to expand")
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Target data source ID or URL")
+	formatFlag(cmd, &opts.dsOpts.format, "Output format", formatJSON, formatTable)
+	cmd.Flags().StringSliceVar(&opts.dsOpts.expandRelations, "expand", nil, "Relation or rollup property names to expand")
+	cmd.Flags().StringSliceVar(
+		&opts.dsOpts.expandFields,
+		"expand-fields",
+		nil,
+		`Properties to keep on expanded pages, e.g. "Name,Status" ("title" keeps the title)`,
+	)
+	cmd.Flags().BoolVar(
+		&opts.dsOpts.expandPeople,
+		"expand-people",
+		false,
+		"Resolve people, created_by, and last_edited_by users to full user objects with names and emails",
+	)
+	cmd.Flags().BoolVar(
+		&opts.dsOpts.expandInline,
+		"expand-inline",
+		false,
+		"Embed expanded pages in the relation values (relation[].page) instead of expanded_relations",
+	)
 	cmd.Flags().String("since", "", "Start of time window (RFC3339)")
 	cmd.Flags().String("until", "", "End of time window (RFC3339)")
 	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
@@ -47,11 +65,12 @@ func (opts *changesOptions) run(globals *globalOptions) func(*cobra.Command, []s
 		}
 
 		opts.dsOpts.dataSourceID = opts.dataSourceID
+		opts.dsOpts.stderr = cmd.ErrOrStderr()
 		if err := opts.prepareQuery(); err != nil {
 			return err
 		}
 
-		client, err := buildClient(globals.profile)
+		client, err := buildClient(globals)
 		if err != nil {
 			return err
 		}
@@ -1,7 +1,14 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/yourorg/notionctl/internal/config"
 	"github.com/yourorg/notionctl/internal/notion"
@@ -9,20 +16,162 @@ import (
 
 var clientFactory = defaultClientFactory
 
-func defaultClientFactory(profile string) (*notion.Client, error) {
-	token, notionVersion, err := config.LoadAuth(profile)
+// tokenEnvVars are read, in order, when the profile has no token in the keyring.
+var tokenEnvVars = []string{"NOTIONCTL_TOKEN", "NOTION_TOKEN"}
+
+// debugOutput receives --debug and --debug-body logs.
+var debugOutput io.Writer = os.Stderr
+
+// baseURLEnv overrides the profile's base URL for one run, e.g. to point at a mock server.
+const baseURLEnv = "NOTIONCTL_BASE_URL"
+
+// httpCacheTTLEnv turns on the on-disk response cache when --http-cache-ttl is not given. An
+// explicit --http-cache-ttl 0 turns it off.
+const httpCacheTTLEnv = "NOTIONCTL_HTTP_CACHE_TTL"
+
+// defaultClientFactory builds a client for globals.profile, applying the global flags that
+// shape requests: the token variable, limits, debug logging, and the response cache.
+func defaultClientFactory(globals *globalOptions) (*notion.Client, error) {
+	profile := globals.profile
+	token, notionVersion, err := resolveAuth(profile, globals.tokenEnv)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := resolveEndpoint(profile)
+	if err != nil {
+		return nil, err
+	}
+	limits, err := resolveLimits(profile, globals)
+	if err != nil {
+		return nil, err
+	}
+	transport, err := endpointTransport(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	cfg := notion.ClientConfig{
+		Transport:         transport,
+		Token:             token,
+		NotionVersion:     notionVersion,
+		BaseURL:           endpoint.BaseURL,
+		Headers:           endpoint.Headers,
+		RequestsPerSecond: limits.RPS,
+		BackoffBase:       limits.BackoffBase,
+		MaxRetries:        clientMaxRetries(limits.MaxRetries),
+		BreakerThreshold:  limits.BreakerThreshold,
+		DebugBodies:       globals.debugBody,
+	}
+	if globals.debug || globals.debugBody {
+		cfg.DebugLog = debugOutput
+	}
+	client := notion.NewClient(cfg)
+	cache, err := responseCache(globals)
+	if err != nil {
+		return nil, err
+	}
+	if cache != nil {
+		client.WrapTransport(cache.Wrap)
+	}
+	return client, nil
+}
+
+// responseCache returns the on-disk response cache, or nil when the TTL is zero.
+func responseCache(globals *globalOptions) (*notion.ResponseCache, error) {
+	ttl := globals.httpCacheTTL
+	if env := strings.TrimSpace(os.Getenv(httpCacheTTLEnv)); !globals.httpCacheTTLSet && env != "" {
+		parsed, err := time.ParseDuration(env)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", httpCacheTTLEnv, err)
+		}
+		ttl = parsed
+	}
+	if ttl < 0 {
+		return nil, errors.New("--http-cache-ttl must not be negative")
+	}
+	if ttl == 0 {
+		return nil, nil
+	}
+	dir, err := httpCacheDir()
 	if err != nil {
-		return nil, fmt.Errorf("load auth: %w", err)
+		return nil, err
 	}
-	if token == "" {
-		return nil, fmt.Errorf("profile %q has no stored Notion token", profile)
+	return notion.NewResponseCache(dir, ttl), nil
+}
+
+func httpCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve cache directory: %w", err)
+	}
+	return filepath.Join(dir, "notionctl", "http"), nil
+}
+
+func resolveEndpoint(profile string) (config.Endpoint, error) {
+	endpoint, err := config.LoadEndpoint(profile)
+	if err != nil {
+		return config.Endpoint{}, fmt.Errorf("load endpoint: %w", err)
+	}
+	if env := strings.TrimSpace(os.Getenv(baseURLEnv)); env != "" {
+		if err := config.ValidateBaseURL(env); err != nil {
+			return config.Endpoint{}, fmt.Errorf("%s: %w", baseURLEnv, err)
+		}
+		endpoint.BaseURL = env
+	}
+	return endpoint, nil
+}
+
+// endpointTransport returns nil, keeping the default transport, unless the profile sets a
+// proxy, CA bundle, or TLS skip-verify.
+func endpointTransport(endpoint config.Endpoint) (http.RoundTripper, error) {
+	if endpoint.ProxyURL == "" && endpoint.CABundle == "" && !endpoint.InsecureSkipVerify {
+		return nil, nil
+	}
+	transport, err := notion.NewTransport(notion.TransportConfig{
+		ProxyURL:           endpoint.ProxyURL,
+		CABundle:           endpoint.CABundle,
+		InsecureSkipVerify: endpoint.InsecureSkipVerify,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("configure transport: %w", err)
+	}
+	return transport, nil
+}
+
+// resolveAuth returns the profile's token and Notion-Version. With tokenEnv set the token
+// comes only from that variable; otherwise the keyring wins and tokenEnvVars are the
+// fallback, so CI runs work without a keyring or interactive login.
+func resolveAuth(profile, tokenEnv string) (token, notionVersion string, err error) {
+	if tokenEnv != "" {
+		token = strings.TrimSpace(os.Getenv(tokenEnv))
+		if token == "" {
+			return "", "", fmt.Errorf("--token-env: environment variable %s is empty or unset", tokenEnv)
+		}
+		return envAuth(profile, token)
+	}
+
+	token, notionVersion, err = config.LoadAuth(profile)
+	if err == nil && token != "" {
+		return token, notionVersion, nil
+	}
+	for _, name := range tokenEnvVars {
+		if env := strings.TrimSpace(os.Getenv(name)); env != "" {
+			return envAuth(profile, env)
+		}
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("load auth: %w (or set %s)", err, strings.Join(tokenEnvVars, "/"))
+	}
+	return "", "", fmt.Errorf("profile %q has no stored Notion token", profile)
+}
+
+func envAuth(profile, token string) (string, string, error) {
+	notionVersion, err := config.LoadVersion(profile)
+	if err != nil {
+		return "", "", fmt.Errorf("load auth: %w", err)
 	}
-	return notion.NewClient(notion.ClientConfig{
-		Token:         token,
-		NotionVersion: notionVersion,
-	}), nil
+	return token, notionVersion, nil
 }
 
-func buildClient(profile string) (*notion.Client, error) {
-	return clientFactory(profile)
+func buildClient(globals *globalOptions) (*notion.Client, error) {
+	return clientFactory(globals)
 }
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zalando/go-keyring"
+
+	"github.com/yourorg/notionctl/internal/config"
+)
+
+func TestResolveAuthFallsBackToEnvironment(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NOTIONCTL_TOKEN", "")
+	t.Setenv("NOTION_TOKEN", "")
+	keyring.MockInit()
+
+	if _, _, err := resolveAuth("ci", ""); err == nil || !strings.Contains(err.Error(), "NOTION_TOKEN") {
+		t.Fatalf("expected an error mentioning NOTION_TOKEN, got %v", err)
+	}
+
+	t.Setenv("NOTION_TOKEN", "secret_env")
+	token, version, err := resolveAuth("ci", "")
+	if err != nil {
+		t.Fatalf("resolveAuth returned error: %v", err)
+	}
+	if token != "secret_env" || version != config.DefaultNotionVersion() {
+		t.Fatalf("resolveAuth = %q, %q", token, version)
+	}
+
+	t.Setenv("NOTIONCTL_TOKEN", "secret_ctl")
+	if token, _, _ := resolveAuth("ci", ""); token != "secret_ctl" {
+		t.Fatalf("expected NOTIONCTL_TOKEN to win over NOTION_TOKEN, got %q", token)
+	}
+
+	if err := config.SaveToken("ci", "secret_keyring", "2025-10-01"); err != nil {
+		t.Fatalf("SaveToken returned error: %v", err)
+	}
+	if token, version, _ := resolveAuth("ci", ""); token != "secret_keyring" || version != "2025-10-01" {
+		t.Fatalf("expected the keyring token to win, got %q, %q", token, version)
+	}
+
+	t.Setenv("CI_NOTION", "secret_explicit")
+	if token, _, _ := resolveAuth("ci", "CI_NOTION"); token != "secret_explicit" {
+		t.Fatalf("expected --token-env to override the keyring, got %q", token)
+	}
+	if _, _, err := resolveAuth("ci", "MISSING_VAR"); err == nil {
+		t.Fatalf("expected an error for an unset --token-env variable")
+	}
+}
+
+func TestDefaultClientFactoryUsesProfileEndpoint(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(baseURLEnv, "")
+	keyring.MockInit()
+
+	var gotPath, gotKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotKey = r.URL.Path, r.Header.Get("X-Gateway-Key")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	if err := config.SaveToken("gw", "secret_gw", ""); err != nil {
+		t.Fatalf("SaveToken returned error: %v", err)
+	}
+	endpoint := config.Endpoint{BaseURL: srv.URL + "/notion/v1", Headers: map[string]string{"X-Gateway-Key": "k1"}}
+	if err := config.SaveEndpoint("gw", endpoint); err != nil {
+		t.Fatalf("SaveEndpoint returned error: %v", err)
+	}
+
+	client, err := defaultClientFactory(&globalOptions{profile: "gw"})
+	if err != nil {
+		t.Fatalf("defaultClientFactory returned error: %v", err)
+	}
+	if err := client.Do(context.Background(), http.MethodGet, "users/me", nil, nil); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if gotPath != "/notion/v1/users/me" || gotKey != "k1" {
+		t.Fatalf("request went to %q with key %q", gotPath, gotKey)
+	}
+
+	t.Setenv(baseURLEnv, "not a url")
+	if _, err := defaultClientFactory(&globalOptions{profile: "gw"}); err == nil {
+		t.Fatalf("expected an invalid %s to be rejected", baseURLEnv)
+	}
+}
+
+func TestDefaultClientFactoryAppliesLimits(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(baseURLEnv, "")
+	keyring.MockInit()
+	opts := &globalOptions{profile: "shared"}
+
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	t.Setenv(baseURLEnv, srv.URL)
+
+	if err := config.SaveToken("shared", "secret_shared", ""); err != nil {
+		t.Fatalf("SaveToken returned error: %v", err)
+	}
+	for key, value := range map[string]string{"max_retries": "2", "backoff_base": "1ms", "rps": "1000"} {
+		if err := config.SetLimit("shared", key, value); err != nil {
+			t.Fatalf("SetLimit returned error: %v", err)
+		}
+	}
+
+	countAttempts := func() int32 {
+		t.Helper()
+		attempts.Store(0)
+		client, err := defaultClientFactory(opts)
+		if err != nil {
+			t.Fatalf("defaultClientFactory returned error: %v", err)
+		}
+		if err := client.Do(context.Background(), http.MethodGet, "users/me", nil, nil); err == nil {
+			t.Fatalf("expected the 503 to be returned")
+		}
+		return attempts.Load()
+	}
+	if got := countAttempts(); got != 3 {
+		t.Fatalf("expected the profile's max_retries 2 to allow 3 attempts, got %d", got)
+	}
+	if err := opts.maxRetries.Set("0"); err != nil {
+		t.Fatalf("set --max-retries: %v", err)
+	}
+	if got := countAttempts(); got != 1 {
+		t.Fatalf("expected --max-retries 0 to disable retries, got %d attempts", got)
+	}
+
+	opts.rps = -1
+	if _, err := defaultClientFactory(opts); err == nil {
+		t.Fatalf("expected a negative --rps to be rejected")
+	}
+}
+
+func TestDefaultClientFactoryDebugLogging(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	keyring.MockInit()
+	savedOutput := debugOutput
+	t.Cleanup(func() { debugOutput = savedOutput })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	t.Setenv(baseURLEnv, srv.URL)
+	if err := config.SaveToken("dbg", "secret_dbg", ""); err != nil {
+		t.Fatalf("SaveToken returned error: %v", err)
+	}
+
+	var log strings.Builder
+	debugOutput = &log
+	for _, debug := range []bool{false, true} {
+		client, err := defaultClientFactory(&globalOptions{profile: "dbg", debug: debug})
+		if err != nil {
+			t.Fatalf("defaultClientFactory returned error: %v", err)
+		}
+		if err := client.Do(context.Background(), http.MethodGet, "users/me", nil, nil); err != nil {
+			t.Fatalf("Do returned error: %v", err)
+		}
+	}
+	if got := strings.Count(log.String(), "GET /users/me -> 200 OK"); got != 1 {
+		t.Fatalf("expected one logged request with --debug only, got:\n%s", log.String())
+	}
+}
+
+func TestResponseCacheTTLFromFlagOrEnvironment(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv(httpCacheTTLEnv, "")
+
+	if cache, err := responseCache(&globalOptions{}); err != nil || cache != nil {
+		t.Fatalf("expected no cache by default, got %v, %v", cache, err)
+	}
+	t.Setenv(httpCacheTTLEnv, "10m")
+	if cache, err := responseCache(&globalOptions{}); err != nil || cache == nil {
+		t.Fatalf("expected %s to enable the cache, got %v, %v", httpCacheTTLEnv, cache, err)
+	}
+	t.Setenv(httpCacheTTLEnv, "soon")
+	if _, err := responseCache(&globalOptions{}); err == nil {
+		t.Fatalf("expected an invalid %s to be rejected", httpCacheTTLEnv)
+	}
+	if cache, err := responseCache(&globalOptions{httpCacheTTL: time.Minute, httpCacheTTLSet: true}); err != nil || cache == nil {
+		t.Fatalf("expected --http-cache-ttl to win over the environment, got %v, %v", cache, err)
+	}
+	t.Setenv(httpCacheTTLEnv, "10m")
+	if cache, err := responseCache(&globalOptions{httpCacheTTLSet: true}); err != nil || cache != nil {
+		t.Fatalf("expected --http-cache-ttl 0 to turn off a cache enabled by %s, got %v, %v", httpCacheTTLEnv, cache, err)
+	}
+}
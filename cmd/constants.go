@@ -4,4 +4,7 @@ const (
 	formatJSON   = "json"
 	formatTable  = "table"
 	relationType = "relation"
+
+	// maxQueryPageSize is the largest page_size the Notion query endpoints accept.
+	maxQueryPageSize = 100
 )
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/props"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+// csvPropertyReader reads CSV records whose header row names data source properties.
+type csvPropertyReader struct {
+	reader *csv.Reader
+	refs   []notion.PropertyReference
+}
+
+// newCSVPropertyReader reads the header row and resolves every column to a property.
+func newCSVPropertyReader(input io.Reader, idx *schema.Index) (*csvPropertyReader, error) {
+	reader := csv.NewReader(input)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("csv is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+
+	refs := make([]notion.PropertyReference, len(header))
+	for i, name := range header {
+		ref, ok := idx.ReferenceForName(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !ok {
+			return nil, fmt.Errorf("csv column %q is not a property of the data source", name)
+		}
+		refs[i] = ref
+	}
+	return &csvPropertyReader{reader: reader, refs: refs}, nil
+}
+
+// column returns the index of the column holding ref, or -1.
+func (r *csvPropertyReader) column(ref notion.PropertyReference) int {
+	for i, candidate := range r.refs {
+		if candidate.ID == ref.ID {
+			return i
+		}
+	}
+	return -1
+}
+
+// next returns the 1-based line number and cells of the next record, or io.EOF.
+func (r *csvPropertyReader) next() (int, []string, error) {
+	record, err := r.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return 0, nil, io.EOF
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("read csv: %w", err)
+	}
+	line, _ := r.reader.FieldPos(0)
+	if len(record) > len(r.refs) {
+		return line, nil, fmt.Errorf("line %d has more cells than the header", line)
+	}
+	return line, record, nil
+}
+
+// properties coerces cells by their column's property type, skipping the column at skip.
+// Empty cells are left out unless clearEmpty is set, in which case they clear the property.
+func (r *csvPropertyReader) properties(cells []string, skip int, clearEmpty bool) (map[string]any, error) {
+	properties := make(map[string]any, len(cells))
+	for i, cell := range cells {
+		if i == skip || (strings.TrimSpace(cell) == "" && !clearEmpty) {
+			continue
+		}
+		payload, err := props.Coerce(r.refs[i], cell)
+		if err != nil {
+			return nil, err
+		}
+		properties[r.refs[i].Name] = payload
+	}
+	return properties, nil
+}
@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+// dryRunClient returns a copy of client that prints its writes to w instead of sending
+// them. The copy keeps the shell's shared client untouched.
+func dryRunClient(client *notion.Client, w io.Writer) *notion.Client {
+	dry := client.Clone()
+	dry.WrapTransport(notion.DryRun(w))
+	return dry
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestPagesUpdateDryRunPrintsMergedRequest(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NOTIONCTL_TOKEN", "secret_dry")
+	keyring.MockInit()
+
+	const pageID = "1234abcd-1234-1234-1234-1234567890ab"
+	var methods []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		_, _ = w.Write([]byte(`{"object":"page","id":"` + pageID + `","properties":{
+			"Blocked By":{"id":"rel","type":"relation","relation":[{"id":"existing"}]}}}`))
+	}))
+	defer srv.Close()
+	t.Setenv(baseURLEnv, srv.URL)
+
+	props := filepath.Join(t.TempDir(), "props.json")
+	if err := os.WriteFile(props, []byte(`{"Blocked By":{"relation":[{"id":"added"}]}}`), 0o600); err != nil {
+		t.Fatalf("write props: %v", err)
+	}
+
+	var out bytes.Buffer
+	root := newRootCmd(&globalOptions{profile: "default"})
+	root.SetArgs([]string{"pages", "update", pageID, "--props", props, "--dry-run"})
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("pages update --dry-run returned error: %v", err)
+	}
+
+	if len(methods) != 1 || methods[0] != http.MethodGet {
+		t.Fatalf("expected only the page read to reach Notion, got %v", methods)
+	}
+	var planned notion.DryRunRequest
+	if err := json.Unmarshal(out.Bytes(), &planned); err != nil {
+		t.Fatalf("decode dry-run output: %v\n%s", err, out.String())
+	}
+	var body struct {
+		Properties map[string]struct {
+			Relation []map[string]string `json:"relation"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(planned.Body, &body); err != nil {
+		t.Fatalf("decode planned body: %v", err)
+	}
+	relations := body.Properties["Blocked By"].Relation
+	if planned.Method != http.MethodPatch || len(relations) != 2 || relations[0]["id"] != "added" {
+		t.Fatalf("expected a PATCH merging the existing relation, got %s %s", planned.Method, planned.Body)
+	}
+}
+
+func TestPagesCreateDryRunPrintsRequest(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NOTIONCTL_TOKEN", "secret_dry")
+	keyring.MockInit()
+
+	var methods []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		_, _ = w.Write([]byte(`{"object":"data_source","id":"ds1","name":"Tasks","properties":{
+			"Name":{"id":"title","name":"Name","type":"title"}}}`))
+	}))
+	defer srv.Close()
+	t.Setenv(baseURLEnv, srv.URL)
+
+	props := filepath.Join(t.TempDir(), "props.json")
+	if err := os.WriteFile(props, []byte(`{"Name":{"title":[{"text":{"content":"Launch"}}]}}`), 0o600); err != nil {
+		t.Fatalf("write props: %v", err)
+	}
+
+	var out bytes.Buffer
+	root := newRootCmd(&globalOptions{profile: "default"})
+	root.SetArgs([]string{
+		"pages", "create", "--data-source-id", "abcdef0123456789abcdef0123456789", "--props", props, "--dry-run",
+	})
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("pages create --dry-run returned error: %v", err)
+	}
+
+	if len(methods) != 1 || methods[0] != http.MethodGet {
+		t.Fatalf("expected only the schema read to reach Notion, got %v", methods)
+	}
+	var planned notion.DryRunRequest
+	if err := json.Unmarshal(out.Bytes(), &planned); err != nil {
+		t.Fatalf("decode dry-run output: %v\n%s", err, out.String())
+	}
+	var body notion.CreatePageRequest
+	if err := json.Unmarshal(planned.Body, &body); err != nil {
+		t.Fatalf("decode planned body: %v", err)
+	}
+	if planned.Method != http.MethodPost || body.Parent.DataSourceID != "abcdef01-2345-6789-abcd-ef0123456789" ||
+		body.Properties["Name"] == nil {
+		t.Fatalf("expected a POST creating the page in the data source, got %s %s", planned.Method, planned.Body)
+	}
+}
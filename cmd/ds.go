@@ -10,6 +10,19 @@ func newDSCmd(globals *globalOptions) *cobra.Command {
 
 	cmd.AddCommand(newDSListCmd(globals))
 	cmd.AddCommand(newDSQueryCmd(globals))
+	cmd.AddCommand(newDSAliasCmd(globals))
+	cmd.AddCommand(newDSExportCmd(globals))
+	cmd.AddCommand(newDSImportCmd(globals))
+	cmd.AddCommand(newDSCopyCmd(globals))
+	cmd.AddCommand(newDSDedupeCmd(globals))
+	cmd.AddCommand(newDSMigrateCmd(globals))
+	cmd.AddCommand(newDSBackfillCmd(globals))
+	cmd.AddCommand(newDSMigratePropCmd(globals))
+	cmd.AddCommand(newDSSQLCmd(globals))
+	cmd.AddCommand(newDSSchemaCmd(globals))
+	cmd.AddCommand(newDSSchemaDiffCmd(globals))
+	cmd.AddCommand(newDSSnapshotCmd(globals))
+	cmd.AddCommand(newDSDiffCmd(globals))
 
 	return cmd
 }
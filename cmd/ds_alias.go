@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/notionid"
+	"github.com/yourorg/notionctl/internal/render"
+)
+
+func newDSAliasCmd(globals *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Manage data source aliases for the active profile",
+	}
+
+	cmd.AddCommand(newDSAliasSetCmd(globals))
+	cmd.AddCommand(newDSAliasListCmd(globals))
+
+	return cmd
+}
+
+func newDSAliasSetCmd(globals *globalOptions) *cobra.Command {
+	var makeDefault bool
+
+	cmd := &cobra.Command{
+		Use:   "set <alias> <data-source-id|url>",
+		Short: "Save a short name for a data source ID",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dataSourceID, err := notionid.Parse(args[1])
+			if err != nil {
+				return fmt.Errorf("parse data source ID: %w", err)
+			}
+			if err := config.SaveDataSourceAlias(globals.profile, args[0], dataSourceID, makeDefault); err != nil {
+				return fmt.Errorf("save alias: %w", err)
+			}
+			if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Saved alias %q → %s\n", args[0], dataSourceID); err != nil {
+				return fmt.Errorf("write confirmation: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&makeDefault, "default", false, "Use this alias as the profile's default data source")
+
+	return cmd
+}
+
+func newDSAliasListCmd(globals *globalOptions) *cobra.Command {
+	format := formatTable
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List data source aliases",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			settings, err := config.LoadDataSourceSettings(globals.profile)
+			if err != nil {
+				return fmt.Errorf("load aliases: %w", err)
+			}
+
+			switch format {
+			case formatJSON:
+				return writeJSON(cmd.Context(), cmd.OutOrStdout(), map[string]any{
+					"aliases": settings.Aliases,
+					"default": settings.Default,
+				})
+			case formatTable:
+				headers := []string{"Alias", "Data Source ID", "Default"}
+				return render.Table(cmd.OutOrStdout(), headers, aliasRows(settings))
+			default:
+				return fmt.Errorf("unknown format %q (expected json or table)", format)
+			}
+		},
+	}
+
+	formatFlag(cmd, &format, "Output format", formatJSON, formatTable)
+
+	return cmd
+}
+
+func aliasRows(settings config.DataSourceSettings) [][]string {
+	names := render.SortedKeys(settings.Aliases)
+
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		marker := ""
+		if name == settings.Default {
+			marker = "*"
+		}
+		rows = append(rows, []string{name, settings.Aliases[name], marker})
+	}
+	return rows
+}
@@ -0,0 +1,383 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/expand"
+	"github.com/yourorg/notionctl/internal/expr"
+	"github.com/yourorg/notionctl/internal/filelock"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/props"
+	"github.com/yourorg/notionctl/internal/schema"
+	"github.com/yourorg/notionctl/internal/where"
+)
+
+const resumeFilePermissions = 0o600
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type dsBackfillOptions struct {
+	dataSourceID string
+	property     string
+	fromExpr     string
+	defaultValue string
+	whereExpr    string
+	resumeFile   string
+	exec         executionOptions
+	retry        retryOptions
+	overwrite    bool
+	dryRun       bool
+}
+
+// backfillClient is the subset of the Notion client used to backfill a property.
+type backfillClient interface {
+	dataSourceQuerier
+	expand.PropertyItemFetcher
+	UpdatePage(ctx context.Context, pageID string, req notion.UpdatePageRequest) (notion.Page, error)
+}
+
+// backfillSummary tallies the outcome of a backfill run.
+type backfillSummary struct {
+	Matched int `json:"matched"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+	Resumed int `json:"resumed"`
+	Failed  int `json:"failed"`
+}
+
+func (s backfillSummary) String() string {
+	return fmt.Sprintf("%d matched: %d updated, %d skipped (empty value), %d already done, %d failed",
+		s.Matched, s.Updated, s.Skipped, s.Resumed, s.Failed)
+}
+
+// backfillJob fills one property on every row matching filter.
+type backfillJob struct {
+	target notion.PropertyReference
+	filter any
+	value  func(page notion.Page) (string, error)
+	// relations are read by value; Notion truncates them to 25 pages in query results, so
+	// truncated ones are read in full first.
+	relations  []notion.PropertyReference
+	done       map[string]bool
+	checkpoint func(pageIDs []string) error
+	// retry records the rows that fail and, with --retry-from, resends only those.
+	retry        *retryOptions
+	dataSourceID string
+	exec         executionOptions
+	dryRun       bool
+}
+
+func newDSBackfillCmd(globals *globalOptions) *cobra.Command {
+	opts := &dsBackfillOptions{exec: defaultExecutionOptions()}
+
+	cmd := &cobra.Command{
+		Use:   "backfill",
+		Short: "Fill a property across existing rows from an expression or default value",
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Target Notion data source ID or URL")
+	cmd.Flags().StringVar(&opts.property, "property", "", "Property to fill")
+	cmd.Flags().StringVar(&opts.fromExpr, "from-expr", "", `Expression computing the value, e.g. 'quarter(Due)'`)
+	cmd.Flags().StringVar(&opts.defaultValue, "default", "", "Constant value to write")
+	cmd.Flags().StringVar(&opts.whereExpr, "where", "", "Only fill rows matching this filter expression")
+	cmd.Flags().BoolVar(&opts.overwrite, "overwrite", false, "Also rewrite rows where the property already has a value")
+	opts.exec.register(cmd, "Rows updated between checkpoints")
+	cmd.Flags().StringVar(
+		&opts.resumeFile,
+		"resume-file",
+		"",
+		"Record finished page IDs here after each batch and skip them when rerun",
+	)
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print the value each row would receive without writing")
+	opts.retry.register(cmd, "ds backfill", "")
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "retry-from")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
+	cobra.CheckErr(cmd.MarkFlagRequired("property"))
+
+	return cmd
+}
+
+func (opts *dsBackfillOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		if err := opts.validate(); err != nil {
+			return err
+		}
+		client, err := opts.exec.buildClient(globals)
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		ds, err := client.GetDataSource(ctx, opts.dataSourceID)
+		if err != nil {
+			return fmt.Errorf("get data source: %w", err)
+		}
+		idx := schema.NewIndex(ds)
+
+		job, err := opts.job(idx)
+		if err != nil {
+			return err
+		}
+		summary, err := job.run(ctx, client, cmd.OutOrStdout(), cmd.ErrOrStderr())
+		safeLog(cmd.ErrOrStderr(), "Backfill %s", summary)
+		return job.saveFailures(err, cmd.ErrOrStderr())
+	}
+}
+
+func (opts *dsBackfillOptions) validate() error {
+	if (opts.fromExpr == "") == (opts.defaultValue == "") {
+		return errors.New("exactly one of --from-expr or --default is required")
+	}
+	return opts.exec.validate()
+}
+
+func (opts *dsBackfillOptions) job(idx *schema.Index) (*backfillJob, error) {
+	target, ok := idx.ReferenceForName(opts.property)
+	if !ok {
+		return nil, fmt.Errorf("unknown property %q", opts.property)
+	}
+	value, err := backfillValue(idx, opts.defaultValue, opts.fromExpr)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := backfillFilter(idx, target, opts.whereExpr, opts.overwrite)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &backfillJob{
+		dataSourceID: opts.dataSourceID,
+		target:       target,
+		filter:       filter,
+		value:        value,
+		exec:         opts.exec,
+		dryRun:       opts.dryRun,
+		retry:        &opts.retry,
+	}
+	if opts.resumeFile != "" && !opts.dryRun {
+		if job.done, err = readResumeFile(opts.resumeFile); err != nil {
+			return nil, err
+		}
+		job.checkpoint = func(ids []string) error { return appendResumeFile(opts.resumeFile, ids) }
+	}
+	return job, nil
+}
+
+// backfillValue returns a function computing the value for a row: the constant when
+// fromExpr is empty, otherwise the expression evaluated against the row's property text.
+func backfillValue(idx *schema.Index, constant, fromExpr string) (func(notion.Page) (string, error), error) {
+	if fromExpr == "" {
+		return func(notion.Page) (string, error) { return constant, nil }, nil
+	}
+	compiled, err := expr.Parse(fromExpr)
+	if err != nil {
+		return nil, fmt.Errorf("parse --from-expr: %w", err)
+	}
+	for _, name := range compiled.Properties() {
+		if _, ok := idx.ReferenceForName(name); !ok {
+			return nil, fmt.Errorf("--from-expr: unknown property %q", name)
+		}
+	}
+	return func(page notion.Page) (string, error) {
+		return compiled.Eval(func(name string) (string, bool) {
+			ref, ok := idx.ReferenceForName(name)
+			if !ok {
+				return "", false
+			}
+			return summarizeProperty(page.Properties[ref.Name]), true
+		})
+	}, nil
+}
+
+// backfillFilter combines an optional --where expression with an is-empty check on the
+// target unless existing values are overwritten.
+func backfillFilter(idx *schema.Index, target notion.PropertyReference, whereExpr string, overwrite bool) (any, error) {
+	var conditions []any
+	if !overwrite {
+		empty, err := where.IsEmpty(target)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, empty)
+	}
+	if strings.TrimSpace(whereExpr) != "" {
+		compiled, err := where.Compile(whereExpr, idx)
+		if err != nil {
+			return nil, fmt.Errorf("parse where: %w", err)
+		}
+		conditions = append(conditions, compiled)
+	}
+	switch len(conditions) {
+	case 0:
+		return nil, nil
+	case 1:
+		return conditions[0], nil
+	default:
+		return map[string]any{"and": conditions}, nil
+	}
+}
+
+// run collects every matching row up front, because filling rows changes which rows an
+// is-empty filter returns, then updates them in concurrent batches with a checkpoint after
+// each. A batch always runs to completion so its checkpoint covers every row that succeeded.
+// Failed rows are logged and recorded, and do not stop the rows after them.
+func (j *backfillJob) run(ctx context.Context, client backfillClient, out, log io.Writer) (backfillSummary, error) {
+	var summary backfillSummary
+	if j.retry != nil && j.retry.retryFrom != "" {
+		updated, failed, err := j.retry.retryPageUpdates(ctx, j.exec, client, log)
+		summary = backfillSummary{Matched: updated + failed, Updated: updated, Failed: failed}
+		if err == nil && failed > 0 {
+			err = fmt.Errorf("%d of %d rows failed", failed, summary.Matched)
+		}
+		return summary, err
+	}
+	resp, err := executeDataSourceQuery(ctx, client, j.dataSourceID, notion.QueryDataSourceRequest{Filter: j.filter}, true, 0)
+	if err != nil {
+		return summary, err
+	}
+	summary.Matched = len(resp.Results)
+	var firstErr error
+
+	batchSize := j.exec.batchSize
+	for start := 0; start < len(resp.Results); start += batchSize {
+		batch := resp.Results[start:min(start+batchSize, len(resp.Results))]
+		if err := expand.CompleteRelations(ctx, client, batch, j.relations, j.exec.concurrency); err != nil {
+			return summary, fmt.Errorf("read relations: %w", err)
+		}
+		values := make([]string, len(batch))
+		updates := make([]map[string]any, len(batch))
+		failed := make([]error, len(batch))
+		_ = j.exec.forEach(ctx, len(batch), func(ctx context.Context, i int) error {
+			if !j.done[batch[i].ID] {
+				values[i], updates[i], failed[i] = j.fill(ctx, client, batch[i])
+			}
+			return nil
+		})
+
+		finished := make([]string, 0, len(batch))
+		for i, page := range batch {
+			switch {
+			case j.done[page.ID]:
+				summary.Resumed++
+			case failed[i] != nil:
+				summary.Failed++
+				safeLog(log, "page %s: %v", page.ID, failed[i])
+				if firstErr == nil {
+					firstErr = fmt.Errorf("page %s: %w", page.ID, failed[i])
+				}
+				j.record(start+i, page.ID, updates[i], failed[i])
+			case values[i] != "":
+				summary.Updated++
+				finished = append(finished, page.ID)
+				if j.dryRun {
+					if err := writeLine(out, fmt.Sprintf("%s\t%s=%s", page.ID, j.target.Name, values[i])); err != nil {
+						return summary, err
+					}
+				}
+			default:
+				summary.Skipped++
+				finished = append(finished, page.ID)
+			}
+		}
+		if err := j.save(finished); err != nil {
+			return summary, err
+		}
+		safeLog(log, "progress: %d/%d rows", min(start+batchSize, len(resp.Results)), summary.Matched)
+	}
+	if summary.Failed > 0 {
+		return summary, fmt.Errorf("%d of %d rows failed: %w", summary.Failed, summary.Matched, firstErr)
+	}
+	return summary, nil
+}
+
+// record adds a failed row to the failures file. Rows that failed before their update was
+// built are recorded without a payload.
+func (j *backfillJob) record(index int, pageID string, properties map[string]any, err error) {
+	if j.retry == nil || j.dryRun {
+		return
+	}
+	var payload *pageUpdatePayload
+	if properties != nil {
+		payload = &pageUpdatePayload{PageID: pageID, Properties: properties}
+	}
+	j.retry.record(index, 0, payload, err)
+}
+
+// saveFailures writes the failures file after a run that ended with err, unless this is a
+// dry run.
+func (j *backfillJob) saveFailures(err error, log io.Writer) error {
+	if j.retry == nil || j.dryRun {
+		return err
+	}
+	if werr := j.retry.save(log); werr != nil && err == nil {
+		return werr
+	}
+	return err
+}
+
+// fill writes the computed value to page and returns it with the properties sent; an empty
+// value means the row was skipped. Dry runs compute the value without writing.
+func (j *backfillJob) fill(ctx context.Context, client backfillClient, page notion.Page) (string, map[string]any, error) {
+	value, err := j.value(page)
+	if err != nil || value == "" {
+		return "", nil, err
+	}
+	payload, err := props.Coerce(j.target, value)
+	if err != nil {
+		return "", nil, err
+	}
+	if j.dryRun {
+		return value, nil, nil
+	}
+	properties := map[string]any{j.target.Name: payload}
+	if _, err := client.UpdatePage(ctx, page.ID, notion.UpdatePageRequest{Properties: properties}); err != nil {
+		return "", properties, fmt.Errorf("update page: %w", err)
+	}
+	return value, properties, nil
+}
+
+func (j *backfillJob) save(pageIDs []string) error {
+	if j.checkpoint == nil || len(pageIDs) == 0 {
+		return nil
+	}
+	return j.checkpoint(pageIDs)
+}
+
+func readResumeFile(path string) (map[string]bool, error) {
+	done := map[string]bool{}
+	f, err := os.Open(path) // #nosec G304 -- reading user-supplied resume file is intentional
+	if errors.Is(err, os.ErrNotExist) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open resume file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if id := strings.TrimSpace(scanner.Text()); id != "" {
+			done[id] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read resume file: %w", err)
+	}
+	return done, nil
+}
+
+func appendResumeFile(path string, pageIDs []string) error {
+	if err := filelock.Append(path, []byte(strings.Join(pageIDs, "\n")+"\n"), resumeFilePermissions); err != nil {
+		return fmt.Errorf("append resume file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+type fakeBackfillClient struct {
+	mu      sync.Mutex
+	rows    []notion.Page
+	filter  any
+	updates map[string]map[string]any
+	// relations serves complete relation lists by page ID.
+	relations map[string][]notion.RelationReference
+}
+
+func (f *fakeBackfillClient) RetrievePageProperty(
+	_ context.Context,
+	pageID string,
+	_ string,
+	_ string,
+) (notion.PropertyItemResponse, error) {
+	var resp notion.PropertyItemResponse
+	for _, rel := range f.relations[pageID] {
+		resp.Results = append(resp.Results, notion.PropertyItem{Type: "relation", Relation: &rel})
+	}
+	return resp, nil
+}
+
+func (f *fakeBackfillClient) QueryDataSource(
+	_ context.Context,
+	_ string,
+	req notion.QueryDataSourceRequest,
+) (notion.QueryDataSourceResponse, error) {
+	f.filter = req.Filter
+	return notion.QueryDataSourceResponse{Results: f.rows}, nil
+}
+
+func (f *fakeBackfillClient) UpdatePage(_ context.Context, pageID string, req notion.UpdatePageRequest) (notion.Page, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updates[pageID] = req.Properties
+	return notion.Page{ID: pageID}, nil
+}
+
+func TestBackfillJobFromExprWithResume(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{
+		Properties: map[string]notion.PropertyReference{
+			"Due":     {ID: "due", Name: "Due", Type: "date"},
+			"Quarter": {ID: "qtr", Name: "Quarter", Type: "select"},
+		},
+	})
+	due := func(id, date string) notion.Page {
+		value := notion.PropertyValue{Type: "date"}
+		if date != "" {
+			value.Date = &notion.DateValue{Start: date}
+		}
+		return notion.Page{ID: id, Properties: map[string]notion.PropertyValue{"Due": value}}
+	}
+	client := &fakeBackfillClient{
+		rows:    []notion.Page{due("p1", "2025-02-01"), due("p2", "2025-08-15"), due("p3", ""), due("p4", "2025-11-30")},
+		updates: map[string]map[string]any{},
+	}
+
+	resume := filepath.Join(t.TempDir(), "resume.txt")
+	if err := os.WriteFile(resume, []byte("p4\n"), 0o600); err != nil {
+		t.Fatalf("write resume file: %v", err)
+	}
+	opts := &dsBackfillOptions{
+		dataSourceID: "ds",
+		property:     "quarter",
+		fromExpr:     "quarter(Due)",
+		exec:         executionOptions{concurrency: 2, batchSize: 2, requestsPerSecond: 1},
+		resumeFile:   resume,
+	}
+	job, err := opts.job(idx)
+	if err != nil {
+		t.Fatalf("job returned error: %v", err)
+	}
+
+	var log bytes.Buffer
+	summary, err := job.run(context.Background(), client, &bytes.Buffer{}, &log)
+	if err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	want := backfillSummary{Matched: 4, Updated: 2, Skipped: 1, Resumed: 1}
+	if summary != want {
+		t.Fatalf("summary = %+v, want %+v", summary, want)
+	}
+	if got := client.updates["p2"]["Quarter"].(map[string]any)["select"]; got.(map[string]any)["name"] != "Q3" {
+		t.Fatalf("unexpected p2 update: %#v", client.updates["p2"])
+	}
+	if filter, _ := client.filter.(map[string]any); filter["property"] != "qtr" {
+		t.Fatalf("expected is-empty filter on Quarter, got %#v", client.filter)
+	}
+
+	data, err := os.ReadFile(resume)
+	if err != nil {
+		t.Fatalf("read resume file: %v", err)
+	}
+	if got := strings.Fields(string(data)); len(got) != 4 {
+		t.Fatalf("expected every processed row in the resume file, got %q", got)
+	}
+	if !strings.Contains(log.String(), "progress: 4/4 rows") {
+		t.Fatalf("unexpected progress log: %q", log.String())
+	}
+}
+
+func TestBackfillDryRunDoesNotWrite(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{
+		Properties: map[string]notion.PropertyReference{
+			"Team": {ID: "team", Name: "Team", Type: "select"},
+		},
+	})
+	client := &fakeBackfillClient{rows: []notion.Page{{ID: "p1"}}, updates: map[string]map[string]any{}}
+	opts := &dsBackfillOptions{dataSourceID: "ds", property: "Team", defaultValue: "Unknown", exec: defaultExecutionOptions(), dryRun: true}
+	job, err := opts.job(idx)
+	if err != nil {
+		t.Fatalf("job returned error: %v", err)
+	}
+
+	var out bytes.Buffer
+	if _, err := job.run(context.Background(), client, &out, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if len(client.updates) != 0 || !strings.Contains(out.String(), "p1\tTeam=Unknown") {
+		t.Fatalf("unexpected dry run: updates=%#v out=%q", client.updates, out.String())
+	}
+}
@@ -0,0 +1,472 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/expand"
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+// dryRunDataSourceID stands in for the copy's ID in --dry-run, where nothing is created.
+const dryRunDataSourceID = "00000000000000000000000000000000"
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type dsCopyOptions struct {
+	fromID       string
+	toDatabaseID string
+	name         string
+	exec         executionOptions
+	retry        retryOptions
+	content      bool
+	dryRun       bool
+}
+
+// copyClient is the subset of the Notion client used to copy a data source.
+type copyClient interface {
+	pageBlockClient
+	dataSourceQuerier
+	GetDataSource(ctx context.Context, dataSourceID string) (notion.DataSource, error)
+	CreateDataSource(ctx context.Context, req notion.CreateDataSourceRequest) (notion.DataSource, error)
+	UpdateDataSource(ctx context.Context, dataSourceID string, req notion.UpdateDataSourceRequest) (notion.DataSource, error)
+	CreatePage(ctx context.Context, req notion.CreatePageRequest) (notion.Page, error)
+	UpdatePage(ctx context.Context, pageID string, req notion.UpdatePageRequest) (notion.Page, error)
+	RetrievePageProperty(
+		ctx context.Context,
+		pageID string,
+		propertyID string,
+		startCursor string,
+	) (notion.PropertyItemResponse, error)
+}
+
+// copySchema is a source schema split by when each property can be created. Relations to
+// the source itself must wait for the copy's ID, and rollups and formulas may refer to them.
+type copySchema struct {
+	base          map[string]any
+	selfRelations map[string]any
+	derived       map[string]any
+}
+
+// copySummary tallies the outcome of a copy.
+type copySummary struct {
+	DataSourceID string
+	Pages        int
+	Copied       int
+	Failed       int
+}
+
+// copyFailurePayload identifies a page that failed to copy in the failures file. Copy is the
+// page created for it, if any, which may lack content or relations.
+type copyFailurePayload struct {
+	Source       string `json:"source"`
+	Copy         string `json:"copy,omitempty"`
+	DataSourceID string `json:"data_source_id"`
+}
+
+func (s copySummary) String() string {
+	return fmt.Sprintf("%d pages to data source %s: %d copied, %d failed", s.Pages, s.DataSourceID, s.Copied, s.Failed)
+}
+
+func newDSCopyCmd(globals *globalOptions) *cobra.Command {
+	opts := &dsCopyOptions{exec: defaultExecutionOptions()}
+
+	cmd := &cobra.Command{
+		Use:   "copy",
+		Short: "Copy a data source's schema and pages into another database",
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(newIDValue(&opts.fromID), "from", "Data source ID or URL to copy")
+	cmd.Flags().Var(newIDValue(&opts.toDatabaseID), "to-database", "Database ID or URL that receives the new data source")
+	cmd.Flags().StringVar(&opts.name, "name", "", "Name of the new data source (default: the source's name)")
+	cmd.Flags().BoolVar(&opts.content, "content", false, "Copy each page's block content as well as its properties")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print each create/update request that would be sent without sending it")
+	opts.exec.register(cmd, "")
+	opts.retry.registerRecordOnly(cmd, "ds copy")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("from"))
+	cobra.CheckErr(cmd.MarkFlagRequired("to-database"))
+
+	return cmd
+}
+
+func (opts *dsCopyOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		client, err := opts.exec.buildClient(globals)
+		if err != nil {
+			return err
+		}
+		if opts.dryRun {
+			client = dryRunClient(client, cmd.OutOrStdout())
+		}
+
+		summary, err := opts.copy(cmd.Context(), client, cmd.ErrOrStderr())
+		if err != nil {
+			return err
+		}
+		if opts.dryRun {
+			safeLog(cmd.ErrOrStderr(), "Dry run: would copy %s", summary)
+			return nil
+		}
+		if err := opts.retry.save(cmd.ErrOrStderr()); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Copied %s\n", summary); err != nil {
+			return fmt.Errorf("write summary: %w", err)
+		}
+		if summary.Failed > 0 {
+			return fmt.Errorf("%d of %d pages failed", summary.Failed, summary.Pages)
+		}
+		return nil
+	}
+}
+
+// copy creates the new data source, then copies every page. Relations between copied pages
+// are pointed at the copies once all pages exist.
+func (opts *dsCopyOptions) copy(ctx context.Context, client copyClient, log io.Writer) (copySummary, error) {
+	var summary copySummary
+	src, err := client.GetDataSource(ctx, opts.fromID)
+	if err != nil {
+		return summary, fmt.Errorf("get data source: %w", err)
+	}
+	schema, err := splitCopySchema(src)
+	if err != nil {
+		return summary, err
+	}
+	dst, err := opts.createDataSource(ctx, client, src, schema)
+	if err != nil {
+		return summary, err
+	}
+	summary.DataSourceID = dst
+
+	pages, err := opts.sourcePages(ctx, client, src)
+	if err != nil {
+		return summary, err
+	}
+	copies := make(map[string]string, len(pages))
+	var mu sync.Mutex
+	created := make([]string, len(pages))
+	failed := make([]error, len(pages))
+	_ = opts.exec.forEach(ctx, len(pages), func(ctx context.Context, i int) error {
+		newID, err := opts.copyPage(ctx, client, schema, dst, pages[i])
+		created[i] = newID
+		if err == nil && newID != "" {
+			mu.Lock()
+			copies[pages[i].ID] = newID
+			mu.Unlock()
+		}
+		failed[i] = err
+		return nil
+	})
+	_ = opts.exec.forEach(ctx, len(pages), func(ctx context.Context, i int) error {
+		if failed[i] == nil {
+			failed[i] = linkCopiedPage(ctx, client, schema, pages[i], copies)
+		}
+		return nil
+	})
+
+	for i, page := range pages {
+		summary.Pages++
+		if failed[i] != nil {
+			summary.Failed++
+			safeLog(log, "page %s: %v", page.ID, failed[i])
+			opts.retry.record(i, 0, copyFailurePayload{Source: page.ID, Copy: created[i], DataSourceID: dst}, failed[i])
+			continue
+		}
+		summary.Copied++
+	}
+	return summary, nil
+}
+
+// createDataSource recreates src's schema under --to-database in up to three requests and
+// returns the new data source's ID.
+func (opts *dsCopyOptions) createDataSource(
+	ctx context.Context,
+	client copyClient,
+	src notion.DataSource,
+	schema copySchema,
+) (string, error) {
+	name := opts.name
+	if name == "" {
+		name = src.Name
+	}
+	created, err := client.CreateDataSource(ctx, notion.CreateDataSourceRequest{
+		Parent:     notion.DatabaseParent(opts.toDatabaseID),
+		Title:      []notion.RichText{{Type: "text", Text: &notion.Text{Content: name}}},
+		Properties: schema.base,
+	})
+	if err != nil {
+		return "", fmt.Errorf("create data source: %w", err)
+	}
+	dst := created.ID
+	if dst == "" && opts.dryRun {
+		dst = dryRunDataSourceID
+	}
+
+	for _, property := range schema.selfRelations {
+		property.(map[string]any)[relationType].(map[string]any)["data_source_id"] = dst
+	}
+	for _, properties := range []map[string]any{schema.selfRelations, schema.derived} {
+		if len(properties) == 0 {
+			continue
+		}
+		if _, err := client.UpdateDataSource(ctx, dst, notion.UpdateDataSourceRequest{Properties: properties}); err != nil {
+			return "", fmt.Errorf("add properties to data source %s: %w", dst, err)
+		}
+	}
+	return dst, nil
+}
+
+// sourcePages reads every page of src, completing relations Notion truncated to 25 entries.
+func (opts *dsCopyOptions) sourcePages(ctx context.Context, client copyClient, src notion.DataSource) ([]notion.Page, error) {
+	var pages []notion.Page
+	for page, err := range notion.QueryDataSourceIterWith(ctx, client.QueryDataSource, src.ID, notion.QueryDataSourceRequest{
+		PageSize: maxQueryPageSize,
+	}) {
+		if err != nil {
+			return nil, fmt.Errorf("query data source: %w", err)
+		}
+		pages = append(pages, page)
+	}
+
+	var relations []notion.PropertyReference
+	for _, ref := range src.Properties {
+		if ref.Type == relationType {
+			relations = append(relations, ref)
+		}
+	}
+	if err := expand.CompleteRelations(ctx, client, pages, relations, opts.exec.concurrency); err != nil {
+		return nil, fmt.Errorf("read relations: %w", err)
+	}
+	return pages, nil
+}
+
+// copyPage creates the copy of page, leaving relations to the source data source for
+// linkCopiedPage, and returns the copy's ID (empty in a dry run).
+func (opts *dsCopyOptions) copyPage(
+	ctx context.Context,
+	client copyClient,
+	schema copySchema,
+	dst string,
+	page notion.Page,
+) (string, error) {
+	properties := map[string]any{}
+	for name, value := range page.Properties {
+		if _, ok := schema.selfRelations[name]; ok {
+			continue
+		}
+		if payload, ok := copyValue(value); ok {
+			properties[name] = map[string]any{value.Type: payload}
+		}
+	}
+	created, err := client.CreatePage(ctx, notion.CreatePageRequest{
+		Parent:     notion.DataSourceParent(dst),
+		Properties: properties,
+	})
+	if err != nil {
+		return "", fmt.Errorf("create page: %w", err)
+	}
+	if !opts.content || created.ID == "" {
+		return created.ID, nil
+	}
+
+	blocks, err := fetchBlockTree(ctx, client, page.ID)
+	if err != nil {
+		return created.ID, fmt.Errorf("read content: %w", err)
+	}
+	if err := appendBlocks(ctx, client, created.ID, copyBlocks(blocks)); err != nil {
+		return created.ID, fmt.Errorf("copy content: %w", err)
+	}
+	return created.ID, nil
+}
+
+// linkCopiedPage points the copy's relations to the source data source at the copied pages.
+// Related pages that failed to copy are left out.
+func linkCopiedPage(
+	ctx context.Context,
+	client copyClient,
+	schema copySchema,
+	page notion.Page,
+	copies map[string]string,
+) error {
+	newID, ok := copies[page.ID]
+	if !ok {
+		return nil
+	}
+	properties := map[string]any{}
+	for name, value := range page.Properties {
+		if _, ok := schema.selfRelations[name]; !ok || len(value.Relation) == 0 {
+			continue
+		}
+		ids := make([]map[string]string, 0, len(value.Relation))
+		for _, rel := range value.Relation {
+			if copied, ok := copies[rel.ID]; ok {
+				ids = append(ids, map[string]string{"id": copied})
+			}
+		}
+		properties[name] = map[string]any{relationType: ids}
+	}
+	if len(properties) == 0 {
+		return nil
+	}
+	if _, err := client.UpdatePage(ctx, newID, notion.UpdatePageRequest{Properties: properties}); err != nil {
+		return fmt.Errorf("link copied relations: %w", err)
+	}
+	return nil
+}
+
+// splitCopySchema converts src's property schemas into creation requests. Option IDs are
+// dropped so the copy gets its own, and pages are later matched to options by name.
+// Relations become one-way so copying never adds properties to other data sources.
+func splitCopySchema(src notion.DataSource) (copySchema, error) {
+	schema := copySchema{base: map[string]any{}, selfRelations: map[string]any{}, derived: map[string]any{}}
+	for name, ref := range src.Properties {
+		var raw map[string]any
+		if len(ref.Raw) > 0 {
+			if err := json.Unmarshal(ref.Raw, &raw); err != nil {
+				return copySchema{}, fmt.Errorf("decode schema of %s: %w", name, err)
+			}
+		}
+		config, _ := raw[ref.Type].(map[string]any)
+		if config == nil {
+			config = map[string]any{}
+		}
+
+		switch ref.Type {
+		case relationType:
+			target, _ := config["data_source_id"].(string)
+			config = map[string]any{"data_source_id": target, "type": "single_property", "single_property": map[string]any{}}
+			property := map[string]any{relationType: config}
+			if target == src.ID {
+				schema.selfRelations[name] = property
+			} else {
+				schema.base[name] = property
+			}
+		case "rollup":
+			schema.derived[name] = map[string]any{"rollup": map[string]any{
+				"relation_property_name": config["relation_property_name"],
+				"rollup_property_name":   config["rollup_property_name"],
+				"function":               config["function"],
+			}}
+		case "formula":
+			schema.derived[name] = map[string]any{"formula": map[string]any{"expression": config["expression"]}}
+		default:
+			if options, ok := config["options"].([]any); ok {
+				config["options"] = copyOptions(options)
+			}
+			delete(config, "groups")
+			schema.base[name] = map[string]any{ref.Type: config}
+		}
+	}
+	return schema, nil
+}
+
+// copyOptions keeps the name and color of each select, multi-select, or status option.
+func copyOptions(options []any) []any {
+	copied := make([]any, 0, len(options))
+	for _, option := range options {
+		m, ok := option.(map[string]any)
+		if !ok {
+			continue
+		}
+		entry := map[string]any{"name": m["name"]}
+		if color, ok := m["color"]; ok {
+			entry["color"] = color
+		}
+		copied = append(copied, entry)
+	}
+	return copied
+}
+
+// copyValue converts a page property value into its write payload. Options are matched by
+// name, so they resolve to the copy's options. ok is false for read-only types such as
+// formulas, rollups, and timestamps, and for relations, which the caller handles.
+func copyValue(value notion.PropertyValue) (any, bool) {
+	var raw map[string]any
+	if len(value.Raw) > 0 && json.Unmarshal(value.Raw, &raw) != nil {
+		return nil, false
+	}
+	content := raw[value.Type]
+
+	switch value.Type {
+	case "title", "rich_text":
+		if content == nil {
+			return []any{}, true
+		}
+		return content, true
+	case "number", "checkbox", "date", "url", "email", "phone_number":
+		return content, true
+	case "select", statusType:
+		option, _ := content.(map[string]any)
+		if option == nil {
+			return nil, true
+		}
+		return map[string]any{"name": option["name"]}, true
+	case "multi_select":
+		names := make([]map[string]any, 0, len(value.MultiSelect))
+		for _, option := range value.MultiSelect {
+			names = append(names, map[string]any{"name": option.Name})
+		}
+		return names, true
+	case "people":
+		ids := make([]map[string]string, 0, len(value.People))
+		for _, person := range value.People {
+			ids = append(ids, map[string]string{"id": person.ID})
+		}
+		return ids, true
+	case relationType:
+		ids := make([]map[string]string, 0, len(value.Relation))
+		for _, rel := range value.Relation {
+			ids = append(ids, map[string]string{"id": rel.ID})
+		}
+		return ids, true
+	case "files":
+		// Files uploaded to Notion have expiring URLs, so only external links carry over.
+		files := make([]map[string]any, 0, len(value.Files))
+		for _, file := range value.Files {
+			if file.External != nil {
+				files = append(files, map[string]any{"name": file.Name, "type": "external", "external": file.External})
+			}
+		}
+		return files, true
+	default:
+		return nil, false
+	}
+}
+
+// copyBlocks strips the IDs Notion assigned to blocks so they can be appended elsewhere.
+// Block types this tool does not model, child pages, and images uploaded to Notion are
+// left out.
+func copyBlocks(blocks []notion.Block) []notion.Block {
+	copied := make([]notion.Block, 0, len(blocks))
+	for _, block := range blocks {
+		if !modeledBlock(block) || (block.Image != nil && block.Image.External == nil) {
+			continue
+		}
+		children := copyBlocks(block.Children())
+		block = block.WithChildren(nil)
+		block.ID, block.Object, block.HasChildren = "", "", false
+		copied = append(copied, block.WithChildren(children))
+	}
+	return copied
+}
+
+// modeledBlock reports whether block's type content was decoded, i.e. whether it can be
+// sent back to Notion without losing the content.
+func modeledBlock(block notion.Block) bool {
+	data, err := json.Marshal(block)
+	if err != nil {
+		return false
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return false
+	}
+	_, ok := fields[block.Type]
+	return ok
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+const copySourceJSON = `{
+  "id": "src",
+  "name": "Tasks",
+  "properties": {
+    "Name": {"id": "title", "name": "Name", "type": "title", "title": {}},
+    "Stage": {"id": "stg", "name": "Stage", "type": "select",
+      "select": {"options": [{"id": "o1", "name": "Todo", "color": "red"}]}},
+    "Parent": {"id": "par", "name": "Parent", "type": "relation",
+      "relation": {"data_source_id": "src", "type": "dual_property", "dual_property": {}}},
+    "Project": {"id": "prj", "name": "Project", "type": "relation",
+      "relation": {"data_source_id": "projects", "type": "single_property", "single_property": {}}},
+    "Score": {"id": "scr", "name": "Score", "type": "formula", "formula": {"expression": "1 + 1"}}
+  }
+}`
+
+const copyPagesJSON = `[
+  {"id": "p1", "properties": {
+    "Name": {"id": "title", "type": "title", "title": [{"type": "text", "text": {"content": "One"}, "plain_text": "One"}]},
+    "Stage": {"id": "stg", "type": "select", "select": {"id": "o1", "name": "Todo", "color": "red"}},
+    "Parent": {"id": "par", "type": "relation", "relation": []},
+    "Project": {"id": "prj", "type": "relation", "relation": [{"id": "proj-1"}]},
+    "Score": {"id": "scr", "type": "formula", "formula": {"type": "number", "number": 2}}
+  }},
+  {"id": "p2", "properties": {
+    "Name": {"id": "title", "type": "title", "title": [{"type": "text", "text": {"content": "Two"}, "plain_text": "Two"}]},
+    "Parent": {"id": "par", "type": "relation", "relation": [{"id": "p1"}]}
+  }}
+]`
+
+// fakeCopyClient serves a fixed source data source and records what the copy writes.
+type fakeCopyClient struct {
+	fakeBlockTree
+	mu            sync.Mutex
+	source        notion.DataSource
+	pages         []notion.Page
+	created       notion.CreateDataSourceRequest
+	schemaUpdates []notion.UpdateDataSourceRequest
+	pageCreates   map[string]notion.CreatePageRequest
+	pageUpdates   map[string]notion.UpdatePageRequest
+}
+
+func newFakeCopyClient(t *testing.T) *fakeCopyClient {
+	t.Helper()
+	client := &fakeCopyClient{
+		fakeBlockTree: fakeBlockTree{children: map[string][]notion.Block{}},
+		pageCreates:   map[string]notion.CreatePageRequest{},
+		pageUpdates:   map[string]notion.UpdatePageRequest{},
+	}
+	if err := json.Unmarshal([]byte(copySourceJSON), &client.source); err != nil {
+		t.Fatalf("decode source: %v", err)
+	}
+	if err := json.Unmarshal([]byte(copyPagesJSON), &client.pages); err != nil {
+		t.Fatalf("decode pages: %v", err)
+	}
+	return client
+}
+
+func (f *fakeCopyClient) GetDataSource(_ context.Context, _ string) (notion.DataSource, error) {
+	return f.source, nil
+}
+
+func (f *fakeCopyClient) QueryDataSource(
+	_ context.Context,
+	_ string,
+	_ notion.QueryDataSourceRequest,
+) (notion.QueryDataSourceResponse, error) {
+	return notion.QueryDataSourceResponse{Results: f.pages}, nil
+}
+
+func (f *fakeCopyClient) RetrievePageProperty(
+	_ context.Context,
+	_ string,
+	_ string,
+	_ string,
+) (notion.PropertyItemResponse, error) {
+	return notion.PropertyItemResponse{}, nil
+}
+
+func (f *fakeCopyClient) CreateDataSource(_ context.Context, req notion.CreateDataSourceRequest) (notion.DataSource, error) {
+	f.created = req
+	return notion.DataSource{ID: "dst"}, nil
+}
+
+func (f *fakeCopyClient) UpdateDataSource(
+	_ context.Context,
+	_ string,
+	req notion.UpdateDataSourceRequest,
+) (notion.DataSource, error) {
+	f.schemaUpdates = append(f.schemaUpdates, req)
+	return notion.DataSource{ID: "dst"}, nil
+}
+
+func (f *fakeCopyClient) CreatePage(_ context.Context, req notion.CreatePageRequest) (notion.Page, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := fmt.Sprintf("copy-%d", len(f.pageCreates)+1)
+	f.pageCreates[id] = req
+	return notion.Page{ID: id}, nil
+}
+
+func (f *fakeCopyClient) UpdatePage(_ context.Context, pageID string, req notion.UpdatePageRequest) (notion.Page, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pageUpdates[pageID] = req
+	return notion.Page{ID: pageID}, nil
+}
+
+func jsonString(t *testing.T, value any) string {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return string(data)
+}
+
+func TestCopyRecreatesSchemaAndRemapsRelations(t *testing.T) {
+	client := newFakeCopyClient(t)
+	opts := &dsCopyOptions{fromID: "src", toDatabaseID: "db", exec: defaultExecutionOptions()}
+	opts.exec.concurrency = 1
+
+	summary, err := opts.copy(context.Background(), client, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("copy returned error: %v", err)
+	}
+	if summary != (copySummary{DataSourceID: "dst", Pages: 2, Copied: 2}) {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+
+	base := client.created.Properties
+	if _, ok := base["Parent"]; ok || len(base) != 3 {
+		t.Fatalf("expected Name, Stage, and Project in the create request, got %v", jsonString(t, base))
+	}
+	if got := jsonString(t, base["Stage"]); got != `{"select":{"options":[{"color":"red","name":"Todo"}]}}` {
+		t.Fatalf("select options were not copied without IDs: %s", got)
+	}
+	if len(client.schemaUpdates) != 2 {
+		t.Fatalf("expected the self relation and the formula to be added afterwards, got %d updates", len(client.schemaUpdates))
+	}
+	if got := jsonString(t, client.schemaUpdates[0].Properties["Parent"]); got !=
+		`{"relation":{"data_source_id":"dst","single_property":{},"type":"single_property"}}` {
+		t.Fatalf("self relation does not point at the copy: %s", got)
+	}
+
+	first := client.pageCreates["copy-1"].Properties
+	if got := jsonString(t, first["Stage"]); got != `{"select":{"name":"Todo"}}` {
+		t.Fatalf("select value was not matched by name: %s", got)
+	}
+	if _, ok := first["Score"]; ok {
+		t.Fatal("formula values must not be written")
+	}
+	if got := jsonString(t, client.pageUpdates["copy-2"].Properties); got != `{"Parent":{"relation":[{"id":"copy-1"}]}}` {
+		t.Fatalf("self relation was not remapped to the copied page: %s", got)
+	}
+}
+
+func TestCopyBlocksStripsIDs(t *testing.T) {
+	client := newFakeCopyClient(t)
+	client.store("p1", []notion.Block{
+		{Type: "paragraph", Paragraph: &notion.ParagraphBlock{
+			Children: []notion.Block{{Type: "divider", Divider: &struct{}{}}},
+		}},
+		{Type: "child_page"},
+	})
+	blocks, err := fetchBlockTree(context.Background(), client, "p1")
+	if err != nil {
+		t.Fatalf("fetchBlockTree returned error: %v", err)
+	}
+
+	copied := copyBlocks(blocks)
+	if got := jsonString(t, copied); got != `[{"paragraph":{"rich_text":null,"children":[{"divider":{},"type":"divider"}]},"type":"paragraph"}]` {
+		t.Fatalf("unexpected copied blocks: %s", got)
+	}
+}
@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/render"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type dsDedupeOptions struct {
+	dataSourceID string
+	by           []string
+	format       string
+	exec         executionOptions
+	retry        retryOptions
+	archive      bool
+	dryRun       bool
+}
+
+// dedupeClient is the subset of the Notion client used to find and archive duplicates.
+type dedupeClient interface {
+	dataSourceQuerier
+	GetDataSource(ctx context.Context, dataSourceID string) (notion.DataSource, error)
+	UpdatePage(ctx context.Context, pageID string, req notion.UpdatePageRequest) (notion.Page, error)
+}
+
+// duplicateCluster is a set of pages sharing the --by values. Keep is the most recently
+// edited page; Duplicates are the rest, newest first.
+type duplicateCluster struct {
+	Values     map[string]string `json:"values"`
+	Keep       dedupePage        `json:"keep"`
+	Duplicates []dedupePage      `json:"duplicates"`
+}
+
+// dedupePage identifies a page in a cluster.
+type dedupePage struct {
+	LastEdited time.Time `json:"last_edited_time"`
+	pageSummary
+}
+
+func newDSDedupeCmd(globals *globalOptions) *cobra.Command {
+	opts := &dsDedupeOptions{format: formatTable, exec: defaultExecutionOptions()}
+
+	cmd := &cobra.Command{
+		Use:   "dedupe",
+		Short: "Report pages that share property values and optionally archive the older copies",
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Data source ID or URL to check")
+	cmd.Flags().StringSliceVar(&opts.by, "by", nil, "Properties whose values identify a duplicate (repeatable or comma-separated)")
+	formatFlag(cmd, &opts.format, "Output format", formatJSON, formatTable)
+	cmd.Flags().BoolVar(&opts.archive, "archive", false, "Archive every page but the most recently edited one in each cluster")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "With --archive, print the archive requests to stderr without sending them")
+	opts.exec.register(cmd, "")
+	opts.retry.register(cmd, "ds dedupe", "")
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "retry-from")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
+	cobra.CheckErr(cmd.MarkFlagRequired("by"))
+
+	return cmd
+}
+
+func (opts *dsDedupeOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		if opts.format != formatJSON && opts.format != formatTable {
+			return fmt.Errorf("unknown format %q (expected json or table)", opts.format)
+		}
+		if opts.retry.retryFrom != "" && !opts.archive {
+			return errors.New("--retry-from retries archiving and needs --archive")
+		}
+		client, err := opts.exec.buildClient(globals)
+		if err != nil {
+			return err
+		}
+		if opts.dryRun {
+			client = dryRunClient(client, cmd.ErrOrStderr())
+		}
+
+		ctx := cmd.Context()
+		var archived, failed int
+		if opts.retry.retryFrom != "" {
+			if archived, failed, err = opts.retry.retryPageUpdates(ctx, opts.exec, client, cmd.ErrOrStderr()); err != nil {
+				return err
+			}
+		} else {
+			clusters, err := findDuplicates(ctx, client, opts.dataSourceID, opts.by)
+			if err != nil {
+				return err
+			}
+			if err := opts.render(cmd, clusters); err != nil {
+				return err
+			}
+			if !opts.archive {
+				return nil
+			}
+			archived, failed = opts.archiveDuplicates(ctx, client, clusters, cmd.ErrOrStderr())
+		}
+		if opts.dryRun {
+			safeLog(cmd.ErrOrStderr(), "Dry run: would archive %d pages", archived)
+			return nil
+		}
+		if err := opts.retry.save(cmd.ErrOrStderr()); err != nil {
+			return err
+		}
+		safeLog(cmd.ErrOrStderr(), "Archived %d pages, %d failed", archived, failed)
+		if failed > 0 {
+			return fmt.Errorf("%d pages could not be archived", failed)
+		}
+		return nil
+	}
+}
+
+// findDuplicates reads every page of the data source and groups them by the normalized
+// values of the by properties. Pages whose values are all empty are never duplicates.
+func findDuplicates(
+	ctx context.Context,
+	client dedupeClient,
+	dataSourceID string,
+	by []string,
+) ([]duplicateCluster, error) {
+	ds, err := client.GetDataSource(ctx, dataSourceID)
+	if err != nil {
+		return nil, fmt.Errorf("get data source: %w", err)
+	}
+	idx := schema.NewIndex(ds)
+	refs := make([]notion.PropertyReference, 0, len(by))
+	ids := make([]string, 0, len(by))
+	for _, name := range by {
+		ref, ok := idx.ReferenceForName(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("unknown --by property %q", name)
+		}
+		refs = append(refs, ref)
+		ids = append(ids, ref.ID)
+	}
+	if len(refs) == 0 {
+		return nil, errors.New("--by needs at least one property")
+	}
+	for _, ref := range ds.Properties {
+		if ref.Type == "title" {
+			// Keep titles in the response so the report can name each page.
+			ids = append(ids, ref.ID)
+		}
+	}
+
+	groups := map[string][]notion.Page{}
+	values := map[string]map[string]string{}
+	req := notion.QueryDataSourceRequest{FilterProperties: ids, PageSize: maxQueryPageSize}
+	for page, err := range notion.QueryDataSourceIterWith(ctx, client.QueryDataSource, dataSourceID, req) {
+		if err != nil {
+			return nil, fmt.Errorf("query data source: %w", err)
+		}
+		key, shown := dedupeKey(page, refs)
+		if key == "" {
+			continue
+		}
+		groups[key] = append(groups[key], page)
+		if _, ok := values[key]; !ok {
+			values[key] = shown
+		}
+	}
+
+	clusters := []duplicateCluster{}
+	for _, key := range render.SortedKeys(groups) {
+		pages := groups[key]
+		if len(pages) < 2 {
+			continue
+		}
+		sort.SliceStable(pages, func(i, j int) bool {
+			return pages[i].LastEditedTime.After(pages[j].LastEditedTime)
+		})
+		cluster := duplicateCluster{Values: values[key], Keep: newDedupePage(pages[0])}
+		for _, page := range pages[1:] {
+			cluster.Duplicates = append(cluster.Duplicates, newDedupePage(page))
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters, nil
+}
+
+// dedupeKey joins the page's values for refs, trimmed, with runs of whitespace collapsed,
+// and case-folded. It returns an empty key when every value is empty, along with the
+// values as shown in the report.
+func dedupeKey(page notion.Page, refs []notion.PropertyReference) (string, map[string]string) {
+	parts := make([]string, 0, len(refs))
+	shown := make(map[string]string, len(refs))
+	empty := true
+	for _, ref := range refs {
+		value := summarizePageProperty(page, ref.Name)
+		shown[ref.Name] = value
+		normalized := strings.ToLower(strings.Join(strings.Fields(value), " "))
+		if normalized != "" {
+			empty = false
+		}
+		parts = append(parts, normalized)
+	}
+	if empty {
+		return "", shown
+	}
+	return strings.Join(parts, "\x1f"), shown
+}
+
+func newDedupePage(page notion.Page) dedupePage {
+	return dedupePage{
+		LastEdited:  page.LastEditedTime,
+		pageSummary: pageSummary{ID: page.ID, Title: pageTitle(page), URL: page.URL},
+	}
+}
+
+func (opts *dsDedupeOptions) render(cmd *cobra.Command, clusters []duplicateCluster) error {
+	if opts.format == formatJSON {
+		if err := writeJSON(cmd.Context(), cmd.OutOrStdout(), clusters); err != nil {
+			return fmt.Errorf("render json: %w", err)
+		}
+		return nil
+	}
+
+	rows := make([][]string, 0, len(clusters))
+	for i, cluster := range clusters {
+		label := make([]string, 0, len(opts.by))
+		for _, name := range render.SortedKeys(cluster.Values) {
+			label = append(label, fmt.Sprintf("%s=%s", name, cluster.Values[name]))
+		}
+		rows = append(rows, dedupeRow(i+1, "keep", strings.Join(label, ", "), cluster.Keep))
+		for _, page := range cluster.Duplicates {
+			rows = append(rows, dedupeRow(i+1, "duplicate", "", page))
+		}
+	}
+	if err := render.Table(cmd.OutOrStdout(), []string{"Cluster", "Action", "Values", "ID", "Title", "Last Edited"}, rows); err != nil {
+		return fmt.Errorf("render table: %w", err)
+	}
+	return nil
+}
+
+func dedupeRow(cluster int, action, values string, page dedupePage) []string {
+	return []string{
+		fmt.Sprint(cluster),
+		action,
+		values,
+		page.ID,
+		page.Title,
+		page.LastEdited.UTC().Format(time.RFC3339),
+	}
+}
+
+// archiveDuplicates archives every duplicate with up to --concurrency requests in flight
+// and returns how many were archived and how many failed. Failures are recorded for
+// --retry-from.
+func (opts *dsDedupeOptions) archiveDuplicates(
+	ctx context.Context,
+	client dedupeClient,
+	clusters []duplicateCluster,
+	log io.Writer,
+) (int, int) {
+	var pages []dedupePage
+	for _, cluster := range clusters {
+		pages = append(pages, cluster.Duplicates...)
+	}
+	archived := true
+	failed := make([]error, len(pages))
+	_ = opts.exec.forEach(ctx, len(pages), func(ctx context.Context, i int) error {
+		_, failed[i] = client.UpdatePage(ctx, pages[i].ID, notion.UpdatePageRequest{Archived: &archived})
+		return nil
+	})
+
+	count := 0
+	for i, page := range pages {
+		if failed[i] != nil {
+			safeLog(log, "archive %s: %v", page.ID, failed[i])
+			opts.retry.record(i, 0, pageUpdatePayload{PageID: page.ID, Archived: true}, failed[i])
+			continue
+		}
+		count++
+	}
+	return count, len(pages) - count
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+// fakeDedupeClient serves titled pages and records which pages were archived.
+type fakeDedupeClient struct {
+	mu       sync.Mutex
+	pages    []notion.Page
+	archived []string
+}
+
+func (f *fakeDedupeClient) GetDataSource(_ context.Context, _ string) (notion.DataSource, error) {
+	return notion.DataSource{Properties: map[string]notion.PropertyReference{
+		"Name": {ID: "title", Name: "Name", Type: "title"},
+	}}, nil
+}
+
+func (f *fakeDedupeClient) QueryDataSource(
+	_ context.Context,
+	_ string,
+	_ notion.QueryDataSourceRequest,
+) (notion.QueryDataSourceResponse, error) {
+	return notion.QueryDataSourceResponse{Results: f.pages}, nil
+}
+
+func (f *fakeDedupeClient) UpdatePage(_ context.Context, pageID string, req notion.UpdatePageRequest) (notion.Page, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if req.Archived != nil && *req.Archived {
+		f.archived = append(f.archived, pageID)
+	}
+	return notion.Page{ID: pageID}, nil
+}
+
+func titledPage(id, title string, edited time.Time) notion.Page {
+	return notion.Page{ID: id, LastEditedTime: edited, Properties: map[string]notion.PropertyValue{
+		"Name": {Type: "title", Title: []notion.RichText{{PlainText: title}}},
+	}}
+}
+
+func TestDedupeKeepsMostRecentlyEditedPage(t *testing.T) {
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := &fakeDedupeClient{pages: []notion.Page{
+		titledPage("a1", "Launch plan", day),
+		titledPage("a2", "launch  PLAN ", day.Add(2*time.Hour)),
+		titledPage("a3", "Launch plan", day.Add(time.Hour)),
+		titledPage("b1", "Budget", day),
+		titledPage("e1", "", day),
+		titledPage("e2", "", day),
+	}}
+
+	clusters, err := findDuplicates(context.Background(), client, "ds", []string{"Name"})
+	if err != nil {
+		t.Fatalf("findDuplicates returned error: %v", err)
+	}
+	if len(clusters) != 1 {
+		t.Fatalf("expected one cluster (empty titles are not duplicates), got %+v", clusters)
+	}
+	cluster := clusters[0]
+	if cluster.Keep.ID != "a2" || len(cluster.Duplicates) != 2 || cluster.Duplicates[0].ID != "a3" {
+		t.Fatalf("expected a2 kept and a3, a1 as duplicates, got %+v", cluster)
+	}
+
+	opts := &dsDedupeOptions{exec: defaultExecutionOptions()}
+	archived, failed := opts.archiveDuplicates(context.Background(), client, clusters, &bytes.Buffer{})
+	if archived != 2 || failed != 0 || len(client.archived) != 2 {
+		t.Fatalf("expected both duplicates archived, got %d archived, %d failed (%v)", archived, failed, client.archived)
+	}
+}
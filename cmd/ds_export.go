@@ -0,0 +1,330 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/mirror"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+const (
+	formatJSONL = "jsonl"
+	formatCSV   = "csv"
+)
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type dsExportOptions struct {
+	query        *dsQueryOptions
+	format       string
+	outPath      string
+	table        string
+	sqliteBinary string
+	includeProps []string
+	excludeProps []string
+	skipEmpty    bool
+}
+
+func newDSExportCmd(globals *globalOptions) *cobra.Command {
+	opts := &dsExportOptions{
+		query:        &dsQueryOptions{format: formatJSON, fetchAll: true, exec: defaultExecutionOptions()},
+		format:       formatJSONL,
+		table:        "pages",
+		sqliteBinary: "sqlite3",
+	}
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export every row of a data source to JSON, JSONL, CSV, or SQLite",
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(newIDValue(&opts.query.dataSourceID), "data-source-id", "Target Notion data source ID or URL")
+	formatFlag(cmd, &opts.format, "Output format", formatJSON, formatJSONL, formatCSV, formatSQLite)
+	cmd.Flags().StringVar(&opts.outPath, "out", "", "Write the export to this file instead of stdout (required for sqlite)")
+	cmd.Flags().StringVar(&opts.table, "table", opts.table, "With --format sqlite, the table to create (replaced if it exists)")
+	cmd.Flags().StringVar(&opts.sqliteBinary, "sqlite3", opts.sqliteBinary, "sqlite3 shell used to write --format sqlite")
+	cmd.Flags().StringVar(&opts.query.filterJSON, "filter", "", "Inline JSON filter payload")
+	cmd.Flags().StringVar(&opts.query.filterFile, "filter-file", "", "Path to JSON filter payload (- for stdin)")
+	cmd.Flags().StringVar(&opts.query.whereExpr, "where", "", "Filter expression (see ds query --where)")
+	cmd.Flags().StringVar(&opts.query.sortSpec, "sort", "", `Sort shorthand such as "Due:asc,Priority:desc"`)
+	cmd.Flags().StringVar(&opts.query.viewRef, "view", "", "Saved view ID, URL, or name (see ds query --view)")
+	cmd.Flags().IntVar(&opts.query.limit, "limit", 0, "Export at most this many rows")
+	cmd.Flags().StringSliceVar(
+		&opts.includeProps,
+		"include-props",
+		nil,
+		"Glob patterns of property names to export (default: all)",
+	)
+	cmd.Flags().StringSliceVar(
+		&opts.excludeProps,
+		"exclude-props",
+		nil,
+		"Glob patterns of property names to omit from the export",
+	)
+	cmd.Flags().BoolVar(&opts.skipEmpty, "skip-empty", false, "Drop empty property values from exported rows")
+	opts.query.exec.register(cmd, "")
+
+	return cmd
+}
+
+func (opts *dsExportOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		if err := opts.validate(); err != nil {
+			return err
+		}
+		opts.query.stdin = cmd.InOrStdin()
+
+		client, err := opts.query.exec.buildClient(globals)
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		if err := opts.query.loadView(ctx, client); err != nil {
+			return err
+		}
+		index, err := opts.query.resolveIndex(ctx, client)
+		if err != nil {
+			return err
+		}
+		names, err := selectPropertyNames(index, opts.includeProps, opts.excludeProps)
+		if err != nil {
+			return err
+		}
+		if len(opts.includeProps) > 0 || len(opts.excludeProps) > 0 {
+			opts.query.filterProperties = names
+		}
+
+		req, err := opts.query.buildRequest(index)
+		if err != nil {
+			return err
+		}
+		// JSON output is a single array, so rows are buffered; the other formats stream them.
+		var rows iter.Seq2[notion.Page, error]
+		if opts.format == formatJSON {
+			resp, err := executeDataSourceQuery(ctx, client, opts.query.dataSourceID, req, true, opts.query.limit)
+			if err != nil {
+				return err
+			}
+			rows = pageSeq(resp.Results)
+		} else {
+			rows = streamDataSourceQuery(ctx, client, opts.query.dataSourceID, req, opts.query.limit)
+		}
+		if opts.skipEmpty {
+			rows = dropEmptyRows(rows)
+		}
+		return opts.write(ctx, cmd.OutOrStdout(), rows, index, names)
+	}
+}
+
+func (opts *dsExportOptions) validate() error {
+	if opts.query.dataSourceID == "" && opts.query.viewRef == "" {
+		return errors.New("--data-source-id or --view is required")
+	}
+	if opts.query.limit < 0 {
+		return errors.New("--limit must not be negative")
+	}
+	switch opts.format {
+	case formatJSON, formatJSONL, formatCSV:
+		return nil
+	case formatSQLite:
+		if opts.outPath == "" {
+			return errors.New("--format sqlite requires --out")
+		}
+		if strings.TrimSpace(opts.table) == "" {
+			return errors.New("--table must not be empty")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q (expected json, jsonl, csv, or sqlite)", opts.format)
+	}
+}
+
+func (opts *dsExportOptions) write(
+	ctx context.Context,
+	stdout io.Writer,
+	rows iter.Seq2[notion.Page, error],
+	idx *schema.Index,
+	names []string,
+) (err error) {
+	if opts.format == formatSQLite {
+		db, err := mirror.Open(opts.outPath, opts.sqliteBinary)
+		if err != nil {
+			return err
+		}
+		if err := writePagesSQLite(ctx, db, opts.table, rows, idx, names); err != nil {
+			return fmt.Errorf("write export: %w", err)
+		}
+		return nil
+	}
+
+	w := stdout
+	if opts.outPath != "" {
+		f, createErr := os.Create(opts.outPath)
+		if createErr != nil {
+			return fmt.Errorf("create %s: %w", opts.outPath, createErr)
+		}
+		// A failed close can lose buffered data, so it fails the export.
+		defer func() {
+			if cerr := f.Close(); cerr != nil {
+				err = errors.Join(err, fmt.Errorf("close %s: %w", opts.outPath, cerr))
+			}
+		}()
+		w = f
+	}
+
+	switch opts.format {
+	case formatJSON:
+		var pages []notion.Page
+		if pages, err = collectPages(rows); err == nil {
+			err = writeJSON(ctx, w, pages)
+		}
+	case formatJSONL:
+		err = writeJSONLines(w, rows)
+	case formatCSV:
+		err = writePagesCSV(w, rows, idx, names)
+	}
+	if err != nil {
+		return fmt.Errorf("write export: %w", err)
+	}
+	return nil
+}
+
+// selectPropertyNames applies include/exclude globs (case-insensitive) to the schema's
+// property names, returning them in schema order.
+func selectPropertyNames(idx *schema.Index, include, exclude []string) ([]string, error) {
+	for _, pattern := range append(append([]string{}, include...), exclude...) {
+		if _, err := path.Match(strings.ToLower(pattern), ""); err != nil {
+			return nil, fmt.Errorf("invalid property pattern %q: %w", pattern, err)
+		}
+	}
+
+	var names []string
+	for _, name := range idx.PropertyNames() {
+		if len(include) > 0 && !matchesAnyGlob(name, include) {
+			continue
+		}
+		if matchesAnyGlob(name, exclude) {
+			continue
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, errors.New("property filters exclude every property")
+	}
+	return names, nil
+}
+
+func matchesAnyGlob(name string, patterns []string) bool {
+	lower := strings.ToLower(name)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(strings.TrimSpace(pattern)), lower); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func dropEmptyProperties(pages []notion.Page) []notion.Page {
+	for i := range pages {
+		dropEmptyPageProperties(pages[i])
+	}
+	return pages
+}
+
+func dropEmptyPageProperties(page notion.Page) {
+	for name, value := range page.Properties {
+		if summarizeProperty(value) == "" {
+			delete(page.Properties, name)
+		}
+	}
+}
+
+func dropEmptyRows(rows iter.Seq2[notion.Page, error]) iter.Seq2[notion.Page, error] {
+	return func(yield func(notion.Page, error) bool) {
+		for page, err := range rows {
+			if err == nil {
+				dropEmptyPageProperties(page)
+			}
+			if !yield(page, err) {
+				return
+			}
+		}
+	}
+}
+
+// pageSeq adapts already fetched pages to the row sequences the writers consume.
+func pageSeq(pages []notion.Page) iter.Seq2[notion.Page, error] {
+	return func(yield func(notion.Page, error) bool) {
+		for _, page := range pages {
+			if !yield(page, nil) {
+				return
+			}
+		}
+	}
+}
+
+func collectPages(rows iter.Seq2[notion.Page, error]) ([]notion.Page, error) {
+	var pages []notion.Page
+	for page, err := range rows {
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, page)
+	}
+	return pages, nil
+}
+
+func writeJSONLines(w io.Writer, rows iter.Seq2[notion.Page, error]) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for page, err := range rows {
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(page); err != nil {
+			return fmt.Errorf("encode page %s: %w", page.ID, err)
+		}
+	}
+	return nil
+}
+
+func writePagesCSV(w io.Writer, rows iter.Seq2[notion.Page, error], idx *schema.Index, names []string) error {
+	cw := csv.NewWriter(w)
+	header := append([]string{"ID", "Last Edited"}, names...)
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("write csv header: %w", err)
+	}
+	for page, err := range rows {
+		if err != nil {
+			cw.Flush()
+			return err
+		}
+		row := []string{page.ID, page.LastEditedTime.UTC().Format(time.RFC3339)}
+		for _, name := range names {
+			ref, _ := idx.ReferenceForName(name)
+			row = append(row, summarizeProperty(page.Properties[ref.Name]))
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("write csv row: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("flush csv: %w", err)
+	}
+	return nil
+}
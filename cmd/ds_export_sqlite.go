@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"context"
+	"iter"
+
+	"github.com/yourorg/notionctl/internal/mirror"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+const formatSQLite = "sqlite"
+
+// sqliteReplacer is the subset of mirror.DB used to write an export.
+type sqliteReplacer interface {
+	Replace(ctx context.Context, t mirror.Table, rows []mirror.Row) error
+}
+
+// writePagesSQLite replaces table with one row per page, using the same columns and types
+// as sync mirror restricted to names. Other tables in the database are left alone.
+func writePagesSQLite(
+	ctx context.Context,
+	db sqliteReplacer,
+	table string,
+	rows iter.Seq2[notion.Page, error],
+	idx *schema.Index,
+	names []string,
+) error {
+	var out []mirror.Row
+	for page, err := range rows {
+		if err != nil {
+			return err
+		}
+		out = append(out, mirrorRow(page, idx, names))
+	}
+	return db.Replace(ctx, mirrorTable(table, idx, names), out)
+}
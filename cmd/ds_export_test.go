@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/mirror"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+func TestSelectPropertyNames(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{
+		Properties: map[string]notion.PropertyReference{
+			"Name":        {ID: "title", Name: "Name", Type: "title"},
+			"Notes":       {ID: "notes", Name: "Notes", Type: "rich_text"},
+			"Attachments": {ID: "files", Name: "Attachments", Type: "files"},
+			"Status":      {ID: "st", Name: "Status", Type: "status"},
+		},
+	})
+
+	names, err := selectPropertyNames(idx, nil, []string{"notes", "attach*"})
+	if err != nil {
+		t.Fatalf("selectPropertyNames returned error: %v", err)
+	}
+	if len(names) != 2 || names[0] != "Name" || names[1] != "Status" {
+		t.Fatalf("unexpected exclude result: %#v", names)
+	}
+
+	names, err = selectPropertyNames(idx, []string{"N*"}, []string{"notes"})
+	if err != nil {
+		t.Fatalf("selectPropertyNames returned error: %v", err)
+	}
+	if len(names) != 1 || names[0] != "Name" {
+		t.Fatalf("unexpected include result: %#v", names)
+	}
+
+	if _, err := selectPropertyNames(idx, []string{"zzz*"}, nil); err == nil {
+		t.Fatalf("expected error when every property is filtered out")
+	}
+}
+
+func TestDSExportValidateLimit(t *testing.T) {
+	opts := &dsExportOptions{query: &dsQueryOptions{dataSourceID: "ds"}, format: formatJSONL}
+	if err := opts.validate(); err != nil {
+		t.Fatalf("--limit 0 means no limit, got %v", err)
+	}
+	opts.query.limit = -1
+	if err := opts.validate(); err == nil || err.Error() != "--limit must not be negative" {
+		t.Fatalf("expected a negative --limit to be rejected, got %v", err)
+	}
+	if err := opts.query.validate(); err == nil || err.Error() != "--limit must not be negative" {
+		t.Fatalf("expected ds query to reject a negative --limit, got %v", err)
+	}
+}
+
+func TestDropEmptyProperties(t *testing.T) {
+	pages := []notion.Page{{
+		ID: "p1",
+		Properties: map[string]notion.PropertyValue{
+			"Name":  {Type: "title", Title: []notion.RichText{{PlainText: "Row"}}},
+			"Notes": {Type: "rich_text"},
+		},
+	}}
+
+	pages = dropEmptyProperties(pages)
+	if _, ok := pages[0].Properties["Notes"]; ok {
+		t.Fatalf("expected empty Notes to be dropped")
+	}
+	if _, ok := pages[0].Properties["Name"]; !ok {
+		t.Fatalf("expected Name to be kept")
+	}
+}
+
+// fakeReplacer records the table and rows an export writes.
+type fakeReplacer struct {
+	table mirror.Table
+	rows  []mirror.Row
+}
+
+func (f *fakeReplacer) Replace(_ context.Context, t mirror.Table, rows []mirror.Row) error {
+	f.table, f.rows = t, rows
+	return nil
+}
+
+func TestWritePagesSQLite(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{
+		Properties: map[string]notion.PropertyReference{
+			"Name":   {ID: "title", Name: "Name", Type: "title"},
+			"Points": {ID: "pts", Name: "Points", Type: "number"},
+			"Done":   {ID: "done", Name: "Done", Type: "checkbox"},
+			"Due":    {ID: "due", Name: "Due", Type: "date"},
+			"Notes":  {ID: "notes", Name: "Notes", Type: "rich_text"},
+		},
+	})
+	points, done := 3.5, true
+	pages := []notion.Page{{ID: "p1", Properties: map[string]notion.PropertyValue{
+		"Name":   {Type: "title", Title: []notion.RichText{{PlainText: "Ship"}}},
+		"Points": {Type: "number", Number: &points},
+		"Done":   {Type: "checkbox", Checkbox: &done},
+		"Due":    {Type: "date", Date: &notion.DateValue{Start: "2025-06-01"}},
+	}}}
+
+	db := &fakeReplacer{}
+	names := []string{"Done", "Due", "Name", "Points"}
+	if err := writePagesSQLite(context.Background(), db, "tasks", pageSeq(pages), idx, names); err != nil {
+		t.Fatalf("writePagesSQLite returned error: %v", err)
+	}
+
+	types := map[string]string{}
+	for _, col := range db.table.Columns {
+		types[col.Name] = col.Type
+	}
+	if db.table.Name != "tasks" || types["Points"] != mirror.TypeReal || types["Done"] != mirror.TypeInteger ||
+		types["Due"] != mirror.TypeText || types["Notes"] != "" {
+		t.Fatalf("unexpected table: %+v", db.table)
+	}
+	row := db.rows[0]
+	if len(db.rows) != 1 || row["id"] != "p1" || row["Points"] != 3.5 || row["Done"] != true || row["Due"] != "2025-06-01" {
+		t.Fatalf("unexpected rows: %#v", db.rows)
+	}
+}
@@ -0,0 +1,523 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/props"
+	"github.com/yourorg/notionctl/internal/schema"
+	"github.com/yourorg/notionctl/internal/where"
+)
+
+const (
+	defaultImportProgressEvery = 100
+	maxImportLineBytes         = 8 << 20
+)
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type dsImportOptions struct {
+	dataSourceID  string
+	ndjsonPath    string
+	keyProperty   string
+	progressEvery int
+	planFormat    string
+	exec          executionOptions
+	retry         retryOptions
+	failFast      bool
+	plan          bool
+	dryRun        bool
+
+	index  *schema.Index
+	keyRef notion.PropertyReference
+
+	// createdMu guards createdKeys, the pages this run created by key value, which a query
+	// may not return yet.
+	createdMu   sync.Mutex
+	createdKeys map[string]string
+}
+
+// importClient is the subset of the Notion client used by imports.
+type importClient interface {
+	QueryDataSource(
+		ctx context.Context,
+		dataSourceID string,
+		req notion.QueryDataSourceRequest,
+	) (notion.QueryDataSourceResponse, error)
+	CreatePage(ctx context.Context, req notion.CreatePageRequest) (notion.Page, error)
+	UpdatePage(ctx context.Context, pageID string, req notion.UpdatePageRequest) (notion.Page, error)
+}
+
+// importSummary tallies the outcome of an import run.
+type importSummary struct {
+	Rows    int `json:"rows"`
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Failed  int `json:"failed"`
+}
+
+func (s importSummary) String() string {
+	return fmt.Sprintf("%d rows: %d created, %d updated, %d failed", s.Rows, s.Created, s.Updated, s.Failed)
+}
+
+// importLine is one NDJSON row with its 1-based line and 0-based row index, or why it
+// cannot be imported.
+type importLine struct {
+	data  []byte
+	err   error
+	line  int
+	index int
+}
+
+func newDSImportCmd(globals *globalOptions) *cobra.Command {
+	opts := &dsImportOptions{
+		progressEvery: defaultImportProgressEvery,
+		planFormat:    formatTable,
+		exec:          defaultExecutionOptions(),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Create or upsert data source rows from newline-delimited JSON",
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Target Notion data source ID or URL")
+	cmd.Flags().StringVar(&opts.ndjsonPath, "ndjson", "", "Path to NDJSON rows (- for stdin)")
+	cmd.Flags().StringVar(
+		&opts.keyProperty,
+		"key",
+		"",
+		"Property used to match existing rows; matching rows are updated instead of created",
+	)
+	cmd.Flags().IntVar(&opts.progressEvery, "progress-every", opts.progressEvery, "Report progress every N rows (0 disables)")
+	cmd.Flags().BoolVar(&opts.failFast, "fail-fast", false, "Stop at the first row that fails")
+	cmd.Flags().BoolVar(
+		&opts.plan,
+		"plan",
+		false,
+		"Print the create/update/no-op/conflict breakdown with per-field diffs without writing",
+	)
+	formatFlag(cmd, &opts.planFormat, "Plan output format", formatJSON, formatTable)
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print each create/update request that would be sent without sending it")
+	cmd.MarkFlagsMutuallyExclusive("plan", "dry-run")
+	opts.exec.register(cmd, "Rows read ahead and written concurrently")
+	opts.retry.register(cmd, "ds import", "ndjson")
+	cmd.MarkFlagsMutuallyExclusive("plan", "retry-from")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
+
+	return cmd
+}
+
+func (opts *dsImportOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		client, err := opts.exec.buildClient(globals)
+		if err != nil {
+			return err
+		}
+		if opts.dryRun {
+			client = dryRunClient(client, cmd.OutOrStdout())
+		}
+
+		ctx := cmd.Context()
+		if err := opts.prepare(ctx, client); err != nil {
+			return err
+		}
+
+		var summary importSummary
+		if opts.retry.retryFrom != "" {
+			items, err := opts.retry.load()
+			if err != nil {
+				return err
+			}
+			summary, err = opts.retryRows(ctx, client, items, cmd.ErrOrStderr())
+		} else {
+			input, closeInput, err := openInput(opts.ndjsonPath, cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			defer closeInput()
+
+			if opts.plan {
+				plan, err := opts.planRows(ctx, client, input)
+				if err != nil {
+					return err
+				}
+				return writeImportPlan(cmd.Context(), cmd.OutOrStdout(), opts.planFormat, plan)
+			}
+			summary, err = opts.importRows(ctx, client, input, cmd.ErrOrStderr())
+		}
+		if opts.dryRun {
+			safeLog(cmd.ErrOrStderr(), "Dry run: would import %s", summary)
+			return err
+		}
+		if werr := opts.retry.save(cmd.ErrOrStderr()); werr != nil && err == nil {
+			err = werr
+		}
+		if _, werr := fmt.Fprintf(cmd.OutOrStdout(), "Imported %s\n", summary); werr != nil && err == nil {
+			err = fmt.Errorf("write summary: %w", werr)
+		}
+		return err
+	}
+}
+
+func (opts *dsImportOptions) prepare(ctx context.Context, client *notion.Client) error {
+	ds, err := client.GetDataSource(ctx, opts.dataSourceID)
+	if err != nil {
+		return fmt.Errorf("get data source: %w", err)
+	}
+	opts.index = schema.NewIndex(ds)
+
+	if opts.keyProperty != "" {
+		ref, ok := opts.index.ReferenceForName(opts.keyProperty)
+		if !ok {
+			return fmt.Errorf("unknown --key property %q", opts.keyProperty)
+		}
+		opts.keyRef = ref
+	}
+	return nil
+}
+
+// importRows streams rows from input in batches of --batch-size, writing each batch with up
+// to --concurrency requests in flight. Results are reported in input order.
+func (opts *dsImportOptions) importRows(
+	ctx context.Context,
+	client importClient,
+	input io.Reader,
+	log io.Writer,
+) (importSummary, error) {
+	var (
+		summary importSummary
+		batch   []importLine
+	)
+	err := scanNDJSON(input, func(line int, data []byte) error {
+		batch = append(batch, importLine{data: data, line: line, index: summary.Rows + len(batch)})
+		if len(batch) < opts.exec.batchSize {
+			return nil
+		}
+		err := opts.importBatch(ctx, client, batch, &summary, log)
+		batch = batch[:0]
+		return err
+	})
+	if err != nil {
+		return summary, err
+	}
+	return summary, opts.importBatch(ctx, client, batch, &summary, log)
+}
+
+// retryRows imports the rows of a failures file in batches, as importRows does.
+func (opts *dsImportOptions) retryRows(
+	ctx context.Context,
+	client importClient,
+	items []failedItem,
+	log io.Writer,
+) (importSummary, error) {
+	var summary importSummary
+	rows := make([]importLine, 0, len(items))
+	for _, item := range items {
+		row := importLine{line: item.Line, index: item.Index}
+		var data json.RawMessage
+		if row.err = decodePayload(item, &data); row.err == nil {
+			row.data = data
+		}
+		rows = append(rows, row)
+	}
+	for start := 0; start < len(rows); start += opts.exec.batchSize {
+		batch := rows[start:min(start+opts.exec.batchSize, len(rows))]
+		if err := opts.importBatch(ctx, client, batch, &summary, log); err != nil {
+			return summary, err
+		}
+	}
+	return summary, nil
+}
+
+// importBatch writes rows with up to --concurrency requests in flight and records failed
+// rows for the failures file. With --fail-fast the first failure stops rows that have not
+// started yet, and is returned.
+func (opts *dsImportOptions) importBatch(
+	ctx context.Context,
+	client importClient,
+	rows []importLine,
+	summary *importSummary,
+	log io.Writer,
+) error {
+	created := make([]bool, len(rows))
+	failed := make([]error, len(rows))
+	skipped := make([]bool, len(rows))
+	groups := opts.keyGroups(rows)
+	_ = opts.exec.forEach(ctx, len(groups), func(ctx context.Context, g int) error {
+		var stop error
+		for _, i := range groups[g] {
+			if stop != nil || ctx.Err() != nil {
+				skipped[i] = true
+				continue
+			}
+			if failed[i] = rows[i].err; failed[i] == nil {
+				created[i], failed[i] = opts.importRow(ctx, client, rows[i].data)
+			}
+			if failed[i] != nil && opts.failFast {
+				stop = failed[i]
+			}
+		}
+		return stop
+	})
+
+	var firstErr error
+	for i, row := range rows {
+		if skipped[i] {
+			continue
+		}
+		summary.Rows++
+		switch {
+		case failed[i] != nil:
+			summary.Failed++
+			safeLog(log, "line %d: %v", row.line, failed[i])
+			opts.retry.record(row.index, row.line, json.RawMessage(row.data), failed[i])
+			if firstErr == nil {
+				firstErr = fmt.Errorf("line %d: %w", row.line, failed[i])
+			}
+		case created[i]:
+			summary.Created++
+		default:
+			summary.Updated++
+		}
+		if opts.progressEvery > 0 && summary.Rows%opts.progressEvery == 0 {
+			safeLog(log, "progress: %s", summary)
+		}
+	}
+	if opts.failFast && firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// keyGroups splits a batch into groups of row indexes that may run concurrently. With --key,
+// rows sharing a key value form one group, in input order, so two of them cannot both find
+// no match and create duplicate pages.
+func (opts *dsImportOptions) keyGroups(rows []importLine) [][]int {
+	groups := make([][]int, 0, len(rows))
+	byKey := map[string]int{}
+	for i, row := range rows {
+		var decoded map[string]any
+		if opts.keyRef.ID == "" || row.err != nil || json.Unmarshal(row.data, &decoded) != nil {
+			groups = append(groups, []int{i})
+			continue
+		}
+		key, ok := opts.keyText(decoded)
+		if !ok {
+			groups = append(groups, []int{i})
+			continue
+		}
+		if g, seen := byKey[key]; seen {
+			groups[g] = append(groups[g], i)
+			continue
+		}
+		byKey[key] = len(groups)
+		groups = append(groups, []int{i})
+	}
+	return groups
+}
+
+// scanNDJSON calls fn with the 1-based line number and content of every non-blank line.
+// Scanning stops at the first error returned by fn.
+func scanNDJSON(input io.Reader, fn func(line int, data []byte) error) error {
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxImportLineBytes)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if err := fn(line, []byte(text)); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read input: %w", err)
+	}
+	return nil
+}
+
+func (opts *dsImportOptions) importRow(ctx context.Context, client importClient, data []byte) (bool, error) {
+	var row map[string]any
+	if err := json.Unmarshal(data, &row); err != nil {
+		return false, fmt.Errorf("decode row: %w", err)
+	}
+	properties, err := rowProperties(row, opts.index)
+	if err != nil {
+		return false, err
+	}
+
+	existingID, err := opts.findExisting(ctx, client, row)
+	if err != nil {
+		return false, err
+	}
+	if existingID != "" {
+		if _, err := client.UpdatePage(ctx, existingID, notion.UpdatePageRequest{Properties: properties}); err != nil {
+			return false, fmt.Errorf("update page %s: %w", existingID, err)
+		}
+		return false, nil
+	}
+
+	page, err := client.CreatePage(ctx, notion.CreatePageRequest{
+		Parent:     notion.DataSourceParent(opts.dataSourceID),
+		Properties: properties,
+	})
+	if err != nil {
+		return false, fmt.Errorf("create page: %w", err)
+	}
+	if opts.keyRef.ID != "" {
+		if key, ok := opts.keyText(row); ok {
+			opts.createdMu.Lock()
+			if opts.createdKeys == nil {
+				opts.createdKeys = map[string]string{}
+			}
+			opts.createdKeys[key] = page.ID
+			opts.createdMu.Unlock()
+		}
+	}
+	return true, nil
+}
+
+// findExisting returns the ID of the row whose key property matches the incoming row, or
+// an empty string when no key is configured or nothing matches.
+func (opts *dsImportOptions) findExisting(ctx context.Context, client importClient, row map[string]any) (string, error) {
+	if opts.keyRef.ID == "" {
+		return "", nil
+	}
+	if key, ok := opts.keyText(row); ok {
+		opts.createdMu.Lock()
+		id := opts.createdKeys[key]
+		opts.createdMu.Unlock()
+		if id != "" {
+			return id, nil
+		}
+	}
+	key, matches, err := opts.lookupKey(ctx, client, row)
+	if err != nil {
+		return "", err
+	}
+	switch len(matches) {
+	case 0:
+		return "", nil
+	case 1:
+		return matches[0].ID, nil
+	default:
+		return "", fmt.Errorf("key %s=%q matches more than one row", opts.keyRef.Name, key)
+	}
+}
+
+// lookupKey queries the data source for rows sharing the incoming row's key value.
+func (opts *dsImportOptions) lookupKey(
+	ctx context.Context,
+	client importClient,
+	row map[string]any,
+) (string, []notion.Page, error) {
+	if _, ok := rowValue(row, opts.keyRef.Name); !ok {
+		return "", nil, fmt.Errorf("row is missing key property %q", opts.keyRef.Name)
+	}
+	text, ok := opts.keyText(row)
+	if !ok {
+		return "", nil, fmt.Errorf("key property %q must be a string or number", opts.keyRef.Name)
+	}
+
+	matches, err := pagesWithKey(ctx, client, opts.dataSourceID, opts.keyRef, text)
+	return text, matches, err
+}
+
+// keyText returns the row's --key value as text, when it has one.
+func (opts *dsImportOptions) keyText(row map[string]any) (string, bool) {
+	value, ok := rowValue(row, opts.keyRef.Name)
+	if !ok {
+		return "", false
+	}
+	return scalarString(value)
+}
+
+// pagesWithKey returns up to two pages of dataSourceID whose key property equals value,
+// which is enough to tell a unique match from an ambiguous one.
+func pagesWithKey(
+	ctx context.Context,
+	client dataSourceQuerier,
+	dataSourceID string,
+	keyRef notion.PropertyReference,
+	value string,
+) ([]notion.Page, error) {
+	filter, err := where.Equals(keyRef, value)
+	if err != nil {
+		return nil, fmt.Errorf("build key filter: %w", err)
+	}
+	resp, err := client.QueryDataSource(ctx, dataSourceID, notion.QueryDataSourceRequest{
+		Filter:   filter,
+		PageSize: 2,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("look up key %q: %w", value, err)
+	}
+	return resp.Results, nil
+}
+
+// rowProperties maps a decoded row onto schema property names and typed payloads.
+func rowProperties(row map[string]any, idx *schema.Index) (map[string]any, error) {
+	if len(row) == 0 {
+		return nil, errors.New("row has no properties")
+	}
+	properties := make(map[string]any, len(row))
+	for key, value := range row {
+		ref, ok := idx.ReferenceForName(key)
+		if !ok {
+			return nil, fmt.Errorf("unknown property %q", key)
+		}
+		payload, err := props.FromJSON(ref, value)
+		if err != nil {
+			return nil, err
+		}
+		properties[ref.Name] = payload
+	}
+	return properties, nil
+}
+
+func rowValue(row map[string]any, name string) (any, bool) {
+	for key, value := range row {
+		if strings.EqualFold(strings.TrimSpace(key), name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func scalarString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64:
+		return fmt.Sprint(v), true
+	default:
+		return "", false
+	}
+}
+
+// openInput opens path for reading, or returns stdin when path is "-".
+func openInput(path string, stdin io.Reader) (io.Reader, func(), error) {
+	if path == stdinPath {
+		return stdin, func() {}, nil
+	}
+	f, err := os.Open(path) // #nosec G304 -- reading user-supplied input file is intentional
+	if err != nil {
+		return nil, nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	return f, func() { _ = f.Close() }, nil
+}
@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/render"
+)
+
+const (
+	planCreate   = "create"
+	planUpdate   = "update"
+	planNoop     = "no-op"
+	planConflict = "conflict"
+)
+
+// importFieldDiff describes how one property would change.
+type importFieldDiff struct {
+	Property string `json:"property"`
+	Old      string `json:"old"`
+	New      string `json:"new"`
+}
+
+// importPlanEntry records what an import would do with one input line.
+//
+//nolint:govet // fieldalignment: JSON field order mirrors the table columns.
+type importPlanEntry struct {
+	Line   int               `json:"line"`
+	Action string            `json:"action"`
+	PageID string            `json:"page_id,omitempty"`
+	Key    string            `json:"key,omitempty"`
+	Reason string            `json:"reason,omitempty"`
+	Diffs  []importFieldDiff `json:"diffs,omitempty"`
+}
+
+// importPlanSummary counts plan entries by action.
+type importPlanSummary struct {
+	Create   int `json:"create"`
+	Update   int `json:"update"`
+	Noop     int `json:"no_op"`
+	Conflict int `json:"conflict"`
+}
+
+func (s importPlanSummary) String() string {
+	return fmt.Sprintf("%d create, %d update, %d no-op, %d conflict", s.Create, s.Update, s.Noop, s.Conflict)
+}
+
+// importPlan is the dry-run result of matching an import against existing rows.
+type importPlan struct {
+	Entries []importPlanEntry `json:"entries"`
+	Summary importPlanSummary `json:"summary"`
+}
+
+func (p *importPlan) add(entry importPlanEntry) {
+	p.Entries = append(p.Entries, entry)
+	switch entry.Action {
+	case planCreate:
+		p.Summary.Create++
+	case planUpdate:
+		p.Summary.Update++
+	case planNoop:
+		p.Summary.Noop++
+	default:
+		p.Summary.Conflict++
+	}
+}
+
+// planRows reads every row from input and classifies it without writing anything. Rows that
+// cannot be decoded, match several pages, or repeat a key seen earlier in the input are
+// reported as conflicts.
+func (opts *dsImportOptions) planRows(ctx context.Context, client importClient, input io.Reader) (importPlan, error) {
+	plan := importPlan{Entries: []importPlanEntry{}}
+	seenKeys := map[string]int{}
+	err := scanNDJSON(input, func(line int, data []byte) error {
+		plan.add(opts.planRow(ctx, client, line, data, seenKeys))
+		return nil
+	})
+	return plan, err
+}
+
+func (opts *dsImportOptions) planRow(
+	ctx context.Context,
+	client importClient,
+	line int,
+	data []byte,
+	seenKeys map[string]int,
+) importPlanEntry {
+	entry := importPlanEntry{Line: line}
+	conflict := func(err error) importPlanEntry {
+		entry.Action = planConflict
+		entry.Reason = err.Error()
+		return entry
+	}
+
+	var row map[string]any
+	if err := json.Unmarshal(data, &row); err != nil {
+		return conflict(fmt.Errorf("decode row: %w", err))
+	}
+	properties, err := rowProperties(row, opts.index)
+	if err != nil {
+		return conflict(err)
+	}
+
+	var existing *notion.Page
+	if opts.keyRef.ID != "" {
+		key, matches, err := opts.lookupKey(ctx, client, row)
+		if err != nil {
+			return conflict(err)
+		}
+		entry.Key = key
+		if first, dup := seenKeys[key]; dup {
+			return conflict(fmt.Errorf("key %q already appears on line %d", key, first))
+		}
+		seenKeys[key] = line
+		if len(matches) > 1 {
+			return conflict(fmt.Errorf("key %s=%q matches more than one row", opts.keyRef.Name, key))
+		}
+		if len(matches) == 1 {
+			existing = &matches[0]
+			entry.PageID = existing.ID
+		}
+	}
+
+	var current map[string]notion.PropertyValue
+	if existing != nil {
+		current = existing.Properties
+	}
+	entry.Diffs = opts.diffProperties(current, properties)
+
+	switch {
+	case existing == nil:
+		entry.Action = planCreate
+	case len(entry.Diffs) == 0:
+		entry.Action = planNoop
+	default:
+		entry.Action = planUpdate
+	}
+	return entry
+}
+
+// diffProperties compares incoming payloads with the current values, using the same text
+// summaries as table output so that equivalent payload shapes compare equal.
+func (opts *dsImportOptions) diffProperties(
+	current map[string]notion.PropertyValue,
+	incoming map[string]any,
+) []importFieldDiff {
+	names := render.SortedKeys(incoming)
+
+	var diffs []importFieldDiff
+	for _, name := range names {
+		ref, _ := opts.index.ReferenceForName(name)
+		payload, _ := incoming[name].(map[string]any)
+		newText := payloadSummary(ref, payload)
+		oldText := summarizeProperty(current[name])
+		if oldText != newText {
+			diffs = append(diffs, importFieldDiff{Property: name, Old: oldText, New: newText})
+		}
+	}
+	return diffs
+}
+
+// payloadSummary renders a property update payload the way summarizeProperty renders the
+// corresponding page value.
+func payloadSummary(ref notion.PropertyReference, payload map[string]any) string {
+	body := make(map[string]any, len(payload)+1)
+	for key, value := range payload {
+		body[key] = value
+	}
+	body["type"] = ref.Type
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Sprint(payload)
+	}
+	var value notion.PropertyValue
+	if err := json.Unmarshal(data, &value); err != nil {
+		return string(data)
+	}
+	fillPlainText(value.Title)
+	fillPlainText(value.RichText)
+	return summarizeProperty(value)
+}
+
+// fillPlainText copies text content into plain_text, which request payloads omit.
+func fillPlainText(parts []notion.RichText) {
+	for i := range parts {
+		if parts[i].PlainText == "" && parts[i].Text != nil {
+			parts[i].PlainText = parts[i].Text.Content
+		}
+	}
+}
+
+func writeImportPlan(ctx context.Context, w io.Writer, format string, plan importPlan) error {
+	switch format {
+	case formatJSON:
+		return writeJSON(ctx, w, plan)
+	case formatTable:
+		headers := []string{"Line", "Action", "Page", "Property", "Old", "New", "Reason"}
+		if err := render.Table(w, headers, importPlanRows(plan)); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "\nPlan: %s\n", plan.Summary); err != nil {
+			return fmt.Errorf("write plan summary: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q (expected json or table)", format)
+	}
+}
+
+func importPlanRows(plan importPlan) [][]string {
+	rows := make([][]string, 0, len(plan.Entries))
+	for _, entry := range plan.Entries {
+		line := strconv.Itoa(entry.Line)
+		if len(entry.Diffs) == 0 {
+			rows = append(rows, []string{line, entry.Action, entry.PageID, "", "", "", entry.Reason})
+			continue
+		}
+		for _, diff := range entry.Diffs {
+			rows = append(rows, []string{line, entry.Action, entry.PageID, diff.Property, diff.Old, diff.New, entry.Reason})
+		}
+	}
+	return rows
+}
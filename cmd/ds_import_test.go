@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+type fakeImportClient struct {
+	mu       sync.Mutex
+	existing map[string][]notion.Page
+	created  []notion.CreatePageRequest
+	updated  []string
+}
+
+func (f *fakeImportClient) QueryDataSource(
+	_ context.Context,
+	_ string,
+	req notion.QueryDataSourceRequest,
+) (notion.QueryDataSourceResponse, error) {
+	filter, _ := req.Filter.(map[string]any)
+	title, _ := filter["title"].(map[string]any)
+	key, _ := title["equals"].(string)
+	return notion.QueryDataSourceResponse{Results: f.existing[key]}, nil
+}
+
+func (f *fakeImportClient) CreatePage(_ context.Context, req notion.CreatePageRequest) (notion.Page, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.created = append(f.created, req)
+	return notion.Page{ID: "new"}, nil
+}
+
+func (f *fakeImportClient) UpdatePage(_ context.Context, pageID string, _ notion.UpdatePageRequest) (notion.Page, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updated = append(f.updated, pageID)
+	return notion.Page{ID: pageID}, nil
+}
+
+func TestImportRowsUpsertsByKey(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{
+		Properties: map[string]notion.PropertyReference{
+			"Name":   {ID: "title", Name: "Name", Type: "title"},
+			"Points": {ID: "pts", Name: "Points", Type: "number"},
+		},
+	})
+	keyRef, _ := idx.ReferenceForName("Name")
+	opts := &dsImportOptions{
+		dataSourceID:  "ds",
+		index:         idx,
+		keyRef:        keyRef,
+		progressEvery: 2,
+		exec:          executionOptions{concurrency: 2, batchSize: 3, requestsPerSecond: 1},
+	}
+	client := &fakeImportClient{existing: map[string][]notion.Page{"Existing": {{ID: "page-1"}}}}
+
+	input := strings.NewReader(`{"Name":"Existing","Points":3}
+
+{"name":"Fresh","points":1}
+not json
+{"Name":"Other","Bogus":true}
+`)
+	var log bytes.Buffer
+	summary, err := opts.importRows(context.Background(), client, input, &log)
+	if err != nil {
+		t.Fatalf("importRows returned error: %v", err)
+	}
+	if summary.Rows != 4 || summary.Created != 1 || summary.Updated != 1 || summary.Failed != 2 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if len(client.updated) != 1 || client.updated[0] != "page-1" {
+		t.Fatalf("unexpected updates: %#v", client.updated)
+	}
+	if len(client.created) != 1 || client.created[0].Parent.DataSourceID != "ds" {
+		t.Fatalf("unexpected creates: %#v", client.created)
+	}
+	if !strings.Contains(log.String(), "line 4:") || !strings.Contains(log.String(), "progress: 2 rows") {
+		t.Fatalf("unexpected log output: %q", log.String())
+	}
+
+	opts.failFast = true
+	if _, err := opts.importRows(context.Background(), client, strings.NewReader("{}\n"), &log); err == nil {
+		t.Fatalf("expected fail-fast error for empty row")
+	}
+}
+
+func TestImportRowsSerializesDuplicateKeys(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{
+		Properties: map[string]notion.PropertyReference{"Name": {ID: "title", Name: "Name", Type: "title"}},
+	})
+	keyRef, _ := idx.ReferenceForName("Name")
+	opts := &dsImportOptions{
+		dataSourceID: "ds",
+		index:        idx,
+		keyRef:       keyRef,
+		exec:         executionOptions{concurrency: 4, batchSize: 2, requestsPerSecond: 1},
+	}
+	// The fake never returns created pages from a query, like Notion before it indexes them.
+	client := &fakeImportClient{}
+	input := strings.NewReader("{\"Name\":\"A\"}\n{\"Name\":\"A\"}\n{\"Name\":\"A\"}\n")
+	summary, err := opts.importRows(context.Background(), client, input, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("importRows returned error: %v", err)
+	}
+	if summary.Created != 1 || summary.Updated != 2 || len(client.created) != 1 {
+		t.Fatalf("expected one create and two updates for a repeated key, got %+v (%d creates)", summary, len(client.created))
+	}
+}
+
+func TestImportRowsFailFastStopsAtFirstFailure(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{
+		Properties: map[string]notion.PropertyReference{"Name": {ID: "title", Name: "Name", Type: "title"}},
+	})
+	opts := &dsImportOptions{
+		dataSourceID: "ds",
+		index:        idx,
+		failFast:     true,
+		exec:         executionOptions{concurrency: 1, batchSize: 10, requestsPerSecond: 1},
+	}
+	client := &fakeImportClient{}
+	input := strings.NewReader("{\"Name\":\"A\"}\n{}\n{\"Name\":\"B\"}\n{\"Name\":\"C\"}\n")
+	summary, err := opts.importRows(context.Background(), client, input, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected the line 2 failure, got %v", err)
+	}
+	if len(client.created) != 1 || summary.Rows != 2 {
+		t.Fatalf("expected nothing written after the failure, got %d creates and %+v", len(client.created), summary)
+	}
+}
+
+func TestPlanRowsClassifiesRows(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{
+		Properties: map[string]notion.PropertyReference{
+			"Name":   {ID: "title", Name: "Name", Type: "title"},
+			"Points": {ID: "pts", Name: "Points", Type: "number"},
+		},
+	})
+	keyRef, _ := idx.ReferenceForName("Name")
+	opts := &dsImportOptions{dataSourceID: "ds", index: idx, keyRef: keyRef}
+
+	three := 3.0
+	row := func(id, name string) notion.Page {
+		return notion.Page{ID: id, Properties: map[string]notion.PropertyValue{
+			"Name":   {Type: "title", Title: []notion.RichText{{PlainText: name}}},
+			"Points": {Type: "number", Number: &three},
+		}}
+	}
+	client := &fakeImportClient{existing: map[string][]notion.Page{
+		"Same":    {row("p-same", "Same")},
+		"Changed": {row("p-changed", "Changed")},
+		"Twice":   {row("p-a", "Twice"), row("p-b", "Twice")},
+	}}
+
+	input := strings.NewReader(`{"Name":"Same","Points":3}
+{"Name":"Changed","Points":{"number":5}}
+{"Name":"Twice"}
+{"Name":"New","Points":1}
+{"Name":"New","Points":2}
+`)
+	plan, err := opts.planRows(context.Background(), client, input)
+	if err != nil {
+		t.Fatalf("planRows returned error: %v", err)
+	}
+
+	want := importPlanSummary{Create: 1, Update: 1, Noop: 1, Conflict: 2}
+	if plan.Summary != want {
+		t.Fatalf("summary = %+v, want %+v", plan.Summary, want)
+	}
+	changed := plan.Entries[1]
+	if changed.Action != planUpdate || changed.PageID != "p-changed" || len(changed.Diffs) != 1 {
+		t.Fatalf("unexpected update entry: %+v", changed)
+	}
+	if diff := changed.Diffs[0]; diff.Property != "Points" || diff.Old != "3" || diff.New != "5" {
+		t.Fatalf("unexpected diff: %+v", diff)
+	}
+	if dup := plan.Entries[4]; dup.Action != planConflict || !strings.Contains(dup.Reason, "line 4") {
+		t.Fatalf("expected duplicate key conflict, got %+v", dup)
+	}
+	if len(client.created) != 0 || len(client.updated) != 0 {
+		t.Fatalf("plan must not write: created=%d updated=%d", len(client.created), len(client.updated))
+	}
+}
@@ -12,10 +12,8 @@ import (
 )
 
 func newDSListCmd(globals *globalOptions) *cobra.Command {
-	var (
-		databaseID string
-		format     string
-	)
+	var databaseID string
+	format := formatTable
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -24,7 +22,7 @@ func newDSListCmd(globals *globalOptions) *cobra.Command {
 			if databaseID == "" {
 				return fmt.Errorf("--database-id is required")
 			}
-			client, err := buildClient(globals.profile)
+			client, err := buildClient(globals)
 			if err != nil {
 				return err
 			}
@@ -37,7 +35,7 @@ func newDSListCmd(globals *globalOptions) *cobra.Command {
 
 			switch format {
 			case formatJSON:
-				return render.JSON(cmd.OutOrStdout(), dataSources)
+				return writeJSON(ctx, cmd.OutOrStdout(), dataSources)
 			case formatTable:
 				headers := []string{"ID", "Name", "Type", "Properties"}
 				return render.Table(cmd.OutOrStdout(), headers, dataSourceRows(dataSources))
@@ -47,8 +45,8 @@ func newDSListCmd(globals *globalOptions) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&databaseID, "database-id", "", "Notion database ID hosting the data sources")
-	cmd.Flags().StringVar(&format, "format", formatTable, "Output format: json|table")
+	cmd.Flags().Var(newIDValue(&databaseID), "database-id", "Notion database ID or URL hosting the data sources")
+	formatFlag(cmd, &format, "Output format", formatJSON, formatTable)
 
 	return cmd
 }
@@ -56,7 +54,10 @@ func newDSListCmd(globals *globalOptions) *cobra.Command {
 func dataSourceRows(sources []notion.DataSource) [][]string {
 	rows := make([][]string, 0, len(sources))
 	sort.SliceStable(sources, func(i, j int) bool {
-		return sources[i].Name < sources[j].Name
+		if sources[i].Name != sources[j].Name {
+			return sources[i].Name < sources[j].Name
+		}
+		return sources[i].ID < sources[j].ID
 	})
 	for _, ds := range sources {
 		rows = append(rows, []string{
@@ -0,0 +1,410 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/yourorg/notionctl/internal/expand"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+const statusType = "status"
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type dsMigrateOptions struct {
+	dataSourceID string
+	dir          string
+	statePageID  string
+	exec         executionOptions
+	dryRun       bool
+}
+
+// migrationFile is the on-disk shape of one migration.
+type migrationFile struct {
+	Description string          `yaml:"description"`
+	Steps       []migrationStep `yaml:"steps"`
+}
+
+// migrationStep holds exactly one schema or data change.
+type migrationStep struct {
+	AddProperty     *addPropertyStep    `yaml:"add_property"`
+	RenameProperty  *renamePropertyStep `yaml:"rename_property"`
+	Backfill        *backfillStep       `yaml:"backfill"`
+	ConvertToStatus *convertStatusStep  `yaml:"convert_to_status"`
+}
+
+type addPropertyStep struct {
+	Name    string   `yaml:"name"`
+	Type    string   `yaml:"type"`
+	Options []string `yaml:"options"`
+}
+
+type renamePropertyStep struct {
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+}
+
+// backfillStep writes Value, the text of From, or the result of Expr into Property on
+// matching rows. Only empty cells are filled unless Overwrite is set; Where narrows rows
+// using the --where syntax.
+type backfillStep struct {
+	Property  string `yaml:"property"`
+	Value     string `yaml:"value"`
+	From      string `yaml:"from"`
+	Expr      string `yaml:"expr"`
+	Where     string `yaml:"where"`
+	Overwrite bool   `yaml:"overwrite"`
+}
+
+// expression returns the backfill source as an expr expression, or "" for a constant.
+func (b backfillStep) expression() string {
+	if b.From != "" {
+		return fmt.Sprintf("prop(%q)", b.From)
+	}
+	return b.Expr
+}
+
+type convertStatusStep struct {
+	Property string `yaml:"property"`
+}
+
+// migration is a parsed migration file keyed by its file name (without extension).
+type migration struct {
+	Name string
+	migrationFile
+}
+
+// migrateClient is the subset of the Notion client used by migrations.
+type migrateClient interface {
+	dataSourceQuerier
+	GetDataSource(ctx context.Context, dataSourceID string) (notion.DataSource, error)
+	UpdateDataSource(ctx context.Context, dataSourceID string, req notion.UpdateDataSourceRequest) (notion.DataSource, error)
+	UpdatePage(ctx context.Context, pageID string, req notion.UpdatePageRequest) (notion.Page, error)
+	expand.PropertyItemFetcher
+}
+
+func newDSMigrateCmd(globals *globalOptions) *cobra.Command {
+	opts := &dsMigrateOptions{exec: defaultExecutionOptions()}
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply ordered schema migration files to a data source",
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Target Notion data source ID or URL")
+	cmd.Flags().StringVar(&opts.dir, "dir", "", "Directory of migration files (*.yaml), applied in file name order")
+	cmd.Flags().Var(
+		newIDValue(&opts.statePageID),
+		"state-page",
+		"Record applied migrations on this Notion page instead of the local config",
+	)
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "List pending migrations without applying them")
+	opts.exec.register(cmd, "Rows updated per batch in backfill steps")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
+	cobra.CheckErr(cmd.MarkFlagRequired("dir"))
+
+	return cmd
+}
+
+func (opts *dsMigrateOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		migrations, err := loadMigrations(opts.dir)
+		if err != nil {
+			return err
+		}
+
+		client, err := opts.exec.buildClient(globals)
+		if err != nil {
+			return err
+		}
+
+		var ledger migrationLedger = configLedger{profile: globals.profile, dataSourceID: opts.dataSourceID}
+		if opts.statePageID != "" {
+			ledger = &pageLedger{client: client, pageID: opts.statePageID}
+		}
+
+		ctx := cmd.Context()
+		applied, err := ledger.Applied(ctx)
+		if err != nil {
+			return err
+		}
+		return opts.apply(ctx, client, ledger, pending(migrations, applied), cmd.OutOrStdout())
+	}
+}
+
+func (opts *dsMigrateOptions) apply(
+	ctx context.Context,
+	client migrateClient,
+	ledger migrationLedger,
+	migrations []migration,
+	out io.Writer,
+) error {
+	if len(migrations) == 0 {
+		return writeLine(out, "No pending migrations")
+	}
+	for _, m := range migrations {
+		if opts.dryRun {
+			if err := writeLine(out, fmt.Sprintf("Pending %s (%d steps) %s", m.Name, len(m.Steps), m.Description)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writeLine(out, "Applying "+m.Name); err != nil {
+			return err
+		}
+		for i, step := range m.Steps {
+			summary, err := runMigrationStep(ctx, client, opts.dataSourceID, step, opts.exec)
+			if err != nil {
+				return fmt.Errorf("%s step %d: %w", m.Name, i+1, err)
+			}
+			if err := writeLine(out, "  "+summary); err != nil {
+				return err
+			}
+		}
+		if err := ledger.Record(ctx, m.Name); err != nil {
+			return fmt.Errorf("record %s: %w", m.Name, err)
+		}
+	}
+	return nil
+}
+
+// loadMigrations reads every *.yaml/*.yml file in dir, sorted by file name.
+func loadMigrations(dir string) ([]migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	migrations := make([]migration, 0, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name)) // #nosec G304 -- reading user-supplied migrations is intentional
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", name, err)
+		}
+		m := migration{Name: strings.TrimSuffix(name, filepath.Ext(name))}
+		if err := yaml.Unmarshal(data, &m.migrationFile); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", name, err)
+		}
+		if err := validateMigration(m); err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, m)
+	}
+	return migrations, nil
+}
+
+func validateMigration(m migration) error {
+	if len(m.Steps) == 0 {
+		return fmt.Errorf("%s defines no steps", m.Name)
+	}
+	for i, step := range m.Steps {
+		set := 0
+		for _, present := range []bool{
+			step.AddProperty != nil,
+			step.RenameProperty != nil,
+			step.Backfill != nil,
+			step.ConvertToStatus != nil,
+		} {
+			if present {
+				set++
+			}
+		}
+		if set != 1 {
+			return fmt.Errorf("%s step %d: exactly one of add_property, rename_property, backfill, "+
+				"or convert_to_status is required", m.Name, i+1)
+		}
+		if b := step.Backfill; b != nil && countSet(b.Value, b.From, b.Expr) != 1 {
+			return fmt.Errorf("%s step %d: backfill needs exactly one of value, from, or expr", m.Name, i+1)
+		}
+	}
+	return nil
+}
+
+func countSet(values ...string) int {
+	n := 0
+	for _, value := range values {
+		if value != "" {
+			n++
+		}
+	}
+	return n
+}
+
+func pending(migrations []migration, applied map[string]bool) []migration {
+	out := make([]migration, 0, len(migrations))
+	for _, m := range migrations {
+		if !applied[m.Name] {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// runMigrationStep applies one step against a freshly fetched schema, so later steps see
+// the effects of earlier renames and additions.
+func runMigrationStep(
+	ctx context.Context,
+	client migrateClient,
+	dataSourceID string,
+	step migrationStep,
+	exec executionOptions,
+) (string, error) {
+	ds, err := client.GetDataSource(ctx, dataSourceID)
+	if err != nil {
+		return "", fmt.Errorf("get data source: %w", err)
+	}
+	idx := schema.NewIndex(ds)
+
+	switch {
+	case step.AddProperty != nil:
+		return addProperty(ctx, client, dataSourceID, idx, *step.AddProperty)
+	case step.RenameProperty != nil:
+		return renameProperty(ctx, client, dataSourceID, idx, *step.RenameProperty)
+	case step.ConvertToStatus != nil:
+		return convertToStatus(ctx, client, dataSourceID, idx, *step.ConvertToStatus)
+	default:
+		return backfillProperty(ctx, client, dataSourceID, idx, *step.Backfill, exec)
+	}
+}
+
+func addProperty(
+	ctx context.Context,
+	client migrateClient,
+	dataSourceID string,
+	idx *schema.Index,
+	step addPropertyStep,
+) (string, error) {
+	if step.Name == "" || step.Type == "" {
+		return "", errors.New("add_property needs name and type")
+	}
+	if _, exists := idx.ReferenceForName(step.Name); exists {
+		return "", fmt.Errorf("property %q already exists", step.Name)
+	}
+	config := map[string]any{}
+	if len(step.Options) > 0 {
+		options := make([]any, 0, len(step.Options))
+		for _, name := range step.Options {
+			options = append(options, map[string]any{"name": name})
+		}
+		config["options"] = options
+	}
+	req := notion.UpdateDataSourceRequest{Properties: map[string]any{
+		step.Name: map[string]any{"type": step.Type, step.Type: config},
+	}}
+	if _, err := client.UpdateDataSource(ctx, dataSourceID, req); err != nil {
+		return "", fmt.Errorf("add property %q: %w", step.Name, err)
+	}
+	return fmt.Sprintf("added %s (%s)", step.Name, step.Type), nil
+}
+
+func renameProperty(
+	ctx context.Context,
+	client migrateClient,
+	dataSourceID string,
+	idx *schema.Index,
+	step renamePropertyStep,
+) (string, error) {
+	ref, ok := idx.ReferenceForName(step.From)
+	if !ok {
+		return "", fmt.Errorf("unknown property %q", step.From)
+	}
+	if step.To == "" {
+		return "", errors.New("rename_property needs to")
+	}
+	req := notion.UpdateDataSourceRequest{Properties: map[string]any{
+		ref.ID: map[string]any{"name": step.To},
+	}}
+	if _, err := client.UpdateDataSource(ctx, dataSourceID, req); err != nil {
+		return "", fmt.Errorf("rename property %q: %w", step.From, err)
+	}
+	return fmt.Sprintf("renamed %s to %s", ref.Name, step.To), nil
+}
+
+func convertToStatus(
+	ctx context.Context,
+	client migrateClient,
+	dataSourceID string,
+	idx *schema.Index,
+	step convertStatusStep,
+) (string, error) {
+	ref, ok := idx.ReferenceForName(step.Property)
+	if !ok {
+		return "", fmt.Errorf("unknown property %q", step.Property)
+	}
+	switch ref.Type {
+	case statusType:
+		return fmt.Sprintf("%s is already a status property", ref.Name), nil
+	case "select":
+	default:
+		return "", fmt.Errorf("property %q has type %s; only select properties convert to status", ref.Name, ref.Type)
+	}
+	req := notion.UpdateDataSourceRequest{Properties: map[string]any{
+		ref.ID: map[string]any{"type": statusType, statusType: map[string]any{}},
+	}}
+	if _, err := client.UpdateDataSource(ctx, dataSourceID, req); err != nil {
+		return "", fmt.Errorf("convert %q to status: %w", ref.Name, err)
+	}
+	return fmt.Sprintf("converted %s to status", ref.Name), nil
+}
+
+func backfillProperty(
+	ctx context.Context,
+	client migrateClient,
+	dataSourceID string,
+	idx *schema.Index,
+	step backfillStep,
+	exec executionOptions,
+) (string, error) {
+	target, ok := idx.ReferenceForName(step.Property)
+	if !ok {
+		return "", fmt.Errorf("unknown property %q", step.Property)
+	}
+	value, err := backfillValue(idx, step.Value, step.expression())
+	if err != nil {
+		return "", err
+	}
+	filter, err := backfillFilter(idx, target, step.Where, step.Overwrite)
+	if err != nil {
+		return "", err
+	}
+
+	job := &backfillJob{
+		dataSourceID: dataSourceID,
+		target:       target,
+		filter:       filter,
+		value:        value,
+		exec:         exec,
+	}
+	summary, err := job.run(ctx, client, io.Discard, io.Discard)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("backfilled %s on %d rows", target.Name, summary.Updated), nil
+}
+
+func writeLine(w io.Writer, line string) error {
+	if _, err := fmt.Fprintln(w, line); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+// migrationRecordPrefix marks the paragraphs that record applied migrations on a state page.
+const migrationRecordPrefix = "notionctl migration applied: "
+
+// migrationLedger remembers which migrations have been applied to a data source.
+type migrationLedger interface {
+	Applied(ctx context.Context) (map[string]bool, error)
+	Record(ctx context.Context, name string) error
+}
+
+// configLedger stores applied migrations in the profile's local config.
+type configLedger struct {
+	profile      string
+	dataSourceID string
+}
+
+func (l configLedger) Applied(context.Context) (map[string]bool, error) {
+	names, err := config.LoadAppliedMigrations(l.profile, l.dataSourceID)
+	if err != nil {
+		return nil, fmt.Errorf("load applied migrations: %w", err)
+	}
+	applied := make(map[string]bool, len(names))
+	for _, name := range names {
+		applied[name] = true
+	}
+	return applied, nil
+}
+
+func (l configLedger) Record(_ context.Context, name string) error {
+	if err := config.RecordAppliedMigration(l.profile, l.dataSourceID, name); err != nil {
+		return fmt.Errorf("record migration: %w", err)
+	}
+	return nil
+}
+
+// pageBlockClient is the subset of the Notion client needed to read and append page blocks.
+type pageBlockClient interface {
+	RetrieveBlockChildren(
+		ctx context.Context,
+		blockID string,
+		startCursor string,
+		pageSize int,
+	) (notion.BlockChildrenResponse, error)
+	AppendBlockChildren(ctx context.Context, blockID string, blocks []notion.Block) ([]notion.Block, error)
+}
+
+// pageLedger stores applied migrations as paragraphs on a shared Notion page, so everyone
+// migrating the same data source sees the same history.
+type pageLedger struct {
+	client pageBlockClient
+	pageID string
+}
+
+func (l *pageLedger) Applied(ctx context.Context) (map[string]bool, error) {
+	applied := map[string]bool{}
+	cursor := ""
+	for {
+		resp, err := l.client.RetrieveBlockChildren(ctx, l.pageID, cursor, maxQueryPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("read migration state page: %w", err)
+		}
+		for _, block := range resp.Results {
+			if block.Paragraph == nil {
+				continue
+			}
+			text := concatRichText(block.Paragraph.RichText)
+			if name, ok := strings.CutPrefix(text, migrationRecordPrefix); ok {
+				applied[strings.TrimSpace(name)] = true
+			}
+		}
+		if !resp.HasMore || resp.NextCursor == "" {
+			return applied, nil
+		}
+		cursor = resp.NextCursor
+	}
+}
+
+func (l *pageLedger) Record(ctx context.Context, name string) error {
+	text := migrationRecordPrefix + name
+	block := notion.Block{
+		Object: "block",
+		Type:   "paragraph",
+		Paragraph: &notion.ParagraphBlock{RichText: []notion.RichText{{
+			Type: "text",
+			Text: &notion.Text{Content: text},
+		}}},
+	}
+	if _, err := l.client.AppendBlockChildren(ctx, l.pageID, []notion.Block{block}); err != nil {
+		return fmt.Errorf("write migration state page: %w", err)
+	}
+	return nil
+}
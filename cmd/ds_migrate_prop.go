@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+const (
+	transformCopy   = "copy"
+	transformSplit  = "split"
+	transformFirst  = "first"
+	transformNumber = "number"
+)
+
+var numberPattern = regexp.MustCompile(`-?\d[\d,]*(?:\.\d+)?|-?\.\d+`)
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type dsMigratePropOptions struct {
+	dataSourceID string
+	from         string
+	to           string
+	transform    string
+	separator    string
+	mappings     []string
+	whereExpr    string
+	resumeFile   string
+	exec         executionOptions
+	retry        retryOptions
+	overwrite    bool
+	dryRun       bool
+}
+
+func newDSMigratePropCmd(globals *globalOptions) *cobra.Command {
+	opts := &dsMigratePropOptions{transform: transformCopy, separator: ";", exec: defaultExecutionOptions()}
+
+	cmd := &cobra.Command{
+		Use:   "migrate-prop",
+		Short: "Copy every row's value from one property into another, converting it to the new type",
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Target Notion data source ID or URL")
+	cmd.Flags().StringVar(&opts.from, "from", "", "Property to read values from")
+	cmd.Flags().StringVar(&opts.to, "to", "", "Property to write values to")
+	cmd.Flags().StringVar(
+		&opts.transform,
+		"transform",
+		opts.transform,
+		"How to convert values: copy|split|first|number",
+	)
+	cmd.Flags().StringVar(&opts.separator, "separator", opts.separator, "Separator that --transform split splits text on")
+	cmd.Flags().StringArrayVar(&opts.mappings, "map", nil, "Rename a value while migrating, as OLD=NEW (repeatable)")
+	cmd.Flags().StringVar(&opts.whereExpr, "where", "", "Only migrate rows matching this filter expression")
+	cmd.Flags().BoolVar(&opts.overwrite, "overwrite", false, "Also rewrite rows where the target already has a value")
+	opts.exec.register(cmd, "Rows updated between checkpoints")
+	cmd.Flags().StringVar(
+		&opts.resumeFile,
+		"resume-file",
+		"",
+		"Record finished page IDs here after each batch and skip them when rerun",
+	)
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print the value each row would receive without writing")
+	opts.retry.register(cmd, "ds migrate-prop", "")
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "retry-from")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
+	cobra.CheckErr(cmd.MarkFlagRequired("from"))
+	cobra.CheckErr(cmd.MarkFlagRequired("to"))
+
+	return cmd
+}
+
+func (opts *dsMigratePropOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		if err := opts.exec.validate(); err != nil {
+			return err
+		}
+		client, err := opts.exec.buildClient(globals)
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		ds, err := client.GetDataSource(ctx, opts.dataSourceID)
+		if err != nil {
+			return fmt.Errorf("get data source: %w", err)
+		}
+
+		job, err := opts.job(schema.NewIndex(ds))
+		if err != nil {
+			return err
+		}
+		summary, err := job.run(ctx, client, cmd.OutOrStdout(), cmd.ErrOrStderr())
+		safeLog(cmd.ErrOrStderr(), "Migrate %s", summary)
+		return job.saveFailures(err, cmd.ErrOrStderr())
+	}
+}
+
+// job builds a backfill of the target whose value for each row is the source property's
+// value after the transform and renames.
+func (opts *dsMigratePropOptions) job(idx *schema.Index) (*backfillJob, error) {
+	source, ok := idx.ReferenceForName(opts.from)
+	if !ok {
+		return nil, fmt.Errorf("unknown --from property %q", opts.from)
+	}
+	target, ok := idx.ReferenceForName(opts.to)
+	if !ok {
+		return nil, fmt.Errorf("unknown --to property %q", opts.to)
+	}
+	if source.ID == target.ID {
+		return nil, errors.New("--from and --to name the same property")
+	}
+	transform, err := opts.transformFunc()
+	if err != nil {
+		return nil, err
+	}
+	renames, err := parseValueMappings(opts.mappings)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := backfillFilter(idx, target, opts.whereExpr, opts.overwrite)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &backfillJob{
+		dataSourceID: opts.dataSourceID,
+		target:       target,
+		filter:       filter,
+		value: func(page notion.Page) (string, error) {
+			items, err := transform(propertyItems(page.Properties[source.Name]))
+			if err != nil {
+				return "", err
+			}
+			renamed := make([]string, 0, len(items))
+			for _, item := range items {
+				if replacement, ok := renames[item]; ok {
+					item = replacement
+				}
+				if item != "" {
+					renamed = append(renamed, item)
+				}
+			}
+			return strings.Join(renamed, ", "), nil
+		},
+		exec:   opts.exec,
+		dryRun: opts.dryRun,
+		retry:  &opts.retry,
+	}
+	if source.Type == relationType {
+		job.relations = []notion.PropertyReference{source}
+	}
+	if opts.resumeFile != "" && !opts.dryRun {
+		if job.done, err = readResumeFile(opts.resumeFile); err != nil {
+			return nil, err
+		}
+		job.checkpoint = func(ids []string) error { return appendResumeFile(opts.resumeFile, ids) }
+	}
+	return job, nil
+}
+
+func (opts *dsMigratePropOptions) transformFunc() (func([]string) ([]string, error), error) {
+	switch opts.transform {
+	case transformCopy:
+		return func(items []string) ([]string, error) { return items, nil }, nil
+	case transformSplit:
+		if opts.separator == "" {
+			return nil, errors.New("--separator must not be empty")
+		}
+		return func(items []string) ([]string, error) {
+			var out []string
+			for _, item := range items {
+				for _, part := range strings.Split(item, opts.separator) {
+					if part = strings.TrimSpace(part); part != "" {
+						out = append(out, part)
+					}
+				}
+			}
+			return out, nil
+		}, nil
+	case transformFirst:
+		return func(items []string) ([]string, error) { return items[:min(1, len(items))], nil }, nil
+	case transformNumber:
+		return func(items []string) ([]string, error) {
+			out := make([]string, 0, len(items))
+			for _, item := range items {
+				number := numberPattern.FindString(item)
+				if number == "" {
+					return nil, fmt.Errorf("no number in %q", item)
+				}
+				out = append(out, strings.ReplaceAll(number, ",", ""))
+			}
+			return out, nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown --transform %q (expected copy, split, first, or number)", opts.transform)
+	}
+}
+
+// propertyItems lists a value's items: one per option, person, or related page, and
+// otherwise the value's text. People are listed by ID so they can be written back.
+func propertyItems(value notion.PropertyValue) []string {
+	var items []string
+	switch value.Type {
+	case "multi_select":
+		for _, option := range value.MultiSelect {
+			items = append(items, option.Name)
+		}
+	case "people":
+		for _, person := range value.People {
+			items = append(items, person.ID)
+		}
+	case relationType:
+		for _, related := range value.Relation {
+			items = append(items, related.ID)
+		}
+	default:
+		if text := strings.TrimSpace(summarizeProperty(value)); text != "" && value.Type != "" {
+			items = append(items, text)
+		}
+	}
+	return items
+}
+
+func parseValueMappings(mappings []string) (map[string]string, error) {
+	renames := make(map[string]string, len(mappings))
+	for _, mapping := range mappings {
+		old, replacement, ok := strings.Cut(mapping, "=")
+		if !ok || strings.TrimSpace(old) == "" {
+			return nil, fmt.Errorf("invalid --map %q (expected OLD=NEW)", mapping)
+		}
+		renames[strings.TrimSpace(old)] = strings.TrimSpace(replacement)
+	}
+	return renames, nil
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+func TestMigratePropSelectToMultiSelectWithRename(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{
+		Properties: map[string]notion.PropertyReference{
+			"Tags (old)": {ID: "old", Name: "Tags (old)", Type: "rich_text"},
+			"Tags":       {ID: "tags", Name: "Tags", Type: "multi_select"},
+		},
+	})
+	row := func(id, text string) notion.Page {
+		value := notion.PropertyValue{Type: "rich_text"}
+		if text != "" {
+			value.RichText = []notion.RichText{{PlainText: text}}
+		}
+		return notion.Page{ID: id, Properties: map[string]notion.PropertyValue{"Tags (old)": value}}
+	}
+	client := &fakeBackfillClient{
+		rows:    []notion.Page{row("p1", "infra; ops"), row("p2", ""), row("p3", "legacy")},
+		updates: map[string]map[string]any{},
+	}
+	opts := &dsMigratePropOptions{
+		dataSourceID: "ds",
+		from:         "Tags (old)",
+		to:           "Tags",
+		transform:    transformSplit,
+		separator:    ";",
+		mappings:     []string{"ops=Operations", "legacy="},
+		exec:         defaultExecutionOptions(),
+	}
+	job, err := opts.job(idx)
+	if err != nil {
+		t.Fatalf("job returned error: %v", err)
+	}
+
+	summary, err := job.run(context.Background(), client, &bytes.Buffer{}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if want := (backfillSummary{Matched: 3, Updated: 1, Skipped: 2}); summary != want {
+		t.Fatalf("summary = %+v, want %+v", summary, want)
+	}
+	want := map[string]any{"multi_select": []any{map[string]any{"name": "infra"}, map[string]any{"name": "Operations"}}}
+	if got := client.updates["p1"]["Tags"]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("p1 update = %#v, want %#v", got, want)
+	}
+}
+
+func TestMigratePropNumberTransform(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{
+		Properties: map[string]notion.PropertyReference{
+			"Budget (text)": {ID: "old", Name: "Budget (text)", Type: "rich_text"},
+			"Budget":        {ID: "budget", Name: "Budget", Type: "number"},
+		},
+	})
+	client := &fakeBackfillClient{
+		rows: []notion.Page{{ID: "p1", Properties: map[string]notion.PropertyValue{
+			"Budget (text)": {Type: "rich_text", RichText: []notion.RichText{{PlainText: "$1,250.50 approx"}}},
+		}}},
+		updates: map[string]map[string]any{},
+	}
+	opts := &dsMigratePropOptions{
+		dataSourceID: "ds",
+		from:         "Budget (text)",
+		to:           "budget",
+		transform:    transformNumber,
+		exec:         defaultExecutionOptions(),
+		dryRun:       true,
+	}
+	job, err := opts.job(idx)
+	if err != nil {
+		t.Fatalf("job returned error: %v", err)
+	}
+
+	var out bytes.Buffer
+	if _, err := job.run(context.Background(), client, &out, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if got := out.String(); got != "p1\tBudget=1250.50\n" || len(client.updates) != 0 {
+		t.Fatalf("unexpected dry run: updates=%#v out=%q", client.updates, got)
+	}
+}
+
+func TestMigratePropCompletesTruncatedRelations(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{
+		Properties: map[string]notion.PropertyReference{
+			"Projects (old)": {ID: "old", Name: "Projects (old)", Type: "relation"},
+			"Projects":       {ID: "projects", Name: "Projects", Type: "relation"},
+		},
+	})
+	var first, all []notion.RelationReference
+	for i := range 30 {
+		ref := notion.RelationReference{ID: fmt.Sprintf("r%02d", i)}
+		if i < 25 {
+			first = append(first, ref)
+		}
+		all = append(all, ref)
+	}
+	client := &fakeBackfillClient{
+		rows: []notion.Page{{ID: "p1", Properties: map[string]notion.PropertyValue{
+			"Projects (old)": {ID: "old", Type: "relation", Relation: first, HasMore: true},
+		}}},
+		relations: map[string][]notion.RelationReference{"p1": all},
+		updates:   map[string]map[string]any{},
+	}
+	opts := &dsMigratePropOptions{
+		dataSourceID: "ds",
+		from:         "Projects (old)",
+		to:           "Projects",
+		transform:    transformCopy,
+		exec:         defaultExecutionOptions(),
+	}
+	job, err := opts.job(idx)
+	if err != nil {
+		t.Fatalf("job returned error: %v", err)
+	}
+
+	if _, err := job.run(context.Background(), client, &bytes.Buffer{}, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	update, ok := client.updates["p1"]["Projects"].(map[string]any)
+	if !ok {
+		t.Fatalf("p1 update = %#v, want a relation value", client.updates["p1"])
+	}
+	if got := update["relation"]; reflect.ValueOf(got).Len() != len(all) {
+		t.Fatalf("p1 relation = %#v, want all %d related pages", got, len(all))
+	}
+}
+
+func TestMigratePropRejectsSameProperty(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{
+		Properties: map[string]notion.PropertyReference{"Tags": {ID: "tags", Name: "Tags", Type: "multi_select"}},
+	})
+	opts := &dsMigratePropOptions{from: "Tags", to: "tags", transform: transformCopy}
+	if _, err := opts.job(idx); err == nil {
+		t.Fatal("expected an error when --from and --to match")
+	}
+}
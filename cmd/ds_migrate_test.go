@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+type fakeMigrateClient struct {
+	mu      sync.Mutex
+	ds      notion.DataSource
+	rows    []notion.Page
+	schema  []notion.UpdateDataSourceRequest
+	updates map[string]map[string]any
+}
+
+func (f *fakeMigrateClient) GetDataSource(context.Context, string) (notion.DataSource, error) {
+	return f.ds, nil
+}
+
+func (f *fakeMigrateClient) UpdateDataSource(
+	_ context.Context,
+	_ string,
+	req notion.UpdateDataSourceRequest,
+) (notion.DataSource, error) {
+	f.schema = append(f.schema, req)
+	for key, raw := range req.Properties {
+		change, _ := raw.(map[string]any)
+		if newName, ok := change["name"].(string); ok {
+			for name, ref := range f.ds.Properties {
+				if ref.ID == key {
+					delete(f.ds.Properties, name)
+					ref.Name = newName
+					f.ds.Properties[newName] = ref
+				}
+			}
+			continue
+		}
+		propType, _ := change["type"].(string)
+		f.ds.Properties[key] = notion.PropertyReference{ID: "new-" + key, Name: key, Type: propType}
+	}
+	return f.ds, nil
+}
+
+func (f *fakeMigrateClient) QueryDataSource(
+	context.Context,
+	string,
+	notion.QueryDataSourceRequest,
+) (notion.QueryDataSourceResponse, error) {
+	return notion.QueryDataSourceResponse{Results: f.rows}, nil
+}
+
+func (f *fakeMigrateClient) UpdatePage(_ context.Context, pageID string, req notion.UpdatePageRequest) (notion.Page, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updates[pageID] = req.Properties
+	return notion.Page{ID: pageID}, nil
+}
+
+func (f *fakeMigrateClient) RetrievePageProperty(
+	context.Context,
+	string,
+	string,
+	string,
+) (notion.PropertyItemResponse, error) {
+	return notion.PropertyItemResponse{}, nil
+}
+
+type memoryLedger map[string]bool
+
+func (l memoryLedger) Applied(context.Context) (map[string]bool, error) { return l, nil }
+
+func (l memoryLedger) Record(_ context.Context, name string) error {
+	l[name] = true
+	return nil
+}
+
+func TestMigrateAppliesPendingFilesInOrder(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"001_rename.yaml": "steps:\n  - rename_property: {from: stage, to: Phase}\n",
+		"002_quarter.yaml": `description: quarter column
+steps:
+  - add_property: {name: Quarter, type: select, options: [Q1, Q2]}
+  - backfill: {property: Quarter, value: Q1}
+`,
+		"notes.txt": "ignored",
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	migrations, err := loadMigrations(dir)
+	if err != nil {
+		t.Fatalf("loadMigrations returned error: %v", err)
+	}
+	if len(migrations) != 2 || migrations[0].Name != "001_rename" || migrations[1].Description != "quarter column" {
+		t.Fatalf("unexpected migrations: %#v", migrations)
+	}
+
+	client := &fakeMigrateClient{
+		ds: notion.DataSource{Properties: map[string]notion.PropertyReference{
+			"Name":  {ID: "title", Name: "Name", Type: "title"},
+			"Stage": {ID: "stg", Name: "Stage", Type: "select"},
+		}},
+		rows:    []notion.Page{{ID: "row-1"}, {ID: "row-2"}},
+		updates: map[string]map[string]any{},
+	}
+	ledger := memoryLedger{"001_rename": true}
+	opts := &dsMigrateOptions{dataSourceID: "ds", exec: defaultExecutionOptions()}
+
+	var out bytes.Buffer
+	if err := opts.apply(context.Background(), client, ledger, pending(migrations, ledger), &out); err != nil {
+		t.Fatalf("apply returned error: %v", err)
+	}
+	if len(client.schema) != 1 {
+		t.Fatalf("expected only the pending add_property schema change, got %#v", client.schema)
+	}
+	if _, ok := client.ds.Properties["Phase"]; ok {
+		t.Fatalf("already-applied rename ran again")
+	}
+	if len(client.updates) != 2 || client.updates["row-1"]["Quarter"] == nil {
+		t.Fatalf("unexpected backfill updates: %#v", client.updates)
+	}
+	if !ledger["002_quarter"] {
+		t.Fatalf("expected 002_quarter to be recorded")
+	}
+	if !strings.Contains(out.String(), "backfilled Quarter on 2 rows") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
+
+func TestValidateMigrationRejectsAmbiguousSteps(t *testing.T) {
+	m := migration{Name: "bad", migrationFile: migrationFile{Steps: []migrationStep{{
+		AddProperty:    &addPropertyStep{Name: "A", Type: "select"},
+		RenameProperty: &renamePropertyStep{From: "A", To: "B"},
+	}}}}
+	if err := validateMigration(m); err == nil {
+		t.Fatalf("expected error for step with two actions")
+	}
+
+	m.Steps = []migrationStep{{Backfill: &backfillStep{Property: "A"}}}
+	if err := validateMigration(m); err == nil {
+		t.Fatalf("expected error for backfill without value or from")
+	}
+}
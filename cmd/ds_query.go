@@ -5,17 +5,21 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
+	"io"
+	"iter"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/yourorg/notionctl/internal/datemacro"
 	"github.com/yourorg/notionctl/internal/expand"
 	"github.com/yourorg/notionctl/internal/notion"
 	"github.com/yourorg/notionctl/internal/render"
 	"github.com/yourorg/notionctl/internal/schema"
+	"github.com/yourorg/notionctl/internal/where"
 )
 
 //nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
@@ -24,19 +28,34 @@ type dsQueryOptions struct {
 	format           string
 	filterJSON       string
 	filterFile       string
+	whereExpr        string
 	sortsJSON        string
 	sortsFile        string
+	sortSpec         string
+	viewRef          string
+	grep             string
 	startCursor      string
 	filterProperties []string
 	expandRelations  []string
+	expandFields     []string
 	pageSize         int
+	limit            int
 	fetchAll         bool
-
+	grepRegex        bool
+	expandPeople     bool
+	expandInline     bool
+	noSchema         bool
+
+	exec       executionOptions
+	aggregate  aggregateOptions
+	view       *notion.View
 	expandRefs []notion.PropertyReference
+	stdin      io.Reader
+	stderr     io.Writer
 }
 
 func newDSQueryCmd(globals *globalOptions) *cobra.Command {
-	opts := &dsQueryOptions{format: formatTable}
+	opts := &dsQueryOptions{format: formatTable, exec: defaultExecutionOptions()}
 
 	cmd := &cobra.Command{
 		Use:   "query",
@@ -44,22 +63,77 @@ func newDSQueryCmd(globals *globalOptions) *cobra.Command {
 		RunE:  opts.run(globals),
 	}
 
-	cmd.Flags().StringVar(&opts.dataSourceID, "data-source-id", "", "Target Notion data source ID")
-	cmd.Flags().StringVar(&opts.format, "format", opts.format, "Output format: json|table")
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Target Notion data source ID or URL")
+	formatFlag(cmd, &opts.format, "Output format", formatJSON, formatTable)
 	cmd.Flags().StringVar(&opts.filterJSON, "filter", "", "Inline JSON filter payload")
-	cmd.Flags().StringVar(&opts.filterFile, "filter-file", "", "Path to JSON filter payload")
+	cmd.Flags().StringVar(&opts.filterFile, "filter-file", "", "Path to JSON filter payload (- for stdin)")
+	cmd.Flags().StringVar(
+		&opts.whereExpr,
+		"where",
+		"",
+		`Filter expression such as 'Status = "Done" AND Due before 2025-01-01' (combined with --filter using AND)`,
+	)
 	cmd.Flags().StringVar(&opts.sortsJSON, "sorts", "", "Inline JSON sorts array")
-	cmd.Flags().StringVar(&opts.sortsFile, "sorts-file", "", "Path to JSON sorts array")
+	cmd.Flags().StringVar(&opts.sortsFile, "sorts-file", "", "Path to JSON sorts array (- for stdin)")
+	cmd.Flags().StringVar(
+		&opts.sortSpec,
+		"sort",
+		"",
+		`Sort shorthand such as "Due:asc,Priority:desc" (appended after --sorts)`,
+	)
+	cmd.Flags().StringVar(
+		&opts.viewRef,
+		"view",
+		"",
+		"Saved view ID, URL, or name whose filter and sorts are applied",
+	)
 	cmd.Flags().StringSliceVar(
 		&opts.filterProperties,
 		"filter-properties",
 		nil,
 		"Property names to include in the response",
 	)
-	cmd.Flags().StringSliceVar(&opts.expandRelations, "expand", nil, "Relation property names to expand")
+	cmd.Flags().StringSliceVar(&opts.expandRelations, "expand", nil, "Relation or rollup property names to expand")
+	cmd.Flags().StringSliceVar(
+		&opts.expandFields,
+		"expand-fields",
+		nil,
+		`Properties to keep on expanded pages, e.g. "Name,Status" ("title" keeps the title)`,
+	)
+	cmd.Flags().BoolVar(
+		&opts.expandPeople,
+		"expand-people",
+		false,
+		"Resolve people, created_by, and last_edited_by users to full user objects with names and emails",
+	)
+	cmd.Flags().BoolVar(
+		&opts.expandInline,
+		"expand-inline",
+		false,
+		"Embed expanded pages in the relation values (relation[].page) instead of expanded_relations",
+	)
 	cmd.Flags().StringVar(&opts.startCursor, "start-cursor", "", "Pagination cursor to resume from")
 	cmd.Flags().IntVar(&opts.pageSize, "page-size", 0, "Page size (max 100)")
 	cmd.Flags().BoolVar(&opts.fetchAll, "all", false, "Fetch all result pages (may issue multiple requests)")
+	cmd.Flags().IntVar(&opts.limit, "limit", 0, "Stop after collecting this many rows across result pages")
+	cmd.Flags().StringVar(
+		&opts.grep,
+		"grep",
+		"",
+		"Keep only rows whose title or text properties contain this text (case-insensitive; fetches all pages)",
+	)
+	cmd.Flags().BoolVar(&opts.grepRegex, "regex", false, "Treat --grep as a regular expression")
+	cmd.Flags().BoolVar(
+		&opts.noSchema,
+		"no-schema",
+		false,
+		"Skip reading the data source schema; property references pass through unmapped",
+	)
+	cmd.Flags().BoolVar(&opts.aggregate.count, "count", false, "Print the number of matching rows instead of the rows")
+	cmd.Flags().StringSliceVar(&opts.aggregate.sum, "sum", nil, "Number properties to sum across matching rows")
+	cmd.Flags().StringSliceVar(&opts.aggregate.avg, "avg", nil, "Number properties to average across matching rows")
+	cmd.Flags().StringVar(&opts.aggregate.groupBy, "group-by", "", "Property whose values group the aggregates")
+	opts.exec.register(cmd, "")
 
 	return cmd
 }
@@ -69,18 +143,34 @@ func (opts *dsQueryOptions) run(globals *globalOptions) func(*cobra.Command, []s
 		if err := opts.validate(); err != nil {
 			return err
 		}
+		opts.stdin = cmd.InOrStdin()
+		opts.stderr = cmd.ErrOrStderr()
 
-		client, err := buildClient(globals.profile)
+		client, err := opts.exec.buildClient(globals)
 		if err != nil {
 			return err
 		}
 
+		if opts.aggregate.enabled() {
+			opts.fetchAll = true
+		}
+
 		ctx := cmd.Context()
 		resp, index, err := opts.executeQuery(ctx, client)
 		if err != nil {
 			return err
 		}
 
+		if opts.aggregate.enabled() {
+			resolved, err := opts.aggregate.resolve(index)
+			if err != nil {
+				return err
+			}
+			if err := checkNumericResults(resp.Results, resolved); err != nil {
+				return err
+			}
+			return renderAggregates(cmd, opts.format, aggregatePages(resp.Results, resolved), resolved)
+		}
 		return opts.renderResults(cmd, resp, index)
 	}
 }
@@ -121,34 +211,135 @@ func (opts *dsQueryOptions) buildRequest(idx *schema.Index) (notion.QueryDataSou
 }
 
 func (opts *dsQueryOptions) buildFilter(idx *schema.Index) (any, error) {
-	payload, err := loadJSONValue(opts.filterJSON, opts.filterFile)
+	payload, err := loadJSONValue(opts.filterJSON, opts.filterFile, opts.stdin)
 	if err != nil {
 		return nil, fmt.Errorf("load filter: %w", err)
 	}
-	if payload == nil {
+	var filters []any
+	if opts.view != nil && opts.view.Filter != nil {
+		filters = append(filters, mapPropertyIdentifiers(opts.view.Filter, idx))
+	}
+	if payload != nil {
+		expanded, err := datemacro.ExpandFilter(payload, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("filter: %w", err)
+		}
+		filters = append(filters, mapPropertyIdentifiers(expanded, idx))
+	}
+	if strings.TrimSpace(opts.whereExpr) != "" {
+		if idx == nil {
+			return nil, errSchemaRequired("--where")
+		}
+		compiled, err := where.Compile(opts.whereExpr, idx)
+		if err != nil {
+			return nil, fmt.Errorf("parse --where: %w", err)
+		}
+		filters = append(filters, compiled)
+	}
+
+	switch len(filters) {
+	case 0:
 		return nil, nil
+	case 1:
+		return filters[0], nil
+	default:
+		return map[string]any{"and": filters}, nil
 	}
-	return mapPropertyIdentifiers(payload, idx), nil
 }
 
 func (opts *dsQueryOptions) buildSorts(idx *schema.Index) ([]any, error) {
-	payload, err := loadJSONValue(opts.sortsJSON, opts.sortsFile)
+	payload, err := loadJSONValue(opts.sortsJSON, opts.sortsFile, opts.stdin)
 	if err != nil {
 		return nil, fmt.Errorf("load sorts: %w", err)
 	}
-	if payload == nil {
+
+	var sorts []any
+	if payload != nil {
+		sortsSlice, ok := toSlice(payload)
+		if !ok {
+			return nil, errors.New("sorts payload must be a JSON array")
+		}
+		mapped := mapPropertyIdentifiers(sortsSlice, idx)
+		mappedSlice, ok := mapped.([]any)
+		if !ok {
+			return nil, errors.New("sorts payload must be a JSON array of objects")
+		}
+		sorts = mappedSlice
+	}
+
+	shorthand, err := parseSortShorthand(opts.sortSpec, idx)
+	if err != nil {
+		return nil, fmt.Errorf("parse --sort: %w", err)
+	}
+	sorts = append(sorts, shorthand...)
+	if len(sorts) == 0 && opts.view != nil && len(opts.view.Sorts) > 0 {
+		viewSorts, ok := mapPropertyIdentifiers(opts.view.Sorts, idx).([]any)
+		if !ok {
+			return nil, errors.New("view sorts must be a JSON array")
+		}
+		return viewSorts, nil
+	}
+	return sorts, nil
+}
+
+// parseSortShorthand expands "Name:dir,..." into Notion sort objects. The direction
+// defaults to ascending; created_time and last_edited_time sort by page timestamps.
+func parseSortShorthand(spec string, idx *schema.Index) ([]any, error) {
+	if strings.TrimSpace(spec) == "" {
 		return nil, nil
 	}
-	sortsSlice, ok := toSlice(payload)
-	if !ok {
-		return nil, errors.New("sorts payload must be a JSON array")
+
+	var sorts []any
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, dir := splitSortItem(item)
+
+		direction, err := sortDirection(dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		switch strings.ToLower(name) {
+		case "created_time", "last_edited_time":
+			sorts = append(sorts, map[string]any{"timestamp": strings.ToLower(name), "direction": direction})
+			continue
+		}
+
+		id, ok := idx.IDForName(name)
+		switch {
+		case idx == nil:
+			id = name
+		case !ok:
+			return nil, fmt.Errorf("unknown property %q", name)
+		}
+		sorts = append(sorts, map[string]any{"property": id, "direction": direction})
+	}
+	return sorts, nil
+}
+
+// splitSortItem separates "Property:dir". Only a trailing direction is split off, so IDs
+// such as property_id:abc keep their colon.
+func splitSortItem(item string) (string, string) {
+	if i := strings.LastIndex(item, ":"); i >= 0 {
+		if _, err := sortDirection(item[i+1:]); err == nil && strings.TrimSpace(item[i+1:]) != "" {
+			return strings.TrimSpace(item[:i]), item[i+1:]
+		}
 	}
-	mapped := mapPropertyIdentifiers(sortsSlice, idx)
-	mappedSlice, ok := mapped.([]any)
-	if !ok {
-		return nil, errors.New("sorts payload must be a JSON array of objects")
+	return strings.TrimSpace(item), ""
+}
+
+func sortDirection(dir string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(dir)) {
+	case "", "asc", "ascending":
+		return "ascending", nil
+	case "desc", "descending":
+		return "descending", nil
+	default:
+		return "", fmt.Errorf("unknown sort direction %q (expected asc or desc)", dir)
 	}
-	return mappedSlice, nil
 }
 
 func (opts *dsQueryOptions) buildFilterProperties(idx *schema.Index) ([]string, error) {
@@ -156,6 +347,10 @@ func (opts *dsQueryOptions) buildFilterProperties(idx *schema.Index) ([]string,
 		return nil, nil
 	}
 
+	if idx == nil {
+		return opts.filterProperties, nil
+	}
+
 	props := make([]string, 0, len(opts.filterProperties))
 	for _, name := range opts.filterProperties {
 		id, ok := idx.IDForName(name)
@@ -171,44 +366,69 @@ func (opts *dsQueryOptions) buildExpandMap(idx *schema.Index) (map[string]bool,
 	if len(opts.expandRelations) == 0 {
 		return nil, nil
 	}
+	if idx == nil {
+		return nil, errSchemaRequired("--expand")
+	}
 
-	expand := make(map[string]bool, len(opts.expandRelations))
+	expandIDs := make(map[string]bool, len(opts.expandRelations))
 	refs := make([]notion.PropertyReference, 0, len(opts.expandRelations))
 
 	for _, name := range opts.expandRelations {
 		ref, ok := idx.ReferenceForName(name)
 		if !ok {
-			return nil, fmt.Errorf("unknown relation %q", name)
+			return nil, fmt.Errorf("unknown property %q", name)
 		}
-		if ref.Type != relationType {
-			return nil, fmt.Errorf("property %q is not a relation", name)
+		if !expand.Expandable(ref.Type) {
+			return nil, fmt.Errorf("property %q is not a relation or rollup", name)
 		}
-		expand[ref.ID] = true
+		expandIDs[ref.ID] = true
 		refs = append(refs, ref)
 	}
 	opts.expandRefs = refs
-	return expand, nil
+	return expandIDs, nil
 }
 
+// dataSourceQuerier is the subset of the Notion client needed to page through query results.
+type dataSourceQuerier interface {
+	QueryDataSource(
+		ctx context.Context,
+		dataSourceID string,
+		req notion.QueryDataSourceRequest,
+	) (notion.QueryDataSourceResponse, error)
+}
+
+// executeDataSourceQuery runs req once, or follows cursors when fetchAll is set or limit is
+// positive. With a limit, page sizes shrink so the final request ends exactly on the limit
+// and the returned cursor resumes right after the last row.
 func executeDataSourceQuery(
 	ctx context.Context,
-	client *notion.Client,
+	client dataSourceQuerier,
 	dataSourceID string,
 	req notion.QueryDataSourceRequest,
 	fetchAll bool,
+	limit int,
 ) (notion.QueryDataSourceResponse, error) {
-	if !fetchAll {
+	if !fetchAll && limit <= 0 {
 		resp, err := client.QueryDataSource(ctx, dataSourceID, req)
 		if err != nil {
 			return notion.QueryDataSourceResponse{}, fmt.Errorf("query data source: %w", err)
 		}
+		recordQuery(ctx, dataSourceID, req, resp)
 		return resp, nil
 	}
 
 	var all notion.QueryDataSourceResponse
+	first := req
+	pageSize := req.PageSize
+	if pageSize <= 0 || pageSize > maxQueryPageSize {
+		pageSize = maxQueryPageSize
+	}
 	cursor := req.StartCursor
 	for {
 		req.StartCursor = cursor
+		if limit > 0 {
+			req.PageSize = min(pageSize, limit-len(all.Results))
+		}
 		resp, err := client.QueryDataSource(ctx, dataSourceID, req)
 		if err != nil {
 			return notion.QueryDataSourceResponse{}, fmt.Errorf("query data source: %w", err)
@@ -216,14 +436,53 @@ func executeDataSourceQuery(
 		all.Results = append(all.Results, resp.Results...)
 		all.HasMore = resp.HasMore
 		all.NextCursor = resp.NextCursor
+		if limit > 0 && len(all.Results) >= limit {
+			all.Results = all.Results[:limit]
+			break
+		}
 		if !resp.HasMore || resp.NextCursor == "" {
 			break
 		}
 		cursor = resp.NextCursor
 	}
+	recordQuery(ctx, dataSourceID, first, all)
 	return all, nil
 }
 
+// streamDataSourceQuery is executeDataSourceQuery for callers that handle rows one at a
+// time: it follows every cursor, stops after limit rows when limit is positive, and holds
+// only one result page in memory.
+func streamDataSourceQuery(
+	ctx context.Context,
+	client dataSourceQuerier,
+	dataSourceID string,
+	req notion.QueryDataSourceRequest,
+	limit int,
+) iter.Seq2[notion.Page, error] {
+	if req.PageSize <= 0 || req.PageSize > maxQueryPageSize {
+		req.PageSize = maxQueryPageSize
+	}
+	if limit > 0 {
+		req.PageSize = min(req.PageSize, limit)
+	}
+	rows := notion.QueryDataSourceIterWith(ctx, client.QueryDataSource, dataSourceID, req)
+	return func(yield func(notion.Page, error) bool) {
+		count := 0
+		for page, err := range rows {
+			if err != nil {
+				yield(notion.Page{}, fmt.Errorf("query data source: %w", err))
+				return
+			}
+			if !yield(page, nil) {
+				return
+			}
+			if count++; limit > 0 && count >= limit {
+				return
+			}
+		}
+	}
+}
+
 func (opts *dsQueryOptions) renderResults(
 	cmd *cobra.Command,
 	resp notion.QueryDataSourceResponse,
@@ -231,7 +490,7 @@ func (opts *dsQueryOptions) renderResults(
 ) error {
 	switch opts.format {
 	case formatJSON:
-		if err := render.JSON(cmd.OutOrStdout(), resp); err != nil {
+		if err := writeJSON(cmd.Context(), cmd.OutOrStdout(), resp); err != nil {
 			return fmt.Errorf("render json: %w", err)
 		}
 		return nil
@@ -247,8 +506,20 @@ func (opts *dsQueryOptions) renderResults(
 }
 
 func (opts *dsQueryOptions) validate() error {
-	if opts.dataSourceID == "" {
-		return errors.New("--data-source-id is required")
+	if opts.dataSourceID == "" && opts.viewRef == "" {
+		return errors.New("--data-source-id or --view is required")
+	}
+	if opts.limit < 0 {
+		return errors.New("--limit must not be negative")
+	}
+	if err := checkOneStdin(map[string]string{"filter-file": opts.filterFile, "sorts-file": opts.sortsFile}); err != nil {
+		return err
+	}
+	if opts.grepRegex && opts.grep == "" {
+		return errors.New("--regex requires --grep")
+	}
+	if (len(opts.expandFields) > 0 || opts.expandInline) && len(opts.expandRelations) == 0 {
+		return errors.New("--expand-fields and --expand-inline require --expand")
 	}
 	return nil
 }
@@ -257,7 +528,10 @@ func (opts *dsQueryOptions) executeQuery(
 	ctx context.Context,
 	client *notion.Client,
 ) (notion.QueryDataSourceResponse, *schema.Index, error) {
-	index, err := opts.resolveIndex(ctx, client)
+	if err := opts.loadView(ctx, client); err != nil {
+		return notion.QueryDataSourceResponse{}, nil, err
+	}
+	index, err := opts.queryIndex(ctx, client)
 	if err != nil {
 		return notion.QueryDataSourceResponse{}, nil, err
 	}
@@ -267,7 +541,7 @@ func (opts *dsQueryOptions) executeQuery(
 		return notion.QueryDataSourceResponse{}, nil, err
 	}
 
-	resp, err := executeDataSourceQuery(ctx, client, opts.dataSourceID, req, opts.fetchAll)
+	resp, err := opts.fetch(ctx, client, req)
 	if err != nil {
 		return notion.QueryDataSourceResponse{}, nil, err
 	}
@@ -279,6 +553,60 @@ func (opts *dsQueryOptions) executeQuery(
 	return resp, index, nil
 }
 
+// fetch runs the query. With --grep every page is fetched and filtered client-side, and
+// --limit then caps the matching rows rather than the fetched ones.
+func (opts *dsQueryOptions) fetch(
+	ctx context.Context,
+	client dataSourceQuerier,
+	req notion.QueryDataSourceRequest,
+) (notion.QueryDataSourceResponse, error) {
+	if opts.grep == "" {
+		return executeDataSourceQuery(ctx, client, opts.dataSourceID, req, opts.fetchAll, opts.limit)
+	}
+	match, err := newTextMatcher(opts.grep, opts.grepRegex)
+	if err != nil {
+		return notion.QueryDataSourceResponse{}, err
+	}
+	resp, err := executeDataSourceQuery(ctx, client, opts.dataSourceID, req, true, 0)
+	if err != nil {
+		return notion.QueryDataSourceResponse{}, err
+	}
+	resp.Results = grepPages(resp.Results, match)
+	if opts.limit > 0 && len(resp.Results) > opts.limit {
+		resp.Results = resp.Results[:opts.limit]
+	}
+	resp.HasMore, resp.NextCursor = false, ""
+	return resp, nil
+}
+
+// queryIndex returns the schema index, or nil when --no-schema is set or the token may query
+// the data source but not read its schema. Without an index, property references in
+// filters and sorts are sent as given and tables show the columns present in the response.
+func (opts *dsQueryOptions) queryIndex(ctx context.Context, client *notion.Client) (*schema.Index, error) {
+	if opts.noSchema {
+		return nil, nil
+	}
+	index, err := opts.resolveIndex(ctx, client)
+	if err != nil && schemaUnavailable(err) {
+		safeLog(opts.stderr, "warning: %v; continuing without schema (property names are not mapped)", err)
+		return nil, nil
+	}
+	return index, err
+}
+
+// schemaUnavailable reports whether err means the schema cannot be read with this token.
+func schemaUnavailable(err error) bool {
+	var apiErr *notion.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Status == http.StatusForbidden || apiErr.Status == http.StatusNotFound
+}
+
+func errSchemaRequired(flag string) error {
+	return fmt.Errorf("%s needs the data source schema, which is unavailable (--no-schema or no read access)", flag)
+}
+
 func (opts *dsQueryOptions) resolveIndex(ctx context.Context, client *notion.Client) (*schema.Index, error) {
 	ds, err := client.GetDataSource(ctx, opts.dataSourceID)
 	if err != nil {
@@ -289,20 +617,29 @@ func (opts *dsQueryOptions) resolveIndex(ctx context.Context, client *notion.Cli
 
 func (opts *dsQueryOptions) expandResults(
 	ctx context.Context,
-	client expand.PageFetcher,
+	client expand.Fetcher,
 	pages []notion.Page,
 ) error {
+	if opts.expandPeople {
+		if err := expand.People(ctx, client, pages, opts.exec.concurrency, opts.stderr); err != nil {
+			return err
+		}
+	}
 	if len(opts.expandRefs) == 0 {
 		return nil
 	}
-	if err := expand.FirstLevel(ctx, client, pages, opts.expandRefs); err != nil {
+	if err := expand.FirstLevel(ctx, client, pages, opts.expandRefs, opts.exec.concurrency); err != nil {
 		return fmt.Errorf("expand relations: %w", err)
 	}
+	expand.Project(pages, opts.expandFields)
+	if opts.expandInline {
+		expand.Inline(pages)
+	}
 	return nil
 }
 
-func loadJSONValue(inline, file string) (any, error) {
-	text, err := readJSONText(inline, file)
+func loadJSONValue(inline, file string, stdin io.Reader) (any, error) {
+	text, err := readJSONText(inline, file, stdin)
 	if err != nil || text == "" {
 		return nil, err
 	}
@@ -313,11 +650,11 @@ func loadJSONValue(inline, file string) (any, error) {
 	return payload, nil
 }
 
-func readJSONText(inline, file string) (string, error) {
+func readJSONText(inline, file string, stdin io.Reader) (string, error) {
 	if file != "" {
-		data, err := os.ReadFile(file) // #nosec G304 -- reading user-supplied filter payload is intentional
+		data, err := readFileOrStdin(file, stdin)
 		if err != nil {
-			return "", fmt.Errorf("read %s: %w", file, err)
+			return "", err
 		}
 		return string(data), nil
 	}
@@ -367,6 +704,9 @@ func mapSliceIdentifiers(values []any, idx *schema.Index) []any {
 }
 
 func queryResultsTable(pages []notion.Page, idx *schema.Index) ([]string, [][]string) {
+	if idx == nil {
+		return schemalessResultsTable(pages)
+	}
 	propertyNames := idx.PropertyNames()
 	headers := append([]string{"ID", "Last Edited"}, propertyHeaders(propertyNames, idx)...)
 	rows := make([][]string, 0, len(pages))
@@ -374,8 +714,35 @@ func queryResultsTable(pages []notion.Page, idx *schema.Index) ([]string, [][]st
 		row := []string{page.ID, page.LastEditedTime.UTC().Format(time.RFC3339)}
 		for _, name := range propertyNames {
 			ref, _ := idx.ReferenceForName(name)
-			value := page.Properties[ref.Name]
-			row = append(row, summarizeProperty(value))
+			row = append(row, summarizePageProperty(page, ref.Name))
+		}
+		rows = append(rows, row)
+	}
+	return headers, rows
+}
+
+// schemalessResultsTable builds columns from the property names and types present in the
+// response itself.
+func schemalessResultsTable(pages []notion.Page) ([]string, [][]string) {
+	types := map[string]string{}
+	for _, page := range pages {
+		for name, value := range page.Properties {
+			if _, ok := types[name]; !ok {
+				types[name] = value.Type
+			}
+		}
+	}
+	names := render.SortedKeys(types)
+
+	headers := []string{"ID", "Last Edited"}
+	for _, name := range names {
+		headers = append(headers, fmt.Sprintf("%s (%s)", name, types[name]))
+	}
+	rows := make([][]string, 0, len(pages))
+	for _, page := range pages {
+		row := []string{page.ID, page.LastEditedTime.UTC().Format(time.RFC3339)}
+		for _, name := range names {
+			row = append(row, summarizePageProperty(page, name))
 		}
 		rows = append(rows, row)
 	}
@@ -565,6 +932,44 @@ func joinPeople(people []notion.UserReference) string {
 	return strings.Join(names, ", ")
 }
 
+// summarizePageProperty summarizes a property for tables, naming expanded related pages by
+// title instead of listing their IDs.
+func summarizePageProperty(page notion.Page, name string) string {
+	related := page.ExpandedRelations[name]
+	if len(related) == 0 {
+		related = inlinedPages(page.Properties[name])
+	}
+	if len(related) == 0 {
+		return summarizeProperty(page.Properties[name])
+	}
+	titles := make([]string, 0, len(related))
+	for _, rel := range related {
+		title := pageTitle(rel)
+		if title == "" {
+			title = rel.ID
+		}
+		titles = append(titles, title)
+	}
+	return strings.Join(titles, ", ")
+}
+
+// inlinedPages returns the related pages --expand-inline placed in a relation or rollup value.
+func inlinedPages(val notion.PropertyValue) []notion.Page {
+	refs := append([]notion.RelationReference(nil), val.Relation...)
+	if val.Rollup != nil {
+		for _, item := range val.Rollup.Array {
+			refs = append(refs, item.Relation...)
+		}
+	}
+	var pages []notion.Page
+	for _, ref := range refs {
+		if ref.Page != nil {
+			pages = append(pages, *ref.Page)
+		}
+	}
+	return pages
+}
+
 func summarizeRelations(val notion.PropertyValue) string {
 	if len(val.Relation) == 0 {
 		return ""
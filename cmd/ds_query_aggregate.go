@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/render"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+// aggregateOptions holds the client-side aggregation flags for ds query.
+type aggregateOptions struct {
+	groupBy string
+	sum     []string
+	avg     []string
+	count   bool
+}
+
+func (a aggregateOptions) enabled() bool {
+	return a.count || a.groupBy != "" || len(a.sum) > 0 || len(a.avg) > 0
+}
+
+// aggregateReport is the JSON shape emitted when aggregation flags are set.
+type aggregateReport struct {
+	GroupBy string         `json:"group_by,omitempty"`
+	Rows    []aggregateRow `json:"rows"`
+}
+
+//nolint:govet // fieldalignment: field order mirrors the rendered columns.
+type aggregateRow struct {
+	Group string             `json:"group,omitempty"`
+	Count int                `json:"count"`
+	Sum   map[string]float64 `json:"sum,omitempty"`
+	Avg   map[string]float64 `json:"avg,omitempty"`
+}
+
+type numericAccumulator struct {
+	total float64
+	n     int
+}
+
+func (a aggregateOptions) resolve(idx *schema.Index) (aggregateOptions, error) {
+	resolved := aggregateOptions{count: a.count}
+	if a.groupBy != "" {
+		ref, ok := idx.ReferenceForName(a.groupBy)
+		if !ok {
+			return aggregateOptions{}, fmt.Errorf("unknown --group-by property %q", a.groupBy)
+		}
+		resolved.groupBy = ref.Name
+	}
+	var err error
+	if resolved.sum, err = resolveAggregateNames(a.sum, idx, "--sum"); err != nil {
+		return aggregateOptions{}, err
+	}
+	if resolved.avg, err = resolveAggregateNames(a.avg, idx, "--avg"); err != nil {
+		return aggregateOptions{}, err
+	}
+	return resolved, nil
+}
+
+// numericRollupFunctions are the rollup functions whose result is a number.
+var numericRollupFunctions = map[string]bool{
+	"count": true, "count_values": true, "empty": true, "not_empty": true, "unique": true,
+	"percent_empty": true, "percent_not_empty": true, "sum": true, "average": true, "median": true,
+	"min": true, "max": true, "range": true, "checked": true, "unchecked": true,
+	"percent_checked": true, "percent_unchecked": true,
+}
+
+// resolveAggregateNames maps names to properties that hold numbers: number properties,
+// rollups whose function returns a number, and formulas, whose result type the schema does
+// not record and checkNumericResults checks in the rows instead.
+func resolveAggregateNames(names []string, idx *schema.Index, flag string) ([]string, error) {
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		ref, ok := idx.ReferenceForName(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown %s property %q", flag, name)
+		}
+		switch ref.Type {
+		case "number", "formula":
+		case "rollup":
+			if function := rollupFunction(ref); function != "" && !numericRollupFunctions[function] {
+				return nil, fmt.Errorf("%s property %q is a rollup with function %s, which is not a number", flag, ref.Name, function)
+			}
+		default:
+			return nil, fmt.Errorf("%s property %q is a %s property, not a number", flag, ref.Name, ref.Type)
+		}
+		out = append(out, ref.Name)
+	}
+	return out, nil
+}
+
+// rollupFunction reads a rollup's function from its raw schema, or returns "" when the
+// schema was not kept.
+func rollupFunction(ref notion.PropertyReference) string {
+	var raw struct {
+		Rollup struct {
+			Function string `json:"function"`
+		} `json:"rollup"`
+	}
+	if len(ref.Raw) == 0 || json.Unmarshal(ref.Raw, &raw) != nil {
+		return ""
+	}
+	return raw.Rollup.Function
+}
+
+// checkNumericResults rejects --sum and --avg formulas that returned something other than a
+// number on any row.
+func checkNumericResults(pages []notion.Page, opts aggregateOptions) error {
+	for _, group := range []struct {
+		flag  string
+		names []string
+	}{{"--sum", opts.sum}, {"--avg", opts.avg}} {
+		for _, name := range group.names {
+			for _, page := range pages {
+				formula := page.Properties[name].Formula
+				if formula != nil && formula.Type != "" && formula.Type != "number" {
+					return fmt.Errorf("%s property %q is a formula with a %s result, not a number", group.flag, name, formula.Type)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// aggregatePages computes counts, sums, and averages per group (or overall when no
+// group-by property is set). Groups are ordered by key for stable output.
+func aggregatePages(pages []notion.Page, opts aggregateOptions) aggregateReport {
+	type groupState struct {
+		sums  map[string]*numericAccumulator
+		avgs  map[string]*numericAccumulator
+		count int
+	}
+	groups := map[string]*groupState{}
+	keys := []string{}
+	groupFor := func(key string) *groupState {
+		state, ok := groups[key]
+		if !ok {
+			state = &groupState{sums: map[string]*numericAccumulator{}, avgs: map[string]*numericAccumulator{}}
+			groups[key] = state
+			keys = append(keys, key)
+		}
+		return state
+	}
+	if opts.groupBy == "" {
+		groupFor("")
+	}
+
+	for _, page := range pages {
+		key := ""
+		if opts.groupBy != "" {
+			key = summarizeProperty(page.Properties[opts.groupBy])
+		}
+		state := groupFor(key)
+		state.count++
+		accumulate(state.sums, opts.sum, page)
+		accumulate(state.avgs, opts.avg, page)
+	}
+	sort.Strings(keys)
+
+	report := aggregateReport{GroupBy: opts.groupBy, Rows: make([]aggregateRow, 0, len(keys))}
+	for _, key := range keys {
+		state := groups[key]
+		row := aggregateRow{Group: key, Count: state.count}
+		if len(opts.sum) > 0 {
+			row.Sum = map[string]float64{}
+			for _, name := range opts.sum {
+				if acc := state.sums[name]; acc != nil {
+					row.Sum[name] = acc.total
+				} else {
+					row.Sum[name] = 0
+				}
+			}
+		}
+		if len(opts.avg) > 0 {
+			row.Avg = map[string]float64{}
+			for _, name := range opts.avg {
+				if acc := state.avgs[name]; acc != nil && acc.n > 0 {
+					row.Avg[name] = acc.total / float64(acc.n)
+				}
+			}
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	return report
+}
+
+func accumulate(accs map[string]*numericAccumulator, names []string, page notion.Page) {
+	for _, name := range names {
+		acc, ok := accs[name]
+		if !ok {
+			acc = &numericAccumulator{}
+			accs[name] = acc
+		}
+		if v, ok := numericValue(page.Properties[name]); ok {
+			acc.total += v
+			acc.n++
+		}
+	}
+}
+
+// numericValue extracts a number from number, formula, and rollup properties.
+func numericValue(val notion.PropertyValue) (float64, bool) {
+	switch {
+	case val.Number != nil:
+		return *val.Number, true
+	case val.Formula != nil && val.Formula.Number != nil:
+		return *val.Formula.Number, true
+	case val.Rollup != nil && val.Rollup.Number != nil:
+		return *val.Rollup.Number, true
+	default:
+		return 0, false
+	}
+}
+
+func renderAggregates(cmd *cobra.Command, format string, report aggregateReport, opts aggregateOptions) error {
+	switch format {
+	case formatJSON:
+		if err := writeJSON(cmd.Context(), cmd.OutOrStdout(), report); err != nil {
+			return fmt.Errorf("render json: %w", err)
+		}
+		return nil
+	case formatTable:
+		headers, rows := aggregateTable(report, opts)
+		if err := render.Table(cmd.OutOrStdout(), headers, rows); err != nil {
+			return fmt.Errorf("render table: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q (expected json or table)", format)
+	}
+}
+
+func aggregateTable(report aggregateReport, opts aggregateOptions) ([]string, [][]string) {
+	var headers []string
+	if opts.groupBy != "" {
+		headers = append(headers, opts.groupBy)
+	}
+	headers = append(headers, "Count")
+	for _, name := range opts.sum {
+		headers = append(headers, fmt.Sprintf("sum(%s)", name))
+	}
+	for _, name := range opts.avg {
+		headers = append(headers, fmt.Sprintf("avg(%s)", name))
+	}
+
+	rows := make([][]string, 0, len(report.Rows))
+	for _, r := range report.Rows {
+		var row []string
+		if opts.groupBy != "" {
+			row = append(row, r.Group)
+		}
+		row = append(row, strconv.Itoa(r.Count))
+		for _, name := range opts.sum {
+			row = append(row, strconv.FormatFloat(r.Sum[name], 'f', -1, 64))
+		}
+		for _, name := range opts.avg {
+			value, ok := r.Avg[name]
+			if !ok {
+				row = append(row, "")
+				continue
+			}
+			row = append(row, strconv.FormatFloat(value, 'f', -1, 64))
+		}
+		rows = append(rows, row)
+	}
+	return headers, rows
+}
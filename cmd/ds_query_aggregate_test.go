@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+func TestAggregatePages(t *testing.T) {
+	page := func(status string, points *float64) notion.Page {
+		return notion.Page{Properties: map[string]notion.PropertyValue{
+			"Status": {Type: "status", Status: &notion.StatusValue{Name: status}},
+			"Points": {Type: "number", Number: points},
+		}}
+	}
+	pages := []notion.Page{
+		page("Done", floatPtr(3)),
+		page("Todo", floatPtr(1)),
+		page("Done", floatPtr(5)),
+		page("Done", nil),
+	}
+
+	report := aggregatePages(pages, aggregateOptions{groupBy: "Status", sum: []string{"Points"}, avg: []string{"Points"}})
+	if len(report.Rows) != 2 {
+		t.Fatalf("expected 2 groups, got %#v", report.Rows)
+	}
+	done := report.Rows[0]
+	if done.Group != "Done" || done.Count != 3 || done.Sum["Points"] != 8 || done.Avg["Points"] != 4 {
+		t.Fatalf("unexpected Done aggregate: %#v", done)
+	}
+	if todo := report.Rows[1]; todo.Group != "Todo" || todo.Count != 1 {
+		t.Fatalf("unexpected Todo aggregate: %#v", todo)
+	}
+
+	overall := aggregatePages(nil, aggregateOptions{count: true, sum: []string{"Points"}})
+	if len(overall.Rows) != 1 || overall.Rows[0].Count != 0 || overall.Rows[0].Sum["Points"] != 0 {
+		t.Fatalf("unexpected empty aggregate: %#v", overall.Rows)
+	}
+}
+
+func TestAggregateRejectsNonNumericProperties(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{Properties: map[string]notion.PropertyReference{
+		"Points":  {Name: "Points", Type: "number"},
+		"Status":  {Name: "Status", Type: "status"},
+		"Total":   {Name: "Total", Type: "rollup", Raw: []byte(`{"type":"rollup","rollup":{"function":"sum"}}`)},
+		"Owners":  {Name: "Owners", Type: "rollup", Raw: []byte(`{"type":"rollup","rollup":{"function":"show_original"}}`)},
+		"Formula": {Name: "Formula", Type: "formula"},
+	}})
+	if _, err := resolveAggregateNames([]string{"Points", "Total", "Formula"}, idx, "--sum"); err != nil {
+		t.Fatalf("numeric properties rejected: %v", err)
+	}
+	for _, name := range []string{"Status", "Owners"} {
+		if _, err := resolveAggregateNames([]string{name}, idx, "--avg"); err == nil {
+			t.Fatalf("--avg %s was accepted", name)
+		}
+	}
+
+	text := "high"
+	pages := []notion.Page{{Properties: map[string]notion.PropertyValue{
+		"Formula": {Type: "formula", Formula: &notion.FormulaValue{Type: "string", String: &text}},
+	}}}
+	if err := checkNumericResults(pages, aggregateOptions{sum: []string{"Formula"}}); err == nil {
+		t.Fatal("a formula with a string result was accepted")
+	}
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+// textMatcher reports whether a row's text matches the --grep pattern.
+type textMatcher func(text string) bool
+
+// newTextMatcher builds a case-insensitive substring matcher, or a regular expression
+// matcher when regex is set.
+func newTextMatcher(pattern string, regex bool) (textMatcher, error) {
+	if regex {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("parse --grep: %w", err)
+		}
+		return re.MatchString, nil
+	}
+	needle := strings.ToLower(pattern)
+	return func(text string) bool { return strings.Contains(strings.ToLower(text), needle) }, nil
+}
+
+// grepPages keeps the pages whose title or rich_text properties match.
+func grepPages(pages []notion.Page, match textMatcher) []notion.Page {
+	kept := pages[:0]
+	for _, page := range pages {
+		if pageTextMatches(page, match) {
+			kept = append(kept, page)
+		}
+	}
+	return kept
+}
+
+func pageTextMatches(page notion.Page, match textMatcher) bool {
+	for _, value := range page.Properties {
+		switch value.Type {
+		case "title", "rich_text":
+			if match(summarizeProperty(value)) {
+				return true
+			}
+		}
+	}
+	return false
+}
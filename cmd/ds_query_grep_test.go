@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+type staticQuerier []notion.Page
+
+func (q staticQuerier) QueryDataSource(
+	context.Context,
+	string,
+	notion.QueryDataSourceRequest,
+) (notion.QueryDataSourceResponse, error) {
+	return notion.QueryDataSourceResponse{Results: append([]notion.Page(nil), q...)}, nil
+}
+
+func TestQueryGrepFiltersTextProperties(t *testing.T) {
+	row := func(id, title, notes, status string) notion.Page {
+		return notion.Page{ID: id, Properties: map[string]notion.PropertyValue{
+			"Name":   {Type: "title", Title: []notion.RichText{{PlainText: title}}},
+			"Notes":  {Type: "rich_text", RichText: []notion.RichText{{PlainText: notes}}},
+			"Status": {Type: "status", Status: &notion.StatusValue{Name: status}},
+		}}
+	}
+	rows := staticQuerier{
+		row("p1", "Fix login timeout", "", "Open"),
+		row("p2", "Docs", "mentions LOGIN flow", "Open"),
+		row("p3", "Refactor", "nothing here", "login"),
+		row("p4", "Login v2", "", "Done"),
+	}
+
+	opts := &dsQueryOptions{dataSourceID: "ds", grep: "login", limit: 2}
+	resp, err := opts.fetch(context.Background(), rows, notion.QueryDataSourceRequest{})
+	if err != nil {
+		t.Fatalf("fetch returned error: %v", err)
+	}
+	if len(resp.Results) != 2 || resp.Results[0].ID != "p1" || resp.Results[1].ID != "p2" {
+		t.Fatalf("unexpected substring matches: %+v", resp.Results)
+	}
+
+	opts = &dsQueryOptions{dataSourceID: "ds", grep: `^(?i)login v\d$`, grepRegex: true}
+	resp, err = opts.fetch(context.Background(), rows, notion.QueryDataSourceRequest{})
+	if err != nil {
+		t.Fatalf("fetch returned error: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].ID != "p4" {
+		t.Fatalf("unexpected regex matches: %+v", resp.Results)
+	}
+
+	opts.grep = "("
+	if _, err := opts.fetch(context.Background(), rows, notion.QueryDataSourceRequest{}); err == nil {
+		t.Fatalf("expected invalid regex error")
+	}
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestQueryWithoutSchema(t *testing.T) {
+	opts := &dsQueryOptions{
+		dataSourceID:     "ds",
+		noSchema:         true,
+		filterJSON:       `{"property":"abc%3D","checkbox":{"equals":true}}`,
+		sortSpec:         "Due:desc",
+		filterProperties: []string{"abc%3D", "title"},
+	}
+	idx, err := opts.queryIndex(context.Background(), nil)
+	if err != nil || idx != nil {
+		t.Fatalf("queryIndex = %v, %v; want nil index", idx, err)
+	}
+
+	req, err := opts.buildRequest(idx)
+	if err != nil {
+		t.Fatalf("buildRequest returned error: %v", err)
+	}
+	if filter, _ := req.Filter.(map[string]any); filter["property"] != "abc%3D" {
+		t.Fatalf("filter should pass through unmapped, got %#v", req.Filter)
+	}
+	if sort, _ := req.Sorts[0].(map[string]any); sort["property"] != "Due" {
+		t.Fatalf("sort should pass through unmapped, got %#v", req.Sorts)
+	}
+	if !slices.Equal(req.FilterProperties, []string{"abc%3D", "title"}) {
+		t.Fatalf("unexpected filter properties: %v", req.FilterProperties)
+	}
+
+	for _, needsSchema := range []*dsQueryOptions{
+		{whereExpr: "Done = true"},
+		{expandRelations: []string{"Owner"}},
+	} {
+		if _, err := needsSchema.buildRequest(nil); err == nil {
+			t.Fatalf("expected schema error for %+v", needsSchema)
+		}
+	}
+
+	done := true
+	headers, rows := queryResultsTable([]notion.Page{
+		{ID: "p1", Properties: map[string]notion.PropertyValue{
+			"Name": {Type: "title", Title: []notion.RichText{{PlainText: "Ship it"}}},
+		}},
+		{ID: "p2", Properties: map[string]notion.PropertyValue{
+			"Done": {Type: "checkbox", Checkbox: &done},
+		}},
+	}, nil)
+	if want := []string{"ID", "Last Edited", "Done (checkbox)", "Name (title)"}; !slices.Equal(headers, want) {
+		t.Fatalf("headers = %v, want %v", headers, want)
+	}
+	if rows[0][3] != "Ship it" || rows[1][2] != "true" {
+		t.Fatalf("unexpected rows: %v", rows)
+	}
+}
+
+func TestSchemaUnavailable(t *testing.T) {
+	if !schemaUnavailable(fmt.Errorf("get data source: %w", &notion.Error{Status: 403})) {
+		t.Fatalf("403 should fall back to schemaless mode")
+	}
+	if schemaUnavailable(fmt.Errorf("get data source: %w", &notion.Error{Status: 500})) {
+		t.Fatalf("500 should not fall back")
+	}
+}
@@ -1,6 +1,11 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/yourorg/notionctl/internal/notion"
@@ -79,3 +84,162 @@ func TestSummarizeProperty(t *testing.T) {
 func floatPtr(v float64) *float64 {
 	return &v
 }
+
+func TestParseSortShorthand(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{
+		Properties: map[string]notion.PropertyReference{
+			"Due":      {ID: "due", Name: "Due", Type: "date"},
+			"Priority": {ID: "pri", Name: "Priority", Type: "select"},
+		},
+	})
+
+	sorts, err := parseSortShorthand("Due:asc, priority:DESC,last_edited_time", idx)
+	if err != nil {
+		t.Fatalf("parseSortShorthand returned error: %v", err)
+	}
+	if len(sorts) != 3 {
+		t.Fatalf("expected 3 sorts, got %d", len(sorts))
+	}
+	first, ok := sorts[0].(map[string]any)
+	if !ok || first["property"] != "due" || first["direction"] != "ascending" {
+		t.Fatalf("unexpected first sort: %#v", sorts[0])
+	}
+	second, ok := sorts[1].(map[string]any)
+	if !ok || second["property"] != "pri" || second["direction"] != "descending" {
+		t.Fatalf("unexpected second sort: %#v", sorts[1])
+	}
+	third, ok := sorts[2].(map[string]any)
+	if !ok || third["timestamp"] != "last_edited_time" {
+		t.Fatalf("unexpected timestamp sort: %#v", sorts[2])
+	}
+
+	if _, err := parseSortShorthand("Due:sideways", idx); err == nil {
+		t.Fatalf("expected error for invalid direction")
+	}
+
+	byID, err := parseSortShorthand("property_id:pri:desc,property_id:due", idx)
+	if err != nil {
+		t.Fatalf("parseSortShorthand with IDs returned error: %v", err)
+	}
+	if first, _ := byID[0].(map[string]any); first["property"] != "pri" || first["direction"] != "descending" {
+		t.Fatalf("unexpected ID sort: %#v", byID[0])
+	}
+	if second, _ := byID[1].(map[string]any); second["property"] != "due" || second["direction"] != "ascending" {
+		t.Fatalf("unexpected ID sort: %#v", byID[1])
+	}
+	if _, err := parseSortShorthand("Missing", idx); err == nil {
+		t.Fatalf("expected error for unknown property")
+	}
+}
+
+func TestLoadJSONValueFromStdin(t *testing.T) {
+	stdin := strings.NewReader(`{"property":"Status","status":{"equals":"Done"}}`)
+	payload, err := loadJSONValue("", "-", stdin)
+	if err != nil {
+		t.Fatalf("loadJSONValue returned error: %v", err)
+	}
+	obj, ok := payload.(map[string]any)
+	if !ok || obj["property"] != "Status" {
+		t.Fatalf("unexpected payload: %#v", payload)
+	}
+}
+
+func TestDSQueryRejectsTwoStdinReaders(t *testing.T) {
+	root := newRootCmd(&globalOptions{profile: "default"})
+	root.SetArgs([]string{"ds", "query", "--data-source-id", "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d", "--filter-file", "-", "--sorts-file", "-"})
+	root.SetIn(strings.NewReader(`{}`))
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--filter-file and --sorts-file each read stdin") {
+		t.Fatalf("expected both stdin readers to be rejected up front, got %v", err)
+	}
+}
+
+type pagedQuerier struct {
+	total     int
+	pageSizes []int
+}
+
+func (q *pagedQuerier) QueryDataSource(
+	_ context.Context,
+	_ string,
+	req notion.QueryDataSourceRequest,
+) (notion.QueryDataSourceResponse, error) {
+	q.pageSizes = append(q.pageSizes, req.PageSize)
+	start, _ := strconv.Atoi(req.StartCursor)
+	end := min(start+req.PageSize, q.total)
+	resp := notion.QueryDataSourceResponse{}
+	for i := start; i < end; i++ {
+		resp.Results = append(resp.Results, notion.Page{ID: strconv.Itoa(i)})
+	}
+	if end < q.total {
+		resp.HasMore = true
+		resp.NextCursor = strconv.Itoa(end)
+	}
+	return resp, nil
+}
+
+func TestExecuteDataSourceQueryLimit(t *testing.T) {
+	q := &pagedQuerier{total: 500}
+	resp, err := executeDataSourceQuery(context.Background(), q, "ds", notion.QueryDataSourceRequest{}, false, 250)
+	if err != nil {
+		t.Fatalf("executeDataSourceQuery returned error: %v", err)
+	}
+	if len(resp.Results) != 250 || !resp.HasMore || resp.NextCursor != "250" {
+		t.Fatalf("unexpected limited response: %d results, has_more=%v, cursor=%q",
+			len(resp.Results), resp.HasMore, resp.NextCursor)
+	}
+	if want := []int{100, 100, 50}; !slices.Equal(q.pageSizes, want) {
+		t.Fatalf("page sizes = %v, want %v", q.pageSizes, want)
+	}
+
+	q = &pagedQuerier{total: 30}
+	resp, err = executeDataSourceQuery(context.Background(), q, "ds", notion.QueryDataSourceRequest{PageSize: 20}, false, 250)
+	if err != nil {
+		t.Fatalf("executeDataSourceQuery returned error: %v", err)
+	}
+	if len(resp.Results) != 30 || resp.HasMore {
+		t.Fatalf("expected every row when the limit exceeds the total, got %d", len(resp.Results))
+	}
+}
+
+func TestStreamDataSourceQueryStopsAtLimit(t *testing.T) {
+	q := &pagedQuerier{total: 500}
+	var ids []string
+	for page, err := range streamDataSourceQuery(context.Background(), q, "ds", notion.QueryDataSourceRequest{}, 150) {
+		if err != nil {
+			t.Fatalf("streamDataSourceQuery returned error: %v", err)
+		}
+		ids = append(ids, page.ID)
+	}
+	if len(ids) != 150 || ids[149] != "149" {
+		t.Fatalf("expected the first 150 rows, got %d", len(ids))
+	}
+	if want := []int{100, 100}; !slices.Equal(q.pageSizes, want) {
+		t.Fatalf("page sizes = %v, want %v", q.pageSizes, want)
+	}
+}
+
+func TestQueryResultsTableShowsExpandedTitles(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{Properties: map[string]notion.PropertyReference{
+		"Project": {ID: "proj", Name: "Project", Type: "relation"},
+	}})
+	page := notion.Page{
+		ID: "task-1",
+		Properties: map[string]notion.PropertyValue{
+			"Project": {Type: "relation", Relation: []notion.RelationReference{{ID: "p1"}, {ID: "p2"}}},
+		},
+		ExpandedRelations: map[string][]notion.Page{"Project": {
+			{ID: "p1", Properties: map[string]notion.PropertyValue{
+				"Name": {Type: "title", Title: []notion.RichText{{PlainText: "Launch"}}},
+			}},
+			{ID: "p2"},
+		}},
+	}
+
+	_, rows := queryResultsTable([]notion.Page{page}, idx)
+	if got := rows[0][2]; got != "Launch, p2" {
+		t.Fatalf("relation cell = %q, want titles with an ID fallback", got)
+	}
+}
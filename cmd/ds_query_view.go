@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/notionid"
+)
+
+// viewClient is the subset of the Notion client needed to look up saved views.
+type viewClient interface {
+	RetrieveView(ctx context.Context, viewID string) (notion.View, error)
+	ListViews(ctx context.Context, dataSourceID string) ([]notion.View, error)
+}
+
+// loadView resolves --view to a saved view definition. IDs and view URLs are fetched
+// directly; anything else is matched by name against the data source's views. When
+// --data-source-id is omitted, the view's own data source is queried.
+func (opts *dsQueryOptions) loadView(ctx context.Context, client viewClient) error {
+	ref := strings.TrimSpace(opts.viewRef)
+	if ref == "" {
+		return nil
+	}
+
+	view, err := findView(ctx, client, ref, opts.dataSourceID)
+	if err != nil {
+		return err
+	}
+	if opts.dataSourceID == "" {
+		if view.DataSourceID == "" {
+			return fmt.Errorf("view %q does not report its data source; pass --data-source-id", ref)
+		}
+		if opts.dataSourceID, err = notionid.Parse(view.DataSourceID); err != nil {
+			return fmt.Errorf("view %q: %w", ref, err)
+		}
+	}
+	opts.view = &view
+	return nil
+}
+
+func findView(ctx context.Context, client viewClient, ref, dataSourceID string) (notion.View, error) {
+	if id, err := viewID(ref); err == nil {
+		view, err := client.RetrieveView(ctx, id)
+		if err != nil {
+			return notion.View{}, fmt.Errorf("retrieve view: %w", err)
+		}
+		return view, nil
+	}
+
+	if dataSourceID == "" {
+		return notion.View{}, errors.New("--data-source-id is required to look up a view by name")
+	}
+	views, err := client.ListViews(ctx, dataSourceID)
+	if err != nil {
+		return notion.View{}, fmt.Errorf("list views: %w", err)
+	}
+	for _, view := range views {
+		if strings.EqualFold(strings.TrimSpace(view.Name), ref) {
+			return view, nil
+		}
+	}
+	return notion.View{}, fmt.Errorf("no view named %q on data source %s", ref, dataSourceID)
+}
+
+// viewID extracts a view ID from a raw ID or from the ?v= parameter of a database URL.
+func viewID(ref string) (string, error) {
+	if _, query, ok := strings.Cut(ref, "?"); ok {
+		for _, pair := range strings.Split(query, "&") {
+			if value, found := strings.CutPrefix(pair, "v="); found {
+				return notionid.Parse(value)
+			}
+		}
+	}
+	return notionid.Parse(ref)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+type fakeViewClient struct {
+	views     []notion.View
+	retrieved string
+}
+
+func (f *fakeViewClient) RetrieveView(_ context.Context, viewID string) (notion.View, error) {
+	f.retrieved = viewID
+	return notion.View{ID: viewID, DataSourceID: "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d"}, nil
+}
+
+func (f *fakeViewClient) ListViews(context.Context, string) ([]notion.View, error) {
+	return f.views, nil
+}
+
+func TestLoadViewResolvesURLAndName(t *testing.T) {
+	client := &fakeViewClient{views: []notion.View{{ID: "v1", Name: "Open bugs"}}}
+
+	opts := &dsQueryOptions{viewRef: "https://www.notion.so/acme/0000?v=ffffffffffffffffffffffffffffffff"}
+	if err := opts.loadView(context.Background(), client); err != nil {
+		t.Fatalf("loadView returned error: %v", err)
+	}
+	if client.retrieved != "ffffffff-ffff-ffff-ffff-ffffffffffff" {
+		t.Fatalf("expected view ID from ?v=, got %q", client.retrieved)
+	}
+	if opts.dataSourceID != "1a2b3c4d-5e6f-7a8b-9c0d-1e2f3a4b5c6d" {
+		t.Fatalf("expected data source from view, got %q", opts.dataSourceID)
+	}
+
+	opts = &dsQueryOptions{viewRef: "open BUGS", dataSourceID: "ds"}
+	if err := opts.loadView(context.Background(), client); err != nil {
+		t.Fatalf("loadView by name returned error: %v", err)
+	}
+	if opts.view == nil || opts.view.ID != "v1" {
+		t.Fatalf("unexpected view: %#v", opts.view)
+	}
+
+	opts = &dsQueryOptions{viewRef: "Missing", dataSourceID: "ds"}
+	if err := opts.loadView(context.Background(), client); err == nil {
+		t.Fatalf("expected error for unknown view name")
+	}
+}
+
+func TestViewFilterAndSortsApplied(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{
+		Properties: map[string]notion.PropertyReference{
+			"Status": {ID: "st", Name: "Status", Type: "status"},
+			"Due":    {ID: "due", Name: "Due", Type: "date"},
+		},
+	})
+	opts := &dsQueryOptions{
+		whereExpr: "Due before 2025-01-01",
+		view: &notion.View{
+			Filter: map[string]any{"property": "Status", "status": map[string]any{"equals": "Open"}},
+			Sorts:  []any{map[string]any{"property": "Due", "direction": "ascending"}},
+		},
+	}
+
+	filter, err := opts.buildFilter(idx)
+	if err != nil {
+		t.Fatalf("buildFilter returned error: %v", err)
+	}
+	and, _ := filter.(map[string]any)["and"].([]any)
+	if len(and) != 2 || and[0].(map[string]any)["property"] != "st" {
+		t.Fatalf("expected view filter ANDed with --where, got %#v", filter)
+	}
+
+	sorts, err := opts.buildSorts(idx)
+	if err != nil {
+		t.Fatalf("buildSorts returned error: %v", err)
+	}
+	if len(sorts) != 1 || sorts[0].(map[string]any)["property"] != "due" {
+		t.Fatalf("expected view sorts, got %#v", sorts)
+	}
+
+	opts.sortSpec = "Status:desc"
+	sorts, err = opts.buildSorts(idx)
+	if err != nil {
+		t.Fatalf("buildSorts returned error: %v", err)
+	}
+	if len(sorts) != 1 || sorts[0].(map[string]any)["property"] != "st" {
+		t.Fatalf("expected --sort to replace view sorts, got %#v", sorts)
+	}
+}
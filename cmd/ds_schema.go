@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/render"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+const (
+	formatEnv    = "env"
+	formatTFVars = "tfvars"
+	formatTS     = "ts"
+	formatYAML   = "yaml"
+
+	defaultEnvPrefix = "NOTION_PROP_"
+)
+
+type dsSchemaOptions struct {
+	dataSourceID string
+	format       string
+	envPrefix    string
+	profile      string
+	interactive  bool
+}
+
+// schemaProperty is one row of the name→ID mapping, in property name order.
+type schemaProperty struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// schemaFile is the document ds schema --format json|yaml writes.
+type schemaFile struct {
+	DataSourceID string                  `json:"data_source_id" yaml:"data_source_id"`
+	Name         string                  `json:"name" yaml:"name"`
+	Properties   []notion.PropertySchema `json:"properties" yaml:"properties"`
+}
+
+func newDSSchemaCmd(globals *globalOptions) *cobra.Command {
+	opts := &dsSchemaOptions{format: formatTable, envPrefix: defaultEnvPrefix}
+
+	cmd := &cobra.Command{
+		Use:   "schema [data-source-id|alias]",
+		Short: "Print a data source's properties with their full configuration, or its name to ID mapping",
+		Args:  dataSourceArg(globals),
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Target Notion data source ID or URL")
+	formatFlag(cmd, &opts.format, "Output format", formatTable, formatJSON, formatYAML, formatEnv, formatTFVars, formatTS)
+	cmd.Flags().StringVar(&opts.envPrefix, "env-prefix", opts.envPrefix, "Variable name prefix for --format env")
+	cmd.Flags().BoolVarP(
+		&opts.interactive,
+		"interactive",
+		"i",
+		false,
+		"Browse properties and their full configuration on the terminal, copying IDs as you go",
+	)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
+
+	cmd.AddCommand(newDSSchemaExportCmd(globals))
+	cmd.AddCommand(newDSSchemaApplyCmd(globals))
+
+	return cmd
+}
+
+// newDSSchemaExportCmd writes the schema as YAML for version control; ds schema apply reads
+// it back.
+func newDSSchemaExportCmd(globals *globalOptions) *cobra.Command {
+	opts := &dsSchemaOptions{format: formatYAML}
+
+	cmd := &cobra.Command{
+		Use:   "export [data-source-id|alias]",
+		Short: "Write a data source's schema as YAML for ds schema apply",
+		Args:  dataSourceArg(globals),
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Source Notion data source ID or URL")
+	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
+
+	return cmd
+}
+
+// dataSourceArg accepts the data source as an optional argument (ID, URL, or alias) in place
+// of --data-source-id. It sets the flag, so required-flag checks and pickers see it.
+func dataSourceArg(globals *globalOptions) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := cobra.MaximumNArgs(1)(cmd, args); err != nil || len(args) == 0 {
+			return err
+		}
+		if cmd.Flags().Changed("data-source-id") {
+			return errors.New("pass the data source as an argument or with --data-source-id, not both")
+		}
+		id, err := resolveDataSourceAlias(globals.profile, args[0])
+		if err != nil {
+			return err
+		}
+		return cmd.Flags().Set("data-source-id", id)
+	}
+}
+
+func (opts *dsSchemaOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		client, err := buildClient(globals)
+		if err != nil {
+			return err
+		}
+		ds, err := client.GetDataSource(cmd.Context(), opts.dataSourceID)
+		if err != nil {
+			return fmt.Errorf("get data source: %w", err)
+		}
+		// Keep a copy for offline helpers such as examples; a failed write is not fatal.
+		if err := config.SaveSchema(globals.profile, ds); err != nil {
+			safeLog(cmd.ErrOrStderr(), "warning: cache schema: %v", err)
+		}
+		if opts.interactive {
+			return explore(cmd.Context(), globals.profile, ds)
+		}
+		opts.profile = globals.profile
+		return opts.write(cmd.Context(), cmd.OutOrStdout(), ds)
+	}
+}
+
+func (opts *dsSchemaOptions) write(ctx context.Context, w io.Writer, ds notion.DataSource) error {
+	switch opts.format {
+	case formatTable, formatJSON, formatYAML:
+		full, err := ds.Schema()
+		if err != nil {
+			return err
+		}
+		return opts.writeFull(ctx, w, full)
+	case formatEnv:
+		return writeLine(w, schemaEnv(ds, schemaProperties(ds), opts.envPrefix))
+	case formatTFVars:
+		return writeLine(w, schemaTFVars(ds, schemaProperties(ds)))
+	case formatTS:
+		return writeLine(w, schemaTS(ds, schemaProperties(ds)))
+	default:
+		return fmt.Errorf("unknown format %q (expected table, json, yaml, env, tfvars, or ts)", opts.format)
+	}
+}
+
+func (opts *dsSchemaOptions) writeFull(ctx context.Context, w io.Writer, full notion.DataSourceSchema) error {
+	file := schemaFile{DataSourceID: full.ID, Name: full.Name, Properties: full.Properties}
+	switch opts.format {
+	case formatJSON:
+		return writeJSON(ctx, w, file)
+	case formatYAML:
+		data, err := yaml.Marshal(file)
+		if err != nil {
+			return fmt.Errorf("encode schema: %w", err)
+		}
+		_, err = w.Write(data)
+		return err
+	default:
+		rows := make([][]string, 0, len(full.Properties))
+		for _, prop := range full.Properties {
+			rows = append(rows, []string{prop.Name, prop.ID, prop.Type, opts.configSummary(prop)})
+		}
+		return render.Table(w, []string{"Name", "ID", "Type", "Configuration"}, rows)
+	}
+}
+
+// configSummary condenses a property's type configuration into one table cell.
+func (opts *dsSchemaOptions) configSummary(prop notion.PropertySchema) string {
+	switch {
+	case len(prop.Groups) > 0:
+		byID := make(map[string]string, len(prop.Options))
+		for _, option := range prop.Options {
+			byID[option.ID] = option.Name
+		}
+		groups := make([]string, 0, len(prop.Groups))
+		for _, group := range prop.Groups {
+			names := make([]string, 0, len(group.OptionIDs))
+			for _, id := range group.OptionIDs {
+				if name, ok := byID[id]; ok {
+					names = append(names, name)
+				}
+			}
+			groups = append(groups, group.Name+": "+strings.Join(names, ", "))
+		}
+		return strings.Join(groups, "; ")
+	case len(prop.Options) > 0:
+		names := make([]string, 0, len(prop.Options))
+		for _, option := range prop.Options {
+			names = append(names, option.Name)
+		}
+		return strings.Join(names, ", ")
+	case prop.Relation != nil:
+		summary := "→ " + prop.Relation.DataSourceID
+		if name := opts.dataSourceName(prop.Relation.DataSourceID); name != "" {
+			summary += " (" + name + ")"
+		}
+		if prop.Relation.SyncedPropertyName != "" {
+			summary += ", synced with " + prop.Relation.SyncedPropertyName
+		}
+		return summary
+	case prop.Rollup != nil:
+		return fmt.Sprintf("%s of %s via %s", prop.Rollup.Function, prop.Rollup.RollupProperty, prop.Rollup.RelationProperty)
+	case prop.Formula != "":
+		return "= " + prop.Formula
+	case prop.NumberFormat != "":
+		return "format: " + prop.NumberFormat
+	case prop.Prefix != "":
+		return "prefix: " + prop.Prefix
+	default:
+		return ""
+	}
+}
+
+// dataSourceName labels a relation target by alias or cached name when a profile is known.
+func (opts *dsSchemaOptions) dataSourceName(id string) string {
+	if opts.profile == "" {
+		return ""
+	}
+	return dataSourceLabel(opts.profile, id)
+}
+
+func schemaProperties(ds notion.DataSource) []schemaProperty {
+	idx := schema.NewIndex(ds)
+	names := idx.PropertyNames()
+	props := make([]schemaProperty, 0, len(names))
+	for _, name := range names {
+		ref := ds.Properties[name]
+		props = append(props, schemaProperty{Name: name, ID: ref.ID, Type: ref.Type})
+	}
+	return props
+}
+
+// schemaEnv renders dotenv assignments such as NOTION_PROP_DUE_DATE="abc". Names that
+// collapse to the same variable get numeric suffixes in property name order.
+func schemaEnv(ds notion.DataSource, props []schemaProperty, prefix string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s (%s)\n", ds.Name, ds.ID)
+	dsKey := prefix + "DATA_SOURCE_ID"
+	fmt.Fprintf(&b, "%s=%s\n", dsKey, strconv.Quote(ds.ID))
+
+	seen := map[string]int{dsKey: 1}
+	for _, prop := range props {
+		key := prefix + envIdentifier(prop.Name)
+		if prefix == "" && unicode.IsDigit(rune(key[0])) {
+			key = "_" + key
+		}
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			key = fmt.Sprintf("%s_%d", key, n)
+		}
+		fmt.Fprintf(&b, "%s=%s\n", key, strconv.Quote(prop.ID))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// schemaTFVars renders Terraform variable assignments keyed by property name.
+func schemaTFVars(ds notion.DataSource, props []schemaProperty) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "notion_data_source_id = %s\n\n", strconv.Quote(ds.ID))
+	b.WriteString("notion_property_ids = {\n")
+	for _, prop := range props {
+		fmt.Fprintf(&b, "  %s = %s\n", strconv.Quote(prop.Name), strconv.Quote(prop.ID))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// schemaTS renders a TypeScript module exporting the mapping as constants.
+func schemaTS(ds notion.DataSource, props []schemaProperty) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Generated by notionctl ds schema from %s. Do not edit.\n\n", ds.Name)
+	fmt.Fprintf(&b, "export const DATA_SOURCE_ID = %s;\n\n", strconv.Quote(ds.ID))
+	b.WriteString("export const PropertyIds = {\n")
+	for _, prop := range props {
+		fmt.Fprintf(&b, "  %s: %s,\n", strconv.Quote(prop.Name), strconv.Quote(prop.ID))
+	}
+	b.WriteString("} as const;\n\n")
+	b.WriteString("export type PropertyName = keyof typeof PropertyIds;")
+	return b.String()
+}
+
+// envIdentifier upper-cases the ASCII letters and digits of name and joins the runs between
+// them with "_".
+func envIdentifier(name string) string {
+	var b strings.Builder
+	pendingSep := false
+	for _, r := range name {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if pendingSep && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			pendingSep = false
+			b.WriteRune(unicode.ToUpper(r))
+			continue
+		}
+		pendingSep = true
+	}
+	if b.Len() == 0 {
+		return "PROPERTY"
+	}
+	return b.String()
+}
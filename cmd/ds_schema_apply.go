@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+type dsSchemaApplyOptions struct {
+	dryRun bool
+}
+
+// schemaPlan is what ds schema apply will send: properties to create (derived ones, which may
+// read the new properties, in a second request) and option additions, plus the differences
+// it leaves for a person to resolve.
+type schemaPlan struct {
+	base     map[string]any
+	derived  map[string]any
+	actions  []string
+	warnings []string
+}
+
+func newDSSchemaApplyCmd(globals *globalOptions) *cobra.Command {
+	opts := &dsSchemaApplyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "apply <schema.yaml> [data-source-id|alias]",
+		Short: "Create the properties and options a schema file has and the data source lacks",
+		Long: "Bring a data source up to a schema file written by ds schema export. Missing properties " +
+			"and select options are created; removals, renames, type changes, and other reconfiguration " +
+			"are only reported, so apply never loses data. The target defaults to the data source the " +
+			"file was exported from.",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: opts.run(globals),
+	}
+
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print the planned requests without sending them")
+
+	return cmd
+}
+
+func (opts *dsSchemaApplyOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		desired, err := loadSchemaFile(args[0])
+		if err != nil {
+			return err
+		}
+		target := desired.ID
+		if len(args) == 2 {
+			if target, err = resolveDataSourceAlias(globals.profile, args[1]); err != nil {
+				return err
+			}
+		}
+		if target == "" {
+			return fmt.Errorf("%s has no data_source_id; pass the target data source", args[0])
+		}
+
+		client, err := buildClient(globals)
+		if err != nil {
+			return err
+		}
+		live, err := client.GetDataSourceSchema(cmd.Context(), target)
+		if err != nil {
+			return fmt.Errorf("get data source: %w", err)
+		}
+		plan := planSchemaApply(live, desired)
+		for _, warning := range plan.warnings {
+			safeLog(cmd.ErrOrStderr(), "warning: %s", warning)
+		}
+		if len(plan.actions) == 0 {
+			safeLog(cmd.OutOrStdout(), "%s already has every property and option in %s", live.Name, args[0])
+			return nil
+		}
+		if opts.dryRun {
+			client = dryRunClient(client, cmd.OutOrStdout())
+		}
+		if err := plan.apply(cmd.Context(), client, target); err != nil {
+			return err
+		}
+		for _, action := range plan.actions {
+			if opts.dryRun {
+				safeLog(cmd.ErrOrStderr(), "dry run: %s", action)
+				continue
+			}
+			safeLog(cmd.OutOrStdout(), "%s", action)
+		}
+		return nil
+	}
+}
+
+// planSchemaApply turns the differences between the live schema and the desired one into
+// additive requests, and everything else into warnings.
+func planSchemaApply(live, desired notion.DataSourceSchema) schemaPlan {
+	plan := schemaPlan{base: map[string]any{}, derived: map[string]any{}}
+	byName := func(s notion.DataSourceSchema, name string) (notion.PropertySchema, bool) {
+		for _, prop := range s.Properties {
+			if prop.Name == name {
+				return prop, true
+			}
+		}
+		return notion.PropertySchema{}, false
+	}
+
+	for _, change := range schema.Diff(live, desired) {
+		switch {
+		case change.Kind == schema.ChangeAdded:
+			prop, _ := byName(desired, change.Property)
+			config, note := propertyCreateConfig(prop)
+			if prop.Type == "rollup" || prop.Type == "formula" {
+				plan.derived[prop.Name] = config
+			} else {
+				plan.base[prop.Name] = config
+			}
+			plan.actions = append(plan.actions, fmt.Sprintf("added %s (%s)", prop.Name, prop.Type))
+			if note != "" {
+				plan.warnings = append(plan.warnings, note)
+			}
+		case change.Kind == schema.ChangeRemoved:
+			plan.warnings = append(plan.warnings, fmt.Sprintf(
+				"%s (%s) is not in the file; left in place, delete it in Notion if that is intended",
+				change.Property, change.Old))
+		case change.Kind == schema.ChangeRenamed:
+			plan.warnings = append(plan.warnings, fmt.Sprintf(
+				"%s is named %s in Notion; not renamed, since renames break scripts that use the old name",
+				change.New, change.Old))
+		case change.Field == "options":
+			plan.planOptions(change, desired, live)
+		default:
+			plan.warnings = append(plan.warnings, fmt.Sprintf("%s %s differs (%s in Notion, %s in the file); not changed",
+				change.Property, change.Field, orNone(change.Old), orNone(change.New)))
+		}
+	}
+	return plan
+}
+
+// planOptions adds the options the file has and Notion lacks. Notion replaces the whole
+// option list on update, so the existing options are sent along by ID.
+func (plan *schemaPlan) planOptions(change schema.Change, desired, live notion.DataSourceSchema) {
+	if change.Old != "" {
+		plan.warnings = append(plan.warnings, fmt.Sprintf(
+			"%s has options not in the file (%s); left in place", change.Property, change.Old))
+	}
+	if change.New == "" {
+		return
+	}
+	var current, wanted notion.PropertySchema
+	for _, prop := range desired.Properties {
+		if prop.Name == change.Property {
+			wanted = prop
+		}
+	}
+	// Diff reports changes under the desired name; find the live property it was paired with.
+	for _, prop := range live.Properties {
+		if prop.Name == change.Property || (prop.ID != "" && prop.ID == wanted.ID) {
+			current = prop
+		}
+	}
+	if current.Type == statusType {
+		plan.warnings = append(plan.warnings, fmt.Sprintf(
+			"%s needs status options %s, which the Notion API cannot add; add them in Notion", change.Property, change.New))
+		return
+	}
+
+	options := make([]any, 0, len(current.Options)+len(wanted.Options))
+	for _, option := range current.Options {
+		options = append(options, map[string]any{"id": option.ID})
+	}
+	for _, option := range wanted.Options {
+		if _, exists := optionByName(current.Options, option.Name); !exists {
+			options = append(options, optionConfig(option))
+		}
+	}
+	plan.base[current.ID] = map[string]any{current.Type: map[string]any{"options": options}}
+	plan.actions = append(plan.actions, fmt.Sprintf("added options %s to %s", change.New, current.Name))
+}
+
+func (plan *schemaPlan) apply(ctx context.Context, client *notion.Client, dataSourceID string) error {
+	for _, properties := range []map[string]any{plan.base, plan.derived} {
+		if len(properties) == 0 {
+			continue
+		}
+		if _, err := client.UpdateDataSource(ctx, dataSourceID, notion.UpdateDataSourceRequest{Properties: properties}); err != nil {
+			return fmt.Errorf("update data source schema: %w", err)
+		}
+	}
+	return nil
+}
+
+// propertyCreateConfig builds the request that creates prop, with a note when part of its
+// configuration cannot be created through the API.
+func propertyCreateConfig(prop notion.PropertySchema) (map[string]any, string) {
+	config := map[string]any{}
+	var note string
+	switch prop.Type {
+	case "select", "multi_select":
+		options := make([]any, 0, len(prop.Options))
+		for _, option := range prop.Options {
+			options = append(options, optionConfig(option))
+		}
+		config["options"] = options
+	case statusType:
+		if len(prop.Options) > 0 {
+			note = fmt.Sprintf("%s is created with Notion's default status options; add the file's options in Notion", prop.Name)
+		}
+	case "number":
+		if prop.NumberFormat != "" {
+			config["format"] = prop.NumberFormat
+		}
+	case "formula":
+		config["expression"] = prop.Formula
+	case relationType:
+		if prop.Relation != nil {
+			config["data_source_id"] = prop.Relation.DataSourceID
+			kind := prop.Relation.Type
+			if kind == "" {
+				kind = "single_property"
+			}
+			config["type"] = kind
+			config[kind] = map[string]any{}
+		}
+	case "rollup":
+		if prop.Rollup != nil {
+			config["relation_property_name"] = prop.Rollup.RelationProperty
+			config["rollup_property_name"] = prop.Rollup.RollupProperty
+			config["function"] = prop.Rollup.Function
+		}
+	case "unique_id":
+		if prop.Prefix != "" {
+			config["prefix"] = prop.Prefix
+		}
+	}
+	return map[string]any{"type": prop.Type, prop.Type: config}, note
+}
+
+func optionConfig(option notion.SelectOption) map[string]any {
+	config := map[string]any{"name": option.Name}
+	if option.Color != "" {
+		config["color"] = option.Color
+	}
+	if option.Description != "" {
+		config["description"] = option.Description
+	}
+	return config
+}
+
+func optionByName(options []notion.SelectOption, name string) (notion.SelectOption, bool) {
+	for _, option := range options {
+		if option.Name == name {
+			return option, true
+		}
+	}
+	return notion.SelectOption{}, false
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestDSSchemaApply(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NOTIONCTL_TOKEN", "secret_apply")
+	keyring.MockInit()
+
+	const dataSourceID = "1a2b3c4d-5e6f-7a8b-9c0d-1e2f3a4b5c6d"
+	var patches []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			body, _ := io.ReadAll(r.Body)
+			var req struct {
+				Properties map[string]any `json:"properties"`
+			}
+			if err := json.Unmarshal(body, &req); err != nil {
+				t.Errorf("decode patch: %v", err)
+			}
+			patches = append(patches, req.Properties)
+		}
+		_, _ = w.Write([]byte(`{"object":"data_source","id":"` + dataSourceID + `","name":"Tasks","properties":{
+			"Name":{"id":"title","name":"Name","type":"title","title":{}},
+			"Stage":{"id":"st","name":"Stage","type":"select","select":{"options":[{"id":"o1","name":"Todo"},{"id":"o9","name":"Old"}]}},
+			"Legacy":{"id":"lg","name":"Legacy","type":"rich_text","rich_text":{}},
+			"Points":{"id":"pt","name":"Points","type":"number","number":{"format":"number"}}}}`))
+	}))
+	defer srv.Close()
+	t.Setenv(baseURLEnv, srv.URL)
+
+	file := filepath.Join(t.TempDir(), "schema.yaml")
+	if err := os.WriteFile(file, []byte(`data_source_id: `+dataSourceID+`
+name: Tasks
+properties:
+    - id: title
+      name: Name
+      type: title
+    - id: st
+      name: Stage
+      type: select
+      options:
+        - name: Todo
+        - name: Done
+          color: green
+    - id: pt
+      name: Points
+      type: number
+      number_format: dollar
+    - name: Owner
+      type: people
+    - name: Double
+      type: formula
+      formula: prop("Points") * 2
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out, errOut bytes.Buffer
+	root := newRootCmd(&globalOptions{profile: "default"})
+	root.SetArgs([]string{"ds", "schema", "apply", file})
+	root.SetOut(&out)
+	root.SetErr(&errOut)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("ds schema apply returned error: %v\n%s", err, errOut.String())
+	}
+
+	if len(patches) != 2 {
+		t.Fatalf("expected base and derived patches, got %d: %v", len(patches), patches)
+	}
+	base, _ := json.Marshal(patches[0])
+	for _, want := range []string{
+		`"Owner":{"people":{},"type":"people"}`,
+		`"st":{"select":{"options":[{"id":"o1"},{"id":"o9"},{"color":"green","name":"Done"}]}}`,
+	} {
+		if !strings.Contains(string(base), want) {
+			t.Fatalf("base patch missing %s: %s", want, base)
+		}
+	}
+	if strings.Contains(string(base), "Legacy") || strings.Contains(string(base), "dollar") {
+		t.Fatalf("base patch changes more than it adds: %s", base)
+	}
+	derived, _ := json.Marshal(patches[1])
+	if string(derived) != `{"Double":{"formula":{"expression":"prop(\"Points\") * 2"},"type":"formula"}}` {
+		t.Fatalf("derived patch = %s", derived)
+	}
+
+	for _, want := range []string{"added Double (formula)", "added Owner (people)", "added options Done to Stage"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	for _, want := range []string{
+		"warning: Legacy (rich_text) is not in the file; left in place",
+		"warning: Stage has options not in the file (Old); left in place",
+		"warning: Points number_format differs (number in Notion, dollar in the file); not changed",
+	} {
+		if !strings.Contains(errOut.String(), want) {
+			t.Fatalf("warnings missing %q:\n%s", want, errOut.String())
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+func TestDSSchemaDiffFiles(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	staging := filepath.Join(dir, "staging.yaml")
+	production := filepath.Join(dir, "production.json")
+	if err := os.WriteFile(staging, []byte(`data_source_id: ds-staging
+name: Tasks
+properties:
+    - id: title
+      name: Name
+      type: title
+    - id: s
+      name: Status
+      type: select
+      options:
+        - name: Todo
+        - name: Review
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(production, []byte(`{"data_source_id":"ds-prod","name":"Tasks","properties":[
+		{"id":"title","name":"Name","type":"title"},
+		{"id":"s","name":"Status","type":"select","options":[{"name":"Todo"}]},
+		{"id":"p","name":"Points","type":"number"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	root := newRootCmd(&globalOptions{profile: "default"})
+	root.SetArgs([]string{"ds", "schema-diff", production, staging, "--format", "json"})
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("ds schema-diff returned error: %v", err)
+	}
+	var changes []schema.Change
+	if err := json.Unmarshal(out.Bytes(), &changes); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, out.String())
+	}
+	if len(changes) != 2 ||
+		changes[0] != (schema.Change{Kind: schema.ChangeRemoved, Property: "Points", Old: "number"}) ||
+		changes[1] != (schema.Change{Kind: schema.ChangeChanged, Property: "Status", Field: "options", New: "Review"}) {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+
+	root = newRootCmd(&globalOptions{profile: "default"})
+	root.SetArgs([]string{"ds", "schema-diff", staging, filepath.Join(dir, "missing.yaml")})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	if err := root.Execute(); err == nil {
+		t.Fatal("expected an error for a missing file that is not a data source either")
+	}
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/yourorg/notionctl/internal/fuzzy"
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+// clipboardCommands are tried in order to copy text; the first one installed wins.
+var clipboardCommands = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"clip.exe"},
+}
+
+// copyToClipboard puts text on the system clipboard, falling back to the OSC 52 escape
+// sequence on tty (understood by most terminals, including over SSH) when no clipboard
+// command is installed.
+var copyToClipboard = func(ctx context.Context, tty io.Writer, text string) error {
+	for _, args := range clipboardCommands {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		copyCmd := exec.CommandContext(ctx, args[0], args[1:]...) // #nosec G204 -- fixed command list
+		copyCmd.Stdin = strings.NewReader(text)
+		return copyCmd.Run()
+	}
+	_, err := fmt.Fprintf(tty, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+	return err
+}
+
+// schemaExplorer browses a data source's properties on the terminal: pick a property, read
+// its full configuration, and copy its ID or name.
+type schemaExplorer struct {
+	schema notion.DataSourceSchema
+	in     *bufio.Reader
+	out    io.Writer
+	label  func(dataSourceID string) string
+}
+
+// explore runs the explorer on the terminal. Details are written to the terminal rather
+// than stdout so they never end up in a pipe or the pager.
+func explore(ctx context.Context, profile string, ds notion.DataSource) error {
+	full, err := ds.Schema()
+	if err != nil {
+		return err
+	}
+	tty, err := openPickTTY()
+	if err != nil {
+		return err
+	}
+	defer tty.Close() //nolint:errcheck // interactive use of the terminal
+	e := &schemaExplorer{
+		schema: full,
+		in:     bufio.NewReader(tty),
+		out:    tty,
+		label:  func(id string) string { return dataSourceLabel(profile, id) },
+	}
+	return e.run(ctx)
+}
+
+func (e *schemaExplorer) run(ctx context.Context) error {
+	for {
+		selected, err := findItem(ctx, preloadedItems(e.items()), fuzzy.FinderOptions{Prompt: e.schema.Name + "> "})
+		if errors.Is(err, fuzzy.ErrCancelled) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		prop, ok := e.property(selected.Value)
+		if !ok {
+			continue
+		}
+		writePropertyDetail(e.out, prop, e.label)
+		quit, err := e.act(ctx, prop)
+		if err != nil || quit {
+			return err
+		}
+	}
+}
+
+func (e *schemaExplorer) items() []fuzzy.Item {
+	items := make([]fuzzy.Item, 0, len(e.schema.Properties))
+	for _, prop := range e.schema.Properties {
+		items = append(items, fuzzy.Item{Label: prop.Name, Detail: prop.Type + " · " + prop.ID, Value: prop.ID})
+	}
+	return items
+}
+
+func (e *schemaExplorer) property(id string) (notion.PropertySchema, bool) {
+	for _, prop := range e.schema.Properties {
+		if prop.ID == id {
+			return prop, true
+		}
+	}
+	return notion.PropertySchema{}, false
+}
+
+// act asks what to do with the property shown, reporting true when the user wants to quit.
+func (e *schemaExplorer) act(ctx context.Context, prop notion.PropertySchema) (bool, error) {
+	for {
+		if _, err := fmt.Fprint(e.out, "\n[c]opy ID, copy [n]ame, [enter] back, [q]uit: "); err != nil {
+			return false, err
+		}
+		line, err := e.in.ReadString('\n')
+		if errors.Is(err, io.EOF) {
+			return true, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("read answer: %w", err)
+		}
+		var text string
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "":
+			return false, nil
+		case "q":
+			return true, nil
+		case "c":
+			text = prop.ID
+		case "n":
+			text = prop.Name
+		default:
+			continue
+		}
+		if err := copyToClipboard(ctx, e.out, text); err != nil {
+			safeLog(e.out, "copy failed: %v", err)
+			continue
+		}
+		safeLog(e.out, "Copied %s", text)
+	}
+}
+
+// writePropertyDetail prints a property's configuration; label names relation targets.
+func writePropertyDetail(w io.Writer, prop notion.PropertySchema, label func(string) string) {
+	var b strings.Builder
+	field := func(key, value string) { fmt.Fprintf(&b, "  %-12s %s\n", key+":", value) }
+	b.WriteString(prop.Name + "\n")
+	field("ID", prop.ID)
+	field("Type", prop.Type)
+	if prop.NumberFormat != "" {
+		field("Format", prop.NumberFormat)
+	}
+	if prop.Prefix != "" {
+		field("Prefix", prop.Prefix)
+	}
+	if prop.Formula != "" {
+		field("Expression", prop.Formula)
+	}
+	if rel := prop.Relation; rel != nil {
+		target := rel.DataSourceID
+		if name := label(rel.DataSourceID); name != "" {
+			target += " (" + name + ")"
+		}
+		field("Target", target)
+		if rel.SyncedPropertyName != "" {
+			field("Synced with", fmt.Sprintf("%s (%s)", rel.SyncedPropertyName, rel.SyncedPropertyID))
+		}
+	}
+	if rollup := prop.Rollup; rollup != nil {
+		field("Rollup", fmt.Sprintf("%s of %s via %s", rollup.Function, rollup.RollupProperty, rollup.RelationProperty))
+	}
+	writeOptionDetail(&b, prop)
+	_, _ = io.WriteString(w, b.String())
+}
+
+// writeOptionDetail lists options, under their status groups when the property has them.
+func writeOptionDetail(b *strings.Builder, prop notion.PropertySchema) {
+	if len(prop.Options) == 0 {
+		return
+	}
+	b.WriteString("  Options:\n")
+	if len(prop.Groups) == 0 {
+		for _, option := range prop.Options {
+			writeOptionLine(b, "    ", option)
+		}
+		return
+	}
+	byID := make(map[string]notion.SelectOption, len(prop.Options))
+	for _, option := range prop.Options {
+		byID[option.ID] = option
+	}
+	for _, group := range prop.Groups {
+		fmt.Fprintf(b, "    %s:\n", group.Name)
+		for _, id := range group.OptionIDs {
+			if option, ok := byID[id]; ok {
+				writeOptionLine(b, "      ", option)
+			}
+		}
+	}
+}
+
+func writeOptionLine(b *strings.Builder, indent string, option notion.SelectOption) {
+	fmt.Fprintf(b, "%s- %s", indent, option.Name)
+	if option.Color != "" {
+		fmt.Fprintf(b, " (%s)", option.Color)
+	}
+	if option.Description != "" {
+		fmt.Fprintf(b, ": %s", option.Description)
+	}
+	b.WriteByte('\n')
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/fuzzy"
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestSchemaExplorer(t *testing.T) {
+	origFind, origCopy := findItem, copyToClipboard
+	t.Cleanup(func() { findItem, copyToClipboard = origFind, origCopy })
+
+	picks := []string{"s%3D", "rel"}
+	findItem = func(_ context.Context, items <-chan fuzzy.Item, _ fuzzy.FinderOptions) (fuzzy.Item, error) {
+		if len(picks) == 0 {
+			return fuzzy.Item{}, fuzzy.ErrCancelled
+		}
+		want := picks[0]
+		picks = picks[1:]
+		for item := range items {
+			if item.Value == want {
+				return item, nil
+			}
+		}
+		t.Fatalf("%s not offered", want)
+		return fuzzy.Item{}, nil
+	}
+	var copied []string
+	copyToClipboard = func(_ context.Context, _ io.Writer, text string) error {
+		copied = append(copied, text)
+		return nil
+	}
+
+	var out strings.Builder
+	e := &schemaExplorer{
+		schema: notion.DataSourceSchema{Name: "Tasks", Properties: []notion.PropertySchema{
+			{ID: "rel", Name: "Project", Type: "relation", Relation: &notion.RelationSchema{
+				DataSourceID: "ds-2", SyncedPropertyName: "Tasks", SyncedPropertyID: "abc",
+			}},
+			{ID: "s%3D", Name: "Status", Type: "status",
+				Options: []notion.SelectOption{{ID: "o1", Name: "Todo", Color: "gray"}, {ID: "o2", Name: "Done"}},
+				Groups: []notion.StatusGroup{
+					{Name: "To-do", OptionIDs: []string{"o1"}},
+					{Name: "Complete", OptionIDs: []string{"o2"}},
+				},
+			},
+		}},
+		in:    bufio.NewReader(strings.NewReader("c\n\nn\nq\n")),
+		out:   &out,
+		label: func(id string) string { return map[string]string{"ds-2": "projects"}[id] },
+	}
+	if err := e.run(context.Background()); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+
+	if strings.Join(copied, ",") != "s%3D,Project" {
+		t.Fatalf("copied = %v", copied)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"Status\n  ID:          s%3D\n  Type:        status\n",
+		"  Options:\n    To-do:\n      - Todo (gray)\n    Complete:\n      - Done\n",
+		"Copied s%3D",
+		"  Target:      ds-2 (projects)\n  Synced with: Tasks (abc)\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("output missing %q:\n%s", want, got)
+		}
+	}
+	if len(picks) != 0 {
+		t.Fatalf("explorer stopped early, picks left: %v", picks)
+	}
+}
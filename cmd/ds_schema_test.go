@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestDSSchemaFormats(t *testing.T) {
+	ds := notion.DataSource{
+		ID:   "ds-1",
+		Name: "Tasks",
+		Properties: map[string]notion.PropertyReference{
+			"Due Date": {ID: "a%3Dbc", Name: "Due Date", Type: "date"},
+			"due-date": {ID: "xyz", Name: "due-date", Type: "date"},
+			"Name":     {ID: "title", Name: "Name", Type: "title"},
+		},
+	}
+
+	cases := map[string][]string{
+		formatEnv: {
+			`NOTION_PROP_DATA_SOURCE_ID="ds-1"`,
+			`NOTION_PROP_DUE_DATE="a%3Dbc"`,
+			`NOTION_PROP_DUE_DATE_2="xyz"`,
+			`NOTION_PROP_NAME="title"`,
+		},
+		formatTFVars: {
+			`notion_data_source_id = "ds-1"`,
+			`  "Due Date" = "a%3Dbc"`,
+		},
+		formatTS: {
+			`export const DATA_SOURCE_ID = "ds-1";`,
+			`  "Due Date": "a%3Dbc",`,
+			`} as const;`,
+		},
+	}
+	for format, want := range cases {
+		var out bytes.Buffer
+		opts := &dsSchemaOptions{format: format, envPrefix: defaultEnvPrefix}
+		if err := opts.write(context.Background(), &out, ds); err != nil {
+			t.Fatalf("write %s returned error: %v", format, err)
+		}
+		for _, line := range want {
+			if !strings.Contains(out.String(), line+"\n") {
+				t.Fatalf("%s output missing %q:\n%s", format, line, out.String())
+			}
+		}
+	}
+
+	if err := (&dsSchemaOptions{format: "xml"}).write(context.Background(), &bytes.Buffer{}, ds); err == nil {
+		t.Fatalf("expected error for unknown format")
+	}
+}
+
+func TestDSSchemaFullConfiguration(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NOTIONCTL_TOKEN", "secret_schema")
+	keyring.MockInit()
+
+	const dataSourceID = "1a2b3c4d-5e6f-7a8b-9c0d-1e2f3a4b5c6d"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data_sources/"+dataSourceID {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"object":"data_source","id":"` + dataSourceID + `","name":"Tasks","properties":{
+			"Name":{"id":"title","name":"Name","type":"title","title":{}},
+			"Status":{"id":"st","name":"Status","type":"status","status":{
+				"options":[{"id":"o1","name":"Todo"},{"id":"o2","name":"Doing"},{"id":"o3","name":"Done"}],
+				"groups":[{"name":"To-do","option_ids":["o1"]},{"name":"In progress","option_ids":["o2"]},
+					{"name":"Complete","option_ids":["o3"]}]}},
+			"Project":{"id":"rel","name":"Project","type":"relation","relation":{"data_source_id":"ds-2",
+				"type":"dual_property","dual_property":{"synced_property_name":"Tasks"}}},
+			"Score":{"id":"f","name":"Score","type":"formula","formula":{"expression":"prop(\"Points\") * 2"}},
+			"Points":{"id":"n","name":"Points","type":"number","number":{"format":"dollar"}}}}`))
+	}))
+	defer srv.Close()
+	t.Setenv(baseURLEnv, srv.URL)
+	if err := config.SaveDataSourceAlias("default", "tasks", dataSourceID, false); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		var out bytes.Buffer
+		root := newRootCmd(&globalOptions{profile: "default"})
+		root.SetArgs(append([]string{"ds", "schema"}, args...))
+		root.SetOut(&out)
+		root.SetErr(&bytes.Buffer{})
+		if err := root.Execute(); err != nil {
+			t.Fatalf("ds schema %v returned error: %v", args, err)
+		}
+		return out.String()
+	}
+
+	table := run("tasks")
+	for _, want := range []string{
+		"To-do: Todo; In progress: Doing; Complete: Done",
+		"→ ds-2, synced with Tasks",
+		`= prop("Points") * 2`,
+		"format: dollar",
+	} {
+		if !strings.Contains(table, want) {
+			t.Fatalf("table missing %q:\n%s", want, table)
+		}
+	}
+
+	yamlOut := run(dataSourceID, "--format", "yaml")
+	for _, want := range []string{
+		"data_source_id: " + dataSourceID + "\n",
+		"      number_format: dollar\n",
+		"      relation:\n        data_source_id: ds-2\n        type: dual_property\n        synced_property_name: Tasks\n",
+		"        - name: Complete\n          option_ids:\n            - o3\n",
+	} {
+		if !strings.Contains(yamlOut, want) {
+			t.Fatalf("yaml missing %q:\n%s", want, yamlOut)
+		}
+	}
+
+	if exported := run("export", "tasks"); exported != yamlOut {
+		t.Fatalf("export differs from --format yaml:\n%s", exported)
+	}
+
+	if jsonOut := run("--data-source-id", dataSourceID, "--format", "json"); !strings.Contains(jsonOut, `"formula": "prop(\"Points\") * 2"`) {
+		t.Fatalf("json missing formula:\n%s", jsonOut)
+	}
+
+	root := newRootCmd(&globalOptions{profile: "default"})
+	root.SetArgs([]string{"ds", "schema", "tasks", "--data-source-id", dataSourceID})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "not both") {
+		t.Fatalf("expected an error for both an argument and --data-source-id, got %v", err)
+	}
+}
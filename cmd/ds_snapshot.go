@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/filelock"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/render"
+)
+
+const snapshotFilePermissions = 0o600
+
+// dataSourceSnapshot is every page of a data source at one point in time, as written by
+// ds snapshot and read by ds diff.
+type dataSourceSnapshot struct {
+	TakenAt      time.Time     `json:"taken_at"`
+	DataSourceID string        `json:"data_source_id"`
+	Name         string        `json:"name,omitempty"`
+	Pages        []notion.Page `json:"pages"`
+
+	// dataSource is the schema fetched with a live snapshot; it is not saved.
+	dataSource notion.DataSource
+}
+
+// snapshotDiff reports what changed between two snapshots, keyed by page ID.
+type snapshotDiff struct {
+	Added   []pageSummary `json:"added"`
+	Removed []pageSummary `json:"removed"`
+	Changed []pageChange  `json:"changed"`
+}
+
+type pageSummary struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	URL   string `json:"url,omitempty"`
+}
+
+type pageChange struct {
+	pageSummary
+	Properties []propertyChange `json:"properties"`
+}
+
+type propertyChange struct {
+	Name string `json:"name"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+type dsSnapshotOptions struct {
+	dataSourceID string
+	outPath      string
+}
+
+type dsDiffOptions struct {
+	format      string
+	againstLive bool
+}
+
+func newDSSnapshotCmd(globals *globalOptions) *cobra.Command {
+	opts := &dsSnapshotOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save every page of a data source to a JSON file for later ds diff",
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Target Notion data source ID or URL")
+	cmd.Flags().StringVar(&opts.outPath, "out", "", "Write the snapshot to this file instead of stdout")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
+
+	return cmd
+}
+
+func (opts *dsSnapshotOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		client, err := buildClient(globals)
+		if err != nil {
+			return err
+		}
+		snap, err := takeSnapshot(cmd.Context(), client, opts.dataSourceID, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(snap, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode snapshot: %w", err)
+		}
+		data = append(data, '\n')
+		if opts.outPath == "" {
+			if _, err := cmd.OutOrStdout().Write(data); err != nil {
+				return fmt.Errorf("write snapshot: %w", err)
+			}
+			return nil
+		}
+		if err := filelock.WriteFile(opts.outPath, data, snapshotFilePermissions); err != nil {
+			return fmt.Errorf("write snapshot: %w", err)
+		}
+		safeLog(cmd.ErrOrStderr(), "saved %d pages to %s", len(snap.Pages), opts.outPath)
+		return nil
+	}
+}
+
+type snapshotClient interface {
+	dataSourceQuerier
+	GetDataSource(ctx context.Context, dataSourceID string) (notion.DataSource, error)
+}
+
+func takeSnapshot(ctx context.Context, client snapshotClient, dataSourceID string, now time.Time) (dataSourceSnapshot, error) {
+	ds, err := client.GetDataSource(ctx, dataSourceID)
+	if err != nil {
+		return dataSourceSnapshot{}, fmt.Errorf("get data source: %w", err)
+	}
+	resp, err := executeDataSourceQuery(ctx, client, dataSourceID, notion.QueryDataSourceRequest{}, true, 0)
+	if err != nil {
+		return dataSourceSnapshot{}, err
+	}
+	pages := resp.Results
+	slices.SortFunc(pages, func(a, b notion.Page) int { return cmp.Compare(a.ID, b.ID) })
+	return dataSourceSnapshot{TakenAt: now, DataSourceID: dataSourceID, Name: ds.Name, Pages: pages, dataSource: ds}, nil
+}
+
+func newDSDiffCmd(globals *globalOptions) *cobra.Command {
+	opts := &dsDiffOptions{format: formatTable}
+
+	cmd := &cobra.Command{
+		Use:   "diff <old.json> [new.json]",
+		Short: "Report pages added, removed, or changed between two snapshots (or a snapshot and now)",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().BoolVar(
+		&opts.againstLive,
+		"against-live",
+		false,
+		"Compare the snapshot with the data source's current pages instead of a second file",
+	)
+	formatFlag(cmd, &opts.format, "Output format", formatJSON, formatTable)
+
+	return cmd
+}
+
+func (opts *dsDiffOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if opts.againstLive == (len(args) == 2) {
+			return errors.New("pass two snapshot files, or one with --against-live")
+		}
+		if opts.format != formatJSON && opts.format != formatTable {
+			return fmt.Errorf("unknown format %q (expected json or table)", opts.format)
+		}
+		old, err := loadSnapshot(args[0])
+		if err != nil {
+			return err
+		}
+		var current dataSourceSnapshot
+		if opts.againstLive {
+			client, err := buildClient(globals)
+			if err != nil {
+				return err
+			}
+			if current, err = takeSnapshot(cmd.Context(), client, old.DataSourceID, time.Now().UTC()); err != nil {
+				return err
+			}
+		} else if current, err = loadSnapshot(args[1]); err != nil {
+			return err
+		}
+		if old.DataSourceID != current.DataSourceID {
+			safeLog(cmd.ErrOrStderr(), "warning: comparing snapshots of different data sources (%s and %s)",
+				old.DataSourceID, current.DataSourceID)
+		}
+
+		diff := diffSnapshots(old, current)
+		if opts.format == formatJSON {
+			return writeJSON(cmd.Context(), cmd.OutOrStdout(), diff)
+		}
+		return render.Table(cmd.OutOrStdout(), []string{"Change", "Page", "Property", "Old", "New"}, diffRows(diff))
+	}
+}
+
+func loadSnapshot(path string) (dataSourceSnapshot, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- reading a user-supplied snapshot is intentional
+	if err != nil {
+		return dataSourceSnapshot{}, fmt.Errorf("read snapshot: %w", err)
+	}
+	var snap dataSourceSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return dataSourceSnapshot{}, fmt.Errorf("decode snapshot %s: %w", path, err)
+	}
+	if snap.DataSourceID == "" {
+		return dataSourceSnapshot{}, fmt.Errorf("%s is not a ds snapshot file", path)
+	}
+	return snap, nil
+}
+
+// diffSnapshots compares pages by ID. Property values are compared as ds query shows them,
+// so a snapshot reloaded from disk compares equal to the live page it was taken from.
+func diffSnapshots(old, current dataSourceSnapshot) snapshotDiff {
+	diff := snapshotDiff{Added: []pageSummary{}, Removed: []pageSummary{}, Changed: []pageChange{}}
+	before := make(map[string]notion.Page, len(old.Pages))
+	for _, page := range old.Pages {
+		before[page.ID] = page
+	}
+	seen := make(map[string]bool, len(current.Pages))
+	for _, page := range current.Pages {
+		seen[page.ID] = true
+		prev, ok := before[page.ID]
+		if !ok {
+			diff.Added = append(diff.Added, summarizePage(page))
+			continue
+		}
+		if changes := diffProperties(prev, page); len(changes) > 0 {
+			diff.Changed = append(diff.Changed, pageChange{pageSummary: summarizePage(page), Properties: changes})
+		}
+	}
+	for _, page := range old.Pages {
+		if !seen[page.ID] {
+			diff.Removed = append(diff.Removed, summarizePage(page))
+		}
+	}
+	bySummary := func(a, b pageSummary) int { return cmp.Compare(a.ID, b.ID) }
+	slices.SortFunc(diff.Added, bySummary)
+	slices.SortFunc(diff.Removed, bySummary)
+	slices.SortFunc(diff.Changed, func(a, b pageChange) int { return bySummary(a.pageSummary, b.pageSummary) })
+	return diff
+}
+
+func diffProperties(old, current notion.Page) []propertyChange {
+	names := render.SortedKeys(old.Properties)
+	for _, name := range render.SortedKeys(current.Properties) {
+		if _, ok := old.Properties[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var changes []propertyChange
+	for _, name := range names {
+		before, hadBefore := old.Properties[name]
+		after, hasAfter := current.Properties[name]
+		oldValue, newValue := "", ""
+		if hadBefore {
+			oldValue = snapshotValue(before)
+		}
+		if hasAfter {
+			newValue = snapshotValue(after)
+		}
+		if oldValue != newValue {
+			changes = append(changes, propertyChange{Name: name, Old: oldValue, New: newValue})
+		}
+	}
+	return changes
+}
+
+// snapshotValue summarizes types ds query knows and falls back to the decoded fields for the
+// rest, since the raw JSON live pages carry is not kept in snapshot files.
+func snapshotValue(val notion.PropertyValue) string {
+	if _, ok := propertySummaryByType[val.Type]; ok {
+		return summarizeProperty(val)
+	}
+	val.ID = ""
+	data, err := json.Marshal(val)
+	if err != nil {
+		return val.Type
+	}
+	return string(data)
+}
+
+func summarizePage(page notion.Page) pageSummary {
+	return pageSummary{ID: page.ID, Title: pageTitle(page), URL: page.URL}
+}
+
+func diffRows(diff snapshotDiff) [][]string {
+	label := func(page pageSummary) string {
+		if page.Title == "" {
+			return page.ID
+		}
+		return page.Title + " (" + page.ID + ")"
+	}
+	var rows [][]string
+	for _, page := range diff.Added {
+		rows = append(rows, []string{"added", label(page), "", "", ""})
+	}
+	for _, page := range diff.Removed {
+		rows = append(rows, []string{"removed", label(page), "", "", ""})
+	}
+	for _, page := range diff.Changed {
+		for _, change := range page.Properties {
+			rows = append(rows, []string{"changed", label(page.pageSummary), change.Name, change.Old, change.New})
+		}
+	}
+	return rows
+}
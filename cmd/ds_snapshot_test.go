@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func snapshotPage(id, title, status string) notion.Page {
+	return notion.Page{
+		ID: id,
+		Properties: map[string]notion.PropertyValue{
+			"Name":   {Type: "title", Title: []notion.RichText{{PlainText: title}}},
+			"Status": {Type: "status", Status: &notion.StatusValue{Name: status}},
+		},
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	old := dataSourceSnapshot{DataSourceID: "ds-1", Pages: []notion.Page{
+		snapshotPage("p1", "Kept", "Todo"),
+		snapshotPage("p2", "Edited", "Todo"),
+		snapshotPage("p3", "Gone", "Done"),
+	}}
+	edited := snapshotPage("p2", "Edited", "Done")
+	edited.Properties["Estimate"] = notion.PropertyValue{Type: "number", Number: new(float64)}
+	current := dataSourceSnapshot{DataSourceID: "ds-1", Pages: []notion.Page{
+		snapshotPage("p4", "New", "Todo"),
+		snapshotPage("p1", "Kept", "Todo"),
+		edited,
+	}}
+
+	diff := diffSnapshots(old, current)
+	if len(diff.Added) != 1 || diff.Added[0].ID != "p4" || diff.Added[0].Title != "New" {
+		t.Fatalf("unexpected added pages: %#v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].ID != "p3" {
+		t.Fatalf("unexpected removed pages: %#v", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].ID != "p2" {
+		t.Fatalf("unexpected changed pages: %#v", diff.Changed)
+	}
+	want := []propertyChange{
+		{Name: "Estimate", Old: "", New: "0"},
+		{Name: "Status", Old: "Todo", New: "Done"},
+	}
+	got := diff.Changed[0].Properties
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("unexpected property changes: %#v", got)
+	}
+
+	rows := diffRows(diff)
+	if len(rows) != 4 || rows[0][0] != "added" || rows[1][0] != "removed" || rows[3][2] != "Status" {
+		t.Fatalf("unexpected table rows: %#v", rows)
+	}
+}
+
+func TestSnapshotRoundTripHasNoChanges(t *testing.T) {
+	var live notion.Page
+	raw := `{"id":"p1","properties":{` +
+		`"Name":{"id":"title","type":"title","title":[{"plain_text":"Row"}]},` +
+		`"Score":{"id":"f","type":"formula","formula":{"type":"number","number":3}}}}`
+	if err := json.Unmarshal([]byte(raw), &live); err != nil {
+		t.Fatalf("decode page: %v", err)
+	}
+	snap := dataSourceSnapshot{DataSourceID: "ds-1", Pages: []notion.Page{live}}
+
+	path := filepath.Join(t.TempDir(), "snap.json")
+	data, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatalf("encode snapshot: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write snapshot: %v", err)
+	}
+	loaded, err := loadSnapshot(path)
+	if err != nil {
+		t.Fatalf("loadSnapshot returned error: %v", err)
+	}
+
+	diff := diffSnapshots(loaded, snap)
+	if len(diff.Added)+len(diff.Removed)+len(diff.Changed) != 0 {
+		t.Fatalf("expected no differences after a round trip, got %#v", diff)
+	}
+}
+
+func TestLoadSnapshotRejectsOtherJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pages.json")
+	if err := os.WriteFile(path, []byte(`[{"id":"p1"}]`), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if _, err := loadSnapshot(path); err == nil {
+		t.Fatalf("expected an error for a file that is not a snapshot")
+	}
+}
@@ -0,0 +1,385 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/notionid"
+	"github.com/yourorg/notionctl/internal/render"
+	"github.com/yourorg/notionctl/internal/schema"
+	"github.com/yourorg/notionctl/internal/sqlquery"
+	"github.com/yourorg/notionctl/internal/where"
+)
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type dsSQLOptions struct {
+	format string
+	exec   executionOptions
+}
+
+// sqlClient is the subset of the Notion client used to answer a SQL query.
+type sqlClient interface {
+	dataSourceQuerier
+	GetDataSource(ctx context.Context, dataSourceID string) (notion.DataSource, error)
+}
+
+// sqlPageColumns are the page fields a query can read besides its properties.
+var sqlPageColumns = map[string]bool{"id": true, "url": true, "created_time": true, "last_edited_time": true}
+
+// sqlResult holds the rows a query selected and the column names to show for them.
+type sqlResult struct {
+	columns []string
+	rows    []notion.Page
+}
+
+func newDSSQLCmd(globals *globalOptions) *cobra.Command {
+	opts := &dsSQLOptions{format: formatTable, exec: defaultExecutionOptions()}
+
+	cmd := &cobra.Command{
+		Use:   "sql <query>",
+		Short: "Query a data source with a SQL SELECT statement",
+		Long: `Query a data source with a SQL SELECT statement such as
+
+  SELECT Name, Status FROM @tasks WHERE Status = "Done" ORDER BY Due DESC LIMIT 20
+
+FROM takes a data source alias prefixed with @, or an ID or URL. WHERE supports
+=, !=, <, <=, >, >=, LIKE, IN, IS [NOT] NULL, AND, OR, NOT, and parentheses.
+Conditions Notion can evaluate are sent as the query filter; the rest are checked
+against each returned row. ORDER BY always runs in Notion.`,
+		Args: cobra.ExactArgs(1),
+		RunE: opts.run(globals),
+	}
+
+	formatFlag(cmd, &opts.format, "Output format", formatJSON, formatTable)
+	opts.exec.register(cmd, "")
+
+	return cmd
+}
+
+func (opts *dsSQLOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if opts.format != formatJSON && opts.format != formatTable {
+			return fmt.Errorf("unknown format %q (expected json or table)", opts.format)
+		}
+		query, err := sqlquery.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("parse query: %w", err)
+		}
+		dataSourceID, err := resolveSQLSource(globals.profile, query.From)
+		if err != nil {
+			return err
+		}
+		client, err := opts.exec.buildClient(globals)
+		if err != nil {
+			return err
+		}
+
+		result, err := runSQLQuery(cmd.Context(), client, dataSourceID, query)
+		if err != nil {
+			return err
+		}
+		if opts.format == formatJSON {
+			return writeJSON(cmd.Context(), cmd.OutOrStdout(), result.objects())
+		}
+		return render.Table(cmd.OutOrStdout(), result.columns, result.table())
+	}
+}
+
+// resolveSQLSource reads FROM: "@alias" is a configured alias, anything else an ID or URL.
+func resolveSQLSource(profile, from string) (string, error) {
+	if alias, ok := strings.CutPrefix(from, "@"); ok {
+		if alias == "" {
+			return "", errors.New("FROM @ needs an alias name")
+		}
+		return resolveDataSourceAlias(profile, alias)
+	}
+	id, err := notionid.Parse(from)
+	if err != nil {
+		return "", fmt.Errorf("FROM %q is neither an @alias nor a data source ID: %w", from, err)
+	}
+	return id, nil
+}
+
+// runSQLQuery sends the parts of the query Notion understands and filters the returned rows
+// with the rest. LIMIT stops the scan early, since Notion already returns rows in order.
+func runSQLQuery(ctx context.Context, client sqlClient, dataSourceID string, query *sqlquery.Query) (sqlResult, error) {
+	ds, err := client.GetDataSource(ctx, dataSourceID)
+	if err != nil {
+		return sqlResult{}, fmt.Errorf("get data source: %w", err)
+	}
+	idx := schema.NewIndex(ds)
+
+	names := map[string]string{}
+	for _, column := range query.ReferencedColumns() {
+		if sqlPageColumns[strings.ToLower(column)] {
+			names[column] = strings.ToLower(column)
+			continue
+		}
+		ref, ok := idx.ReferenceForName(column)
+		if !ok {
+			return sqlResult{}, fmt.Errorf("unknown column %q", column)
+		}
+		names[column] = ref.Name
+	}
+
+	result := sqlResult{columns: idx.PropertyNames()}
+	if len(query.Columns) > 0 {
+		result.columns = make([]string, 0, len(query.Columns))
+		for _, column := range query.Columns {
+			result.columns = append(result.columns, names[column])
+		}
+	}
+
+	sorts, err := sqlSorts(query.OrderBy, idx)
+	if err != nil {
+		return sqlResult{}, err
+	}
+	filter, residual := pushdownSQL(query.Where, idx)
+	req := notion.QueryDataSourceRequest{Sorts: sorts}
+	if filter != nil {
+		req.Filter = filter
+	}
+
+	for page, err := range streamDataSourceQuery(ctx, client, dataSourceID, req, 0) {
+		if err != nil {
+			return sqlResult{}, err
+		}
+		if residual != nil && !residual.Eval(sqlRow(page, names)) {
+			continue
+		}
+		result.rows = append(result.rows, page)
+		if query.Limit > 0 && len(result.rows) >= query.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func sqlSorts(order []sqlquery.Order, idx *schema.Index) ([]any, error) {
+	sorts := make([]any, 0, len(order))
+	for _, item := range order {
+		direction := "ascending"
+		if item.Desc {
+			direction = "descending"
+		}
+		switch name := strings.ToLower(item.Column); name {
+		case "created_time", "last_edited_time":
+			sorts = append(sorts, map[string]any{"timestamp": name, "direction": direction})
+			continue
+		case "id", "url":
+			return nil, fmt.Errorf("cannot ORDER BY %s", item.Column)
+		}
+		id, _ := idx.IDForName(item.Column)
+		sorts = append(sorts, map[string]any{"property": id, "direction": direction})
+	}
+	return sorts, nil
+}
+
+// pushdownSQL splits a WHERE condition into a Notion filter and the residual condition
+// that has to be checked client-side. AND pushes each side it can; OR and IN are pushed
+// only when every branch is, and NOT never is.
+func pushdownSQL(expr sqlquery.Expr, idx *schema.Index) (map[string]any, sqlquery.Expr) {
+	switch e := expr.(type) {
+	case nil:
+		return nil, nil
+	case sqlquery.And:
+		leftFilter, leftRest := pushdownSQL(e.Left, idx)
+		rightFilter, rightRest := pushdownSQL(e.Right, idx)
+		var rest sqlquery.Expr
+		switch {
+		case leftRest != nil && rightRest != nil:
+			rest = sqlquery.And{Left: leftRest, Right: rightRest}
+		case leftRest != nil:
+			rest = leftRest
+		default:
+			rest = rightRest
+		}
+		return combineFilters("and", leftFilter, rightFilter), rest
+	case sqlquery.Or:
+		leftFilter, leftRest := pushdownSQL(e.Left, idx)
+		rightFilter, rightRest := pushdownSQL(e.Right, idx)
+		if leftRest != nil || rightRest != nil {
+			return nil, e
+		}
+		return combineFilters("or", leftFilter, rightFilter), nil
+	}
+	if filter := sqlCondition(expr, idx); filter != nil {
+		return filter, nil
+	}
+	return nil, expr
+}
+
+// sqlCondition translates a single predicate, or returns nil when Notion cannot express it.
+func sqlCondition(expr sqlquery.Expr, idx *schema.Index) map[string]any {
+	switch e := expr.(type) {
+	case sqlquery.Comparison:
+		return sqlPropertyCondition(idx, e.Column, e.Op, e.Value)
+	case sqlquery.IsNull:
+		if e.Negate {
+			return sqlPropertyCondition(idx, e.Column, "is_not_empty", "")
+		}
+		return sqlPropertyCondition(idx, e.Column, "is_empty", "")
+	case sqlquery.Like:
+		op, text, ok := e.Simple()
+		switch {
+		case !ok:
+			return nil
+		case e.Negate && op != "contains":
+			return nil
+		case e.Negate:
+			op = "does_not_contain"
+		}
+		return sqlPropertyCondition(idx, e.Column, op, text)
+	case sqlquery.In:
+		if e.Negate {
+			return nil
+		}
+		var filter map[string]any
+		for _, value := range e.Values {
+			condition := sqlPropertyCondition(idx, e.Column, "=", value)
+			if condition == nil {
+				return nil
+			}
+			filter = combineFilters("or", filter, condition)
+		}
+		return filter
+	}
+	return nil
+}
+
+func sqlPropertyCondition(idx *schema.Index, column, op, value string) map[string]any {
+	ref, ok := idx.ReferenceForName(column)
+	// People filters take user IDs, while rows are matched by name client-side.
+	if !ok || ref.Type == "people" {
+		return nil
+	}
+	condition, err := where.Condition(ref, op, value)
+	if err != nil {
+		return nil
+	}
+	return condition
+}
+
+// combineFilters joins two filters with "and" or "or", flattening nested chains of the
+// same kind. Either filter may be nil.
+func combineFilters(kind string, left, right map[string]any) map[string]any {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	var items []any
+	for _, filter := range []map[string]any{left, right} {
+		if nested, ok := filter[kind].([]any); ok && len(filter) == 1 {
+			items = append(items, nested...)
+			continue
+		}
+		items = append(items, filter)
+	}
+	return map[string]any{kind: items}
+}
+
+// sqlRow exposes a page to client-side evaluation. names maps query columns to property
+// names or page fields.
+func sqlRow(page notion.Page, names map[string]string) sqlquery.Row {
+	return func(column string) []string {
+		return sqlValues(page, names[column])
+	}
+}
+
+// sqlValues lists a column's values: one per option, person, or related page for list
+// properties, and the start of a date range.
+func sqlValues(page notion.Page, name string) []string {
+	switch name {
+	case "id":
+		return []string{page.ID}
+	case "url":
+		return nonEmpty(page.URL)
+	case "created_time":
+		return []string{page.CreatedTime.Format(time.RFC3339)}
+	case "last_edited_time":
+		return []string{page.LastEditedTime.Format(time.RFC3339)}
+	}
+
+	value, ok := page.Properties[name]
+	if !ok {
+		return nil
+	}
+	switch value.Type {
+	case "multi_select":
+		var values []string
+		for _, option := range value.MultiSelect {
+			values = append(values, option.Name)
+		}
+		return values
+	case "people":
+		var values []string
+		for _, person := range value.People {
+			// Match either the name or the ID of each person.
+			values = append(values, nonEmpty(person.Name)...)
+			values = append(values, person.ID)
+		}
+		return values
+	case relationType:
+		var values []string
+		for _, rel := range value.Relation {
+			values = append(values, rel.ID)
+		}
+		return values
+	case "date":
+		if value.Date == nil {
+			return nil
+		}
+		return nonEmpty(value.Date.Start)
+	}
+	if text := summarizeProperty(value); text != value.Type {
+		return nonEmpty(text)
+	}
+	return nil
+}
+
+func nonEmpty(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return []string{text}
+}
+
+func (r sqlResult) table() [][]string {
+	rows := make([][]string, 0, len(r.rows))
+	for _, page := range r.rows {
+		row := make([]string, 0, len(r.columns))
+		for _, column := range r.columns {
+			if sqlPageColumns[column] {
+				row = append(row, strings.Join(sqlValues(page, column), ""))
+				continue
+			}
+			row = append(row, summarizePageProperty(page, column))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (r sqlResult) objects() []map[string]any {
+	objects := make([]map[string]any, 0, len(r.rows))
+	for _, page := range r.rows {
+		object := make(map[string]any, len(r.columns))
+		for _, column := range r.columns {
+			if sqlPageColumns[column] {
+				object[column] = strings.Join(sqlValues(page, column), "")
+				continue
+			}
+			object[column] = frontmatterValue(page.Properties[column])
+		}
+		objects = append(objects, object)
+	}
+	return objects
+}
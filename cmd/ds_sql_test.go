@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+	"github.com/yourorg/notionctl/internal/sqlquery"
+)
+
+// fakeSQLClient returns every page for any query and records the requests it received.
+type fakeSQLClient struct {
+	pages    []notion.Page
+	requests []notion.QueryDataSourceRequest
+}
+
+func (f *fakeSQLClient) GetDataSource(context.Context, string) (notion.DataSource, error) {
+	return notion.DataSource{Properties: map[string]notion.PropertyReference{
+		"Name":   {ID: "title", Name: "Name", Type: "title"},
+		"Status": {ID: "st", Name: "Status", Type: "status"},
+		"Due":    {ID: "due", Name: "Due", Type: "date"},
+		"Owner":  {ID: "own", Name: "Owner", Type: "people"},
+	}}, nil
+}
+
+func (f *fakeSQLClient) QueryDataSource(
+	_ context.Context,
+	_ string,
+	req notion.QueryDataSourceRequest,
+) (notion.QueryDataSourceResponse, error) {
+	f.requests = append(f.requests, req)
+	return notion.QueryDataSourceResponse{Results: f.pages}, nil
+}
+
+func sqlTaskPage(id, name, owner string) notion.Page {
+	return notion.Page{ID: id, Properties: map[string]notion.PropertyValue{
+		"Name":   {Type: "title", Title: []notion.RichText{{PlainText: name}}},
+		"Status": {Type: "status", Status: &notion.StatusValue{Name: "Done"}},
+		"Owner":  {Type: "people", People: []notion.UserReference{{ID: "u-" + owner, Name: owner}}},
+	}}
+}
+
+func TestRunSQLQueryPushesDownAndFiltersTheRest(t *testing.T) {
+	client := &fakeSQLClient{pages: []notion.Page{
+		sqlTaskPage("p2", "Fix bug", "Grace"),
+		sqlTaskPage("p3", "Fix typo", "Ada"),
+		sqlTaskPage("p4", "Fix build", "Ada"),
+	}}
+	query, err := sqlquery.Parse(
+		`SELECT Name, id FROM @tasks WHERE status = "Done" AND Name LIKE 'fix%' AND Owner = Ada ORDER BY Due DESC LIMIT 1`,
+	)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	result, err := runSQLQuery(context.Background(), client, "ds", query)
+	if err != nil {
+		t.Fatalf("runSQLQuery returned error: %v", err)
+	}
+	if !reflect.DeepEqual(result.columns, []string{"Name", "id"}) {
+		t.Fatalf("columns = %v", result.columns)
+	}
+	if got := result.objects(); len(got) != 1 || got[0]["Name"] != "Fix typo" || got[0]["id"] != "p3" {
+		t.Fatalf("rows = %v, want only Fix typo", got)
+	}
+
+	filter, err := json.Marshal(client.requests[0].Filter)
+	if err != nil {
+		t.Fatalf("marshal filter: %v", err)
+	}
+	want := `{"and":[{"property":"st","status":{"equals":"Done"}},{"property":"title","title":{"starts_with":"fix"}}]}`
+	if string(filter) != want {
+		t.Fatalf("filter = %s, want %s", filter, want)
+	}
+	sorts := client.requests[0].Sorts
+	if len(sorts) != 1 || sorts[0].(map[string]any)["property"] != "due" {
+		t.Fatalf("sorts = %v", sorts)
+	}
+}
+
+func TestPushdownSQLKeepsMixedOrClientSide(t *testing.T) {
+	client := &fakeSQLClient{}
+	ds, err := client.GetDataSource(context.Background(), "ds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	query, err := sqlquery.Parse(`SELECT * FROM x WHERE Status IN (Done, Open) AND (Name = a OR Owner = Ada)`)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	filter, rest := pushdownSQL(query.Where, schema.NewIndex(ds))
+	encoded, err := json.Marshal(filter)
+	if err != nil {
+		t.Fatalf("marshal filter: %v", err)
+	}
+	want := `{"or":[{"property":"st","status":{"equals":"Done"}},{"property":"st","status":{"equals":"Open"}}]}`
+	if string(encoded) != want {
+		t.Fatalf("filter = %s, want %s", encoded, want)
+	}
+	if _, ok := rest.(sqlquery.Or); !ok {
+		t.Fatalf("residual = %#v, want the OR", rest)
+	}
+}
+
+func TestRunSQLQueryRejectsUnknownColumns(t *testing.T) {
+	query, err := sqlquery.Parse(`SELECT Name FROM x WHERE Bogus = 1`)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if _, err := runSQLQuery(context.Background(), &fakeSQLClient{}, "ds", query); err == nil {
+		t.Fatal("expected an unknown column error")
+	}
+}
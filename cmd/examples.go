@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/notionid"
+	"github.com/yourorg/notionctl/internal/render"
+)
+
+const placeholderDataSource = "<data-source-id>"
+
+type examplesOptions struct {
+	dataSource string
+	refresh    bool
+}
+
+// exampleContext holds what examples are filled in with: the data source and, when its schema
+// is cached, the first property name of each type.
+type exampleContext struct {
+	dataSourceID string
+	dataSource   string
+	name         string
+	byType       map[string]string
+	now          time.Time
+}
+
+// example is one runnable invocation; build reports false when the data source lacks the
+// properties the example needs.
+type example struct {
+	command string
+	summary string
+	build   func(c exampleContext) (string, bool)
+}
+
+func newExamplesCmd(globals *globalOptions) *cobra.Command {
+	opts := &examplesOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "examples [command]",
+		Short: "Print runnable examples filled in with your aliases and property names",
+		Long: "Print example invocations for a command (or all commands) using the profile's aliases, " +
+			"default data source, and property names from the cached schema. Schemas are cached by " +
+			"ds schema and by examples --refresh.",
+		RunE: opts.run(globals),
+	}
+
+	cmd.Flags().StringVar(
+		&opts.dataSource,
+		"data-source",
+		"",
+		"Data source ID, URL, or alias (default: the profile's default data source)",
+	)
+	cmd.Flags().BoolVar(&opts.refresh, "refresh", false, "Fetch the data source schema from Notion and cache it")
+
+	return cmd
+}
+
+func (opts *examplesOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		selected, err := selectExamples(strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+		c, err := opts.context(cmd, globals)
+		if err != nil {
+			return err
+		}
+		return writeExamples(cmd.OutOrStdout(), c, selected)
+	}
+}
+
+// context resolves the data source and loads (or, with --refresh, fetches) its schema.
+func (opts *examplesOptions) context(cmd *cobra.Command, globals *globalOptions) (exampleContext, error) {
+	profile := globals.profile
+	c := exampleContext{dataSourceID: placeholderDataSource, dataSource: placeholderDataSource, now: time.Now()}
+	settings, err := config.LoadDataSourceSettings(profile)
+	if err != nil {
+		return c, fmt.Errorf("load data source aliases: %w", err)
+	}
+	raw, ok := settings.ResolveDataSource(opts.dataSource)
+	if !ok {
+		safeLog(cmd.ErrOrStderr(), "note: no default data source; pass --data-source or run "+
+			"notionctl ds alias set <name> <data-source-id> --default to fill in your own")
+		return c, nil
+	}
+	id, err := notionid.Parse(raw)
+	if err != nil {
+		return c, fmt.Errorf("resolve data source: %w", err)
+	}
+	c.dataSourceID, c.dataSource = id, id
+	if alias := aliasFor(settings, id, opts.dataSource); alias != "" {
+		c.dataSource = alias
+	}
+
+	var ds notion.DataSource
+	cached := false
+	if opts.refresh {
+		client, err := buildClient(globals)
+		if err != nil {
+			return c, err
+		}
+		if ds, err = client.GetDataSource(cmd.Context(), id); err != nil {
+			return c, fmt.Errorf("get data source: %w", err)
+		}
+		if err := config.SaveSchema(profile, ds); err != nil {
+			return c, err
+		}
+		cached = true
+	} else if ds, cached, err = config.LoadSchema(profile, id); err != nil {
+		return c, err
+	}
+	if !cached {
+		safeLog(cmd.ErrOrStderr(), "note: no cached schema for %s; rerun with --refresh to use its property names", id)
+		return c, nil
+	}
+
+	c.name = ds.Name
+	c.byType = map[string]string{}
+	for _, name := range render.SortedKeys(ds.Properties) {
+		kind := ds.Properties[name].Type
+		if _, taken := c.byType[kind]; !taken {
+			c.byType[kind] = name
+		}
+	}
+	return c, nil
+}
+
+// aliasFor prefers the alias the user named, then the default alias, then any alias for id.
+func aliasFor(settings config.DataSourceSettings, id, requested string) string {
+	for _, candidate := range []string{strings.ToLower(strings.TrimSpace(requested)), settings.Default} {
+		if candidate != "" && sameID(settings.Aliases[candidate], id) {
+			return candidate
+		}
+	}
+	for _, alias := range render.SortedKeys(settings.Aliases) {
+		if sameID(settings.Aliases[alias], id) {
+			return alias
+		}
+	}
+	return ""
+}
+
+func sameID(raw, id string) bool {
+	parsed, err := notionid.Parse(raw)
+	return err == nil && parsed == id
+}
+
+// prop returns the first property of any of the given types.
+func (c exampleContext) prop(types ...string) (string, bool) {
+	for _, kind := range types {
+		if name, ok := c.byType[kind]; ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// whereName quotes a property name for a --where expression when it is not a bare word.
+func whereName(name string) string {
+	for _, r := range name {
+		if !isBareRune(r) {
+			return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(name) + `"`
+		}
+	}
+	return name
+}
+
+// shellQuote single-quotes value unless it is safe to pass to a POSIX shell as is.
+func shellQuote(value string) string {
+	unsafe := func(r rune) bool { return !isBareRune(r) && !strings.ContainsRune(",:@/", r) }
+	if value != "" && !strings.ContainsFunc(value, unsafe) {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func isBareRune(r rune) bool {
+	return r == '_' || r == '-' || r == '.' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
+
+func fixed(command string) func(exampleContext) (string, bool) {
+	return func(c exampleContext) (string, bool) {
+		return strings.NewReplacer("{ds}", c.dataSourceID, "{alias}", c.dataSource).Replace(command), true
+	}
+}
+
+func withProp(types []string, build func(c exampleContext, name string) string) func(exampleContext) (string, bool) {
+	return func(c exampleContext) (string, bool) {
+		name, ok := c.prop(types...)
+		if !ok {
+			return "", false
+		}
+		return build(c, name), true
+	}
+}
+
+var examples = []example{
+	{command: "ds schema", summary: "List property names, IDs, and types (and cache the schema)",
+		build: fixed("notionctl ds schema --data-source-id {ds}")},
+	{command: "ds query", summary: "Show the 20 most recently edited rows as a table",
+		build: fixed(`notionctl ds query --data-source-id {ds} --sort "last_edited_time:desc" --limit 20 --format table`)},
+	{command: "ds query", summary: "Filter on a status or select property",
+		build: withProp([]string{"status", "select"}, func(c exampleContext, name string) string {
+			return fmt.Sprintf("notionctl ds query --data-source-id %s --where %s --format table",
+				c.dataSourceID, shellQuote(whereName(name)+" is not empty"))
+		})},
+	{command: "ds query", summary: "Rows due from today, soonest first",
+		build: withProp([]string{"date"}, func(c exampleContext, name string) string {
+			return fmt.Sprintf("notionctl ds query --data-source-id %s --where %s --sort %s --format table",
+				c.dataSourceID, shellQuote(whereName(name)+" on_or_after @today"), shellQuote(name+":asc"))
+		})},
+	{command: "ds query", summary: "Unchecked rows only",
+		build: withProp([]string{"checkbox"}, func(c exampleContext, name string) string {
+			return fmt.Sprintf("notionctl ds query --data-source-id %s --where %s --format table",
+				c.dataSourceID, shellQuote(whereName(name)+" = false"))
+		})},
+	{command: "ds query", summary: "Search titles and text for a word",
+		build: fixed("notionctl ds query --data-source-id {ds} --grep 'release' --format table")},
+	{command: "ds query", summary: "Count rows per group",
+		build: withProp([]string{"status", "select"}, func(c exampleContext, name string) string {
+			cmd := fmt.Sprintf("notionctl ds query --data-source-id %s --group-by %s --count", c.dataSourceID, shellQuote(name))
+			if number, ok := c.prop("number"); ok {
+				cmd += " --sum " + shellQuote(number)
+			}
+			return cmd
+		})},
+	{command: "ds query", summary: "Inline related pages",
+		build: withProp([]string{relationType}, func(c exampleContext, name string) string {
+			return fmt.Sprintf("notionctl ds query --data-source-id %s --expand %s --limit 10", c.dataSourceID, shellQuote(name))
+		})},
+	{command: "ds export", summary: "Export every row to CSV",
+		build: fixed("notionctl ds export --data-source-id {ds} --format csv --out export.csv")},
+	{command: "changes", summary: "Pages edited in the last 7 days",
+		build: func(c exampleContext) (string, bool) {
+			since := c.now.UTC().Add(-7 * 24 * time.Hour).Truncate(time.Hour).Format(time.RFC3339)
+			return fmt.Sprintf("notionctl changes --data-source-id %s --since %s --format table", c.dataSourceID, since), true
+		}},
+	{command: "pages get", summary: "Show one page with its properties",
+		build: fixed("notionctl pages get <page-id|url> --data-source {alias} --format table")},
+	{command: "pages update", summary: "Link a related page, choosing it interactively",
+		build: withProp([]string{relationType}, func(c exampleContext, name string) string {
+			return "notionctl pages update <page-id|url> --add-relation " + shellQuote(name+"=?")
+		})},
+	{command: "pick pages", summary: "Fuzzy-find a page and show it",
+		build: fixed(`notionctl pages get "$(notionctl pick pages --data-source {alias})" --format table`)},
+	{command: "sync watch", summary: "Print a JSON line whenever pages change",
+		build: fixed("notionctl sync watch --data-source-id {ds} --no-webhook --poll-interval 1m --suppress-empty")},
+	{command: "sync mirror", summary: "Keep a local SQLite copy up to date",
+		build: fixed("notionctl sync mirror --data-source-id {ds} --db notion.sqlite --interval 10m")},
+	{command: "sync export-md", summary: "Export pages as Markdown notes",
+		build: fixed("notionctl sync export-md --data-source-id {ds} --dir ./notes")},
+}
+
+// selectExamples returns the examples for command and its subcommands, or all of them.
+func selectExamples(command string) ([]example, error) {
+	command = strings.Join(strings.Fields(strings.TrimPrefix(strings.TrimSpace(command), "notionctl")), " ")
+	var selected []example
+	for _, ex := range examples {
+		if command == "" || ex.command == command || strings.HasPrefix(ex.command, command+" ") {
+			selected = append(selected, ex)
+		}
+	}
+	if len(selected) == 0 {
+		var commands []string
+		for _, ex := range examples {
+			if len(commands) == 0 || commands[len(commands)-1] != ex.command {
+				commands = append(commands, ex.command)
+			}
+		}
+		return nil, fmt.Errorf("no examples for %q (try one of: %s)", command, strings.Join(commands, ", "))
+	}
+	return selected, nil
+}
+
+func writeExamples(w io.Writer, c exampleContext, selected []example) error {
+	var b strings.Builder
+	if c.name != "" {
+		fmt.Fprintf(&b, "# Examples for %s (%s)\n", c.name, c.dataSourceID)
+	}
+	current := ""
+	written := 0
+	for _, ex := range selected {
+		line, ok := ex.build(c)
+		if !ok {
+			continue
+		}
+		if ex.command != current {
+			if b.Len() > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "## %s\n", ex.command)
+			current = ex.command
+		}
+		fmt.Fprintf(&b, "# %s\n%s\n", ex.summary, line)
+		written++
+	}
+	if written == 0 {
+		return errors.New("the data source has none of the property types these examples need")
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("write examples: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/config"
+)
+
+func TestWriteExamplesUsesPropertyNames(t *testing.T) {
+	c := exampleContext{
+		dataSourceID: "ds-1",
+		dataSource:   "tasks",
+		name:         "Tasks",
+		byType:       map[string]string{"date": "Due date", "status": "Stage", "relation": "Blocked by"},
+		now:          time.Date(2025, 3, 10, 12, 30, 0, 0, time.UTC),
+	}
+	selected, err := selectExamples("ds query")
+	if err != nil {
+		t.Fatalf("selectExamples: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := writeExamples(&out, c, selected); err != nil {
+		t.Fatalf("writeExamples: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"# Examples for Tasks (ds-1)",
+		`--where '"Due date" on_or_after @today' --sort 'Due date:asc'`,
+		"--where 'Stage is not empty'",
+		"--group-by Stage --count\n",
+		"--expand 'Blocked by'",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Unchecked") || strings.Contains(got, "## pages") {
+		t.Fatalf("expected only ds query examples backed by existing properties:\n%s", got)
+	}
+}
+
+func TestSelectExamples(t *testing.T) {
+	selected, err := selectExamples("notionctl sync")
+	if err != nil {
+		t.Fatalf("selectExamples: %v", err)
+	}
+	for _, ex := range selected {
+		if !strings.HasPrefix(ex.command, "sync ") {
+			t.Fatalf("unexpected example %q for sync", ex.command)
+		}
+	}
+	if len(selected) != 3 {
+		t.Fatalf("expected 3 sync examples, got %d", len(selected))
+	}
+	if _, err := selectExamples("ds quer"); err == nil || !strings.Contains(err.Error(), "ds query") {
+		t.Fatalf("expected an error listing commands, got %v", err)
+	}
+}
+
+func TestExampleQuoting(t *testing.T) {
+	cases := map[string]string{
+		"Status":        "Status",
+		"Due date:asc":  "'Due date:asc'",
+		"it's":          `'it'\''s'`,
+		"Done = false":  "'Done = false'",
+		"@today,2025/1": "@today,2025/1",
+	}
+	for in, want := range cases {
+		if got := shellQuote(in); got != want {
+			t.Fatalf("shellQuote(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := whereName(`Say "hi"`); got != `"Say \"hi\""` {
+		t.Fatalf("whereName = %q", got)
+	}
+}
+
+func TestAliasFor(t *testing.T) {
+	const id = "12345678-90ab-cdef-1234-567890abcdef"
+	settings := config.DataSourceSettings{
+		Aliases: map[string]string{"b": "1234567890abcdef1234567890abcdef", "a": id, "other": "ffffffffffffffffffffffffffffffff"},
+		Default: "b",
+	}
+	if got := aliasFor(settings, id, "A"); got != "a" {
+		t.Fatalf("expected the requested alias, got %q", got)
+	}
+	if got := aliasFor(settings, id, id); got != "b" {
+		t.Fatalf("expected the default alias, got %q", got)
+	}
+	settings.Default = ""
+	if got := aliasFor(settings, id, ""); got != "a" {
+		t.Fatalf("expected the first matching alias, got %q", got)
+	}
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+const (
+	defaultConcurrency = 3
+	defaultBatchSize   = 50
+)
+
+// executionOptions controls how commands that issue many requests pace themselves. Every
+// multi-request command shares it so --concurrency, --batch-size, and --requests-per-second
+// mean the same thing everywhere.
+type executionOptions struct {
+	concurrencyFlag   *pflag.Flag
+	requestsPerSecond float64
+	concurrency       int
+	batchSize         int
+}
+
+func defaultExecutionOptions() executionOptions {
+	return executionOptions{
+		concurrency: defaultConcurrency,
+		batchSize:   defaultBatchSize,
+	}
+}
+
+// register adds the execution flags to cmd. batchUsage describes what a batch is for the
+// command; an empty string leaves --batch-size out.
+func (e *executionOptions) register(cmd *cobra.Command, batchUsage string) {
+	flags := cmd.Flags()
+	flags.IntVar(
+		&e.concurrency,
+		"concurrency",
+		e.concurrency,
+		"Maximum requests in flight at once (default: the profile's concurrency limit, else the request rate, at least 3)",
+	)
+	// The default is resolved in buildClient; keep help from advertising a fixed value.
+	e.concurrencyFlag = flags.Lookup("concurrency")
+	e.concurrencyFlag.DefValue = "0"
+	if batchUsage != "" {
+		flags.IntVar(&e.batchSize, "batch-size", e.batchSize, batchUsage)
+	}
+	flags.Float64Var(
+		&e.requestsPerSecond,
+		"requests-per-second",
+		e.requestsPerSecond,
+		"Sustained request rate shared by all workers (default: --rps)",
+	)
+}
+
+func (e executionOptions) validate() error {
+	switch {
+	case e.concurrency <= 0:
+		return errors.New("--concurrency must be positive")
+	case e.batchSize <= 0:
+		return errors.New("--batch-size must be positive")
+	case e.requestsPerSecond < 0:
+		return errors.New("--requests-per-second must be positive")
+	}
+	return nil
+}
+
+// buildClient builds the profile's client, replacing its request rate when
+// --requests-per-second is given, and sizes the worker pool when --concurrency is not.
+func (e *executionOptions) buildClient(globals *globalOptions) (*notion.Client, error) {
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+	client, err := buildClient(globals)
+	if err != nil {
+		return nil, err
+	}
+	if e.requestsPerSecond > 0 {
+		// A clone, so the new rate stays with this command even when the client is shared,
+		// as it is across the lines of a shell session.
+		client = client.Clone()
+		client.WithLimiter(notion.NewRateLimiter(e.requestsPerSecond))
+	}
+	if e.concurrencyFlag != nil && !e.concurrencyFlag.Changed {
+		if e.concurrency, err = autoConcurrency(globals.profile, client); err != nil {
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+// forEach calls fn for indexes 0..n-1 with at most e.concurrency calls running at once. It
+// returns the first error; remaining calls see a cancelled context.
+func (e executionOptions) forEach(ctx context.Context, n int, fn func(ctx context.Context, i int) error) error {
+	g, groupCtx := errgroup.WithContext(ctx)
+	g.SetLimit(max(1, e.concurrency))
+	for i := range n {
+		g.Go(func() error { return fn(groupCtx, i) })
+	}
+	return g.Wait()
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/zalando/go-keyring"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestExecutionForEachBoundsConcurrency(t *testing.T) {
+	exec := executionOptions{concurrency: 2, batchSize: 1, requestsPerSecond: 1}
+	var running, peak, calls atomic.Int32
+	err := exec.forEach(context.Background(), 10, func(context.Context, int) error {
+		calls.Add(1)
+		n := running.Add(1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		running.Add(-1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("forEach returned error: %v", err)
+	}
+	if calls.Load() != 10 || peak.Load() > 2 {
+		t.Fatalf("calls=%d peak=%d", calls.Load(), peak.Load())
+	}
+
+	for _, bad := range []executionOptions{
+		{concurrency: 0, batchSize: 1, requestsPerSecond: 1},
+		{concurrency: 1, batchSize: 0, requestsPerSecond: 1},
+		{concurrency: 1, batchSize: 1, requestsPerSecond: -1},
+	} {
+		if err := bad.validate(); err == nil {
+			t.Fatalf("expected validation error for %+v", bad)
+		}
+	}
+}
+
+func TestExecutionConcurrencyFollowsRateUnlessSet(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(baseURLEnv, "")
+	keyring.MockInit()
+	if err := config.SaveToken("work", "secret_work", ""); err != nil {
+		t.Fatalf("SaveToken returned error: %v", err)
+	}
+
+	resolve := func(args ...string) int {
+		t.Helper()
+		cmd := &cobra.Command{Use: "test"}
+		exec := defaultExecutionOptions()
+		exec.register(cmd, "")
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatalf("parse flags: %v", err)
+		}
+		if _, err := exec.buildClient(&globalOptions{profile: "work"}); err != nil {
+			t.Fatalf("buildClient returned error: %v", err)
+		}
+		return exec.concurrency
+	}
+
+	if got := resolve(); got != defaultConcurrency {
+		t.Fatalf("default concurrency = %d, want %d", got, defaultConcurrency)
+	}
+	if got := resolve("--requests-per-second", "9.5"); got != 10 {
+		t.Fatalf("concurrency at 9.5 rps = %d, want 10", got)
+	}
+	if got := resolve("--requests-per-second", "9.5", "--concurrency", "2"); got != 2 {
+		t.Fatalf("explicit --concurrency = %d, want 2", got)
+	}
+	if err := config.SetLimit("work", config.LimitConcurrency, "6"); err != nil {
+		t.Fatalf("SetLimit returned error: %v", err)
+	}
+	if got := resolve("--requests-per-second", "20"); got != 6 {
+		t.Fatalf("profile concurrency = %d, want 6", got)
+	}
+}
+
+func TestExecutionBuildClientKeepsRateOffSharedClient(t *testing.T) {
+	shared := notion.NewClient(notion.ClientConfig{Token: "test"})
+	saved := clientFactory
+	clientFactory = func(*globalOptions) (*notion.Client, error) { return shared, nil }
+	t.Cleanup(func() { clientFactory = saved })
+
+	exec := executionOptions{concurrency: 1, batchSize: 1, requestsPerSecond: 50}
+	client, err := exec.buildClient(&globalOptions{profile: "default"})
+	if err != nil {
+		t.Fatalf("buildClient returned error: %v", err)
+	}
+	if client.RequestsPerSecond() != 50 {
+		t.Fatalf("expected the command's client to allow 50 requests per second, got %v", client.RequestsPerSecond())
+	}
+	if got := shared.RequestsPerSecond(); got != notion.DefaultRequestsPerSecond {
+		t.Fatalf("--requests-per-second leaked into the shared client: %v", got)
+	}
+}
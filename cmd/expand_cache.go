@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/expand"
+)
+
+// startExpansionCache gives the command one expansion cache, so related pages are fetched
+// once per run across every --expand property and query page.
+func startExpansionCache(cmd *cobra.Command) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cmd.SetContext(expand.WithCache(ctx, expand.NewCache()))
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/yourorg/notionctl/internal/notionid"
+)
+
+// idValue is a flag value that accepts a Notion ID or URL and stores the normalized ID, so
+// every command sees the same canonical form regardless of how the user copied it.
+type idValue struct {
+	target *string
+}
+
+func newIDValue(target *string) *idValue {
+	return &idValue{target: target}
+}
+
+func (v *idValue) String() string {
+	if v.target == nil {
+		return ""
+	}
+	return *v.target
+}
+
+func (v *idValue) Set(raw string) error {
+	id, err := notionid.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("parse Notion ID: %w", err)
+	}
+	*v.target = id
+	return nil
+}
+
+func (v *idValue) Type() string {
+	return "id|url"
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/render"
+)
+
+// optionalInt is an int flag that remembers whether it was given, so an explicit 0 can be
+// told apart from the default.
+type optionalInt struct {
+	value int
+	set   bool
+}
+
+func (o *optionalInt) String() string {
+	if !o.set {
+		return ""
+	}
+	return strconv.Itoa(o.value)
+}
+
+func (o *optionalInt) Set(raw string) error {
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return fmt.Errorf("parse integer: %w", err)
+	}
+	o.value, o.set = n, true
+	return nil
+}
+
+func (o *optionalInt) Type() string {
+	return "int"
+}
+
+// resolveLimits returns the profile's limits with the global --max-retries, --rps, and
+// --backoff-base flags applied on top.
+func resolveLimits(profile string, globals *globalOptions) (config.Limits, error) {
+	limits, err := config.LoadLimits(profile)
+	if err != nil {
+		return config.Limits{}, fmt.Errorf("load limits: %w", err)
+	}
+	switch {
+	case globals.maxRetries.set && globals.maxRetries.value < 0:
+		return config.Limits{}, errors.New("--max-retries must not be negative")
+	case globals.rps < 0:
+		return config.Limits{}, errors.New("--rps must be positive")
+	case globals.backoffBase < 0:
+		return config.Limits{}, errors.New("--backoff-base must be positive")
+	}
+	if globals.maxRetries.set {
+		limits.MaxRetries = &globals.maxRetries.value
+	}
+	if globals.rps > 0 {
+		limits.RPS = globals.rps
+	}
+	if globals.backoffBase > 0 {
+		limits.BackoffBase = globals.backoffBase
+	}
+	return limits, nil
+}
+
+// autoConcurrency sizes a worker pool when --concurrency is not given: the profile's
+// concurrency limit if set, else enough workers to keep the client's rate limiter busy, and
+// never fewer than defaultConcurrency.
+func autoConcurrency(profile string, client *notion.Client) (int, error) {
+	limits, err := config.LoadLimits(profile)
+	if err != nil {
+		return 0, fmt.Errorf("load limits: %w", err)
+	}
+	if limits.Concurrency > 0 {
+		return limits.Concurrency, nil
+	}
+	return max(defaultConcurrency, int(math.Ceil(client.RequestsPerSecond()))), nil
+}
+
+// clientMaxRetries maps a configured retry count onto notion.ClientConfig, where zero means
+// the default and a negative value disables retries.
+func clientMaxRetries(retries *int) int {
+	switch {
+	case retries == nil:
+		return 0
+	case *retries == 0:
+		return -1
+	default:
+		return *retries
+	}
+}
+
+func newAuthLimitsCmd(globals *globalOptions) *cobra.Command {
+	format := formatTable
+
+	cmd := &cobra.Command{
+		Use:   "limits",
+		Short: "Show the request rate, retry, and circuit breaker limits of the active profile",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			limits, err := config.LoadLimits(globals.profile)
+			if err != nil {
+				return fmt.Errorf("load limits: %w", err)
+			}
+			switch format {
+			case formatJSON:
+				return writeJSON(cmd.Context(), cmd.OutOrStdout(), limits)
+			case formatTable:
+				rows := [][]string{
+					{config.LimitBackoffBase, "(default) 500ms"},
+					{config.LimitBreakerThreshold, "(default) " + strconv.Itoa(notion.DefaultBreakerThreshold)},
+					{config.LimitConcurrency, "(default) from rps, at least " + strconv.Itoa(defaultConcurrency)},
+					{config.LimitMaxRetries, "(default) 5"},
+					{config.LimitRPS, "(default) 3"},
+				}
+				if limits.BackoffBase > 0 {
+					rows[0][1] = limits.BackoffBase.String()
+				}
+				if limits.BreakerThreshold > 0 {
+					rows[1][1] = strconv.Itoa(limits.BreakerThreshold)
+				}
+				if limits.Concurrency > 0 {
+					rows[2][1] = strconv.Itoa(limits.Concurrency)
+				}
+				if limits.MaxRetries != nil {
+					rows[3][1] = strconv.Itoa(*limits.MaxRetries)
+				}
+				if limits.RPS > 0 {
+					rows[4][1] = strconv.FormatFloat(limits.RPS, 'g', -1, 64)
+				}
+				return render.Table(cmd.OutOrStdout(), []string{"Setting", "Value"}, rows)
+			default:
+				return fmt.Errorf("unknown format %q (expected json or table)", format)
+			}
+		},
+	}
+
+	formatFlag(cmd, &format, "Output format", formatJSON, formatTable)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set <rps|max_retries|backoff_base|breaker_threshold|concurrency> <value>",
+		Short: "Set a request limit for the active profile",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := config.SetLimit(globals.profile, args[0], args[1]); err != nil {
+				return fmt.Errorf("set limit: %w", err)
+			}
+			safeLog(cmd.ErrOrStderr(), "Profile %q now uses %s %s", globals.profile, args[0], args[1])
+			return nil
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "unset <rps|max_retries|backoff_base|breaker_threshold|concurrency>",
+		Short: "Restore the default for a request limit of the active profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if err := config.UnsetLimit(globals.profile, args[0]); err != nil {
+				return fmt.Errorf("unset limit: %w", err)
+			}
+			return nil
+		},
+	})
+
+	return cmd
+}
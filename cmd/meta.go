@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/render"
+)
+
+type runMetaKey struct{}
+
+// runMeta collects what --with-meta reports about one command invocation.
+type runMeta struct { //nolint:govet // fieldalignment: grouped by purpose
+	started time.Time
+	stats   *notion.Stats
+	command string
+	profile string
+	args    []string
+	flags   map[string]string
+
+	mu    sync.Mutex
+	query *metaQuery
+	next  string
+	more  bool
+}
+
+type metaQuery struct {
+	DataSourceID string                        `json:"data_source_id"`
+	Request      notion.QueryDataSourceRequest `json:"request"`
+}
+
+// outputMeta is the "meta" half of the --with-meta envelope.
+type outputMeta struct { //nolint:govet // fieldalignment: mirrors the JSON layout
+	Command       string            `json:"command"`
+	Args          []string          `json:"args,omitempty"`
+	Flags         map[string]string `json:"flags,omitempty"`
+	Profile       string            `json:"profile"`
+	Query         *metaQuery        `json:"query,omitempty"`
+	NextCursor    string            `json:"next_cursor,omitempty"`
+	HasMore       bool              `json:"has_more"`
+	StartedAt     time.Time         `json:"started_at"`
+	DurationMS    int64             `json:"duration_ms"`
+	Requests      int               `json:"requests"`
+	Retries       int               `json:"retries"`
+	NotionVersion string            `json:"notion_version,omitempty"`
+}
+
+type metaEnvelope struct {
+	Data any        `json:"data"`
+	Meta outputMeta `json:"meta"`
+}
+
+// startRunMeta attaches a runMeta to cmd's context so request counts and the query echo are
+// collected while the command runs.
+func startRunMeta(cmd *cobra.Command, args []string, profile string) {
+	meta := &runMeta{
+		started: time.Now().UTC(),
+		stats:   &notion.Stats{},
+		command: cmd.CommandPath(),
+		profile: profile,
+		args:    args,
+	}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if f.Name == "with-meta" {
+			return
+		}
+		if meta.flags == nil {
+			meta.flags = map[string]string{}
+		}
+		meta.flags[f.Name] = f.Value.String()
+	})
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = context.WithValue(ctx, runMetaKey{}, meta)
+	cmd.SetContext(notion.WithStats(ctx, meta.stats))
+}
+
+func runMetaFromContext(ctx context.Context) *runMeta {
+	if ctx == nil {
+		return nil
+	}
+	meta, _ := ctx.Value(runMetaKey{}).(*runMeta)
+	return meta
+}
+
+// recordQuery keeps the first data source query of the run and the cursor it ended on.
+func recordQuery(
+	ctx context.Context,
+	dataSourceID string,
+	req notion.QueryDataSourceRequest,
+	resp notion.QueryDataSourceResponse,
+) {
+	meta := runMetaFromContext(ctx)
+	if meta == nil {
+		return
+	}
+	meta.mu.Lock()
+	defer meta.mu.Unlock()
+	if meta.query != nil {
+		return
+	}
+	meta.query = &metaQuery{DataSourceID: dataSourceID, Request: req}
+	meta.next, meta.more = resp.NextCursor, resp.HasMore
+}
+
+func (m *runMeta) snapshot() outputMeta {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return outputMeta{
+		Command:       m.command,
+		Args:          m.args,
+		Flags:         m.flags,
+		Profile:       m.profile,
+		Query:         m.query,
+		NextCursor:    m.next,
+		HasMore:       m.more,
+		StartedAt:     m.started,
+		DurationMS:    time.Since(m.started).Milliseconds(),
+		Requests:      m.stats.Requests(),
+		Retries:       m.stats.Retries(),
+		NotionVersion: m.stats.NotionVersion(),
+	}
+}
+
+// writeJSON renders v as JSON, wrapped as {"data": v, "meta": {...}} when the run was
+// started with --with-meta.
+func writeJSON(ctx context.Context, w io.Writer, v any) error {
+	meta := runMetaFromContext(ctx)
+	if meta == nil {
+		return render.JSON(w, v)
+	}
+	return render.JSON(w, metaEnvelope{Data: v, Meta: meta.snapshot()})
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestWriteJSONWithoutMetaIsPlain(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeJSON(context.Background(), &out, map[string]int{"count": 2}); err != nil {
+		t.Fatalf("writeJSON: %v", err)
+	}
+	if got := out.String(); got != "{\n  \"count\": 2\n}\n" {
+		t.Fatalf("unexpected output %q", got)
+	}
+}
+
+func TestWriteJSONWithMetaWrapsOutput(t *testing.T) {
+	var limit int
+	cmd := &cobra.Command{Use: "query"}
+	cmd.Flags().IntVar(&limit, "limit", 0, "")
+	cmd.Flags().Bool("with-meta", false, "")
+	if err := cmd.ParseFlags([]string{"--limit", "5", "--with-meta"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	cmd.SetContext(context.Background())
+	startRunMeta(cmd, []string{"extra"}, "work")
+
+	ctx := cmd.Context()
+	req := notion.QueryDataSourceRequest{Filter: map[string]any{"property": "Done"}}
+	resp, err := executeDataSourceQuery(ctx, &pagedQuerier{total: 12}, "ds-1", req, false, 5)
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	// Later queries in the same run do not replace the echo.
+	recordQuery(ctx, "ds-2", notion.QueryDataSourceRequest{}, notion.QueryDataSourceResponse{})
+
+	var out bytes.Buffer
+	if err := writeJSON(ctx, &out, resp); err != nil {
+		t.Fatalf("writeJSON: %v", err)
+	}
+	var envelope struct {
+		Data notion.QueryDataSourceResponse `json:"data"`
+		Meta outputMeta                     `json:"meta"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode envelope: %v\n%s", err, out.String())
+	}
+
+	meta := envelope.Meta
+	if len(envelope.Data.Results) != 5 || meta.Command != "query" || meta.Profile != "work" {
+		t.Fatalf("unexpected envelope: %s", out.String())
+	}
+	if len(meta.Args) != 1 || meta.Flags["limit"] != "5" || len(meta.Flags) != 1 {
+		t.Fatalf("expected args and changed flags (without --with-meta), got %v %v", meta.Args, meta.Flags)
+	}
+	if meta.Query == nil || meta.Query.DataSourceID != "ds-1" || meta.Query.Request.Filter == nil {
+		t.Fatalf("expected the first query to be echoed, got %+v", meta.Query)
+	}
+	if meta.NextCursor != "5" || !meta.HasMore {
+		t.Fatalf("expected cursor 5 with has_more, got %q %v", meta.NextCursor, meta.HasMore)
+	}
+	if meta.StartedAt.IsZero() || meta.DurationMS < 0 {
+		t.Fatalf("expected timing, got %+v", meta)
+	}
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/notionid"
+)
+
+const uniqueIDType = "unique_id"
+
+var uniqueIDHandlePattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_]*)-(\d+)$`)
+
+// pageHandleClient is the subset of the Notion client needed to resolve unique ID handles.
+type pageHandleClient interface {
+	GetDataSource(ctx context.Context, dataSourceID string) (notion.DataSource, error)
+	QueryDataSource(
+		ctx context.Context,
+		dataSourceID string,
+		req notion.QueryDataSourceRequest,
+	) (notion.QueryDataSourceResponse, error)
+}
+
+type uniqueIDHandle struct {
+	prefix string
+	number int
+}
+
+// parseUniqueIDHandle recognises handles such as TASK-123.
+func parseUniqueIDHandle(ref string) (uniqueIDHandle, bool) {
+	match := uniqueIDHandlePattern.FindStringSubmatch(strings.TrimSpace(ref))
+	if match == nil {
+		return uniqueIDHandle{}, false
+	}
+	number, err := strconv.Atoi(match[2])
+	if err != nil {
+		return uniqueIDHandle{}, false
+	}
+	return uniqueIDHandle{prefix: match[1], number: number}, true
+}
+
+func (h uniqueIDHandle) String() string {
+	return fmt.Sprintf("%s-%d", h.prefix, h.number)
+}
+
+// resolvePageRef turns a page argument into a page ID. IDs and Notion URLs are normalized;
+// unique ID handles are looked up in the named (or default) data source.
+func resolvePageRef(
+	ctx context.Context,
+	client pageHandleClient,
+	profile string,
+	ref string,
+	dataSource string,
+) (string, error) {
+	return resolveRef(ctx, client, profile, ref, dataSource, notionid.Parse)
+}
+
+// resolveBlockRef is resolvePageRef for arguments that may name a block, so a link's block
+// anchor wins over the page it is in.
+func resolveBlockRef(
+	ctx context.Context,
+	client pageHandleClient,
+	profile string,
+	ref string,
+	dataSource string,
+) (string, error) {
+	return resolveRef(ctx, client, profile, ref, dataSource, notionid.ParseBlock)
+}
+
+func resolveRef(
+	ctx context.Context,
+	client pageHandleClient,
+	profile string,
+	ref string,
+	dataSource string,
+	parse func(string) (string, error),
+) (string, error) {
+	handle, ok := parseUniqueIDHandle(ref)
+	if !ok {
+		id, err := parse(ref)
+		if err != nil {
+			return "", fmt.Errorf("parse page reference: %w", err)
+		}
+		return id, nil
+	}
+
+	dataSourceID, err := resolveDataSourceAlias(profile, dataSource)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", handle, err)
+	}
+	return resolveUniqueIDHandle(ctx, client, dataSourceID, handle)
+}
+
+// resolveDataSourceAlias turns an alias, ID, or URL into a data source ID. An empty name uses
+// the profile's default data source.
+func resolveDataSourceAlias(profile, name string) (string, error) {
+	settings, err := config.LoadDataSourceSettings(profile)
+	if err != nil {
+		return "", fmt.Errorf("load data source aliases: %w", err)
+	}
+	dataSourceID, ok := settings.ResolveDataSource(name)
+	if !ok {
+		return "", errors.New("pass --data-source or configure a default data source")
+	}
+	return notionid.Parse(dataSourceID)
+}
+
+func resolveUniqueIDHandle(
+	ctx context.Context,
+	client pageHandleClient,
+	dataSourceID string,
+	handle uniqueIDHandle,
+) (string, error) {
+	ds, err := client.GetDataSource(ctx, dataSourceID)
+	if err != nil {
+		return "", fmt.Errorf("get data source: %w", err)
+	}
+	prop, err := uniqueIDProperty(ds)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.QueryDataSource(ctx, dataSourceID, notion.QueryDataSourceRequest{
+		Filter: map[string]any{
+			"property":   prop.ID,
+			uniqueIDType: map[string]any{"equals": handle.number},
+		},
+		FilterProperties: []string{prop.ID},
+	})
+	if err != nil {
+		return "", fmt.Errorf("query data source: %w", err)
+	}
+
+	for _, page := range resp.Results {
+		value, ok := page.Properties[prop.Name]
+		if !ok || value.UniqueID == nil {
+			continue
+		}
+		if strings.EqualFold(value.UniqueID.Prefix, handle.prefix) && value.UniqueID.Number == handle.number {
+			return page.ID, nil
+		}
+	}
+	return "", fmt.Errorf("no page with unique ID %s in data source %s", handle, dataSourceID)
+}
+
+func uniqueIDProperty(ds notion.DataSource) (notion.PropertyReference, error) {
+	names := make([]string, 0, len(ds.Properties))
+	for name, ref := range ds.Properties {
+		if ref.Type == uniqueIDType {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return notion.PropertyReference{}, errors.New("data source has no unique_id property")
+	}
+	sort.Strings(names)
+	ref := ds.Properties[names[0]]
+	if ref.Name == "" {
+		ref.Name = names[0]
+	}
+	return ref, nil
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+type stubHandleClient struct {
+	ds      notion.DataSource
+	results []notion.Page
+	lastReq notion.QueryDataSourceRequest
+}
+
+func (s *stubHandleClient) GetDataSource(context.Context, string) (notion.DataSource, error) {
+	return s.ds, nil
+}
+
+func (s *stubHandleClient) QueryDataSource(
+	_ context.Context,
+	_ string,
+	req notion.QueryDataSourceRequest,
+) (notion.QueryDataSourceResponse, error) {
+	s.lastReq = req
+	return notion.QueryDataSourceResponse{Results: s.results}, nil
+}
+
+func TestParseUniqueIDHandle(t *testing.T) {
+	handle, ok := parseUniqueIDHandle("TASK-123")
+	if !ok || handle.prefix != "TASK" || handle.number != 123 {
+		t.Fatalf("unexpected handle: %#v ok=%v", handle, ok)
+	}
+	for _, ref := range []string{"abcd1234-ef56-7890-ab12-34567890cdef", "abcd1234ef567890ab1234567890cdef", "123"} {
+		if _, ok := parseUniqueIDHandle(ref); ok {
+			t.Fatalf("expected %q not to parse as a handle", ref)
+		}
+	}
+}
+
+func TestResolveUniqueIDHandle(t *testing.T) {
+	client := &stubHandleClient{
+		ds: notion.DataSource{Properties: map[string]notion.PropertyReference{
+			"ID":   {ID: "uid", Name: "ID", Type: "unique_id"},
+			"Name": {ID: "title", Name: "Name", Type: "title"},
+		}},
+		results: []notion.Page{
+			{ID: "page-other", Properties: map[string]notion.PropertyValue{
+				"ID": {Type: "unique_id", UniqueID: &notion.UniqueIDValue{Prefix: "BUG", Number: 7}},
+			}},
+			{ID: "page-7", Properties: map[string]notion.PropertyValue{
+				"ID": {Type: "unique_id", UniqueID: &notion.UniqueIDValue{Prefix: "TASK", Number: 7}},
+			}},
+		},
+	}
+
+	id, err := resolveUniqueIDHandle(context.Background(), client, "ds-1", uniqueIDHandle{prefix: "task", number: 7})
+	if err != nil {
+		t.Fatalf("resolveUniqueIDHandle returned error: %v", err)
+	}
+	if id != "page-7" {
+		t.Fatalf("resolved id = %q, want page-7", id)
+	}
+
+	filter, ok := client.lastReq.Filter.(map[string]any)
+	if !ok || filter["property"] != "uid" {
+		t.Fatalf("unexpected filter: %#v", client.lastReq.Filter)
+	}
+
+	if _, err := resolveUniqueIDHandle(
+		context.Background(), client, "ds-1", uniqueIDHandle{prefix: "TASK", number: 8},
+	); err == nil {
+		t.Fatalf("expected error for missing handle")
+	}
+}
+
+func TestResolveRefPrefersPageOverBlockAnchor(t *testing.T) {
+	const link = "https://www.notion.so/Roadmap-1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d#aaaaaaaabbbbccccddddeeeeeeeeeeee"
+	ctx := context.Background()
+	page, err := resolvePageRef(ctx, &stubHandleClient{}, "default", link, "")
+	if err != nil || page != "1a2b3c4d-5e6f-7a8b-9c0d-1e2f3a4b5c6d" {
+		t.Fatalf("resolvePageRef = %q, %v; want the page ID", page, err)
+	}
+	block, err := resolveBlockRef(ctx, &stubHandleClient{}, "default", link, "")
+	if err != nil || block != "aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee" {
+		t.Fatalf("resolveBlockRef = %q, %v; want the block ID", block, err)
+	}
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const (
+	pagerEnv      = "NOTIONCTL_PAGER"
+	defaultPager  = "less"
+	defaultLessOp = "FRX"
+)
+
+// pagedFormats are the --format values meant for people; JSON and CSV are left for pipes.
+var pagedFormats = map[string]bool{formatTable: true, "markdown": true}
+
+// pagerAnnotation marks commands whose output is always for reading, such as pages read.
+const pagerAnnotation = "notionctl/pager"
+
+// pagerWriter holds output back until it would overflow the terminal, then starts the pager
+// and streams everything through it, the way git does. Output that fits is written straight
+// to the terminal when the command ends.
+type pagerWriter struct {
+	term    *os.File
+	command string
+	buf     bytes.Buffer
+	pager   *exec.Cmd
+	stdin   io.WriteCloser
+	width   int
+	limit   int
+	lines   int
+	column  int
+	direct  bool
+	closed  bool
+}
+
+// startPager redirects the command's output through a pager when stdout is a terminal, the
+// output is a table or Markdown, and neither --no-pager nor an empty $PAGER turned it off.
+func startPager(cmd *cobra.Command, globals *globalOptions) {
+	if globals.noPager || !pagedOutput(cmd) {
+		return
+	}
+	out, ok := cmd.OutOrStdout().(*os.File)
+	if !ok || !term.IsTerminal(int(out.Fd())) {
+		return
+	}
+	command := pagerCommand()
+	if command == "" {
+		return
+	}
+	width, height, err := term.GetSize(int(out.Fd()))
+	if err != nil || height < 2 {
+		return
+	}
+	cmd.SetOut(&pagerWriter{term: out, command: command, width: width, limit: height - 1})
+}
+
+func pagedOutput(cmd *cobra.Command) bool {
+	if cmd.Annotations[pagerAnnotation] != "" {
+		return true
+	}
+	f := cmd.Flags().Lookup("format")
+	return f != nil && pagedFormats[f.Value.String()]
+}
+
+// pagerCommand picks $NOTIONCTL_PAGER, then $PAGER, then less. Setting either to an empty
+// string or cat disables paging.
+func pagerCommand() string {
+	for _, name := range []string{pagerEnv, "PAGER"} {
+		if value, ok := os.LookupEnv(name); ok {
+			value = strings.TrimSpace(value)
+			if value == "cat" {
+				return ""
+			}
+			return value
+		}
+	}
+	return defaultPager
+}
+
+// closePager flushes output that fit on the screen, or waits for the user to leave the
+// pager. The pager's own exit status is not the command's concern.
+func closePager(cmd *cobra.Command) {
+	if cmd == nil {
+		return
+	}
+	if w, ok := cmd.OutOrStdout().(*pagerWriter); ok {
+		w.Close()
+	}
+}
+
+// terminalFile returns the terminal behind w, seeing through a pager so color and TTY
+// checks still apply to paged output.
+func terminalFile(w io.Writer) (*os.File, bool) {
+	switch v := w.(type) {
+	case *os.File:
+		return v, true
+	case *pagerWriter:
+		return v.term, true
+	default:
+		return nil, false
+	}
+}
+
+func (w *pagerWriter) Write(p []byte) (int, error) {
+	switch {
+	case w.closed:
+		return len(p), nil
+	case w.direct:
+		return w.term.Write(p)
+	case w.stdin != nil:
+		if _, err := w.stdin.Write(p); err != nil {
+			if errors.Is(err, syscall.EPIPE) || errors.Is(err, os.ErrClosed) {
+				// The user quit the pager; drop the rest like a closed pipe would.
+				w.closed = true
+				return len(p), nil
+			}
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	w.buf.Write(p)
+	w.count(p)
+	if w.lines < w.limit {
+		return len(p), nil
+	}
+	if err := w.start(); err != nil {
+		// Without a working pager, print as if there were none.
+		w.direct = true
+		if _, err := w.term.Write(w.buf.Bytes()); err != nil {
+			return 0, err
+		}
+		w.buf.Reset()
+	}
+	return len(p), nil
+}
+
+// count tracks screen rows used, including lines that wrap.
+func (w *pagerWriter) count(p []byte) {
+	for len(p) > 0 {
+		r, size := utf8.DecodeRune(p)
+		p = p[size:]
+		if r == '\n' {
+			w.lines++
+			w.column = 0
+			continue
+		}
+		w.column++
+		if w.width > 0 && w.column > w.width {
+			w.lines++
+			w.column = 1
+		}
+	}
+}
+
+func (w *pagerWriter) start() error {
+	pager := shellCommand(context.Background(), w.command)
+	pager.Stdout, pager.Stderr = w.term, os.Stderr
+	pager.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		pager.Env = append(pager.Env, "LESS="+defaultLessOp)
+	}
+	stdin, err := pager.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := pager.Start(); err != nil {
+		return err
+	}
+	w.pager, w.stdin = pager, stdin
+	if _, err := stdin.Write(w.buf.Bytes()); err != nil {
+		// The pager exited already; the rest of the output has nowhere to go.
+		w.closed = true
+	}
+	w.buf.Reset()
+	return nil
+}
+
+// Close ends the output: short output goes to the terminal, paged output waits for the pager.
+func (w *pagerWriter) Close() {
+	if w.stdin == nil {
+		if w.buf.Len() > 0 {
+			_, _ = w.term.Write(w.buf.Bytes())
+			w.buf.Reset()
+		}
+		w.closed = true
+		return
+	}
+	_ = w.stdin.Close()
+	_ = w.pager.Wait()
+	w.stdin = nil
+	w.closed = true
+}
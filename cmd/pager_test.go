@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestPagerCommand(t *testing.T) {
+	cases := []struct {
+		name     string
+		env      map[string]string
+		expected string
+	}{
+		{name: "default", expected: defaultPager},
+		{name: "pager", env: map[string]string{"PAGER": "more"}, expected: "more"},
+		{name: "override", env: map[string]string{"PAGER": "more", pagerEnv: "bat -p"}, expected: "bat -p"},
+		{name: "empty disables", env: map[string]string{"PAGER": ""}, expected: ""},
+		{name: "cat disables", env: map[string]string{pagerEnv: "cat", "PAGER": "more"}, expected: ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, name := range []string{pagerEnv, "PAGER"} {
+				t.Setenv(name, "")
+				if err := os.Unsetenv(name); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for name, value := range tc.env {
+				t.Setenv(name, value)
+			}
+			if got := pagerCommand(); got != tc.expected {
+				t.Fatalf("pagerCommand() = %q, want %q", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestPagedOutput(t *testing.T) {
+	t.Parallel()
+
+	withFormat := func(format string) *cobra.Command {
+		cmd := &cobra.Command{Use: "query"}
+		cmd.Flags().String("format", format, "")
+		return cmd
+	}
+	if !pagedOutput(withFormat(formatTable)) || !pagedOutput(withFormat("markdown")) {
+		t.Fatal("table and markdown output should be paged")
+	}
+	if pagedOutput(withFormat("json")) || pagedOutput(&cobra.Command{Use: "plain"}) {
+		t.Fatal("json output and commands without --format should not be paged")
+	}
+	annotated := &cobra.Command{Use: "read", Annotations: map[string]string{pagerAnnotation: "true"}}
+	if !pagedOutput(annotated) {
+		t.Fatal("annotated command should be paged")
+	}
+}
+
+func TestPagerWriter(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	screen, err := os.Create(filepath.Join(dir, "screen"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer screen.Close() //nolint:errcheck // test cleanup
+	paged := filepath.Join(dir, "paged")
+
+	short := &pagerWriter{term: screen, command: "cat > " + paged, width: 80, limit: 5}
+	if _, err := short.Write([]byte("one\ntwo\n")); err != nil {
+		t.Fatal(err)
+	}
+	if short.pager != nil {
+		t.Fatal("pager started for output that fits")
+	}
+	short.Close()
+	if data, _ := os.ReadFile(screen.Name()); string(data) != "one\ntwo\n" {
+		t.Fatalf("screen = %q", data)
+	}
+
+	long := &pagerWriter{term: screen, command: "cat > " + paged, width: 80, limit: 5}
+	output := strings.Repeat("row\n", 8)
+	for _, line := range strings.SplitAfter(output, "\n") {
+		if _, err := long.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if long.pager == nil {
+		t.Fatal("pager not started for output taller than the screen")
+	}
+	long.Close()
+	if data, _ := os.ReadFile(paged); string(data) != output {
+		t.Fatalf("paged = %q, want %q", data, output)
+	}
+	if data, _ := os.ReadFile(screen.Name()); string(data) != "one\ntwo\n" {
+		t.Fatalf("paged output reached the screen: %q", data)
+	}
+	if f, ok := terminalFile(long); !ok || f != screen {
+		t.Fatal("terminalFile does not see through the pager")
+	}
+}
+
+func TestPagerWriterCountsWrappedLines(t *testing.T) {
+	t.Parallel()
+
+	w := &pagerWriter{width: 10}
+	w.count([]byte(strings.Repeat("x", 25) + "\nshort\n"))
+	if w.lines != 4 {
+		t.Fatalf("lines = %d, want 4", w.lines)
+	}
+}
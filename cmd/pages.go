@@ -8,8 +8,17 @@ func newPagesCmd(globals *globalOptions) *cobra.Command {
 		Short: "Work with Notion pages",
 	}
 
-	cmd.AddCommand(newPagesGetCmd(globals))
-	cmd.AddCommand(newPagesUpdateCmd(globals))
+	cmd.AddCommand(pagePickerCommand(newPagesGetCmd(globals), globals))
+	cmd.AddCommand(newPagesCreateCmd(globals))
+	cmd.AddCommand(pagePickerCommand(newPagesUpdateCmd(globals), globals))
+	cmd.AddCommand(pagePickerCommand(newPagesBacklinksCmd(globals), globals))
+	cmd.AddCommand(pagePickerCommand(newPagesExportCmd(globals), globals))
+	cmd.AddCommand(newPagesImportCmd(globals))
+	cmd.AddCommand(pagePickerCommand(newPagesReadCmd(globals), globals))
+	cmd.AddCommand(newPagesBulkCreateCmd(globals))
+	cmd.AddCommand(newPagesBulkUpdateCmd(globals))
+	cmd.AddCommand(newPagesLinkCmd(globals))
+	cmd.AddCommand(pagePickerCommand(newPagesEditCmd(globals), globals))
 
 	return cmd
 }
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/render"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+type pagesBacklinksOptions struct {
+	fromDataSource string
+	via            string
+	dataSource     string
+	format         string
+	limit          int
+}
+
+func newPagesBacklinksCmd(globals *globalOptions) *cobra.Command {
+	opts := &pagesBacklinksOptions{format: formatJSON}
+
+	cmd := &cobra.Command{
+		Use:   "backlinks <page-id|url|unique-id>",
+		Short: "List the pages of a data source whose relation points at a page",
+		Args:  cobra.ExactArgs(1),
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().StringVar(
+		&opts.fromDataSource,
+		"from-data-source",
+		"",
+		"Data source ID, URL, or alias whose pages link to the target (default: the profile's default)",
+	)
+	cmd.Flags().StringVar(
+		&opts.via,
+		"via",
+		"",
+		"Relation property on --from-data-source to follow (default: its only relation property)",
+	)
+	formatFlag(cmd, &opts.format, "Output format", formatJSON, formatTable)
+	cmd.Flags().IntVar(&opts.limit, "limit", 0, "Stop after this many linking pages")
+	cmd.Flags().StringVar(
+		&opts.dataSource,
+		"data-source",
+		"",
+		"Data source ID or alias used to resolve unique ID handles such as TASK-123",
+	)
+
+	return cmd
+}
+
+func (opts *pagesBacklinksOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if opts.limit < 0 {
+			return errors.New("--limit must be positive")
+		}
+		client, err := buildClient(globals)
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		pageID, err := resolvePageRef(ctx, client, globals.profile, args[0], opts.dataSource)
+		if err != nil {
+			return err
+		}
+		fromID, err := resolveDataSourceAlias(globals.profile, opts.fromDataSource)
+		if err != nil {
+			return fmt.Errorf("resolve --from-data-source: %w", err)
+		}
+
+		pages, err := findBacklinks(ctx, client, fromID, opts.via, pageID, opts.limit)
+		if err != nil {
+			return err
+		}
+		return opts.render(cmd, pages)
+	}
+}
+
+// findBacklinks queries dataSourceID for pages whose via relation contains pageID.
+func findBacklinks(
+	ctx context.Context,
+	client relationClient,
+	dataSourceID string,
+	via string,
+	pageID string,
+	limit int,
+) ([]notion.Page, error) {
+	ds, err := client.GetDataSource(ctx, dataSourceID)
+	if err != nil {
+		return nil, fmt.Errorf("get data source: %w", err)
+	}
+	ref, err := backlinkProperty(schema.NewIndex(ds), via)
+	if err != nil {
+		return nil, err
+	}
+
+	req := notion.QueryDataSourceRequest{
+		Filter: map[string]any{
+			"property": ref.ID,
+			"relation": map[string]any{"contains": pageID},
+		},
+	}
+	resp, err := executeDataSourceQuery(ctx, client, dataSourceID, req, true, limit)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Results, nil
+}
+
+// backlinkProperty resolves --via, or the data source's only relation property when it is
+// empty.
+func backlinkProperty(idx *schema.Index, via string) (notion.PropertyReference, error) {
+	if via != "" {
+		ref, ok := idx.ReferenceForName(via)
+		if !ok {
+			return notion.PropertyReference{}, fmt.Errorf("unknown property %q", via)
+		}
+		if ref.Type != relationType {
+			return notion.PropertyReference{}, fmt.Errorf("property %q is %s, not a relation", ref.Name, ref.Type)
+		}
+		return ref, nil
+	}
+
+	var relations []notion.PropertyReference
+	for _, name := range idx.PropertyNames() {
+		if ref, _ := idx.ReferenceForName(name); ref.Type == relationType {
+			relations = append(relations, ref)
+		}
+	}
+	switch len(relations) {
+	case 0:
+		return notion.PropertyReference{}, errors.New("data source has no relation properties")
+	case 1:
+		return relations[0], nil
+	default:
+		names := make([]string, 0, len(relations))
+		for _, ref := range relations {
+			names = append(names, ref.Name)
+		}
+		return notion.PropertyReference{}, fmt.Errorf(
+			"data source has several relation properties; choose one with --via (%s)", strings.Join(names, ", "))
+	}
+}
+
+func (opts *pagesBacklinksOptions) render(cmd *cobra.Command, pages []notion.Page) error {
+	switch opts.format {
+	case formatJSON:
+		if pages == nil {
+			pages = []notion.Page{}
+		}
+		if err := writeJSON(cmd.Context(), cmd.OutOrStdout(), pages); err != nil {
+			return fmt.Errorf("render json: %w", err)
+		}
+		return nil
+	case formatTable:
+		rows := make([][]string, 0, len(pages))
+		for _, page := range pages {
+			rows = append(rows, []string{
+				page.ID,
+				pageTitle(page),
+				page.LastEditedTime.UTC().Format(time.RFC3339),
+				page.URL,
+			})
+		}
+		if err := render.Table(cmd.OutOrStdout(), []string{"ID", "Title", "Last Edited", "URL"}, rows); err != nil {
+			return fmt.Errorf("render table: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q (expected json or table)", opts.format)
+	}
+}
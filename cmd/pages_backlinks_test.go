@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestFindBacklinks(t *testing.T) {
+	client := &stubHandleClient{
+		ds: notion.DataSource{Properties: map[string]notion.PropertyReference{
+			"Name":    {ID: "title", Name: "Name", Type: "title"},
+			"Project": {ID: "proj%3D", Name: "Project", Type: "relation"},
+		}},
+		results: []notion.Page{{ID: "task-1"}, {ID: "task-2"}},
+	}
+
+	pages, err := findBacklinks(context.Background(), client, "tasks", "", "page-1", 0)
+	if err != nil {
+		t.Fatalf("findBacklinks returned error: %v", err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("expected both linking pages, got %+v", pages)
+	}
+	filter, _ := client.lastReq.Filter.(map[string]any)
+	relation, _ := filter["relation"].(map[string]any)
+	if filter["property"] != "proj%3D" || relation["contains"] != "page-1" {
+		t.Fatalf("expected a relation contains filter on the only relation, got %#v", client.lastReq.Filter)
+	}
+
+	client.ds.Properties["Parent"] = notion.PropertyReference{ID: "parent", Name: "Parent", Type: "relation"}
+	if _, err := findBacklinks(context.Background(), client, "tasks", "", "page-1", 0); err == nil ||
+		!strings.Contains(err.Error(), "--via") {
+		t.Fatalf("expected ambiguity to ask for --via, got %v", err)
+	}
+	if _, err := findBacklinks(context.Background(), client, "tasks", "Name", "page-1", 0); err == nil {
+		t.Fatalf("expected a non-relation --via to fail")
+	}
+	if _, err := findBacklinks(context.Background(), client, "tasks", "parent", "page-1", 0); err != nil {
+		t.Fatalf("expected --via to pick Parent, got %v", err)
+	}
+}
@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/render"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+const (
+	inputFormatCSV   = "csv"
+	inputFormatJSONL = "jsonl"
+)
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type pagesBulkCreateOptions struct {
+	dataSourceID  string
+	inputPath     string
+	inputFormat   string
+	progressEvery int
+	exec          executionOptions
+	retry         retryOptions
+	dryRun        bool
+	noValidate    bool
+}
+
+// bulkCreateClient is the subset of the Notion client used by bulk creates.
+type bulkCreateClient interface {
+	GetDataSource(ctx context.Context, dataSourceID string) (notion.DataSource, error)
+	CreatePage(ctx context.Context, req notion.CreatePageRequest) (notion.Page, error)
+}
+
+// bulkCreateRow is one input row: its 1-based line, 0-based index, and properties, or why
+// it cannot be sent.
+type bulkCreateRow struct {
+	properties map[string]any
+	err        error
+	line       int
+	index      int
+}
+
+// bulkCreateSummary tallies the outcome of a bulk create.
+type bulkCreateSummary struct {
+	Rows    int
+	Created int
+	Failed  int
+}
+
+func (s bulkCreateSummary) String() string {
+	return fmt.Sprintf("%d rows: %d created, %d failed", s.Rows, s.Created, s.Failed)
+}
+
+func newPagesBulkCreateCmd(globals *globalOptions) *cobra.Command {
+	opts := &pagesBulkCreateOptions{
+		progressEvery: defaultImportProgressEvery,
+		exec:          defaultExecutionOptions(),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "bulk-create",
+		Short: "Create one page per row of a CSV or JSONL file",
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Data source ID or URL to create the pages in")
+	cmd.Flags().StringVar(&opts.inputPath, "input", "", "Path to the rows (- for stdin)")
+	cmd.Flags().StringVar(
+		&opts.inputFormat,
+		"input-format",
+		"",
+		"Input format: csv|jsonl (default: csv for .csv files, jsonl otherwise)",
+	)
+	cmd.Flags().IntVar(&opts.progressEvery, "progress-every", opts.progressEvery, "Report progress every N rows (0 disables)")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print each create request that would be sent without sending it")
+	cmd.Flags().BoolVar(
+		&opts.noValidate,
+		"no-validate",
+		false,
+		"Send rows without checking them against the data source schema first",
+	)
+	opts.exec.register(cmd, "Rows created concurrently before the next batch starts")
+	opts.retry.register(cmd, "pages bulk-create", "input")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
+
+	return cmd
+}
+
+func (opts *pagesBulkCreateOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		client, err := opts.exec.buildClient(globals)
+		if err != nil {
+			return err
+		}
+		if opts.dryRun {
+			client = dryRunClient(client, cmd.OutOrStdout())
+		}
+
+		summary, err := opts.createFromFlags(cmd, client)
+		if err != nil {
+			return err
+		}
+		if opts.dryRun {
+			safeLog(cmd.ErrOrStderr(), "Dry run: would create %s", summary)
+			return nil
+		}
+		if err := opts.retry.save(cmd.ErrOrStderr()); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Created %s\n", summary); err != nil {
+			return fmt.Errorf("write summary: %w", err)
+		}
+		if summary.Failed > 0 {
+			return fmt.Errorf("%d of %d rows failed", summary.Failed, summary.Rows)
+		}
+		return nil
+	}
+}
+
+// createFromFlags creates the rows of --input, or of the --retry-from failures file.
+func (opts *pagesBulkCreateOptions) createFromFlags(cmd *cobra.Command, client bulkCreateClient) (bulkCreateSummary, error) {
+	if opts.retry.retryFrom != "" {
+		items, err := opts.retry.load()
+		if err != nil {
+			return bulkCreateSummary{}, err
+		}
+		rows := make([]bulkCreateRow, 0, len(items))
+		for _, item := range items {
+			row := bulkCreateRow{line: item.Line, index: item.Index}
+			row.err = decodePayload(item, &row.properties)
+			rows = append(rows, row)
+		}
+		return opts.createRows(cmd.Context(), client, rows, cmd.ErrOrStderr()), nil
+	}
+
+	format, err := opts.format()
+	if err != nil {
+		return bulkCreateSummary{}, err
+	}
+	input, closeInput, err := openInput(opts.inputPath, cmd.InOrStdin())
+	if err != nil {
+		return bulkCreateSummary{}, err
+	}
+	defer closeInput()
+	return opts.create(cmd.Context(), client, input, format, cmd.ErrOrStderr())
+}
+
+// format resolves --input-format, falling back to the input file's extension.
+func (opts *pagesBulkCreateOptions) format() (string, error) {
+	switch strings.ToLower(opts.inputFormat) {
+	case inputFormatCSV:
+		return inputFormatCSV, nil
+	case inputFormatJSONL, "ndjson":
+		return inputFormatJSONL, nil
+	case "":
+		if strings.EqualFold(filepath.Ext(opts.inputPath), ".csv") {
+			return inputFormatCSV, nil
+		}
+		return inputFormatJSONL, nil
+	default:
+		return "", fmt.Errorf("unsupported --input-format %q (want csv or jsonl)", opts.inputFormat)
+	}
+}
+
+// create reads every row, then creates the pages with createRows.
+func (opts *pagesBulkCreateOptions) create(
+	ctx context.Context,
+	client bulkCreateClient,
+	input io.Reader,
+	format string,
+	log io.Writer,
+) (bulkCreateSummary, error) {
+	var summary bulkCreateSummary
+	ds, err := client.GetDataSource(ctx, opts.dataSourceID)
+	if err != nil {
+		return summary, fmt.Errorf("get data source: %w", err)
+	}
+	idx := schema.NewIndex(ds)
+
+	var rows []bulkCreateRow
+	if format == inputFormatCSV {
+		rows, err = readCSVCreateRows(input, idx)
+	} else {
+		rows, err = readJSONLCreateRows(input, idx)
+	}
+	if err != nil {
+		return summary, err
+	}
+	if !opts.noValidate {
+		// A row that does not fit the schema fails on its own, like one that cannot be read.
+		// New select options are noted once however many rows use them.
+		notes := map[string]bool{}
+		for i := range rows {
+			if rows[i].err == nil {
+				if rows[i].err = validateProperties(ds, rows[i].properties, io.Discard); rows[i].err == nil {
+					for _, note := range schema.NewOptions(ds, rows[i].properties) {
+						notes[note] = true
+					}
+				}
+			}
+		}
+		for _, note := range render.SortedKeys(notes) {
+			safeLog(log, "warning: %s", note)
+		}
+	}
+	return opts.createRows(ctx, client, rows, log), nil
+}
+
+// createRows creates the pages in batches of --batch-size with up to --concurrency
+// requests in flight. Rows that fail are logged in input order, recorded for the failures
+// file, and do not stop the others.
+func (opts *pagesBulkCreateOptions) createRows(
+	ctx context.Context,
+	client bulkCreateClient,
+	rows []bulkCreateRow,
+	log io.Writer,
+) bulkCreateSummary {
+	var summary bulkCreateSummary
+	for start := 0; start < len(rows); start += opts.exec.batchSize {
+		batch := rows[start:min(start+opts.exec.batchSize, len(rows))]
+		_ = opts.exec.forEach(ctx, len(batch), func(ctx context.Context, i int) error {
+			if batch[i].err == nil {
+				batch[i].err = opts.createRow(ctx, client, batch[i])
+			}
+			return nil
+		})
+
+		for _, row := range batch {
+			summary.Rows++
+			if row.err != nil {
+				summary.Failed++
+				safeLog(log, "line %d: %v", row.line, row.err)
+				opts.retry.record(row.index, row.line, row.properties, row.err)
+			} else {
+				summary.Created++
+			}
+			if opts.progressEvery > 0 && summary.Rows%opts.progressEvery == 0 {
+				safeLog(log, "progress: %s", summary)
+			}
+		}
+	}
+	return summary
+}
+
+func (opts *pagesBulkCreateOptions) createRow(ctx context.Context, client bulkCreateClient, row bulkCreateRow) error {
+	if _, err := client.CreatePage(ctx, notion.CreatePageRequest{
+		Parent:     notion.DataSourceParent(opts.dataSourceID),
+		Properties: row.properties,
+	}); err != nil {
+		return fmt.Errorf("create page: %w", err)
+	}
+	return nil
+}
+
+// readCSVCreateRows coerces each record's non-empty cells by property type. A cell that
+// cannot be coerced fails only its own row.
+func readCSVCreateRows(input io.Reader, idx *schema.Index) ([]bulkCreateRow, error) {
+	reader, err := newCSVPropertyReader(input, idx)
+	if err != nil {
+		return nil, err
+	}
+	var rows []bulkCreateRow
+	for {
+		line, cells, err := reader.next()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := bulkCreateRow{line: line, index: len(rows)}
+		row.properties, row.err = reader.properties(cells, -1, false)
+		rows = append(rows, row)
+	}
+}
+
+// readJSONLCreateRows decodes one object per line, keyed by property name, as ds import does.
+func readJSONLCreateRows(input io.Reader, idx *schema.Index) ([]bulkCreateRow, error) {
+	var rows []bulkCreateRow
+	err := scanNDJSON(input, func(line int, data []byte) error {
+		row := bulkCreateRow{line: line, index: len(rows)}
+		var values map[string]any
+		if err := json.Unmarshal(data, &values); err != nil {
+			row.err = fmt.Errorf("decode row: %w", err)
+		} else {
+			row.properties, row.err = rowProperties(values, idx)
+		}
+		rows = append(rows, row)
+		return nil
+	})
+	return rows, err
+}
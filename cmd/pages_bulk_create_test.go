@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+// fakeBulkCreateClient records the properties of every created page by title.
+type fakeBulkCreateClient struct {
+	mu      sync.Mutex
+	created map[string]map[string]any
+}
+
+func (f *fakeBulkCreateClient) GetDataSource(_ context.Context, _ string) (notion.DataSource, error) {
+	return notion.DataSource{Properties: map[string]notion.PropertyReference{
+		"Name":    {ID: "title", Name: "Name", Type: "title"},
+		"Points":  {ID: "pts", Name: "Points", Type: "number"},
+		"Due":     {ID: "due", Name: "Due", Type: "date"},
+		"Status":  {ID: "st", Name: "Status", Type: "select"},
+		"Blocker": {ID: "rel", Name: "Blocker", Type: "relation"},
+	}}, nil
+}
+
+func (f *fakeBulkCreateClient) CreatePage(_ context.Context, req notion.CreatePageRequest) (notion.Page, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var name struct {
+		Title []struct {
+			Text struct{ Content string } `json:"text"`
+		} `json:"title"`
+	}
+	data, _ := json.Marshal(req.Properties["Name"])
+	_ = json.Unmarshal(data, &name)
+	f.created[name.Title[0].Text.Content] = req.Properties
+	return notion.Page{ID: "page"}, nil
+}
+
+func TestBulkCreateCoercesCSVCells(t *testing.T) {
+	client := &fakeBulkCreateClient{created: map[string]map[string]any{}}
+	opts := &pagesBulkCreateOptions{dataSourceID: "ds", progressEvery: 2, exec: defaultExecutionOptions()}
+	input := "Name,Points,Due,Status,Blocker\n" +
+		"Alpha,3,2026-01-02,Todo,abc123\n" +
+		"Beta,lots,,,\n" +
+		"Gamma,,,Done,\n"
+	var log bytes.Buffer
+
+	summary, err := opts.create(context.Background(), client, strings.NewReader(input), inputFormatCSV, &log)
+	if err != nil {
+		t.Fatalf("create returned error: %v", err)
+	}
+	if summary != (bulkCreateSummary{Rows: 3, Created: 2, Failed: 1}) {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	alpha := client.created["Alpha"]
+	if len(alpha) != 5 {
+		t.Fatalf("expected every Alpha cell to be sent, got %+v", alpha)
+	}
+	if got := client.created["Gamma"]; len(got) != 2 || got["Status"] == nil {
+		t.Fatalf("expected empty Gamma cells to be left out, got %+v", got)
+	}
+	if !strings.Contains(log.String(), "line 3:") || !strings.Contains(log.String(), "progress: 2 rows") {
+		t.Fatalf("unexpected log output: %q", log.String())
+	}
+}
+
+func TestBulkCreateReadsJSONL(t *testing.T) {
+	client := &fakeBulkCreateClient{created: map[string]map[string]any{}}
+	opts := &pagesBulkCreateOptions{dataSourceID: "ds", exec: defaultExecutionOptions()}
+	input := `{"Name": "Alpha", "Points": 3}` + "\n\n" + `{"Owner": "x"}` + "\n"
+
+	summary, err := opts.create(context.Background(), client, strings.NewReader(input), inputFormatJSONL, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("create returned error: %v", err)
+	}
+	if summary != (bulkCreateSummary{Rows: 2, Created: 1, Failed: 1}) {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+}
+
+func TestBulkCreateFormatFromExtension(t *testing.T) {
+	for path, want := range map[string]string{"rows.CSV": inputFormatCSV, "rows.jsonl": inputFormatJSONL, "-": inputFormatJSONL} {
+		opts := &pagesBulkCreateOptions{inputPath: path}
+		if got, err := opts.format(); err != nil || got != want {
+			t.Fatalf("format for %q = %q, %v; want %q", path, got, err, want)
+		}
+	}
+}
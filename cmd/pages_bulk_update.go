@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type pagesBulkUpdateOptions struct {
+	dataSourceID string
+	csvPath      string
+	keyProperty  string
+	exec         executionOptions
+	retry        retryOptions
+	clearEmpty   bool
+	dryRun       bool
+}
+
+// bulkUpdateClient is the subset of the Notion client used by bulk updates.
+type bulkUpdateClient interface {
+	dataSourceQuerier
+	GetDataSource(ctx context.Context, dataSourceID string) (notion.DataSource, error)
+	UpdatePage(ctx context.Context, pageID string, req notion.UpdatePageRequest) (notion.Page, error)
+}
+
+// bulkUpdateRow is one CSV record: its 1-based line, 0-based index, key value, and
+// property patch.
+type bulkUpdateRow struct {
+	properties map[string]any
+	key        string
+	line       int
+	index      int
+}
+
+// bulkUpdatePayload is how a failed row is kept in the failures file.
+type bulkUpdatePayload struct {
+	Properties map[string]any `json:"properties"`
+	Key        string         `json:"key"`
+}
+
+// bulkUpdateSummary tallies the outcome of a bulk update.
+type bulkUpdateSummary struct {
+	Rows    int
+	Updated int
+	Failed  int
+}
+
+func (s bulkUpdateSummary) String() string {
+	return fmt.Sprintf("%d rows: %d updated, %d failed", s.Rows, s.Updated, s.Failed)
+}
+
+func newPagesBulkUpdateCmd(globals *globalOptions) *cobra.Command {
+	opts := &pagesBulkUpdateOptions{exec: defaultExecutionOptions()}
+
+	cmd := &cobra.Command{
+		Use:   "bulk-update",
+		Short: "Update the pages matched by a key column with the other columns of a CSV file",
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Data source ID or URL holding the pages")
+	cmd.Flags().StringVar(&opts.csvPath, "csv", "", "Path to the CSV file (- for stdin); the header row names properties")
+	cmd.Flags().StringVar(&opts.keyProperty, "key", "", "Column and property whose value identifies each page")
+	cmd.Flags().BoolVar(&opts.clearEmpty, "clear-empty", false, "Clear properties whose cell is empty instead of leaving them unchanged")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print each update request that would be sent without sending it")
+	opts.exec.register(cmd, "")
+	opts.retry.register(cmd, "pages bulk-update", "csv")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
+	cobra.CheckErr(cmd.MarkFlagRequired("key"))
+
+	return cmd
+}
+
+func (opts *pagesBulkUpdateOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		client, err := opts.exec.buildClient(globals)
+		if err != nil {
+			return err
+		}
+		if opts.dryRun {
+			client = dryRunClient(client, cmd.OutOrStdout())
+		}
+
+		var input io.Reader
+		if opts.retry.retryFrom == "" {
+			reader, closeInput, err := openInput(opts.csvPath, cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			defer closeInput()
+			input = reader
+		}
+
+		summary, err := opts.update(cmd.Context(), client, input, cmd.ErrOrStderr())
+		if err != nil {
+			return err
+		}
+		if opts.dryRun {
+			safeLog(cmd.ErrOrStderr(), "Dry run: would update %s", summary)
+			return nil
+		}
+		if err := opts.retry.save(cmd.ErrOrStderr()); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Updated %s\n", summary); err != nil {
+			return fmt.Errorf("write summary: %w", err)
+		}
+		if summary.Failed > 0 {
+			return fmt.Errorf("%d of %d rows failed", summary.Failed, summary.Rows)
+		}
+		return nil
+	}
+}
+
+// update reads every row of input, or of the --retry-from failures file, then applies the
+// patches with up to --concurrency requests in flight. Failures are logged and recorded per
+// row, in input order, and do not stop the other rows.
+func (opts *pagesBulkUpdateOptions) update(
+	ctx context.Context,
+	client bulkUpdateClient,
+	input io.Reader,
+	log io.Writer,
+) (bulkUpdateSummary, error) {
+	var summary bulkUpdateSummary
+	ds, err := client.GetDataSource(ctx, opts.dataSourceID)
+	if err != nil {
+		return summary, fmt.Errorf("get data source: %w", err)
+	}
+	idx := schema.NewIndex(ds)
+	keyRef, ok := idx.ReferenceForName(opts.keyProperty)
+	if !ok {
+		return summary, fmt.Errorf("unknown --key property %q", opts.keyProperty)
+	}
+
+	var rows []bulkUpdateRow
+	if opts.retry.retryFrom != "" {
+		rows, err = opts.retryRows()
+	} else {
+		rows, err = opts.readRows(input, idx, keyRef)
+	}
+	if err != nil {
+		return summary, err
+	}
+	failed := make([]error, len(rows))
+	_ = opts.exec.forEach(ctx, len(rows), func(ctx context.Context, i int) error {
+		failed[i] = opts.updateRow(ctx, client, keyRef, rows[i])
+		return nil
+	})
+
+	for i, row := range rows {
+		summary.Rows++
+		if failed[i] != nil {
+			summary.Failed++
+			safeLog(log, "line %d (%s=%q): %v", row.line, keyRef.Name, row.key, failed[i])
+			opts.retry.record(row.index, row.line, bulkUpdatePayload{Properties: row.properties, Key: row.key}, failed[i])
+			continue
+		}
+		summary.Updated++
+	}
+	return summary, nil
+}
+
+// readRows parses the CSV into per-row property patches. Every column must name a property,
+// and cells are coerced by the property's type as `ds backfill` does.
+func (opts *pagesBulkUpdateOptions) readRows(
+	input io.Reader,
+	idx *schema.Index,
+	keyRef notion.PropertyReference,
+) ([]bulkUpdateRow, error) {
+	reader, err := newCSVPropertyReader(input, idx)
+	if err != nil {
+		return nil, err
+	}
+	keyColumn := reader.column(keyRef)
+	if keyColumn < 0 {
+		return nil, fmt.Errorf("csv has no %q column", keyRef.Name)
+	}
+
+	var rows []bulkUpdateRow
+	for {
+		line, cells, err := reader.next()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := bulkUpdateRow{line: line, index: len(rows)}
+		if keyColumn < len(cells) {
+			row.key = strings.TrimSpace(cells[keyColumn])
+		}
+		if row.key == "" {
+			return nil, fmt.Errorf("line %d has no %s value", line, keyRef.Name)
+		}
+		if row.properties, err = reader.properties(cells, keyColumn, opts.clearEmpty); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rows = append(rows, row)
+	}
+}
+
+// retryRows reads the rows of the --retry-from failures file.
+func (opts *pagesBulkUpdateOptions) retryRows() ([]bulkUpdateRow, error) {
+	items, err := opts.retry.load()
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]bulkUpdateRow, 0, len(items))
+	for _, item := range items {
+		var payload bulkUpdatePayload
+		if err := decodePayload(item, &payload); err != nil {
+			return nil, fmt.Errorf("line %d: %w", item.Line, err)
+		}
+		rows = append(rows, bulkUpdateRow{
+			properties: payload.Properties,
+			key:        payload.Key,
+			line:       item.Line,
+			index:      item.Index,
+		})
+	}
+	return rows, nil
+}
+
+func (opts *pagesBulkUpdateOptions) updateRow(
+	ctx context.Context,
+	client bulkUpdateClient,
+	keyRef notion.PropertyReference,
+	row bulkUpdateRow,
+) error {
+	matches, err := pagesWithKey(ctx, client, opts.dataSourceID, keyRef, row.key)
+	if err != nil {
+		return err
+	}
+	switch len(matches) {
+	case 0:
+		return errors.New("no page has this key")
+	case 1:
+	default:
+		return errors.New("key matches more than one page")
+	}
+	if len(row.properties) == 0 {
+		return nil
+	}
+	if _, err := client.UpdatePage(ctx, matches[0].ID, notion.UpdatePageRequest{Properties: row.properties}); err != nil {
+		return fmt.Errorf("update page %s: %w", matches[0].ID, err)
+	}
+	return nil
+}
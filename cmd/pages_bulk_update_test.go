@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+// fakeBulkUpdateClient matches pages by the rich_text key filter and records updates.
+type fakeBulkUpdateClient struct {
+	mu      sync.Mutex
+	pages   map[string][]notion.Page
+	updated map[string]map[string]any
+}
+
+func (f *fakeBulkUpdateClient) GetDataSource(_ context.Context, _ string) (notion.DataSource, error) {
+	return notion.DataSource{Properties: map[string]notion.PropertyReference{
+		"Name":      {ID: "title", Name: "Name", Type: "title"},
+		"Ticket ID": {ID: "tid", Name: "Ticket ID", Type: "rich_text"},
+		"Points":    {ID: "pts", Name: "Points", Type: "number"},
+		"Status":    {ID: "st", Name: "Status", Type: "select"},
+	}}, nil
+}
+
+func (f *fakeBulkUpdateClient) QueryDataSource(
+	_ context.Context,
+	_ string,
+	req notion.QueryDataSourceRequest,
+) (notion.QueryDataSourceResponse, error) {
+	filter, _ := req.Filter.(map[string]any)
+	text, _ := filter["rich_text"].(map[string]any)
+	key, _ := text["equals"].(string)
+	return notion.QueryDataSourceResponse{Results: f.pages[key]}, nil
+}
+
+func (f *fakeBulkUpdateClient) UpdatePage(
+	_ context.Context,
+	pageID string,
+	req notion.UpdatePageRequest,
+) (notion.Page, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updated[pageID] = req.Properties
+	return notion.Page{ID: pageID}, nil
+}
+
+func TestBulkUpdateMatchesRowsByKey(t *testing.T) {
+	client := &fakeBulkUpdateClient{
+		pages: map[string][]notion.Page{
+			"T-1": {{ID: "page-1"}},
+			"T-3": {{ID: "page-3a"}, {ID: "page-3b"}},
+		},
+		updated: map[string]map[string]any{},
+	}
+	opts := &pagesBulkUpdateOptions{dataSourceID: "ds", keyProperty: "Ticket ID", exec: defaultExecutionOptions()}
+	input := "Ticket ID,Points,Status\nT-1,5,\nT-2,3,Done\nT-3,1,Done\n"
+	var log bytes.Buffer
+
+	summary, err := opts.update(context.Background(), client, strings.NewReader(input), &log)
+	if err != nil {
+		t.Fatalf("update returned error: %v", err)
+	}
+	if summary != (bulkUpdateSummary{Rows: 3, Updated: 1, Failed: 2}) {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	patch := client.updated["page-1"]
+	if len(client.updated) != 1 || len(patch) != 1 || patch["Points"] == nil {
+		t.Fatalf("expected only Points to be sent for page-1, got %+v", client.updated)
+	}
+	for _, want := range []string{`line 3 (Ticket ID="T-2"): no page`, `line 4 (Ticket ID="T-3"): key matches more`} {
+		if !strings.Contains(log.String(), want) {
+			t.Fatalf("log %q is missing %q", log.String(), want)
+		}
+	}
+}
+
+func TestBulkUpdateRejectsUnknownColumns(t *testing.T) {
+	client := &fakeBulkUpdateClient{updated: map[string]map[string]any{}}
+	opts := &pagesBulkUpdateOptions{dataSourceID: "ds", keyProperty: "Ticket ID", exec: defaultExecutionOptions()}
+
+	_, err := opts.update(context.Background(), client, strings.NewReader("Ticket ID,Owner\nT-1,x\n"), &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), `"Owner"`) {
+		t.Fatalf("expected an unknown column error, got %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/render"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+type pagesCreateOptions struct {
+	dataSourceID string
+	propsPath    string
+	format       string
+	dryRun       bool
+	noValidate   bool
+}
+
+// pageCreator is the subset of the Notion client used to create a page.
+type pageCreator interface {
+	GetDataSource(ctx context.Context, dataSourceID string) (notion.DataSource, error)
+	CreatePage(ctx context.Context, req notion.CreatePageRequest) (notion.Page, error)
+}
+
+func newPagesCreateCmd(globals *globalOptions) *cobra.Command {
+	opts := &pagesCreateOptions{format: formatJSON}
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a page in a data source",
+		Args:  cobra.NoArgs,
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Data source ID or URL to create the page in")
+	cmd.Flags().StringVar(&opts.propsPath, "props", "", "Path to JSON file describing the page's properties (- for stdin)")
+	formatFlag(cmd, &opts.format, "Output format", formatJSON, formatTable)
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print the POST request that would be sent without sending it")
+	cmd.Flags().BoolVar(
+		&opts.noValidate,
+		"no-validate",
+		false,
+		"Send the properties without checking them against the data source schema first",
+	)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
+	cobra.CheckErr(cmd.MarkFlagRequired("props"))
+
+	return cmd
+}
+
+func (opts *pagesCreateOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		client, err := buildClient(globals)
+		if err != nil {
+			return err
+		}
+		if opts.dryRun {
+			client = dryRunClient(client, cmd.OutOrStdout())
+		}
+
+		page, err := opts.create(cmd.Context(), client, cmd.InOrStdin(), cmd.ErrOrStderr())
+		if err != nil || opts.dryRun {
+			return err
+		}
+		return opts.renderPage(cmd, page)
+	}
+}
+
+func (opts *pagesCreateOptions) create(
+	ctx context.Context,
+	client pageCreator,
+	stdin io.Reader,
+	log io.Writer,
+) (notion.Page, error) {
+	properties, err := loadUpdatePayload(opts.propsPath, stdin)
+	if err != nil {
+		return notion.Page{}, err
+	}
+	ds, err := client.GetDataSource(ctx, opts.dataSourceID)
+	if err != nil {
+		return notion.Page{}, fmt.Errorf("get data source: %w", err)
+	}
+	if properties, err = propertiesByName(ds, properties); err != nil {
+		return notion.Page{}, err
+	}
+	if !opts.noValidate {
+		if err := validateProperties(ds, properties, log); err != nil {
+			return notion.Page{}, err
+		}
+	}
+	page, err := client.CreatePage(ctx, notion.CreatePageRequest{
+		Parent:     notion.DataSourceParent(opts.dataSourceID),
+		Properties: properties,
+	})
+	if err != nil {
+		return notion.Page{}, fmt.Errorf("create page: %w", err)
+	}
+	return page, nil
+}
+
+// propertiesByName keys properties by their schema names, resolving property IDs (with or
+// without the property_id: marker) as pages update does, since creating a page takes names.
+// Keys matching no property are kept for validation to report.
+func propertiesByName(ds notion.DataSource, properties map[string]any) (map[string]any, error) {
+	idx := schema.NewIndex(ds)
+	resolved := make(map[string]any, len(properties))
+	from := make(map[string]string, len(properties))
+	for _, key := range render.SortedKeys(properties) {
+		name := key
+		if ref, ok := idx.ReferenceForName(key); ok {
+			name = ref.Name
+		}
+		if other, dup := from[name]; dup {
+			return nil, fmt.Errorf("properties %q and %q both set %q", other, key, name)
+		}
+		resolved[name], from[name] = properties[key], key
+	}
+	return resolved, nil
+}
+
+func (opts *pagesCreateOptions) renderPage(cmd *cobra.Command, page notion.Page) error {
+	switch opts.format {
+	case formatJSON:
+		if err := writeJSON(cmd.Context(), cmd.OutOrStdout(), page); err != nil {
+			return fmt.Errorf("render json: %w", err)
+		}
+		return nil
+	case formatTable:
+		headers, rows := singlePageTable(page)
+		if err := render.Table(cmd.OutOrStdout(), headers, rows); err != nil {
+			return fmt.Errorf("render table: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q (expected json or table)", opts.format)
+	}
+}
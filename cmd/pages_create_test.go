@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+type fakePageCreator struct {
+	ds      notion.DataSource
+	created []notion.CreatePageRequest
+}
+
+func (f *fakePageCreator) GetDataSource(context.Context, string) (notion.DataSource, error) {
+	return f.ds, nil
+}
+
+func (f *fakePageCreator) CreatePage(_ context.Context, req notion.CreatePageRequest) (notion.Page, error) {
+	f.created = append(f.created, req)
+	return notion.Page{ID: "new"}, nil
+}
+
+func TestPagesCreateValidatesAgainstSchema(t *testing.T) {
+	client := &fakePageCreator{ds: notion.DataSource{Name: "Tasks", Properties: map[string]notion.PropertyReference{
+		"Name": {ID: "title", Name: "Name", Type: "title"},
+	}}}
+	opts := &pagesCreateOptions{dataSourceID: "ds", propsPath: "-"}
+	props := `{"Name":{"title":[{"text":{"content":"Launch"}}]},"Stauts":{"select":{"name":"Done"}}}`
+
+	_, err := opts.create(context.Background(), client, strings.NewReader(props), io.Discard)
+	if err == nil || !strings.Contains(err.Error(), `unknown property "Stauts"`) {
+		t.Fatalf("expected a schema error naming the property, got %v", err)
+	}
+	if len(client.created) != 0 {
+		t.Fatalf("invalid properties were sent to Notion")
+	}
+
+	opts.noValidate = true
+	if _, err := opts.create(context.Background(), client, strings.NewReader(props), io.Discard); err != nil {
+		t.Fatalf("create with --no-validate returned error: %v", err)
+	}
+	if len(client.created) != 1 || client.created[0].Parent.DataSourceID != "ds" {
+		t.Fatalf("expected --no-validate to send the page, got %+v", client.created)
+	}
+}
+
+func TestPagesCreateResolvesPropertyIDs(t *testing.T) {
+	client := &fakePageCreator{ds: notion.DataSource{Name: "Tasks", Properties: map[string]notion.PropertyReference{
+		"Name":   {ID: "title", Name: "Name", Type: "title"},
+		"Points": {ID: "pt%3A", Name: "Points", Type: "number"},
+	}}}
+	opts := &pagesCreateOptions{dataSourceID: "ds", propsPath: "-"}
+	props := `{"property_id:title":{"title":[{"text":{"content":"Launch"}}]},"pt%3A":{"number":3}}`
+
+	if _, err := opts.create(context.Background(), client, strings.NewReader(props), io.Discard); err != nil {
+		t.Fatalf("create returned error: %v", err)
+	}
+	got := client.created[0].Properties
+	if len(got) != 2 || got["Name"] == nil || got["Points"] == nil {
+		t.Fatalf("expected properties keyed by name, got %#v", got)
+	}
+
+	dup := `{"Name":{"title":[]},"property_id:title":{"title":[]}}`
+	if _, err := opts.create(context.Background(), client, strings.NewReader(dup), io.Discard); err == nil || !strings.Contains(err.Error(), "both set") {
+		t.Fatalf("expected an error for two keys naming one property, got %v", err)
+	}
+}
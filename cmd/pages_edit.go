@@ -0,0 +1,542 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+	"golang.org/x/term"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/props"
+)
+
+const (
+	editErrorPrefix     = "# error: "
+	editFilePermissions = 0o600
+)
+
+var errEditCancelled = errors.New("edit cancelled")
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type pagesEditOptions struct {
+	format     string
+	dataSource string
+	prompt     bool
+	dryRun     bool
+	exec       executionOptions
+}
+
+// runEditor opens path in the user's editor and waits for it to exit.
+var runEditor = func(ctx context.Context, editor, path string) error {
+	c := shellCommand(ctx, editor+` "`+path+`"`)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return c.Run()
+}
+
+func newPagesEditCmd(globals *globalOptions) *cobra.Command {
+	opts := &pagesEditOptions{format: formatJSON, exec: defaultExecutionOptions()}
+
+	cmd := &cobra.Command{
+		Use:   "edit <page-id|url|unique-id>",
+		Short: "Edit a page's properties in $EDITOR or at interactive prompts",
+		Long: `Edit a page's properties interactively.
+
+With $VISUAL or $EDITOR set, the current values are written to a YAML file and opened in
+the editor; the properties you change are updated when you save and quit. Otherwise, or
+with --prompt, you choose properties from a numbered list and enter new values, which are
+checked against the property type before anything is sent.`,
+		Args: cobra.ExactArgs(1),
+		RunE: opts.run(globals),
+	}
+
+	cmd.Flags().BoolVar(&opts.prompt, "prompt", false, "Edit at prompts even when $EDITOR is set")
+	formatFlag(cmd, &opts.format, "Output format", formatJSON, formatTable)
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print the PATCH request that would be sent without sending it")
+	cmd.Flags().StringVar(
+		&opts.dataSource,
+		"data-source",
+		"",
+		"Data source ID or alias used to resolve unique ID handles such as TASK-123",
+	)
+	opts.exec.register(cmd, "")
+
+	return cmd
+}
+
+func (opts *pagesEditOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		editor := editorCommand()
+		if opts.prompt || editor == "" {
+			if f, ok := cmd.InOrStdin().(*os.File); !ok || !term.IsTerminal(int(f.Fd())) {
+				return errors.New("pages edit needs an interactive terminal on stdin")
+			}
+		}
+
+		client, err := opts.exec.buildClient(globals)
+		if err != nil {
+			return err
+		}
+		if opts.dryRun {
+			client = dryRunClient(client, cmd.OutOrStdout())
+		}
+
+		ctx := cmd.Context()
+		pageID, err := resolvePageRef(ctx, client, globals.profile, args[0], opts.dataSource)
+		if err != nil {
+			return err
+		}
+		page, err := client.RetrievePage(ctx, pageID)
+		if err != nil {
+			return fmt.Errorf("retrieve page: %w", err)
+		}
+		var ds *notion.DataSource
+		if page.Parent.DataSourceID != "" {
+			// The schema only adds status options to validate against, so a failure is not fatal.
+			if got, dsErr := client.GetDataSource(ctx, page.Parent.DataSourceID); dsErr == nil {
+				ds = &got
+			}
+		}
+		fields := editableFields(page, ds)
+		if len(fields) == 0 {
+			return errors.New("page has no properties that can be edited as text")
+		}
+
+		var changes map[string]any
+		if opts.prompt || editor == "" {
+			prompter := &propertyPrompter{in: bufio.NewReader(cmd.InOrStdin()), out: cmd.ErrOrStderr()}
+			changes, err = prompter.edit(page, fields)
+		} else {
+			changes, err = editInEditor(ctx, editor, page, fields)
+		}
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			safeLog(cmd.ErrOrStderr(), "No changes.")
+			return nil
+		}
+
+		updated, err := client.UpdatePage(ctx, pageID, notion.UpdatePageRequest{Properties: changes})
+		if err != nil {
+			return fmt.Errorf("update page: %w", err)
+		}
+		if opts.dryRun {
+			return nil
+		}
+		return (&pagesUpdateOptions{format: opts.format}).renderPage(cmd, updated)
+	}
+}
+
+// editorCommand returns $VISUAL, else $EDITOR, following the usual precedence.
+func editorCommand() string {
+	if editor := strings.TrimSpace(os.Getenv("VISUAL")); editor != "" {
+		return editor
+	}
+	return strings.TrimSpace(os.Getenv("EDITOR"))
+}
+
+// editableField is a page property whose value can be shown and changed as text.
+type editableField struct {
+	ref     notion.PropertyReference
+	value   string
+	options []string
+}
+
+func (f editableField) list() bool {
+	switch f.ref.Type {
+	case "multi_select", relationType, "people":
+		return true
+	}
+	return false
+}
+
+// coerce validates text for the property's type and builds its update payload.
+func (f editableField) coerce(text string) (map[string]any, error) {
+	text = strings.TrimSpace(text)
+	if f.ref.Type == "status" && text != "" && len(f.options) > 0 && !slices.Contains(f.options, text) {
+		return nil, fmt.Errorf("property %q: %q is not a status option (%s)", f.ref.Name, text, strings.Join(f.options, ", "))
+	}
+	return props.Coerce(f.ref, text)
+}
+
+// unchanged reports whether text equals the current value, ignoring list spacing.
+func (f editableField) unchanged(text string) bool {
+	if f.list() {
+		return normalizeEditList(text) == normalizeEditList(f.value)
+	}
+	return strings.TrimSpace(text) == f.value
+}
+
+// editableFields lists the properties props.Coerce can set, title first. Computed
+// properties, files, date ranges, and relations longer than the API returns inline are
+// left out so saving can never truncate them.
+func editableFields(page notion.Page, ds *notion.DataSource) []editableField {
+	names := make([]string, 0, len(page.Properties))
+	for name := range page.Properties {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		aTitle, bTitle := page.Properties[a].Type == "title", page.Properties[b].Type == "title"
+		if aTitle != bTitle {
+			if aTitle {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+
+	fields := make([]editableField, 0, len(names))
+	for _, name := range names {
+		val := page.Properties[name]
+		text, ok := editableText(val)
+		if !ok {
+			continue
+		}
+		field := editableField{ref: notion.PropertyReference{ID: val.ID, Name: name, Type: val.Type}, value: text}
+		if ds != nil && val.Type == "status" {
+			field.options = statusOptions(ds.Properties[name])
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func editableText(val notion.PropertyValue) (string, bool) {
+	switch val.Type {
+	case "title", "rich_text", "number", "checkbox", "select", "status", "multi_select", "url", "email", "phone_number":
+		return summarizeProperty(val), true
+	case "date":
+		if val.Date != nil && val.Date.End != nil && *val.Date.End != "" {
+			return "", false
+		}
+		return summarizeProperty(val), true
+	case relationType:
+		return summarizeRelations(val), !val.HasMore
+	case "people":
+		ids := make([]string, 0, len(val.People))
+		for _, person := range val.People {
+			ids = append(ids, person.ID)
+		}
+		return strings.Join(ids, ", "), true
+	default:
+		return "", false
+	}
+}
+
+func statusOptions(ref notion.PropertyReference) []string {
+	var config struct {
+		Status struct {
+			Options []struct {
+				Name string `json:"name"`
+			} `json:"options"`
+		} `json:"status"`
+	}
+	if len(ref.Raw) == 0 || json.Unmarshal(ref.Raw, &config) != nil {
+		return nil
+	}
+	names := make([]string, 0, len(config.Status.Options))
+	for _, option := range config.Status.Options {
+		names = append(names, option.Name)
+	}
+	return names
+}
+
+func normalizeEditList(text string) string {
+	var items []string
+	for item := range strings.SplitSeq(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return strings.Join(items, ", ")
+}
+
+// editInEditor round-trips the fields through a YAML file in editor. A file that fails to
+// parse is reopened with the error at the top, the way git and kubectl do; saving it
+// unchanged gives up.
+func editInEditor(ctx context.Context, editor string, page notion.Page, fields []editableField) (map[string]any, error) {
+	doc, err := renderEditDocument(page, fields)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp("", "notionctl-edit-*.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("create edit file: %w", err)
+	}
+	path := f.Name()
+	_ = f.Close()
+	defer os.Remove(path) //nolint:errcheck // best-effort cleanup of the temp file
+
+	written := doc
+	var lastErr error
+	for {
+		if err := os.WriteFile(path, written, editFilePermissions); err != nil {
+			return nil, fmt.Errorf("write edit file: %w", err)
+		}
+		if err := runEditor(ctx, editor, path); err != nil {
+			return nil, fmt.Errorf("run editor %q: %w", editor, err)
+		}
+		edited, err := os.ReadFile(path) // #nosec G304 -- path is the temp file created above
+		if err != nil {
+			return nil, fmt.Errorf("read edit file: %w", err)
+		}
+		if bytes.Equal(edited, written) {
+			return nil, lastErr
+		}
+		changes, err := parseEditDocument(edited, fields)
+		if err == nil {
+			return changes, nil
+		}
+		lastErr = err
+		message := editErrorPrefix + strings.ReplaceAll(err.Error(), "\n", " ") + "\n"
+		written = append([]byte(message), stripEditErrors(edited)...)
+	}
+}
+
+func stripEditErrors(data []byte) []byte {
+	for bytes.HasPrefix(data, []byte(editErrorPrefix)) {
+		_, data, _ = bytes.Cut(data, []byte("\n"))
+	}
+	return data
+}
+
+// renderEditDocument writes the fields as a YAML mapping, lists as flow sequences, with
+// each property's type as a line comment.
+func renderEditDocument(page notion.Page, fields []editableField) ([]byte, error) {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, field := range fields {
+		key := &yaml.Node{}
+		key.SetString(field.ref.Name)
+		value := editValueNode(field)
+		value.LineComment = field.ref.Type
+		if len(field.options) > 0 {
+			value.LineComment += ": " + strings.Join(field.options, " | ")
+		}
+		root.Content = append(root.Content, key, value)
+	}
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}
+	doc.HeadComment = fmt.Sprintf(
+		"Editing %q (%s).\nChange values, then save and quit to update the page. Only changed properties are sent.\nLeave a value empty to clear it; lists are comma-separated or [a, b].",
+		pickerTitle(page), page.ID,
+	)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("encode edit file: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encode edit file: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func editValueNode(field editableField) *yaml.Node {
+	switch {
+	case field.list():
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for item := range strings.SplitSeq(normalizeEditList(field.value), ", ") {
+			if item != "" {
+				node := &yaml.Node{}
+				node.SetString(item)
+				seq.Content = append(seq.Content, node)
+			}
+		}
+		return seq
+	case field.value == "":
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}
+	case field.ref.Type == "number", field.ref.Type == "checkbox":
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: field.value}
+	default:
+		node := &yaml.Node{}
+		node.SetString(field.value)
+		return node
+	}
+}
+
+// parseEditDocument returns update payloads for the properties whose values changed.
+// Removing a line leaves that property alone.
+func parseEditDocument(data []byte, fields []editableField) (map[string]any, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse edit file: %w", err)
+	}
+	changes := map[string]any{}
+	if len(doc.Content) == 0 {
+		return changes, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, errors.New("parse edit file: expected one \"Property: value\" line per property")
+	}
+
+	byName := make(map[string]editableField, len(fields))
+	for _, field := range fields {
+		byName[field.ref.Name] = field
+	}
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		field, ok := byName[key.Value]
+		if !ok {
+			return nil, fmt.Errorf("line %d: %q is not an editable property", key.Line, key.Value)
+		}
+		if seen[key.Value] {
+			return nil, fmt.Errorf("line %d: %q appears more than once", key.Line, key.Value)
+		}
+		seen[key.Value] = true
+
+		text, err := editNodeText(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", value.Line, key.Value, err)
+		}
+		if field.unchanged(text) {
+			continue
+		}
+		payload, err := field.coerce(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", value.Line, err)
+		}
+		changes[field.ref.Name] = payload
+	}
+	return changes, nil
+}
+
+func editNodeText(node *yaml.Node) (string, error) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			return "", nil
+		}
+		return node.Value, nil
+	case yaml.SequenceNode:
+		items := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return "", errors.New("list items must be plain values")
+			}
+			items = append(items, item.Value)
+		}
+		return strings.Join(items, ", "), nil
+	default:
+		return "", errors.New("expected a value or a list")
+	}
+}
+
+// propertyPrompter edits fields one at a time at line prompts on a terminal.
+type propertyPrompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// edit lets the user pick properties by number or name and enter new values until a blank
+// line, then confirms the changes. A blank value keeps the current one and ~ clears it.
+func (p *propertyPrompter) edit(page notion.Page, fields []editableField) (map[string]any, error) {
+	safeLog(p.out, "Editing %q (%s)", pickerTitle(page), page.ID)
+	for i, field := range fields {
+		hint := field.ref.Type
+		if len(field.options) > 0 {
+			hint += ": " + strings.Join(field.options, " | ")
+		}
+		safeLog(p.out, "%3d) %s = %s  [%s]", i+1, field.ref.Name, field.value, hint)
+	}
+
+	changes := map[string]any{}
+	for {
+		answer, err := p.prompt("Property to change (number or name, blank to finish): ")
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			break
+		}
+		field, ok := pickEditField(fields, answer)
+		if !ok {
+			safeLog(p.out, "no editable property %q", answer)
+			continue
+		}
+		payload, err := p.promptValue(field)
+		if err != nil {
+			return nil, err
+		}
+		if payload != nil {
+			changes[field.ref.Name] = payload
+		}
+	}
+	if len(changes) == 0 {
+		return changes, nil
+	}
+
+	answer, err := p.prompt(fmt.Sprintf("Apply %d change(s)? [y/N]: ", len(changes)))
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+		return nil, errEditCancelled
+	}
+	return changes, nil
+}
+
+// promptValue asks until the value is valid for the property's type. It returns nil when
+// the value is kept.
+func (p *propertyPrompter) promptValue(field editableField) (map[string]any, error) {
+	for {
+		answer, err := p.prompt(fmt.Sprintf("%s [%s]: ", field.ref.Name, field.value))
+		if err != nil {
+			return nil, err
+		}
+		switch answer {
+		case "":
+			return nil, nil
+		case "~":
+			answer = ""
+		}
+		if field.unchanged(answer) {
+			return nil, nil
+		}
+		payload, err := field.coerce(answer)
+		if err == nil {
+			return payload, nil
+		}
+		safeLog(p.out, "%v", err)
+	}
+}
+
+func (p *propertyPrompter) prompt(label string) (string, error) {
+	if _, err := fmt.Fprint(p.out, label); err != nil {
+		return "", fmt.Errorf("write prompt: %w", err)
+	}
+	line, err := p.in.ReadString('\n')
+	if errors.Is(err, io.EOF) && line == "" {
+		return "", errEditCancelled
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read answer: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func pickEditField(fields []editableField, answer string) (editableField, bool) {
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(fields) {
+		return fields[n-1], true
+	}
+	for _, field := range fields {
+		if strings.EqualFold(field.ref.Name, answer) {
+			return field, true
+		}
+	}
+	return editableField{}, false
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func editTestPage() (notion.Page, *notion.DataSource) {
+	points, done := 3.0, false
+	end := "2025-01-05"
+	page := notion.Page{ID: "p1", Properties: map[string]notion.PropertyValue{
+		"Name":    {ID: "title", Type: "title", Title: []notion.RichText{{PlainText: "Fix login"}}},
+		"Status":  {ID: "st", Type: "status", Status: &notion.StatusValue{Name: "Open"}},
+		"Tags":    {ID: "tg", Type: "multi_select", MultiSelect: []notion.SelectValue{{Name: "bug"}, {Name: "auth"}}},
+		"Points":  {ID: "pt", Type: "number", Number: &points},
+		"Done":    {ID: "dn", Type: "checkbox", Checkbox: &done},
+		"Sprint":  {ID: "sp", Type: "date", Date: &notion.DateValue{Start: "2025-01-01", End: &end}},
+		"Formula": {ID: "fx", Type: "formula"},
+	}}
+	ds := &notion.DataSource{Properties: map[string]notion.PropertyReference{
+		"Status": {Raw: json.RawMessage(`{"type":"status","status":{"options":[{"name":"Open"},{"name":"Done"}]}}`)},
+	}}
+	return page, ds
+}
+
+func TestEditableFields(t *testing.T) {
+	t.Parallel()
+
+	page, ds := editTestPage()
+	fields := editableFields(page, ds)
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, field.ref.Name)
+	}
+	if got := strings.Join(names, ","); got != "Name,Done,Points,Status,Tags" {
+		t.Fatalf("fields = %s, want title first and no date ranges or formulas", got)
+	}
+	if fields[3].value != "Open" || strings.Join(fields[3].options, ",") != "Open,Done" {
+		t.Fatalf("status field = %+v", fields[3])
+	}
+}
+
+func TestParseEditDocument(t *testing.T) {
+	t.Parallel()
+
+	page, ds := editTestPage()
+	fields := editableFields(page, ds)
+	doc, err := renderEditDocument(page, fields)
+	if err != nil {
+		t.Fatalf("renderEditDocument returned error: %v", err)
+	}
+	for _, want := range []string{`# Editing "Fix login" (p1).`, "Points: 3 # number", "Tags: [bug, auth]", "Status: Open # status: Open | Done"} {
+		if !strings.Contains(string(doc), want) {
+			t.Fatalf("edit document missing %q:\n%s", want, doc)
+		}
+	}
+
+	changes, err := parseEditDocument(doc, fields)
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("unchanged document gave %v, %v", changes, err)
+	}
+
+	edited := strings.NewReplacer("Points: 3", "Points:", "Tags: [bug, auth]", "Tags: auth,bug,ui", "Status: Open", "Status: Done").Replace(string(doc))
+	changes, err = parseEditDocument([]byte(edited), fields)
+	if err != nil {
+		t.Fatalf("parseEditDocument returned error: %v", err)
+	}
+	got, _ := json.Marshal(changes)
+	want := `{"Points":{"number":null},"Status":{"status":{"name":"Done"}},"Tags":{"multi_select":[{"name":"auth"},{"name":"bug"},{"name":"ui"}]}}`
+	if string(got) != want {
+		t.Fatalf("changes = %s, want %s", got, want)
+	}
+
+	for _, bad := range []string{"Status: Closed\n", "Points: many\n", "Formula: x\n", "Name: a\nName: b\n", "- a\n"} {
+		if _, err := parseEditDocument([]byte(bad), fields); err == nil {
+			t.Errorf("parseEditDocument(%q) expected error", bad)
+		}
+	}
+}
+
+func TestEditInEditorReopensOnError(t *testing.T) {
+	orig := runEditor
+	t.Cleanup(func() { runEditor = orig })
+
+	page, ds := editTestPage()
+	fields := editableFields(page, ds)
+	var rounds []string
+	runEditor = func(_ context.Context, editor, path string) error {
+		if editor != "vi" {
+			t.Fatalf("editor = %q", editor)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rounds = append(rounds, string(data))
+		next := strings.Replace(string(data), "Points: 3", "Points: lots", 1)
+		if len(rounds) == 2 {
+			next = strings.Replace(string(data), "Points: lots", "Points: 5", 1)
+		}
+		return os.WriteFile(path, []byte(next), 0o600)
+	}
+
+	changes, err := editInEditor(context.Background(), "vi", page, fields)
+	if err != nil {
+		t.Fatalf("editInEditor returned error: %v", err)
+	}
+	if len(rounds) != 2 || !strings.HasPrefix(rounds[1], editErrorPrefix+"line ") {
+		t.Fatalf("expected the editor to reopen with the error, got %q", rounds)
+	}
+	if got, _ := json.Marshal(changes); string(got) != `{"Points":{"number":5}}` {
+		t.Fatalf("changes = %s", got)
+	}
+
+	runEditor = func(context.Context, string, string) error { return nil }
+	if changes, err := editInEditor(context.Background(), "vi", page, fields); err != nil || len(changes) != 0 {
+		t.Fatalf("unchanged save gave %v, %v", changes, err)
+	}
+}
+
+func TestPropertyPrompter(t *testing.T) {
+	t.Parallel()
+
+	page, ds := editTestPage()
+	fields := editableFields(page, ds)
+	input := "status\nClosed\nDone\n3\nnope\n5\nfoo\n\ny\n"
+	prompter := &propertyPrompter{in: bufio.NewReader(strings.NewReader(input)), out: io.Discard}
+	changes, err := prompter.edit(page, fields)
+	if err != nil {
+		t.Fatalf("edit returned error: %v", err)
+	}
+	got, _ := json.Marshal(changes)
+	if string(got) != `{"Points":{"number":5},"Status":{"status":{"name":"Done"}}}` {
+		t.Fatalf("changes = %s", got)
+	}
+
+	prompter = &propertyPrompter{in: bufio.NewReader(strings.NewReader("1\nNew title\n\nn\n")), out: io.Discard}
+	if _, err := prompter.edit(page, fields); err == nil {
+		t.Fatal("expected declining the confirmation to cancel")
+	}
+}
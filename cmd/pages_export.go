@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/filelock"
+	"github.com/yourorg/notionctl/internal/markdown"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+type pagesExportOptions struct {
+	markdownPath string
+	dataSource   string
+	frontmatter  bool
+}
+
+// pageExportClient is the subset of the Notion client used to export a page.
+type pageExportClient interface {
+	blockReader
+	RetrievePage(ctx context.Context, pageID string) (notion.Page, error)
+	GetDataSource(ctx context.Context, dataSourceID string) (notion.DataSource, error)
+}
+
+func newPagesExportCmd(globals *globalOptions) *cobra.Command {
+	opts := &pagesExportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "export <page-id|url|unique-id>",
+		Short: "Export a page's content as Markdown",
+		Args:  cobra.ExactArgs(1),
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().StringVar(&opts.markdownPath, "md", "", "Write the Markdown to this file instead of stdout")
+	cmd.Flags().BoolVar(
+		&opts.frontmatter,
+		"frontmatter",
+		false,
+		"Start the document with YAML frontmatter holding the page's title, Notion metadata, and properties",
+	)
+	cmd.Flags().StringVar(
+		&opts.dataSource,
+		"data-source",
+		"",
+		"Data source ID or alias used to resolve unique ID handles such as TASK-123",
+	)
+
+	return cmd
+}
+
+func (opts *pagesExportOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		client, err := buildClient(globals)
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		pageID, err := resolvePageRef(ctx, client, globals.profile, args[0], opts.dataSource)
+		if err != nil {
+			return err
+		}
+
+		render := pageMarkdown
+		if opts.frontmatter {
+			render = pageMarkdownDocument
+		}
+		doc, err := render(ctx, client, pageID)
+		if err != nil {
+			return err
+		}
+		return writeMarkdown(cmd.OutOrStdout(), opts.markdownPath, doc)
+	}
+}
+
+// pageMarkdown renders the content of pageID, including nested blocks, as Markdown.
+func pageMarkdown(ctx context.Context, client pageExportClient, pageID string) ([]byte, error) {
+	blocks, err := fetchBlockTree(ctx, client, pageID)
+	if err != nil {
+		return nil, fmt.Errorf("read page %s: %w", pageID, err)
+	}
+	return []byte(markdown.FromBlocks(blocks)), nil
+}
+
+// pageMarkdownDocument renders pageID like sync export-md: frontmatter with the page's
+// properties under their schema names, then the content. Pages outside a data source, or
+// whose schema the token cannot read, use the property names on the page itself.
+func pageMarkdownDocument(ctx context.Context, client pageExportClient, pageID string) ([]byte, error) {
+	page, err := client.RetrievePage(ctx, pageID)
+	if err != nil {
+		return nil, fmt.Errorf("retrieve page: %w", err)
+	}
+	idx, err := pageSchemaIndex(ctx, client, page)
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := fetchBlockTree(ctx, client, pageID)
+	if err != nil {
+		return nil, fmt.Errorf("read page %s: %w", pageID, err)
+	}
+	return markdownDocument(page, idx, blocks)
+}
+
+func pageSchemaIndex(ctx context.Context, client pageExportClient, page notion.Page) (*schema.Index, error) {
+	if id := page.Parent.DataSourceID; id != "" {
+		ds, err := client.GetDataSource(ctx, id)
+		if err == nil {
+			return schema.NewIndex(ds), nil
+		}
+		if !schemaUnavailable(err) {
+			return nil, fmt.Errorf("get data source: %w", err)
+		}
+	}
+	refs := make(map[string]notion.PropertyReference, len(page.Properties))
+	for name, value := range page.Properties {
+		refs[name] = notion.PropertyReference{ID: value.ID, Name: name, Type: value.Type}
+	}
+	return schema.NewIndex(notion.DataSource{Properties: refs}), nil
+}
+
+// writeMarkdown writes doc to path, or to stdout when path is empty or "-".
+func writeMarkdown(stdout io.Writer, path string, doc []byte) error {
+	if path == "" || path == "-" {
+		if _, err := stdout.Write(doc); err != nil {
+			return fmt.Errorf("write markdown: %w", err)
+		}
+		return nil
+	}
+	return filelock.WriteFile(path, doc, markdownFilePermissions)
+}
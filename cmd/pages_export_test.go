@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestPageMarkdownIncludesNestedBlocks(t *testing.T) {
+	rich := func(s string) []notion.RichText { return []notion.RichText{{PlainText: s}} }
+	client := &fakeMarkdownClient{blocks: map[string][]notion.Block{
+		"page-1": {
+			{Type: "heading_2", Heading2: &notion.HeadingBlock{RichText: rich("Notes")}},
+			{ID: "toggle-1", Type: "toggle", HasChildren: true, Toggle: &notion.ToggleBlock{RichText: rich("Details")}},
+		},
+		"toggle-1": {
+			{Type: "to_do", ToDo: &notion.ToDoBlock{RichText: rich("Write docs")}},
+		},
+	}}
+
+	doc, err := pageMarkdown(context.Background(), client, "page-1")
+	if err != nil {
+		t.Fatalf("pageMarkdown returned error: %v", err)
+	}
+	want := "## Notes\n\n<details>\n<summary>Details</summary>\n\n- [ ] Write docs\n\n</details>\n"
+	if string(doc) != want {
+		t.Fatalf("markdown mismatch:\n%s\nwant:\n%s", doc, want)
+	}
+
+	var stdout bytes.Buffer
+	if err := writeMarkdown(&stdout, "", doc); err != nil || stdout.String() != want {
+		t.Fatalf("writeMarkdown to stdout = %q, %v", stdout.String(), err)
+	}
+	path := filepath.Join(t.TempDir(), "page.md")
+	if err := writeMarkdown(&stdout, path, doc); err != nil {
+		t.Fatalf("writeMarkdown returned error: %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != want {
+		t.Fatalf("exported file = %q, %v", data, err)
+	}
+}
+
+func TestPageMarkdownDocumentWritesPropertyFrontmatter(t *testing.T) {
+	page := notion.Page{
+		ID:     "page-1",
+		Parent: notion.DataSourceParent("ds"),
+		Properties: map[string]notion.PropertyValue{
+			"Name":   {ID: "title", Type: "title", Title: []notion.RichText{{PlainText: "Launch plan"}}},
+			"Status": {ID: "st", Type: "status", Status: &notion.StatusValue{Name: "Open"}},
+		},
+	}
+	client := &fakeMarkdownClient{
+		fakeMirrorClient: fakeMirrorClient{
+			ds: notion.DataSource{Properties: map[string]notion.PropertyReference{
+				"Name":   {ID: "title", Name: "Name", Type: "title"},
+				"Status": {ID: "st", Name: "Status", Type: "status"},
+			}},
+			pages: []notion.Page{page},
+		},
+		blocks: map[string][]notion.Block{
+			"page-1": {{Type: "paragraph", Paragraph: &notion.ParagraphBlock{RichText: []notion.RichText{{PlainText: "Body"}}}}},
+		},
+	}
+
+	doc, err := pageMarkdownDocument(context.Background(), client, "page-1")
+	if err != nil {
+		t.Fatalf("pageMarkdownDocument returned error: %v", err)
+	}
+	for _, want := range []string{"---\ntitle: Launch plan\n", "notion_id: page-1\n", "Status: Open\n", "---\n\nBody\n"} {
+		if !strings.Contains(string(doc), want) {
+			t.Fatalf("document missing %q:\n%s", want, doc)
+		}
+	}
+}
+
+func TestReadPageStartsWithTitle(t *testing.T) {
+	page := notion.Page{ID: "page-1", Properties: map[string]notion.PropertyValue{
+		"Name": {Type: "title", Title: []notion.RichText{{PlainText: "Launch plan"}}},
+	}}
+	client := &fakeMarkdownClient{
+		fakeMirrorClient: fakeMirrorClient{pages: []notion.Page{page}},
+		blocks: map[string][]notion.Block{
+			"page-1": {{Type: "paragraph", Paragraph: &notion.ParagraphBlock{RichText: []notion.RichText{{PlainText: "Body"}}}}},
+		},
+	}
+
+	got, err := readPage(context.Background(), client, "page-1", false)
+	if err != nil || got != "# Launch plan\n\nBody\n" {
+		t.Fatalf("readPage = %q, %v", got, err)
+	}
+	if got, err = readPage(context.Background(), client, "page-1", true); err != nil || !strings.Contains(got, "\x1b[") {
+		t.Fatalf("expected styled output, got %q, %v", got, err)
+	}
+}
@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"sort"
+	"io"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -11,45 +13,82 @@ import (
 	"github.com/yourorg/notionctl/internal/expand"
 	"github.com/yourorg/notionctl/internal/notion"
 	"github.com/yourorg/notionctl/internal/render"
+	"github.com/yourorg/notionctl/internal/schema"
 )
 
 type pagesGetOptions struct {
-	format      string
-	expandProps []string
+	format       string
+	dataSource   string
+	expandProps  []string
+	expandFields []string
+	expandPeople bool
+	expandInline bool
+	exec         executionOptions
 }
 
 func newPagesGetCmd(globals *globalOptions) *cobra.Command {
-	opts := &pagesGetOptions{format: formatJSON}
+	opts := &pagesGetOptions{format: formatJSON, exec: defaultExecutionOptions()}
 
 	cmd := &cobra.Command{
-		Use:   "get <page-id>",
+		Use:   "get <page-id|url|unique-id>",
 		Short: "Retrieve a Notion page",
 		Args:  cobra.ExactArgs(1),
 		RunE:  opts.run(globals),
 	}
 
-	cmd.Flags().StringVar(&opts.format, "format", opts.format, "Output format: json|table")
-	cmd.Flags().StringSliceVar(&opts.expandProps, "expand", nil, "Relation property names to expand")
+	formatFlag(cmd, &opts.format, "Output format", formatJSON, formatTable)
+	cmd.Flags().StringSliceVar(&opts.expandProps, "expand", nil, "Relation or rollup property names to expand")
+	cmd.Flags().StringSliceVar(
+		&opts.expandFields,
+		"expand-fields",
+		nil,
+		`Properties to keep on expanded pages, e.g. "Name,Status" ("title" keeps the title)`,
+	)
+	cmd.Flags().BoolVar(
+		&opts.expandPeople,
+		"expand-people",
+		false,
+		"Resolve people, created_by, and last_edited_by users to full user objects with names and emails",
+	)
+	cmd.Flags().BoolVar(
+		&opts.expandInline,
+		"expand-inline",
+		false,
+		"Embed expanded pages in the relation values (relation[].page) instead of expanded_relations",
+	)
+	cmd.Flags().StringVar(
+		&opts.dataSource,
+		"data-source",
+		"",
+		"Data source ID or alias used to resolve unique ID handles such as TASK-123",
+	)
+	opts.exec.register(cmd, "")
 
 	return cmd
 }
 
 func (opts *pagesGetOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, args []string) error {
-		pageID := args[0]
-
-		client, err := buildClient(globals.profile)
+		if (len(opts.expandFields) > 0 || opts.expandInline) && len(opts.expandProps) == 0 {
+			return errors.New("--expand-fields and --expand-inline require --expand")
+		}
+		client, err := opts.exec.buildClient(globals)
 		if err != nil {
 			return err
 		}
 
 		ctx := cmd.Context()
+		pageID, err := resolvePageRef(ctx, client, globals.profile, args[0], opts.dataSource)
+		if err != nil {
+			return err
+		}
+
 		page, err := opts.fetchPage(ctx, client, pageID)
 		if err != nil {
 			return err
 		}
 
-		page, err = opts.expandPage(ctx, client, page)
+		page, err = opts.expandPage(ctx, client, page, cmd.ErrOrStderr())
 		if err != nil {
 			return err
 		}
@@ -68,9 +107,17 @@ func (opts *pagesGetOptions) fetchPage(ctx context.Context, client *notion.Clien
 
 func (opts *pagesGetOptions) expandPage(
 	ctx context.Context,
-	client expand.PageFetcher,
+	client expand.Fetcher,
 	page notion.Page,
+	log io.Writer,
 ) (notion.Page, error) {
+	if opts.expandPeople {
+		pages := []notion.Page{page}
+		if err := expand.People(ctx, client, pages, opts.exec.concurrency, log); err != nil {
+			return notion.Page{}, err
+		}
+		page = pages[0]
+	}
 	if len(opts.expandProps) == 0 {
 		return page, nil
 	}
@@ -78,16 +125,20 @@ func (opts *pagesGetOptions) expandPage(
 	if err != nil {
 		return notion.Page{}, err
 	}
-	if err := expand.FirstLevel(ctx, client, pages, refs); err != nil {
+	if err := expand.FirstLevel(ctx, client, pages, refs, opts.exec.concurrency); err != nil {
 		return notion.Page{}, fmt.Errorf("expand relations: %w", err)
 	}
+	expand.Project(pages, opts.expandFields)
+	if opts.expandInline {
+		expand.Inline(pages)
+	}
 	return pages[0], nil
 }
 
 func (opts *pagesGetOptions) renderPage(cmd *cobra.Command, page notion.Page) error {
 	switch opts.format {
 	case formatJSON:
-		if err := render.JSON(cmd.OutOrStdout(), page); err != nil {
+		if err := writeJSON(cmd.Context(), cmd.OutOrStdout(), page); err != nil {
 			return fmt.Errorf("render json: %w", err)
 		}
 		return nil
@@ -104,19 +155,42 @@ func (opts *pagesGetOptions) renderPage(cmd *cobra.Command, page notion.Page) er
 
 func preparePageExpansion(page notion.Page, names []string) ([]notion.Page, []notion.PropertyReference, error) {
 	refs := make([]notion.PropertyReference, 0, len(names))
-	for _, name := range names {
-		prop, ok := page.Properties[name]
+	for _, ref := range names {
+		name, prop, ok := pageProperty(page, ref)
 		if !ok {
-			return nil, nil, fmt.Errorf("unknown property %q", name)
+			return nil, nil, fmt.Errorf("unknown property %q", ref)
 		}
-		if prop.Type != relationType {
-			return nil, nil, fmt.Errorf("property %q is not a relation", name)
+		if !expand.Expandable(prop.Type) {
+			return nil, nil, fmt.Errorf("property %q is not a relation or rollup", name)
 		}
 		refs = append(refs, notion.PropertyReference{ID: prop.ID, Name: name, Type: prop.Type})
 	}
 	return []notion.Page{page}, refs, nil
 }
 
+// pageProperty finds a property on page by name, raw property ID, or property_id: prefix,
+// mirroring schema.Index lookups for commands that work from a page without its schema.
+func pageProperty(page notion.Page, ref string) (string, notion.PropertyValue, bool) {
+	id, prefixed := schema.ParsePropertyID(ref)
+	if !prefixed {
+		if value, ok := page.Properties[ref]; ok {
+			return ref, value, true
+		}
+		for _, name := range render.SortedKeys(page.Properties) {
+			if strings.EqualFold(name, strings.TrimSpace(ref)) {
+				return name, page.Properties[name], true
+			}
+		}
+		id = strings.TrimSpace(ref)
+	}
+	for name, value := range page.Properties {
+		if value.ID != "" && value.ID == id {
+			return name, value, true
+		}
+	}
+	return "", notion.PropertyValue{}, false
+}
+
 func singlePageTable(page notion.Page) ([]string, [][]string) {
 	headers := []string{"Field", "Value"}
 	rows := [][]string{
@@ -127,14 +201,8 @@ func singlePageTable(page notion.Page) ([]string, [][]string) {
 		rows = append(rows, []string{"Last Edited", page.LastEditedTime.UTC().Format(time.RFC3339)})
 	}
 
-	names := make([]string, 0, len(page.Properties))
-	for name := range page.Properties {
-		names = append(names, name)
-	}
-	sort.Strings(names)
-
-	for _, name := range names {
-		rows = append(rows, []string{name, summarizeProperty(page.Properties[name])})
+	for _, name := range render.SortedKeys(page.Properties) {
+		rows = append(rows, []string{name, summarizePageProperty(page, name)})
 	}
 
 	return headers, rows
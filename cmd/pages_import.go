@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/filelock"
+	"github.com/yourorg/notionctl/internal/markdown"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+// markdownImportManifest records, inside the import directory, which page each file created.
+const markdownImportManifest = ".notionctl-import.json"
+
+type pagesImportOptions struct {
+	dataSourceID string
+	dir          string
+	force        bool
+}
+
+// markdownImportClient adds page creation to the writes used by sync export-md --push.
+type markdownImportClient interface {
+	markdownSyncClient
+	CreatePage(ctx context.Context, req notion.CreatePageRequest) (notion.Page, error)
+}
+
+// importManifest is persisted as markdownImportManifest. Files are keyed by their
+// slash-separated path relative to the directory; Hash is the SHA-256 of the file as last
+// imported.
+type importManifest struct {
+	Files        map[string]importManifestEntry `json:"files"`
+	DataSourceID string                         `json:"data_source_id"`
+}
+
+type importManifestEntry struct {
+	PageID string `json:"page_id"`
+	Hash   string `json:"hash"`
+}
+
+// markdownImportSummary tallies the outcome of an import run.
+type markdownImportSummary struct {
+	Files     int
+	Created   int
+	Updated   int
+	Unchanged int
+}
+
+func (s markdownImportSummary) String() string {
+	return fmt.Sprintf("%d files: %d created, %d updated, %d unchanged", s.Files, s.Created, s.Updated, s.Unchanged)
+}
+
+func newPagesImportCmd(globals *globalOptions) *cobra.Command {
+	opts := &pagesImportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Create a data source page from each Markdown file in a directory",
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Target Notion data source ID or URL")
+	cmd.Flags().StringVar(&opts.dir, "dir", "", "Directory of Markdown files to import")
+	cmd.Flags().BoolVar(
+		&opts.force,
+		"force",
+		false,
+		"Update edited pages even when that deletes blocks Markdown cannot represent",
+	)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
+	cobra.CheckErr(cmd.MarkFlagRequired("dir"))
+
+	return cmd
+}
+
+func (opts *pagesImportOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		client, err := buildClient(globals)
+		if err != nil {
+			return err
+		}
+		summary, err := opts.importDir(cmd.Context(), client)
+		if _, werr := fmt.Fprintf(cmd.OutOrStdout(), "Imported %s\n", summary); werr != nil && err == nil {
+			err = fmt.Errorf("write summary: %w", werr)
+		}
+		return err
+	}
+}
+
+// importDir creates a page for every Markdown file the manifest does not know yet and
+// updates the pages of files edited since the last run. The manifest is saved after each
+// file, so an interrupted run resumes where it stopped.
+func (opts *pagesImportOptions) importDir(
+	ctx context.Context,
+	client markdownImportClient,
+) (markdownImportSummary, error) {
+	var summary markdownImportSummary
+	manifest, err := loadImportManifest(opts.dir)
+	if err != nil {
+		return summary, err
+	}
+	if manifest.DataSourceID != "" && manifest.DataSourceID != opts.dataSourceID {
+		return summary, fmt.Errorf("%s was imported into data source %s; import into it or remove %s",
+			opts.dir, manifest.DataSourceID, markdownImportManifest)
+	}
+	manifest.DataSourceID = opts.dataSourceID
+
+	ds, err := client.GetDataSource(ctx, opts.dataSourceID)
+	if err != nil {
+		return summary, fmt.Errorf("get data source: %w", err)
+	}
+	idx := schema.NewIndex(ds)
+
+	files, err := markdownFiles(opts.dir)
+	if err != nil {
+		return summary, err
+	}
+	for _, rel := range files {
+		summary.Files++
+		data, err := os.ReadFile(filepath.Join(opts.dir, filepath.FromSlash(rel))) // #nosec G304 -- file inside the import directory
+		if err != nil {
+			return summary, fmt.Errorf("read %s: %w", rel, err)
+		}
+		hash := contentHash(data)
+		entry, known := manifest.Files[rel]
+		switch {
+		case known && entry.Hash == hash:
+			summary.Unchanged++
+			continue
+		case known:
+			page, err := client.RetrievePage(ctx, entry.PageID)
+			if err != nil {
+				return summary, fmt.Errorf("retrieve page for %s: %w", rel, err)
+			}
+			err = pushMarkdownPage(ctx, client, idx, page, data, opts.force)
+			if errors.Is(err, errUnrepresentable) {
+				return summary, fmt.Errorf("update %s: %w; rerun with --force to replace them", rel, err)
+			}
+			if err != nil {
+				return summary, fmt.Errorf("update %s: %w", rel, err)
+			}
+			summary.Updated++
+		default:
+			page, err := createMarkdownPage(ctx, client, opts.dataSourceID, idx, rel, data)
+			if err != nil {
+				if page.ID != "" {
+					// Remember the partly written page so the next run updates it
+					// instead of creating another.
+					manifest.Files[rel] = importManifestEntry{PageID: page.ID}
+					err = errors.Join(err, manifest.save(opts.dir))
+				}
+				return summary, fmt.Errorf("import %s: %w", rel, err)
+			}
+			entry.PageID = page.ID
+			summary.Created++
+		}
+		entry.Hash = hash
+		manifest.Files[rel] = entry
+		if err := manifest.save(opts.dir); err != nil {
+			return summary, err
+		}
+	}
+	return summary, nil
+}
+
+// createMarkdownPage creates a page from a Markdown file. The title comes from the title
+// frontmatter key, else the file name.
+func createMarkdownPage(
+	ctx context.Context,
+	client markdownImportClient,
+	dataSourceID string,
+	idx *schema.Index,
+	rel string,
+	data []byte,
+) (notion.Page, error) {
+	fields, body, err := markdown.Parse(data)
+	if err != nil {
+		return notion.Page{}, err
+	}
+	if fields == nil {
+		fields = map[string]any{}
+	}
+	if _, ok := fields[frontmatterTitle]; !ok {
+		fields[frontmatterTitle] = strings.TrimSuffix(filepath.Base(filepath.FromSlash(rel)), filepath.Ext(rel))
+	}
+	properties, err := frontmatterProperties(idx, fields)
+	if err != nil {
+		return notion.Page{}, err
+	}
+	blocks, err := markdown.ToBlocks(body)
+	if err != nil {
+		return notion.Page{}, err
+	}
+
+	first := blocks[:min(len(blocks), maxAppendBlocks)]
+	page, err := client.CreatePage(ctx, notion.CreatePageRequest{
+		Parent:     notion.DataSourceParent(dataSourceID),
+		Properties: properties,
+		Children:   first,
+	})
+	if err != nil {
+		return notion.Page{}, fmt.Errorf("create page: %w", err)
+	}
+	if err := appendBlocks(ctx, client, page.ID, blocks[len(first):]); err != nil {
+		return page, err
+	}
+	return page, nil
+}
+
+// frontmatterProperties maps frontmatter keys onto the writable properties of idx, using
+// the keys sync export-md writes. Other keys, including the Notion metadata, are ignored.
+func frontmatterProperties(idx *schema.Index, fields map[string]any) (map[string]any, error) {
+	properties := map[string]any{}
+	for _, name := range idx.PropertyNames() {
+		ref, _ := idx.ReferenceForName(name)
+		key := frontmatterKey(name)
+		if ref.Type == "title" {
+			key = frontmatterTitle
+		} else if !pushableTypes[ref.Type] {
+			continue
+		}
+		value, ok := fields[key]
+		if !ok {
+			continue
+		}
+		payload, err := frontmatterPayload(ref, value)
+		if err != nil {
+			return nil, err
+		}
+		properties[ref.Name] = payload
+	}
+	return properties, nil
+}
+
+// markdownFiles lists the .md files under dir as sorted slash-separated relative paths,
+// skipping hidden files and directories.
+func markdownFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".md") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %w", dir, err)
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+func loadImportManifest(dir string) (*importManifest, error) {
+	manifest := &importManifest{Files: map[string]importManifestEntry{}}
+	data, err := os.ReadFile(filepath.Join(dir, markdownImportManifest)) // #nosec G304 -- manifest inside the user's import directory
+	if errors.Is(err, fs.ErrNotExist) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read import manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("decode import manifest: %w", err)
+	}
+	if manifest.Files == nil {
+		manifest.Files = map[string]importManifestEntry{}
+	}
+	return manifest, nil
+}
+
+func (m *importManifest) save(dir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode import manifest: %w", err)
+	}
+	path := filepath.Join(dir, markdownImportManifest)
+	return filelock.With(path, func() error {
+		return filelock.WriteFile(path, append(data, '\n'), markdownFilePermissions)
+	})
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+type fakeMarkdownImportClient struct {
+	fakeMarkdownClient
+	created []notion.CreatePageRequest
+}
+
+func (f *fakeMarkdownImportClient) CreatePage(_ context.Context, req notion.CreatePageRequest) (notion.Page, error) {
+	f.created = append(f.created, req)
+	page := notion.Page{ID: fmt.Sprintf("page-%d", len(f.created)), Properties: map[string]notion.PropertyValue{}}
+	f.pages = append(f.pages, page)
+	f.blocks[page.ID] = nil
+	if _, err := f.AppendBlockChildren(context.Background(), page.ID, req.Children); err != nil {
+		return notion.Page{}, err
+	}
+	return page, nil
+}
+
+func TestPagesImportCreatesPagesOnceAndUpdatesEditedFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("launch.md", "---\ntitle: Launch plan\nStatus: Open\nnotion_id: ignored\n---\n\nShip it.\n")
+	write("notes/Retro.md", "Went well.\n")
+	write(".drafts/skip.md", "Hidden.\n")
+	write("readme.txt", "Not Markdown.\n")
+
+	client := &fakeMarkdownImportClient{fakeMarkdownClient: fakeMarkdownClient{
+		fakeMirrorClient: fakeMirrorClient{ds: notion.DataSource{Properties: map[string]notion.PropertyReference{
+			"Name":   {ID: "title", Name: "Name", Type: "title"},
+			"Status": {ID: "st", Name: "Status", Type: "status"},
+		}}},
+		blocks: map[string][]notion.Block{},
+	}}
+	opts := &pagesImportOptions{dataSourceID: "ds", dir: dir}
+
+	summary, err := opts.importDir(context.Background(), client)
+	if err != nil {
+		t.Fatalf("importDir returned error: %v", err)
+	}
+	if summary.Files != 2 || summary.Created != 2 || len(client.created) != 2 {
+		t.Fatalf("unexpected first run: %+v, %d creates", summary, len(client.created))
+	}
+	launch := client.created[0]
+	if launch.Parent.DataSourceID != "ds" || len(launch.Children) != 1 {
+		t.Fatalf("unexpected create request: %+v", launch)
+	}
+	if _, ok := launch.Properties["Status"]; !ok || len(launch.Properties) != 2 {
+		t.Fatalf("expected title and Status properties, got %v", launch.Properties)
+	}
+	if title := fmt.Sprint(client.created[1].Properties["Name"]); !strings.Contains(title, "Retro") {
+		t.Fatalf("expected the file name as the title, got %s", title)
+	}
+
+	// A rerun without edits writes nothing; an edited file updates its page in place.
+	if summary, err = opts.importDir(context.Background(), client); err != nil || summary.Unchanged != 2 {
+		t.Fatalf("second run = %+v, %v", summary, err)
+	}
+	write("notes/Retro.md", "Went very well.\n")
+	if summary, err = opts.importDir(context.Background(), client); err != nil || summary.Updated != 1 {
+		t.Fatalf("third run = %+v, %v", summary, err)
+	}
+	if len(client.created) != 2 || len(client.deleted) != 1 {
+		t.Fatalf("expected the edit to replace content without creating a page: %d creates, %v deleted",
+			len(client.created), client.deleted)
+	}
+
+	// Blocks the Markdown cannot bring back stop an update unless it is forced.
+	client.blocks["page-2"] = append(client.blocks["page-2"], notion.Block{ID: "cols", Type: "column_list"})
+	write("notes/Retro.md", "Went well again.\n")
+	if _, err := opts.importDir(context.Background(), client); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected the update to be refused, got %v", err)
+	}
+	opts.force = true
+	if summary, err = opts.importDir(context.Background(), client); err != nil || summary.Updated != 1 {
+		t.Fatalf("forced run = %+v, %v", summary, err)
+	}
+
+	opts.dataSourceID = "other"
+	if _, err := opts.importDir(context.Background(), client); err == nil {
+		t.Fatal("expected importing into a different data source to fail")
+	}
+}
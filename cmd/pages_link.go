@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/notionid"
+)
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type pagesLinkOptions struct {
+	mapPath  string
+	property string
+	exec     executionOptions
+	retry    retryOptions
+	dryRun   bool
+}
+
+// linkClient is the subset of the Notion client used to link pages in bulk.
+type linkClient interface {
+	relationClient
+	RetrievePage(ctx context.Context, pageID string) (notion.Page, error)
+	UpdatePage(ctx context.Context, pageID string, req notion.UpdatePageRequest) (notion.Page, error)
+}
+
+// linkSource collects every relation to add to one source page, keeping the first mapping
+// line that mentions it for error reports and its position among the source pages.
+type linkSource struct {
+	pageID    string
+	additions []relationAddition
+	line      int
+	index     int
+}
+
+// linkPayload is how a failed source page is kept in the failures file.
+type linkPayload struct {
+	Source string       `json:"source"`
+	Links  []linkTarget `json:"links"`
+}
+
+// linkTarget is one relation to add in a linkPayload.
+type linkTarget struct {
+	Property string `json:"property"`
+	Target   string `json:"target"`
+}
+
+// linkSummary tallies the outcome of a bulk link.
+type linkSummary struct {
+	Pages     int
+	Linked    int
+	Unchanged int
+	Failed    int
+}
+
+func (s linkSummary) String() string {
+	return fmt.Sprintf("%d pages: %d linked, %d unchanged, %d failed", s.Pages, s.Linked, s.Unchanged, s.Failed)
+}
+
+func newPagesLinkCmd(globals *globalOptions) *cobra.Command {
+	opts := &pagesLinkOptions{exec: defaultExecutionOptions()}
+
+	cmd := &cobra.Command{
+		Use:   "link",
+		Short: "Add relations in bulk from a CSV of source page, target page, and relation property",
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().StringVar(
+		&opts.mapPath,
+		"map",
+		"",
+		"Path to a CSV with source, target, and optional property columns (- for stdin)",
+	)
+	cmd.Flags().StringVar(&opts.property, "property", "", "Relation property for rows without a property column")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print each PATCH request that would be sent without sending it")
+	opts.exec.register(cmd, "")
+	opts.retry.register(cmd, "pages link", "map")
+
+	return cmd
+}
+
+func (opts *pagesLinkOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		client, err := opts.exec.buildClient(globals)
+		if err != nil {
+			return err
+		}
+		if opts.dryRun {
+			client = dryRunClient(client, cmd.OutOrStdout())
+		}
+
+		sources, err := opts.sources(cmd)
+		if err != nil {
+			return err
+		}
+		summary := opts.link(cmd.Context(), client, sources, cmd.ErrOrStderr())
+		if opts.dryRun {
+			safeLog(cmd.ErrOrStderr(), "Dry run: would link %s", summary)
+			return nil
+		}
+		if err := opts.retry.save(cmd.ErrOrStderr()); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Linked %s\n", summary); err != nil {
+			return fmt.Errorf("write summary: %w", err)
+		}
+		if summary.Failed > 0 {
+			return fmt.Errorf("%d of %d pages failed", summary.Failed, summary.Pages)
+		}
+		return nil
+	}
+}
+
+// sources reads the source pages from --map, or from the --retry-from failures file.
+func (opts *pagesLinkOptions) sources(cmd *cobra.Command) ([]*linkSource, error) {
+	if opts.retry.retryFrom == "" {
+		input, closeInput, err := openInput(opts.mapPath, cmd.InOrStdin())
+		if err != nil {
+			return nil, err
+		}
+		defer closeInput()
+		return readLinkMap(input, opts.property)
+	}
+
+	items, err := opts.retry.load()
+	if err != nil {
+		return nil, err
+	}
+	sources := make([]*linkSource, 0, len(items))
+	for _, item := range items {
+		var payload linkPayload
+		if err := decodePayload(item, &payload); err != nil {
+			return nil, fmt.Errorf("line %d: %w", item.Line, err)
+		}
+		source := &linkSource{pageID: payload.Source, line: item.Line, index: item.Index}
+		for _, link := range payload.Links {
+			source.additions = append(source.additions, relationAddition{property: link.Property, value: link.Target})
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// readLinkMap groups the mapping rows by source page so each page is updated once. The
+// header row must name source and target columns; a property column overrides property.
+func readLinkMap(input io.Reader, property string) ([]*linkSource, error) {
+	reader := csv.NewReader(input)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("mapping is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read mapping header: %w", err)
+	}
+
+	columns := map[string]int{"source": -1, "target": -1, "property": -1}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := columns[name]; ok {
+			columns[name] = i
+		}
+	}
+	if columns["source"] < 0 || columns["target"] < 0 {
+		return nil, errors.New("mapping header must name source and target columns")
+	}
+	if columns["property"] < 0 && property == "" {
+		return nil, errors.New("mapping has no property column; pass --property")
+	}
+
+	cell := func(record []string, column string) string {
+		if i := columns[column]; i >= 0 && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var sources []*linkSource
+	byID := map[string]*linkSource{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return sources, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read mapping: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		sourceID, err := notionid.Parse(cell(record, "source"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: source: %w", line, err)
+		}
+		target := cell(record, "target")
+		if _, err := notionid.Parse(target); err != nil {
+			return nil, fmt.Errorf("line %d: target: %w", line, err)
+		}
+		name := cell(record, "property")
+		if name == "" {
+			name = property
+		}
+		if name == "" {
+			return nil, fmt.Errorf("line %d has no relation property", line)
+		}
+
+		source, ok := byID[sourceID]
+		if !ok {
+			source = &linkSource{pageID: sourceID, line: line, index: len(sources)}
+			byID[sourceID] = source
+			sources = append(sources, source)
+		}
+		source.additions = append(source.additions, relationAddition{property: name, value: target})
+	}
+}
+
+// link updates the source pages with up to --concurrency requests in flight, merging the
+// new relations into the existing ones as `pages update --add-relation` does.
+func (opts *pagesLinkOptions) link(
+	ctx context.Context,
+	client linkClient,
+	sources []*linkSource,
+	log io.Writer,
+) linkSummary {
+	changed := make([]bool, len(sources))
+	failed := make([]error, len(sources))
+	_ = opts.exec.forEach(ctx, len(sources), func(ctx context.Context, i int) error {
+		changed[i], failed[i] = linkPage(ctx, client, sources[i])
+		return nil
+	})
+
+	var summary linkSummary
+	for i, source := range sources {
+		summary.Pages++
+		switch {
+		case failed[i] != nil:
+			summary.Failed++
+			safeLog(log, "line %d (%s): %v", source.line, source.pageID, failed[i])
+			opts.retry.record(source.index, source.line, source.payload(), failed[i])
+		case changed[i]:
+			summary.Linked++
+		default:
+			summary.Unchanged++
+		}
+	}
+	return summary
+}
+
+func (s *linkSource) payload() linkPayload {
+	payload := linkPayload{Source: s.pageID, Links: make([]linkTarget, 0, len(s.additions))}
+	for _, addition := range s.additions {
+		payload.Links = append(payload.Links, linkTarget{Property: addition.property, Target: addition.value})
+	}
+	return payload
+}
+
+// linkPage adds the source's relations to its page and reports whether anything was new.
+func linkPage(ctx context.Context, client linkClient, source *linkSource) (bool, error) {
+	existing, err := client.RetrievePage(ctx, source.pageID)
+	if err != nil {
+		return false, fmt.Errorf("retrieve page: %w", err)
+	}
+	added, err := resolveRelationAdditions(ctx, client, existing, source.additions, nil)
+	if err != nil {
+		return false, err
+	}
+
+	updates := map[string]any{}
+	if err := addRelationUpdates(updates, added); err != nil {
+		return false, err
+	}
+	if err := mergeRelationProperties(existing, updates, false); err != nil {
+		return false, err
+	}
+	if !relationsChanged(existing, updates) {
+		return false, nil
+	}
+
+	if _, err := client.UpdatePage(ctx, source.pageID, notion.UpdatePageRequest{Properties: updates}); err != nil {
+		return false, fmt.Errorf("update page: %w", err)
+	}
+	return true, nil
+}
+
+// relationsChanged reports whether any merged relation array in updates is longer than the
+// page's current one, i.e. whether the merge added a page.
+func relationsChanged(existing notion.Page, updates map[string]any) bool {
+	for name, raw := range updates {
+		entry, _ := raw.(map[string]any)
+		merged, _ := entry["relation"].([]map[string]string)
+		if len(merged) != len(existing.Properties[name].Relation) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+const (
+	linkTask1   = "11111111-1111-1111-1111-111111111111"
+	linkTask2   = "22222222-2222-2222-2222-222222222222"
+	linkProject = "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa"
+	linkEpic    = "bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb"
+)
+
+// fakeLinkClient serves pages with a Project relation and records relation updates.
+type fakeLinkClient struct {
+	fakeRelationClient
+	mu      sync.Mutex
+	pages   map[string]notion.Page
+	updates map[string]map[string]any
+}
+
+func (f *fakeLinkClient) RetrievePage(_ context.Context, pageID string) (notion.Page, error) {
+	return f.pages[pageID], nil
+}
+
+func (f *fakeLinkClient) UpdatePage(_ context.Context, pageID string, req notion.UpdatePageRequest) (notion.Page, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updates[pageID] = req.Properties
+	return notion.Page{ID: pageID}, nil
+}
+
+func linkTaskPage(id string, related ...string) notion.Page {
+	relation := make([]notion.RelationReference, 0, len(related))
+	for _, rel := range related {
+		relation = append(relation, notion.RelationReference{ID: rel})
+	}
+	return notion.Page{ID: id, Properties: map[string]notion.PropertyValue{
+		"Project": {ID: "prj", Type: relationType, Relation: relation},
+		"Epic":    {ID: "epc", Type: relationType},
+	}}
+}
+
+func TestLinkMergesRelationsPerSourcePage(t *testing.T) {
+	mapping := "Source,Target,Property\n" +
+		linkTask1 + "," + linkProject + ",\n" +
+		linkTask1 + "," + linkEpic + ",Epic\n" +
+		linkTask2 + "," + linkProject + ",\n"
+	sources, err := readLinkMap(strings.NewReader(mapping), "Project")
+	if err != nil {
+		t.Fatalf("readLinkMap returned error: %v", err)
+	}
+	if len(sources) != 2 || len(sources[0].additions) != 2 {
+		t.Fatalf("expected rows grouped by source page, got %+v", sources)
+	}
+
+	client := &fakeLinkClient{
+		pages: map[string]notion.Page{
+			linkTask1: linkTaskPage(linkTask1),
+			linkTask2: linkTaskPage(linkTask2, linkProject),
+		},
+		updates: map[string]map[string]any{},
+	}
+	opts := &pagesLinkOptions{exec: defaultExecutionOptions()}
+	summary := opts.link(context.Background(), client, sources, &bytes.Buffer{})
+	if summary != (linkSummary{Pages: 2, Linked: 1, Unchanged: 1}) {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if len(client.updates) != 1 || len(client.updates[linkTask1]) != 2 {
+		t.Fatalf("expected one update setting Project and Epic on task 1, got %+v", client.updates)
+	}
+}
+
+func TestReadLinkMapRequiresProperty(t *testing.T) {
+	if _, err := readLinkMap(strings.NewReader("source,target\n"), ""); err == nil {
+		t.Fatal("expected an error when neither a property column nor --property is given")
+	}
+	if _, err := readLinkMap(strings.NewReader("source,target\nnope,"+linkProject+"\n"), "Project"); err == nil {
+		t.Fatal("expected an error for a source that is not a page ID")
+	}
+}
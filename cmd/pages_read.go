@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/markdown"
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+type pagesReadOptions struct {
+	dataSource string
+	raw        bool
+}
+
+func newPagesReadCmd(globals *globalOptions) *cobra.Command {
+	opts := &pagesReadOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "read <page-id|url|unique-id>",
+		Short: "Show a page's title and content in the terminal",
+		Args:  cobra.ExactArgs(1),
+		RunE:  opts.run(globals),
+		Annotations: map[string]string{
+			pagerAnnotation: "true",
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.raw, "raw", false, "Print plain Markdown without terminal colors")
+	cmd.Flags().StringVar(
+		&opts.dataSource,
+		"data-source",
+		"",
+		"Data source ID or alias used to resolve unique ID handles such as TASK-123",
+	)
+
+	return cmd
+}
+
+func (opts *pagesReadOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		client, err := buildClient(globals)
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		pageID, err := resolvePageRef(ctx, client, globals.profile, args[0], opts.dataSource)
+		if err != nil {
+			return err
+		}
+
+		// Styling follows the page picker's terminal check, and NO_COLOR turns it off.
+		ansi := !opts.raw && interactiveOutput(cmd) && os.Getenv("NO_COLOR") == ""
+		out := cmd.OutOrStdout()
+		text, err := readPage(ctx, client, pageID, ansi)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(out, text); err != nil {
+			return fmt.Errorf("write page: %w", err)
+		}
+		return nil
+	}
+}
+
+// readPage renders the page title as a heading followed by the page content, styled for a
+// terminal when ansi is set.
+func readPage(ctx context.Context, client pageExportClient, pageID string, ansi bool) (string, error) {
+	page, err := client.RetrievePage(ctx, pageID)
+	if err != nil {
+		return "", fmt.Errorf("retrieve page: %w", err)
+	}
+	blocks, err := fetchBlockTree(ctx, client, pageID)
+	if err != nil {
+		return "", fmt.Errorf("read page %s: %w", pageID, err)
+	}
+
+	title := notion.Block{Type: "heading_1", Heading1: &notion.HeadingBlock{
+		RichText: []notion.RichText{{Type: "text", PlainText: pageTitle(page)}},
+	}}
+	blocks = append([]notion.Block{title}, blocks...)
+	if ansi {
+		return markdown.Terminal(blocks), nil
+	}
+	return markdown.FromBlocks(blocks), nil
+}
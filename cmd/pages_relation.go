@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/yourorg/notionctl/internal/fuzzy"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/notionid"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+const (
+	// relationPickMarker asks for the related page interactively: --add-relation 'Epic=?'.
+	relationPickMarker = "?"
+	// relationPickLimit caps how many search results the picker lists.
+	relationPickLimit = 25
+)
+
+var errRelationPickCancelled = errors.New("relation picker cancelled")
+
+// relationAddition is one --add-relation Property=<page> entry.
+type relationAddition struct {
+	property string
+	value    string
+}
+
+func parseRelationAdditions(raw []string) ([]relationAddition, error) {
+	additions := make([]relationAddition, 0, len(raw))
+	for _, entry := range raw {
+		property, value, ok := strings.Cut(entry, "=")
+		property, value = strings.TrimSpace(property), strings.TrimSpace(value)
+		if !ok || property == "" || value == "" {
+			return nil, fmt.Errorf("invalid --add-relation %q (expected Property=<page-id|url|?>)", entry)
+		}
+		additions = append(additions, relationAddition{property: property, value: value})
+	}
+	return additions, nil
+}
+
+func needsRelationPicker(additions []relationAddition) bool {
+	for _, addition := range additions {
+		if addition.value == relationPickMarker {
+			return true
+		}
+	}
+	return false
+}
+
+// relationClient is the subset of the Notion client used to resolve --add-relation.
+type relationClient interface {
+	dataSourceQuerier
+	GetDataSource(ctx context.Context, dataSourceID string) (notion.DataSource, error)
+}
+
+// resolveRelationAdditions returns the page IDs to add per relation property, keyed by the
+// property name as it appears on page. Entries with "?" are resolved with picker.
+func resolveRelationAdditions(
+	ctx context.Context,
+	client relationClient,
+	page notion.Page,
+	additions []relationAddition,
+	picker *relationPicker,
+) (map[string][]string, error) {
+	var idx *schema.Index
+	out := make(map[string][]string, len(additions))
+	for _, addition := range additions {
+		name, value, ok := pageProperty(page, addition.property)
+		if !ok {
+			return nil, fmt.Errorf("page has no property %q", addition.property)
+		}
+		if value.Type != relationType {
+			return nil, fmt.Errorf("property %q is %s, not a relation", name, value.Type)
+		}
+
+		if addition.value != relationPickMarker {
+			id, err := notionid.Parse(addition.value)
+			if err != nil {
+				return nil, fmt.Errorf("--add-relation %s: %w", name, err)
+			}
+			out[name] = append(out[name], id)
+			continue
+		}
+
+		if idx == nil {
+			if page.Parent.DataSourceID == "" {
+				return nil, errors.New("cannot pick a relation: page is not in a data source")
+			}
+			ds, err := client.GetDataSource(ctx, page.Parent.DataSourceID)
+			if err != nil {
+				return nil, fmt.Errorf("get data source: %w", err)
+			}
+			idx = schema.NewIndex(ds)
+		}
+		ref, ok := idx.ReferenceForName(name)
+		if !ok || ref.Relation == nil || ref.Relation.DataSourceID == "" {
+			return nil, fmt.Errorf("cannot pick a relation: schema for %q has no target data source", name)
+		}
+		id, err := picker.pick(ctx, name, ref.Relation.DataSourceID)
+		if err != nil {
+			return nil, err
+		}
+		out[name] = append(out[name], id)
+	}
+	return out, nil
+}
+
+// addRelationUpdates merges the resolved additions into the --props payload, appending to
+// any relation array the payload already sets for the same property.
+func addRelationUpdates(updates map[string]any, additions map[string][]string) error {
+	for name, ids := range additions {
+		entry := map[string]any{}
+		if raw, ok := updates[name]; ok {
+			if entry, ok = raw.(map[string]any); !ok {
+				return fmt.Errorf("props entry for %s must be an object", name)
+			}
+		}
+		relations, _ := entry["relation"].([]any)
+		for _, id := range ids {
+			relations = append(relations, map[string]any{"id": id})
+		}
+		entry["relation"] = relations
+		updates[name] = entry
+	}
+	return nil
+}
+
+// relationPicker is a line-based fuzzy finder over the titles of a data source. It reads
+// search text and selections from in and prompts on out.
+type relationPicker struct {
+	client relationClient
+	in     *bufio.Reader
+	out    io.Writer
+}
+
+// newRelationPicker requires in to be a terminal so scripts fail instead of blocking.
+func newRelationPicker(client relationClient, in io.Reader, out io.Writer) (*relationPicker, error) {
+	f, ok := in.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return nil, errors.New("--add-relation with ? needs an interactive terminal on stdin")
+	}
+	return &relationPicker{client: client, in: bufio.NewReader(in), out: out}, nil
+}
+
+// pick searches the target data source by title until the user selects a page.
+func (p *relationPicker) pick(ctx context.Context, property, dataSourceID string) (string, error) {
+	ds, err := p.client.GetDataSource(ctx, dataSourceID)
+	if err != nil {
+		return "", fmt.Errorf("get related data source: %w", err)
+	}
+	titleID := ""
+	for _, ref := range ds.Properties {
+		if ref.Type == "title" {
+			titleID = ref.ID
+		}
+	}
+
+	query, err := p.prompt(fmt.Sprintf("Search %s: ", property))
+	if err != nil {
+		return "", err
+	}
+	for {
+		pages, err := p.search(ctx, dataSourceID, titleID, query)
+		if err != nil {
+			return "", err
+		}
+		if len(pages) == 0 {
+			safeLog(p.out, "no pages match %q", query)
+			if query, err = p.prompt(fmt.Sprintf("Search %s: ", property)); err != nil {
+				return "", err
+			}
+			continue
+		}
+
+		for i, page := range pages {
+			safeLog(p.out, "%3d) %s  %s", i+1, pickerTitle(page), page.ID)
+		}
+		answer, err := p.prompt(fmt.Sprintf("Pick 1-%d, or type to search again: ", len(pages)))
+		if err != nil {
+			return "", err
+		}
+		if n, convErr := strconv.Atoi(answer); convErr == nil && n >= 1 && n <= len(pages) {
+			return pages[n-1].ID, nil
+		}
+		query = answer
+	}
+}
+
+// search narrows candidates server-side with a title contains filter, then ranks them.
+func (p *relationPicker) search(ctx context.Context, dataSourceID, titleID, query string) ([]notion.Page, error) {
+	req := notion.QueryDataSourceRequest{
+		PageSize: relationPickLimit,
+		Sorts:    []any{map[string]any{"timestamp": "last_edited_time", "direction": "descending"}},
+	}
+	if titleID != "" {
+		req.FilterProperties = []string{titleID}
+		if query != "" {
+			req.Filter = map[string]any{"property": titleID, "title": map[string]any{"contains": query}}
+		}
+	}
+	resp, err := p.client.QueryDataSource(ctx, dataSourceID, req)
+	if err != nil {
+		return nil, fmt.Errorf("search related pages: %w", err)
+	}
+
+	titles := make([]string, len(resp.Results))
+	for i, page := range resp.Results {
+		titles[i] = pageTitle(page)
+	}
+	ranked := fuzzy.Rank(query, titles)
+	pages := make([]notion.Page, 0, len(ranked))
+	for _, i := range ranked {
+		pages = append(pages, resp.Results[i])
+	}
+	return pages, nil
+}
+
+// prompt writes label and reads one trimmed line. A blank line or end of input cancels.
+func (p *relationPicker) prompt(label string) (string, error) {
+	if _, err := fmt.Fprint(p.out, label); err != nil {
+		return "", fmt.Errorf("write prompt: %w", err)
+	}
+	line, err := p.in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read answer: %w", err)
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return "", errRelationPickCancelled
+	}
+	return line, nil
+}
+
+func pickerTitle(page notion.Page) string {
+	if title := pageTitle(page); title != "" {
+		return title
+	}
+	return "(untitled)"
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+type fakeRelationClient struct {
+	dataSources map[string]notion.DataSource
+	pages       []notion.Page
+	filters     []any
+}
+
+func (c *fakeRelationClient) GetDataSource(_ context.Context, id string) (notion.DataSource, error) {
+	ds, ok := c.dataSources[id]
+	if !ok {
+		return notion.DataSource{}, errors.New("unknown data source")
+	}
+	return ds, nil
+}
+
+func (c *fakeRelationClient) QueryDataSource(
+	_ context.Context,
+	_ string,
+	req notion.QueryDataSourceRequest,
+) (notion.QueryDataSourceResponse, error) {
+	c.filters = append(c.filters, req.Filter)
+	return notion.QueryDataSourceResponse{Results: append([]notion.Page(nil), c.pages...)}, nil
+}
+
+func TestResolveRelationAdditionsPicksInteractively(t *testing.T) {
+	const (
+		epicA = "11111111111111111111111111111111"
+		epicB = "22222222222222222222222222222222"
+		other = "33333333-3333-3333-3333-333333333333"
+	)
+	epic := func(id, title string) notion.Page {
+		return notion.Page{ID: id, Properties: map[string]notion.PropertyValue{
+			"Name": {Type: "title", Title: []notion.RichText{{PlainText: title}}},
+		}}
+	}
+	client := &fakeRelationClient{
+		dataSources: map[string]notion.DataSource{
+			"tasks": {Properties: map[string]notion.PropertyReference{
+				"Epic":    {ID: "ep", Name: "Epic", Type: "relation", Relation: &notion.RelationConfig{DataSourceID: "epics"}},
+				"Blocked": {ID: "bl", Name: "Blocked", Type: "relation"},
+			}},
+			"epics": {Properties: map[string]notion.PropertyReference{
+				"Name": {ID: "title", Name: "Name", Type: "title"},
+			}},
+		},
+		pages: []notion.Page{epic(epicA, "Payments platform"), epic(epicB, "Platform")},
+	}
+	page := notion.Page{
+		Parent: notion.DataSourceParent("tasks"),
+		Properties: map[string]notion.PropertyValue{
+			"Epic":    {ID: "ep", Type: "relation"},
+			"Blocked": {ID: "bl", Type: "relation"},
+		},
+	}
+
+	var prompts bytes.Buffer
+	picker := &relationPicker{client: client, in: bufio.NewReader(strings.NewReader("platform\n1\n")), out: &prompts}
+	additions, err := parseRelationAdditions([]string{"epic=?", "Blocked=" + other})
+	if err != nil {
+		t.Fatalf("parseRelationAdditions returned error: %v", err)
+	}
+	got, err := resolveRelationAdditions(context.Background(), client, page, additions, picker)
+	if err != nil {
+		t.Fatalf("resolveRelationAdditions returned error: %v", err)
+	}
+	// The exact title match ranks first, so "1" selects Platform.
+	if len(got["Epic"]) != 1 || got["Epic"][0] != epicB {
+		t.Fatalf("Epic = %v, want [%s]; prompts:\n%s", got["Epic"], epicB, prompts.String())
+	}
+	if len(got["Blocked"]) != 1 || got["Blocked"][0] != other {
+		t.Fatalf("Blocked = %v", got["Blocked"])
+	}
+	filter, _ := client.filters[0].(map[string]any)
+	if filter["property"] != "title" {
+		t.Fatalf("expected a title filter, got %#v", client.filters[0])
+	}
+
+	updates := map[string]any{"Epic": map[string]any{"relation": []any{map[string]any{"id": epicA}}}}
+	if err := addRelationUpdates(updates, got); err != nil {
+		t.Fatalf("addRelationUpdates returned error: %v", err)
+	}
+	if rel := updates["Epic"].(map[string]any)["relation"].([]any); len(rel) != 2 {
+		t.Fatalf("expected the picked page to be appended, got %#v", rel)
+	}
+}
+
+func TestResolveRelationAdditionsErrors(t *testing.T) {
+	if _, err := parseRelationAdditions([]string{"Epic"}); err == nil {
+		t.Fatalf("expected error for entry without =")
+	}
+
+	page := notion.Page{Properties: map[string]notion.PropertyValue{"Status": {Type: "status"}}}
+	client := &fakeRelationClient{}
+	for _, entry := range []string{"Status=?", "Missing=?"} {
+		additions, _ := parseRelationAdditions([]string{entry})
+		if _, err := resolveRelationAdditions(context.Background(), client, page, additions, nil); err == nil {
+			t.Fatalf("expected error for %s", entry)
+		}
+	}
+
+	picker := &relationPicker{client: &fakeRelationClient{dataSources: map[string]notion.DataSource{"ds": {}}},
+		in: bufio.NewReader(strings.NewReader("\n")), out: &bytes.Buffer{}}
+	if _, err := picker.pick(context.Background(), "Epic", "ds"); !errors.Is(err, errRelationPickCancelled) {
+		t.Fatalf("expected a blank answer to cancel, got %v", err)
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
+	"io"
 	"sort"
 
 	"github.com/spf13/cobra"
@@ -13,36 +13,76 @@ import (
 	"github.com/yourorg/notionctl/internal/expand"
 	"github.com/yourorg/notionctl/internal/notion"
 	"github.com/yourorg/notionctl/internal/render"
+	"github.com/yourorg/notionctl/internal/schema"
 )
 
 type pagesUpdateOptions struct {
 	propsPath        string
 	format           string
+	dataSource       string
 	expandProps      []string
+	expandFields     []string
+	expandInline     bool
+	addRelations     []string
 	replaceRelations bool
 	archive          bool
+	dryRun           bool
+	noValidate       bool
+	exec             executionOptions
 }
 
 func newPagesUpdateCmd(globals *globalOptions) *cobra.Command {
-	opts := &pagesUpdateOptions{format: formatJSON}
+	opts := &pagesUpdateOptions{format: formatJSON, exec: defaultExecutionOptions()}
 
 	cmd := &cobra.Command{
-		Use:   "update <page-id>",
+		Use:   "update <page-id|url|unique-id>",
 		Short: "Update a Notion page's properties",
 		Args:  cobra.ExactArgs(1),
 		RunE:  opts.run(globals),
 	}
 
-	cmd.Flags().StringVar(&opts.propsPath, "props", "", "Path to JSON file describing property updates")
+	cmd.Flags().StringVar(&opts.propsPath, "props", "", "Path to JSON file describing property updates (- for stdin)")
 	cmd.Flags().BoolVar(
 		&opts.replaceRelations,
 		"replace-relations",
 		false,
 		"Replace relation properties instead of merging with existing values",
 	)
-	cmd.Flags().StringSliceVar(&opts.expandProps, "expand", nil, "Relation property names to expand after update")
-	cmd.Flags().StringVar(&opts.format, "format", opts.format, "Output format: json|table")
+	cmd.Flags().StringArrayVar(
+		&opts.addRelations,
+		"add-relation",
+		nil,
+		"Add a related page as Property=<page-id|url>, or Property=? to pick one interactively (repeatable)",
+	)
+	cmd.Flags().StringSliceVar(&opts.expandProps, "expand", nil, "Relation or rollup property names to expand after update")
+	cmd.Flags().StringSliceVar(
+		&opts.expandFields,
+		"expand-fields",
+		nil,
+		`Properties to keep on expanded pages, e.g. "Name,Status" ("title" keeps the title)`,
+	)
+	cmd.Flags().BoolVar(
+		&opts.expandInline,
+		"expand-inline",
+		false,
+		"Embed expanded pages in the relation values (relation[].page) instead of expanded_relations",
+	)
+	formatFlag(cmd, &opts.format, "Output format", formatJSON, formatTable)
 	cmd.Flags().BoolVar(&opts.archive, "archive", false, "Archive or unarchive the page")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print the PATCH request that would be sent without sending it")
+	cmd.Flags().BoolVar(
+		&opts.noValidate,
+		"no-validate",
+		false,
+		"Send the properties without checking them against the data source schema first",
+	)
+	cmd.Flags().StringVar(
+		&opts.dataSource,
+		"data-source",
+		"",
+		"Data source ID or alias used to resolve unique ID handles such as TASK-123",
+	)
+	opts.exec.register(cmd, "")
 
 	return cmd
 }
@@ -53,19 +93,39 @@ func (opts *pagesUpdateOptions) run(globals *globalOptions) func(*cobra.Command,
 			return err
 		}
 
-		client, err := buildClient(globals.profile)
+		client, err := opts.exec.buildClient(globals)
 		if err != nil {
 			return err
 		}
+		if opts.dryRun {
+			client = dryRunClient(client, cmd.OutOrStdout())
+		}
 
 		ctx := cmd.Context()
-		pageID := args[0]
+		pageID, err := resolvePageRef(ctx, client, globals.profile, args[0], opts.dataSource)
+		if err != nil {
+			return err
+		}
 
-		archiveSet := cmd.Flags().Changed("archive")
-		updated, err := opts.applyUpdates(ctx, client, pageID, archiveSet)
+		additions, err := parseRelationAdditions(opts.addRelations)
 		if err != nil {
 			return err
 		}
+		var picker *relationPicker
+		if needsRelationPicker(additions) {
+			if opts.propsPath == stdinPath {
+				return errors.New("--add-relation with ? reads from the terminal and cannot be combined with --props -")
+			}
+			if picker, err = newRelationPicker(client, cmd.InOrStdin(), cmd.ErrOrStderr()); err != nil {
+				return err
+			}
+		}
+
+		archiveSet := cmd.Flags().Changed("archive")
+		updated, err := opts.applyUpdates(ctx, client, pageID, archiveSet, cmd.InOrStdin(), cmd.ErrOrStderr(), additions, picker)
+		if err != nil || opts.dryRun {
+			return err
+		}
 
 		updated, err = opts.expandPage(ctx, client, updated)
 		if err != nil {
@@ -77,8 +137,11 @@ func (opts *pagesUpdateOptions) run(globals *globalOptions) func(*cobra.Command,
 }
 
 func (opts *pagesUpdateOptions) validate() error {
-	if opts.propsPath == "" {
-		return errors.New("--props is required")
+	if opts.propsPath == "" && len(opts.addRelations) == 0 {
+		return errors.New("--props or --add-relation is required")
+	}
+	if (len(opts.expandFields) > 0 || opts.expandInline) && len(opts.expandProps) == 0 {
+		return errors.New("--expand-fields and --expand-inline require --expand")
 	}
 	return nil
 }
@@ -88,20 +151,42 @@ func (opts *pagesUpdateOptions) applyUpdates(
 	client *notion.Client,
 	pageID string,
 	archiveSet bool,
+	stdin io.Reader,
+	log io.Writer,
+	additions []relationAddition,
+	picker *relationPicker,
 ) (notion.Page, error) {
 	existing, err := client.RetrievePage(ctx, pageID)
 	if err != nil {
 		return notion.Page{}, fmt.Errorf("retrieve page: %w", err)
 	}
 
-	updates, err := loadUpdatePayload(opts.propsPath)
+	updates := map[string]any{}
+	if opts.propsPath != "" {
+		if updates, err = loadUpdatePayload(opts.propsPath, stdin); err != nil {
+			return notion.Page{}, err
+		}
+	}
+	added, err := resolveRelationAdditions(ctx, client, existing, additions, picker)
 	if err != nil {
 		return notion.Page{}, err
 	}
+	if err := addRelationUpdates(updates, added); err != nil {
+		return notion.Page{}, err
+	}
 
 	if mergeErr := mergeRelationProperties(existing, updates, opts.replaceRelations); mergeErr != nil {
 		return notion.Page{}, mergeErr
 	}
+	if !opts.noValidate && len(updates) > 0 && existing.Parent.DataSourceID != "" {
+		ds, err := client.GetDataSource(ctx, existing.Parent.DataSourceID)
+		if err != nil {
+			return notion.Page{}, fmt.Errorf("get data source: %w", err)
+		}
+		if err := validateProperties(ds, updates, log); err != nil {
+			return notion.Page{}, err
+		}
+	}
 
 	req := notion.UpdatePageRequest{Properties: updates}
 	if archiveSet {
@@ -127,16 +212,20 @@ func (opts *pagesUpdateOptions) expandPage(
 	if err != nil {
 		return notion.Page{}, err
 	}
-	if err := expand.FirstLevel(ctx, client, pages, refs); err != nil {
+	if err := expand.FirstLevel(ctx, client, pages, refs, opts.exec.concurrency); err != nil {
 		return notion.Page{}, fmt.Errorf("expand relations: %w", err)
 	}
+	expand.Project(pages, opts.expandFields)
+	if opts.expandInline {
+		expand.Inline(pages)
+	}
 	return pages[0], nil
 }
 
 func (opts *pagesUpdateOptions) renderPage(cmd *cobra.Command, page notion.Page) error {
 	switch opts.format {
 	case formatJSON:
-		if err := render.JSON(cmd.OutOrStdout(), page); err != nil {
+		if err := writeJSON(cmd.Context(), cmd.OutOrStdout(), page); err != nil {
 			return fmt.Errorf("render json: %w", err)
 		}
 		return nil
@@ -151,8 +240,21 @@ func (opts *pagesUpdateOptions) renderPage(cmd *cobra.Command, page notion.Page)
 	}
 }
 
-func loadUpdatePayload(path string) (map[string]any, error) {
-	data, err := os.ReadFile(path) // #nosec G304 -- reading user-specified update payload is intended
+// validateProperties checks properties against the data source's schema before they are
+// sent, so mistakes are reported together and by name rather than as Notion's 400. Select
+// and multi-select options Notion will create are logged as warnings.
+func validateProperties(ds notion.DataSource, properties map[string]any, log io.Writer) error {
+	if err := schema.Validate(ds, properties); err != nil {
+		return fmt.Errorf("properties do not match the %s schema (nothing was sent; --no-validate skips this check):\n%w", ds.Name, err)
+	}
+	for _, note := range schema.NewOptions(ds, properties) {
+		safeLog(log, "warning: %s", note)
+	}
+	return nil
+}
+
+func loadUpdatePayload(path string, stdin io.Reader) (map[string]any, error) {
+	data, err := readFileOrStdin(path, stdin)
 	if err != nil {
 		return nil, fmt.Errorf("read props: %w", err)
 	}
@@ -163,6 +265,13 @@ func loadUpdatePayload(path string) (map[string]any, error) {
 	if len(payload) == 0 {
 		return nil, errors.New("property payload is empty")
 	}
+	// Notion accepts property IDs as keys; only the property_id: marker needs stripping.
+	for key, value := range payload {
+		if id, ok := schema.ParsePropertyID(key); ok {
+			delete(payload, key)
+			payload[id] = value
+		}
+	}
 	return payload, nil
 }
 
@@ -172,7 +281,7 @@ func mergeRelationProperties(
 	replace bool,
 ) error {
 	for name, raw := range updates {
-		existingValue, ok := existing.Properties[name]
+		_, existingValue, ok := pageProperty(existing, name)
 		if !ok || existingValue.Type != relationType {
 			continue
 		}
@@ -1,8 +1,16 @@
 package cmd
 
 import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/zalando/go-keyring"
+
 	"github.com/yourorg/notionctl/internal/notion"
 )
 
@@ -43,3 +51,92 @@ func TestNormalizeRelationArrayErrors(t *testing.T) {
 		t.Fatalf("expected error for missing relation id")
 	}
 }
+
+func TestPreparePageExpansionAcceptsPropertyIDs(t *testing.T) {
+	page := notion.Page{ID: "p1", Properties: map[string]notion.PropertyValue{
+		"Owner": {ID: "own", Type: "relation"},
+		"Name":  {ID: "title", Type: "title"},
+	}}
+	for _, ref := range []string{"Owner", "owner", "own", "property_id:own"} {
+		_, refs, err := preparePageExpansion(page, []string{ref})
+		if err != nil {
+			t.Fatalf("preparePageExpansion(%q) returned error: %v", ref, err)
+		}
+		if refs[0].Name != "Owner" || refs[0].ID != "own" {
+			t.Fatalf("preparePageExpansion(%q) = %+v", ref, refs)
+		}
+	}
+	if _, _, err := preparePageExpansion(page, []string{"property_id:Owner"}); err == nil {
+		t.Fatalf("expected property_id: lookup by name to fail")
+	}
+}
+
+func TestPagesUpdateValidatesAgainstSchema(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NOTIONCTL_TOKEN", "secret_validate")
+	keyring.MockInit()
+
+	const pageID = "1234abcd-1234-1234-1234-1234567890ab"
+	var patched bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPatch:
+			patched = true
+			_, _ = w.Write([]byte(`{"object":"page","id":"` + pageID + `"}`))
+		case strings.HasPrefix(r.URL.Path, "/data_sources/"):
+			_, _ = w.Write([]byte(`{"object":"data_source","id":"ds1","name":"Tasks","properties":{
+				"Status":{"id":"s","name":"Status","type":"status","status":{"options":[{"name":"Done"}]}},
+				"Priority":{"id":"p","name":"Priority","type":"select","select":{"options":[{"name":"Low"}]}}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"object":"page","id":"` + pageID + `","parent":{"type":"data_source_id","data_source_id":"ds1"},
+				"properties":{"Status":{"id":"s","type":"status","status":null},"Priority":{"id":"p","type":"select","select":null}}}`))
+		}
+	}))
+	defer srv.Close()
+	t.Setenv(baseURLEnv, srv.URL)
+
+	props := filepath.Join(t.TempDir(), "props.json")
+	if err := os.WriteFile(props, []byte(`{"Status":{"status":{"name":"Shipped"}}}`), 0o600); err != nil {
+		t.Fatalf("write props: %v", err)
+	}
+
+	root := newRootCmd(&globalOptions{profile: "default"})
+	root.SetArgs([]string{"pages", "update", pageID, "--props", props})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), `Status: no option "Shipped" (options: Done)`) {
+		t.Fatalf("expected a schema error naming the option, got %v", err)
+	}
+	if patched {
+		t.Fatalf("invalid properties were sent to Notion")
+	}
+
+	root = newRootCmd(&globalOptions{profile: "default"})
+	root.SetArgs([]string{"pages", "update", pageID, "--props", props, "--no-validate"})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("pages update --no-validate returned error: %v", err)
+	}
+	if !patched {
+		t.Fatalf("expected --no-validate to send the update")
+	}
+
+	// Notion creates select options on write, so a new one is only a warning.
+	patched = false
+	if err := os.WriteFile(props, []byte(`{"Priority":{"select":{"name":"Urgent"}}}`), 0o600); err != nil {
+		t.Fatalf("write props: %v", err)
+	}
+	var stderr bytes.Buffer
+	root = newRootCmd(&globalOptions{profile: "default"})
+	root.SetArgs([]string{"pages", "update", pageID, "--props", props})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&stderr)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("pages update with a new select option returned error: %v", err)
+	}
+	if !patched || !strings.Contains(stderr.String(), `warning: Priority: Notion will create the new option "Urgent"`) {
+		t.Fatalf("expected the update to be sent with a warning, patched=%v stderr:\n%s", patched, stderr.String())
+	}
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/fuzzy"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/render"
+)
+
+// pickTTY is opened directly so the finder works while stdout is captured by $(...).
+const pickTTY = "/dev/tty"
+
+type pickPagesOptions struct {
+	dataSource string
+	query      string
+	printURL   bool
+}
+
+type pickDataSourcesOptions struct {
+	databaseID string
+	query      string
+}
+
+func newPickCmd(globals *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pick",
+		Short: "Choose a page or data source with a fuzzy finder and print its ID",
+	}
+
+	cmd.AddCommand(newPickPagesCmd(globals))
+	cmd.AddCommand(newPickDataSourcesCmd(globals))
+
+	return cmd
+}
+
+func newPickPagesCmd(globals *globalOptions) *cobra.Command {
+	opts := &pickPagesOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "pages",
+		Short: "Fuzzy-find a page in a data source by title and print its ID",
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().StringVar(
+		&opts.dataSource,
+		"data-source",
+		"",
+		"Data source ID, URL, or alias (default: the profile's default data source)",
+	)
+	cmd.Flags().StringVar(&opts.query, "query", "", "Initial search text")
+	cmd.Flags().BoolVar(&opts.printURL, "url", false, "Print the page URL instead of its ID")
+
+	return cmd
+}
+
+func (opts *pickPagesOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		dataSourceID, err := resolveDataSourceAlias(globals.profile, opts.dataSource)
+		if err != nil {
+			return err
+		}
+		client, err := buildClient(globals)
+		if err != nil {
+			return err
+		}
+		tty, err := openPickTTY()
+		if err != nil {
+			return err
+		}
+		defer tty.Close() //nolint:errcheck // read-only use of the terminal
+
+		ds, err := client.GetDataSource(cmd.Context(), dataSourceID)
+		if err != nil {
+			return fmt.Errorf("get data source: %w", err)
+		}
+
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		items := make(chan fuzzy.Item, maxQueryPageSize)
+		fetched := make(chan error, 1)
+		go func() {
+			defer close(items)
+			fetched <- opts.streamItems(ctx, client, ds, items)
+		}()
+
+		selected, err := fuzzy.Find(ctx, tty, items, fuzzy.FinderOptions{Prompt: ds.Name + "> ", Query: opts.query})
+		cancel()
+		if fetchErr := <-fetched; fetchErr != nil && !errors.Is(fetchErr, context.Canceled) {
+			return fetchErr
+		}
+		if err != nil {
+			return err
+		}
+		return writeLine(cmd.OutOrStdout(), selected.Value)
+	}
+}
+
+// streamItems sends one item per page, newest edits first, as each result page arrives so
+// the finder is usable before large data sources finish loading.
+func (opts *pickPagesOptions) streamItems(
+	ctx context.Context,
+	client dataSourceQuerier,
+	ds notion.DataSource,
+	out chan<- fuzzy.Item,
+) error {
+	req := notion.QueryDataSourceRequest{
+		PageSize: maxQueryPageSize,
+		Sorts:    []any{map[string]any{"timestamp": "last_edited_time", "direction": "descending"}},
+	}
+	for _, ref := range ds.Properties {
+		if ref.Type == "title" {
+			req.FilterProperties = []string{ref.ID}
+		}
+	}
+
+	for {
+		resp, err := client.QueryDataSource(ctx, ds.ID, req)
+		if err != nil {
+			return fmt.Errorf("query data source: %w", err)
+		}
+		for _, page := range resp.Results {
+			item := fuzzy.Item{Label: pickerTitle(page), Detail: page.ID, Value: page.ID}
+			if opts.printURL {
+				item.Value = page.URL
+			}
+			select {
+			case out <- item:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if !resp.HasMore || resp.NextCursor == "" {
+			return nil
+		}
+		req.StartCursor = resp.NextCursor
+	}
+}
+
+func newPickDataSourcesCmd(globals *globalOptions) *cobra.Command {
+	opts := &pickDataSourcesOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "data-sources",
+		Short: "Fuzzy-find a data source among saved aliases (and a database's data sources)",
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(
+		newIDValue(&opts.databaseID),
+		"database-id",
+		"Also offer the data sources of this database (ID or URL)",
+	)
+	cmd.Flags().StringVar(&opts.query, "query", "", "Initial search text")
+
+	return cmd
+}
+
+func (opts *pickDataSourcesOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		settings, err := config.LoadDataSourceSettings(globals.profile)
+		if err != nil {
+			return fmt.Errorf("load data source aliases: %w", err)
+		}
+		var sources []notion.DataSource
+		if opts.databaseID != "" {
+			client, err := buildClient(globals)
+			if err != nil {
+				return err
+			}
+			if sources, err = client.ListDataSources(cmd.Context(), opts.databaseID); err != nil {
+				return fmt.Errorf("list data sources: %w", err)
+			}
+		}
+
+		candidates := dataSourceItems(settings, sources)
+		if len(candidates) == 0 {
+			return errors.New("no data sources to pick from: save aliases with ds alias set or pass --database-id")
+		}
+		tty, err := openPickTTY()
+		if err != nil {
+			return err
+		}
+		defer tty.Close() //nolint:errcheck // read-only use of the terminal
+
+		items := make(chan fuzzy.Item, len(candidates))
+		for _, item := range candidates {
+			items <- item
+		}
+		close(items)
+
+		selected, err := fuzzy.Find(cmd.Context(), tty, items, fuzzy.FinderOptions{Prompt: "data source> ", Query: opts.query})
+		if err != nil {
+			return err
+		}
+		return writeLine(cmd.OutOrStdout(), selected.Value)
+	}
+}
+
+// dataSourceItems lists aliases first (sorted, labelled by alias) followed by the database's
+// data sources not already covered by an alias.
+func dataSourceItems(settings config.DataSourceSettings, sources []notion.DataSource) []fuzzy.Item {
+	aliases := render.SortedKeys(settings.Aliases)
+
+	items := make([]fuzzy.Item, 0, len(aliases)+len(sources))
+	aliased := map[string]bool{}
+	for _, alias := range aliases {
+		id := settings.Aliases[alias]
+		aliased[id] = true
+		items = append(items, fuzzy.Item{Label: alias, Detail: id, Value: id})
+	}
+	for _, ds := range sources {
+		if aliased[ds.ID] {
+			continue
+		}
+		label := ds.Name
+		if label == "" {
+			label = "(untitled)"
+		}
+		items = append(items, fuzzy.Item{Label: label, Detail: ds.ID, Value: ds.ID})
+	}
+	return items
+}
+
+func openPickTTY() (*os.File, error) {
+	tty, err := os.OpenFile(pickTTY, os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("pick needs an interactive terminal: %w", err)
+	}
+	return tty, nil
+}
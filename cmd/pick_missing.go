@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/fuzzy"
+)
+
+// pagePickerAnnotation marks commands whose page argument is picked interactively when omitted.
+const pagePickerAnnotation = "notionctl/page-picker"
+
+// interactiveOutput reports whether a missing ID may be picked instead of failing: stdout
+// must be a terminal, so scripts and $(...) captures keep their errors.
+var interactiveOutput = func(cmd *cobra.Command) bool {
+	f, ok := terminalFile(cmd.OutOrStdout())
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// findItem runs the fuzzy finder on the terminal.
+var findItem = func(ctx context.Context, items <-chan fuzzy.Item, opts fuzzy.FinderOptions) (fuzzy.Item, error) {
+	tty, err := openPickTTY()
+	if err != nil {
+		return fuzzy.Item{}, err
+	}
+	defer tty.Close() //nolint:errcheck // read-only use of the terminal
+	return fuzzy.Find(ctx, tty, items, opts)
+}
+
+// pickMissingDataSource fills a required --data-source-id left unset (after profile
+// defaults) by asking the user to choose among recent data sources and saved aliases.
+func pickMissingDataSource(cmd *cobra.Command, profile string) error {
+	f := unsetFlag(cmd.Flags(), "data-source-id")
+	if f == nil || strings.HasSuffix(f.Value.Type(), "Slice") || !flagRequired(f.Annotations) || !interactiveOutput(cmd) {
+		return nil
+	}
+	candidates, err := recentDataSourceItems(profile)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		// Nothing to offer; let cobra report the missing flag.
+		return nil
+	}
+	selected, err := findItem(cmd.Context(), preloadedItems(candidates), fuzzy.FinderOptions{Prompt: "data source> "})
+	if err != nil {
+		return err
+	}
+	if err := cmd.Flags().Set("data-source-id", selected.Value); err != nil {
+		return fmt.Errorf("use picked data source: %w", err)
+	}
+	_ = config.RecordRecent(profile, config.RecentItem{Kind: config.RecentDataSource, ID: selected.Value, Label: selected.Label})
+	return nil
+}
+
+func flagRequired(annotations map[string][]string) bool {
+	required := annotations[cobra.BashCompOneRequiredFlag]
+	return len(required) > 0 && required[0] == "true"
+}
+
+// recentDataSourceItems lists recently used data sources, most recent first, followed by
+// saved aliases not already listed.
+func recentDataSourceItems(profile string) ([]fuzzy.Item, error) {
+	recent, err := config.LoadRecent(profile, config.RecentDataSource)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := config.LoadDataSourceSettings(profile)
+	if err != nil {
+		return nil, fmt.Errorf("load data source aliases: %w", err)
+	}
+	items := recentItems(recent)
+	seen := map[string]bool{}
+	for _, item := range items {
+		seen[item.Value] = true
+	}
+	for _, item := range dataSourceItems(settings, nil) {
+		if !seen[item.Value] {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// pagePickerCommand lets cmd run without its page argument on a terminal: the user picks
+// one of the recently used pages or, when the profile has a default data source, any of its
+// pages.
+func pagePickerCommand(cmd *cobra.Command, globals *globalOptions) *cobra.Command {
+	run := cmd.RunE
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[pagePickerAnnotation] = "true"
+	cmd.Args = func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && interactiveOutput(cmd) {
+			return nil
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	}
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			pageID, err := pickPage(cmd, globals)
+			if err != nil {
+				return err
+			}
+			args = []string{pageID}
+		}
+		return run(cmd, args)
+	}
+	return cmd
+}
+
+func pickPage(cmd *cobra.Command, globals *globalOptions) (string, error) {
+	profile := globals.profile
+	recent, err := config.LoadRecent(profile, config.RecentPage)
+	if err != nil {
+		return "", err
+	}
+	dataSourceID, _ := resolveDataSourceAlias(profile, "")
+	if len(recent) == 0 && dataSourceID == "" {
+		return "", errors.New("accepts 1 arg(s), received 0 (no recent pages or default data source to pick from)")
+	}
+
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+	items := make(chan fuzzy.Item, maxQueryPageSize)
+	fetched := make(chan error, 1)
+	go func() {
+		defer close(items)
+		fetched <- streamPickPages(ctx, globals, dataSourceID, recentItems(recent), items)
+	}()
+
+	selected, err := findItem(ctx, items, fuzzy.FinderOptions{Prompt: "page> "})
+	cancel()
+	if fetchErr := <-fetched; fetchErr != nil && !errors.Is(fetchErr, context.Canceled) {
+		return "", fetchErr
+	}
+	if err != nil {
+		return "", err
+	}
+	_ = config.RecordRecent(profile, config.RecentItem{Kind: config.RecentPage, ID: selected.Value, Label: selected.Label})
+	return selected.Value, nil
+}
+
+// streamPickPages sends the recent pages, then the default data source's pages that are
+// not among them.
+func streamPickPages(
+	ctx context.Context,
+	globals *globalOptions,
+	dataSourceID string,
+	recent []fuzzy.Item,
+	out chan<- fuzzy.Item,
+) error {
+	seen := map[string]bool{}
+	for _, item := range recent {
+		seen[item.Value] = true
+		select {
+		case out <- item:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if dataSourceID == "" {
+		return nil
+	}
+	client, err := buildClient(globals)
+	if err != nil {
+		return err
+	}
+	ds, err := client.GetDataSource(ctx, dataSourceID)
+	if err != nil {
+		return fmt.Errorf("get data source: %w", err)
+	}
+	pages := make(chan fuzzy.Item, maxQueryPageSize)
+	streamed := make(chan error, 1)
+	go func() {
+		defer close(pages)
+		streamed <- (&pickPagesOptions{}).streamItems(ctx, client, ds, pages)
+	}()
+	for item := range pages {
+		if seen[item.Value] {
+			continue
+		}
+		select {
+		case out <- item:
+		case <-ctx.Done():
+		}
+	}
+	return <-streamed
+}
+
+func recentItems(recent []config.RecentItem) []fuzzy.Item {
+	items := make([]fuzzy.Item, 0, len(recent))
+	for _, item := range recent {
+		label := item.Label
+		if label == "" {
+			label = item.ID
+		}
+		items = append(items, fuzzy.Item{Label: label, Detail: "recent · " + item.ID, Value: item.ID})
+	}
+	return items
+}
+
+func preloadedItems(items []fuzzy.Item) <-chan fuzzy.Item {
+	ch := make(chan fuzzy.Item, len(items))
+	for _, item := range items {
+		ch <- item
+	}
+	close(ch)
+	return ch
+}
+
+// recordRecentUse remembers the data source and page a successful command worked on, so
+// the picker can offer them next time. Failures are ignored like the usage log's.
+func recordRecentUse(cmd *cobra.Command, profile string, runErr error) {
+	if cmd == nil || runErr != nil {
+		return
+	}
+	if f := cmd.Flags().Lookup("data-source-id"); f != nil && !strings.HasSuffix(f.Value.Type(), "Slice") {
+		if id := f.Value.String(); id != "" {
+			_ = config.RecordRecent(profile, config.RecentItem{Kind: config.RecentDataSource, ID: id, Label: dataSourceLabel(profile, id)})
+		}
+	}
+	if cmd.Annotations[pagePickerAnnotation] != "" {
+		if args := cmd.Flags().Args(); len(args) == 1 {
+			_ = config.RecordRecent(profile, config.RecentItem{Kind: config.RecentPage, ID: args[0]})
+		}
+	}
+}
+
+// dataSourceLabel names a data source by its alias or cached schema name, if known.
+func dataSourceLabel(profile, id string) string {
+	if settings, err := config.LoadDataSourceSettings(profile); err == nil {
+		for alias, aliased := range settings.Aliases {
+			if strings.EqualFold(aliased, id) {
+				return alias
+			}
+		}
+	}
+	if ds, ok, err := config.LoadSchema(profile, id); err == nil && ok {
+		return ds.Name
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/fuzzy"
+)
+
+// stubPicker makes every command interactive and picks the first offered item whose value
+// is want, recording what was offered.
+func stubPicker(t *testing.T, want string) *[]string {
+	t.Helper()
+	origInteractive, origFind := interactiveOutput, findItem
+	t.Cleanup(func() { interactiveOutput, findItem = origInteractive, origFind })
+
+	var offered []string
+	interactiveOutput = func(*cobra.Command) bool { return true }
+	findItem = func(_ context.Context, items <-chan fuzzy.Item, _ fuzzy.FinderOptions) (fuzzy.Item, error) {
+		var picked fuzzy.Item
+		for item := range items {
+			offered = append(offered, item.Value)
+			if item.Value == want {
+				picked = item
+			}
+		}
+		if picked.Value == "" {
+			return fuzzy.Item{}, fuzzy.ErrCancelled
+		}
+		return picked, nil
+	}
+	return &offered
+}
+
+func TestPickMissingDataSource(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	const recentID = "1a2b3c4d-5e6f-7a8b-9c0d-1e2f3a4b5c6d"
+	const aliasID = "2a2b3c4d-5e6f-7a8b-9c0d-1e2f3a4b5c6d"
+	if err := config.SaveDataSourceAlias("work", "tasks", aliasID, false); err != nil {
+		t.Fatalf("SaveDataSourceAlias returned error: %v", err)
+	}
+	if err := config.RecordRecent("work", config.RecentItem{Kind: config.RecentDataSource, ID: recentID, Label: "Bugs"}); err != nil {
+		t.Fatalf("RecordRecent returned error: %v", err)
+	}
+	offered := stubPicker(t, aliasID)
+
+	var dataSourceID string
+	cmd := &cobra.Command{Use: "query"}
+	cmd.Flags().Var(newIDValue(&dataSourceID), "data-source-id", "Target Notion data source ID or URL")
+	if err := pickMissingDataSource(cmd, "work"); err != nil {
+		t.Fatalf("pickMissingDataSource returned error: %v", err)
+	}
+	if dataSourceID != "" || len(*offered) != 0 {
+		t.Fatalf("optional flag should not prompt, got %q offered %v", dataSourceID, *offered)
+	}
+
+	if err := cmd.MarkFlagRequired("data-source-id"); err != nil {
+		t.Fatalf("MarkFlagRequired: %v", err)
+	}
+	if err := pickMissingDataSource(cmd, "work"); err != nil {
+		t.Fatalf("pickMissingDataSource returned error: %v", err)
+	}
+	if dataSourceID != aliasID {
+		t.Fatalf("data source = %q, want %q", dataSourceID, aliasID)
+	}
+	if len(*offered) != 2 || (*offered)[0] != recentID || (*offered)[1] != aliasID {
+		t.Fatalf("offered = %v, want recent first then aliases", *offered)
+	}
+	recent, err := config.LoadRecent("work", config.RecentDataSource)
+	if err != nil || len(recent) != 2 || recent[0].ID != aliasID {
+		t.Fatalf("picked data source not recorded first: %+v %v", recent, err)
+	}
+}
+
+func TestPagePickerCommand(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	for _, id := range []string{"page-old", "page-new"} {
+		if err := config.RecordRecent("default", config.RecentItem{Kind: config.RecentPage, ID: id}); err != nil {
+			t.Fatalf("RecordRecent returned error: %v", err)
+		}
+	}
+	offered := stubPicker(t, "page-old")
+
+	var ran []string
+	cmd := pagePickerCommand(&cobra.Command{
+		Use:  "get <page-id>",
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			ran = args
+			return nil
+		},
+	}, &globalOptions{profile: "default"})
+	cmd.SetArgs(nil)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if len(ran) != 1 || ran[0] != "page-old" {
+		t.Fatalf("ran with %v, want the picked page", ran)
+	}
+	if len(*offered) != 2 || (*offered)[0] != "page-new" {
+		t.Fatalf("offered = %v, want most recent first", *offered)
+	}
+
+	interactiveOutput = func(*cobra.Command) bool { return false }
+	cmd.SetArgs(nil)
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected missing argument error without a terminal")
+	}
+}
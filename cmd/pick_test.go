@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/fuzzy"
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestPickPagesStreamsItems(t *testing.T) {
+	pages := staticQuerier{
+		{ID: "p1", URL: "https://notion.so/p1", Properties: map[string]notion.PropertyValue{
+			"Name": {Type: "title", Title: []notion.RichText{{PlainText: "Launch plan"}}},
+		}},
+		{ID: "p2", URL: "https://notion.so/p2", Properties: map[string]notion.PropertyValue{
+			"Name": {Type: "title"},
+		}},
+	}
+	ds := notion.DataSource{ID: "ds", Properties: map[string]notion.PropertyReference{
+		"Name": {ID: "title", Name: "Name", Type: "title"},
+	}}
+
+	for _, printURL := range []bool{false, true} {
+		opts := &pickPagesOptions{printURL: printURL}
+		out := make(chan fuzzy.Item, len(pages))
+		if err := opts.streamItems(context.Background(), pages, ds, out); err != nil {
+			t.Fatalf("streamItems returned error: %v", err)
+		}
+		close(out)
+
+		var got []fuzzy.Item
+		for item := range out {
+			got = append(got, item)
+		}
+		want := []fuzzy.Item{
+			{Label: "Launch plan", Detail: "p1", Value: "p1"},
+			{Label: "(untitled)", Detail: "p2", Value: "p2"},
+		}
+		if printURL {
+			want[0].Value, want[1].Value = "https://notion.so/p1", "https://notion.so/p2"
+		}
+		if len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+			t.Fatalf("printURL=%v: items = %+v, want %+v", printURL, got, want)
+		}
+	}
+}
+
+func TestDataSourceItems(t *testing.T) {
+	settings := config.DataSourceSettings{Aliases: map[string]string{"tasks": "ds-1", "bugs": "ds-2"}}
+	sources := []notion.DataSource{{ID: "ds-1", Name: "Tasks"}, {ID: "ds-3", Name: "Docs"}}
+
+	got := dataSourceItems(settings, sources)
+	labels := make([]string, 0, len(got))
+	for _, item := range got {
+		labels = append(labels, item.Label+"="+item.Value)
+	}
+	want := []string{"bugs=ds-2", "tasks=ds-1", "Docs=ds-3"}
+	if len(labels) != len(want) {
+		t.Fatalf("items = %v, want %v", labels, want)
+	}
+	for i := range want {
+		if labels[i] != want[i] {
+			t.Fatalf("items = %v, want %v", labels, want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/notionid"
+	"github.com/yourorg/notionctl/internal/render"
+)
+
+// applyProfileDefaults fills --format, --page-size, and --data-source-id from the profile's
+// defaults when the command has the flag and it was not given. It runs before cobra checks
+// required flags, so a default data source satisfies them.
+func applyProfileDefaults(cmd *cobra.Command, profile string) error {
+	defaults, err := config.LoadProfileDefaults(profile)
+	if err != nil {
+		return fmt.Errorf("load profile defaults: %w", err)
+	}
+	flags := cmd.Flags()
+
+	if f := unsetFlag(flags, "format"); f != nil && defaults.Format != "" && slices.Contains(f.Annotations[formatsAnnotation], defaults.Format) {
+		if err := flags.Set("format", defaults.Format); err != nil {
+			return fmt.Errorf("apply default format: %w", err)
+		}
+	}
+	if f := unsetFlag(flags, "page-size"); f != nil && defaults.PageSize > 0 {
+		if err := flags.Set("page-size", strconv.Itoa(defaults.PageSize)); err != nil {
+			return fmt.Errorf("apply default page size: %w", err)
+		}
+	}
+	if f := unsetFlag(flags, "data-source-id"); f != nil && defaults.DataSource != "" && !strings.HasSuffix(f.Value.Type(), "Slice") {
+		settings, err := config.LoadDataSourceSettings(profile)
+		if err != nil {
+			return fmt.Errorf("load data source aliases: %w", err)
+		}
+		raw, _ := settings.ResolveDataSource(defaults.DataSource)
+		id, err := notionid.Parse(raw)
+		if err != nil {
+			return fmt.Errorf("profile default data source %q is neither a saved alias nor a data source ID "+
+				"(see notionctl ds alias list): %w", defaults.DataSource, err)
+		}
+		if err := flags.Set("data-source-id", id); err != nil {
+			return fmt.Errorf("apply default data source %q: %w", defaults.DataSource, err)
+		}
+	}
+	return nil
+}
+
+func unsetFlag(flags *pflag.FlagSet, name string) *pflag.Flag {
+	f := flags.Lookup(name)
+	if f == nil || f.Changed {
+		return nil
+	}
+	return f
+}
+
+// formatsAnnotation lists the values a command's --format accepts, so a default like csv is
+// only applied to commands that support it.
+const formatsAnnotation = "notionctl_formats"
+
+// formatFlag adds --format, defaulting to the current value of target, and records formats
+// as the values it accepts. The usage reads "<label>: json|table".
+func formatFlag(cmd *cobra.Command, target *string, label string, formats ...string) {
+	cmd.Flags().StringVar(target, "format", *target, label+": "+strings.Join(formats, "|"))
+	cobra.CheckErr(cmd.Flags().SetAnnotation("format", formatsAnnotation, formats))
+}
+
+func newAuthDefaultsCmd(globals *globalOptions) *cobra.Command {
+	format := formatTable
+
+	cmd := &cobra.Command{
+		Use:   "defaults",
+		Short: "Show the flag defaults (format, page_size, data_source_id) of the active profile",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			defaults, err := config.LoadProfileDefaults(globals.profile)
+			if err != nil {
+				return fmt.Errorf("load profile defaults: %w", err)
+			}
+			switch format {
+			case formatJSON:
+				return writeJSON(cmd.Context(), cmd.OutOrStdout(), defaults)
+			case formatTable:
+				pageSize := ""
+				if defaults.PageSize > 0 {
+					pageSize = strconv.Itoa(defaults.PageSize)
+				}
+				rows := [][]string{
+					{config.DefaultDataSource, defaults.DataSource},
+					{config.DefaultFormat, defaults.Format},
+					{config.DefaultPageSize, pageSize},
+				}
+				return render.Table(cmd.OutOrStdout(), []string{"Setting", "Value"}, rows)
+			default:
+				return fmt.Errorf("unknown format %q (expected json or table)", format)
+			}
+		},
+	}
+
+	formatFlag(cmd, &format, "Output format", formatJSON, formatTable)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set <format|page_size|data_source_id> <value>",
+		Short: "Set a flag default for the active profile",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := config.SetProfileDefault(globals.profile, args[0], args[1]); err != nil {
+				return fmt.Errorf("set default: %w", err)
+			}
+			safeLog(cmd.ErrOrStderr(), "Profile %q now defaults %s to %s", globals.profile, args[0], args[1])
+			return nil
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "unset <format|page_size|data_source_id>",
+		Short: "Remove a flag default from the active profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if err := config.UnsetProfileDefault(globals.profile, args[0]); err != nil {
+				return fmt.Errorf("unset default: %w", err)
+			}
+			return nil
+		},
+	})
+
+	return cmd
+}
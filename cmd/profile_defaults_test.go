@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/config"
+)
+
+func TestApplyProfileDefaults(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	const id = "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d"
+	for key, value := range map[string]string{
+		config.DefaultFormat:     "csv",
+		config.DefaultPageSize:   "25",
+		config.DefaultDataSource: "tasks",
+	} {
+		if err := config.SetProfileDefault("work", key, value); err != nil {
+			t.Fatalf("SetProfileDefault(%s) returned error: %v", key, err)
+		}
+	}
+	if err := config.SaveDataSourceAlias("work", "tasks", id, false); err != nil {
+		t.Fatalf("SaveDataSourceAlias returned error: %v", err)
+	}
+
+	newCmd := func(formats ...string) (*cobra.Command, *string, *string, *int) {
+		var dataSourceID string
+		var pageSize int
+		format := formatTable
+		cmd := &cobra.Command{Use: "query"}
+		cmd.Flags().Var(newIDValue(&dataSourceID), "data-source-id", "Target Notion data source ID or URL")
+		formatFlag(cmd, &format, "Output format", formats...)
+		cmd.Flags().IntVar(&pageSize, "page-size", 0, "Page size (max 100)")
+		return cmd, &dataSourceID, &format, &pageSize
+	}
+
+	cmd, dataSourceID, format, pageSize := newCmd(formatJSON, formatJSONL, formatCSV)
+	if err := applyProfileDefaults(cmd, "work"); err != nil {
+		t.Fatalf("applyProfileDefaults returned error: %v", err)
+	}
+	if *dataSourceID != "1a2b3c4d-5e6f-7a8b-9c0d-1e2f3a4b5c6d" || *format != "csv" || *pageSize != 25 {
+		t.Fatalf("defaults not applied: %q %q %d", *dataSourceID, *format, *pageSize)
+	}
+
+	cmd, _, format, pageSize = newCmd(formatJSON, formatTable)
+	if err := cmd.Flags().Set("page-size", "5"); err != nil {
+		t.Fatalf("set page-size: %v", err)
+	}
+	if err := applyProfileDefaults(cmd, "work"); err != nil {
+		t.Fatalf("applyProfileDefaults returned error: %v", err)
+	}
+	if *format != formatTable || *pageSize != 5 {
+		t.Fatalf("expected explicit and unsupported values to win, got %q %d", *format, *pageSize)
+	}
+}
+
+func TestApplyProfileDefaultsReportsUnknownDataSource(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := config.SetProfileDefault("work", config.DefaultDataSource, "taks"); err != nil {
+		t.Fatalf("SetProfileDefault returned error: %v", err)
+	}
+
+	var dataSourceID string
+	cmd := &cobra.Command{Use: "query"}
+	cmd.Flags().Var(newIDValue(&dataSourceID), "data-source-id", "Target Notion data source ID or URL")
+	err := applyProfileDefaults(cmd, "work")
+	if err == nil || !strings.Contains(err.Error(), `profile default data source "taks" is neither a saved alias`) {
+		t.Fatalf("expected an error naming the alias, got %v", err)
+	}
+}
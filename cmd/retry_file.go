@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/filelock"
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+const failuresFilePermission = 0o600
+
+// retryOptions writes the items a bulk command could not process to a failures file and
+// reads them back with --retry-from, so a rerun sends only what failed.
+type retryOptions struct {
+	command      string
+	failuresPath string
+	retryFrom    string
+	failed       []failedItem
+	recordOnly   bool
+}
+
+// failureReport is the failures file. Command guards against retrying one command's
+// failures with another.
+type failureReport struct {
+	Command  string       `json:"command"`
+	Failures []failedItem `json:"failures"`
+}
+
+// failedItem is one item that failed: its 0-based position in the original input, its
+// 1-based input line when the input has lines, the error, and the payload needed to retry.
+type failedItem struct {
+	Payload json.RawMessage `json:"payload"`
+	Error   string          `json:"error"`
+	Index   int             `json:"index"`
+	Line    int             `json:"line,omitempty"`
+}
+
+// defaultFailuresFile names the failures file of command, such as
+// failures-pages-bulk-update.json, so bulk commands run from one directory keep separate files.
+func defaultFailuresFile(command string) string {
+	return "failures-" + strings.ReplaceAll(command, " ", "-") + ".json"
+}
+
+// register adds --failures-file and --retry-from, making --retry-from an alternative to the
+// command's required input flag. Commands that select their items with a query pass no
+// input flag and keep their usual flags on a retry. command names the command in the
+// failures file.
+func (r *retryOptions) register(cmd *cobra.Command, command, inputFlag string) {
+	r.command = command
+	cmd.Flags().StringVar(
+		&r.failuresPath,
+		"failures-file",
+		defaultFailuresFile(command),
+		"Write items that fail, with their error and payload, to this file",
+	)
+	cmd.Flags().StringVar(&r.retryFrom, "retry-from", "", "Process only the items in this failures file instead of the input")
+	if inputFlag != "" {
+		cmd.MarkFlagsOneRequired(inputFlag, "retry-from")
+		cmd.MarkFlagsMutuallyExclusive(inputFlag, "retry-from")
+	}
+}
+
+// registerRecordOnly adds only --failures-file, for commands whose items cannot be retried
+// on their own. The file lists what failed and why.
+func (r *retryOptions) registerRecordOnly(cmd *cobra.Command, command string) {
+	r.command = command
+	r.recordOnly = true
+	cmd.Flags().StringVar(
+		&r.failuresPath,
+		"failures-file",
+		defaultFailuresFile(command),
+		"Write items that fail, with their error, to this file",
+	)
+}
+
+// record adds a failed item. A payload that cannot be encoded is stored as null, which
+// fails again on retry with a clear error instead of being lost.
+func (r *retryOptions) record(index, line int, payload any, err error) {
+	data, merr := json.Marshal(payload)
+	if merr != nil {
+		data = nil
+	}
+	r.failed = append(r.failed, failedItem{Payload: data, Error: err.Error(), Index: index, Line: line})
+}
+
+// load reads the items of the --retry-from file, which must have been written by the same
+// command.
+func (r *retryOptions) load() ([]failedItem, error) {
+	data, err := os.ReadFile(r.retryFrom) // #nosec G304 -- reading user-supplied failures file is intentional
+	if err != nil {
+		return nil, fmt.Errorf("read failures file: %w", err)
+	}
+	var report failureReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("decode failures file: %w", err)
+	}
+	if report.Command != r.command {
+		return nil, fmt.Errorf("%s holds failures of %q, not %q", r.retryFrom, report.Command, r.command)
+	}
+	return report.Failures, nil
+}
+
+// save writes the recorded failures. When nothing failed it removes a failures file left by
+// an earlier run of the same command, so stale items are not retried again.
+func (r *retryOptions) save(log io.Writer) error {
+	if r.failuresPath == "" {
+		return nil
+	}
+	if len(r.failed) == 0 {
+		return r.removeStale()
+	}
+	data, err := json.MarshalIndent(failureReport{Command: r.command, Failures: r.failed}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode failures: %w", err)
+	}
+	if err := filelock.WriteFile(r.failuresPath, append(data, '\n'), failuresFilePermission); err != nil {
+		return fmt.Errorf("write failures file: %w", err)
+	}
+	if r.recordOnly {
+		safeLog(log, "Wrote %d failed items to %s", len(r.failed), r.failuresPath)
+		return nil
+	}
+	safeLog(log, "Wrote %d failed items to %s; rerun with --retry-from %s", len(r.failed), r.failuresPath, r.failuresPath)
+	return nil
+}
+
+// removeStale removes the failures file when it holds failures of this command. A file
+// written by another command, or that is not a failures file, is left alone.
+func (r *retryOptions) removeStale() error {
+	data, err := os.ReadFile(r.failuresPath) // #nosec G304 -- reading user-supplied failures file is intentional
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read failures file: %w", err)
+	}
+	var report failureReport
+	if json.Unmarshal(data, &report) != nil || report.Command != r.command {
+		return nil
+	}
+	if err := os.Remove(r.failuresPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove failures file: %w", err)
+	}
+	return nil
+}
+
+// decodePayload decodes a failed item's payload into v.
+func decodePayload(item failedItem, v any) error {
+	if len(item.Payload) == 0 || string(item.Payload) == "null" {
+		return errors.New("no payload to retry; fix the input row and run it again")
+	}
+	if err := json.Unmarshal(item.Payload, v); err != nil {
+		return fmt.Errorf("decode payload: %w", err)
+	}
+	return nil
+}
+
+// pageUpdater is the part of the Notion client that retries of page updates need.
+type pageUpdater interface {
+	UpdatePage(ctx context.Context, pageID string, req notion.UpdatePageRequest) (notion.Page, error)
+}
+
+// pageUpdatePayload is how a failed page update is kept in the failures file.
+type pageUpdatePayload struct {
+	Properties map[string]any `json:"properties,omitempty"`
+	PageID     string         `json:"page_id"`
+	Archived   bool           `json:"archived,omitempty"`
+}
+
+func (p pageUpdatePayload) request() notion.UpdatePageRequest {
+	req := notion.UpdatePageRequest{Properties: p.Properties}
+	if p.Archived {
+		req.Archived = &p.Archived
+	}
+	return req
+}
+
+// retryPageUpdates resends the page updates of the --retry-from file with up to
+// --concurrency requests in flight. Updates that fail again are logged and recorded, and the
+// counts of updated and failed pages are returned.
+func (r *retryOptions) retryPageUpdates(
+	ctx context.Context,
+	exec executionOptions,
+	client pageUpdater,
+	log io.Writer,
+) (int, int, error) {
+	items, err := r.load()
+	if err != nil {
+		return 0, 0, err
+	}
+	payloads := make([]pageUpdatePayload, len(items))
+	failed := make([]error, len(items))
+	_ = exec.forEach(ctx, len(items), func(ctx context.Context, i int) error {
+		if failed[i] = decodePayload(items[i], &payloads[i]); failed[i] == nil {
+			_, failed[i] = client.UpdatePage(ctx, payloads[i].PageID, payloads[i].request())
+		}
+		return nil
+	})
+
+	updated := 0
+	for i, item := range items {
+		if failed[i] != nil {
+			safeLog(log, "page %s: %v", payloads[i].PageID, failed[i])
+			r.failed = append(r.failed, failedItem{Payload: item.Payload, Error: failed[i].Error(), Index: item.Index, Line: item.Line})
+			continue
+		}
+		updated++
+	}
+	return updated, len(items) - updated, nil
+}
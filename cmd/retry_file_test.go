@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+// flakyImportClient fails the first failures creates, as a burst of rate limiting would.
+type flakyImportClient struct {
+	*fakeImportClient
+	failures int
+}
+
+func (f *flakyImportClient) CreatePage(ctx context.Context, req notion.CreatePageRequest) (notion.Page, error) {
+	f.mu.Lock()
+	if f.failures > 0 {
+		f.failures--
+		f.mu.Unlock()
+		return notion.Page{}, errors.New("rate limited")
+	}
+	f.mu.Unlock()
+	return f.fakeImportClient.CreatePage(ctx, req)
+}
+
+func TestImportFailuresFileRoundTrip(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{
+		Properties: map[string]notion.PropertyReference{
+			"Name": {ID: "title", Name: "Name", Type: "title"},
+		},
+	})
+	path := filepath.Join(t.TempDir(), "failures.json")
+	newOpts := func() *dsImportOptions {
+		return &dsImportOptions{
+			dataSourceID: "ds",
+			index:        idx,
+			exec:         executionOptions{concurrency: 1, batchSize: 10, requestsPerSecond: 1},
+			retry:        retryOptions{command: "ds import", failuresPath: path},
+		}
+	}
+	client := &flakyImportClient{fakeImportClient: &fakeImportClient{}, failures: 2}
+
+	opts := newOpts()
+	input := strings.NewReader("{\"Name\":\"a\"}\n{\"Name\":\"b\"}\n\n{\"Name\":\"c\"}\n")
+	summary, err := opts.importRows(context.Background(), client, input, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("importRows returned error: %v", err)
+	}
+	if summary.Failed != 2 || summary.Created != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if err := opts.retry.save(&bytes.Buffer{}); err != nil {
+		t.Fatalf("save returned error: %v", err)
+	}
+
+	retry := newOpts()
+	retry.retry.retryFrom = path
+	items, err := retry.retry.load()
+	if err != nil {
+		t.Fatalf("load returned error: %v", err)
+	}
+	if len(items) != 2 || items[1].Index != 1 || items[1].Line != 2 || items[1].Error != "create page: rate limited" {
+		t.Fatalf("unexpected failures: %+v", items)
+	}
+	var row map[string]any
+	if err := decodePayload(items[1], &row); err != nil || row["Name"] != "b" {
+		t.Fatalf("payload = %v, %v", row, err)
+	}
+
+	summary, err = retry.retryRows(context.Background(), client, items, &bytes.Buffer{})
+	if err != nil || summary.Created != 2 || summary.Failed != 0 {
+		t.Fatalf("retryRows = %+v, %v", summary, err)
+	}
+	if err := retry.retry.save(&bytes.Buffer{}); err != nil {
+		t.Fatalf("save returned error: %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("failures file should be removed after a clean retry, stat err = %v", err)
+	}
+}
+
+// flakyBackfillClient fails the first update of each page in failing.
+type flakyBackfillClient struct {
+	*fakeBackfillClient
+	failing map[string]bool
+}
+
+func (f *flakyBackfillClient) UpdatePage(ctx context.Context, pageID string, req notion.UpdatePageRequest) (notion.Page, error) {
+	f.mu.Lock()
+	if f.failing[pageID] {
+		delete(f.failing, pageID)
+		f.mu.Unlock()
+		return notion.Page{}, errors.New("rate limited")
+	}
+	f.mu.Unlock()
+	return f.fakeBackfillClient.UpdatePage(ctx, pageID, req)
+}
+
+func TestBackfillFailuresFileRoundTrip(t *testing.T) {
+	target := notion.PropertyReference{ID: "status", Name: "Status", Type: "rich_text"}
+	path := filepath.Join(t.TempDir(), "failures.json")
+	newJob := func(retry *retryOptions) *backfillJob {
+		return &backfillJob{
+			dataSourceID: "ds",
+			target:       target,
+			value:        func(notion.Page) (string, error) { return "Todo", nil },
+			exec:         executionOptions{concurrency: 1, batchSize: 2, requestsPerSecond: 1},
+			retry:        retry,
+		}
+	}
+	client := &flakyBackfillClient{
+		fakeBackfillClient: &fakeBackfillClient{
+			rows:    []notion.Page{{ID: "p1"}, {ID: "p2"}, {ID: "p3"}},
+			updates: map[string]map[string]any{},
+		},
+		failing: map[string]bool{"p1": true},
+	}
+
+	first := &retryOptions{command: "ds backfill", failuresPath: path}
+	summary, err := newJob(first).run(context.Background(), client, &bytes.Buffer{}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 rows failed") {
+		t.Fatalf("run error = %v, want a failed-rows error", err)
+	}
+	if summary.Updated != 2 || summary.Failed != 1 || client.updates["p3"] == nil {
+		t.Fatalf("a failed row should not stop later batches: %+v", summary)
+	}
+	if err := first.save(&bytes.Buffer{}); err != nil {
+		t.Fatalf("save returned error: %v", err)
+	}
+
+	retry := &retryOptions{command: "ds backfill", failuresPath: path, retryFrom: path}
+	summary, err = newJob(retry).run(context.Background(), client, &bytes.Buffer{}, &bytes.Buffer{})
+	if err != nil || summary.Updated != 1 || summary.Failed != 0 {
+		t.Fatalf("retry = %+v, %v", summary, err)
+	}
+	if _, ok := client.updates["p1"]["Status"]; !ok {
+		t.Fatalf("retry did not resend the update of p1: %#v", client.updates)
+	}
+	if err := retry.save(&bytes.Buffer{}); err != nil {
+		t.Fatalf("save returned error: %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("failures file should be removed after a clean retry, stat err = %v", err)
+	}
+}
+
+func TestRetryFromRejectsOtherCommandsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "failures.json")
+	writer := retryOptions{command: "pages link", failuresPath: path}
+	writer.record(0, 2, linkPayload{Source: "a"}, errors.New("boom"))
+	if err := writer.save(&bytes.Buffer{}); err != nil {
+		t.Fatalf("save returned error: %v", err)
+	}
+
+	reader := retryOptions{command: "ds import", retryFrom: path}
+	if _, err := reader.load(); err == nil || !strings.Contains(err.Error(), `"pages link"`) {
+		t.Fatalf("expected a command mismatch error, got %v", err)
+	}
+}
+
+func TestCleanRunRemovesOwnStaleFailuresFile(t *testing.T) {
+	dir := t.TempDir()
+	own := filepath.Join(dir, defaultFailuresFile("pages bulk-update"))
+	other := filepath.Join(dir, "other.json")
+	for path, command := range map[string]string{own: "pages bulk-update", other: "ds backfill"} {
+		stale := retryOptions{command: command, failuresPath: path}
+		stale.record(0, 2, pageUpdatePayload{PageID: "p"}, errors.New("boom"))
+		if err := stale.save(&bytes.Buffer{}); err != nil {
+			t.Fatalf("save returned error: %v", err)
+		}
+	}
+
+	clean := retryOptions{command: "pages bulk-update", failuresPath: own}
+	if err := clean.save(&bytes.Buffer{}); err != nil {
+		t.Fatalf("save returned error: %v", err)
+	}
+	if _, err := os.Stat(own); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("stale failures file should be removed after a clean run, stat err = %v", err)
+	}
+
+	clean.failuresPath = other
+	if err := clean.save(&bytes.Buffer{}); err != nil {
+		t.Fatalf("save returned error: %v", err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Fatalf("another command's failures file should be kept, stat err = %v", err)
+	}
+}
+
+func TestDecodePayloadRejectsMissingPayload(t *testing.T) {
+	var properties map[string]any
+	if err := decodePayload(failedItem{Payload: []byte("null")}, &properties); err == nil {
+		t.Fatal("expected an error for a null payload")
+	}
+}
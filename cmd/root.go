@@ -3,43 +3,163 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/config"
 )
 
 type globalOptions struct {
-	profile string
+	profile         string
+	tokenEnv        string
+	maxRetries      optionalInt
+	rps             float64
+	backoffBase     time.Duration
+	httpCacheTTL    time.Duration
+	httpCacheTTLSet bool
+	withMeta        bool
+	debug           bool
+	debugBody       bool
+	stats           bool
+	noPager         bool
 }
 
 var globals = &globalOptions{
 	profile: "default",
 }
 
-var rootCmd = &cobra.Command{
-	Use:           "notionctl",
-	Short:         "CLI for working with the modern Notion API",
-	SilenceUsage:  true,
-	SilenceErrors: true,
-}
+var rootCmd = newRootCmd(globals)
 
 // Execute runs the command hierarchy.
 func Execute() error {
-	if err := rootCmd.Execute(); err != nil {
+	started := time.Now()
+	cmd, err := rootCmd.ExecuteC()
+	closePager(cmd)
+	recordUsage(cmd, started, err)
+	recordRecentUse(cmd, globals.profile, err)
+	reportRunStats(cmd, rootCmd.ErrOrStderr(), started)
+	if err != nil {
 		return fmt.Errorf("execute command: %w", err)
 	}
 	return nil
 }
 
+// newRootCmd builds the command tree. The shell builds a fresh tree for every line, since
+// cobra keeps flag values between executions.
+func newRootCmd(globals *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "notionctl",
+		Short:         "CLI for working with the modern Notion API",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// A shell line keeps the --http-cache-ttl given to the shell itself.
+			globals.httpCacheTTLSet = globals.httpCacheTTLSet || cmd.Flags().Changed("http-cache-ttl")
+			if err := applyProfileDefaults(cmd, globals.profile); err != nil {
+				return err
+			}
+			if err := pickMissingDataSource(cmd, globals.profile); err != nil {
+				return err
+			}
+			if globals.withMeta {
+				startRunMeta(cmd, args, globals.profile)
+			}
+			if globals.stats {
+				startRunStats(cmd)
+			}
+			startExpansionCache(cmd)
+			startPager(cmd, globals)
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&globals.profile, "profile", globals.profile, "Auth profile to use")
+	cmd.PersistentFlags().StringVar(
+		&globals.tokenEnv,
+		"token-env",
+		globals.tokenEnv,
+		"Read the Notion token from this environment variable instead of the keyring",
+	)
+	cmd.PersistentFlags().Var(
+		&globals.maxRetries,
+		"max-retries",
+		"Retries of rate-limited and 5xx responses; 0 disables them (default: the profile's max_retries, else 5)",
+	)
+	cmd.PersistentFlags().Float64Var(
+		&globals.rps,
+		"rps",
+		globals.rps,
+		"Sustained requests per second (default: the profile's rps, else 3)",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&globals.backoffBase,
+		"backoff-base",
+		globals.backoffBase,
+		"First retry delay, doubled on each further retry (default: the profile's backoff_base, else 500ms)",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&globals.httpCacheTTL,
+		"http-cache-ttl",
+		globals.httpCacheTTL,
+		"Reuse cached page, data source, and database reads on disk for this long (default: $"+httpCacheTTLEnv+", else off)",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&globals.withMeta,
+		"with-meta",
+		false,
+		"Wrap JSON output in an envelope with the query, timing, request count, cursor, and API version",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&globals.stats,
+		"stats",
+		false,
+		"Print API calls, retries, rate-limit waits, and latency to stderr when the command ends",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&globals.noPager,
+		"no-pager",
+		false,
+		"Never page long table or Markdown output (default: pipe it through $PAGER on a terminal)",
+	)
+	cmd.PersistentFlags().BoolVarP(
+		&globals.debug,
+		"debug",
+		"v",
+		false,
+		"Log each HTTP request's method, path, status, attempt, and latency to stderr",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&globals.debugBody,
+		"debug-body",
+		false,
+		"Like --debug, and also log headers (credentials redacted) and request and response bodies",
+	)
+
+	cmd.AddCommand(newAuthCmd(globals))
+	cmd.AddCommand(newDSCmd(globals))
+	cmd.AddCommand(newPagesCmd(globals))
+	cmd.AddCommand(newBlocksCmd(globals))
+	cmd.AddCommand(newChangesCmd(globals))
+	cmd.AddCommand(newSyncCmd(globals))
+	cmd.AddCommand(newTriageCmd(globals))
+	cmd.AddCommand(newPickCmd(globals))
+	cmd.AddCommand(newExamplesCmd(globals))
+	cmd.AddCommand(newStatsCmd(globals))
+	cmd.AddCommand(newUpgradeCheckCmd(globals))
+	cmd.AddCommand(newServeCmd(globals))
+	cmd.AddCommand(newCalendarCmd(globals))
+	cmd.AddCommand(newBackupCmd(globals))
+
+	return cmd
+}
+
 func init() {
-	rootCmd.PersistentFlags().StringVar(&globals.profile, "profile", globals.profile, "Auth profile to use")
+	config.SetPassphrasePrompt(promptPassphrase)
 
 	rootCmd.SetErr(os.Stderr)
 	rootCmd.SetOut(os.Stdout)
 
-	rootCmd.AddCommand(newAuthCmd(globals))
-	rootCmd.AddCommand(newDSCmd(globals))
-	rootCmd.AddCommand(newPagesCmd(globals))
-	rootCmd.AddCommand(newBlocksCmd(globals))
-	rootCmd.AddCommand(newChangesCmd(globals))
-	rootCmd.AddCommand(newSyncCmd(globals))
+	rootCmd.AddCommand(newShellCmd(globals))
 }
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+type runStatsKey struct{}
+
+// startRunStats counts the command's API calls for --stats, sharing --with-meta's counters
+// when both are set.
+func startRunStats(cmd *cobra.Command) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	stats := &notion.Stats{}
+	if meta := runMetaFromContext(ctx); meta != nil {
+		stats = meta.stats
+	} else {
+		ctx = notion.WithStats(ctx, stats)
+	}
+	cmd.SetContext(context.WithValue(ctx, runStatsKey{}, stats))
+}
+
+// reportRunStats prints the --stats summary to w, whether or not the command succeeded.
+func reportRunStats(cmd *cobra.Command, w io.Writer, started time.Time) {
+	if cmd == nil || cmd.Context() == nil {
+		return
+	}
+	stats, _ := cmd.Context().Value(runStatsKey{}).(*notion.Stats)
+	if stats == nil {
+		return
+	}
+	safeLog(w,
+		"notionctl stats: %d API calls (retries: %d, rate limited: %d) in %s; "+
+			"HTTP %s, rate limiter wait %s, retry backoff %s",
+		stats.Requests(), stats.Retries(), stats.RateLimited(), roundStat(time.Since(started)),
+		roundStat(stats.Latency()), roundStat(stats.LimiterWait()), roundStat(stats.Backoff()))
+}
+
+func roundStat(d time.Duration) time.Duration {
+	return d.Round(time.Millisecond)
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/time/rate"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestReportRunStatsSummarizesRequests(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := notion.NewClient(notion.ClientConfig{Token: "secret", BaseURL: srv.URL})
+	client.WithLimiter(rate.NewLimiter(rate.Inf, 0))
+	client.WithSleeper(func(time.Duration) {})
+
+	cmd := &cobra.Command{Use: "query"}
+	cmd.SetContext(context.Background())
+	startRunStats(cmd)
+	if err := client.Do(cmd.Context(), http.MethodGet, "users/me", nil, nil); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+
+	var out strings.Builder
+	reportRunStats(cmd, &out, time.Now())
+	if got := out.String(); !strings.HasPrefix(got, "notionctl stats: 2 API calls (retries: 1, rate limited: 1) in ") ||
+		!strings.Contains(got, "retry backoff") {
+		t.Fatalf("unexpected summary %q", got)
+	}
+
+	out.Reset()
+	plain := &cobra.Command{Use: "query"}
+	plain.SetContext(context.Background())
+	reportRunStats(plain, &out, time.Now())
+	if out.Len() != 0 {
+		t.Fatalf("expected no summary without --stats, got %q", out.String())
+	}
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const serveTokenEnv = "NOTIONCTL_SERVE_TOKEN" // #nosec G101 -- environment variable name, not a secret
+
+// serveToken returns the bearer token a server requires: the flag value, or the environment.
+func serveToken(flag string) string {
+	return firstNonEmpty(strings.TrimSpace(flag), strings.TrimSpace(os.Getenv(serveTokenEnv)))
+}
+
+// loopbackListen reports whether addr only accepts connections from this machine.
+func loopbackListen(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// checkServeExposure refuses to serve the stored token's workspace to other machines unless
+// the server is limited to some data sources (restricted) or requires a bearer token.
+func checkServeExposure(addr string, restricted bool, token, limitFlag string) error {
+	if loopbackListen(addr) || restricted || token != "" {
+		return nil
+	}
+	return fmt.Errorf(
+		"--listen %s accepts connections from other machines; limit the data sources served with %s "+
+			"or require a bearer token with --token (or $%s)",
+		addr, limitFlag, serveTokenEnv,
+	)
+}
+
+// checkServeWrites refuses to accept writes from other machines without a bearer token:
+// limiting the data sources served still leaves every page in them writable by anyone who
+// can reach the address.
+func checkServeWrites(addr string, allowWrites bool, token string) error {
+	if !allowWrites || loopbackListen(addr) || token != "" {
+		return nil
+	}
+	return fmt.Errorf(
+		"--allow-writes with --listen %s accepts writes from other machines; require a bearer token with --token (or $%s)",
+		addr, serveTokenEnv,
+	)
+}
+
+// requireBearer rejects requests without "Authorization: Bearer <token>" when token is set.
+func requireBearer(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "missing or invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
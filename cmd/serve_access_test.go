@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckServeExposure(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1:8080", "localhost:8080", "[::1]:8080"} {
+		if err := checkServeExposure(addr, false, "", "--data-source"); err != nil {
+			t.Fatalf("checkServeExposure(%q) = %v, want loopback allowed", addr, err)
+		}
+	}
+	for _, addr := range []string{":8080", "0.0.0.0:8080", "192.168.1.5:8080"} {
+		if err := checkServeExposure(addr, false, "", "--data-source"); err == nil {
+			t.Fatalf("checkServeExposure(%q) allowed an open, unauthenticated listener", addr)
+		}
+		if err := checkServeExposure(addr, true, "", "--data-source"); err != nil {
+			t.Fatalf("checkServeExposure(%q) with an allow-list = %v", addr, err)
+		}
+		if err := checkServeExposure(addr, false, "s3cret", "--data-source"); err != nil {
+			t.Fatalf("checkServeExposure(%q) with a token = %v", addr, err)
+		}
+	}
+}
+
+func TestCheckServeWrites(t *testing.T) {
+	if err := checkServeWrites("127.0.0.1:8080", true, ""); err != nil {
+		t.Fatalf("checkServeWrites on loopback = %v", err)
+	}
+	if err := checkServeWrites("0.0.0.0:8080", false, ""); err != nil {
+		t.Fatalf("checkServeWrites without --allow-writes = %v", err)
+	}
+	if err := checkServeWrites("0.0.0.0:8080", true, ""); err == nil {
+		t.Fatal("checkServeWrites allowed unauthenticated writes from other machines")
+	}
+	if err := checkServeWrites("0.0.0.0:8080", true, "s3cret"); err != nil {
+		t.Fatalf("checkServeWrites with a token = %v", err)
+	}
+}
+
+func TestRequireBearer(t *testing.T) {
+	handler := requireBearer("s3cret", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	for header, want := range map[string]int{
+		"":               http.StatusUnauthorized,
+		"Bearer wrong":   http.StatusUnauthorized,
+		"Bearer s3cret":  http.StatusNoContent,
+		"Basic czNjcmV0": http.StatusUnauthorized,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Fatalf("Authorization %q status = %d, want %d", header, rec.Code, want)
+		}
+	}
+}
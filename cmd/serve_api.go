@@ -0,0 +1,379 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/notionid"
+	"github.com/yourorg/notionctl/internal/querycache"
+	"github.com/yourorg/notionctl/internal/schema"
+	"github.com/yourorg/notionctl/internal/where"
+)
+
+const (
+	defaultServeAPIListen = "127.0.0.1:8080"
+	defaultAPIRowLimit    = 100
+)
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type serveAPIOptions struct {
+	listenAddr  string
+	dataSources []string
+	token       string
+	ttl         time.Duration
+	allowWrites bool
+}
+
+// apiClient is the subset of the Notion client behind the REST API.
+type apiClient interface {
+	dataSourceQuerier
+	GetDataSource(ctx context.Context, dataSourceID string) (notion.DataSource, error)
+	RetrievePage(ctx context.Context, pageID string) (notion.Page, error)
+	UpdatePage(ctx context.Context, pageID string, req notion.UpdatePageRequest) (notion.Page, error)
+}
+
+// apiServer serves rows and page updates with properties keyed by name. Query results and
+// schemas are cached for ttl; an update drops its data source's cached queries.
+type apiServer struct {
+	client      apiClient
+	cache       *querycache.Cache
+	resolve     func(ref string) (string, bool)
+	allowed     map[string]bool
+	ttl         time.Duration
+	allowWrites bool
+
+	mu      sync.Mutex
+	schemas map[string]cachedSchema
+}
+
+type cachedSchema struct {
+	fetched time.Time
+	idx     *schema.Index
+}
+
+// apiRow is a page as the API returns it: plain property values keyed by name.
+type apiRow struct {
+	CreatedTime    time.Time      `json:"created_time"`
+	LastEditedTime time.Time      `json:"last_edited_time"`
+	Properties     map[string]any `json:"properties"`
+	ID             string         `json:"id"`
+	URL            string         `json:"url"`
+}
+
+// apiRows is the response of GET /ds/{alias}/rows.
+type apiRows struct {
+	Rows       []apiRow `json:"rows"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+	HasMore    bool     `json:"has_more"`
+}
+
+func newServeAPICmd(globals *globalOptions) *cobra.Command {
+	opts := &serveAPIOptions{listenAddr: defaultServeAPIListen, ttl: defaultQueryCacheTTL}
+
+	cmd := &cobra.Command{
+		Use:   "api",
+		Short: "Serve a simplified REST API over data source rows and pages",
+		Long: "Serve GET /ds/{alias}/rows, which accepts ?where=, ?sort=, ?limit=, and ?cursor= and returns rows " +
+			"with plain values keyed by property name, and PATCH /pages/{id}, which takes a JSON object of " +
+			"property names and values when --allow-writes is set. Query results are cached for --cache-ttl " +
+			"and dropped after updates. The server listens on loopback by default; other addresses need " +
+			"--data-source or --token, and --token when --allow-writes is set.",
+		RunE: opts.run(globals),
+	}
+
+	cmd.Flags().StringVar(&opts.listenAddr, "listen", opts.listenAddr, "Address to serve HTTP on (host:port)")
+	cmd.Flags().DurationVar(&opts.ttl, "cache-ttl", opts.ttl, "How long query results and schemas are reused")
+	cmd.Flags().StringSliceVar(
+		&opts.dataSources,
+		"data-source",
+		nil,
+		"Only serve these data source aliases or IDs (default: any the integration can read)",
+	)
+	cmd.Flags().BoolVar(&opts.allowWrites, "allow-writes", false, "Accept PATCH requests (default: read-only)")
+	cmd.Flags().StringVar(
+		&opts.token,
+		"token",
+		"",
+		"Bearer token clients must send in the Authorization header (default: $"+serveTokenEnv+")",
+	)
+
+	return cmd
+}
+
+func (opts *serveAPIOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		if opts.ttl <= 0 {
+			return errors.New("--cache-ttl must be greater than zero")
+		}
+		token := serveToken(opts.token)
+		if err := checkServeExposure(opts.listenAddr, len(opts.dataSources) > 0, token, "--data-source"); err != nil {
+			return err
+		}
+		if err := checkServeWrites(opts.listenAddr, opts.allowWrites, token); err != nil {
+			return err
+		}
+		client, err := buildClient(globals)
+		if err != nil {
+			return err
+		}
+		settings, err := config.LoadDataSourceSettings(globals.profile)
+		if err != nil {
+			return fmt.Errorf("load data source aliases: %w", err)
+		}
+		srv, err := newAPIServer(client, settings.ResolveDataSource, opts.dataSources, opts.ttl)
+		if err != nil {
+			return err
+		}
+		srv.allowWrites = opts.allowWrites
+
+		server := &http.Server{
+			Addr:              opts.listenAddr,
+			Handler:           requireBearer(token, srv.handler()),
+			ReadHeaderTimeout: serverReadTimeout,
+		}
+		errCh := make(chan error, 1)
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- fmt.Errorf("api server: %w", err)
+			}
+		}()
+		defer func() {
+			shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), serverShutdownTimeout)
+			defer cancelShutdown()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+		safeLog(cmd.ErrOrStderr(), "Serving the REST API on http://%s", opts.listenAddr)
+
+		select {
+		case <-cmd.Context().Done():
+			return nil
+		case err := <-errCh:
+			return err
+		}
+	}
+}
+
+func newAPIServer(
+	client apiClient,
+	resolve func(string) (string, bool),
+	dataSources []string,
+	ttl time.Duration,
+) (*apiServer, error) {
+	srv := &apiServer{
+		client:  client,
+		cache:   querycache.New(ttl),
+		resolve: resolve,
+		ttl:     ttl,
+		schemas: map[string]cachedSchema{},
+	}
+	for _, ref := range dataSources {
+		id, err := srv.dataSourceID(ref)
+		if err != nil {
+			return nil, fmt.Errorf("--data-source %q: %w", ref, err)
+		}
+		if srv.allowed == nil {
+			srv.allowed = map[string]bool{}
+		}
+		srv.allowed[id] = true
+	}
+	return srv, nil
+}
+
+func (s *apiServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ds/{alias}/rows", s.serveRows)
+	mux.HandleFunc("PATCH /pages/{id}", s.servePatch)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		respondJSON(w, http.StatusOK, s.cache.Stats())
+	})
+	return mux
+}
+
+func (s *apiServer) serveRows(w http.ResponseWriter, r *http.Request) {
+	id, err := s.dataSourceID(r.PathValue("alias"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if s.allowed != nil && !s.allowed[id] {
+		http.Error(w, "data source not served", http.StatusForbidden)
+		return
+	}
+	idx, err := s.index(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), upstreamStatus(err))
+		return
+	}
+	req, err := rowsRequest(r, idx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, ok := s.cache.Get(id, req)
+	w.Header().Set("X-Cache", "HIT")
+	if !ok {
+		if resp, err = s.client.QueryDataSource(r.Context(), id, req); err != nil {
+			http.Error(w, err.Error(), upstreamStatus(err))
+			return
+		}
+		s.cache.Put(id, req, resp)
+		w.Header().Set("X-Cache", "MISS")
+	}
+
+	out := apiRows{Rows: make([]apiRow, 0, len(resp.Results)), HasMore: resp.HasMore, NextCursor: resp.NextCursor}
+	for _, page := range resp.Results {
+		out.Rows = append(out.Rows, newAPIRow(page))
+	}
+	respondJSON(w, http.StatusOK, out)
+}
+
+// rowsRequest builds the query from ?where= (the --where syntax), ?sort= (the --sort
+// shorthand), ?limit= (at most 100), and ?cursor=.
+func rowsRequest(r *http.Request, idx *schema.Index) (notion.QueryDataSourceRequest, error) {
+	query := r.URL.Query()
+	req := notion.QueryDataSourceRequest{PageSize: defaultAPIRowLimit, StartCursor: query.Get("cursor")}
+	if expr := query.Get("where"); expr != "" {
+		filter, err := where.Compile(expr, idx)
+		if err != nil {
+			return req, fmt.Errorf("parse where: %w", err)
+		}
+		req.Filter = filter
+	}
+	sorts, err := parseSortShorthand(query.Get("sort"), idx)
+	if err != nil {
+		return req, fmt.Errorf("parse sort: %w", err)
+	}
+	req.Sorts = sorts
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > maxQueryPageSize {
+			return req, fmt.Errorf("limit must be between 1 and %d", maxQueryPageSize)
+		}
+		req.PageSize = limit
+	}
+	return req, nil
+}
+
+// servePatch maps the body's property names onto the page's data source schema, coercing
+// plain values as ds import does, and returns the updated row.
+func (s *apiServer) servePatch(w http.ResponseWriter, r *http.Request) {
+	if !s.allowWrites {
+		http.Error(w, "server is read-only; start it with --allow-writes", http.StatusMethodNotAllowed)
+		return
+	}
+	pageID, err := notionid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var values map[string]any
+	body, err := io.ReadAll(io.LimitReader(r.Body, queryMaxBodyBytes))
+	if err != nil {
+		http.Error(w, "read body", http.StatusBadRequest)
+		return
+	}
+	if err := json.Unmarshal(body, &values); err != nil {
+		http.Error(w, fmt.Sprintf("decode body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	page, err := s.client.RetrievePage(r.Context(), pageID)
+	if err != nil {
+		http.Error(w, err.Error(), upstreamStatus(err))
+		return
+	}
+	dataSourceID, err := notionid.Parse(page.Parent.DataSourceID)
+	if err != nil {
+		http.Error(w, "page is not in a data source", http.StatusBadRequest)
+		return
+	}
+	if s.allowed != nil && !s.allowed[dataSourceID] {
+		http.Error(w, "data source not served", http.StatusForbidden)
+		return
+	}
+	idx, err := s.index(r.Context(), dataSourceID)
+	if err != nil {
+		http.Error(w, err.Error(), upstreamStatus(err))
+		return
+	}
+	properties, err := rowProperties(values, idx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	updated, err := s.client.UpdatePage(r.Context(), pageID, notion.UpdatePageRequest{Properties: properties})
+	if err != nil {
+		http.Error(w, err.Error(), upstreamStatus(err))
+		return
+	}
+	s.cache.Invalidate(dataSourceID)
+	respondJSON(w, http.StatusOK, newAPIRow(updated))
+}
+
+func (s *apiServer) dataSourceID(ref string) (string, error) {
+	resolved, ok := s.resolve(ref)
+	if !ok {
+		return "", fmt.Errorf("unknown data source %q", ref)
+	}
+	id, err := notionid.Parse(resolved)
+	if err != nil {
+		return "", fmt.Errorf("unknown data source %q", ref)
+	}
+	return id, nil
+}
+
+// index returns the data source's schema, fetching it again once it is older than ttl.
+func (s *apiServer) index(ctx context.Context, dataSourceID string) (*schema.Index, error) {
+	s.mu.Lock()
+	entry, ok := s.schemas[dataSourceID]
+	s.mu.Unlock()
+	if ok && time.Since(entry.fetched) < s.ttl {
+		return entry.idx, nil
+	}
+	ds, err := s.client.GetDataSource(ctx, dataSourceID)
+	if err != nil {
+		return nil, fmt.Errorf("get data source: %w", err)
+	}
+	idx := schema.NewIndex(ds)
+	s.mu.Lock()
+	s.schemas[dataSourceID] = cachedSchema{fetched: time.Now(), idx: idx}
+	s.mu.Unlock()
+	return idx, nil
+}
+
+func newAPIRow(page notion.Page) apiRow {
+	row := apiRow{
+		CreatedTime:    page.CreatedTime,
+		LastEditedTime: page.LastEditedTime,
+		Properties:     make(map[string]any, len(page.Properties)),
+		ID:             page.ID,
+		URL:            page.URL,
+	}
+	for name, value := range page.Properties {
+		row.Properties[name] = frontmatterValue(value)
+	}
+	return row
+}
+
+// upstreamStatus passes Notion's HTTP status through, or reports a bad gateway when the
+// request did not get a response.
+func upstreamStatus(err error) int {
+	var apiErr *notion.Error
+	if errors.As(err, &apiErr) && apiErr.Status != 0 {
+		return apiErr.Status
+	}
+	return http.StatusBadGateway
+}
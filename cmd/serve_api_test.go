@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+const apiTasksID = "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d"
+
+// fakeAPIClient serves one data source with a single task page.
+type fakeAPIClient struct {
+	page     notion.Page
+	requests []notion.QueryDataSourceRequest
+	updates  []map[string]any
+}
+
+func (f *fakeAPIClient) GetDataSource(context.Context, string) (notion.DataSource, error) {
+	return notion.DataSource{ID: apiTasksID, Properties: map[string]notion.PropertyReference{
+		"Name":   {ID: "title", Name: "Name", Type: "title"},
+		"Points": {ID: "pts", Name: "Points", Type: "number"},
+		"Status": {ID: "st", Name: "Status", Type: "status"},
+	}}, nil
+}
+
+func (f *fakeAPIClient) QueryDataSource(
+	_ context.Context,
+	_ string,
+	req notion.QueryDataSourceRequest,
+) (notion.QueryDataSourceResponse, error) {
+	f.requests = append(f.requests, req)
+	return notion.QueryDataSourceResponse{Results: []notion.Page{f.page}}, nil
+}
+
+func (f *fakeAPIClient) RetrievePage(context.Context, string) (notion.Page, error) {
+	return f.page, nil
+}
+
+func (f *fakeAPIClient) UpdatePage(_ context.Context, _ string, req notion.UpdatePageRequest) (notion.Page, error) {
+	f.updates = append(f.updates, req.Properties)
+	return f.page, nil
+}
+
+func serveAPIRequest(t *testing.T, handler http.Handler, method, path, body string, wantStatus int) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != wantStatus {
+		t.Fatalf("%s %s status = %d, want %d: %s", method, path, rec.Code, wantStatus, rec.Body.String())
+	}
+	return rec
+}
+
+func TestAPIServerRowsAndPatch(t *testing.T) {
+	points := 3.0
+	client := &fakeAPIClient{page: notion.Page{
+		ID:     "page-1",
+		Parent: notion.PageParent{Type: "data_source_id", DataSourceID: apiTasksID},
+		Properties: map[string]notion.PropertyValue{
+			"Name":   {Type: "title", Title: []notion.RichText{{PlainText: "Ship it"}}},
+			"Points": {Type: "number", Number: &points},
+		},
+	}}
+	resolve := func(ref string) (string, bool) {
+		if ref == "tasks" {
+			return apiTasksID, true
+		}
+		return ref, true
+	}
+	srv, err := newAPIServer(client, resolve, []string{"tasks"}, time.Hour)
+	if err != nil {
+		t.Fatalf("newAPIServer returned error: %v", err)
+	}
+	handler := srv.handler()
+
+	path := "/ds/tasks/rows?where=Points+%3E+2&sort=Points:desc&limit=10"
+	rec := serveAPIRequest(t, handler, http.MethodGet, path, "", http.StatusOK)
+	var rows apiRows
+	if err := json.Unmarshal(rec.Body.Bytes(), &rows); err != nil {
+		t.Fatalf("decode rows: %v", err)
+	}
+	if len(rows.Rows) != 1 || rows.Rows[0].Properties["Name"] != "Ship it" || rows.Rows[0].Properties["Points"] != 3.0 {
+		t.Fatalf("unexpected rows: %+v", rows)
+	}
+	if req := client.requests[0]; req.PageSize != 10 || req.Filter == nil || len(req.Sorts) != 1 {
+		t.Fatalf("unexpected query: %+v", req)
+	}
+	if rec := serveAPIRequest(t, handler, http.MethodGet, path, "", http.StatusOK); rec.Header().Get("X-Cache") != "HIT" {
+		t.Fatalf("repeated query X-Cache = %q, want HIT", rec.Header().Get("X-Cache"))
+	}
+
+	serveAPIRequest(t, handler, http.MethodPatch, "/pages/"+apiTasksID, `{"Points":1}`, http.StatusMethodNotAllowed)
+	srv.allowWrites = true
+	serveAPIRequest(t, handler, http.MethodPatch, "/pages/"+apiTasksID, `{"status":"Done","Points":5}`, http.StatusOK)
+	update := client.updates[0]
+	if _, ok := update["Status"]; !ok || update["Points"].(map[string]any)["number"] != 5.0 {
+		t.Fatalf("unexpected update: %#v", update)
+	}
+	if rec := serveAPIRequest(t, handler, http.MethodGet, path, "", http.StatusOK); rec.Header().Get("X-Cache") != "MISS" {
+		t.Fatalf("query after a PATCH X-Cache = %q, want MISS", rec.Header().Get("X-Cache"))
+	}
+
+	serveAPIRequest(t, handler, http.MethodGet, "/ds/tasks/rows?where=Bogus+%3D+1", "", http.StatusBadRequest)
+	serveAPIRequest(t, handler, http.MethodGet, "/ds/ffffffffffffffffffffffffffffffff/rows", "", http.StatusForbidden)
+	serveAPIRequest(t, handler, http.MethodPatch, "/pages/"+apiTasksID, `{"Bogus":1}`, http.StatusBadRequest)
+}
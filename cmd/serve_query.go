@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/notionid"
+	"github.com/yourorg/notionctl/internal/querycache"
+)
+
+const (
+	defaultServeQueryListen = "127.0.0.1:8091"
+	defaultQueryCacheTTL    = time.Minute
+	defaultQueryPoll        = 30 * time.Second
+	queryMaxBodyBytes       = 1 << 20
+	// lastEditedGranularity is the rounding Notion applies to last_edited_time; polls
+	// overlap by this much so edits late in a minute are not missed.
+	lastEditedGranularity = time.Minute
+)
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type serveQueryOptions struct {
+	listenAddr   string
+	token        string
+	allowed      []string
+	ttl          time.Duration
+	pollInterval time.Duration
+}
+
+// queryServer answers data source queries from a querycache.Cache, invalidating a data
+// source's entries when polling its last_edited_time finds changes.
+type queryServer struct {
+	client  changeClient
+	cache   *querycache.Cache
+	allowed map[string]bool
+	log     io.Writer
+
+	mu          sync.Mutex
+	lastChecked map[string]time.Time
+}
+
+func newServeQueryCmd(globals *globalOptions) *cobra.Command {
+	opts := &serveQueryOptions{
+		listenAddr:   defaultServeQueryListen,
+		ttl:          defaultQueryCacheTTL,
+		pollInterval: defaultQueryPoll,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "query",
+		Short: "Serve data source queries over HTTP from a short-lived in-memory cache",
+		Long: "Proxy POST /data_sources/{id}/query to Notion, caching each result for --cache-ttl. Cached data " +
+			"sources are polled for edits every --poll-interval and dropped from the cache as soon as one changes. " +
+			"The server listens on loopback by default; other addresses need --data-source-id or --token.",
+		RunE: opts.run(globals),
+	}
+
+	cmd.Flags().StringVar(&opts.listenAddr, "listen", opts.listenAddr, "Address to serve HTTP on (host:port)")
+	cmd.Flags().DurationVar(&opts.ttl, "cache-ttl", opts.ttl, "How long a query result is reused")
+	cmd.Flags().DurationVar(
+		&opts.pollInterval,
+		"poll-interval",
+		opts.pollInterval,
+		"How often cached data sources are checked for edits (0 relies on --cache-ttl alone)",
+	)
+	cmd.Flags().StringSliceVar(
+		&opts.allowed,
+		"data-source-id",
+		nil,
+		"Only serve these data source IDs or URLs (default: any the integration can read)",
+	)
+	cmd.Flags().StringVar(
+		&opts.token,
+		"token",
+		"",
+		"Bearer token clients must send in the Authorization header (default: $"+serveTokenEnv+")",
+	)
+
+	return cmd
+}
+
+func (opts *serveQueryOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		if opts.ttl <= 0 {
+			return errors.New("--cache-ttl must be greater than zero")
+		}
+		if opts.pollInterval < 0 {
+			return errors.New("--poll-interval cannot be negative")
+		}
+		token := serveToken(opts.token)
+		if err := checkServeExposure(opts.listenAddr, len(opts.allowed) > 0, token, "--data-source-id"); err != nil {
+			return err
+		}
+		client, err := buildClient(globals)
+		if err != nil {
+			return err
+		}
+		srv, err := newQueryServer(client, querycache.New(opts.ttl), opts.allowed, cmd.ErrOrStderr())
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		server := &http.Server{
+			Addr:              opts.listenAddr,
+			Handler:           requireBearer(token, srv.handler()),
+			ReadHeaderTimeout: serverReadTimeout,
+		}
+		errCh := make(chan error, 1)
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- fmt.Errorf("query server: %w", err)
+			}
+		}()
+		defer func() {
+			shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), serverShutdownTimeout)
+			defer cancelShutdown()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+		safeLog(cmd.ErrOrStderr(), "Serving cached queries on http://%s (ttl %s)", opts.listenAddr, opts.ttl)
+
+		var tick <-chan time.Time
+		if opts.pollInterval > 0 {
+			ticker := time.NewTicker(opts.pollInterval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case err := <-errCh:
+				return err
+			case <-tick:
+				srv.pollChanges(ctx, time.Now().UTC())
+			}
+		}
+	}
+}
+
+func newQueryServer(client changeClient, cache *querycache.Cache, allowed []string, log io.Writer) (*queryServer, error) {
+	srv := &queryServer{client: client, cache: cache, log: log, lastChecked: map[string]time.Time{}}
+	for _, ref := range allowed {
+		id, err := notionid.Parse(ref)
+		if err != nil {
+			return nil, fmt.Errorf("parse --data-source-id %q: %w", ref, err)
+		}
+		if srv.allowed == nil {
+			srv.allowed = map[string]bool{}
+		}
+		srv.allowed[id] = true
+	}
+	return srv, nil
+}
+
+func (s *queryServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /data_sources/{id}/query", s.serveQuery)
+	mux.HandleFunc("POST /invalidate", s.serveInvalidate)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		respondJSON(w, http.StatusOK, s.cache.Stats())
+	})
+	return mux
+}
+
+func (s *queryServer) serveQuery(w http.ResponseWriter, r *http.Request) {
+	id, err := notionid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if s.allowed != nil && !s.allowed[id] {
+		http.Error(w, "data source not served", http.StatusForbidden)
+		return
+	}
+	var req notion.QueryDataSourceRequest
+	body, err := io.ReadAll(io.LimitReader(r.Body, queryMaxBodyBytes))
+	if err != nil {
+		http.Error(w, "read body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, fmt.Sprintf("decode query: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if resp, ok := s.cache.Get(id, req); ok {
+		w.Header().Set("X-Cache", "HIT")
+		respondJSON(w, http.StatusOK, resp)
+		return
+	}
+	// Record the check time before querying so edits made during the query are still seen
+	// by the next poll.
+	s.markChecked(id, time.Now().UTC())
+	resp, err := s.client.QueryDataSource(r.Context(), id, req)
+	if err != nil {
+		http.Error(w, err.Error(), upstreamStatus(err))
+		return
+	}
+	s.cache.Put(id, req, resp)
+	w.Header().Set("X-Cache", "MISS")
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// serveInvalidate drops one data source (?data_source_id=) or, without it, every cached
+// data source. It lets other watchers, such as a sync watch --exec hook, push changes.
+func (s *queryServer) serveInvalidate(w http.ResponseWriter, r *http.Request) {
+	ids := s.cache.DataSources()
+	if raw := r.URL.Query().Get("data_source_id"); raw != "" {
+		id, err := notionid.Parse(raw)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ids = []string{id}
+	}
+	dropped := 0
+	for _, id := range ids {
+		dropped += s.cache.Invalidate(id)
+	}
+	respondJSON(w, http.StatusOK, map[string]int{"dropped": dropped})
+}
+
+func (s *queryServer) markChecked(id string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.lastChecked[id]; !ok {
+		s.lastChecked[id] = at
+	}
+}
+
+// pollChanges asks each cached data source for pages edited since it was last checked and
+// drops the queries cached before those edits. The overlap means an edit is seen by more
+// than one poll, so queries cached after the minute it was made are kept.
+func (s *queryServer) pollChanges(ctx context.Context, now time.Time) {
+	for _, id := range s.cache.DataSources() {
+		s.mu.Lock()
+		since, ok := s.lastChecked[id]
+		s.mu.Unlock()
+		if !ok {
+			since = now
+		}
+		pages, err := fetchChanges(ctx, s.client, id, since.Add(-lastEditedGranularity), now, false)
+		if err != nil {
+			if ctx.Err() == nil {
+				safeLog(s.log, "poll %s: %v", id, err)
+			}
+			continue
+		}
+		s.mu.Lock()
+		s.lastChecked[id] = now
+		s.mu.Unlock()
+		if len(pages) == 0 {
+			continue
+		}
+		var latest time.Time
+		for _, page := range pages {
+			if page.LastEditedTime.After(latest) {
+				latest = page.LastEditedTime
+			}
+		}
+		// last_edited_time is rounded down, so the edit may be up to a minute later.
+		if n := s.cache.InvalidateBefore(id, latest.Add(lastEditedGranularity)); n > 0 {
+			safeLog(s.log, "%s: %d pages edited, dropped %d cached queries", id, len(pages), n)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/querycache"
+)
+
+// editPollingClient counts regular queries and answers change polls with edited.
+type editPollingClient struct {
+	edited  []notion.Page
+	queries int
+}
+
+func (c *editPollingClient) QueryDataSource(
+	_ context.Context,
+	_ string,
+	req notion.QueryDataSourceRequest,
+) (notion.QueryDataSourceResponse, error) {
+	if filter, ok := req.Filter.(map[string]any); ok && filter["timestamp"] == "last_edited_time" {
+		return notion.QueryDataSourceResponse{Results: c.edited}, nil
+	}
+	c.queries++
+	return notion.QueryDataSourceResponse{Results: []notion.Page{{ID: "p1"}}}, nil
+}
+
+func postQuery(t *testing.T, handler http.Handler, path, body string, wantStatus int) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != wantStatus {
+		t.Fatalf("POST %s status = %d, want %d: %s", path, rec.Code, wantStatus, rec.Body.String())
+	}
+	return rec
+}
+
+func TestQueryServerCachesUntilChanged(t *testing.T) {
+	const id = "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d"
+	client := &editPollingClient{}
+	now := time.Date(2026, 3, 1, 12, 0, 10, 0, time.UTC)
+	cache := querycache.New(time.Hour)
+	cache.SetClock(func() time.Time { return now })
+	srv, err := newQueryServer(client, cache, []string{id}, io.Discard)
+	if err != nil {
+		t.Fatalf("newQueryServer returned error: %v", err)
+	}
+	handler := srv.handler()
+	path := "/data_sources/" + id + "/query"
+
+	if rec := postQuery(t, handler, path, `{"page_size":5}`, http.StatusOK); rec.Header().Get("X-Cache") != "MISS" {
+		t.Fatalf("first query X-Cache = %q, want MISS", rec.Header().Get("X-Cache"))
+	}
+	if rec := postQuery(t, handler, path, `{"page_size":5}`, http.StatusOK); rec.Header().Get("X-Cache") != "HIT" {
+		t.Fatalf("repeated query X-Cache = %q, want HIT", rec.Header().Get("X-Cache"))
+	}
+
+	srv.pollChanges(context.Background(), now.Add(10*time.Second))
+	postQuery(t, handler, path, `{"page_size":5}`, http.StatusOK)
+	if client.queries != 1 {
+		t.Fatalf("expected a quiet poll to keep the cache, got %d queries", client.queries)
+	}
+
+	// Notion reports the edit at 12:00, so it may have been made up to 12:00:59.
+	client.edited = []notion.Page{{ID: "p1", LastEditedTime: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}}
+	srv.pollChanges(context.Background(), now.Add(20*time.Second))
+	if rec := postQuery(t, handler, path, `{"page_size":5}`, http.StatusOK); rec.Header().Get("X-Cache") != "MISS" {
+		t.Fatalf("query after an edit X-Cache = %q, want MISS", rec.Header().Get("X-Cache"))
+	}
+	if client.queries != 2 {
+		t.Fatalf("expected 2 queries to reach Notion, got %d", client.queries)
+	}
+
+	// The overlapping poll sees the same edit again; a query cached after its minute stays.
+	now = now.Add(time.Minute)
+	postQuery(t, handler, path, `{"page_size":6}`, http.StatusOK)
+	srv.pollChanges(context.Background(), now.Add(20*time.Second))
+	if rec := postQuery(t, handler, path, `{"page_size":6}`, http.StatusOK); rec.Header().Get("X-Cache") != "HIT" {
+		t.Fatalf("query cached after the edit X-Cache = %q, want HIT", rec.Header().Get("X-Cache"))
+	}
+	if client.queries != 3 {
+		t.Fatalf("expected 3 queries to reach Notion, got %d", client.queries)
+	}
+
+	postQuery(t, handler, "/invalidate", "", http.StatusOK)
+	postQuery(t, handler, path, `{"page_size":5}`, http.StatusOK)
+	if client.queries != 4 {
+		t.Fatalf("expected /invalidate to drop the cache, got %d queries", client.queries)
+	}
+
+	postQuery(t, handler, "/data_sources/ffffffffffffffffffffffffffffffff/query", `{}`, http.StatusForbidden)
+	postQuery(t, handler, path, `{"page_size":`, http.StatusBadRequest)
+}
@@ -0,0 +1,345 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/filelock"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+const (
+	defaultServeListen   = "127.0.0.1:8090"
+	defaultServeInterval = time.Hour
+	defaultServeKeep     = 24
+	snapshotFilePrefix   = "snapshot-"
+	snapshotFileLayout   = "20060102T150405.000000000Z"
+	snapshotDirPerms     = 0o700
+)
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type serveSnapshotsOptions struct {
+	dataSourceID string
+	listenAddr   string
+	dir          string
+	interval     time.Duration
+	keep         int
+}
+
+// snapshotStore holds the rolling snapshots served over HTTP, oldest first.
+type snapshotStore struct {
+	mu        sync.RWMutex
+	snapshots []dataSourceSnapshot
+	lastErr   error
+	keep      int
+	dir       string
+}
+
+func newServeCmd(globals *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Long-running HTTP servers backed by Notion data",
+	}
+
+	cmd.AddCommand(newServeSnapshotsCmd(globals))
+	cmd.AddCommand(newServeQueryCmd(globals))
+	cmd.AddCommand(newServeAPICmd(globals))
+
+	return cmd
+}
+
+func newServeSnapshotsCmd(globals *globalOptions) *cobra.Command {
+	opts := &serveSnapshotsOptions{
+		listenAddr: defaultServeListen,
+		interval:   defaultServeInterval,
+		keep:       defaultServeKeep,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "snapshots",
+		Short: "Snapshot a data source on an interval and serve the latest export and diffs over HTTP",
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Target Notion data source ID or URL")
+	cmd.Flags().StringVar(&opts.listenAddr, "listen", opts.listenAddr, "Address to serve HTTP on (host:port)")
+	cmd.Flags().DurationVar(&opts.interval, "interval", opts.interval, "Time between snapshots")
+	cmd.Flags().IntVar(&opts.keep, "keep", opts.keep, "Number of snapshots to retain for diffs")
+	cmd.Flags().StringVar(
+		&opts.dir,
+		"dir",
+		"",
+		"Directory to persist snapshots in, so history survives restarts (default: memory only)",
+	)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
+
+	return cmd
+}
+
+func (opts *serveSnapshotsOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		if opts.interval <= 0 {
+			return errors.New("--interval must be greater than zero")
+		}
+		if opts.keep < 2 {
+			return errors.New("--keep must be at least 2 so diffs have something to compare")
+		}
+		client, err := buildClient(globals)
+		if err != nil {
+			return err
+		}
+
+		store := &snapshotStore{keep: opts.keep, dir: opts.dir}
+		if err := store.load(opts.dataSourceID); err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		server := &http.Server{Addr: opts.listenAddr, Handler: store.handler(), ReadHeaderTimeout: serverReadTimeout}
+		errCh := make(chan error, 1)
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- fmt.Errorf("snapshot server: %w", err)
+			}
+		}()
+		defer func() {
+			shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), serverShutdownTimeout)
+			defer cancelShutdown()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+		safeLog(cmd.ErrOrStderr(), "Serving snapshots of %s on http://%s", opts.dataSourceID, opts.listenAddr)
+
+		ticker := time.NewTicker(opts.interval)
+		defer ticker.Stop()
+		for {
+			store.refresh(ctx, client, opts.dataSourceID, cmd.ErrOrStderr())
+			select {
+			case <-ctx.Done():
+				return nil
+			case err := <-errCh:
+				return err
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// refresh takes a snapshot; a failure is logged and reported by /healthz while the previous
+// snapshots keep being served.
+func (s *snapshotStore) refresh(ctx context.Context, client snapshotClient, dataSourceID string, log io.Writer) {
+	snap, err := takeSnapshot(ctx, client, dataSourceID, time.Now().UTC())
+	if err == nil {
+		err = s.persist(snap)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastErr = err
+	if err != nil {
+		if ctx.Err() == nil {
+			safeLog(log, "snapshot failed: %v", err)
+		}
+		return
+	}
+	s.snapshots = append(s.snapshots, snap)
+	if extra := len(s.snapshots) - s.keep; extra > 0 {
+		for _, old := range s.snapshots[:extra] {
+			s.remove(old)
+		}
+		s.snapshots = slices.Delete(s.snapshots, 0, extra)
+	}
+	safeLog(log, "snapshot at %s: %d pages", snap.TakenAt.Format(time.RFC3339), len(snap.Pages))
+}
+
+func (s *snapshotStore) snapshotPath(snap dataSourceSnapshot) string {
+	return filepath.Join(s.dir, snapshotFilePrefix+snap.TakenAt.UTC().Format(snapshotFileLayout)+".json")
+}
+
+func (s *snapshotStore) persist(snap dataSourceSnapshot) error {
+	if s.dir == "" {
+		return nil
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+	if err := filelock.WriteFile(s.snapshotPath(snap), data, snapshotFilePermissions); err != nil {
+		return fmt.Errorf("save snapshot: %w", err)
+	}
+	return nil
+}
+
+func (s *snapshotStore) remove(snap dataSourceSnapshot) {
+	if s.dir != "" {
+		_ = os.Remove(s.snapshotPath(snap))
+	}
+}
+
+// load reads the snapshots of dataSourceID kept in --dir by a previous run.
+func (s *snapshotStore) load(dataSourceID string) error {
+	if s.dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(s.dir, snapshotDirPerms); err != nil {
+		return fmt.Errorf("create snapshot directory: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(s.dir, snapshotFilePrefix+"*.json"))
+	if err != nil {
+		return fmt.Errorf("list snapshots: %w", err)
+	}
+	slices.Sort(paths)
+	for _, path := range paths {
+		snap, err := loadSnapshot(path)
+		if err != nil || snap.DataSourceID != dataSourceID {
+			continue
+		}
+		s.snapshots = append(s.snapshots, snap)
+	}
+	if extra := len(s.snapshots) - s.keep; extra > 0 {
+		s.snapshots = s.snapshots[extra:]
+	}
+	return nil
+}
+
+func (s *snapshotStore) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.serveHealth)
+	mux.HandleFunc("GET /snapshots", s.serveList)
+	mux.HandleFunc("GET /latest", s.serveLatest)
+	mux.HandleFunc("GET /diff", s.serveDiff)
+	return mux
+}
+
+func (s *snapshotStore) serveHealth(w http.ResponseWriter, _ *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	status := map[string]any{"ok": len(s.snapshots) > 0, "snapshots": len(s.snapshots)}
+	if n := len(s.snapshots); n > 0 {
+		status["latest"] = s.snapshots[n-1].TakenAt
+	}
+	if s.lastErr != nil {
+		status["last_error"] = s.lastErr.Error()
+	}
+	code := http.StatusOK
+	if len(s.snapshots) == 0 {
+		code = http.StatusServiceUnavailable
+	}
+	respondJSON(w, code, status)
+}
+
+func (s *snapshotStore) serveList(w http.ResponseWriter, _ *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	type entry struct {
+		TakenAt time.Time `json:"taken_at"`
+		Pages   int       `json:"pages"`
+	}
+	list := make([]entry, 0, len(s.snapshots))
+	for _, snap := range s.snapshots {
+		list = append(list, entry{TakenAt: snap.TakenAt, Pages: len(snap.Pages)})
+	}
+	respondJSON(w, http.StatusOK, list)
+}
+
+// serveLatest writes the newest snapshot as JSON (the snapshot file format), JSON Lines, or
+// CSV, chosen with ?format=.
+func (s *snapshotStore) serveLatest(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.snapshots) == 0 {
+		http.Error(w, "no snapshot yet", http.StatusServiceUnavailable)
+		return
+	}
+	snap := s.snapshots[len(s.snapshots)-1]
+	w.Header().Set("Last-Modified", snap.TakenAt.UTC().Format(http.TimeFormat))
+	switch format := r.URL.Query().Get("format"); format {
+	case "", formatJSON:
+		respondJSON(w, http.StatusOK, snap)
+	case formatJSONL:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_ = writeJSONLines(w, pageSeq(snap.Pages))
+	case formatCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		idx := snapshotIndex(snap)
+		_ = writePagesCSV(w, pageSeq(snap.Pages), idx, idx.PropertyNames())
+	default:
+		http.Error(w, fmt.Sprintf("unknown format %q (expected json, jsonl, or csv)", format), http.StatusBadRequest)
+	}
+}
+
+// serveDiff compares the latest snapshot with the one before it, or with the newest
+// snapshot taken at or before ?since=<RFC3339>.
+func (s *snapshotStore) serveDiff(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.snapshots) < 2 {
+		http.Error(w, "need at least two snapshots to diff", http.StatusServiceUnavailable)
+		return
+	}
+	latest := s.snapshots[len(s.snapshots)-1]
+	base := s.snapshots[len(s.snapshots)-2]
+	if raw := r.URL.Query().Get("since"); raw != "" {
+		since, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			http.Error(w, "since must be an RFC3339 time", http.StatusBadRequest)
+			return
+		}
+		i := snapshotAtOrBefore(s.snapshots, since)
+		if i < 0 {
+			http.Error(w, "no snapshot that old is retained", http.StatusNotFound)
+			return
+		}
+		base = s.snapshots[i]
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"from": base.TakenAt,
+		"to":   latest.TakenAt,
+		"diff": diffSnapshots(base, latest),
+	})
+}
+
+// snapshotAtOrBefore returns the index of the newest snapshot taken at or before t, or -1.
+func snapshotAtOrBefore(snapshots []dataSourceSnapshot, t time.Time) int {
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if !snapshots[i].TakenAt.After(t) {
+			return i
+		}
+	}
+	return -1
+}
+
+// snapshotIndex uses the schema fetched with the snapshot, or rebuilds one from the page
+// properties for snapshots loaded from disk.
+func snapshotIndex(snap dataSourceSnapshot) *schema.Index {
+	ds := snap.dataSource
+	if len(ds.Properties) == 0 {
+		ds = notion.DataSource{ID: snap.DataSourceID, Name: snap.Name, Properties: map[string]notion.PropertyReference{}}
+		for _, page := range snap.Pages {
+			for name, value := range page.Properties {
+				ds.Properties[name] = notion.PropertyReference{ID: value.ID, Name: name, Type: value.Type}
+			}
+		}
+	}
+	return schema.NewIndex(ds)
+}
+
+func respondJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(v)
+}
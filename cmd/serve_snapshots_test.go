@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+// rotatingSnapshotClient returns the next page set on every query.
+type rotatingSnapshotClient struct {
+	pages [][]notion.Page
+	calls int
+}
+
+func (c *rotatingSnapshotClient) GetDataSource(context.Context, string) (notion.DataSource, error) {
+	return notion.DataSource{ID: "ds-1", Name: "Tasks", Properties: map[string]notion.PropertyReference{
+		"Name":   {ID: "title", Name: "Name", Type: "title"},
+		"Status": {ID: "st", Name: "Status", Type: "status"},
+	}}, nil
+}
+
+func (c *rotatingSnapshotClient) QueryDataSource(
+	context.Context,
+	string,
+	notion.QueryDataSourceRequest,
+) (notion.QueryDataSourceResponse, error) {
+	pages := c.pages[min(c.calls, len(c.pages)-1)]
+	c.calls++
+	return notion.QueryDataSourceResponse{Results: pages}, nil
+}
+
+func TestSnapshotStoreServesLatestAndDiff(t *testing.T) {
+	client := &rotatingSnapshotClient{pages: [][]notion.Page{
+		{snapshotPage("p1", "One", "Todo")},
+		{snapshotPage("p1", "One", "Done"), snapshotPage("p2", "Two", "Todo")},
+		{snapshotPage("p2", "Two", "Todo")},
+	}}
+	dir := t.TempDir()
+	store := &snapshotStore{keep: 2, dir: dir}
+	var log bytes.Buffer
+	for range 3 {
+		store.refresh(context.Background(), client, "ds-1", &log)
+	}
+	if len(store.snapshots) != 2 {
+		t.Fatalf("expected 2 retained snapshots, got %d", len(store.snapshots))
+	}
+
+	handler := store.handler()
+	body := serveGet(t, handler, "/latest?format=csv", http.StatusOK)
+	if !strings.HasPrefix(body, "ID,Last Edited,Name,Status\n") || !strings.Contains(body, "p2,") || strings.Contains(body, "p1,") {
+		t.Fatalf("unexpected CSV export:\n%s", body)
+	}
+
+	var resp struct {
+		Diff snapshotDiff `json:"diff"`
+	}
+	if err := json.Unmarshal([]byte(serveGet(t, handler, "/diff", http.StatusOK)), &resp); err != nil {
+		t.Fatalf("decode diff: %v", err)
+	}
+	if len(resp.Diff.Removed) != 1 || resp.Diff.Removed[0].ID != "p1" || len(resp.Diff.Added) != 0 {
+		t.Fatalf("unexpected diff: %#v", resp.Diff)
+	}
+	serveGet(t, handler, "/latest?format=xml", http.StatusBadRequest)
+	serveGet(t, handler, "/healthz", http.StatusOK)
+
+	reloaded := &snapshotStore{keep: 5, dir: dir}
+	if err := reloaded.load("ds-1"); err != nil {
+		t.Fatalf("load returned error: %v", err)
+	}
+	if len(reloaded.snapshots) != 2 {
+		t.Fatalf("expected the 2 persisted snapshots to reload, got %d", len(reloaded.snapshots))
+	}
+	if csv := serveGet(t, reloaded.handler(), "/latest?format=csv", http.StatusOK); !strings.HasPrefix(csv, "ID,Last Edited,Name,Status\n") {
+		t.Fatalf("expected reloaded snapshots to rebuild CSV columns, got:\n%s", csv)
+	}
+}
+
+func TestSnapshotStoreBeforeFirstSnapshot(t *testing.T) {
+	handler := (&snapshotStore{keep: 2}).handler()
+	serveGet(t, handler, "/healthz", http.StatusServiceUnavailable)
+	serveGet(t, handler, "/latest", http.StatusServiceUnavailable)
+	serveGet(t, handler, "/diff", http.StatusServiceUnavailable)
+}
+
+func serveGet(t *testing.T, handler http.Handler, target string, wantStatus int) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if rec.Code != wantStatus {
+		t.Fatalf("GET %s = %d, want %d: %s", target, rec.Code, wantStatus, rec.Body.String())
+	}
+	return rec.Body.String()
+}
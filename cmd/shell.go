@@ -0,0 +1,585 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/filelock"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/notionid"
+	"github.com/yourorg/notionctl/internal/render"
+)
+
+const (
+	shellPrompt           = "notionctl> "
+	shellHistoryFile      = "shell_history"
+	shellHistoryLimit     = 1000
+	shellCompleteTimeout  = 5 * time.Second
+	shellHistoryFilePerms = 0o600
+	shellHistoryDirPerms  = 0o700
+)
+
+// shellPropertyFlags take property names, so the shell completes them from the schema.
+var shellPropertyFlags = map[string]bool{
+	"--sort": true, "--group-by": true, "--sum": true, "--avg": true,
+	"--expand": true, "--filter-properties": true, "--where": true,
+}
+
+// dataSourcePath matches GET /v1/data_sources/{id}: the schema, not its query endpoint.
+var dataSourcePath = regexp.MustCompile(`/data_sources/[^/]+$`)
+
+type shellOptions struct {
+	historyPath string
+	noHistory   bool
+}
+
+// shellSession is the state kept between lines: one client per profile and set of global
+// flags, and the schemas those clients fetched.
+type shellSession struct {
+	globals globalOptions
+	out     io.Writer
+	errOut  io.Writer
+	in      io.Reader
+	factory func(globals *globalOptions) (*notion.Client, error)
+	clients map[globalOptions]*notion.Client
+	schemas *schemaCache
+}
+
+func newShellCmd(globals *globalOptions) *cobra.Command {
+	opts := &shellOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "shell",
+		Aliases: []string{"repl"},
+		Short:   "Interactive session that keeps the client and schemas warm between commands",
+		Long: "Run notionctl commands without the notionctl prefix. The token is read once, data source " +
+			"schemas are fetched once per session, and Tab completes commands, flags, data source aliases, " +
+			"and property names. Built-ins: help, profile [name], reload, exit. Lines can also be piped in.",
+		Args: cobra.NoArgs,
+		RunE: opts.run(globals),
+	}
+
+	cmd.Flags().StringVar(
+		&opts.historyPath,
+		"history-file",
+		"",
+		"File that keeps command history between sessions (default: ~/.config/notionctl/shell_history)",
+	)
+	cmd.Flags().BoolVar(&opts.noHistory, "no-history", false, "Do not read or write the history file")
+
+	return cmd
+}
+
+func (opts *shellOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		session := newShellSession(*globals, clientFactory, cmd.OutOrStdout(), cmd.ErrOrStderr())
+		restore := session.install()
+		defer restore()
+
+		stdin, ok := cmd.InOrStdin().(*os.File)
+		if !ok || !term.IsTerminal(int(stdin.Fd())) {
+			session.in = strings.NewReader("")
+			return session.runScript(cmd.Context(), cmd.InOrStdin())
+		}
+		session.in = stdin
+
+		history := &shellHistory{}
+		if !opts.noHistory {
+			path, err := opts.resolveHistoryPath()
+			if err != nil {
+				return err
+			}
+			history.load(path)
+		}
+		return session.runInteractive(cmd.Context(), stdin, history)
+	}
+}
+
+func (opts *shellOptions) resolveHistoryPath() (string, error) {
+	if opts.historyPath != "" {
+		return opts.historyPath, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "notionctl", shellHistoryFile), nil
+}
+
+func newShellSession(
+	globals globalOptions,
+	factory func(*globalOptions) (*notion.Client, error),
+	out, errOut io.Writer,
+) *shellSession {
+	return &shellSession{
+		globals: globals,
+		out:     out,
+		errOut:  errOut,
+		in:      strings.NewReader(""),
+		factory: factory,
+		clients: map[globalOptions]*notion.Client{},
+		schemas: &schemaCache{entries: map[string][]byte{}},
+	}
+}
+
+// install routes buildClient through the session for as long as it runs.
+func (s *shellSession) install() func() {
+	prev := clientFactory
+	clientFactory = s.client
+	return func() { clientFactory = prev }
+}
+
+// client returns the client for a line's global flags, building one the first time a
+// profile is used with them. Each call gets a clone sharing the session's rate limiter,
+// breaker, and schema cache, so a command that changes its client (its Notion-Version or
+// request rate) leaves the session and later lines alone.
+func (s *shellSession) client(globals *globalOptions) (*notion.Client, error) {
+	key := clientKey(globals)
+	if c, ok := s.clients[key]; ok {
+		return c.Clone(), nil
+	}
+	c, err := s.factory(globals)
+	if err != nil {
+		return nil, err
+	}
+	c.WrapTransport(s.schemas.wrap)
+	s.clients[key] = c
+	return c.Clone(), nil
+}
+
+// clientKey drops the global flags that do not change how a client is built.
+func clientKey(globals *globalOptions) globalOptions {
+	key := *globals
+	key.withMeta, key.stats, key.noPager = false, false, false
+	return key
+}
+
+func (s *shellSession) runScript(ctx context.Context, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if s.handleLine(ctx, scanner.Text()) {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read commands: %w", err)
+	}
+	return nil
+}
+
+func (s *shellSession) runInteractive(ctx context.Context, stdin *os.File, history *shellHistory) error {
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{stdin, s.out}, shellPrompt)
+	terminal.History = history
+	terminal.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		return s.complete(ctx, line, pos)
+	}
+	safeLog(s.errOut, "notionctl shell (profile %s). Tab completes; type help for built-ins, exit or Ctrl-D to leave.",
+		s.globals.profile)
+
+	fd := int(stdin.Fd())
+	for {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("enter raw mode: %w", err)
+		}
+		line, readErr := terminal.ReadLine()
+		if err := term.Restore(fd, state); err != nil {
+			return fmt.Errorf("restore terminal: %w", err)
+		}
+		if errors.Is(readErr, io.EOF) {
+			return nil
+		}
+		if readErr != nil {
+			return fmt.Errorf("read command: %w", readErr)
+		}
+		if s.handleLine(ctx, line) {
+			return nil
+		}
+	}
+}
+
+// handleLine runs one line and reports whether the session should end.
+func (s *shellSession) handleLine(ctx context.Context, line string) bool {
+	args, err := splitShellWords(line)
+	if err != nil {
+		safeLog(s.errOut, "Error: %v", err)
+		return false
+	}
+	if len(args) == 0 || strings.HasPrefix(args[0], "#") {
+		return false
+	}
+	switch args[0] {
+	case "exit", "quit":
+		return true
+	case "help":
+		if len(args) == 1 {
+			safeLog(s.out, "Built-ins: help [command], profile [name], reload, exit. Anything else runs as a notionctl command.")
+			args = []string{"--help"}
+		} else {
+			args = append(args[1:], "--help")
+		}
+	case "profile":
+		if len(args) > 1 {
+			s.globals.profile = args[1]
+		}
+		safeLog(s.out, "profile: %s", s.globals.profile)
+		return false
+	case "reload":
+		s.clients = map[globalOptions]*notion.Client{}
+		s.schemas.reset()
+		safeLog(s.out, "dropped cached clients and schemas")
+		return false
+	case "shell", "repl":
+		safeLog(s.errOut, "Error: already in a shell")
+		return false
+	}
+	s.exec(ctx, args)
+	return false
+}
+
+// exec runs args against a fresh command tree; Ctrl-C cancels the command, not the shell.
+func (s *shellSession) exec(ctx context.Context, args []string) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	lineGlobals := s.globals
+	root := newRootCmd(&lineGlobals)
+	root.SetArgs(args)
+	root.SetIn(s.in)
+	root.SetOut(s.out)
+	root.SetErr(s.errOut)
+	started := time.Now()
+	cmd, err := root.ExecuteContextC(ctx)
+	closePager(cmd)
+	recordUsage(cmd, started, err)
+	reportRunStats(cmd, s.errOut, started)
+	if err != nil {
+		safeLog(s.errOut, "Error: %v", err)
+	}
+}
+
+// complete extends the word before pos with the longest prefix shared by its candidates.
+func (s *shellSession) complete(ctx context.Context, line string, pos int) (string, int, bool) {
+	start := strings.LastIndexAny(line[:pos], " \t") + 1
+	if start < pos && (line[start] == '"' || line[start] == '\'') {
+		start++
+	}
+	prefix := line[start:pos]
+	before, _ := splitShellWords(line[:start])
+
+	var candidates []string
+	switch {
+	case strings.HasPrefix(prefix, "-"):
+		candidates = flagNames(s.resolve(before))
+	case len(before) > 0 && before[len(before)-1] == "--data-source-id":
+		return s.completeDataSource(line, pos, start, prefix)
+	case len(before) > 0 && shellPropertyFlags[before[len(before)-1]]:
+		candidates = s.propertyNames(ctx, before)
+		if i := strings.LastIndex(prefix, ","); i >= 0 {
+			for j, c := range candidates {
+				candidates[j] = prefix[:i+1] + c
+			}
+		}
+	default:
+		cmd := s.resolve(before)
+		for _, sub := range cmd.Commands() {
+			if sub.IsAvailableCommand() {
+				candidates = append(candidates, sub.Name())
+			}
+		}
+	}
+
+	word, ok := completeWord(candidates, prefix)
+	if !ok {
+		return "", 0, false
+	}
+	return replaceWord(line, start, pos, word)
+}
+
+// replaceWord puts word in place of line[start:pos], opening a quote if it needs one.
+func replaceWord(line string, start, pos int, word string) (string, int, bool) {
+	quoted := start > 0 && (line[start-1] == '"' || line[start-1] == '\'')
+	if !quoted && strings.ContainsAny(word, " \t") {
+		word = `"` + word
+	}
+	return line[:start] + word + line[pos:], start + len(word), true
+}
+
+// completeDataSource expands an alias to its data source ID, since --data-source-id takes IDs.
+func (s *shellSession) completeDataSource(line string, pos, start int, prefix string) (string, int, bool) {
+	settings, err := config.LoadDataSourceSettings(s.globals.profile)
+	if err != nil {
+		return "", 0, false
+	}
+	word, ok := completeWord(render.SortedKeys(settings.Aliases), strings.ToLower(prefix))
+	if !ok {
+		return "", 0, false
+	}
+	if id, exact := settings.Aliases[word]; exact {
+		word = id
+	}
+	return replaceWord(line, start, pos, word)
+}
+
+// resolve finds the command named by the leading words of a line.
+func (s *shellSession) resolve(words []string) *cobra.Command {
+	lineGlobals := s.globals
+	cmd := newRootCmd(&lineGlobals)
+	for _, word := range words {
+		if strings.HasPrefix(word, "-") {
+			break
+		}
+		next, _, err := cmd.Find([]string{word})
+		if err != nil || next == cmd {
+			break
+		}
+		cmd = next
+	}
+	return cmd
+}
+
+// propertyNames lists the properties of the line's data source, or the profile's default.
+func (s *shellSession) propertyNames(ctx context.Context, words []string) []string {
+	var ref string
+	for i, word := range words {
+		if word == "--data-source-id" && i+1 < len(words) {
+			ref = words[i+1]
+		}
+	}
+	settings, err := config.LoadDataSourceSettings(s.globals.profile)
+	if err != nil {
+		return nil
+	}
+	raw, _ := settings.ResolveDataSource(ref)
+	id, err := notionid.Parse(raw)
+	if err != nil {
+		return nil
+	}
+	client, err := s.client(&s.globals)
+	if err != nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, shellCompleteTimeout)
+	defer cancel()
+	ds, err := client.GetDataSource(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return render.SortedKeys(ds.Properties)
+}
+
+func flagNames(cmd *cobra.Command) []string {
+	var names []string
+	add := func(f *pflag.Flag) {
+		if !f.Hidden {
+			names = append(names, "--"+f.Name)
+		}
+	}
+	cmd.Flags().VisitAll(add)
+	cmd.InheritedFlags().VisitAll(add)
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// completeWord returns the longest common prefix of the candidates starting with prefix.
+func completeWord(candidates []string, prefix string) (string, bool) {
+	var common string
+	matched := false
+	for _, c := range candidates {
+		if !strings.HasPrefix(c, prefix) {
+			continue
+		}
+		if !matched {
+			common, matched = c, true
+			continue
+		}
+		for !strings.HasPrefix(c, common) {
+			common = common[:len(common)-1]
+		}
+	}
+	if !matched || common == prefix {
+		return "", false
+	}
+	return common, true
+}
+
+// splitShellWords splits a line on whitespace, honoring single and double quotes and
+// backslash escapes outside single quotes.
+func splitShellWords(line string) ([]string, error) {
+	var (
+		words   []string
+		current strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
+
+// schemaCache is an http.RoundTripper layer that remembers GET data_sources/{id}
+// responses and forgets them when the schema is updated.
+type schemaCache struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+}
+
+func (c *schemaCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string][]byte{}
+}
+
+func (c *schemaCache) wrap(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if !dataSourcePath.MatchString(req.URL.Path) {
+			return next.RoundTrip(req)
+		}
+		key := req.Header.Get("Notion-Version") + " " + req.URL.Path
+		if req.Method != http.MethodGet {
+			c.mu.Lock()
+			delete(c.entries, key)
+			c.mu.Unlock()
+			return next.RoundTrip(req)
+		}
+
+		c.mu.Lock()
+		body, ok := c.entries[key]
+		c.mu.Unlock()
+		if ok {
+			return &http.Response{
+				Status:        "200 OK",
+				StatusCode:    http.StatusOK,
+				Proto:         req.Proto,
+				ProtoMajor:    req.ProtoMajor,
+				ProtoMinor:    req.ProtoMinor,
+				Header:        http.Header{"Content-Type": {"application/json"}},
+				Body:          io.NopCloser(bytes.NewReader(body)),
+				ContentLength: int64(len(body)),
+				Request:       req,
+			}, nil
+		}
+
+		resp, err := next.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusOK {
+			return resp, err //nolint:wrapcheck // transparent transport layer
+		}
+		body, err = io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read data source response: %w", err)
+		}
+		c.mu.Lock()
+		c.entries[key] = body
+		c.mu.Unlock()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return resp, nil
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// shellHistory is the terminal's history, appended to a file as lines are entered. Lines
+// with a --token flag stay in memory only.
+type shellHistory struct {
+	path    string
+	entries []string // oldest first
+}
+
+func (h *shellHistory) load(path string) {
+	h.path = path
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	for line := range strings.SplitSeq(strings.TrimRight(string(data), "\n"), "\n") {
+		if line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	if extra := len(h.entries) - shellHistoryLimit; extra > 0 {
+		h.entries = h.entries[extra:]
+	}
+}
+
+func (h *shellHistory) Add(entry string) {
+	if strings.TrimSpace(entry) == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry) {
+		return
+	}
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > shellHistoryLimit {
+		h.entries = h.entries[1:]
+	}
+	if h.path != "" && !strings.Contains(entry, "--token") {
+		if err := os.MkdirAll(filepath.Dir(h.path), shellHistoryDirPerms); err == nil {
+			_ = filelock.Append(h.path, []byte(entry+"\n"), shellHistoryFilePerms)
+		}
+	}
+}
+
+func (h *shellHistory) Len() int {
+	return len(h.entries)
+}
+
+func (h *shellHistory) At(idx int) string {
+	return h.entries[len(h.entries)-1-idx]
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestSplitShellWords(t *testing.T) {
+	got, err := splitShellWords(`ds query --where 'Status = "Done"' --sort "Due Date:asc" a\ b`)
+	if err != nil {
+		t.Fatalf("splitShellWords returned error: %v", err)
+	}
+	want := []string{"ds", "query", "--where", `Status = "Done"`, "--sort", "Due Date:asc", "a b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("splitShellWords = %q, want %q", got, want)
+	}
+	if _, err := splitShellWords(`ds query --where "open`); err == nil {
+		t.Fatalf("expected an error for an unterminated quote")
+	}
+}
+
+// newShellTestSession serves one data source schema and counts schema fetches and clients.
+func newShellTestSession(t *testing.T) (*shellSession, *bytes.Buffer, *int32, *int32) {
+	t.Helper()
+	var schemaGets, clients int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&schemaGets, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object":"data_source","id":"1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d","properties":{
+			"Name":{"id":"title","name":"Name","type":"title"},
+			"Due Date":{"id":"d","name":"Due Date","type":"date"},
+			"Done":{"id":"x","name":"Done","type":"checkbox"}}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	factory := func(*globalOptions) (*notion.Client, error) {
+		atomic.AddInt32(&clients, 1)
+		return notion.NewClient(notion.ClientConfig{Token: "test", BaseURL: srv.URL}), nil
+	}
+	var out bytes.Buffer
+	session := newShellSession(globalOptions{profile: "default"}, factory, &out, &out)
+	t.Cleanup(session.install())
+	return session, &out, &schemaGets, &clients
+}
+
+func TestShellReusesClientAndSchema(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	session, out, schemaGets, clients := newShellTestSession(t)
+
+	script := strings.Join([]string{
+		"# comments and blank lines are skipped",
+		"",
+		"ds schema --data-source-id 1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d --format json",
+		"ds schema --data-source-id 1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d --format json",
+		"shell",
+		"repl",
+		"exit",
+		"ds schema --data-source-id 1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d",
+	}, "\n")
+	if err := session.runScript(context.Background(), strings.NewReader(script)); err != nil {
+		t.Fatalf("runScript returned error: %v", err)
+	}
+	if n := strings.Count(out.String(), `"Due Date"`); n != 2 {
+		t.Fatalf("expected two schema outputs before exit, got %d:\n%s", n, out.String())
+	}
+	if n := strings.Count(out.String(), "already in a shell"); n != 2 {
+		t.Fatalf("expected nested shell to be refused:\n%s", out.String())
+	}
+	if *clients != 1 || *schemaGets != 1 {
+		t.Fatalf("expected 1 client and 1 schema fetch, got %d and %d", *clients, *schemaGets)
+	}
+}
+
+func TestShellUpgradeCheckKeepsSessionVersion(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	session, out, _, _ := newShellTestSession(t)
+
+	const target = "2099-01-01"
+	session.handleLine(context.Background(),
+		"upgrade-check --data-source-id 1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d --target "+target)
+	if strings.Contains(out.String(), "already uses") {
+		t.Fatalf("expected upgrade-check to compare versions, got:\n%s", out.String())
+	}
+	if len(session.clients) != 1 {
+		t.Fatalf("expected one session client, got %d", len(session.clients))
+	}
+	for _, c := range session.clients {
+		if c.NotionVersion() == target {
+			t.Fatalf("upgrade-check left the session client on Notion-Version %s", target)
+		}
+	}
+}
+
+func TestShellKeepsPerCommandRateToItsLine(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	session, _, _, _ := newShellTestSession(t)
+
+	session.handleLine(context.Background(),
+		"ds export --data-source-id 1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d --requests-per-second 50 --out "+
+			filepath.Join(t.TempDir(), "rows.jsonl"))
+	c, err := session.client(&session.globals)
+	if err != nil {
+		t.Fatalf("client returned error: %v", err)
+	}
+	if got := c.RequestsPerSecond(); got != notion.DefaultRequestsPerSecond {
+		t.Fatalf("expected later lines to keep %v requests per second, got %v", notion.DefaultRequestsPerSecond, got)
+	}
+}
+
+func TestShellAppliesGlobalFlagsPerLine(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	keyring.MockInit()
+	savedOutput := debugOutput
+	t.Cleanup(func() { debugOutput = savedOutput })
+
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	t.Setenv(baseURLEnv, srv.URL)
+	if err := config.SaveToken("default", "secret_default", ""); err != nil {
+		t.Fatalf("SaveToken returned error: %v", err)
+	}
+
+	var log, out bytes.Buffer
+	debugOutput = &log
+	session := newShellSession(globalOptions{profile: "default"}, defaultClientFactory, &out, &out)
+	t.Cleanup(session.install())
+
+	run := func(line string) int32 {
+		t.Helper()
+		attempts.Store(0)
+		session.handleLine(context.Background(), line)
+		return attempts.Load()
+	}
+	const schema = " ds schema --data-source-id 1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d"
+	if got := run("--max-retries 0 --debug" + schema); got != 1 {
+		t.Fatalf("expected --max-retries 0 to send one request, got %d", got)
+	}
+	logged := strings.Count(log.String(), "-> 503")
+	if logged != 1 {
+		t.Fatalf("expected --debug to log the request, got:\n%s", log.String())
+	}
+	if got := run("--max-retries 2 --backoff-base 1ms" + schema); got != 3 {
+		t.Fatalf("expected --max-retries 2 to send three requests, got %d", got)
+	}
+	if strings.Count(log.String(), "-> 503") != logged {
+		t.Fatalf("expected a line without --debug not to log, got:\n%s", log.String())
+	}
+	if got := run("--max-retries 0" + schema); got != 1 {
+		t.Fatalf("expected a later line to use its own --max-retries, got %d requests", got)
+	}
+}
+
+func TestShellCompletion(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	session, _, _, _ := newShellTestSession(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"ds que", "ds query"},
+		{"ds query --data-so", "ds query --data-source-id"},
+		{"ds query --data-source-id 1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d --sort Du", `ds query --data-source-id 1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d --sort "Due Date`},
+		{"ds query --data-source-id 1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d --sum Name,Do", "ds query --data-source-id 1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d --sum Name,Done"},
+	}
+	for _, tt := range tests {
+		got, pos, ok := session.complete(ctx, tt.line, len(tt.line))
+		if !ok || got != tt.want || pos != len(tt.want) {
+			t.Errorf("complete(%q) = %q, %d, %v; want %q", tt.line, got, pos, ok, tt.want)
+		}
+	}
+	if _, _, ok := session.complete(ctx, "zzz", 3); ok {
+		t.Errorf("expected no completion for an unknown command")
+	}
+}
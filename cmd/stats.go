@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/render"
+	"github.com/yourorg/notionctl/internal/usage"
+)
+
+// statsTopFlags caps how many flags the table lists per command.
+const statsTopFlags = 3
+
+type statsCLIOptions struct {
+	format  string
+	since   time.Duration
+	enable  bool
+	disable bool
+	clear   bool
+}
+
+func newStatsCmd(globals *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show local statistics about notionctl",
+	}
+
+	cmd.AddCommand(newStatsCLICmd(globals))
+
+	return cmd
+}
+
+func newStatsCLICmd(_ *globalOptions) *cobra.Command {
+	opts := &statsCLIOptions{format: formatTable}
+
+	cmd := &cobra.Command{
+		Use:   "cli",
+		Short: "Summarize the opt-in local usage log: commands, flags, and durations",
+		RunE:  opts.run,
+	}
+
+	formatFlag(cmd, &opts.format, "Output format", formatJSON, formatTable)
+	cmd.Flags().DurationVar(&opts.since, "since", 0, "Only include runs from this long ago onwards (e.g. 168h)")
+	cmd.Flags().BoolVar(&opts.enable, "enable", false, "Start recording command usage to the local log")
+	cmd.Flags().BoolVar(&opts.disable, "disable", false, "Stop recording command usage")
+	cmd.Flags().BoolVar(&opts.clear, "clear", false, "Delete the recorded usage log")
+	cmd.MarkFlagsMutuallyExclusive("enable", "disable")
+
+	return cmd
+}
+
+func (opts *statsCLIOptions) run(cmd *cobra.Command, _ []string) error {
+	path, err := config.UsageLogPath()
+	if err != nil {
+		return err
+	}
+	if opts.enable || opts.disable || opts.clear {
+		return opts.configure(cmd.ErrOrStderr(), path)
+	}
+
+	var since time.Time
+	if opts.since > 0 {
+		since = time.Now().Add(-opts.since)
+	}
+	entries, err := usage.Load(path, since)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		if enabled, err := config.UsageLogEnabled(); err == nil && !enabled {
+			safeLog(cmd.ErrOrStderr(), "usage logging is off; turn it on with notionctl stats cli --enable")
+		}
+	}
+	summaries := usage.Summarize(entries)
+
+	switch opts.format {
+	case formatJSON:
+		return writeJSON(cmd.Context(), cmd.OutOrStdout(), summaries)
+	case formatTable:
+		headers := []string{"Command", "Runs", "Failed", "Median", "P90", "Max", "Top flags"}
+		return render.Table(cmd.OutOrStdout(), headers, statsRows(summaries))
+	default:
+		return fmt.Errorf("unknown format %q (expected json or table)", opts.format)
+	}
+}
+
+func (opts *statsCLIOptions) configure(log io.Writer, path string) error {
+	if opts.clear {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("clear usage log: %w", err)
+		}
+		safeLog(log, "cleared %s", path)
+	}
+	if opts.enable || opts.disable {
+		if err := config.SetUsageLog(opts.enable); err != nil {
+			return err
+		}
+		if opts.enable {
+			safeLog(log, "recording command usage to %s (stored locally only)", path)
+		} else {
+			safeLog(log, "stopped recording command usage")
+		}
+	}
+	return nil
+}
+
+func statsRows(summaries []usage.Summary) [][]string {
+	rows := make([][]string, 0, len(summaries))
+	for _, s := range summaries {
+		rows = append(rows, []string{
+			s.Command,
+			strconv.Itoa(s.Runs),
+			strconv.Itoa(s.Failures),
+			formatMillis(s.MedianMS),
+			formatMillis(s.P90MS),
+			formatMillis(s.MaxMS),
+			topFlags(s.Flags),
+		})
+	}
+	return rows
+}
+
+func formatMillis(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).Round(time.Millisecond).String()
+}
+
+// topFlags lists the most used flags with their counts, most used first.
+func topFlags(counts map[string]int) string {
+	names := render.SortedKeys(counts)
+	slices.SortStableFunc(names, func(a, b string) int { return counts[b] - counts[a] })
+	if len(names) > statsTopFlags {
+		names = names[:statsTopFlags]
+	}
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("--%s (%d)", name, counts[name]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// recordUsage appends the finished command to the usage log when the user has opted in.
+// Failures are ignored: usage logging must never break a command.
+func recordUsage(cmd *cobra.Command, started time.Time, runErr error) {
+	if cmd == nil || !cmd.HasParent() {
+		return
+	}
+	if enabled, err := config.UsageLogEnabled(); err != nil || !enabled {
+		return
+	}
+	path, err := config.UsageLogPath()
+	if err != nil {
+		return
+	}
+	entry := usage.Entry{
+		Time:       started.UTC(),
+		Command:    strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "),
+		DurationMS: time.Since(started).Milliseconds(),
+		Failed:     runErr != nil,
+	}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		entry.Flags = append(entry.Flags, f.Name)
+	})
+	_ = usage.Append(path, entry)
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/usage"
+)
+
+func TestStatsRows(t *testing.T) {
+	rows := statsRows([]usage.Summary{{
+		Command:  "ds query",
+		Runs:     4,
+		Failures: 1,
+		MedianMS: 1500,
+		P90MS:    2250,
+		MaxMS:    61000,
+		Flags:    map[string]int{"where": 3, "format": 3, "all": 1, "limit": 2},
+	}})
+	want := []string{"ds query", "4", "1", "1.5s", "2.25s", "1m1s", "--format (3), --where (3), --limit (2)"}
+	if len(rows) != 1 {
+		t.Fatalf("expected one row, got %d", len(rows))
+	}
+	for i := range want {
+		if rows[0][i] != want[i] {
+			t.Fatalf("column %d = %q, want %q", i, rows[0][i], want[i])
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/yourorg/notionctl/internal/render"
+)
+
+// stdinPath is the conventional file argument meaning "read from standard input".
+const stdinPath = "-"
+
+// readFileOrStdin reads path, or all of stdin when path is "-".
+func readFileOrStdin(path string, stdin io.Reader) ([]byte, error) {
+	if path != stdinPath {
+		data, err := os.ReadFile(path) // #nosec G304 -- reading user-supplied payload files is intentional
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+		return data, nil
+	}
+	if stdin == nil {
+		return nil, errors.New("read stdin: no input stream available")
+	}
+	data, err := io.ReadAll(stdin)
+	if err != nil {
+		return nil, fmt.Errorf("read stdin: %w", err)
+	}
+	return data, nil
+}
+
+// checkOneStdin rejects more than one of paths, keyed by flag name, reading stdin: the first
+// reader would consume all of it and the next would fail on an empty or partial document.
+func checkOneStdin(paths map[string]string) error {
+	var readers []string
+	for _, flag := range render.SortedKeys(paths) {
+		if strings.TrimSpace(paths[flag]) == stdinPath {
+			readers = append(readers, "--"+flag)
+		}
+	}
+	if len(readers) > 1 {
+		return fmt.Errorf("%s each read stdin (-), but only one flag can; pass the rest as files",
+			strings.Join(readers, " and "))
+	}
+	return nil
+}
@@ -9,6 +9,10 @@ func newSyncCmd(globals *globalOptions) *cobra.Command {
 	}
 
 	cmd.AddCommand(newSyncWatchCmd(globals))
+	cmd.AddCommand(newSyncMirrorCmd(globals))
+	cmd.AddCommand(newSyncExportMDCmd(globals))
+	cmd.AddCommand(newSyncDeliveriesCmd(globals))
+	cmd.AddCommand(newSyncGitHubCmd(globals))
 
 	return cmd
 }
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/filelock"
+	"github.com/yourorg/notionctl/internal/render"
+)
+
+// deliveryStore appends verified webhook deliveries to a JSON Lines file before they are
+// acknowledged, so a consumer that crashed can replay what it missed. Each line is the same
+// {"kind":"webhook",...} object sync watch prints.
+type deliveryStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func (s *deliveryStore) append(delivery webhookDelivery) error {
+	if s == nil {
+		return nil
+	}
+	line, err := json.Marshal(deliveryOutput(delivery))
+	if err != nil {
+		return fmt.Errorf("encode delivery: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := filelock.Append(s.path, append(line, '\n'), 0o600); err != nil {
+		return fmt.Errorf("append delivery store: %w", err)
+	}
+	return nil
+}
+
+func deliveryOutput(delivery webhookDelivery) watchOutput {
+	return watchOutput{
+		Kind:       "webhook",
+		EventType:  delivery.eventType,
+		DeliveryID: delivery.deliveryID,
+		ReceivedAt: delivery.receivedAt,
+		Raw:        delivery.payload,
+	}
+}
+
+// loadDeliveries reads a delivery store, skipping lines that do not decode (such as a line
+// cut short by a crash mid-write).
+func loadDeliveries(path string) ([]watchOutput, error) {
+	f, err := os.Open(path) // #nosec G304 -- user-chosen store path
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("delivery store %s does not exist (record one with sync watch --store)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open delivery store: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var deliveries []watchOutput
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), webhookMaxBodyBytes*2)
+	for scanner.Scan() {
+		var delivery watchOutput
+		if err := json.Unmarshal(scanner.Bytes(), &delivery); err != nil || delivery.Kind != "webhook" {
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read delivery store: %w", err)
+	}
+	return deliveries, nil
+}
+
+type deliveryFilter struct {
+	since     time.Time
+	after     string
+	eventType string
+}
+
+// apply keeps deliveries received at or after since, strictly after the delivery with ID
+// after, and matching eventType.
+func (f deliveryFilter) apply(deliveries []watchOutput) ([]watchOutput, error) {
+	start := 0
+	if f.after != "" {
+		start = -1
+		for i, delivery := range deliveries {
+			if delivery.DeliveryID == f.after {
+				start = i + 1
+			}
+		}
+		if start < 0 {
+			return nil, fmt.Errorf("delivery %q is not in the store", f.after)
+		}
+	}
+	var kept []watchOutput
+	for _, delivery := range deliveries[start:] {
+		if delivery.ReceivedAt.Before(f.since) {
+			continue
+		}
+		if f.eventType != "" && delivery.EventType != f.eventType {
+			continue
+		}
+		kept = append(kept, delivery)
+	}
+	return kept, nil
+}
+
+type syncDeliveriesOptions struct {
+	storePath   string
+	sinceArg    string
+	format      string
+	execCommand string
+	execTimeout time.Duration
+	filter      deliveryFilter
+}
+
+func newSyncDeliveriesCmd(globals *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deliveries",
+		Short: "Inspect and replay webhook deliveries recorded by sync watch --store",
+	}
+
+	cmd.AddCommand(newSyncDeliveriesListCmd(globals))
+	cmd.AddCommand(newSyncDeliveriesReplayCmd(globals))
+
+	return cmd
+}
+
+func (opts *syncDeliveriesOptions) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&opts.storePath, "store", "", "Delivery store written by sync watch --store")
+	cmd.Flags().StringVar(&opts.sinceArg, "since", "", "Only deliveries received at or after this RFC3339 time")
+	cmd.Flags().StringVar(&opts.filter.after, "after", "", "Only deliveries recorded after this delivery ID")
+	cmd.Flags().StringVar(&opts.filter.eventType, "event-type", "", "Only deliveries of this event type")
+	cobra.CheckErr(cmd.MarkFlagRequired("store"))
+}
+
+func (opts *syncDeliveriesOptions) load() ([]watchOutput, error) {
+	if opts.sinceArg != "" {
+		since, err := time.Parse(time.RFC3339, opts.sinceArg)
+		if err != nil {
+			return nil, fmt.Errorf("parse --since: %w", err)
+		}
+		opts.filter.since = since
+	}
+	deliveries, err := loadDeliveries(opts.storePath)
+	if err != nil {
+		return nil, err
+	}
+	return opts.filter.apply(deliveries)
+}
+
+func newSyncDeliveriesListCmd(_ *globalOptions) *cobra.Command {
+	opts := &syncDeliveriesOptions{format: formatTable}
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List recorded webhook deliveries",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			deliveries, err := opts.load()
+			if err != nil {
+				return err
+			}
+			switch opts.format {
+			case formatJSON:
+				return writeJSON(cmd.Context(), cmd.OutOrStdout(), deliveries)
+			case formatTable:
+				headers := []string{"Received", "Delivery ID", "Event Type", "Entity"}
+				return render.Table(cmd.OutOrStdout(), headers, deliveryRows(deliveries))
+			default:
+				return fmt.Errorf("unknown format %q (expected json or table)", opts.format)
+			}
+		},
+	}
+
+	opts.register(cmd)
+	formatFlag(cmd, &opts.format, "Output format", formatJSON, formatTable)
+
+	return cmd
+}
+
+func deliveryRows(deliveries []watchOutput) [][]string {
+	rows := make([][]string, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		entity := ""
+		if kind, id := extractEntity(delivery.Raw); id != "" {
+			entity = kind + " " + id
+		}
+		rows = append(rows, []string{
+			delivery.ReceivedAt.UTC().Format(time.RFC3339),
+			delivery.DeliveryID,
+			delivery.EventType,
+			entity,
+		})
+	}
+	return rows
+}
+
+func newSyncDeliveriesReplayCmd(_ *globalOptions) *cobra.Command {
+	opts := &syncDeliveriesOptions{execTimeout: defaultExecTimeout}
+
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Re-emit recorded deliveries as sync watch events, optionally running --exec for each",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			deliveries, err := opts.load()
+			if err != nil {
+				return err
+			}
+			var hook *execHook
+			if opts.execCommand != "" {
+				hook = &execHook{log: cmd.ErrOrStderr(), command: opts.execCommand, timeout: opts.execTimeout}
+			}
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetEscapeHTML(false)
+			for _, delivery := range deliveries {
+				if err := enc.Encode(delivery); err != nil {
+					return fmt.Errorf("write webhook event: %w", err)
+				}
+				hook.fireWebhook(cmd.Context(), delivery)
+			}
+			safeLog(cmd.ErrOrStderr(), "replayed %d deliveries", len(deliveries))
+			return nil
+		},
+	}
+
+	opts.register(cmd)
+	cmd.Flags().StringVar(
+		&opts.execCommand,
+		"exec",
+		"",
+		"Shell command to run for every replayed delivery (see sync watch --exec)",
+	)
+	cmd.Flags().DurationVar(&opts.execTimeout, "exec-timeout", opts.execTimeout, "Maximum run time for each --exec command")
+
+	return cmd
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func postDelivery(t *testing.T, handler http.Handler, id, body string) int {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("Notion-Delivery-ID", id)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestWebhookHandlerStoresDeliveries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deliveries.jsonl")
+	opts := &syncWatchOptions{store: &deliveryStore{path: path}}
+	deliveries := make(chan webhookDelivery, 4)
+	handler := opts.webhookHandler(deliveries, nil)
+
+	bodies := []string{
+		`{"type":"page.created","entity":{"id":"page-1","type":"page"}}`,
+		`{"type":"page.content_updated","entity":{"id":"page-1","type":"page"}}`,
+		`{"type":"page.created","entity":{"id":"page-2","type":"page"}}`,
+	}
+	for i, body := range bodies {
+		if code := postDelivery(t, handler, "d"+string(rune('1'+i)), body); code != http.StatusOK {
+			t.Fatalf("delivery %d: expected 200, got %d", i, code)
+		}
+	}
+	// A line cut short by a crash is skipped.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	if _, err := f.WriteString(`{"kind":"webhook","delivery_id":"d4"`); err != nil {
+		t.Fatalf("write partial line: %v", err)
+	}
+	_ = f.Close()
+
+	stored, err := loadDeliveries(path)
+	if err != nil {
+		t.Fatalf("loadDeliveries: %v", err)
+	}
+	if len(stored) != 3 || stored[1].EventType != "page.content_updated" || stored[1].DeliveryID != "d2" {
+		t.Fatalf("unexpected stored deliveries: %+v", stored)
+	}
+
+	kept, err := deliveryFilter{after: "d1", eventType: "page.created"}.apply(stored)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if len(kept) != 1 || kept[0].DeliveryID != "d3" {
+		t.Fatalf("expected only d3, got %+v", kept)
+	}
+	if _, err := (deliveryFilter{after: "missing"}).apply(stored); err == nil {
+		t.Fatal("expected an unknown --after delivery to fail")
+	}
+	kept, _ = deliveryFilter{since: time.Now().Add(time.Hour)}.apply(stored)
+	if len(kept) != 0 {
+		t.Fatalf("expected nothing after a future --since, got %d", len(kept))
+	}
+}
+
+func TestWebhookHandlerRejectsWhenStoreFails(t *testing.T) {
+	opts := &syncWatchOptions{store: &deliveryStore{path: filepath.Join(t.TempDir(), "missing", "store.jsonl")}}
+	deliveries := make(chan webhookDelivery, 1)
+	code := postDelivery(t, opts.webhookHandler(deliveries, nil), "d1", `{"type":"page.created"}`)
+	if code != http.StatusInternalServerError {
+		t.Fatalf("expected 500 so Notion retries, got %d", code)
+	}
+	if len(deliveries) != 0 {
+		t.Fatal("an unstored delivery must not be processed")
+	}
+}
+
+func TestSyncDeliveriesReplay(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "deliveries.jsonl")
+	store := &deliveryStore{path: path}
+	for _, id := range []string{"d1", "d2"} {
+		if err := store.append(webhookDelivery{
+			deliveryID: id,
+			eventType:  "page.created",
+			receivedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			payload:    json.RawMessage(`{"entity":{"id":"page-` + id + `","type":"page"}}`),
+		}); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	cmd := newSyncDeliveriesReplayCmd(nil)
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{"--store", path, "--after", "d1", "--exec", `cat > "` + dir + `/$NOTION_DELIVERY_ID.json"`})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+
+	var event watchOutput
+	if err := json.Unmarshal(out.Bytes(), &event); err != nil {
+		t.Fatalf("decode replayed event: %v\n%s", err, out.String())
+	}
+	if event.Kind != "webhook" || event.DeliveryID != "d2" {
+		t.Fatalf("unexpected replayed event: %+v", event)
+	}
+	hookInput, err := os.ReadFile(filepath.Join(dir, "d2.json"))
+	if err != nil {
+		t.Fatalf("exec hook did not run: %v", err)
+	}
+	if !bytes.Equal(bytes.TrimSpace(hookInput), bytes.TrimSpace(out.Bytes())) {
+		t.Fatalf("hook input differs from stdout:\n%s\n%s", hookInput, out.String())
+	}
+	if !strings.Contains(errOut.String(), "replayed 1 deliveries") {
+		t.Fatalf("expected a replay summary, got %q", errOut.String())
+	}
+}
@@ -0,0 +1,523 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/filelock"
+	"github.com/yourorg/notionctl/internal/markdown"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/render"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+// markdownStateFile records, inside the export directory, which file holds which page.
+const (
+	markdownStateFile = ".notionctl-sync.json"
+	// markdownFilePermissions keeps exported notes private, matching the config directory.
+	markdownFilePermissions = 0o600
+)
+
+// Frontmatter keys written ahead of the page properties.
+const (
+	frontmatterTitle      = "title"
+	frontmatterID         = "notion_id"
+	frontmatterURL        = "notion_url"
+	frontmatterCreated    = "notion_created_time"
+	frontmatterLastEdited = "notion_last_edited_time"
+)
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type syncExportMDOptions struct {
+	dataSourceID string
+	dir          string
+	onConflict   string
+	interval     time.Duration
+	full         bool
+	push         bool
+	force        bool
+	exec         executionOptions
+}
+
+// markdownExportClient is the subset of the Notion client used to export pages as files.
+type markdownExportClient interface {
+	mirrorClient
+	blockReader
+}
+
+// blockReader lists the children of a block or page.
+type blockReader interface {
+	RetrieveBlockChildren(
+		ctx context.Context,
+		blockID string,
+		startCursor string,
+		pageSize int,
+	) (notion.BlockChildrenResponse, error)
+}
+
+// markdownSyncState is persisted as markdownStateFile. Hash is the SHA-256 of the file as
+// last written, so later passes can tell local edits from exported content. PropertiesHash
+// covers the page's writable properties as last exported: last_edited_time only has minute
+// precision, so it tells a remote edit made in the minute of the export apart.
+type markdownSyncState struct {
+	SyncedUntil  time.Time                    `json:"synced_until"`
+	Pages        map[string]markdownSyncEntry `json:"pages"`
+	DataSourceID string                       `json:"data_source_id"`
+}
+
+type markdownSyncEntry struct {
+	LastEditedTime time.Time `json:"last_edited_time"`
+	Path           string    `json:"path"`
+	Hash           string    `json:"hash"`
+	PropertiesHash string    `json:"properties_hash,omitempty"`
+}
+
+func newSyncExportMDCmd(globals *globalOptions) *cobra.Command {
+	opts := &syncExportMDOptions{onConflict: conflictSkip, exec: defaultExecutionOptions()}
+
+	cmd := &cobra.Command{
+		Use:   "export-md",
+		Short: "Write each page of a data source as a Markdown file with YAML frontmatter",
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Target Notion data source ID or URL")
+	cmd.Flags().StringVar(&opts.dir, "dir", "", "Directory to write Markdown files into")
+	cmd.Flags().DurationVar(
+		&opts.interval,
+		"interval",
+		0,
+		"Keep running and export changes at this interval (0 exports once and exits)",
+	)
+	cmd.Flags().BoolVar(&opts.full, "full", false, "Re-export every page and remove files for pages no longer present")
+	cmd.Flags().BoolVar(&opts.push, "push", false, "Push locally edited files back to Notion before exporting")
+	cmd.Flags().StringVar(
+		&opts.onConflict,
+		"on-conflict",
+		opts.onConflict,
+		"With --push, what to do when a file and its page both changed: skip|local|remote",
+	)
+	cmd.Flags().BoolVar(
+		&opts.force,
+		"force",
+		false,
+		"With --push, replace page content even when that deletes blocks Markdown cannot represent",
+	)
+	opts.exec.register(cmd, "")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
+	cobra.CheckErr(cmd.MarkFlagRequired("dir"))
+
+	return cmd
+}
+
+func (opts *syncExportMDOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		if opts.interval < 0 {
+			return errors.New("--interval must not be negative")
+		}
+		switch opts.onConflict {
+		case conflictSkip, conflictLocal, conflictRemote:
+		default:
+			return fmt.Errorf("unknown --on-conflict %q (expected skip, local, or remote)", opts.onConflict)
+		}
+		client, err := opts.exec.buildClient(globals)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(opts.dir, 0o750); err != nil {
+			return fmt.Errorf("create export directory: %w", err)
+		}
+
+		ctx := cmd.Context()
+		full := opts.full
+		for {
+			if err := opts.syncOnce(ctx, client, full, time.Now(), cmd.ErrOrStderr()); err != nil {
+				return err
+			}
+			if opts.interval == 0 {
+				return nil
+			}
+			full = false
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(opts.interval):
+			}
+		}
+	}
+}
+
+// syncOnce exports every page on the first run (or with full set) and otherwise only pages
+// edited since the previous pass. Files whose content is unchanged are left untouched. With
+// push set, local edits are uploaded first.
+func (opts *syncExportMDOptions) syncOnce(
+	ctx context.Context,
+	client markdownSyncClient,
+	full bool,
+	now time.Time,
+	log io.Writer,
+) error {
+	state, err := loadMarkdownSyncState(opts.dir)
+	if err != nil {
+		return err
+	}
+	if state.DataSourceID != "" && state.DataSourceID != opts.dataSourceID {
+		return fmt.Errorf("%s is already synced with data source %s", opts.dir, state.DataSourceID)
+	}
+	state.DataSourceID = opts.dataSourceID
+
+	ds, err := client.GetDataSource(ctx, opts.dataSourceID)
+	if err != nil {
+		return fmt.Errorf("get data source: %w", err)
+	}
+	idx := schema.NewIndex(ds)
+
+	conflicts := map[string]bool{}
+	if opts.push {
+		if conflicts, err = opts.pushLocalEdits(ctx, client, state, idx, log); err != nil {
+			return err
+		}
+	}
+
+	until := now.UTC().Truncate(time.Second)
+	var pages []notion.Page
+	if full || state.SyncedUntil.IsZero() {
+		resp, err := executeDataSourceQuery(ctx, client, opts.dataSourceID, notion.QueryDataSourceRequest{}, true, 0)
+		if err != nil {
+			return err
+		}
+		pages, full = resp.Results, true
+	} else {
+		pages, err = fetchChanges(ctx, client, opts.dataSourceID, state.SyncedUntil.Add(-mirrorOverlap), until, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The overlap window re-reads pages already exported; skip those unless forced.
+	// Conflicted pages keep their local edits until the conflict is resolved.
+	pending := make([]notion.Page, 0, len(pages))
+	for _, page := range pages {
+		entry, ok := state.Pages[page.ID]
+		if conflicts[page.ID] || (!full && ok && entry.LastEditedTime.Equal(page.LastEditedTime)) {
+			continue
+		}
+		pending = append(pending, page)
+	}
+
+	docs := make([][]byte, len(pending))
+	err = opts.exec.forEach(ctx, len(pending), func(ctx context.Context, i int) error {
+		if pending[i].Archived {
+			return nil
+		}
+		blocks, err := fetchBlockTree(ctx, client, pending[i].ID)
+		if err != nil {
+			return fmt.Errorf("read page %s: %w", pending[i].ID, err)
+		}
+		docs[i], err = markdownDocument(pending[i], idx, blocks)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	var written, removed int
+	seen := make(map[string]bool, len(pages))
+	for _, page := range pages {
+		seen[page.ID] = true
+	}
+	for i, page := range pending {
+		if page.Archived {
+			ok, err := state.remove(opts.dir, page.ID)
+			if err != nil {
+				return err
+			}
+			if ok {
+				removed++
+			}
+			continue
+		}
+		changed, err := state.write(opts.dir, page, docs[i])
+		if err != nil {
+			return err
+		}
+		if changed {
+			written++
+		}
+	}
+	if full {
+		for _, id := range render.SortedKeys(state.Pages) {
+			if seen[id] {
+				continue
+			}
+			if _, err := state.remove(opts.dir, id); err != nil {
+				return err
+			}
+			removed++
+		}
+	}
+
+	state.SyncedUntil = until
+	if err := state.save(opts.dir); err != nil {
+		return err
+	}
+	safeLog(log, "exported %d pages to %s (%d written, %d removed)", len(pending), opts.dir, written, removed)
+	return nil
+}
+
+// markdownDocument renders a page as frontmatter (title, Notion metadata, then properties in
+// name order) followed by its content.
+func markdownDocument(page notion.Page, idx *schema.Index, blocks []notion.Block) ([]byte, error) {
+	fields := []markdown.Field{
+		{Key: frontmatterTitle, Value: pageTitle(page)},
+		{Key: frontmatterID, Value: page.ID},
+		{Key: frontmatterURL, Value: page.URL},
+		{Key: frontmatterCreated, Value: page.CreatedTime.UTC().Format(time.RFC3339)},
+		{Key: frontmatterLastEdited, Value: page.LastEditedTime.UTC().Format(time.RFC3339)},
+	}
+	for _, name := range idx.PropertyNames() {
+		ref, _ := idx.ReferenceForName(name)
+		value, ok := page.Properties[ref.Name]
+		if !ok || value.Type == "title" {
+			continue
+		}
+		fields = append(fields, markdown.Field{Key: frontmatterKey(name), Value: frontmatterValue(value)})
+	}
+	return markdown.Document(fields, markdown.FromBlocks(blocks))
+}
+
+// frontmatterKey keeps property names as-is unless they collide with a reserved key.
+func frontmatterKey(property string) string {
+	switch strings.ToLower(property) {
+	case frontmatterTitle, frontmatterID, frontmatterURL, frontmatterCreated, frontmatterLastEdited:
+		return property + "_property"
+	}
+	return property
+}
+
+// frontmatterValue keeps numbers, checkboxes, and lists typed so YAML consumers can use
+// them; everything else is the text ds query shows.
+func frontmatterValue(value notion.PropertyValue) any {
+	switch value.Type {
+	case "number":
+		if value.Number == nil {
+			return nil
+		}
+		return *value.Number
+	case "checkbox":
+		return value.Checkbox != nil && *value.Checkbox
+	case "multi_select":
+		names := make([]string, 0, len(value.MultiSelect))
+		for _, option := range value.MultiSelect {
+			names = append(names, option.Name)
+		}
+		return names
+	case "people":
+		names := make([]string, 0, len(value.People))
+		for _, person := range value.People {
+			names = append(names, joinPeople([]notion.UserReference{person}))
+		}
+		return names
+	case relationType:
+		ids := make([]string, 0, len(value.Relation))
+		for _, rel := range value.Relation {
+			ids = append(ids, rel.ID)
+		}
+		return ids
+	case "date":
+		if value.Date == nil {
+			return nil
+		}
+		if value.Date.End != nil && *value.Date.End != "" {
+			return map[string]string{"start": value.Date.Start, "end": *value.Date.End}
+		}
+		return value.Date.Start
+	}
+	if text := summarizeProperty(value); text != "" && text != value.Type {
+		return text
+	}
+	return nil
+}
+
+// fetchBlockTree reads every block under blockID, attaching nested children to the blocks
+// that can hold them.
+func fetchBlockTree(ctx context.Context, client blockReader, blockID string) ([]notion.Block, error) {
+	blocks, err := listBlockChildren(ctx, client, blockID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range blocks {
+		// SetChildren(nil) doubles as a check that the type can hold children; child pages
+		// and databases report has_children but are not part of this page's content.
+		if !blocks[i].HasChildren || blocks[i].ID == "" || !blocks[i].SetChildren(nil) {
+			continue
+		}
+		children, err := fetchBlockTree(ctx, client, blocks[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		blocks[i].SetChildren(children)
+	}
+	return blocks, nil
+}
+
+// listBlockChildren reads every direct child of blockID.
+func listBlockChildren(ctx context.Context, client blockReader, blockID string) ([]notion.Block, error) {
+	var blocks []notion.Block
+	cursor := ""
+	for {
+		resp, err := client.RetrieveBlockChildren(ctx, blockID, cursor, maxQueryPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("retrieve block children: %w", err)
+		}
+		blocks = append(blocks, resp.Results...)
+		if !resp.HasMore || resp.NextCursor == "" {
+			return blocks, nil
+		}
+		cursor = resp.NextCursor
+	}
+}
+
+func loadMarkdownSyncState(dir string) (*markdownSyncState, error) {
+	state := &markdownSyncState{Pages: map[string]markdownSyncEntry{}}
+	data, err := os.ReadFile(filepath.Join(dir, markdownStateFile)) // #nosec G304 -- state file inside the user's export directory
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read sync state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("decode sync state: %w", err)
+	}
+	if state.Pages == nil {
+		state.Pages = map[string]markdownSyncEntry{}
+	}
+	return state, nil
+}
+
+func (s *markdownSyncState) save(dir string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode sync state: %w", err)
+	}
+	path := filepath.Join(dir, markdownStateFile)
+	return filelock.With(path, func() error {
+		return filelock.WriteFile(path, append(data, '\n'), markdownFilePermissions)
+	})
+}
+
+// write stores doc for page, renaming the file when the title changed. It reports whether
+// anything on disk changed.
+func (s *markdownSyncState) write(dir string, page notion.Page, doc []byte) (bool, error) {
+	entry, known := s.Pages[page.ID]
+	path := s.pathFor(page)
+	hash := contentHash(doc)
+
+	if known && entry.Path == path && entry.Hash == hash {
+		if _, err := os.Stat(filepath.Join(dir, path)); err == nil {
+			entry.LastEditedTime = page.LastEditedTime
+			entry.PropertiesHash = propertiesHash(page)
+			s.Pages[page.ID] = entry
+			return false, nil
+		}
+	}
+	if err := filelock.WriteFile(filepath.Join(dir, path), doc, markdownFilePermissions); err != nil {
+		return false, err
+	}
+	if known && entry.Path != path {
+		if err := os.Remove(filepath.Join(dir, entry.Path)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return false, fmt.Errorf("remove renamed file: %w", err)
+		}
+	}
+	s.Pages[page.ID] = markdownSyncEntry{
+		LastEditedTime: page.LastEditedTime,
+		Path:           path,
+		Hash:           hash,
+		PropertiesHash: propertiesHash(page),
+	}
+	return true, nil
+}
+
+// remove deletes the file exported for pageID, reporting whether there was one.
+func (s *markdownSyncState) remove(dir, pageID string) (bool, error) {
+	entry, ok := s.Pages[pageID]
+	if !ok {
+		return false, nil
+	}
+	if err := os.Remove(filepath.Join(dir, entry.Path)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, fmt.Errorf("remove %s: %w", entry.Path, err)
+	}
+	delete(s.Pages, pageID)
+	return true, nil
+}
+
+// pathFor names the file after the page title. A page keeps its file while the title is
+// unchanged; titles already taken by another page get a short ID suffix.
+func (s *markdownSyncState) pathFor(page notion.Page) string {
+	base := markdownFileName(pageTitle(page))
+	path := base + ".md"
+	short := strings.ReplaceAll(page.ID, "-", "")
+	if len(short) > 8 {
+		short = short[:8]
+	}
+	suffixed := fmt.Sprintf("%s (%s).md", base, short)
+	if entry, ok := s.Pages[page.ID]; ok && (entry.Path == path || entry.Path == suffixed) {
+		return entry.Path
+	}
+	for id, entry := range s.Pages {
+		if id != page.ID && strings.EqualFold(entry.Path, path) {
+			return suffixed
+		}
+	}
+	return path
+}
+
+// markdownFileName drops characters that are invalid in file names on common platforms.
+func markdownFileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if r < ' ' || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return -1
+		}
+		return r
+	}, title)
+	name = strings.Trim(strings.TrimSpace(name), ".")
+	if name == "" {
+		return "Untitled"
+	}
+	return name
+}
+
+// propertiesHash hashes the values of the title and the properties a push writes back, so
+// formulas and rollups recomputing on their own are not mistaken for edits.
+func propertiesHash(page notion.Page) string {
+	values := map[string]any{}
+	for name, value := range page.Properties {
+		if value.Type == "title" || pushableTypes[value.Type] {
+			values[name] = frontmatterValue(value)
+		}
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return ""
+	}
+	return contentHash(data)
+}
+
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+type fakeMarkdownClient struct {
+	fakeMirrorClient
+	blocks  map[string][]notion.Block
+	updates []map[string]any
+	deleted []string
+}
+
+func (f *fakeMarkdownClient) RetrieveBlockChildren(
+	_ context.Context,
+	blockID string,
+	_ string,
+	_ int,
+) (notion.BlockChildrenResponse, error) {
+	return notion.BlockChildrenResponse{Results: f.blocks[blockID]}, nil
+}
+
+func (f *fakeMarkdownClient) RetrievePage(_ context.Context, pageID string) (notion.Page, error) {
+	for _, page := range f.pages {
+		if page.ID == pageID {
+			return page, nil
+		}
+	}
+	return notion.Page{}, errors.New("page not found")
+}
+
+func (f *fakeMarkdownClient) UpdatePage(
+	_ context.Context,
+	pageID string,
+	req notion.UpdatePageRequest,
+) (notion.Page, error) {
+	f.updates = append(f.updates, req.Properties)
+	return f.RetrievePage(context.Background(), pageID)
+}
+
+func (f *fakeMarkdownClient) AppendBlockChildren(
+	_ context.Context,
+	blockID string,
+	blocks []notion.Block,
+) ([]notion.Block, error) {
+	for i := range blocks {
+		blocks[i].ID = fmt.Sprintf("%s-new-%d", blockID, i)
+	}
+	f.blocks[blockID] = append(f.blocks[blockID], blocks...)
+	return blocks, nil
+}
+
+func (f *fakeMarkdownClient) DeleteBlock(_ context.Context, blockID string) error {
+	f.deleted = append(f.deleted, blockID)
+	for parent, blocks := range f.blocks {
+		f.blocks[parent] = slices.DeleteFunc(blocks, func(b notion.Block) bool { return b.ID == blockID })
+	}
+	return nil
+}
+
+func TestSyncExportMarkdown(t *testing.T) {
+	edited := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	page := func(id, title, status string) notion.Page {
+		return notion.Page{ID: id, URL: "https://notion.so/" + id, LastEditedTime: edited, Properties: map[string]notion.PropertyValue{
+			"Name":   {Type: "title", Title: []notion.RichText{{PlainText: title}}},
+			"Status": {Type: "status", Status: &notion.StatusValue{Name: status}},
+			"Tags":   {Type: "multi_select", MultiSelect: []notion.SelectValue{{Name: "cli"}}},
+		}}
+	}
+	client := &fakeMarkdownClient{
+		fakeMirrorClient: fakeMirrorClient{
+			ds: notion.DataSource{Properties: map[string]notion.PropertyReference{
+				"Name":   {ID: "title", Name: "Name", Type: "title"},
+				"Status": {ID: "st", Name: "Status", Type: "status"},
+				"Tags":   {ID: "tg", Name: "Tags", Type: "multi_select"},
+			}},
+			pages: []notion.Page{page("p1", "Launch plan", "Open"), page("p2", "Launch plan", "Done")},
+		},
+		blocks: map[string][]notion.Block{
+			"p1": {{ID: "b1", Type: "toggle", HasChildren: true, Toggle: &notion.ToggleBlock{
+				RichText: []notion.RichText{{PlainText: "Details"}},
+			}}},
+			"b1": {{Type: "paragraph", Paragraph: &notion.ParagraphBlock{RichText: []notion.RichText{{PlainText: "Hidden"}}}}},
+		},
+	}
+
+	dir := t.TempDir()
+	opts := &syncExportMDOptions{dataSourceID: "ds", dir: dir, exec: defaultExecutionOptions()}
+	now := edited.Add(time.Hour)
+	if err := opts.syncOnce(context.Background(), client, false, now, nil); err != nil {
+		t.Fatalf("first sync returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "Launch plan.md"))
+	if err != nil {
+		t.Fatalf("read exported file: %v", err)
+	}
+	doc := string(data)
+	for _, want := range []string{
+		"title: Launch plan\n",
+		"notion_id: p1\n",
+		"Status: Open\n",
+		"Tags:\n  - cli\n",
+		"<summary>Details</summary>\n\nHidden\n\n</details>\n",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Fatalf("exported file missing %q:\n%s", want, doc)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Launch plan (p2).md")); err != nil {
+		t.Fatalf("expected the duplicate title to get an ID suffix: %v", err)
+	}
+
+	// An incremental pass renames the file after a title change and drops archived pages.
+	renamed := page("p1", "Launch retro", "Done")
+	renamed.LastEditedTime = now.Add(time.Minute)
+	archived := page("p2", "Launch plan", "Done")
+	archived.LastEditedTime, archived.Archived = now.Add(time.Minute), true
+	client.pages = []notion.Page{renamed, archived}
+	if err := opts.syncOnce(context.Background(), client, false, now.Add(time.Hour), nil); err != nil {
+		t.Fatalf("second sync returned error: %v", err)
+	}
+	if len(client.filters) != 2 || client.filters[1] == nil {
+		t.Fatalf("expected an incremental last_edited_time query, got %#v", client.filters)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if got := strings.Join(names, ","); got != ".notionctl-sync.json,Launch retro.md" {
+		t.Fatalf("unexpected directory contents: %s", got)
+	}
+
+	other := &syncExportMDOptions{dataSourceID: "other", dir: dir, exec: defaultExecutionOptions()}
+	if err := other.syncOnce(context.Background(), client, false, now, nil); err == nil {
+		t.Fatalf("expected an error when reusing the directory for another data source")
+	}
+}
+
+func TestSyncExportMarkdownPushesLocalEdits(t *testing.T) {
+	edited := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	task := notion.Page{ID: "p1", LastEditedTime: edited, Properties: map[string]notion.PropertyValue{
+		"Name":   {Type: "title", Title: []notion.RichText{{PlainText: "Launch"}}},
+		"Points": {Type: "number"},
+		"Status": {Type: "status", Status: &notion.StatusValue{Name: "Open"}},
+	}}
+	client := &fakeMarkdownClient{
+		fakeMirrorClient: fakeMirrorClient{
+			ds: notion.DataSource{Properties: map[string]notion.PropertyReference{
+				"Name":   {ID: "title", Name: "Name", Type: "title"},
+				"Points": {ID: "pt", Name: "Points", Type: "number"},
+				"Status": {ID: "st", Name: "Status", Type: "status"},
+			}},
+			pages: []notion.Page{task},
+		},
+		blocks: map[string][]notion.Block{"p1": {{ID: "old", Type: "paragraph", Paragraph: &notion.ParagraphBlock{
+			RichText: []notion.RichText{{PlainText: "Draft"}},
+		}}}},
+	}
+
+	dir := t.TempDir()
+	opts := &syncExportMDOptions{dataSourceID: "ds", dir: dir, push: true, onConflict: conflictSkip, exec: defaultExecutionOptions()}
+	now := edited.Add(time.Hour)
+	if err := opts.syncOnce(context.Background(), client, false, now, nil); err != nil {
+		t.Fatalf("export returned error: %v", err)
+	}
+	path := filepath.Join(dir, "Launch.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read exported file: %v", err)
+	}
+
+	local := strings.Replace(string(data), "Status: Open", "Status: Done", 1)
+	local = strings.Replace(local, "Points: null", "Points: 5", 1)
+	local = strings.Replace(local, "Draft", "Final copy", 1)
+	if err := os.WriteFile(path, []byte(local), 0o600); err != nil {
+		t.Fatalf("edit file: %v", err)
+	}
+	if err := opts.syncOnce(context.Background(), client, false, now.Add(time.Minute), nil); err != nil {
+		t.Fatalf("push returned error: %v", err)
+	}
+	if len(client.updates) != 1 {
+		t.Fatalf("expected one property update, got %#v", client.updates)
+	}
+	status, _ := client.updates[0]["Status"].(map[string]any)
+	points, _ := client.updates[0]["Points"].(map[string]any)
+	if _, titled := client.updates[0]["Name"]; titled || status["status"] == nil || points["number"] != 5.0 {
+		t.Fatalf("unexpected property update: %#v", client.updates[0])
+	}
+	if !slices.Equal(client.deleted, []string{"old"}) {
+		t.Fatalf("expected the old content to be deleted, got %v", client.deleted)
+	}
+	if got := client.blocks["p1"]; len(got) != 1 || got[0].Paragraph == nil {
+		t.Fatalf("expected the body to be appended as one paragraph, got %#v", got)
+	}
+
+	// A file edited while the page also changed in Notion is left alone by default.
+	local = strings.Replace(local, "Final copy", "Local only", 1)
+	if err := os.WriteFile(path, []byte(local), 0o600); err != nil {
+		t.Fatalf("edit file: %v", err)
+	}
+	client.pages[0].LastEditedTime = now.Add(2 * time.Hour)
+	var log bytes.Buffer
+	if err := opts.syncOnce(context.Background(), client, false, now.Add(3*time.Hour), &log); err != nil {
+		t.Fatalf("conflicting sync returned error: %v", err)
+	}
+	if !strings.Contains(log.String(), "conflict: Launch.md") || len(client.updates) != 1 {
+		t.Fatalf("expected a skipped conflict, log:\n%s", log.String())
+	}
+	if data, _ := os.ReadFile(path); string(data) != local {
+		t.Fatalf("conflicting local edits were overwritten:\n%s", data)
+	}
+}
+
+func TestRemoteChangedWithinTheExportMinute(t *testing.T) {
+	edited := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	page := notion.Page{ID: "p1", LastEditedTime: edited, Properties: map[string]notion.PropertyValue{
+		"Status": {Type: "status", Status: &notion.StatusValue{Name: "Open"}},
+		"Total":  {Type: "formula"},
+	}}
+	entry := markdownSyncEntry{LastEditedTime: edited, PropertiesHash: propertiesHash(page)}
+	if remoteChanged(page, entry) {
+		t.Fatal("an unchanged page was reported as edited")
+	}
+
+	page.Properties["Total"] = notion.PropertyValue{Type: "formula", Formula: &notion.FormulaValue{Type: "number"}}
+	if remoteChanged(page, entry) {
+		t.Fatal("a recomputed formula was reported as an edit")
+	}
+	page.Properties["Status"] = notion.PropertyValue{Type: "status", Status: &notion.StatusValue{Name: "Done"}}
+	if !remoteChanged(page, entry) {
+		t.Fatal("an edit in the same minute as the export was missed")
+	}
+	if !remoteChanged(notion.Page{LastEditedTime: edited.Add(-time.Minute)}, entry) {
+		t.Fatal("a different edit time should count as a change")
+	}
+}
+
+func TestFrontmatterUpdatesSkipTruncatedRelations(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{Properties: map[string]notion.PropertyReference{
+		"Name":  {ID: "title", Name: "Name", Type: "title"},
+		"Tasks": {ID: "tk", Name: "Tasks", Type: relationType},
+	}})
+	page := notion.Page{ID: "p1", Properties: map[string]notion.PropertyValue{
+		"Name":  {Type: "title", Title: []notion.RichText{{PlainText: "Launch"}}},
+		"Tasks": {Type: relationType, Relation: []notion.RelationReference{{ID: "a"}}, HasMore: true},
+	}}
+	updates, err := frontmatterUpdates(page, idx, map[string]any{"Tasks": []any{"b"}})
+	if err != nil {
+		t.Fatalf("frontmatterUpdates returned error: %v", err)
+	}
+	if len(updates) != 0 {
+		t.Fatalf("a truncated relation must not be written back, got %#v", updates)
+	}
+
+	page.Properties["Tasks"] = notion.PropertyValue{Type: relationType, Relation: []notion.RelationReference{{ID: "a"}}}
+	if updates, err = frontmatterUpdates(page, idx, map[string]any{"Tasks": []any{"b"}}); err != nil || updates["Tasks"] == nil {
+		t.Fatalf("expected a complete relation to be written back, got %#v, %v", updates, err)
+	}
+}
+
+func TestSyncExportMarkdownPushKeepsUnsupportedBlocks(t *testing.T) {
+	edited := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	client := &fakeMarkdownClient{
+		fakeMirrorClient: fakeMirrorClient{
+			ds: notion.DataSource{Properties: map[string]notion.PropertyReference{
+				"Name": {ID: "title", Name: "Name", Type: "title"},
+			}},
+			pages: []notion.Page{{ID: "p1", LastEditedTime: edited, Properties: map[string]notion.PropertyValue{
+				"Name": {Type: "title", Title: []notion.RichText{{PlainText: "Launch"}}},
+			}}},
+		},
+		blocks: map[string][]notion.Block{"p1": {
+			{ID: "text", Type: "paragraph", Paragraph: &notion.ParagraphBlock{
+				RichText: []notion.RichText{{PlainText: "Draft"}},
+			}},
+			{ID: "synced", Type: "synced_block"},
+		}},
+	}
+
+	dir := t.TempDir()
+	opts := &syncExportMDOptions{dataSourceID: "ds", dir: dir, push: true, onConflict: conflictSkip, exec: defaultExecutionOptions()}
+	now := edited.Add(time.Hour)
+	if err := opts.syncOnce(context.Background(), client, false, now, nil); err != nil {
+		t.Fatalf("export returned error: %v", err)
+	}
+	path := filepath.Join(dir, "Launch.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read exported file: %v", err)
+	}
+	local := strings.Replace(string(data), "Draft", "Final", 1)
+	if err := os.WriteFile(path, []byte(local), 0o600); err != nil {
+		t.Fatalf("edit file: %v", err)
+	}
+
+	var log bytes.Buffer
+	if err := opts.syncOnce(context.Background(), client, false, now.Add(time.Minute), &log); err != nil {
+		t.Fatalf("push returned error: %v", err)
+	}
+	if len(client.deleted) != 0 || len(client.blocks["p1"]) != 2 {
+		t.Fatalf("a page with unsupported blocks was changed: deleted %v, blocks %#v", client.deleted, client.blocks["p1"])
+	}
+	if !strings.Contains(log.String(), "Launch.md: page has blocks Markdown cannot represent (synced_block)") {
+		t.Fatalf("expected the skipped push to be reported, log:\n%s", log.String())
+	}
+	if data, _ := os.ReadFile(path); string(data) != local {
+		t.Fatalf("the skipped file was overwritten:\n%s", data)
+	}
+
+	opts.force = true
+	if err := opts.syncOnce(context.Background(), client, false, now.Add(2*time.Minute), nil); err != nil {
+		t.Fatalf("forced push returned error: %v", err)
+	}
+	if !slices.Equal(client.deleted, []string{"text", "synced"}) {
+		t.Fatalf("expected --force to replace the content, deleted %v", client.deleted)
+	}
+}
@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/yourorg/notionctl/internal/filelock"
+	"github.com/yourorg/notionctl/internal/github"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/props"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+const (
+	githubTokenEnv         = "GITHUB_TOKEN"
+	githubAPIURLEnv        = "GITHUB_API_URL"
+	githubStatePermissions = 0o600
+)
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type syncGitHubOptions struct {
+	repo         string
+	dataSourceID string
+	mapPath      string
+	statePath    string
+	sinceArg     string
+	apiURL       string
+	dryRun       bool
+}
+
+// githubMap is the --map file: which property identifies an issue and which issue field
+// fills each other property.
+type githubMap struct {
+	Key        string            `yaml:"key"`
+	Properties map[string]string `yaml:"properties"`
+}
+
+// githubSyncState records, per repository, the newest updated_at already synced.
+type githubSyncState struct {
+	Repos map[string]time.Time `json:"repos"`
+}
+
+// githubIssueSource is the subset of github.Client used by a sync.
+type githubIssueSource interface {
+	Issues(ctx context.Context, repo string, since time.Time) iter.Seq2[github.Issue, error]
+}
+
+// githubSyncClient is the subset of the Notion client used by a sync.
+type githubSyncClient interface {
+	importClient
+	GetDataSource(ctx context.Context, dataSourceID string) (notion.DataSource, error)
+}
+
+func newSyncGitHubCmd(globals *globalOptions) *cobra.Command {
+	opts := &syncGitHubOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "github",
+		Short: "Upsert GitHub issues and pull requests into a data source",
+		Long: "Pull issues and pull requests from a GitHub repository and create or update one page per issue, " +
+			"matched on the map file's key property. The token is read from " + githubTokenEnv + ". With --state, " +
+			"each run only fetches issues updated since the previous one.",
+		RunE: opts.run(globals),
+	}
+
+	cmd.Flags().StringVar(&opts.repo, "repo", "", "GitHub repository as owner/name")
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Target Notion data source ID or URL")
+	cmd.Flags().StringVar(&opts.mapPath, "map", "", "YAML file mapping issue fields to properties")
+	cmd.Flags().StringVar(&opts.statePath, "state", "", "JSON file remembering the last sync per repository")
+	cmd.Flags().StringVar(&opts.sinceArg, "since", "", "Only sync issues updated at or after this RFC3339 time (overrides --state)")
+	cmd.Flags().StringVar(&opts.apiURL, "github-api", "", "GitHub API base URL (default: $"+githubAPIURLEnv+" or "+
+		github.DefaultBaseURL+")")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print each create/update request that would be sent without sending it")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("repo"))
+	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
+	cobra.CheckErr(cmd.MarkFlagRequired("map"))
+
+	return cmd
+}
+
+func (opts *syncGitHubOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		if _, _, err := github.ParseRepo(opts.repo); err != nil {
+			return err
+		}
+		mapping, err := loadGitHubMap(opts.mapPath)
+		if err != nil {
+			return err
+		}
+		state, err := loadGitHubSyncState(opts.statePath)
+		if err != nil {
+			return err
+		}
+		since := state.Repos[opts.repo]
+		if opts.sinceArg != "" {
+			parsed, err := time.Parse(time.RFC3339, opts.sinceArg)
+			if err != nil {
+				return fmt.Errorf("parse --since: %w", err)
+			}
+			since = parsed.UTC()
+		}
+
+		client, err := buildClient(globals)
+		if err != nil {
+			return err
+		}
+		if opts.dryRun {
+			client = dryRunClient(client, cmd.OutOrStdout())
+		}
+		source := &github.Client{
+			BaseURL: firstNonEmpty(opts.apiURL, os.Getenv(githubAPIURLEnv)),
+			Token:   os.Getenv(githubTokenEnv),
+		}
+
+		summary, newest, err := opts.sync(cmd.Context(), client, source, mapping, since, cmd.ErrOrStderr())
+		// Issues arrive oldest update first, so everything up to newest is done even when a
+		// later issue failed or the listing stopped.
+		if opts.statePath != "" && !opts.dryRun && newest.After(state.Repos[opts.repo]) {
+			state.Repos[opts.repo] = newest
+			if saveErr := state.save(opts.statePath); saveErr != nil {
+				return errors.Join(err, saveErr)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		safeLog(cmd.ErrOrStderr(), "%s: %s", opts.repo, summary)
+		if summary.Failed > 0 {
+			return fmt.Errorf("%d of %d issues failed; the next run retries them", summary.Failed, summary.Rows)
+		}
+		return nil
+	}
+}
+
+// sync upserts every issue updated at or after since and returns the updated_at up to which
+// every issue was written. An issue that fails is logged and counted, and holds the
+// returned time before it so the next run tries it again.
+func (opts *syncGitHubOptions) sync(
+	ctx context.Context,
+	client githubSyncClient,
+	source githubIssueSource,
+	mapping githubMap,
+	since time.Time,
+	log io.Writer,
+) (importSummary, time.Time, error) {
+	var (
+		summary importSummary
+		newest  time.Time
+	)
+	ds, err := client.GetDataSource(ctx, opts.dataSourceID)
+	if err != nil {
+		return summary, newest, fmt.Errorf("get data source: %w", err)
+	}
+	idx := schema.NewIndex(ds)
+	keyRef, err := mapping.validate(idx)
+	if err != nil {
+		return summary, newest, err
+	}
+
+	for issue, err := range source.Issues(ctx, opts.repo, since) {
+		if err != nil {
+			return summary, newest, err
+		}
+		summary.Rows++
+		created, err := opts.upsertIssue(ctx, client, idx, keyRef, mapping, issue)
+		if err != nil {
+			summary.Failed++
+			safeLog(log, "%s#%d: %v", opts.repo, issue.Number, err)
+			continue
+		}
+		if created {
+			summary.Created++
+		} else {
+			summary.Updated++
+		}
+		if summary.Failed == 0 && issue.UpdatedAt.After(newest) {
+			newest = issue.UpdatedAt
+		}
+		safeLog(log, "Synced %s#%d %s", opts.repo, issue.Number, issue.Title)
+	}
+	return summary, newest, nil
+}
+
+// upsertIssue writes one issue, updating the page whose key matches or creating a new one.
+func (opts *syncGitHubOptions) upsertIssue(
+	ctx context.Context,
+	client importClient,
+	idx *schema.Index,
+	keyRef notion.PropertyReference,
+	mapping githubMap,
+	issue github.Issue,
+) (bool, error) {
+	key := opts.issueKey(keyRef, issue)
+	properties, err := mapping.properties(idx, issue)
+	if err != nil {
+		return false, err
+	}
+	keyPayload, err := props.Coerce(keyRef, key)
+	if err != nil {
+		return false, err
+	}
+	properties[keyRef.Name] = keyPayload
+
+	matches, err := pagesWithKey(ctx, client, opts.dataSourceID, keyRef, key)
+	if err != nil {
+		return false, err
+	}
+	switch len(matches) {
+	case 0:
+		if _, err := client.CreatePage(ctx, notion.CreatePageRequest{
+			Parent:     notion.DataSourceParent(opts.dataSourceID),
+			Properties: properties,
+		}); err != nil {
+			return false, fmt.Errorf("create page: %w", err)
+		}
+		return true, nil
+	case 1:
+		if _, err := client.UpdatePage(ctx, matches[0].ID, notion.UpdatePageRequest{Properties: properties}); err != nil {
+			return false, fmt.Errorf("update page %s: %w", matches[0].ID, err)
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("key %s=%q matches more than one row", keyRef.Name, key)
+	}
+}
+
+// issueKey is the issue number for a number key property and "owner/name#number" otherwise,
+// so one data source can hold issues from several repositories.
+func (opts *syncGitHubOptions) issueKey(keyRef notion.PropertyReference, issue github.Issue) string {
+	if keyRef.Type == "number" {
+		return fmt.Sprint(issue.Number)
+	}
+	return fmt.Sprintf("%s#%d", opts.repo, issue.Number)
+}
+
+func loadGitHubMap(path string) (githubMap, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- reading user-supplied map file is intentional
+	if err != nil {
+		return githubMap{}, fmt.Errorf("read map: %w", err)
+	}
+	var mapping githubMap
+	if err := yaml.Unmarshal(data, &mapping); err != nil {
+		return githubMap{}, fmt.Errorf("decode map: %w", err)
+	}
+	if mapping.Key == "" {
+		return githubMap{}, errors.New("map file must name a key property")
+	}
+	return mapping, nil
+}
+
+// validate checks every mapped property and field up front so a typo fails before any page
+// is written, and returns the key property.
+func (m githubMap) validate(idx *schema.Index) (notion.PropertyReference, error) {
+	keyRef, ok := idx.ReferenceForName(m.Key)
+	if !ok {
+		return notion.PropertyReference{}, fmt.Errorf("map: unknown key property %q", m.Key)
+	}
+	for name, field := range m.Properties {
+		ref, ok := idx.ReferenceForName(name)
+		if !ok {
+			return notion.PropertyReference{}, fmt.Errorf("map: unknown property %q", name)
+		}
+		if ref.Name == keyRef.Name {
+			return notion.PropertyReference{}, fmt.Errorf("map: key property %q cannot also be mapped to a field", name)
+		}
+		if _, err := (github.Issue{}).Field(field); err != nil {
+			return notion.PropertyReference{}, fmt.Errorf("map: property %q: %w (want one of %v)", name, err, github.Fields)
+		}
+	}
+	return keyRef, nil
+}
+
+// properties builds the page properties for issue from the mapped fields.
+func (m githubMap) properties(idx *schema.Index, issue github.Issue) (map[string]any, error) {
+	properties := make(map[string]any, len(m.Properties)+1)
+	for name, field := range m.Properties {
+		ref, _ := idx.ReferenceForName(name)
+		value, err := issue.Field(field)
+		if err != nil {
+			return nil, err
+		}
+		payload, err := props.Coerce(ref, value)
+		if err != nil {
+			return nil, err
+		}
+		properties[ref.Name] = payload
+	}
+	return properties, nil
+}
+
+func loadGitHubSyncState(path string) (*githubSyncState, error) {
+	state := &githubSyncState{Repos: map[string]time.Time{}}
+	if path == "" {
+		return state, nil
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- reading user-supplied state file is intentional
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read sync state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("decode sync state: %w", err)
+	}
+	if state.Repos == nil {
+		state.Repos = map[string]time.Time{}
+	}
+	return state, nil
+}
+
+func (s *githubSyncState) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode sync state: %w", err)
+	}
+	return filelock.With(path, func() error {
+		return filelock.WriteFile(path, append(data, '\n'), githubStatePermissions)
+	})
+}
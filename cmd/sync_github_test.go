@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/github"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+type fakeGitHubSyncClient struct {
+	existing  map[string][]notion.Page
+	createErr error
+	created   []notion.CreatePageRequest
+	updated   map[string]notion.UpdatePageRequest
+}
+
+func (f *fakeGitHubSyncClient) GetDataSource(context.Context, string) (notion.DataSource, error) {
+	return notion.DataSource{ID: "ds", Properties: map[string]notion.PropertyReference{
+		"Name":   {ID: "title", Name: "Name", Type: "title"},
+		"Issue":  {ID: "key", Name: "Issue", Type: "rich_text"},
+		"State":  {ID: "st", Name: "State", Type: "select"},
+		"Labels": {ID: "lb", Name: "Labels", Type: "multi_select"},
+	}}, nil
+}
+
+func (f *fakeGitHubSyncClient) QueryDataSource(
+	_ context.Context,
+	_ string,
+	req notion.QueryDataSourceRequest,
+) (notion.QueryDataSourceResponse, error) {
+	filter, _ := req.Filter.(map[string]any)
+	text, _ := filter["rich_text"].(map[string]any)
+	key, _ := text["equals"].(string)
+	return notion.QueryDataSourceResponse{Results: f.existing[key]}, nil
+}
+
+func (f *fakeGitHubSyncClient) CreatePage(_ context.Context, req notion.CreatePageRequest) (notion.Page, error) {
+	if f.createErr != nil {
+		return notion.Page{}, f.createErr
+	}
+	f.created = append(f.created, req)
+	return notion.Page{ID: "new"}, nil
+}
+
+func (f *fakeGitHubSyncClient) UpdatePage(_ context.Context, pageID string, req notion.UpdatePageRequest) (notion.Page, error) {
+	if f.updated == nil {
+		f.updated = map[string]notion.UpdatePageRequest{}
+	}
+	f.updated[pageID] = req
+	return notion.Page{ID: pageID}, nil
+}
+
+type fakeIssueSource struct {
+	issues []github.Issue
+	err    error
+	since  time.Time
+}
+
+func (f *fakeIssueSource) Issues(_ context.Context, _ string, since time.Time) iter.Seq2[github.Issue, error] {
+	f.since = since
+	return func(yield func(github.Issue, error) bool) {
+		for _, issue := range f.issues {
+			if !yield(issue, nil) {
+				return
+			}
+		}
+		if f.err != nil {
+			yield(github.Issue{}, f.err)
+		}
+	}
+}
+
+func TestSyncGitHubUpsertsIssuesByKey(t *testing.T) {
+	mapping := githubMap{Key: "Issue", Properties: map[string]string{"Name": "title", "State": "state", "Labels": "labels"}}
+	client := &fakeGitHubSyncClient{existing: map[string][]notion.Page{"acme/app#1": {{ID: "page-1"}}}}
+	first := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	source := &fakeIssueSource{issues: []github.Issue{
+		{Number: 1, Title: "Bug", State: "closed", UpdatedAt: first},
+		{Number: 2, Title: "Feature", State: "open", Labels: []github.Label{{Name: "ui"}}, UpdatedAt: first.Add(time.Hour)},
+	}}
+	opts := &syncGitHubOptions{repo: "acme/app", dataSourceID: "ds"}
+
+	since := first.Add(-time.Hour)
+	summary, newest, err := opts.sync(context.Background(), client, source, mapping, since, io.Discard)
+	if err != nil {
+		t.Fatalf("sync returned error: %v", err)
+	}
+	if summary.Rows != 2 || summary.Created != 1 || summary.Updated != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if !source.since.Equal(since) || !newest.Equal(first.Add(time.Hour)) {
+		t.Fatalf("since = %s, newest = %s", source.since, newest)
+	}
+	if state := client.updated["page-1"].Properties["State"]; state == nil {
+		t.Fatalf("update missing State: %#v", client.updated["page-1"].Properties)
+	}
+	created := client.created[0].Properties
+	key := created["Issue"].(map[string]any)["rich_text"].([]any)[0].(map[string]any)["text"].(map[string]any)["content"]
+	if key != "acme/app#2" {
+		t.Fatalf("created key = %v", key)
+	}
+	labels := created["Labels"].(map[string]any)["multi_select"].([]any)
+	if len(labels) != 1 {
+		t.Fatalf("created labels = %#v", labels)
+	}
+
+	// A failure mid-stream still reports how far the sync got.
+	source.err = errors.New("rate limited")
+	_, newest, err = opts.sync(context.Background(), client, source, mapping, time.Time{}, io.Discard)
+	if err == nil || !newest.Equal(first.Add(time.Hour)) {
+		t.Fatalf("expected error with progress, got %v, %s", err, newest)
+	}
+}
+
+func TestSyncGitHubContinuesPastFailedIssues(t *testing.T) {
+	mapping := githubMap{Key: "Issue", Properties: map[string]string{"Name": "title"}}
+	client := &fakeGitHubSyncClient{
+		existing: map[string][]notion.Page{
+			"acme/app#1": {{ID: "page-1"}},
+			"acme/app#3": {{ID: "page-3"}},
+		},
+		createErr: errors.New("body.properties.Body.rich_text[0].text.content.length should be ≤ 2000"),
+	}
+	first := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	source := &fakeIssueSource{issues: []github.Issue{
+		{Number: 1, Title: "Bug", UpdatedAt: first},
+		{Number: 2, Title: "New", UpdatedAt: first.Add(time.Hour)},
+		{Number: 3, Title: "Chore", UpdatedAt: first.Add(2 * time.Hour)},
+	}}
+	opts := &syncGitHubOptions{repo: "acme/app", dataSourceID: "ds"}
+
+	var log strings.Builder
+	summary, newest, err := opts.sync(context.Background(), client, source, mapping, time.Time{}, &log)
+	if err != nil {
+		t.Fatalf("sync returned error: %v", err)
+	}
+	if summary.Rows != 3 || summary.Updated != 2 || summary.Failed != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if _, ok := client.updated["page-3"]; !ok {
+		t.Fatalf("expected the issue after the failure to be synced")
+	}
+	if !newest.Equal(first) {
+		t.Fatalf("newest = %s, want the last issue before the failure so it is retried", newest)
+	}
+	if !strings.Contains(log.String(), "acme/app#2:") {
+		t.Fatalf("failure not logged: %q", log.String())
+	}
+}
+
+func TestGitHubMapValidate(t *testing.T) {
+	client := &fakeGitHubSyncClient{}
+	ds, _ := client.GetDataSource(context.Background(), "ds")
+	idx := schema.NewIndex(ds)
+
+	cases := map[string]githubMap{
+		"unknown key property": {Key: "Nope"},
+		"unknown property":     {Key: "Issue", Properties: map[string]string{"Nope": "title"}},
+		"unknown issue field":  {Key: "Issue", Properties: map[string]string{"Name": "reactions"}},
+		"key also mapped":      {Key: "Issue", Properties: map[string]string{"Issue": "title"}},
+	}
+	for name, mapping := range cases {
+		if _, err := mapping.validate(idx); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestGitHubSyncStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	state, err := loadGitHubSyncState(path)
+	if err != nil || len(state.Repos) != 0 {
+		t.Fatalf("load missing state = %+v, %v", state, err)
+	}
+	when := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	state.Repos["acme/app"] = when
+	if err := state.save(path); err != nil {
+		t.Fatalf("save returned error: %v", err)
+	}
+	loaded, err := loadGitHubSyncState(path)
+	if err != nil || !loaded.Repos["acme/app"].Equal(when) {
+		t.Fatalf("reloaded state = %+v, %v", loaded, err)
+	}
+
+	mapPath := filepath.Join(t.TempDir(), "map.yaml")
+	if err := os.WriteFile(mapPath, []byte("key: Issue\nproperties:\n  Name: title\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	mapping, err := loadGitHubMap(mapPath)
+	if err != nil || mapping.Key != "Issue" || mapping.Properties["Name"] != "title" {
+		t.Fatalf("loadGitHubMap = %+v, %v", mapping, err)
+	}
+	if err := os.WriteFile(mapPath, []byte("properties: {}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadGitHubMap(mapPath); err == nil || !strings.Contains(err.Error(), "key") {
+		t.Fatalf("expected missing key error, got %v", err)
+	}
+}
@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/mirror"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+// mirrorOverlap re-reads a little before the last sync because last_edited_time is only
+// precise to the minute.
+const mirrorOverlap = 2 * time.Minute
+
+// Metadata columns carry a leading underscore so they cannot collide with property names.
+const (
+	mirrorURLColumn        = "_url"
+	mirrorCreatedColumn    = "_created_time"
+	mirrorLastEditedColumn = "_last_edited_time"
+)
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type syncMirrorOptions struct {
+	dataSourceID string
+	dbPath       string
+	table        string
+	sqliteBinary string
+	serveAddr    string
+	interval     time.Duration
+	full         bool
+}
+
+// mirrorClient is the subset of the Notion client used to mirror a data source.
+type mirrorClient interface {
+	dataSourceQuerier
+	GetDataSource(ctx context.Context, dataSourceID string) (notion.DataSource, error)
+}
+
+// mirrorStore is the subset of mirror.DB used by a sync pass.
+type mirrorStore interface {
+	Ensure(ctx context.Context, t mirror.Table) error
+	SyncedUntil(ctx context.Context, table string) (time.Time, bool, error)
+	Apply(ctx context.Context, t mirror.Table, dataSourceID string, rows []mirror.Row, until time.Time, replace bool) error
+}
+
+func newSyncMirrorCmd(globals *globalOptions) *cobra.Command {
+	opts := &syncMirrorOptions{sqliteBinary: "sqlite3"}
+
+	cmd := &cobra.Command{
+		Use:   "mirror",
+		Short: "Mirror a data source into a local SQLite table, one column per property",
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Target Notion data source ID or URL")
+	cmd.Flags().StringVar(&opts.dbPath, "db", "", "SQLite database file to write")
+	cmd.Flags().StringVar(&opts.table, "table", "", "Table name (default: the data source name)")
+	cmd.Flags().StringVar(&opts.sqliteBinary, "sqlite3", opts.sqliteBinary, "sqlite3 shell used to write the database")
+	cmd.Flags().DurationVar(
+		&opts.interval,
+		"interval",
+		0,
+		"Keep running and pull changes at this interval (0 syncs once and exits)",
+	)
+	cmd.Flags().BoolVar(&opts.full, "full", false, "Re-pull every row and drop rows no longer in the data source")
+	cmd.Flags().StringVar(
+		&opts.serveAddr,
+		"serve",
+		"",
+		"Also serve the mirrored rows as a read-only JSON API on this address (host:port) until interrupted",
+	)
+
+	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
+	cobra.CheckErr(cmd.MarkFlagRequired("db"))
+
+	return cmd
+}
+
+func (opts *syncMirrorOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		if opts.interval < 0 {
+			return errors.New("--interval must not be negative")
+		}
+		db, err := mirror.Open(opts.dbPath, opts.sqliteBinary)
+		if err != nil {
+			return err
+		}
+		client, err := buildClient(globals)
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		var serveErr chan error
+		if opts.serveAddr != "" {
+			serveErr = make(chan error, 1)
+			srv := &mirrorServer{db: db, dataSourceID: opts.dataSourceID, log: cmd.ErrOrStderr()}
+			server := &http.Server{Addr: opts.serveAddr, Handler: srv.handler(), ReadHeaderTimeout: serverReadTimeout}
+			go func() {
+				if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					serveErr <- fmt.Errorf("mirror server: %w", err)
+				}
+			}()
+			defer func() {
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+				defer cancel()
+				_ = server.Shutdown(shutdownCtx)
+			}()
+			safeLog(cmd.ErrOrStderr(), "Serving the mirror on http://%s/rows", opts.serveAddr)
+		}
+
+		full := opts.full
+		for {
+			if err := opts.syncOnce(ctx, client, db, full, time.Now(), cmd.ErrOrStderr()); err != nil {
+				return err
+			}
+			if opts.interval == 0 && serveErr == nil {
+				return nil
+			}
+			full = false
+			// Without --interval a serving mirror keeps answering from the last sync.
+			var next <-chan time.Time
+			if opts.interval > 0 {
+				next = time.After(opts.interval)
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case err := <-serveErr:
+				return err
+			case <-next:
+			}
+		}
+	}
+}
+
+// syncOnce pulls every row on the first run (or with full set) and otherwise only rows
+// edited since the previous sync, then applies them in one transaction.
+func (opts *syncMirrorOptions) syncOnce(
+	ctx context.Context,
+	client mirrorClient,
+	store mirrorStore,
+	full bool,
+	now time.Time,
+	log io.Writer,
+) error {
+	ds, err := client.GetDataSource(ctx, opts.dataSourceID)
+	if err != nil {
+		return fmt.Errorf("get data source: %w", err)
+	}
+	idx := schema.NewIndex(ds)
+	props := idx.PropertyNames()
+	table := mirrorTable(opts.tableName(ds), idx, props)
+	if err := store.Ensure(ctx, table); err != nil {
+		return err
+	}
+
+	since, synced, err := store.SyncedUntil(ctx, table.Name)
+	if err != nil {
+		return err
+	}
+	until := now.UTC().Truncate(time.Second)
+
+	var pages []notion.Page
+	if full || !synced {
+		resp, err := executeDataSourceQuery(ctx, client, opts.dataSourceID, notion.QueryDataSourceRequest{}, true, 0)
+		if err != nil {
+			return err
+		}
+		pages, full = resp.Results, true
+	} else {
+		pages, err = fetchChanges(ctx, client, opts.dataSourceID, since.Add(-mirrorOverlap), until, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	rows := make([]mirror.Row, 0, len(pages))
+	for _, page := range pages {
+		rows = append(rows, mirrorRow(page, idx, props))
+	}
+	if err := store.Apply(ctx, table, opts.dataSourceID, rows, until, full); err != nil {
+		return err
+	}
+
+	mode := "incremental"
+	if full {
+		mode = "full"
+	}
+	safeLog(log, "%s sync of %q: %d rows written", mode, table.Name, len(rows))
+	return nil
+}
+
+func (opts *syncMirrorOptions) tableName(ds notion.DataSource) string {
+	if opts.table != "" {
+		return opts.table
+	}
+	if name := strings.TrimSpace(ds.Name); name != "" {
+		return name
+	}
+	return "notion_" + strings.ReplaceAll(ds.ID, "-", "")
+}
+
+// mirrorTable maps each of props to a column named after it. Numbers are stored as REAL,
+// checkboxes as INTEGER 0/1, and everything else as the text ds query shows.
+func mirrorTable(name string, idx *schema.Index, props []string) mirror.Table {
+	table := mirror.Table{Name: name, Columns: []mirror.Column{
+		{Name: mirrorURLColumn, Type: mirror.TypeText},
+		{Name: mirrorCreatedColumn, Type: mirror.TypeText},
+		{Name: mirrorLastEditedColumn, Type: mirror.TypeText},
+	}}
+	for _, prop := range props {
+		ref, _ := idx.ReferenceForName(prop)
+		table.Columns = append(table.Columns, mirror.Column{Name: mirrorColumnName(prop), Type: mirrorColumnType(ref.Type)})
+	}
+	return table
+}
+
+// mirrorColumnName keeps property names as-is except for the reserved key column.
+func mirrorColumnName(property string) string {
+	if strings.EqualFold(property, mirror.KeyColumn) {
+		return property + "_property"
+	}
+	return property
+}
+
+func mirrorColumnType(propertyType string) string {
+	switch propertyType {
+	case "number":
+		return mirror.TypeReal
+	case "checkbox":
+		return mirror.TypeInteger
+	default:
+		return mirror.TypeText
+	}
+}
+
+func mirrorRow(page notion.Page, idx *schema.Index, props []string) mirror.Row {
+	row := mirror.Row{
+		mirror.KeyColumn:       page.ID,
+		mirrorURLColumn:        page.URL,
+		mirrorCreatedColumn:    page.CreatedTime.UTC().Format(time.RFC3339),
+		mirrorLastEditedColumn: page.LastEditedTime.UTC().Format(time.RFC3339),
+	}
+	for _, prop := range props {
+		ref, _ := idx.ReferenceForName(prop)
+		value, ok := page.Properties[ref.Name]
+		if !ok {
+			continue
+		}
+		row[mirrorColumnName(prop)] = mirrorValue(value)
+	}
+	return row
+}
+
+func mirrorValue(value notion.PropertyValue) any {
+	switch value.Type {
+	case "number":
+		if value.Number == nil {
+			return nil
+		}
+		return *value.Number
+	case "checkbox":
+		return value.Checkbox != nil && *value.Checkbox
+	}
+	if text := summarizeProperty(value); text != "" && text != value.Type {
+		return text
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/mirror"
+	"github.com/yourorg/notionctl/internal/sqlquery"
+)
+
+const maxMirrorRowLimit = 1000
+
+// mirrorReader is the subset of mirror.DB used to answer HTTP queries.
+type mirrorReader interface {
+	Mirrored(ctx context.Context, dataSourceID string) (string, time.Time, bool, error)
+	Columns(ctx context.Context, table string) ([]string, error)
+	Rows(ctx context.Context, table string, order ...mirror.Order) ([]mirror.Row, error)
+}
+
+// mirrorServer answers read-only queries from the mirror database alone; it never calls
+// Notion, so dashboards can poll it freely.
+type mirrorServer struct {
+	db           mirrorReader
+	dataSourceID string
+	log          io.Writer
+}
+
+// mirrorHealth is the response of GET /healthz.
+type mirrorHealth struct {
+	SyncedUntil *time.Time `json:"synced_until,omitempty"`
+	Status      string     `json:"status"`
+	Table       string     `json:"table,omitempty"`
+}
+
+// mirrorPageColumns maps the page fields ds sql accepts onto the mirror's metadata columns.
+var mirrorPageColumns = map[string]string{
+	"url":              mirrorURLColumn,
+	"created_time":     mirrorCreatedColumn,
+	"last_edited_time": mirrorLastEditedColumn,
+}
+
+func (s *mirrorServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rows", s.serveRows)
+	mux.HandleFunc("GET /healthz", s.serveHealth)
+	return mux
+}
+
+func (s *mirrorServer) serveHealth(w http.ResponseWriter, r *http.Request) {
+	table, until, ok, err := s.db.Mirrored(r.Context(), s.dataSourceID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		respondJSON(w, http.StatusServiceUnavailable, mirrorHealth{Status: "not synced"})
+		return
+	}
+	respondJSON(w, http.StatusOK, mirrorHealth{Status: "ok", Table: table, SyncedUntil: &until})
+}
+
+// serveRows accepts ?where= (a ds sql WHERE condition), ?sort= (the --sort shorthand),
+// ?limit= (default 100), and ?cursor= from a previous response, and returns rows in the
+// serve api shape.
+func (s *mirrorServer) serveRows(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	table, until, ok, err := s.db.Mirrored(ctx, s.dataSourceID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		http.Error(w, "mirror has not been synced yet", http.StatusServiceUnavailable)
+		return
+	}
+	columns, err := s.db.Columns(ctx, table)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	query, err := parseMirrorQuery(r, columns)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rows, err := s.db.Rows(ctx, table, query.order...)
+	if err != nil {
+		safeLog(s.log, "Read mirror: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	out := apiRows{Rows: []apiRow{}}
+	skipped := 0
+	for _, row := range rows {
+		if query.where != nil && !query.where.Eval(mirrorSQLRow(row, query.resolve)) {
+			continue
+		}
+		if skipped < query.offset {
+			skipped++
+			continue
+		}
+		if len(out.Rows) == query.limit {
+			out.HasMore = true
+			out.NextCursor = strconv.Itoa(query.offset + query.limit)
+			break
+		}
+		out.Rows = append(out.Rows, mirrorAPIRow(row, columns))
+	}
+	w.Header().Set("X-Synced-Until", until.UTC().Format(time.RFC3339))
+	respondJSON(w, http.StatusOK, out)
+}
+
+type mirrorQuery struct {
+	where   sqlquery.Expr
+	resolve func(name string) (string, bool)
+	order   []mirror.Order
+	limit   int
+	offset  int
+}
+
+func parseMirrorQuery(r *http.Request, columns []string) (mirrorQuery, error) {
+	params := r.URL.Query()
+	query := mirrorQuery{limit: defaultAPIRowLimit, resolve: mirrorColumnResolver(columns)}
+
+	if expr := params.Get("where"); expr != "" {
+		where, err := sqlquery.ParseCondition(expr)
+		if err != nil {
+			return query, fmt.Errorf("parse where: %w", err)
+		}
+		for _, column := range where.Columns() {
+			if _, ok := query.resolve(column); !ok {
+				return query, fmt.Errorf("parse where: unknown column %q", column)
+			}
+		}
+		query.where = where
+	}
+	for item := range strings.SplitSeq(params.Get("sort"), ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		name, dir := splitSortItem(item)
+		direction, err := sortDirection(dir)
+		if err != nil {
+			return query, fmt.Errorf("parse sort: %s: %w", name, err)
+		}
+		column, ok := query.resolve(name)
+		if !ok {
+			return query, fmt.Errorf("parse sort: unknown column %q", name)
+		}
+		query.order = append(query.order, mirror.Order{Column: column, Desc: direction == "descending"})
+	}
+	if raw := params.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > maxMirrorRowLimit {
+			return query, fmt.Errorf("limit must be between 1 and %d", maxMirrorRowLimit)
+		}
+		query.limit = limit
+	}
+	if raw := params.Get("cursor"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return query, fmt.Errorf("invalid cursor %q", raw)
+		}
+		query.offset = offset
+	}
+	return query, nil
+}
+
+// mirrorColumnResolver matches names to columns ignoring case, accepting url, created_time,
+// and last_edited_time for the metadata columns unless a property has that name.
+func mirrorColumnResolver(columns []string) func(string) (string, bool) {
+	byName := make(map[string]string, len(columns)+len(mirrorPageColumns))
+	for name, column := range mirrorPageColumns {
+		if slices.Contains(columns, column) {
+			byName[name] = column
+		}
+	}
+	for _, column := range columns {
+		byName[strings.ToLower(column)] = column
+	}
+	return func(name string) (string, bool) {
+		column, ok := byName[strings.ToLower(strings.TrimSpace(name))]
+		return column, ok
+	}
+}
+
+func mirrorSQLRow(row mirror.Row, resolve func(string) (string, bool)) sqlquery.Row {
+	return func(name string) []string {
+		column, _ := resolve(name)
+		switch v := row[column].(type) {
+		case nil:
+			return nil
+		case string:
+			return nonEmpty(v)
+		case float64:
+			return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+		default:
+			return []string{fmt.Sprint(v)}
+		}
+	}
+}
+
+// mirrorAPIRow shapes a mirror row like serve api: metadata at the top level and every
+// property column under properties.
+func mirrorAPIRow(row mirror.Row, columns []string) apiRow {
+	out := apiRow{Properties: map[string]any{}}
+	out.ID, _ = row[mirror.KeyColumn].(string)
+	out.URL, _ = row[mirrorURLColumn].(string)
+	if created, ok := row[mirrorCreatedColumn].(string); ok {
+		out.CreatedTime, _ = time.Parse(time.RFC3339, created)
+	}
+	if edited, ok := row[mirrorLastEditedColumn].(string); ok {
+		out.LastEditedTime, _ = time.Parse(time.RFC3339, edited)
+	}
+	for _, column := range columns {
+		switch column {
+		case mirror.KeyColumn, mirrorURLColumn, mirrorCreatedColumn, mirrorLastEditedColumn:
+			continue
+		}
+		out.Properties[column] = row[column]
+	}
+	return out
+}
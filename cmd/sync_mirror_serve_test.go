@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/mirror"
+)
+
+type fakeMirrorReader struct {
+	synced time.Time
+	rows   []mirror.Row
+	order  []mirror.Order
+	table  string
+}
+
+func (f *fakeMirrorReader) Mirrored(_ context.Context, dataSourceID string) (string, time.Time, bool, error) {
+	if f.table == "" || dataSourceID != "ds" {
+		return "", time.Time{}, false, nil
+	}
+	return f.table, f.synced, true, nil
+}
+
+func (f *fakeMirrorReader) Columns(context.Context, string) ([]string, error) {
+	return []string{"id", "_url", "_created_time", "_last_edited_time", "Name", "Status", "Points"}, nil
+}
+
+func (f *fakeMirrorReader) Rows(_ context.Context, table string, order ...mirror.Order) ([]mirror.Row, error) {
+	if table != f.table {
+		return nil, errors.New("no such table")
+	}
+	f.order = order
+	return f.rows, nil
+}
+
+func TestMirrorServerRows(t *testing.T) {
+	t.Parallel()
+
+	synced := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	reader := &fakeMirrorReader{table: "Tasks", synced: synced, rows: []mirror.Row{
+		{"id": "p1", "_url": "https://notion.so/p1", "_last_edited_time": "2025-06-01T10:00:00Z", "Name": "Alpha", "Status": "Done", "Points": 5.0},
+		{"id": "p2", "Name": "Beta", "Status": "Open", "Points": 2.0},
+		{"id": "p3", "Name": "Gamma", "Status": "Done", "Points": 8.0},
+		{"id": "p4", "Name": "Delta", "Status": "Done", "Points": nil},
+	}}
+	srv := httptest.NewServer((&mirrorServer{db: reader, dataSourceID: "ds", log: io.Discard}).handler())
+	defer srv.Close()
+
+	get := func(query url.Values) (*http.Response, apiRows) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + "/rows?" + query.Encode())
+		if err != nil {
+			t.Fatalf("GET /rows: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		var out apiRows
+		if resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+				t.Fatalf("decode rows: %v", err)
+			}
+		}
+		return resp, out
+	}
+
+	resp, out := get(url.Values{"where": {`status = "Done" AND Points >= 5`}, "sort": {"Points:desc,url"}, "limit": {"1"}})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	if len(out.Rows) != 1 || out.Rows[0].ID != "p1" || !out.HasMore || out.NextCursor != "1" {
+		t.Fatalf("first page = %+v", out)
+	}
+	if out.Rows[0].URL != "https://notion.so/p1" || out.Rows[0].Properties["Points"] != 5.0 {
+		t.Fatalf("unexpected row shape: %+v", out.Rows[0])
+	}
+	if _, ok := out.Rows[0].Properties["_url"]; ok {
+		t.Fatalf("metadata column leaked into properties: %+v", out.Rows[0].Properties)
+	}
+	if got := resp.Header.Get("X-Synced-Until"); got != "2025-06-01T12:00:00Z" {
+		t.Fatalf("X-Synced-Until = %q", got)
+	}
+	if len(reader.order) != 2 || reader.order[0] != (mirror.Order{Column: "Points", Desc: true}) || reader.order[1].Column != "_url" {
+		t.Fatalf("order = %+v", reader.order)
+	}
+
+	_, out = get(url.Values{"where": {`status = "Done" AND Points >= 5`}, "limit": {"1"}, "cursor": {"1"}})
+	if len(out.Rows) != 1 || out.Rows[0].ID != "p3" || out.HasMore {
+		t.Fatalf("second page = %+v", out)
+	}
+
+	_, out = get(url.Values{"where": {"Points IS NULL"}})
+	if len(out.Rows) != 1 || out.Rows[0].ID != "p4" {
+		t.Fatalf("IS NULL rows = %+v", out)
+	}
+
+	for _, bad := range []url.Values{
+		{"where": {"Nope = 1"}},
+		{"where": {"Name ="}},
+		{"sort": {"Nope"}},
+		{"limit": {"0"}},
+		{"cursor": {"x"}},
+	} {
+		if resp, _ := get(bad); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%v: status = %d, want 400", bad, resp.StatusCode)
+		}
+	}
+}
+
+func TestMirrorServerBeforeFirstSync(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer((&mirrorServer{db: &fakeMirrorReader{}, dataSourceID: "ds", log: io.Discard}).handler())
+	defer srv.Close()
+
+	for _, path := range []string{"/rows", "/healthz"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("GET %s status = %d, want 503", path, resp.StatusCode)
+		}
+	}
+}
+
+func TestMirrorServerHealth(t *testing.T) {
+	t.Parallel()
+
+	reader := &fakeMirrorReader{table: "Tasks", synced: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)}
+	rec := httptest.NewRecorder()
+	(&mirrorServer{db: reader, dataSourceID: "ds"}).handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"table":"Tasks"`) ||
+		!strings.Contains(rec.Body.String(), "2025-06-01T12:00:00Z") {
+		t.Fatalf("healthz = %d %s", rec.Code, rec.Body.String())
+	}
+}
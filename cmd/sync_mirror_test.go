@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/mirror"
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+type fakeMirrorClient struct {
+	ds      notion.DataSource
+	pages   []notion.Page
+	filters []any
+}
+
+func (f *fakeMirrorClient) GetDataSource(context.Context, string) (notion.DataSource, error) {
+	return f.ds, nil
+}
+
+func (f *fakeMirrorClient) QueryDataSource(
+	_ context.Context,
+	_ string,
+	req notion.QueryDataSourceRequest,
+) (notion.QueryDataSourceResponse, error) {
+	f.filters = append(f.filters, req.Filter)
+	return notion.QueryDataSourceResponse{Results: f.pages}, nil
+}
+
+type fakeMirrorStore struct {
+	synced  time.Time
+	table   mirror.Table
+	rows    []mirror.Row
+	replace bool
+}
+
+func (s *fakeMirrorStore) Ensure(_ context.Context, t mirror.Table) error {
+	s.table = t
+	return nil
+}
+
+func (s *fakeMirrorStore) SyncedUntil(context.Context, string) (time.Time, bool, error) {
+	return s.synced, !s.synced.IsZero(), nil
+}
+
+func (s *fakeMirrorStore) Apply(
+	_ context.Context,
+	_ mirror.Table,
+	_ string,
+	rows []mirror.Row,
+	until time.Time,
+	replace bool,
+) error {
+	s.rows, s.synced, s.replace = rows, until, replace
+	return nil
+}
+
+func TestSyncMirrorFullThenIncremental(t *testing.T) {
+	points, done := 3.0, true
+	client := &fakeMirrorClient{
+		ds: notion.DataSource{ID: "ds", Name: "Tasks", Properties: map[string]notion.PropertyReference{
+			"Name":   {ID: "title", Name: "Name", Type: "title"},
+			"Points": {ID: "pts", Name: "Points", Type: "number"},
+			"Done":   {ID: "done", Name: "Done", Type: "checkbox"},
+			"ID":     {ID: "uid", Name: "ID", Type: "rich_text"},
+		}},
+		pages: []notion.Page{{ID: "p1", Properties: map[string]notion.PropertyValue{
+			"Name":   {Type: "title", Title: []notion.RichText{{PlainText: "Ship"}}},
+			"Points": {Type: "number", Number: &points},
+			"Done":   {Type: "checkbox", Checkbox: &done},
+			"ID":     {Type: "rich_text"},
+		}}},
+	}
+	store := &fakeMirrorStore{}
+	opts := &syncMirrorOptions{dataSourceID: "ds"}
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := opts.syncOnce(context.Background(), client, store, false, now, nil); err != nil {
+		t.Fatalf("first sync returned error: %v", err)
+	}
+	if !store.replace || client.filters[0] != nil || store.table.Name != "Tasks" {
+		t.Fatalf("first sync should be a full pull: replace=%v filter=%#v table=%q",
+			store.replace, client.filters[0], store.table.Name)
+	}
+	row := store.rows[0]
+	if row["Name"] != "Ship" || row["Points"] != 3.0 || row["Done"] != true || row["ID_property"] != nil {
+		t.Fatalf("unexpected row: %#v", row)
+	}
+
+	later := now.Add(time.Hour)
+	if err := opts.syncOnce(context.Background(), client, store, false, later, nil); err != nil {
+		t.Fatalf("second sync returned error: %v", err)
+	}
+	filter, _ := client.filters[1].(map[string]any)
+	window, _ := filter["last_edited_time"].(map[string]any)
+	if store.replace || window["on_or_after"] != now.Add(-mirrorOverlap).Format(time.RFC3339) {
+		t.Fatalf("second sync should be incremental from the last sync: replace=%v filter=%#v", store.replace, filter)
+	}
+	if !store.synced.Equal(later) {
+		t.Fatalf("sync state = %v, want %v", store.synced, later)
+	}
+}
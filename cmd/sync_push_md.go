@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/markdown"
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/props"
+	"github.com/yourorg/notionctl/internal/render"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+// Conflict policies for --on-conflict, applied when a file and its page both changed since
+// the last sync.
+const (
+	conflictSkip   = "skip"
+	conflictLocal  = "local"
+	conflictRemote = "remote"
+)
+
+// markdownSyncClient adds the writes needed to push local edits back to Notion.
+type markdownSyncClient interface {
+	markdownExportClient
+	RetrievePage(ctx context.Context, pageID string) (notion.Page, error)
+	UpdatePage(ctx context.Context, pageID string, req notion.UpdatePageRequest) (notion.Page, error)
+	AppendBlockChildren(ctx context.Context, blockID string, blocks []notion.Block) ([]notion.Block, error)
+	DeleteBlock(ctx context.Context, blockID string) error
+}
+
+// pushableTypes are the property types whose frontmatter values are written back. People
+// are exported as names, which the API cannot resolve, so they stay read-only. Relations
+// Notion truncated to 25 pages are exported incomplete and are never written back.
+var pushableTypes = map[string]bool{
+	"rich_text":    true,
+	"number":       true,
+	"checkbox":     true,
+	"select":       true,
+	"status":       true,
+	"multi_select": true,
+	"date":         true,
+	"url":          true,
+	"email":        true,
+	"phone_number": true,
+	relationType:   true,
+}
+
+// errUnrepresentable marks a page whose content would lose blocks if it were replaced with
+// the converted Markdown body.
+var errUnrepresentable = errors.New("page has blocks Markdown cannot represent")
+
+// pushLocalEdits uploads every exported file whose content hash no longer matches the sync
+// state. It returns the pages left in conflict or skipped, which the pull that follows must
+// not touch.
+func (opts *syncExportMDOptions) pushLocalEdits(
+	ctx context.Context,
+	client markdownSyncClient,
+	state *markdownSyncState,
+	idx *schema.Index,
+	log io.Writer,
+) (map[string]bool, error) {
+	ids := render.SortedKeys(state.Pages)
+
+	conflicts := map[string]bool{}
+	pushed := 0
+	for _, id := range ids {
+		entry := state.Pages[id]
+		data, err := os.ReadFile(filepath.Join(opts.dir, entry.Path)) // #nosec G304 -- file inside the export directory
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // deleting a file never deletes the page
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", entry.Path, err)
+		}
+		if contentHash(data) == entry.Hash {
+			continue
+		}
+
+		page, err := client.RetrievePage(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("retrieve page for %s: %w", entry.Path, err)
+		}
+		if remoteChanged(page, entry) {
+			switch opts.onConflict {
+			case conflictLocal:
+				safeLog(log, "conflict: %s changed locally and in Notion; pushing the local version", entry.Path)
+			case conflictRemote:
+				safeLog(log, "conflict: %s changed locally and in Notion; keeping the Notion version", entry.Path)
+				if err := refreshMarkdownPage(ctx, client, state, opts.dir, idx, id); err != nil {
+					return nil, err
+				}
+				continue
+			default:
+				safeLog(log, "conflict: %s changed locally and in Notion since the last sync; skipping "+
+					"(rerun with --on-conflict local or remote)", entry.Path)
+				conflicts[id] = true
+				continue
+			}
+		}
+
+		err = pushMarkdownPage(ctx, client, idx, page, data, opts.force)
+		if errors.Is(err, errUnrepresentable) {
+			safeLog(log, "%s: %v; skipping (rerun with --force to replace them)", entry.Path, err)
+			conflicts[id] = true
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("push %s: %w", entry.Path, err)
+		}
+		// Re-export so the file and its hash reflect what Notion stored.
+		if err := refreshMarkdownPage(ctx, client, state, opts.dir, idx, id); err != nil {
+			return nil, err
+		}
+		pushed++
+	}
+	if pushed > 0 {
+		safeLog(log, "pushed %d edited files from %s", pushed, opts.dir)
+	}
+	return conflicts, nil
+}
+
+// remoteChanged reports whether page was edited in Notion since entry was exported. Edit
+// times only have minute precision, so the writable properties are compared too. Entries
+// written before properties were hashed fall back to the edit time alone.
+func remoteChanged(page notion.Page, entry markdownSyncEntry) bool {
+	if !page.LastEditedTime.Equal(entry.LastEditedTime) {
+		return true
+	}
+	return entry.PropertiesHash != "" && propertiesHash(page) != entry.PropertiesHash
+}
+
+// pushMarkdownPage writes changed frontmatter values as property updates and, when the body
+// differs from the page's current content, replaces the content with the converted body.
+// Unless force is set, nothing is written when that would delete blocks the body cannot
+// represent.
+func pushMarkdownPage(
+	ctx context.Context,
+	client markdownSyncClient,
+	idx *schema.Index,
+	page notion.Page,
+	data []byte,
+	force bool,
+) error {
+	fields, body, err := markdown.Parse(data)
+	if err != nil {
+		return err
+	}
+	updates, err := frontmatterUpdates(page, idx, fields)
+	if err != nil {
+		return err
+	}
+
+	current, err := fetchBlockTree(ctx, client, page.ID)
+	if err != nil {
+		return err
+	}
+	replace := strings.TrimSpace(markdown.FromBlocks(current)) != strings.TrimSpace(body)
+	if replace && !force {
+		if lost := markdown.Unsupported(replacedBlocks(current)); len(lost) > 0 {
+			return fmt.Errorf("%w (%s)", errUnrepresentable, strings.Join(lost, ", "))
+		}
+	}
+
+	if len(updates) > 0 {
+		if _, err := client.UpdatePage(ctx, page.ID, notion.UpdatePageRequest{Properties: updates}); err != nil {
+			return fmt.Errorf("update properties: %w", err)
+		}
+	}
+	if !replace {
+		return nil
+	}
+	blocks, err := markdown.ToBlocks(body)
+	if err != nil {
+		return err
+	}
+	return replacePageContent(ctx, client, page.ID, current, blocks)
+}
+
+// replacePageContent appends the new blocks before deleting the old ones, so a failure part
+// way through leaves duplicated content rather than lost content.
+func replacePageContent(
+	ctx context.Context,
+	client markdownSyncClient,
+	pageID string,
+	current []notion.Block,
+	blocks []notion.Block,
+) error {
+	if err := appendBlocks(ctx, client, pageID, blocks); err != nil {
+		return err
+	}
+	for _, block := range replacedBlocks(current) {
+		if err := client.DeleteBlock(ctx, block.ID); err != nil {
+			return fmt.Errorf("delete block %s: %w", block.ID, err)
+		}
+	}
+	return nil
+}
+
+// replacedBlocks returns the top-level blocks a push replaces. Child pages and databases are
+// kept.
+func replacedBlocks(current []notion.Block) []notion.Block {
+	var blocks []notion.Block
+	for _, block := range current {
+		if block.ID == "" || block.Type == "child_page" || block.Type == "child_database" {
+			continue
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// refreshMarkdownPage re-reads one page and rewrites its file.
+func refreshMarkdownPage(
+	ctx context.Context,
+	client markdownSyncClient,
+	state *markdownSyncState,
+	dir string,
+	idx *schema.Index,
+	pageID string,
+) error {
+	page, err := client.RetrievePage(ctx, pageID)
+	if err != nil {
+		return fmt.Errorf("retrieve page %s: %w", pageID, err)
+	}
+	blocks, err := fetchBlockTree(ctx, client, pageID)
+	if err != nil {
+		return fmt.Errorf("read page %s: %w", pageID, err)
+	}
+	doc, err := markdownDocument(page, idx, blocks)
+	if err != nil {
+		return err
+	}
+	_, err = state.write(dir, page, doc)
+	return err
+}
+
+// frontmatterUpdates compares edited frontmatter against what export would write for page
+// and returns property payloads for the keys that changed. Removed keys are left alone.
+func frontmatterUpdates(page notion.Page, idx *schema.Index, fields map[string]any) (map[string]any, error) {
+	updates := map[string]any{}
+	if len(fields) == 0 {
+		return updates, nil
+	}
+	for _, name := range idx.PropertyNames() {
+		ref, _ := idx.ReferenceForName(name)
+		key := frontmatterKey(name)
+		value := page.Properties[ref.Name]
+		current := frontmatterValue(value)
+		if ref.Type == "title" {
+			key, current = frontmatterTitle, pageTitle(page)
+		} else if !pushableTypes[ref.Type] || value.HasMore {
+			continue
+		}
+
+		local, ok := fields[key]
+		if !ok || sameFrontmatterValue(local, current) {
+			continue
+		}
+		payload, err := frontmatterPayload(ref, local)
+		if err != nil {
+			return nil, err
+		}
+		updates[ref.Name] = payload
+	}
+	return updates, nil
+}
+
+func frontmatterPayload(ref notion.PropertyReference, value any) (map[string]any, error) {
+	value = normalizeFrontmatter(value)
+	if span, ok := value.(map[string]any); ok && ref.Type == "date" {
+		return map[string]any{"date": span}, nil
+	}
+	return props.FromJSON(ref, value)
+}
+
+func sameFrontmatterValue(a, b any) bool {
+	left, errA := json.Marshal(normalizeFrontmatter(a))
+	right, errB := json.Marshal(normalizeFrontmatter(b))
+	return errA == nil && errB == nil && string(left) == string(right)
+}
+
+// normalizeFrontmatter maps YAML-decoded values onto the JSON shapes props.FromJSON expects:
+// integers become float64 and unquoted dates become strings.
+func normalizeFrontmatter(value any) any {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case time.Time:
+		if v.Equal(v.Truncate(24 * time.Hour)) {
+			return v.Format(time.DateOnly)
+		}
+		return v.Format(time.RFC3339)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = normalizeFrontmatter(item)
+		}
+		return out
+	case []string:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = item
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = normalizeFrontmatter(item)
+		}
+		return out
+	case map[string]string:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = item
+		}
+		return out
+	default:
+		return value
+	}
+}
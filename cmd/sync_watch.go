@@ -22,13 +22,27 @@ type syncWatchOptions struct { //nolint:govet // field order favors readability
 	initialSince time.Time
 	pollInterval time.Duration
 	lookback     time.Duration
+	execTimeout  time.Duration
 
 	dataSourceID  string
 	listenAddr    string
 	callbackPath  string
 	webhookSecret string
-
-	flags uint8
+	execCommand   string
+	forwardURL    string
+	forwardBody   string
+	storePath     string
+	outputFile    string
+	rotateSize    int64
+	rotateDaily   bool
+
+	out       io.Writer
+	hook      *execHook
+	forward   *forwardHook
+	store     *deliveryStore
+	metrics   *watchMetrics
+	dashboard *watchDashboard
+	flags     uint8
 }
 
 func (opts *syncWatchOptions) setDisableWebhook(enabled bool) {
@@ -107,21 +121,24 @@ func newSyncWatchCmd(globals *globalOptions) *cobra.Command {
 		callbackPath: defaultCallback,
 		pollInterval: defaultPollInterval,
 		lookback:     defaultLookbackWindow,
+		execTimeout:  defaultExecTimeout,
 	}
 
 	var (
 		sinceArg     string
 		disableFlag  bool
 		suppressFlag bool
+		metricsFlag  bool
+		tuiFlag      bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "watch",
 		Short: "Watch Notion data source changes via webhooks with polling fallback",
-		RunE:  opts.run(globals, &sinceArg, &disableFlag, &suppressFlag),
+		RunE:  opts.run(globals, &sinceArg, &disableFlag, &suppressFlag, &metricsFlag, &tuiFlag),
 	}
 
-	cmd.Flags().StringVar(&opts.dataSourceID, "data-source-id", "", "Target Notion data source ID")
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Target Notion data source ID or URL")
 	cmd.Flags().StringVar(
 		&opts.listenAddr,
 		"listen",
@@ -170,6 +187,65 @@ func newSyncWatchCmd(globals *globalOptions) *cobra.Command {
 		false,
 		"Suppress poll output when no changes are detected",
 	)
+	cmd.Flags().StringVar(
+		&opts.storePath,
+		"store",
+		"",
+		"Append every verified webhook delivery to this JSON Lines file before acknowledging it",
+	)
+	cmd.Flags().StringVar(
+		&opts.execCommand,
+		"exec",
+		"",
+		"Shell command to run for every change event (event JSON on stdin, NOTION_* env vars)",
+	)
+	cmd.Flags().DurationVar(
+		&opts.execTimeout,
+		"exec-timeout",
+		opts.execTimeout,
+		"Maximum run time for each --exec command (0 disables the limit)",
+	)
+	cmd.Flags().StringVar(
+		&opts.forwardURL,
+		"forward-url",
+		"",
+		"POST every change event to this webhook (slack=URL for Slack incoming webhooks, a bare URL for generic JSON)",
+	)
+	cmd.Flags().StringVar(
+		&opts.forwardBody,
+		"forward-template",
+		"",
+		"Go template for the --forward-url message body (default: the page title)",
+	)
+	cmd.Flags().StringVar(
+		&opts.outputFile,
+		"output-file",
+		"",
+		"Append events to this JSON Lines file instead of stdout",
+	)
+	cmd.Flags().Var(
+		newByteSizeValue(&opts.rotateSize),
+		"rotate-size",
+		"Rotate --output-file once it would grow past this size (e.g. 10MB)",
+	)
+	cmd.Flags().BoolVar(
+		&opts.rotateDaily,
+		"rotate-daily",
+		false,
+		"Rotate --output-file when the local date changes",
+	)
+	cmd.Flags().BoolVar(
+		&metricsFlag,
+		"metrics",
+		false,
+		"Serve Prometheus counters at /metrics on --listen (also with --no-webhook)",
+	)
+	cmd.Flags().BoolVar(
+		&tuiFlag,
+		"tui",
+		false,
+		"Show a live dashboard of changes, webhook counts, the next poll, and errors instead of JSON lines",
+	)
 
 	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
 
@@ -181,6 +257,8 @@ func (opts *syncWatchOptions) run(
 	sinceArg *string,
 	disableFlag *bool,
 	suppressFlag *bool,
+	metricsFlag *bool,
+	tuiFlag *bool,
 ) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, _ []string) error {
 		if err := opts.prepare(*sinceArg); err != nil {
@@ -188,12 +266,53 @@ func (opts *syncWatchOptions) run(
 		}
 		opts.setDisableWebhook(*disableFlag)
 		opts.setSuppressEmpty(*suppressFlag)
+		if *tuiFlag {
+			stop, err := opts.startDashboard(cmd)
+			if err != nil {
+				return err
+			}
+			defer stop()
+		}
+		if opts.storePath != "" {
+			opts.store = &deliveryStore{path: opts.storePath}
+		}
+		if opts.execCommand != "" {
+			opts.hook = &execHook{
+				log:          cmd.ErrOrStderr(),
+				command:      opts.execCommand,
+				dataSourceID: opts.dataSourceID,
+				timeout:      opts.execTimeout,
+			}
+		}
+
+		if opts.forwardURL != "" {
+			forward, err := newForwardHook(opts.forwardURL, opts.forwardBody, cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			opts.forward = forward
+		} else if opts.forwardBody != "" {
+			return errors.New("--forward-template requires --forward-url")
+		}
+
+		if *metricsFlag {
+			opts.metrics = newWatchMetrics(cmd)
+		}
 
-		client, err := buildClient(globals.profile)
+		client, err := buildClient(globals)
 		if err != nil {
 			return err
 		}
 
+		if opts.outputFile != "" {
+			out, err := newRotatingWriter(opts.outputFile, opts.rotateSize, opts.rotateDaily)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = out.Close() }()
+			opts.out = out
+		}
+
 		rt := newWatchRuntime(cmd, opts, client)
 		return rt.run()
 	}
@@ -215,7 +334,11 @@ type watchRuntime struct {
 }
 
 func newWatchRuntime(cmd *cobra.Command, opts *syncWatchOptions, client changeClient) *watchRuntime {
-	enc := json.NewEncoder(cmd.OutOrStdout())
+	out := opts.out
+	if out == nil {
+		out = cmd.OutOrStdout()
+	}
+	enc := json.NewEncoder(out)
 	enc.SetEscapeHTML(false)
 
 	return &watchRuntime{
@@ -243,12 +366,13 @@ func (rt *watchRuntime) run() error {
 
 	rt.ticker = time.NewTicker(rt.opts.pollInterval)
 	defer rt.ticker.Stop()
+	rt.opts.dashboard.scheduled(time.Now().Add(rt.opts.pollInterval))
 
 	return rt.loop(ctx)
 }
 
 func (rt *watchRuntime) startServer(ctx context.Context) error {
-	if rt.opts.disableWebhookEnabled() {
+	if rt.opts.disableWebhookEnabled() && rt.opts.metrics == nil {
 		return nil
 	}
 	server, err := rt.opts.startWebhookServer(ctx, rt.cmd, rt.deliveries, rt.errCh)
@@ -282,7 +406,7 @@ func (rt *watchRuntime) bootstrap(ctx context.Context) error {
 		initialUntil,
 		false,
 	); err != nil {
-		return err
+		return rt.pollFailed(err)
 	}
 	rt.lastPollEnd = initialUntil
 	rt.lowerExclusiveLB = true
@@ -297,27 +421,27 @@ func (rt *watchRuntime) loop(ctx context.Context) error {
 		case err := <-rt.errCh:
 			return err
 		case delivery := <-rt.deliveries:
-			if err := rt.emitWebhook(delivery); err != nil {
+			if err := rt.emitWebhook(ctx, delivery); err != nil {
 				return err
 			}
 		case <-rt.ticker.C:
 			if err := rt.pollNext(ctx); err != nil {
 				return err
 			}
+			rt.opts.dashboard.scheduled(time.Now().Add(rt.opts.pollInterval))
 		}
 	}
 }
 
-func (rt *watchRuntime) emitWebhook(delivery webhookDelivery) error {
-	if err := rt.encoder.Encode(watchOutput{
-		Kind:       "webhook",
-		EventType:  delivery.eventType,
-		DeliveryID: delivery.deliveryID,
-		ReceivedAt: delivery.receivedAt,
-		Raw:        delivery.payload,
-	}); err != nil {
+func (rt *watchRuntime) emitWebhook(ctx context.Context, delivery webhookDelivery) error {
+	output := deliveryOutput(delivery)
+	if err := rt.encoder.Encode(output); err != nil {
 		return fmt.Errorf("write webhook event: %w", err)
 	}
+	rt.opts.hook.fireWebhook(ctx, output)
+	rt.opts.forward.fireWebhook(ctx, rt.opts.dataSourceID, output)
+	rt.opts.metrics.webhookDelivered()
+	rt.opts.dashboard.webhookReceived(output)
 	return nil
 }
 
@@ -331,13 +455,23 @@ func (rt *watchRuntime) pollNext(ctx context.Context) error {
 		until,
 		rt.lowerExclusiveLB,
 	); err != nil {
-		return err
+		return rt.pollFailed(err)
 	}
 	rt.lastPollEnd = until
 	rt.lowerExclusiveLB = true
 	return nil
 }
 
+// pollFailed ends the watch, except under --tui, where the error is shown and the same
+// window is polled again on the next tick.
+func (rt *watchRuntime) pollFailed(err error) error {
+	if rt.opts.dashboard == nil {
+		return err
+	}
+	rt.opts.dashboard.pollFailed(err)
+	return nil
+}
+
 func (opts *syncWatchOptions) prepare(sinceArg string) error {
 	if opts.dataSourceID == "" {
 		return errors.New("data-source-id is required")
@@ -345,6 +479,12 @@ func (opts *syncWatchOptions) prepare(sinceArg string) error {
 	if opts.pollInterval <= 0 {
 		return errors.New("poll-interval must be greater than zero")
 	}
+	if opts.execTimeout < 0 {
+		return errors.New("exec-timeout cannot be negative")
+	}
+	if opts.outputFile == "" && (opts.rotateSize > 0 || opts.rotateDaily) {
+		return errors.New("--rotate-size and --rotate-daily require --output-file")
+	}
 	if sinceArg != "" {
 		parsed, err := time.Parse(time.RFC3339, sinceArg)
 		if err != nil {
@@ -370,7 +510,12 @@ func (opts *syncWatchOptions) startWebhookServer(
 	errCh chan<- error,
 ) (*http.Server, error) {
 	mux := http.NewServeMux()
-	mux.Handle(opts.callbackPath, opts.webhookHandler(deliveries, cmd.ErrOrStderr()))
+	if !opts.disableWebhookEnabled() {
+		mux.Handle(opts.callbackPath, opts.webhookHandler(deliveries, cmd.ErrOrStderr()))
+	}
+	if opts.metrics != nil {
+		mux.Handle(metricsPath, opts.metrics.handler())
+	}
 
 	server := &http.Server{
 		Addr:              opts.listenAddr,
@@ -393,13 +538,18 @@ func (opts *syncWatchOptions) startWebhookServer(
 		}
 	}()
 
-	if _, err := fmt.Fprintf(
-		cmd.ErrOrStderr(),
-		"Listening for Notion webhooks on http://%s%s\n",
-		server.Addr,
-		opts.callbackPath,
-	); err != nil {
-		return nil, fmt.Errorf("announce webhook listener: %w", err)
+	if !opts.disableWebhookEnabled() {
+		if _, err := fmt.Fprintf(
+			cmd.ErrOrStderr(),
+			"Listening for Notion webhooks on http://%s%s\n",
+			server.Addr,
+			opts.callbackPath,
+		); err != nil {
+			return nil, fmt.Errorf("announce webhook listener: %w", err)
+		}
+	}
+	if opts.metrics != nil {
+		safeLog(cmd.ErrOrStderr(), "Serving metrics on http://%s%s", server.Addr, metricsPath)
 	}
 
 	return server, nil
@@ -432,10 +582,6 @@ func (opts *syncWatchOptions) emitPoll(
 	if err != nil {
 		return fmt.Errorf("poll changes: %w", err)
 	}
-	if opts.suppressEmptyEnabled() && len(pages) == 0 {
-		return nil
-	}
-
 	output := watchOutput{
 		Kind: "poll",
 		Window: &watchWindow{
@@ -445,9 +591,17 @@ func (opts *syncWatchOptions) emitPoll(
 		Count: len(pages),
 		Pages: pages,
 	}
+	opts.metrics.pollCompleted(len(pages))
+	opts.dashboard.pollCompleted(output)
+	if opts.suppressEmptyEnabled() && len(pages) == 0 {
+		return nil
+	}
+
 	if err := encoder.Encode(output); err != nil {
 		return fmt.Errorf("write poll output: %w", err)
 	}
+	opts.hook.firePoll(ctx, output)
+	opts.forward.firePoll(ctx, opts.dataSourceID, output)
 	return nil
 }
 
@@ -480,6 +634,12 @@ func (opts *syncWatchOptions) webhookHandler(deliveries chan<- webhookDelivery,
 			receivedAt: time.Now().UTC(),
 		}
 
+		// Persist before acknowledging so a failed write makes Notion retry the delivery.
+		if err := opts.store.append(delivery); err != nil {
+			safeLog(log, "store webhook delivery: %v", err)
+			http.Error(w, "store delivery", http.StatusInternalServerError)
+			return
+		}
 		offerDelivery(deliveries, delivery, log)
 		respondWebhookOK(w, log)
 	})
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+const defaultExecTimeout = 5 * time.Minute
+
+// execHook runs a shell command once per change event: once per changed page for polls and
+// once per webhook delivery. The event is written to the command's stdin as the same JSON
+// object sync watch prints, and NOTION_* variables describe it. Command output goes to
+// stderr so stdout stays a clean event stream.
+type execHook struct {
+	log          io.Writer
+	command      string
+	dataSourceID string
+	timeout      time.Duration
+}
+
+// firePoll runs the hook for each page in a poll, oldest edit first.
+func (h *execHook) firePoll(ctx context.Context, output watchOutput) {
+	if h == nil {
+		return
+	}
+	for i := len(output.Pages) - 1; i >= 0; i-- {
+		page := output.Pages[i]
+		event := output
+		event.Pages, event.Count = output.Pages[i:i+1], 1
+		h.fire(ctx, event, map[string]string{
+			"NOTION_PAGE_ID":          page.ID,
+			"NOTION_PAGE_URL":         page.URL,
+			"NOTION_LAST_EDITED_TIME": page.LastEditedTime.UTC().Format(time.RFC3339),
+		})
+	}
+}
+
+// fireWebhook runs the hook for one webhook delivery.
+func (h *execHook) fireWebhook(ctx context.Context, output watchOutput) {
+	if h == nil {
+		return
+	}
+	env := map[string]string{
+		"NOTION_EVENT_TYPE":  output.EventType,
+		"NOTION_DELIVERY_ID": output.DeliveryID,
+	}
+	if entityType, id := extractEntity(output.Raw); entityType == "page" {
+		env["NOTION_PAGE_ID"] = id
+	}
+	h.fire(ctx, output, env)
+}
+
+// fire runs the command and logs failures; a failing hook never stops the watcher.
+func (h *execHook) fire(ctx context.Context, event watchOutput, env map[string]string) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		safeLog(h.log, "exec hook: encode event: %v", err)
+		return
+	}
+
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+	cmd := shellCommand(ctx, h.command)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = h.log
+	cmd.Stderr = h.log
+	cmd.Env = append(os.Environ(),
+		"NOTION_EVENT_KIND="+event.Kind,
+		"NOTION_DATA_SOURCE_ID="+h.dataSourceID,
+	)
+	for key, value := range env {
+		if value != "" {
+			cmd.Env = append(cmd.Env, key+"="+value)
+		}
+	}
+
+	if err := cmd.Run(); err != nil {
+		safeLog(h.log, "exec hook failed for %s event%s: %v", event.Kind, pageSuffix(env), err)
+	}
+}
+
+func pageSuffix(env map[string]string) string {
+	if id := env["NOTION_PAGE_ID"]; id != "" {
+		return " on page " + id
+	}
+	return ""
+}
+
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command) // #nosec G204 -- the user supplies the command
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command) // #nosec G204 -- the user supplies the command
+}
+
+// extractEntity returns the entity a webhook payload refers to, if any.
+func extractEntity(payload []byte) (string, string) {
+	var outer struct {
+		Entity struct {
+			ID   string `json:"id"`
+			Type string `json:"type"`
+		} `json:"entity"`
+	}
+	if err := json.Unmarshal(payload, &outer); err != nil {
+		return "", ""
+	}
+	return outer.Entity.Type, outer.Entity.ID
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestExecHookRunsOncePerPolledPage(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	edited := time.Date(2024, 4, 10, 15, 31, 0, 0, time.UTC)
+	client := &recordingChangeClient{
+		t:            t,
+		expectedKeys: []string{"on_or_after"},
+		perCallPages: [][]notion.Page{{
+			{ID: "page-2", URL: "https://notion.so/page-2", LastEditedTime: edited.Add(time.Minute)},
+			{ID: "page-1", URL: "https://notion.so/page-1", LastEditedTime: edited},
+		}},
+		expectedDataSource: "ds-1",
+	}
+
+	var log bytes.Buffer
+	opts := &syncWatchOptions{
+		dataSourceID: "ds-1",
+		hook: &execHook{
+			log:          &log,
+			command:      `cat > "` + dir + `/$NOTION_PAGE_ID.json"; echo "$NOTION_EVENT_KIND $NOTION_DATA_SOURCE_ID $NOTION_PAGE_URL $NOTION_LAST_EDITED_TIME" >> "` + dir + `/env.log"`,
+			dataSourceID: "ds-1",
+			timeout:      time.Minute,
+		},
+	}
+	since := edited.Add(-time.Hour)
+	var out bytes.Buffer
+	if err := opts.emitPoll(context.Background(), client, json.NewEncoder(&out), since, edited.Add(time.Hour), false); err != nil {
+		t.Fatalf("emitPoll failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "page-1.json"))
+	if err != nil {
+		t.Fatalf("hook did not run for page-1: %v", err)
+	}
+	var event watchOutput
+	if err := json.Unmarshal(data, &event); err != nil {
+		t.Fatalf("decode hook stdin: %v", err)
+	}
+	if event.Kind != "poll" || event.Count != 1 || len(event.Pages) != 1 || event.Pages[0].ID != "page-1" {
+		t.Fatalf("unexpected hook event: %+v", event)
+	}
+
+	envLog, err := os.ReadFile(filepath.Join(dir, "env.log"))
+	if err != nil {
+		t.Fatalf("read env log: %v", err)
+	}
+	want := "poll ds-1 https://notion.so/page-1 2024-04-10T15:31:00Z\n" +
+		"poll ds-1 https://notion.so/page-2 2024-04-10T15:32:00Z\n"
+	if string(envLog) != want {
+		t.Fatalf("expected hooks oldest first:\n%s\ngot:\n%s", want, envLog)
+	}
+}
+
+func TestExecHookWebhookEnvironment(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	var log bytes.Buffer
+	hook := &execHook{
+		log:     &log,
+		command: `echo "$NOTION_EVENT_KIND $NOTION_EVENT_TYPE $NOTION_DELIVERY_ID $NOTION_PAGE_ID" > "` + dir + `/env.log"`,
+	}
+	hook.fireWebhook(context.Background(), watchOutput{
+		Kind:       "webhook",
+		EventType:  "page.content_updated",
+		DeliveryID: "delivery-1",
+		Raw:        json.RawMessage(`{"type":"page.content_updated","entity":{"id":"page-9","type":"page"}}`),
+	})
+
+	got, err := os.ReadFile(filepath.Join(dir, "env.log"))
+	if err != nil {
+		t.Fatalf("read env log: %v", err)
+	}
+	if want := "webhook page.content_updated delivery-1 page-9\n"; string(got) != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestExecHookFailureIsLogged(t *testing.T) {
+	t.Parallel()
+
+	var log bytes.Buffer
+	hook := &execHook{log: &log, command: "echo boom >&2; exit 3"}
+	hook.fireWebhook(context.Background(), watchOutput{Kind: "webhook"})
+
+	if !strings.Contains(log.String(), "boom") || !strings.Contains(log.String(), "exec hook failed for webhook event") {
+		t.Fatalf("expected hook output and failure in log, got %q", log.String())
+	}
+}
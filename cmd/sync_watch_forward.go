@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/notify"
+)
+
+const defaultForwardTemplate = `{{if .Title}}{{.Title}}{{else}}{{.PageID}}{{end}}`
+
+// forwardHook posts a chat message for every change event, with the same granularity as
+// execHook: once per changed page for polls and once per webhook delivery. Delivery
+// failures are logged and never stop the watcher.
+type forwardHook struct {
+	log      io.Writer
+	notifier notify.Notifier
+	body     *template.Template
+}
+
+// forwardEvent is the data --forward-template is rendered with.
+type forwardEvent struct {
+	Properties     map[string]string
+	LastEditedTime time.Time
+	Kind           string
+	EventType      string
+	DeliveryID     string
+	DataSourceID   string
+	PageID         string
+	URL            string
+	Title          string
+}
+
+// newForwardHook parses the --forward-url target and --forward-template body.
+func newForwardHook(target, body string, log io.Writer) (*forwardHook, error) {
+	cfg, err := notify.ParseTarget(target)
+	if err != nil {
+		return nil, fmt.Errorf("parse --forward-url: %w", err)
+	}
+	notifier, err := notify.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("parse --forward-url: %w", err)
+	}
+	if body == "" {
+		body = defaultForwardTemplate
+	}
+	tmpl, err := template.New("forward").Option("missingkey=zero").Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("parse --forward-template: %w", err)
+	}
+	return &forwardHook{log: log, notifier: notifier, body: tmpl}, nil
+}
+
+// firePoll forwards each page in a poll, oldest edit first.
+func (h *forwardHook) firePoll(ctx context.Context, dataSourceID string, output watchOutput) {
+	if h == nil {
+		return
+	}
+	for i := len(output.Pages) - 1; i >= 0; i-- {
+		page := output.Pages[i]
+		properties := make(map[string]string, len(page.Properties))
+		for name := range page.Properties {
+			properties[name] = summarizePageProperty(page, name)
+		}
+		h.fire(ctx, "Notion page changed", forwardEvent{
+			Properties:     properties,
+			LastEditedTime: page.LastEditedTime,
+			Kind:           output.Kind,
+			DataSourceID:   dataSourceID,
+			PageID:         page.ID,
+			URL:            page.URL,
+			Title:          pageTitle(page),
+		})
+	}
+}
+
+// fireWebhook forwards one webhook delivery. Deliveries carry no page properties, so only
+// the event metadata is available to the template.
+func (h *forwardHook) fireWebhook(ctx context.Context, dataSourceID string, output watchOutput) {
+	if h == nil {
+		return
+	}
+	event := forwardEvent{
+		Kind:         output.Kind,
+		EventType:    output.EventType,
+		DeliveryID:   output.DeliveryID,
+		DataSourceID: dataSourceID,
+	}
+	if entityType, id := extractEntity(output.Raw); entityType == "page" {
+		event.PageID = id
+	}
+	title := "Notion webhook event"
+	if output.EventType != "" {
+		title = "Notion " + output.EventType
+	}
+	h.fire(ctx, title, event)
+}
+
+func (h *forwardHook) fire(ctx context.Context, title string, event forwardEvent) {
+	var body strings.Builder
+	if err := h.body.Execute(&body, event); err != nil {
+		safeLog(h.log, "forward: render template for %s event: %v", event.Kind, err)
+		return
+	}
+	msg := notify.Message{Title: title, Body: strings.TrimSpace(body.String()), URL: event.URL, Level: notify.LevelInfo}
+	if err := h.notifier.Notify(ctx, msg); err != nil {
+		safeLog(h.log, "forward failed for %s event%s: %v", event.Kind, forwardPageSuffix(event.PageID), err)
+	}
+}
+
+func forwardPageSuffix(pageID string) string {
+	if pageID != "" {
+		return " on page " + pageID
+	}
+	return ""
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+type forwardRecorder struct {
+	mu     sync.Mutex
+	bodies []map[string]any
+	status int
+}
+
+func (r *forwardRecorder) serve(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Errorf("decode forwarded body: %v", err)
+		}
+		r.mu.Lock()
+		r.bodies = append(r.bodies, body)
+		r.mu.Unlock()
+		if r.status != 0 {
+			w.WriteHeader(r.status)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestForwardHookPostsSlackMessagePerPolledPage(t *testing.T) {
+	t.Parallel()
+
+	recorder := &forwardRecorder{}
+	server := recorder.serve(t)
+	hook, err := newForwardHook("slack="+server.URL, `{{.Title}} is now {{.Properties.Status}}{{.Properties.Missing}}`, io.Discard)
+	if err != nil {
+		t.Fatalf("newForwardHook returned error: %v", err)
+	}
+
+	edited := time.Date(2024, 4, 10, 15, 31, 0, 0, time.UTC)
+	page := func(id, title, status string, at time.Time) notion.Page {
+		return notion.Page{ID: id, URL: "https://notion.so/" + id, LastEditedTime: at, Properties: map[string]notion.PropertyValue{
+			"Name":   {Type: "title", Title: []notion.RichText{{PlainText: title}}},
+			"Status": {Type: "status", Status: &notion.StatusValue{Name: status}},
+		}}
+	}
+	client := &recordingChangeClient{
+		t:            t,
+		expectedKeys: []string{"on_or_after"},
+		perCallPages: [][]notion.Page{{
+			page("page-2", "Launch", "Done", edited.Add(time.Minute)),
+			page("page-1", "Draft", "In progress", edited),
+		}},
+	}
+	opts := &syncWatchOptions{dataSourceID: "ds-1", forward: hook}
+	var out bytes.Buffer
+	if err := opts.emitPoll(context.Background(), client, json.NewEncoder(&out), edited.Add(-time.Hour), edited.Add(time.Hour), false); err != nil {
+		t.Fatalf("emitPoll failed: %v", err)
+	}
+
+	if len(recorder.bodies) != 2 {
+		t.Fatalf("expected two forwarded messages, got %d", len(recorder.bodies))
+	}
+	want := "*Notion page changed*\nDraft is now In progress\nhttps://notion.so/page-1"
+	if got := recorder.bodies[0]["text"]; got != want {
+		t.Fatalf("first message = %q, want %q", got, want)
+	}
+	if got := recorder.bodies[1]["text"].(string); !strings.Contains(got, "Launch is now Done") {
+		t.Fatalf("second message = %q", got)
+	}
+}
+
+func TestForwardHookPostsGenericWebhookDelivery(t *testing.T) {
+	t.Parallel()
+
+	recorder := &forwardRecorder{}
+	server := recorder.serve(t)
+	hook, err := newForwardHook(server.URL, "", io.Discard)
+	if err != nil {
+		t.Fatalf("newForwardHook returned error: %v", err)
+	}
+	hook.fireWebhook(context.Background(), "ds-1", watchOutput{
+		Kind:       "webhook",
+		EventType:  "page.content_updated",
+		DeliveryID: "delivery-1",
+		Raw:        json.RawMessage(`{"entity":{"id":"page-9","type":"page"}}`),
+	})
+
+	if len(recorder.bodies) != 1 {
+		t.Fatalf("expected one forwarded message, got %d", len(recorder.bodies))
+	}
+	body := recorder.bodies[0]
+	if body["title"] != "Notion page.content_updated" || body["body"] != "page-9" {
+		t.Fatalf("unexpected generic payload: %#v", body)
+	}
+}
+
+func TestForwardHookFailureIsLogged(t *testing.T) {
+	t.Parallel()
+
+	recorder := &forwardRecorder{status: http.StatusInternalServerError}
+	server := recorder.serve(t)
+	var log bytes.Buffer
+	hook, err := newForwardHook(server.URL, "", &log)
+	if err != nil {
+		t.Fatalf("newForwardHook returned error: %v", err)
+	}
+	hook.fireWebhook(context.Background(), "ds-1", watchOutput{Kind: "webhook"})
+
+	if !strings.Contains(log.String(), "forward failed for webhook event") {
+		t.Fatalf("expected failure in log, got %q", log.String())
+	}
+}
+
+func TestNewForwardHookRejectsBadInput(t *testing.T) {
+	t.Parallel()
+
+	if _, err := newForwardHook("carrier-pigeon=https://example.com", "", io.Discard); err == nil {
+		t.Fatal("expected error for unknown provider")
+	}
+	if _, err := newForwardHook("https://example.com", "{{.Title", io.Discard); err == nil {
+		t.Fatal("expected error for bad template")
+	}
+}
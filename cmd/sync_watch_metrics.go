@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+const (
+	metricsPath        = "/metrics"
+	metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// watchMetrics counts sync watch activity for the --metrics endpoint. API counters come
+// from the notion.Stats attached to the watch's context. A nil *watchMetrics ignores updates.
+type watchMetrics struct {
+	stats      *notion.Stats
+	webhooks   atomic.Int64
+	pollCycles atomic.Int64
+	changes    atomic.Int64
+}
+
+// newWatchMetrics counts the command's API calls, sharing the --stats or --with-meta
+// counters when either is set.
+func newWatchMetrics(cmd *cobra.Command) *watchMetrics {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	stats, _ := ctx.Value(runStatsKey{}).(*notion.Stats)
+	if meta := runMetaFromContext(ctx); stats == nil && meta != nil {
+		stats = meta.stats
+	}
+	if stats == nil {
+		stats = &notion.Stats{}
+		cmd.SetContext(notion.WithStats(ctx, stats))
+	}
+	return &watchMetrics{stats: stats}
+}
+
+func (m *watchMetrics) webhookDelivered() {
+	if m != nil {
+		m.webhooks.Add(1)
+	}
+}
+
+func (m *watchMetrics) pollCompleted(changes int) {
+	if m != nil {
+		m.pollCycles.Add(1)
+		m.changes.Add(int64(changes))
+	}
+}
+
+// handler serves the counters in the Prometheus text exposition format.
+func (m *watchMetrics) handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", metricsContentType)
+		m.write(w)
+	})
+}
+
+func (m *watchMetrics) write(w io.Writer) {
+	counter := func(name, help string, value any) {
+		_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %v\n", name, help, name, name, value)
+	}
+	counter("notionctl_watch_webhook_deliveries_total", "Verified webhook deliveries processed.", m.webhooks.Load())
+	counter("notionctl_watch_poll_cycles_total", "Completed polls for changed pages.", m.pollCycles.Load())
+	counter("notionctl_watch_changes_detected_total", "Changed pages found by polls.", m.changes.Load())
+	counter("notionctl_watch_api_requests_total", "Notion API requests sent, including retries.", m.stats.Requests())
+	counter(
+		"notionctl_watch_api_errors_total",
+		"Notion API requests that failed or returned a 4xx or 5xx status.",
+		m.stats.Errors(),
+	)
+	counter("notionctl_watch_rate_limited_total", "Notion API responses that were 429 Too Many Requests.", m.stats.RateLimited())
+	counter(
+		"notionctl_watch_rate_limit_wait_seconds_total",
+		"Time spent waiting on the client rate limiter and retry backoff.",
+		(m.stats.LimiterWait() + m.stats.Backoff()).Seconds(),
+	)
+}
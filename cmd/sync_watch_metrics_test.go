@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestWatchMetricsCountPollsAndWebhooks(t *testing.T) {
+	since := time.Date(2024, 4, 10, 15, 30, 0, 0, time.UTC)
+	client := &recordingChangeClient{
+		t:                  t,
+		expectedKeys:       []string{"on_or_after"},
+		perCallPages:       [][]notion.Page{{{ID: "page-1"}, {ID: "page-2"}}},
+		expectedDataSource: "ds-1",
+	}
+	metrics := &watchMetrics{stats: &notion.Stats{}}
+	opts := &syncWatchOptions{dataSourceID: "ds-1", metrics: metrics}
+	if err := opts.emitPoll(context.Background(), client, json.NewEncoder(&bytes.Buffer{}), since, since.Add(time.Minute), false); err != nil {
+		t.Fatalf("emitPoll failed: %v", err)
+	}
+	metrics.webhookDelivered()
+
+	rec := httptest.NewRecorder()
+	metrics.handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, metricsPath, nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != metricsContentType {
+		t.Fatalf("GET /metrics = %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE notionctl_watch_poll_cycles_total counter\nnotionctl_watch_poll_cycles_total 1\n",
+		"notionctl_watch_changes_detected_total 2\n",
+		"notionctl_watch_webhook_deliveries_total 1\n",
+		"notionctl_watch_api_errors_total 0\n",
+		"notionctl_watch_rate_limit_wait_seconds_total 0\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+
+	// Without --metrics the counters are nil and updates are ignored.
+	var disabled *watchMetrics
+	disabled.pollCompleted(3)
+	disabled.webhookDelivered()
+}
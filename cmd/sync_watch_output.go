@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const outputFilePermissions = 0o600
+
+// rotatingWriter appends JSON lines to a file and moves it aside once it would grow past
+// maxSize bytes or, with daily set, when the local date changes. Rotated files keep the base
+// name with the rotation time inserted, e.g. events-20250102-150405.jsonl. Each Write is
+// expected to be one whole line, so lines are never split across files.
+type rotatingWriter struct {
+	now     func() time.Time
+	file    *os.File
+	opened  time.Time
+	path    string
+	size    int64
+	maxSize int64
+	daily   bool
+}
+
+func newRotatingWriter(path string, maxSize int64, daily bool) (*rotatingWriter, error) {
+	w := &rotatingWriter{now: time.Now, path: path, maxSize: maxSize, daily: daily}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *rotatingWriter) open() error {
+	// #nosec G304 -- user-chosen output file
+	f, err := os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, outputFilePermissions)
+	if err != nil {
+		return fmt.Errorf("open output file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("stat output file: %w", err)
+	}
+	w.file, w.size, w.opened = f, info.Size(), w.now()
+	if w.size > 0 {
+		// An existing file belongs to the day it was last written, so a restart on a new
+		// day still rotates it.
+		w.opened = info.ModTime()
+	}
+	return nil
+}
+
+func (w *rotatingWriter) Write(p []byte) (int, error) {
+	if w.shouldRotate(len(p)) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	if err != nil {
+		return n, fmt.Errorf("write output file: %w", err)
+	}
+	return n, nil
+}
+
+func (w *rotatingWriter) shouldRotate(next int) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.maxSize > 0 && w.size+int64(next) > w.maxSize {
+		return true
+	}
+	if w.daily {
+		y1, m1, d1 := w.opened.Local().Date()
+		y2, m2, d2 := w.now().Local().Date()
+		return y1 != y2 || m1 != m2 || d1 != d2
+	}
+	return false
+}
+
+func (w *rotatingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("close output file: %w", err)
+	}
+	target, err := w.rotatedName()
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(w.path, target); err != nil {
+		return fmt.Errorf("rotate output file: %w", err)
+	}
+	return w.open()
+}
+
+// rotatedName picks an unused name for the file being moved aside.
+func (w *rotatingWriter) rotatedName() (string, error) {
+	ext := filepath.Ext(w.path)
+	base := strings.TrimSuffix(w.path, ext) + "-" + w.now().Local().Format("20060102-150405")
+	candidate := base + ext
+	for i := 1; ; i++ {
+		if _, err := os.Stat(candidate); errors.Is(err, os.ErrNotExist) {
+			return candidate, nil
+		} else if err != nil {
+			return "", fmt.Errorf("rotate output file: %w", err)
+		}
+		candidate = base + "." + strconv.Itoa(i) + ext
+	}
+}
+
+func (w *rotatingWriter) Close() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("close output file: %w", err)
+	}
+	return nil
+}
+
+// byteSizeValue is a flag accepting sizes such as 500KB, 10MB, or 1GB (powers of 1024).
+type byteSizeValue struct {
+	target *int64
+}
+
+func newByteSizeValue(target *int64) *byteSizeValue {
+	return &byteSizeValue{target: target}
+}
+
+func (v *byteSizeValue) String() string {
+	if v.target == nil || *v.target == 0 {
+		return ""
+	}
+	return strconv.FormatInt(*v.target, 10)
+}
+
+func (v *byteSizeValue) Set(raw string) error {
+	size, err := parseByteSize(raw)
+	if err != nil {
+		return err
+	}
+	*v.target = size
+	return nil
+}
+
+func (v *byteSizeValue) Type() string {
+	return "size"
+}
+
+func parseByteSize(raw string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(raw))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		factor int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(s, unit.suffix) {
+			s, multiplier = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix)), unit.factor
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %q (expected a positive number such as 500KB, 10MB, or 1GB)", raw)
+	}
+	return n * multiplier, nil
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingWriterRotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.jsonl")
+	clock := time.Date(2025, 3, 1, 9, 30, 0, 0, time.Local)
+
+	w, err := newRotatingWriter(path, 20, false)
+	if err != nil {
+		t.Fatalf("newRotatingWriter returned error: %v", err)
+	}
+	w.now = func() time.Time { return clock }
+	for _, line := range []string{"{\"n\":1}\n", "{\"n\":2}\n", "{\"n\":3}\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	rotated := readFile(t, filepath.Join(dir, "events-20250301-093000.jsonl"))
+	if rotated != "{\"n\":1}\n{\"n\":2}\n" {
+		t.Fatalf("unexpected rotated content %q", rotated)
+	}
+	if current := readFile(t, path); current != "{\"n\":3}\n" {
+		t.Fatalf("unexpected current content %q", current)
+	}
+}
+
+func TestRotatingWriterRotatesDaily(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.jsonl")
+	clock := time.Date(2025, 3, 1, 23, 59, 0, 0, time.Local)
+
+	w, err := newRotatingWriter(path, 0, true)
+	if err != nil {
+		t.Fatalf("newRotatingWriter returned error: %v", err)
+	}
+	w.now = func() time.Time { return clock }
+	w.opened = clock
+	if _, err := w.Write([]byte("a\n")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	clock = clock.Add(2 * time.Minute)
+	if _, err := w.Write([]byte("b\n")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if _, err := w.Write([]byte("c\n")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	if rotated := readFile(t, filepath.Join(dir, "events-20250302-000100.jsonl")); rotated != "a\n" {
+		t.Fatalf("unexpected rotated content %q", rotated)
+	}
+	if current := readFile(t, path); current != "b\nc\n" {
+		t.Fatalf("unexpected current content %q", current)
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	cases := map[string]int64{"512": 512, "10KB": 10 << 10, "5mb": 5 << 20, "1G": 1 << 30, "2 MB": 2 << 20}
+	for input, want := range cases {
+		got, err := parseByteSize(input)
+		if err != nil || got != want {
+			t.Fatalf("parseByteSize(%q) = %d, %v; want %d", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "0", "-1MB", "ten"} {
+		if _, err := parseByteSize(input); err == nil || !strings.Contains(err.Error(), "invalid size") {
+			t.Fatalf("parseByteSize(%q) should fail, got %v", input, err)
+		}
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const (
+	dashboardRefresh    = time.Second
+	dashboardMaxChanges = 200
+	dashboardMaxLogs    = 50
+	dashboardLogLines   = 6
+	dashboardMinWidth   = 40
+
+	ansiEnterScreen = "\x1b[?1049h\x1b[?25l"
+	ansiLeaveScreen = "\x1b[?25h\x1b[?1049l"
+	ansiRedraw      = "\x1b[H\x1b[2J"
+)
+
+// watchDashboard is the --tui view of sync watch: counters, the next poll, recently changed
+// pages, and the log lines the watcher would otherwise print to stderr. A nil
+// *watchDashboard ignores updates, like watchMetrics.
+type watchDashboard struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	started  time.Time
+	nextPoll time.Time
+	listen   string
+	source   string
+	changes  []dashboardChange
+	logs     []dashboardLine
+	partial  []byte
+	webhooks int
+	polls    int
+	found    int
+	failures int
+}
+
+type dashboardChange struct {
+	at    time.Time
+	kind  string
+	title string
+	id    string
+}
+
+type dashboardLine struct {
+	at   time.Time
+	text string
+}
+
+func newWatchDashboard(opts *syncWatchOptions) *watchDashboard {
+	d := &watchDashboard{now: time.Now, source: opts.dataSourceID}
+	if !opts.disableWebhookEnabled() {
+		d.listen = opts.listenAddr + opts.callbackPath
+	}
+	d.started = d.now()
+	return d
+}
+
+// startDashboard takes over the terminal for --tui. Events still go to --output-file when
+// given, stderr output is shown in the dashboard's log, and Ctrl-C stops the watcher
+// cleanly so the terminal is restored. The returned func undoes all of it.
+func (opts *syncWatchOptions) startDashboard(cmd *cobra.Command) (func(), error) {
+	out, ok := cmd.OutOrStdout().(*os.File)
+	if !ok || !term.IsTerminal(int(out.Fd())) {
+		return nil, errors.New("--tui needs a terminal on stdout; use --output-file for unattended runs")
+	}
+	d := newWatchDashboard(opts)
+	opts.dashboard = d
+	opts.out = io.Discard
+
+	stderr := cmd.ErrOrStderr()
+	cmd.SetErr(d)
+	ctx, stopSignals := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	cmd.SetContext(ctx)
+
+	drawCtx, stopDrawing := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.run(drawCtx, out)
+	}()
+	return func() {
+		stopDrawing()
+		<-done
+		stopSignals()
+		cmd.SetErr(stderr)
+	}, nil
+}
+
+// Write collects stderr output as log lines so it does not tear the screen.
+func (d *watchDashboard) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.partial = append(d.partial, p...)
+	for {
+		i := bytes.IndexByte(d.partial, '\n')
+		if i < 0 {
+			break
+		}
+		d.logLocked(string(d.partial[:i]))
+		d.partial = d.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+func (d *watchDashboard) logLocked(text string) {
+	if text = strings.TrimSpace(text); text == "" {
+		return
+	}
+	d.logs = append(d.logs, dashboardLine{at: d.now(), text: text})
+	if len(d.logs) > dashboardMaxLogs {
+		d.logs = d.logs[len(d.logs)-dashboardMaxLogs:]
+	}
+}
+
+func (d *watchDashboard) scheduled(next time.Time) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.nextPoll = next
+}
+
+func (d *watchDashboard) pollCompleted(output watchOutput) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.polls++
+	d.found += len(output.Pages)
+	for i := len(output.Pages) - 1; i >= 0; i-- {
+		page := output.Pages[i]
+		d.addLocked(dashboardChange{at: page.LastEditedTime, kind: output.Kind, title: pickerTitle(page), id: page.ID})
+	}
+}
+
+func (d *watchDashboard) webhookReceived(output watchOutput) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.webhooks++
+	_, id := extractEntity(output.Raw)
+	title := output.EventType
+	if title == "" {
+		title = "webhook event"
+	}
+	d.addLocked(dashboardChange{at: output.ReceivedAt, kind: output.Kind, title: title, id: id})
+}
+
+func (d *watchDashboard) pollFailed(err error) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.failures++
+	d.logLocked("error: " + err.Error())
+}
+
+func (d *watchDashboard) addLocked(change dashboardChange) {
+	d.changes = append([]dashboardChange{change}, d.changes...)
+	if len(d.changes) > dashboardMaxChanges {
+		d.changes = d.changes[:dashboardMaxChanges]
+	}
+}
+
+// run redraws the dashboard on out every second until ctx is done, then restores the
+// terminal.
+func (d *watchDashboard) run(ctx context.Context, out *os.File) {
+	_, _ = io.WriteString(out, ansiEnterScreen)
+	defer func() { _, _ = io.WriteString(out, ansiLeaveScreen) }()
+
+	ticker := time.NewTicker(dashboardRefresh)
+	defer ticker.Stop()
+	for {
+		width, height, err := term.GetSize(int(out.Fd()))
+		if err != nil {
+			width, height = 80, 24
+		}
+		var frame strings.Builder
+		frame.WriteString(ansiRedraw)
+		d.render(&frame, width, height)
+		_, _ = io.WriteString(out, frame.String())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// render draws one frame sized to width columns and height rows.
+func (d *watchDashboard) render(w io.Writer, width, height int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	width = max(width, dashboardMinWidth)
+	now := d.now()
+	lines := make([]string, 0, height)
+
+	lines = append(lines,
+		fmt.Sprintf("notionctl sync watch  %s  up %s", d.source, now.Sub(d.started).Round(time.Second)),
+		d.statusLine(now),
+		"",
+		"RECENT CHANGES",
+	)
+
+	logRows := min(len(d.logs), dashboardLogLines)
+	footer := 2 + logRows
+	if logRows == 0 {
+		footer = 0
+	}
+	// Leave the last row empty so the final newline does not scroll the screen.
+	room := height - len(lines) - footer - 1
+	if len(d.changes) == 0 {
+		lines = append(lines, "  (no changes yet)")
+	}
+	for i, change := range d.changes {
+		if i >= room {
+			break
+		}
+		at := "--:--:--"
+		if !change.at.IsZero() {
+			at = change.at.Local().Format(time.TimeOnly)
+		}
+		lines = append(lines, fmt.Sprintf("  %s  %-7s  %s  %s", at, change.kind, change.title, change.id))
+	}
+
+	if logRows > 0 {
+		lines = append(lines, "", "LOG")
+		for _, entry := range d.logs[len(d.logs)-logRows:] {
+			lines = append(lines, fmt.Sprintf("  %s  %s", entry.at.Local().Format(time.TimeOnly), entry.text))
+		}
+	}
+
+	for _, line := range lines {
+		_, _ = fmt.Fprintln(w, truncateColumns(line, width))
+	}
+}
+
+func (d *watchDashboard) statusLine(now time.Time) string {
+	parts := make([]string, 0, 4)
+	if d.listen != "" {
+		parts = append(parts, fmt.Sprintf("webhooks %d (on %s)", d.webhooks, d.listen))
+	} else {
+		parts = append(parts, "webhooks off")
+	}
+	parts = append(parts, fmt.Sprintf("polls %d", d.polls), fmt.Sprintf("changes %d", d.found))
+	if d.failures > 0 {
+		parts = append(parts, fmt.Sprintf("errors %d", d.failures))
+	}
+	switch {
+	case d.nextPoll.IsZero():
+		parts = append(parts, "first poll running")
+	case d.nextPoll.After(now):
+		parts = append(parts, "next poll in "+d.nextPoll.Sub(now).Round(time.Second).String())
+	default:
+		parts = append(parts, "polling now")
+	}
+	return strings.Join(parts, " | ")
+}
+
+func truncateColumns(line string, width int) string {
+	runes := []rune(line)
+	if len(runes) <= width {
+		return line
+	}
+	return string(runes[:width-1]) + "…"
+}
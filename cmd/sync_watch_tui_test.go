@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestWatchDashboardRender(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	opts := &syncWatchOptions{dataSourceID: "ds-1", listenAddr: ":8914", callbackPath: "/webhook"}
+	d := newWatchDashboard(opts)
+	d.now = func() time.Time { return now }
+	d.started = now.Add(-90 * time.Second)
+
+	d.scheduled(now.Add(75 * time.Second))
+	d.pollCompleted(watchOutput{Kind: "poll", Pages: []notion.Page{
+		{ID: "p2", LastEditedTime: now.Add(-time.Minute), Properties: map[string]notion.PropertyValue{
+			"Name": {Type: "title", Title: []notion.RichText{{PlainText: "Newest"}}},
+		}},
+		{ID: "p1", LastEditedTime: now.Add(-2 * time.Minute)},
+	}})
+	d.webhookReceived(watchOutput{
+		Kind:       "webhook",
+		EventType:  "page.created",
+		ReceivedAt: now,
+		Raw:        []byte(`{"entity":{"id":"p3","type":"page"}}`),
+	})
+	d.pollFailed(errors.New("poll changes: 502 bad gateway"))
+	_, _ = fmt.Fprint(d, "forward failed for poll")
+	_, _ = fmt.Fprint(d, " event\n")
+
+	var frame strings.Builder
+	d.render(&frame, 120, 40)
+	got := frame.String()
+	for _, want := range []string{
+		"notionctl sync watch  ds-1  up 1m30s",
+		"webhooks 1 (on :8914/webhook) | polls 1 | changes 2 | errors 1 | next poll in 1m15s",
+		"webhook  page.created  p3",
+		"poll     Newest  p2",
+		"error: poll changes: 502 bad gateway",
+		"forward failed for poll event",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("frame missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "p3") > strings.Index(got, "p2") || strings.Index(got, "p2") > strings.Index(got, "(untitled)  p1") {
+		t.Fatalf("changes not newest first:\n%s", got)
+	}
+
+	frame.Reset()
+	d.render(&frame, 50, 9)
+	lines := strings.Split(strings.TrimSuffix(frame.String(), "\n"), "\n")
+	if len(lines) >= 9 {
+		t.Fatalf("frame has %d lines for a 9-row terminal:\n%s", len(lines), frame.String())
+	}
+	for _, line := range lines {
+		if n := len([]rune(line)); n > 50 {
+			t.Fatalf("line %q is %d columns wide", line, n)
+		}
+	}
+}
+
+func TestWatchRuntimeKeepsPollingUnderTUI(t *testing.T) {
+	t.Parallel()
+
+	rt := &watchRuntime{opts: &syncWatchOptions{}}
+	failure := errors.New("boom")
+	if err := rt.pollFailed(failure); !errors.Is(err, failure) {
+		t.Fatalf("pollFailed without --tui = %v, want the error", err)
+	}
+	rt.opts.dashboard = newWatchDashboard(rt.opts)
+	if err := rt.pollFailed(failure); err != nil {
+		t.Fatalf("pollFailed with --tui = %v, want nil", err)
+	}
+	if rt.opts.dashboard.failures != 1 {
+		t.Fatalf("failures = %d", rt.opts.dashboard.failures)
+	}
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+const defaultTriageLookback = 24 * time.Hour
+
+//nolint:govet // fieldalignment: struct keeps related CLI options grouped logically.
+type triageOptions struct {
+	dataSourceID string
+	rulesPath    string
+	sinceArg     string
+	lookback     time.Duration
+	pollInterval time.Duration
+	watch        bool
+	dryRun       bool
+
+	rules []triageRule
+	index *schema.Index
+}
+
+func newTriageCmd(globals *globalOptions) *cobra.Command {
+	opts := &triageOptions{
+		lookback:     defaultTriageLookback,
+		pollInterval: defaultPollInterval,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "triage",
+		Short: "Apply rule-based property updates to new or edited pages",
+		RunE:  opts.run(globals),
+	}
+
+	cmd.Flags().Var(newIDValue(&opts.dataSourceID), "data-source-id", "Target Notion data source ID or URL")
+	cmd.Flags().StringVar(&opts.rulesPath, "rules", "", "Path to the YAML triage rules file")
+	cmd.Flags().StringVar(&opts.sinceArg, "since", "", "RFC3339 timestamp of the first change to triage (overrides --lookback)")
+	cmd.Flags().DurationVar(&opts.lookback, "lookback", opts.lookback, "Initial window to triage when --since is omitted")
+	cmd.Flags().BoolVar(&opts.watch, "watch", false, "Keep polling for changes and triage them as they arrive")
+	cmd.Flags().DurationVar(&opts.pollInterval, "poll-interval", opts.pollInterval, "Polling interval in --watch mode")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Report planned updates without applying them")
+
+	cobra.CheckErr(cmd.MarkFlagRequired("data-source-id"))
+	cobra.CheckErr(cmd.MarkFlagRequired("rules"))
+
+	return cmd
+}
+
+func (opts *triageOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		since, err := opts.initialSince()
+		if err != nil {
+			return err
+		}
+
+		rules, err := loadTriageRules(opts.rulesPath)
+		if err != nil {
+			return err
+		}
+		opts.rules = rules
+
+		client, err := buildClient(globals)
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		ds, err := client.GetDataSource(ctx, opts.dataSourceID)
+		if err != nil {
+			return fmt.Errorf("get data source: %w", err)
+		}
+		opts.index = schema.NewIndex(ds)
+		if err := validateTriageRules(opts.rules, opts.index); err != nil {
+			return err
+		}
+
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetEscapeHTML(false)
+
+		until := time.Now().UTC()
+		if err := opts.triageWindow(ctx, client, enc, since, until, false); err != nil {
+			return err
+		}
+		if !opts.watch {
+			return nil
+		}
+		return opts.watchLoop(ctx, client, enc, until)
+	}
+}
+
+func (opts *triageOptions) initialSince() (time.Time, error) {
+	if opts.watch && opts.pollInterval <= 0 {
+		return time.Time{}, errors.New("poll-interval must be greater than zero")
+	}
+	if opts.sinceArg == "" {
+		if opts.lookback <= 0 {
+			return time.Time{}, errors.New("lookback must be greater than zero when --since is omitted")
+		}
+		return time.Now().UTC().Add(-opts.lookback), nil
+	}
+	since, err := time.Parse(time.RFC3339, opts.sinceArg)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse --since: %w", err)
+	}
+	return since.UTC(), nil
+}
+
+func (opts *triageOptions) watchLoop(
+	ctx context.Context,
+	client *notion.Client,
+	enc *json.Encoder,
+	lastPollEnd time.Time,
+) error {
+	ticker := time.NewTicker(opts.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			until := time.Now().UTC()
+			if err := opts.triageWindow(ctx, client, enc, lastPollEnd, until, true); err != nil {
+				return err
+			}
+			lastPollEnd = until
+		}
+	}
+}
+
+func (opts *triageOptions) triageWindow(
+	ctx context.Context,
+	client *notion.Client,
+	enc *json.Encoder,
+	since,
+	until time.Time,
+	lowerExclusive bool,
+) error {
+	pages, err := fetchChanges(ctx, client, opts.dataSourceID, since, until, lowerExclusive)
+	if err != nil {
+		return fmt.Errorf("fetch changes: %w", err)
+	}
+
+	for _, page := range pages {
+		action, err := planTriage(page, opts.rules, opts.index)
+		if err != nil {
+			return fmt.Errorf("triage page %s: %w", page.ID, err)
+		}
+		if len(action.Updates) == 0 {
+			continue
+		}
+		if !opts.dryRun {
+			if _, err := client.UpdatePage(ctx, page.ID, notion.UpdatePageRequest{Properties: action.Updates}); err != nil {
+				return fmt.Errorf("update page %s: %w", page.ID, err)
+			}
+			action.Applied = true
+		}
+		if err := enc.Encode(action); err != nil {
+			return fmt.Errorf("write triage output: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/props"
+	"github.com/yourorg/notionctl/internal/render"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+// triageRuleSet is the on-disk shape of a --rules file.
+type triageRuleSet struct {
+	Rules []triageRule `yaml:"rules"`
+}
+
+// triageRule sets properties on every page matching all of its conditions.
+type triageRule struct {
+	Set  map[string]string `yaml:"set"`
+	Name string            `yaml:"name"`
+	When triageCondition   `yaml:"when"`
+}
+
+// triageCondition lists the checks a page must pass; empty fields are ignored.
+type triageCondition struct {
+	Equals        map[string]string `yaml:"equals"`
+	TitleContains []string          `yaml:"title_contains"`
+	CreatedBy     []string          `yaml:"created_by"`
+	Empty         []string          `yaml:"empty"`
+}
+
+// triageAction captures what the rules decided for a single page.
+type triageAction struct {
+	Updates map[string]any `json:"updates,omitempty"`
+	PageID  string         `json:"page_id"`
+	Rules   []string       `json:"rules"`
+	Applied bool           `json:"applied"`
+}
+
+func loadTriageRules(path string) ([]triageRule, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- reading user-supplied rules file is intentional
+	if err != nil {
+		return nil, fmt.Errorf("read rules: %w", err)
+	}
+	var set triageRuleSet
+	if err := yaml.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("decode rules: %w", err)
+	}
+	if len(set.Rules) == 0 {
+		return nil, errors.New("rules file defines no rules")
+	}
+	for i := range set.Rules {
+		if set.Rules[i].Name == "" {
+			set.Rules[i].Name = fmt.Sprintf("rule-%d", i+1)
+		}
+		if len(set.Rules[i].Set) == 0 {
+			return nil, fmt.Errorf("rule %q has no properties to set", set.Rules[i].Name)
+		}
+	}
+	return set.Rules, nil
+}
+
+// validateTriageRules checks every referenced property against the schema up front so a
+// typo fails before any page is touched.
+func validateTriageRules(rules []triageRule, idx *schema.Index) error {
+	for _, rule := range rules {
+		for name, value := range rule.Set {
+			ref, ok := idx.ReferenceForName(name)
+			if !ok {
+				return fmt.Errorf("rule %q: unknown property %q", rule.Name, name)
+			}
+			if _, err := props.Coerce(ref, value); err != nil {
+				return fmt.Errorf("rule %q: %w", rule.Name, err)
+			}
+		}
+		for name := range rule.When.Equals {
+			if _, ok := idx.ReferenceForName(name); !ok {
+				return fmt.Errorf("rule %q: unknown property %q", rule.Name, name)
+			}
+		}
+		for _, name := range rule.When.Empty {
+			if _, ok := idx.ReferenceForName(name); !ok {
+				return fmt.Errorf("rule %q: unknown property %q", rule.Name, name)
+			}
+		}
+	}
+	return nil
+}
+
+// planTriage evaluates rules in order against page. Later rules override properties set by
+// earlier ones, and properties that already hold the desired value are left alone so the
+// triage's own edits do not retrigger updates.
+func planTriage(page notion.Page, rules []triageRule, idx *schema.Index) (triageAction, error) {
+	action := triageAction{PageID: page.ID}
+	desired := map[string]string{}
+	for _, rule := range rules {
+		if !rule.When.matches(page, idx) {
+			continue
+		}
+		action.Rules = append(action.Rules, rule.Name)
+		for name, value := range rule.Set {
+			ref, _ := idx.ReferenceForName(name)
+			desired[ref.Name] = value
+		}
+	}
+
+	for _, name := range render.SortedKeys(desired) {
+		value := desired[name]
+		if sameTriageValue(summarizeProperty(page.Properties[name]), value) {
+			continue
+		}
+		ref, _ := idx.ReferenceForName(name)
+		payload, err := props.Coerce(ref, value)
+		if err != nil {
+			return triageAction{}, err
+		}
+		if action.Updates == nil {
+			action.Updates = map[string]any{}
+		}
+		action.Updates[name] = payload
+	}
+	return action, nil
+}
+
+func (c triageCondition) matches(page notion.Page, idx *schema.Index) bool {
+	if len(c.TitleContains) > 0 && !containsAnyFold(pageTitle(page), c.TitleContains) {
+		return false
+	}
+	if len(c.CreatedBy) > 0 && !createdByAny(page, c.CreatedBy) {
+		return false
+	}
+	for name, want := range c.Equals {
+		ref, _ := idx.ReferenceForName(name)
+		if !sameTriageValue(summarizeProperty(page.Properties[ref.Name]), want) {
+			return false
+		}
+	}
+	for _, name := range c.Empty {
+		ref, _ := idx.ReferenceForName(name)
+		if summarizeProperty(page.Properties[ref.Name]) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func pageTitle(page notion.Page) string {
+	for _, value := range page.Properties {
+		if value.Type == "title" {
+			return concatRichText(value.Title)
+		}
+	}
+	return ""
+}
+
+func containsAnyFold(text string, keywords []string) bool {
+	lower := strings.ToLower(text)
+	for _, keyword := range keywords {
+		if keyword != "" && strings.Contains(lower, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+func createdByAny(page notion.Page, users []string) bool {
+	candidates := make([]notion.UserReference, 0, 1)
+	if page.CreatedBy != nil {
+		candidates = append(candidates, *page.CreatedBy)
+	}
+	for _, value := range page.Properties {
+		if value.Type == "created_by" && value.CreatedBy != nil {
+			candidates = append(candidates, *value.CreatedBy)
+		}
+	}
+	for _, candidate := range candidates {
+		for _, user := range users {
+			if strings.EqualFold(candidate.ID, user) || (candidate.Name != "" && strings.EqualFold(candidate.Name, user)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameTriageValue compares a property summary with a rule value, ignoring case and the
+// spacing of comma-separated lists.
+func sameTriageValue(summary, want string) bool {
+	return normalizeTriageList(summary) == normalizeTriageList(want)
+}
+
+func normalizeTriageList(value string) string {
+	parts := strings.Split(value, ",")
+	for i := range parts {
+		parts[i] = strings.ToLower(strings.TrimSpace(parts[i]))
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+func TestPlanTriage(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{
+		Properties: map[string]notion.PropertyReference{
+			"Name":     {ID: "title", Name: "Name", Type: "title"},
+			"Team":     {ID: "team", Name: "Team", Type: "select"},
+			"Priority": {ID: "pri", Name: "Priority", Type: "select"},
+		},
+	})
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "triage.yaml")
+	rulesYAML := `rules:
+  - name: infra
+    when:
+      title_contains: [terraform, kubernetes]
+    set:
+      team: Infra
+  - name: vip
+    when:
+      created_by: [user-ceo]
+    set:
+      Priority: P1
+`
+	if err := os.WriteFile(path, []byte(rulesYAML), 0o600); err != nil {
+		t.Fatalf("write rules: %v", err)
+	}
+	rules, err := loadTriageRules(path)
+	if err != nil {
+		t.Fatalf("loadTriageRules returned error: %v", err)
+	}
+	if err := validateTriageRules(rules, idx); err != nil {
+		t.Fatalf("validateTriageRules returned error: %v", err)
+	}
+
+	page := notion.Page{
+		ID:        "page-1",
+		CreatedBy: &notion.UserReference{ID: "user-ceo"},
+		Properties: map[string]notion.PropertyValue{
+			"Name":     {Type: "title", Title: []notion.RichText{{PlainText: "Upgrade Terraform modules"}}},
+			"Team":     {Type: "select"},
+			"Priority": {Type: "select", Select: &notion.SelectValue{Name: "P1"}},
+		},
+	}
+
+	action, err := planTriage(page, rules, idx)
+	if err != nil {
+		t.Fatalf("planTriage returned error: %v", err)
+	}
+	if len(action.Rules) != 2 {
+		t.Fatalf("expected both rules to match, got %v", action.Rules)
+	}
+	if len(action.Updates) != 1 || action.Updates["Team"] == nil {
+		t.Fatalf("expected only Team to change, got %#v", action.Updates)
+	}
+
+	page.Properties["Name"] = notion.PropertyValue{Type: "title", Title: []notion.RichText{{PlainText: "Write docs"}}}
+	page.CreatedBy = &notion.UserReference{ID: "someone-else"}
+	action, err = planTriage(page, rules, idx)
+	if err != nil {
+		t.Fatalf("planTriage returned error: %v", err)
+	}
+	if len(action.Rules) != 0 || len(action.Updates) != 0 {
+		t.Fatalf("expected no matches, got %#v", action)
+	}
+}
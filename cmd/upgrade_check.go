@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/jsondiff"
+	"github.com/yourorg/notionctl/internal/notionid"
+	"github.com/yourorg/notionctl/internal/render"
+)
+
+const (
+	defaultUpgradeSample = 3
+	defaultUpgradeRows   = 3
+
+	upgradeErrorTarget = "fails_on_target"
+	upgradeErrorPinned = "fails_on_pinned"
+)
+
+// upgradeIgnoredPaths differ on every request and say nothing about compatibility.
+var upgradeIgnoredPaths = map[string]bool{"request_id": true}
+
+type upgradeCheckOptions struct {
+	dataSources []string
+	target      string
+	format      string
+	sample      int
+	rows        int
+}
+
+// upgradeFinding is one difference between the responses under the two versions.
+type upgradeFinding struct {
+	DataSource string `json:"data_source_id"`
+	Request    string `json:"request"`
+	Path       string `json:"path,omitempty"`
+	Kind       string `json:"kind"`
+	Pinned     string `json:"pinned,omitempty"`
+	Target     string `json:"target,omitempty"`
+}
+
+// rawRequester is the part of notion.Client upgrade-check needs, so both versions can be
+// queried with identical requests.
+type rawRequester interface {
+	Do(ctx context.Context, method, path string, body any, out any) error
+}
+
+func newUpgradeCheckCmd(globals *globalOptions) *cobra.Command {
+	opts := &upgradeCheckOptions{
+		target: config.DefaultNotionVersion(),
+		format: formatTable,
+		sample: defaultUpgradeSample,
+		rows:   defaultUpgradeRows,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "upgrade-check",
+		Short: "Compare API responses under the profile's pinned and a newer Notion-Version",
+		Long: "Fetch a sample of your data sources and their first rows with both the profile's pinned " +
+			"Notion-Version and the target version, and report fields that were removed, added, retyped, or " +
+			"changed, and requests that fail under only one version.",
+		RunE: opts.run(globals),
+	}
+
+	cmd.Flags().StringSliceVar(
+		&opts.dataSources,
+		"data-source-id",
+		nil,
+		"Data source IDs, URLs, or aliases to check (default: the profile's aliases)",
+	)
+	cmd.Flags().StringVar(&opts.target, "target", opts.target, "Notion-Version to compare against")
+	cmd.Flags().IntVar(&opts.sample, "sample", opts.sample, "Check at most this many aliased data sources")
+	cmd.Flags().IntVar(&opts.rows, "rows", opts.rows, "Rows to query from each data source")
+	formatFlag(cmd, &opts.format, "Output format", formatJSON, formatTable)
+
+	return cmd
+}
+
+func (opts *upgradeCheckOptions) run(globals *globalOptions) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		if opts.format != formatJSON && opts.format != formatTable {
+			return fmt.Errorf("unknown format %q (expected json or table)", opts.format)
+		}
+		if opts.rows <= 0 || opts.rows > maxQueryPageSize {
+			return fmt.Errorf("--rows must be between 1 and %d", maxQueryPageSize)
+		}
+		ids, err := opts.selectDataSources(globals.profile)
+		if err != nil {
+			return err
+		}
+
+		pinned, err := buildClient(globals)
+		if err != nil {
+			return err
+		}
+		target := pinned.Clone()
+		target.SetNotionVersion(opts.target)
+		if pinned.NotionVersion() == opts.target {
+			safeLog(cmd.ErrOrStderr(), "profile %q already uses Notion-Version %s; pass --target to compare another version",
+				globals.profile, opts.target)
+			return nil
+		}
+
+		findings, err := checkUpgrade(cmd.Context(), pinned, target, ids, opts.rows)
+		if err != nil {
+			return err
+		}
+		safeLog(cmd.ErrOrStderr(), "checked %d data sources: %d differences between %s and %s",
+			len(ids), len(findings), pinned.NotionVersion(), opts.target)
+
+		if opts.format == formatJSON {
+			return writeJSON(cmd.Context(), cmd.OutOrStdout(), findings)
+		}
+		rows := make([][]string, 0, len(findings))
+		for _, f := range findings {
+			rows = append(rows, []string{f.DataSource, f.Request, f.Path, f.Kind, f.Pinned, f.Target})
+		}
+		headers := []string{"Data Source", "Request", "Path", "Change", pinned.NotionVersion(), opts.target}
+		return render.Table(cmd.OutOrStdout(), headers, rows)
+	}
+}
+
+// selectDataSources resolves --data-source-id values, or samples the profile's aliases.
+func (opts *upgradeCheckOptions) selectDataSources(profile string) ([]string, error) {
+	settings, err := config.LoadDataSourceSettings(profile)
+	if err != nil {
+		return nil, fmt.Errorf("load data source aliases: %w", err)
+	}
+	refs := opts.dataSources
+	if len(refs) == 0 {
+		for _, alias := range render.SortedKeys(settings.Aliases) {
+			refs = append(refs, settings.Aliases[alias])
+		}
+	}
+
+	var ids []string
+	seen := map[string]bool{}
+	for _, ref := range refs {
+		raw, _ := settings.ResolveDataSource(ref)
+		id, err := notionid.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("resolve data source %q: %w", ref, err)
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+		if len(opts.dataSources) == 0 && len(ids) == opts.sample {
+			break
+		}
+	}
+	if len(ids) == 0 {
+		return nil, errors.New("no data sources to check; pass --data-source-id or add aliases with ds alias set")
+	}
+	return ids, nil
+}
+
+// checkUpgrade sends the same requests under both versions and diffs the responses.
+func checkUpgrade(ctx context.Context, pinned, target rawRequester, ids []string, rows int) ([]upgradeFinding, error) {
+	var findings []upgradeFinding
+	for _, id := range ids {
+		requests := []struct {
+			method string
+			path   string
+			body   any
+		}{
+			{http.MethodGet, path.Join("data_sources", id), nil},
+			{http.MethodPost, path.Join("data_sources", id, "query"), map[string]any{"page_size": rows}},
+		}
+		for _, req := range requests {
+			label := req.method + " " + strings.Replace(req.path, id, "{id}", 1)
+			var before, after any
+			beforeErr := pinned.Do(ctx, req.method, req.path, req.body, &before)
+			afterErr := target.Do(ctx, req.method, req.path, req.body, &after)
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("upgrade check canceled: %w", ctx.Err())
+			}
+			switch {
+			case beforeErr != nil && afterErr != nil:
+				return nil, fmt.Errorf("%s for %s fails under both versions: %w", label, id, afterErr)
+			case afterErr != nil:
+				findings = append(findings, upgradeFinding{
+					DataSource: id, Request: label, Kind: upgradeErrorTarget, Pinned: "ok", Target: afterErr.Error(),
+				})
+				continue
+			case beforeErr != nil:
+				findings = append(findings, upgradeFinding{
+					DataSource: id, Request: label, Kind: upgradeErrorPinned, Pinned: beforeErr.Error(), Target: "ok",
+				})
+				continue
+			}
+			for _, change := range jsondiff.Diff(before, after) {
+				if upgradeIgnoredPaths[change.Path] {
+					continue
+				}
+				findings = append(findings, upgradeFinding{
+					DataSource: id,
+					Request:    label,
+					Path:       change.Path,
+					Kind:       change.Kind,
+					Pinned:     findingValue(change.Old),
+					Target:     findingValue(change.New),
+				})
+			}
+		}
+	}
+	return findings, nil
+}
+
+func findingValue(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type cannedRequester map[string]string
+
+func (c cannedRequester) Do(_ context.Context, method, path string, _ any, out any) error {
+	raw, ok := c[method+" "+path]
+	if !ok {
+		return errors.New("notion: invalid_request_url")
+	}
+	return json.Unmarshal([]byte(raw), out)
+}
+
+func TestCheckUpgrade(t *testing.T) {
+	pinned := cannedRequester{
+		"GET data_sources/ds1":        `{"object":"data_source","request_id":"a","title":[{"plain_text":"Tasks"}]}`,
+		"POST data_sources/ds1/query": `{"results":[{"parent":{"type":"database_id"}},{"parent":{"type":"database_id"}}]}`,
+	}
+	target := cannedRequester{
+		"GET data_sources/ds1":        `{"object":"data_source","request_id":"b","title":[{"plain_text":"Tasks"}],"icon":null}`,
+		"POST data_sources/ds1/query": `{"results":[{"parent":{"type":"data_source_id"}},{"parent":{"type":"data_source_id"}}]}`,
+	}
+
+	findings, err := checkUpgrade(context.Background(), pinned, target, []string{"ds1"}, 3)
+	if err != nil {
+		t.Fatalf("checkUpgrade returned error: %v", err)
+	}
+	want := []upgradeFinding{
+		{DataSource: "ds1", Request: "GET data_sources/{id}", Path: "icon", Kind: "added"},
+		{
+			DataSource: "ds1", Request: "POST data_sources/{id}/query", Path: "results[].parent.type",
+			Kind: "changed", Pinned: "database_id", Target: "data_source_id",
+		},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("expected %d findings, got %#v", len(want), findings)
+	}
+	for i := range want {
+		if findings[i] != want[i] {
+			t.Fatalf("finding %d = %#v, want %#v", i, findings[i], want[i])
+		}
+	}
+}
+
+func TestCheckUpgradeReportsOneSidedFailures(t *testing.T) {
+	pinned := cannedRequester{
+		"GET data_sources/ds1":        `{"object":"data_source"}`,
+		"POST data_sources/ds1/query": `{"results":[]}`,
+	}
+	target := cannedRequester{"GET data_sources/ds1": `{"object":"data_source"}`}
+
+	findings, err := checkUpgrade(context.Background(), pinned, target, []string{"ds1"}, 3)
+	if err != nil {
+		t.Fatalf("checkUpgrade returned error: %v", err)
+	}
+	if len(findings) != 1 || findings[0].Kind != upgradeErrorTarget || !strings.Contains(findings[0].Target, "invalid_request_url") {
+		t.Fatalf("unexpected findings: %#v", findings)
+	}
+
+	if _, err := checkUpgrade(context.Background(), cannedRequester{}, cannedRequester{}, []string{"ds1"}, 3); err == nil {
+		t.Fatalf("expected an error when both versions fail")
+	}
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/yourorg/notionctl/internal/filelock"
+)
+
+// DataSourceSettings captures the data source shortcuts stored for a profile.
+type DataSourceSettings struct {
+	Aliases map[string]string
+	Default string
+}
+
+// SaveDataSourceAlias records a named shortcut for a data source ID. When makeDefault is
+// set the alias also becomes the profile's default data source.
+func SaveDataSourceAlias(profile, alias, dataSourceID string, makeDefault bool) error {
+	if profile == "" {
+		return errors.New("profile name cannot be empty")
+	}
+	alias = normalizeAlias(alias)
+	if alias == "" {
+		return errors.New("alias cannot be empty")
+	}
+	dataSourceID = strings.TrimSpace(dataSourceID)
+	if dataSourceID == "" {
+		return errors.New("data source ID cannot be empty")
+	}
+
+	return updateConfig(func(cfg *viper.Viper) {
+		cfg.Set(fmt.Sprintf("profiles.%s.aliases.%s", profile, alias), dataSourceID)
+		if makeDefault {
+			cfg.Set(fmt.Sprintf("profiles.%s.default_data_source", profile), alias)
+		}
+	})
+}
+
+// LoadDataSourceSettings returns the aliases and default data source configured for a profile.
+func LoadDataSourceSettings(profile string) (DataSourceSettings, error) {
+	if profile == "" {
+		return DataSourceSettings{}, errors.New("profile name cannot be empty")
+	}
+
+	cfg, err := readConfig()
+	if err != nil {
+		return DataSourceSettings{}, err
+	}
+
+	settings := DataSourceSettings{Aliases: map[string]string{}}
+	if cfg == nil {
+		return settings, nil
+	}
+	for alias, id := range cfg.GetStringMapString(fmt.Sprintf("profiles.%s.aliases", profile)) {
+		settings.Aliases[normalizeAlias(alias)] = id
+	}
+	settings.Default = cfg.GetString(fmt.Sprintf("profiles.%s.default_data_source", profile))
+	return settings, nil
+}
+
+// ResolveDataSource maps an alias (or a raw ID) to a data source ID. An empty name resolves
+// to the profile's default data source, if one is configured.
+func (s DataSourceSettings) ResolveDataSource(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = s.Default
+	}
+	if name == "" {
+		return "", false
+	}
+	if id, ok := s.Aliases[normalizeAlias(name)]; ok {
+		return id, true
+	}
+	return name, true
+}
+
+// readConfig loads config.yaml, returning nil when it does not exist yet.
+func readConfig() (*viper.Viper, error) {
+	dir, err := ensureConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := viper.New()
+	cfg.SetConfigFile(filepath.Join(dir, "config.yaml"))
+	if err := cfg.ReadInConfig(); err != nil {
+		if isConfigNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	return cfg, nil
+}
+
+// updateConfig applies fn to config.yaml while holding its lock, so concurrent invocations
+// never drop each other's changes, and replaces the file atomically.
+func updateConfig(fn func(cfg *viper.Viper)) error {
+	return rewriteConfig(func(cfg *viper.Viper) (map[string]any, error) {
+		fn(cfg)
+		return cfg.AllSettings(), nil
+	})
+}
+
+// rewriteConfig is updateConfig for edits viper cannot express, such as removing keys: fn
+// returns the complete settings to write.
+func rewriteConfig(fn func(cfg *viper.Viper) (map[string]any, error)) error {
+	dir, err := ensureConfigDir()
+	if err != nil {
+		return err
+	}
+	configPath := filepath.Join(dir, "config.yaml")
+	return filelock.With(configPath, func() error {
+		cfg := viper.New()
+		cfg.SetConfigFile(configPath)
+		if err := cfg.ReadInConfig(); err != nil && !isConfigNotFound(err) {
+			return fmt.Errorf("read config: %w", err)
+		}
+		settings, err := fn(cfg)
+		if err != nil {
+			return err
+		}
+		data, err := yaml.Marshal(settings)
+		if err != nil {
+			return fmt.Errorf("encode config: %w", err)
+		}
+		if err := filelock.WriteFile(configPath, data, filePermissions); err != nil {
+			return fmt.Errorf("write config: %w", err)
+		}
+		return nil
+	})
+}
+
+func normalizeAlias(alias string) string {
+	return strings.ToLower(strings.TrimSpace(alias))
+}
@@ -1,4 +1,4 @@
-// Package config manages disk and keyring state for notionctl profiles.
+// Package config manages disk and credential store state for notionctl profiles.
 package config
 
 import (
@@ -9,7 +9,6 @@ import (
 	"strings"
 
 	"github.com/spf13/viper"
-	"github.com/zalando/go-keyring"
 )
 
 const (
@@ -46,7 +45,8 @@ func ensureConfigDir() (string, error) {
 	return dir, nil
 }
 
-// SaveToken stores the integration token for the provided profile in the OS keyring.
+// SaveToken stores the integration token for the provided profile in the configured
+// credential store (the OS keyring by default).
 // It also records the Notion API version alongside the credential metadata.
 func SaveToken(profile, token, version string) error {
 	token = strings.TrimSpace(token)
@@ -60,7 +60,11 @@ func SaveToken(profile, token, version string) error {
 		version = defaultNotionVersion
 	}
 
-	if err := keyring.Set(serviceName, profile, token); err != nil {
+	store, err := credentials()
+	if err != nil {
+		return err
+	}
+	if err := store.Set(profile, token); err != nil {
 		return fmt.Errorf("save token: %w", err)
 	}
 	if err := SaveVersion(profile, version); err != nil {
@@ -78,29 +82,10 @@ func SaveVersion(profile, version string) error {
 		version = defaultNotionVersion
 	}
 
-	dir, err := ensureConfigDir()
-	if err != nil {
-		return err
-	}
-
-	cfg := viper.New()
-	configPath := filepath.Join(dir, "config.yaml")
-	cfg.SetConfigFile(configPath)
-	readErr := cfg.ReadInConfig()
-	if readErr != nil && !isConfigNotFound(readErr) {
-		return fmt.Errorf("read config: %w", readErr)
-	}
-
 	key := fmt.Sprintf("profiles.%s.notion_version", profile)
-	cfg.Set(key, version)
-
-	if err := cfg.WriteConfigAs(configPath); err != nil {
-		return fmt.Errorf("write config: %w", err)
-	}
-	if err := os.Chmod(configPath, filePermissions); err != nil {
-		return fmt.Errorf("restrict config permissions: %w", err)
-	}
-	return nil
+	return updateConfig(func(cfg *viper.Viper) {
+		cfg.Set(key, version)
+	})
 }
 
 // LoadAuth returns the stored token and Notion API version for a profile.
@@ -109,9 +94,13 @@ func LoadAuth(profile string) (token, notionVersion string, err error) {
 		return "", "", errors.New("profile name cannot be empty")
 	}
 
-	tok, err := keyring.Get(serviceName, profile)
+	store, err := credentials()
+	if err != nil {
+		return "", "", err
+	}
+	tok, err := store.Get(profile)
 	if err != nil {
-		if errors.Is(err, keyring.ErrNotFound) {
+		if errors.Is(err, ErrTokenNotFound) {
 			return "", "", fmt.Errorf("load token: no stored credentials for profile %q", profile)
 		}
 		return "", "", fmt.Errorf("load token: %w", err)
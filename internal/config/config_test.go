@@ -1,14 +1,19 @@
 package config_test
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/zalando/go-keyring"
 
 	"github.com/yourorg/notionctl/internal/config"
+	"github.com/yourorg/notionctl/internal/notion"
 )
 
 func TestSaveAndLoadToken(t *testing.T) {
@@ -100,3 +105,503 @@ func setupHome(t *testing.T) string {
 	t.Setenv("HOME", home)
 	return home
 }
+
+func TestDataSourceAliases(t *testing.T) {
+	setupHome(t)
+	keyring.MockInit()
+
+	if err := config.SaveDataSourceAlias("default", "Tasks", "ds-tasks", true); err != nil {
+		t.Fatalf("SaveDataSourceAlias returned error: %v", err)
+	}
+	if err := config.SaveDataSourceAlias("default", "bugs", "ds-bugs", false); err != nil {
+		t.Fatalf("SaveDataSourceAlias returned error: %v", err)
+	}
+
+	settings, err := config.LoadDataSourceSettings("default")
+	if err != nil {
+		t.Fatalf("LoadDataSourceSettings returned error: %v", err)
+	}
+	if id, ok := settings.ResolveDataSource(""); !ok || id != "ds-tasks" {
+		t.Fatalf("default data source = %q,%v", id, ok)
+	}
+	if id, ok := settings.ResolveDataSource("BUGS"); !ok || id != "ds-bugs" {
+		t.Fatalf("ResolveDataSource(BUGS) = %q,%v", id, ok)
+	}
+	if id, ok := settings.ResolveDataSource("raw-id"); !ok || id != "raw-id" {
+		t.Fatalf("ResolveDataSource(raw-id) = %q,%v", id, ok)
+	}
+}
+
+func TestRecordAppliedMigration(t *testing.T) {
+	setupHome(t)
+
+	const dataSource = "1A2B3C4D-5E6F-7A8B-9C0D-1E2F3A4B5C6D"
+	for _, name := range []string{"001_init", "002_quarter", "001_init"} {
+		if err := config.RecordAppliedMigration("work", dataSource, name); err != nil {
+			t.Fatalf("RecordAppliedMigration(%q) returned error: %v", name, err)
+		}
+	}
+
+	applied, err := config.LoadAppliedMigrations("work", strings.ToLower(dataSource))
+	if err != nil {
+		t.Fatalf("LoadAppliedMigrations returned error: %v", err)
+	}
+	if len(applied) != 2 || applied[0] != "001_init" || applied[1] != "002_quarter" {
+		t.Fatalf("unexpected applied migrations: %#v", applied)
+	}
+
+	other, err := config.LoadAppliedMigrations("personal", dataSource)
+	if err != nil {
+		t.Fatalf("LoadAppliedMigrations returned error: %v", err)
+	}
+	if len(other) != 0 {
+		t.Fatalf("expected no migrations for another profile, got %#v", other)
+	}
+}
+
+func TestSchemaCacheRoundTrip(t *testing.T) {
+	setupHome(t)
+
+	if _, ok, err := config.LoadSchema("default", "ds-1"); err != nil || ok {
+		t.Fatalf("expected no cached schema, got ok=%v err=%v", ok, err)
+	}
+
+	ds := notion.DataSource{
+		ID:   "ds-1",
+		Name: "Tasks",
+		Properties: map[string]notion.PropertyReference{
+			"Name": {ID: "title", Name: "Name", Type: "title"},
+		},
+	}
+	if err := config.SaveSchema("default", ds); err != nil {
+		t.Fatalf("SaveSchema returned error: %v", err)
+	}
+	got, ok, err := config.LoadSchema("default", "DS-1")
+	if err != nil || !ok {
+		t.Fatalf("LoadSchema returned ok=%v err=%v", ok, err)
+	}
+	if got.Name != "Tasks" || got.Properties["Name"].Type != "title" {
+		t.Fatalf("unexpected cached schema: %+v", got)
+	}
+	if _, _, err := config.LoadSchema("other", "ds-1"); err != nil {
+		t.Fatalf("other profiles should simply miss: %v", err)
+	}
+	if err := config.SaveSchema("default", notion.DataSource{ID: "../escape"}); err == nil {
+		t.Fatal("expected path separators in the ID to be rejected")
+	}
+}
+
+func TestUsageLogToggle(t *testing.T) {
+	setupHome(t)
+
+	if enabled, err := config.UsageLogEnabled(); err != nil || enabled {
+		t.Fatalf("usage log should be off by default, got %v %v", enabled, err)
+	}
+	if err := config.SetUsageLog(true); err != nil {
+		t.Fatalf("SetUsageLog: %v", err)
+	}
+	if enabled, err := config.UsageLogEnabled(); err != nil || !enabled {
+		t.Fatalf("expected usage log on, got %v %v", enabled, err)
+	}
+	path, err := config.UsageLogPath()
+	if err != nil || filepath.Base(path) != "usage.jsonl" {
+		t.Fatalf("unexpected usage log path %q (%v)", path, err)
+	}
+}
+
+func TestConcurrentConfigWritesKeepEveryChange(t *testing.T) {
+	home := setupHome(t)
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			alias := fmt.Sprintf("alias%d", i)
+			if err := config.SaveDataSourceAlias("default", alias, "ds-"+alias, false); err != nil {
+				t.Errorf("SaveDataSourceAlias(%s) returned error: %v", alias, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	settings, err := config.LoadDataSourceSettings("default")
+	if err != nil {
+		t.Fatalf("LoadDataSourceSettings returned error: %v", err)
+	}
+	if len(settings.Aliases) != 10 {
+		t.Fatalf("expected 10 aliases after concurrent writes, got %v", settings.Aliases)
+	}
+	info, err := os.Stat(filepath.Join(home, ".config", "notionctl", "config.yaml"))
+	if err != nil {
+		t.Fatalf("stat config: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("expected config permissions 0600, got %o", perm)
+	}
+}
+
+func TestWorkspaceRoundTrip(t *testing.T) {
+	setupHome(t)
+
+	if _, ok, err := config.LoadWorkspace("default"); err != nil || ok {
+		t.Fatalf("expected no workspace before login, got ok=%v err=%v", ok, err)
+	}
+	want := config.Workspace{ID: "w1", Name: "Acme", BotID: "b1"}
+	if err := config.SaveWorkspace("default", want); err != nil {
+		t.Fatalf("SaveWorkspace returned error: %v", err)
+	}
+	got, ok, err := config.LoadWorkspace("default")
+	if err != nil || !ok || got != want {
+		t.Fatalf("LoadWorkspace = %#v, %v, %v", got, ok, err)
+	}
+}
+
+func TestProfileRenameAndDelete(t *testing.T) {
+	home := setupHome(t)
+	keyring.MockInit()
+
+	if err := config.SaveToken("work", "secret_work", "2025-10-01"); err != nil {
+		t.Fatalf("SaveToken returned error: %v", err)
+	}
+	if err := config.SaveDataSourceAlias("work", "tasks", "ds-tasks", true); err != nil {
+		t.Fatalf("SaveDataSourceAlias returned error: %v", err)
+	}
+	if err := config.SaveToken("home", "secret_home", ""); err != nil {
+		t.Fatalf("SaveToken returned error: %v", err)
+	}
+	schemaDir := filepath.Join(home, ".config", "notionctl", "schemas", "work")
+	if err := os.MkdirAll(schemaDir, 0o700); err != nil {
+		t.Fatalf("create schema dir: %v", err)
+	}
+
+	if err := config.RenameProfile("work", "home"); err == nil {
+		t.Fatalf("RenameProfile onto an existing profile expected error")
+	}
+	if err := config.RenameProfile("work", "acme"); err != nil {
+		t.Fatalf("RenameProfile returned error: %v", err)
+	}
+
+	profiles, err := config.ListProfiles()
+	if err != nil {
+		t.Fatalf("ListProfiles returned error: %v", err)
+	}
+	if len(profiles) != 2 || profiles[0].Name != "acme" || profiles[1].Name != "home" {
+		t.Fatalf("ListProfiles = %+v", profiles)
+	}
+	acme := profiles[0]
+	if !acme.HasToken || acme.NotionVersion != "2025-10-01" || acme.DefaultDataSource != "tasks" || acme.Aliases != 1 {
+		t.Fatalf("renamed profile = %+v", acme)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".config", "notionctl", "schemas", "acme")); err != nil {
+		t.Fatalf("schema cache not moved: %v", err)
+	}
+	if _, _, err := config.LoadAuth("work"); err == nil {
+		t.Fatalf("expected old profile token to be gone")
+	}
+
+	if err := config.DeleteProfile("acme"); err != nil {
+		t.Fatalf("DeleteProfile returned error: %v", err)
+	}
+	if err := config.DeleteProfile("acme"); err == nil {
+		t.Fatalf("DeleteProfile of a missing profile expected error")
+	}
+	profiles, err = config.ListProfiles()
+	if err != nil {
+		t.Fatalf("ListProfiles returned error: %v", err)
+	}
+	if len(profiles) != 1 || profiles[0].Name != "home" {
+		t.Fatalf("ListProfiles after delete = %+v", profiles)
+	}
+}
+
+func TestFileCredentialStore(t *testing.T) {
+	home := setupHome(t)
+	t.Setenv(config.PassphraseEnv, "correct horse")
+
+	if err := config.SetCredentialStore("vault"); err == nil {
+		t.Fatalf("SetCredentialStore with an unknown store expected error")
+	}
+	if err := config.SetCredentialStore(config.StoreFile); err != nil {
+		t.Fatalf("SetCredentialStore returned error: %v", err)
+	}
+	if store, err := config.CredentialStore(); err != nil || store != config.StoreFile {
+		t.Fatalf("CredentialStore = %q, %v", store, err)
+	}
+
+	if err := config.SaveToken("work", "secret_file_token", ""); err != nil {
+		t.Fatalf("SaveToken returned error: %v", err)
+	}
+	if err := config.SaveToken("home", "secret_home", ""); err != nil {
+		t.Fatalf("SaveToken returned error: %v", err)
+	}
+	token, _, err := config.LoadAuth("work")
+	if err != nil || token != "secret_file_token" {
+		t.Fatalf("LoadAuth = %q, %v", token, err)
+	}
+
+	path := filepath.Join(home, ".config", "notionctl", "credentials.enc")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read credentials file: %v", err)
+	}
+	if strings.Contains(string(data), "secret_") {
+		t.Fatalf("credentials file contains a plaintext token: %s", data)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("credentials file mode = %v, %v", info, err)
+	}
+
+	if err := config.DeleteProfile("home"); err != nil {
+		t.Fatalf("DeleteProfile returned error: %v", err)
+	}
+	if _, _, err := config.LoadAuth("home"); err == nil {
+		t.Fatalf("expected deleted profile to have no token")
+	}
+
+	t.Setenv(config.PassphraseEnv, "wrong")
+	if _, _, err := config.LoadAuth("work"); err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Fatalf("expected a wrong passphrase error, got %v", err)
+	}
+}
+
+func TestProfileDefaults(t *testing.T) {
+	setupHome(t)
+
+	if err := config.SetProfileDefault("work", config.DefaultFormat, "json"); err != nil {
+		t.Fatalf("SetProfileDefault(format) returned error: %v", err)
+	}
+	if err := config.SetProfileDefault("work", config.DefaultPageSize, "25"); err != nil {
+		t.Fatalf("SetProfileDefault(page_size) returned error: %v", err)
+	}
+	if err := config.SetProfileDefault("work", config.DefaultDataSource, "tasks"); err != nil {
+		t.Fatalf("SetProfileDefault(data_source_id) returned error: %v", err)
+	}
+	if err := config.SetProfileDefault("work", config.DefaultPageSize, "500"); err == nil {
+		t.Fatalf("expected an out-of-range page_size to be rejected")
+	}
+	if err := config.SetProfileDefault("work", "color", "blue"); err == nil {
+		t.Fatalf("expected an unknown default to be rejected")
+	}
+
+	got, err := config.LoadProfileDefaults("work")
+	if err != nil {
+		t.Fatalf("LoadProfileDefaults returned error: %v", err)
+	}
+	if want := (config.ProfileDefaults{Format: "json", PageSize: 25, DataSource: "tasks"}); got != want {
+		t.Fatalf("LoadProfileDefaults = %+v, want %+v", got, want)
+	}
+
+	if err := config.UnsetProfileDefault("work", config.DefaultFormat); err != nil {
+		t.Fatalf("UnsetProfileDefault returned error: %v", err)
+	}
+	if got, _ := config.LoadProfileDefaults("work"); got.Format != "" || got.PageSize != 25 {
+		t.Fatalf("after unset, defaults = %+v", got)
+	}
+
+	// The data source default is the one ds alias set --default writes.
+	if settings, _ := config.LoadDataSourceSettings("work"); settings.Default != "tasks" {
+		t.Fatalf("default data source = %q, want tasks", settings.Default)
+	}
+	if err := config.SaveDataSourceAlias("work", "notes", "ds-notes", true); err != nil {
+		t.Fatalf("SaveDataSourceAlias returned error: %v", err)
+	}
+	if got, _ := config.LoadProfileDefaults("work"); got.DataSource != "notes" {
+		t.Fatalf("after alias --default, data source default = %q, want notes", got.DataSource)
+	}
+	if err := config.UnsetProfileDefault("work", config.DefaultDataSource); err != nil {
+		t.Fatalf("UnsetProfileDefault(data_source_id) returned error: %v", err)
+	}
+	if settings, _ := config.LoadDataSourceSettings("work"); settings.Default != "" || settings.Aliases["notes"] != "ds-notes" {
+		t.Fatalf("after unset, data source settings = %+v", settings)
+	}
+}
+
+func TestExportImportProfile(t *testing.T) {
+	setupHome(t)
+	keyring.MockInit()
+
+	if err := config.SaveToken("work", "secret_work", "2025-10-01"); err != nil {
+		t.Fatalf("SaveToken returned error: %v", err)
+	}
+	if err := config.SaveDataSourceAlias("work", "tasks", "ds-tasks", true); err != nil {
+		t.Fatalf("SaveDataSourceAlias returned error: %v", err)
+	}
+	data, err := config.ExportProfile("work", "hunter2")
+	if err != nil {
+		t.Fatalf("ExportProfile returned error: %v", err)
+	}
+	if strings.Contains(string(data), "secret_work") {
+		t.Fatalf("export contains the plaintext token:\n%s", data)
+	}
+
+	if _, err := config.ImportProfile(data, "wrong", "copy", false); err == nil {
+		t.Fatalf("expected a wrong passphrase to fail")
+	}
+	for field, value := range map[string]any{
+		"nonce":      "AAAA",
+		"salt":       "",
+		"iterations": 1,
+		"kdf":        "scrypt",
+	} {
+		var bundle map[string]any
+		if err := json.Unmarshal(data, &bundle); err != nil {
+			t.Fatalf("decode bundle: %v", err)
+		}
+		bundle["token"].(map[string]any)[field] = value
+		tampered, err := json.Marshal(bundle)
+		if err != nil {
+			t.Fatalf("encode bundle: %v", err)
+		}
+		if _, err := config.ImportProfile(tampered, "hunter2", "copy", false); err == nil {
+			t.Fatalf("expected a bundle with a bad %s to be rejected", field)
+		}
+	}
+	if _, err := config.ImportProfile(data, "hunter2", "", false); err == nil {
+		t.Fatalf("expected importing over an existing profile to fail without overwrite")
+	}
+	name, err := config.ImportProfile(data, "hunter2", "copy", false)
+	if err != nil || name != "copy" {
+		t.Fatalf("ImportProfile = %q, %v", name, err)
+	}
+	token, version, err := config.LoadAuth("copy")
+	if err != nil || token != "secret_work" || version != "2025-10-01" {
+		t.Fatalf("LoadAuth(copy) = %q, %q, %v", token, version, err)
+	}
+	settings, err := config.LoadDataSourceSettings("copy")
+	if err != nil || settings.Aliases["tasks"] != "ds-tasks" || settings.Default != "tasks" {
+		t.Fatalf("imported aliases = %+v, %v", settings, err)
+	}
+}
+
+func TestProfileEndpoint(t *testing.T) {
+	setupHome(t)
+
+	if err := config.SaveEndpoint("work", config.Endpoint{BaseURL: "gateway.local"}); err == nil {
+		t.Fatalf("expected a relative base URL to be rejected")
+	}
+	if err := config.SaveEndpoint("work", config.Endpoint{Headers: map[string]string{"authorization": "x"}}); err == nil {
+		t.Fatalf("expected an Authorization header to be rejected")
+	}
+	endpoint := config.Endpoint{
+		BaseURL: "https://gateway.example.com/notion/v1",
+		Headers: map[string]string{"X-Gateway-Key": "k1"},
+	}
+	if err := config.SaveEndpoint("work", endpoint); err != nil {
+		t.Fatalf("SaveEndpoint returned error: %v", err)
+	}
+	got, err := config.LoadEndpoint("work")
+	if err != nil || got.BaseURL != endpoint.BaseURL || got.Headers["X-Gateway-Key"] != "k1" {
+		t.Fatalf("LoadEndpoint = %+v, %v", got, err)
+	}
+
+	if err := config.SaveEndpoint("work", config.Endpoint{}); err != nil {
+		t.Fatalf("SaveEndpoint (clear) returned error: %v", err)
+	}
+	if got, err := config.LoadEndpoint("work"); err != nil || got.BaseURL != "" || len(got.Headers) != 0 {
+		t.Fatalf("LoadEndpoint after clear = %+v, %v", got, err)
+	}
+
+	if err := config.SaveEndpoint("work", config.Endpoint{ProxyURL: "proxy.corp:8080"}); err == nil {
+		t.Fatalf("expected a proxy URL without a scheme to be rejected")
+	}
+	if err := config.SaveEndpoint("work", config.Endpoint{CABundle: "missing.pem"}); err == nil {
+		t.Fatalf("expected a missing CA bundle to be rejected")
+	}
+	network := config.Endpoint{ProxyURL: "http://proxy.corp:8080", InsecureSkipVerify: true}
+	if err := config.SaveEndpoint("work", network); err != nil {
+		t.Fatalf("SaveEndpoint (network) returned error: %v", err)
+	}
+	if got, err := config.LoadEndpoint("work"); err != nil || got.ProxyURL != network.ProxyURL || !got.InsecureSkipVerify {
+		t.Fatalf("LoadEndpoint (network) = %+v, %v", got, err)
+	}
+}
+
+func TestLoadEndpointRejectsHandEditedBaseURL(t *testing.T) {
+	home := setupHome(t)
+
+	configPath := filepath.Join(home, ".config", "notionctl", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o750); err != nil {
+		t.Fatalf("create config dir: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte("profiles:\n  work:\n    base_url: \"://broken\"\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := config.LoadEndpoint("work"); err == nil || !strings.Contains(err.Error(), "base_url") {
+		t.Fatalf("LoadEndpoint with a bad base_url = %v, want an error", err)
+	}
+}
+
+func TestProfileLimits(t *testing.T) {
+	setupHome(t)
+
+	for key, value := range map[string]string{
+		"rps": "0", "max_retries": "-1", "backoff_base": "soon", "breaker_threshold": "0", "concurrency": "0", "burst": "2",
+	} {
+		if err := config.SetLimit("work", key, value); err == nil {
+			t.Fatalf("expected %s=%s to be rejected", key, value)
+		}
+	}
+	for key, value := range map[string]string{
+		"rps": "10", "max_retries": "0", "backoff_base": "250ms", "breaker_threshold": "25", "concurrency": "8",
+	} {
+		if err := config.SetLimit("work", key, value); err != nil {
+			t.Fatalf("SetLimit(%s) returned error: %v", key, err)
+		}
+	}
+	limits, err := config.LoadLimits("work")
+	if err != nil || limits.RPS != 10 || limits.MaxRetries == nil || *limits.MaxRetries != 0 ||
+		limits.BackoffBase != 250*time.Millisecond || limits.BreakerThreshold != 25 || limits.Concurrency != 8 {
+		t.Fatalf("LoadLimits = %+v, %v", limits, err)
+	}
+
+	if err := config.UnsetLimit("work", "max_retries"); err != nil {
+		t.Fatalf("UnsetLimit returned error: %v", err)
+	}
+	if limits, err := config.LoadLimits("work"); err != nil || limits.MaxRetries != nil || limits.RPS != 10 {
+		t.Fatalf("LoadLimits after unset = %+v, %v", limits, err)
+	}
+}
+
+func TestRecordRecent(t *testing.T) {
+	setupHome(t)
+
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	record := func(kind, id, label string, offset time.Duration) {
+		t.Helper()
+		item := config.RecentItem{Kind: kind, ID: id, Label: label, UsedAt: base.Add(offset)}
+		if err := config.RecordRecent("default", item); err != nil {
+			t.Fatalf("RecordRecent returned error: %v", err)
+		}
+	}
+	record(config.RecentDataSource, "ds-1", "Tasks", 0)
+	record(config.RecentPage, "page-1", "Launch", time.Minute)
+	record(config.RecentDataSource, "ds-2", "Bugs", 2*time.Minute)
+	record(config.RecentDataSource, "DS-1", "", 3*time.Minute)
+
+	items, err := config.LoadRecent("default", config.RecentDataSource)
+	if err != nil {
+		t.Fatalf("LoadRecent returned error: %v", err)
+	}
+	if len(items) != 2 || items[0].ID != "DS-1" || items[0].Label != "Tasks" || items[1].ID != "ds-2" {
+		t.Fatalf("recent data sources = %+v", items)
+	}
+	pages, err := config.LoadRecent("default", config.RecentPage)
+	if err != nil || len(pages) != 1 || pages[0].Label != "Launch" {
+		t.Fatalf("recent pages = %+v, %v", pages, err)
+	}
+	if other, err := config.LoadRecent("work", config.RecentPage); err != nil || len(other) != 0 {
+		t.Fatalf("other profile = %+v, %v", other, err)
+	}
+
+	for i := range 60 {
+		record(config.RecentPage, fmt.Sprintf("p-%d", i), "", time.Hour+time.Duration(i)*time.Second)
+	}
+	pages, err = config.LoadRecent("default", config.RecentPage)
+	if err != nil || len(pages) != 50 || pages[0].ID != "p-59" {
+		t.Fatalf("expected the 50 newest pages, got %d (first %+v), %v", len(pages), pages[0], err)
+	}
+	if items, _ := config.LoadRecent("default", config.RecentDataSource); len(items) != 2 {
+		t.Fatalf("capping pages dropped data sources: %+v", items)
+	}
+}
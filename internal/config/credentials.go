@@ -0,0 +1,330 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+	"github.com/zalando/go-keyring"
+
+	"github.com/yourorg/notionctl/internal/filelock"
+)
+
+// Credential stores selectable with SetCredentialStore or NOTIONCTL_CREDENTIAL_STORE.
+const (
+	StoreKeyring = "keyring"
+	StoreFile    = "file"
+
+	// CredentialStoreEnv overrides the credential_store config setting.
+	CredentialStoreEnv = "NOTIONCTL_CREDENTIAL_STORE"
+	// PassphraseEnv supplies the file store passphrase without prompting.
+	PassphraseEnv = "NOTIONCTL_PASSPHRASE"
+
+	credentialsFileName = "credentials.enc"
+	credentialsKDF      = "pbkdf2-sha256"
+	kdfIterations       = 600_000
+	saltSize            = 16
+	keySize             = 32
+
+	// maxKDFIterations bounds what a file may ask for, so a hostile one cannot stall the CLI.
+	maxKDFIterations = 10 * kdfIterations
+)
+
+// ErrTokenNotFound reports that the credential store has no token for a profile.
+var ErrTokenNotFound = keyring.ErrNotFound
+
+var (
+	passphraseMu     sync.Mutex
+	passphrasePrompt func() (string, error)
+	passphrase       string
+
+	// derivedKeys caches keys by passphrase and salt, since each derivation is deliberately slow.
+	derivedKeys sync.Map
+)
+
+// SetPassphrasePrompt registers how to ask for the file store passphrase when
+// NOTIONCTL_PASSPHRASE is unset. The answer is reused for the rest of the process.
+func SetPassphrasePrompt(prompt func() (string, error)) {
+	passphraseMu.Lock()
+	defer passphraseMu.Unlock()
+	passphrasePrompt = prompt
+}
+
+// tokenStore holds integration tokens by profile. Get returns ErrTokenNotFound for
+// unknown profiles.
+type tokenStore interface {
+	Get(profile string) (string, error)
+	Set(profile, token string) error
+	Delete(profile string) error
+}
+
+// CredentialStore returns the configured credential store name.
+func CredentialStore() (string, error) {
+	if env := strings.TrimSpace(os.Getenv(CredentialStoreEnv)); env != "" {
+		return validateStore(env)
+	}
+	cfg, err := readConfig()
+	if err != nil {
+		return "", err
+	}
+	if cfg == nil || cfg.GetString("credential_store") == "" {
+		return StoreKeyring, nil
+	}
+	return validateStore(cfg.GetString("credential_store"))
+}
+
+// SetCredentialStore records which credential store every profile uses. Tokens already
+// saved in the other store are not moved.
+func SetCredentialStore(name string) error {
+	name, err := validateStore(name)
+	if err != nil {
+		return err
+	}
+	return updateConfig(func(cfg *viper.Viper) {
+		cfg.Set("credential_store", name)
+	})
+}
+
+func validateStore(name string) (string, error) {
+	switch name = strings.ToLower(strings.TrimSpace(name)); name {
+	case StoreKeyring, StoreFile:
+		return name, nil
+	default:
+		return "", fmt.Errorf("unknown credential store %q (expected keyring or file)", name)
+	}
+}
+
+func credentials() (tokenStore, error) {
+	name, err := CredentialStore()
+	if err != nil {
+		return nil, err
+	}
+	if name == StoreFile {
+		dir, err := ensureConfigDir()
+		if err != nil {
+			return nil, err
+		}
+		return fileStore{path: filepath.Join(dir, credentialsFileName)}, nil
+	}
+	return keyringStore{}, nil
+}
+
+type keyringStore struct{}
+
+func (keyringStore) Get(profile string) (string, error) {
+	token, err := keyring.Get(serviceName, profile)
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return "", fmt.Errorf("%w (use --credential-store %s on systems without a keyring)", err, StoreFile)
+	}
+	return token, err
+}
+
+func (keyringStore) Set(profile, token string) error {
+	if err := keyring.Set(serviceName, profile, token); err != nil {
+		return fmt.Errorf("%w (use --credential-store %s on systems without a keyring)", err, StoreFile)
+	}
+	return nil
+}
+
+func (keyringStore) Delete(profile string) error {
+	return keyring.Delete(serviceName, profile)
+}
+
+// fileStore keeps every profile's token in one file, encrypted with AES-256-GCM under a
+// key derived from the passphrase with PBKDF2-SHA256.
+type fileStore struct {
+	path string
+}
+
+type encryptedCredentials struct {
+	KDF        string `json:"kdf"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+	Iterations int    `json:"iterations"`
+}
+
+func (s fileStore) Get(profile string) (string, error) {
+	tokens, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	token, ok := tokens[profile]
+	if !ok {
+		return "", ErrTokenNotFound
+	}
+	return token, nil
+}
+
+func (s fileStore) Set(profile, token string) error {
+	return filelock.With(s.path, func() error {
+		tokens, err := s.read()
+		if err != nil {
+			return err
+		}
+		tokens[profile] = token
+		return s.write(tokens)
+	})
+}
+
+func (s fileStore) Delete(profile string) error {
+	return filelock.With(s.path, func() error {
+		tokens, err := s.read()
+		if err != nil {
+			return err
+		}
+		if _, ok := tokens[profile]; !ok {
+			return ErrTokenNotFound
+		}
+		delete(tokens, profile)
+		return s.write(tokens)
+	})
+}
+
+func (s fileStore) read() (map[string]string, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read credentials: %w", err)
+	}
+	var enc encryptedCredentials
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return nil, fmt.Errorf("decode credentials: %w", err)
+	}
+	pass, err := loadPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	plain, err := openSealed(enc, pass)
+	if err != nil {
+		return nil, err
+	}
+	tokens := map[string]string{}
+	if err := json.Unmarshal(plain, &tokens); err != nil {
+		return nil, fmt.Errorf("decode credentials: %w", err)
+	}
+	return tokens, nil
+}
+
+// write re-encrypts tokens with a fresh salt and nonce.
+func (s fileStore) write(tokens map[string]string) error {
+	plain, err := json.Marshal(tokens)
+	if err != nil {
+		return fmt.Errorf("encode credentials: %w", err)
+	}
+	pass, err := loadPassphrase()
+	if err != nil {
+		return err
+	}
+	enc, err := seal(plain, pass)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(enc)
+	if err != nil {
+		return fmt.Errorf("encode credentials: %w", err)
+	}
+	if err := filelock.WriteFile(s.path, data, filePermissions); err != nil {
+		return fmt.Errorf("write credentials: %w", err)
+	}
+	return nil
+}
+
+// seal encrypts plain under pass with a fresh salt and nonce.
+func seal(plain []byte, pass string) (encryptedCredentials, error) {
+	enc := encryptedCredentials{KDF: credentialsKDF, Iterations: kdfIterations, Salt: make([]byte, saltSize)}
+	if _, err := rand.Read(enc.Salt); err != nil {
+		return enc, fmt.Errorf("generate salt: %w", err)
+	}
+	gcm, err := credentialsCipher(pass, enc.Salt, enc.Iterations)
+	if err != nil {
+		return enc, err
+	}
+	enc.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(enc.Nonce); err != nil {
+		return enc, fmt.Errorf("generate nonce: %w", err)
+	}
+	enc.Ciphertext = gcm.Seal(nil, enc.Nonce, plain, nil)
+	return enc, nil
+}
+
+func openSealed(enc encryptedCredentials, pass string) ([]byte, error) {
+	if enc.KDF != credentialsKDF {
+		return nil, fmt.Errorf("unsupported key derivation %q", enc.KDF)
+	}
+	if enc.Iterations < kdfIterations || enc.Iterations > maxKDFIterations {
+		return nil, fmt.Errorf("unsupported key derivation iterations %d", enc.Iterations)
+	}
+	if len(enc.Salt) == 0 {
+		return nil, errors.New("decrypt credentials: missing salt")
+	}
+	gcm, err := credentialsCipher(pass, enc.Salt, enc.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	// gcm.Open panics on a nonce of the wrong size.
+	if len(enc.Nonce) != gcm.NonceSize() {
+		return nil, errors.New("decrypt credentials: wrong passphrase or corrupted file")
+	}
+	plain, err := gcm.Open(nil, enc.Nonce, enc.Ciphertext, nil)
+	if err != nil {
+		return nil, errors.New("decrypt credentials: wrong passphrase or corrupted file")
+	}
+	return plain, nil
+}
+
+func credentialsCipher(pass string, salt []byte, iterations int) (cipher.AEAD, error) {
+	cacheKey := fmt.Sprintf("%d\x00%x\x00%s", iterations, salt, pass)
+	key, ok := derivedKeys.Load(cacheKey)
+	if !ok {
+		derived, err := pbkdf2.Key(sha256.New, pass, salt, iterations, keySize)
+		if err != nil {
+			return nil, fmt.Errorf("derive key: %w", err)
+		}
+		key, _ = derivedKeys.LoadOrStore(cacheKey, derived)
+	}
+	block, err := aes.NewCipher(key.([]byte))
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	return gcm, nil
+}
+
+func loadPassphrase() (string, error) {
+	if env := os.Getenv(PassphraseEnv); env != "" {
+		return env, nil
+	}
+	passphraseMu.Lock()
+	defer passphraseMu.Unlock()
+	if passphrase != "" {
+		return passphrase, nil
+	}
+	if passphrasePrompt == nil {
+		return "", fmt.Errorf("the file credential store needs a passphrase: set %s", PassphraseEnv)
+	}
+	pass, err := passphrasePrompt()
+	if err != nil {
+		return "", fmt.Errorf("read passphrase: %w", err)
+	}
+	if pass == "" {
+		return "", errors.New("passphrase cannot be empty")
+	}
+	passphrase = pass
+	return passphrase, nil
+}
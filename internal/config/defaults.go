@@ -0,0 +1,110 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Keys accepted by SetProfileDefault.
+const (
+	DefaultFormat     = "format"
+	DefaultPageSize   = "page_size"
+	DefaultDataSource = "data_source_id"
+
+	maxDefaultPageSize = 100
+)
+
+// profileDefaultKey returns where a default is stored. The data source default is the
+// profile's default_data_source, which ds alias set --default also writes.
+func profileDefaultKey(profile, key string) string {
+	if key == DefaultDataSource {
+		return "profiles." + profile + ".default_data_source"
+	}
+	return "profiles." + profile + ".defaults." + key
+}
+
+// ProfileDefaults are flag values commands use when the flag is not given.
+type ProfileDefaults struct {
+	Format     string `json:"format,omitempty"`
+	DataSource string `json:"data_source_id,omitempty"`
+	PageSize   int    `json:"page_size,omitempty"`
+}
+
+// LoadProfileDefaults returns the defaults declared under profiles.<profile>.defaults, and
+// the profile's default data source.
+func LoadProfileDefaults(profile string) (ProfileDefaults, error) {
+	if profile == "" {
+		return ProfileDefaults{}, errors.New("profile name cannot be empty")
+	}
+	cfg, err := readConfig()
+	if err != nil || cfg == nil {
+		return ProfileDefaults{}, err
+	}
+	return ProfileDefaults{
+		Format:     cfg.GetString(profileDefaultKey(profile, DefaultFormat)),
+		DataSource: cfg.GetString(profileDefaultKey(profile, DefaultDataSource)),
+		PageSize:   cfg.GetInt(profileDefaultKey(profile, DefaultPageSize)),
+	}, nil
+}
+
+// SetProfileDefault records one default. The data source may be an alias, ID, or URL.
+func SetProfileDefault(profile, key, value string) error {
+	if profile == "" {
+		return errors.New("profile name cannot be empty")
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fmt.Errorf("%s cannot be empty", key)
+	}
+	var stored any = value
+	switch key {
+	case DefaultFormat, DefaultDataSource:
+	case DefaultPageSize:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxDefaultPageSize {
+			return fmt.Errorf("page_size must be a number between 1 and %d", maxDefaultPageSize)
+		}
+		stored = n
+	default:
+		return unknownDefaultError(key)
+	}
+	return updateConfig(func(cfg *viper.Viper) {
+		cfg.Set(profileDefaultKey(profile, key), stored)
+	})
+}
+
+// UnsetProfileDefault removes one default.
+func UnsetProfileDefault(profile, key string) error {
+	if profile == "" {
+		return errors.New("profile name cannot be empty")
+	}
+	switch key {
+	case DefaultFormat, DefaultPageSize, DefaultDataSource:
+	default:
+		return unknownDefaultError(key)
+	}
+	return rewriteConfig(func(cfg *viper.Viper) (map[string]any, error) {
+		settings := cfg.AllSettings()
+		profiles, _ := settings["profiles"].(map[string]any)
+		section, _ := profiles[strings.ToLower(profile)].(map[string]any)
+		if key == DefaultDataSource {
+			delete(section, "default_data_source")
+			return settings, nil
+		}
+		if defaults, ok := section["defaults"].(map[string]any); ok {
+			delete(defaults, key)
+			if len(defaults) == 0 {
+				delete(section, "defaults")
+			}
+		}
+		return settings, nil
+	})
+}
+
+func unknownDefaultError(key string) error {
+	return fmt.Errorf("unknown default %q (expected %s, %s, or %s)", key, DefaultFormat, DefaultPageSize, DefaultDataSource)
+}
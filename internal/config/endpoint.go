@@ -0,0 +1,127 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+// Endpoint points a profile's client somewhere other than api.notion.com, such as a
+// corporate API gateway or a mock server, and through proxies or TLS interception.
+type Endpoint struct {
+	Headers            map[string]string `json:"headers,omitempty"`
+	BaseURL            string            `json:"base_url,omitempty"`
+	ProxyURL           string            `json:"proxy_url,omitempty"`
+	CABundle           string            `json:"ca_bundle,omitempty"`
+	InsecureSkipVerify bool              `json:"insecure_skip_verify,omitempty"`
+}
+
+// LoadEndpoint returns the profile's base URL, extra headers, and network settings; all are
+// empty by default.
+func LoadEndpoint(profile string) (Endpoint, error) {
+	if profile == "" {
+		return Endpoint{}, errors.New("profile name cannot be empty")
+	}
+	cfg, err := readConfig()
+	if err != nil || cfg == nil {
+		return Endpoint{}, err
+	}
+	prefix := "profiles." + profile + "."
+	endpoint := Endpoint{
+		BaseURL:            cfg.GetString(prefix + "base_url"),
+		ProxyURL:           cfg.GetString(prefix + "proxy_url"),
+		CABundle:           cfg.GetString(prefix + "ca_bundle"),
+		InsecureSkipVerify: cfg.GetBool(prefix + "insecure_skip_verify"),
+	}
+	// The file may have been edited by hand since SaveEndpoint checked it.
+	if endpoint.BaseURL != "" {
+		if err := ValidateBaseURL(endpoint.BaseURL); err != nil {
+			return Endpoint{}, fmt.Errorf("profile %q base_url: %w", profile, err)
+		}
+	}
+	for name, value := range cfg.GetStringMapString(prefix + "headers") {
+		if endpoint.Headers == nil {
+			endpoint.Headers = map[string]string{}
+		}
+		endpoint.Headers[http.CanonicalHeaderKey(name)] = value
+	}
+	return endpoint, nil
+}
+
+// SaveEndpoint replaces the profile's endpoint settings; empty values remove them. The CA
+// bundle path is made absolute so later runs from other directories find it.
+func SaveEndpoint(profile string, endpoint Endpoint) error {
+	if profile == "" {
+		return errors.New("profile name cannot be empty")
+	}
+	if endpoint.BaseURL != "" {
+		if err := ValidateBaseURL(endpoint.BaseURL); err != nil {
+			return err
+		}
+	}
+	if endpoint.ProxyURL != "" {
+		if _, err := notion.ParseProxyURL(endpoint.ProxyURL); err != nil {
+			return err
+		}
+	}
+	if endpoint.CABundle != "" {
+		abs, err := filepath.Abs(endpoint.CABundle)
+		if err != nil {
+			return fmt.Errorf("resolve CA bundle path: %w", err)
+		}
+		if _, err := notion.LoadCABundle(abs); err != nil {
+			return err
+		}
+		endpoint.CABundle = abs
+	}
+	headers := map[string]any{}
+	for name, value := range endpoint.Headers {
+		switch canonical := http.CanonicalHeaderKey(strings.TrimSpace(name)); canonical {
+		case "", "Authorization", "Notion-Version":
+			return fmt.Errorf("header %q cannot be configured", name)
+		default:
+			headers[strings.ToLower(canonical)] = value
+		}
+	}
+
+	return rewriteConfig(func(cfg *viper.Viper) (map[string]any, error) {
+		cfg.Set("profiles."+profile+".base_url", endpoint.BaseURL)
+		settings := cfg.AllSettings()
+		profiles, _ := settings["profiles"].(map[string]any)
+		section, _ := profiles[strings.ToLower(profile)].(map[string]any)
+		for key, value := range map[string]any{
+			"base_url":             endpoint.BaseURL,
+			"proxy_url":            endpoint.ProxyURL,
+			"ca_bundle":            endpoint.CABundle,
+			"insecure_skip_verify": endpoint.InsecureSkipVerify,
+		} {
+			if value == "" || value == false {
+				delete(section, key)
+			} else {
+				section[key] = value
+			}
+		}
+		if len(headers) == 0 {
+			delete(section, "headers")
+		} else {
+			section["headers"] = headers
+		}
+		return settings, nil
+	})
+}
+
+// ValidateBaseURL checks that raw is an absolute http or https URL.
+func ValidateBaseURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("base URL %q must be an absolute http or https URL", raw)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Keys accepted by SetLimit.
+const (
+	LimitMaxRetries       = "max_retries"
+	LimitRPS              = "rps"
+	LimitBackoffBase      = "backoff_base"
+	LimitBreakerThreshold = "breaker_threshold"
+	LimitConcurrency      = "concurrency"
+)
+
+// Limits tune a profile's request rate and retries. Zero fields keep the client defaults of
+// 3 requests per second, 5 retries, a 500ms backoff base, and a breaker threshold of 10, and
+// let commands size their worker pools from the request rate.
+type Limits struct {
+	MaxRetries       *int          `json:"max_retries,omitempty"`
+	RPS              float64       `json:"rps,omitempty"`
+	BackoffBase      time.Duration `json:"backoff_base,omitempty"`
+	BreakerThreshold int           `json:"breaker_threshold,omitempty"`
+	Concurrency      int           `json:"concurrency,omitempty"`
+}
+
+// LoadLimits returns the limits declared under profiles.<profile>.limits.
+func LoadLimits(profile string) (Limits, error) {
+	if profile == "" {
+		return Limits{}, errors.New("profile name cannot be empty")
+	}
+	cfg, err := readConfig()
+	if err != nil || cfg == nil {
+		return Limits{}, err
+	}
+	prefix := "profiles." + profile + ".limits."
+	var limits Limits
+	if cfg.IsSet(prefix + LimitMaxRetries) {
+		retries := cfg.GetInt(prefix + LimitMaxRetries)
+		limits.MaxRetries = &retries
+	}
+	limits.RPS = cfg.GetFloat64(prefix + LimitRPS)
+	limits.BackoffBase = cfg.GetDuration(prefix + LimitBackoffBase)
+	limits.BreakerThreshold = cfg.GetInt(prefix + LimitBreakerThreshold)
+	limits.Concurrency = cfg.GetInt(prefix + LimitConcurrency)
+	return limits, nil
+}
+
+// SetLimit validates and stores one limit for profile.
+func SetLimit(profile, key, value string) error {
+	if profile == "" {
+		return errors.New("profile name cannot be empty")
+	}
+	value = strings.TrimSpace(value)
+	var stored any
+	switch key {
+	case LimitMaxRetries:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return errors.New("max_retries must be a whole number of at least 0")
+		}
+		stored = n
+	case LimitRPS:
+		rps, err := strconv.ParseFloat(value, 64)
+		if err != nil || rps <= 0 {
+			return errors.New("rps must be a positive number")
+		}
+		stored = rps
+	case LimitBackoffBase:
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return errors.New("backoff_base must be a positive duration such as 500ms")
+		}
+		stored = d.String()
+	case LimitBreakerThreshold:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return errors.New("breaker_threshold must be a whole number of at least 1")
+		}
+		stored = n
+	case LimitConcurrency:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return errors.New("concurrency must be a whole number of at least 1")
+		}
+		stored = n
+	default:
+		return unknownLimitError(key)
+	}
+	return updateConfig(func(cfg *viper.Viper) {
+		cfg.Set(fmt.Sprintf("profiles.%s.limits.%s", profile, key), stored)
+	})
+}
+
+// UnsetLimit removes one limit, restoring the client default.
+func UnsetLimit(profile, key string) error {
+	if profile == "" {
+		return errors.New("profile name cannot be empty")
+	}
+	switch key {
+	case LimitMaxRetries, LimitRPS, LimitBackoffBase, LimitBreakerThreshold, LimitConcurrency:
+	default:
+		return unknownLimitError(key)
+	}
+	return rewriteConfig(func(cfg *viper.Viper) (map[string]any, error) {
+		settings := cfg.AllSettings()
+		profiles, _ := settings["profiles"].(map[string]any)
+		section, _ := profiles[strings.ToLower(profile)].(map[string]any)
+		if limits, ok := section["limits"].(map[string]any); ok {
+			delete(limits, key)
+			if len(limits) == 0 {
+				delete(section, "limits")
+			}
+		}
+		return settings, nil
+	})
+}
+
+func unknownLimitError(key string) error {
+	return fmt.Errorf("unknown limit %q (expected %s, %s, %s, %s, or %s)",
+		key, LimitMaxRetries, LimitRPS, LimitBackoffBase, LimitBreakerThreshold, LimitConcurrency)
+}
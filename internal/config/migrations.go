@@ -0,0 +1,46 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// LoadAppliedMigrations returns the names of migrations recorded as applied to a data
+// source under the given profile.
+func LoadAppliedMigrations(profile, dataSourceID string) ([]string, error) {
+	if profile == "" {
+		return nil, errors.New("profile name cannot be empty")
+	}
+	cfg, err := readConfig()
+	if err != nil || cfg == nil {
+		return nil, err
+	}
+	return cfg.GetStringSlice(migrationsKey(profile, dataSourceID)), nil
+}
+
+// RecordAppliedMigration appends name to the applied-migrations list for a data source.
+func RecordAppliedMigration(profile, dataSourceID, name string) error {
+	if profile == "" {
+		return errors.New("profile name cannot be empty")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("migration name cannot be empty")
+	}
+
+	key := migrationsKey(profile, dataSourceID)
+	return updateConfig(func(cfg *viper.Viper) {
+		applied := cfg.GetStringSlice(key)
+		if !slices.Contains(applied, name) {
+			cfg.Set(key, append(applied, name))
+		}
+	})
+}
+
+func migrationsKey(profile, dataSourceID string) string {
+	return fmt.Sprintf("profiles.%s.migrations.%s", profile, strings.ToLower(strings.TrimSpace(dataSourceID)))
+}
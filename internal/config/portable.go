@@ -0,0 +1,127 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const profileBundleVersion = 1
+
+// profileBundle is the file written by ExportProfile: the profile's config section in the
+// clear and its token encrypted with the export passphrase.
+type profileBundle struct {
+	Settings map[string]any        `json:"settings"`
+	Token    *encryptedCredentials `json:"token,omitempty"`
+	Profile  string                `json:"profile"`
+	Version  int                   `json:"version"`
+}
+
+// ExportProfile bundles a profile's settings (Notion-Version, aliases, defaults, workspace)
+// and its token, encrypted with passphrase, so it can be imported on another machine.
+func ExportProfile(profile, passphrase string) ([]byte, error) {
+	if profile == "" {
+		return nil, errors.New("profile name cannot be empty")
+	}
+	if passphrase == "" {
+		return nil, errors.New("passphrase cannot be empty")
+	}
+	bundle := profileBundle{Version: profileBundleVersion, Profile: profile, Settings: map[string]any{}}
+	cfg, err := readConfig()
+	if err != nil {
+		return nil, err
+	}
+	if cfg != nil {
+		bundle.Settings = cfg.GetStringMap("profiles." + profile)
+	}
+
+	store, err := credentials()
+	if err != nil {
+		return nil, err
+	}
+	token, err := store.Get(profile)
+	switch {
+	case errors.Is(err, ErrTokenNotFound):
+		if len(bundle.Settings) == 0 {
+			return nil, fmt.Errorf("profile %q not found", profile)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("load token: %w", err)
+	default:
+		enc, err := seal([]byte(token), passphrase)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Token = &enc
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode profile: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// ImportProfile restores a bundle written by ExportProfile under name, or under the
+// exported name when name is empty, and returns the name used. An existing profile is only
+// replaced when overwrite is set.
+func ImportProfile(data []byte, passphrase, name string, overwrite bool) (string, error) {
+	var bundle profileBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return "", fmt.Errorf("decode profile: %w", err)
+	}
+	if bundle.Version != profileBundleVersion {
+		return "", fmt.Errorf("unsupported profile file version %d", bundle.Version)
+	}
+	if name == "" {
+		name = bundle.Profile
+	}
+	if name == "" {
+		return "", errors.New("profile name cannot be empty")
+	}
+
+	var token string
+	if bundle.Token != nil {
+		plain, err := openSealed(*bundle.Token, passphrase)
+		if err != nil {
+			return "", err
+		}
+		token = string(plain)
+	}
+
+	store, err := credentials()
+	if err != nil {
+		return "", err
+	}
+	if !overwrite {
+		if _, err := store.Get(name); err == nil {
+			return "", fmt.Errorf("profile %q already has stored credentials (use --force to replace it)", name)
+		}
+	}
+	err = rewriteConfig(func(cfg *viper.Viper) (map[string]any, error) {
+		settings := cfg.AllSettings()
+		profiles, ok := settings["profiles"].(map[string]any)
+		if !ok {
+			profiles = map[string]any{}
+			settings["profiles"] = profiles
+		}
+		key := strings.ToLower(name)
+		if _, exists := profiles[key]; exists && !overwrite {
+			return nil, fmt.Errorf("profile %q already exists (use --force to replace it)", name)
+		}
+		profiles[key] = bundle.Settings
+		return settings, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if token != "" {
+		if err := store.Set(name, token); err != nil {
+			return "", fmt.Errorf("save token: %w", err)
+		}
+	}
+	return name, nil
+}
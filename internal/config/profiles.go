@@ -0,0 +1,176 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Profile summarizes what is stored for one profile.
+type Profile struct {
+	Name              string `json:"name"`
+	NotionVersion     string `json:"notion_version"`
+	Workspace         string `json:"workspace,omitempty"`
+	DefaultDataSource string `json:"default_data_source,omitempty"`
+	Aliases           int    `json:"aliases"`
+	HasToken          bool   `json:"has_token"`
+}
+
+// ListProfiles returns every profile recorded in config.yaml, sorted by name. Keyring
+// entries cannot be enumerated, so a token stored without config is not listed.
+func ListProfiles() ([]Profile, error) {
+	cfg, err := readConfig()
+	if err != nil || cfg == nil {
+		return nil, err
+	}
+	names := make([]string, 0)
+	for name := range cfg.GetStringMap("profiles") {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	store, err := credentials()
+	if err != nil {
+		return nil, err
+	}
+	profiles := make([]Profile, 0, len(names))
+	for _, name := range names {
+		prefix := "profiles." + name + "."
+		version := cfg.GetString(prefix + "notion_version")
+		if version == "" {
+			version = defaultNotionVersion
+		}
+		_, tokenErr := store.Get(name)
+		profiles = append(profiles, Profile{
+			Name:              name,
+			NotionVersion:     version,
+			Workspace:         cfg.GetString(prefix + "workspace.name"),
+			DefaultDataSource: cfg.GetString(prefix + "default_data_source"),
+			Aliases:           len(cfg.GetStringMap(prefix + "aliases")),
+			HasToken:          tokenErr == nil,
+		})
+	}
+	return profiles, nil
+}
+
+// DeleteProfile removes a profile's stored token, its config section, and its cached
+// schemas.
+func DeleteProfile(profile string) error {
+	if profile == "" {
+		return errors.New("profile name cannot be empty")
+	}
+	store, err := credentials()
+	if err != nil {
+		return err
+	}
+	tokenErr := store.Delete(profile)
+	if tokenErr != nil && !errors.Is(tokenErr, ErrTokenNotFound) {
+		return fmt.Errorf("delete token: %w", tokenErr)
+	}
+
+	found := tokenErr == nil
+	if err := rewriteConfig(func(cfg *viper.Viper) (map[string]any, error) {
+		settings := cfg.AllSettings()
+		profiles, _ := settings["profiles"].(map[string]any)
+		if _, ok := profiles[strings.ToLower(profile)]; ok {
+			found = true
+			delete(profiles, strings.ToLower(profile))
+		}
+		return settings, nil
+	}); err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("profile %q not found", profile)
+	}
+	return removeProfileSchemas(profile)
+}
+
+// RenameProfile moves a profile's token, config section, and cached schemas to a new name.
+func RenameProfile(from, to string) error {
+	if from == "" || to == "" {
+		return errors.New("profile name cannot be empty")
+	}
+	if strings.EqualFold(from, to) {
+		return errors.New("new profile name must differ from the old one")
+	}
+	store, err := credentials()
+	if err != nil {
+		return err
+	}
+	if _, err := store.Get(to); err == nil {
+		return fmt.Errorf("profile %q already has stored credentials", to)
+	}
+
+	err = rewriteConfig(func(cfg *viper.Viper) (map[string]any, error) {
+		settings := cfg.AllSettings()
+		profiles, _ := settings["profiles"].(map[string]any)
+		section, ok := profiles[strings.ToLower(from)]
+		if !ok {
+			return nil, fmt.Errorf("profile %q not found", from)
+		}
+		if _, taken := profiles[strings.ToLower(to)]; taken {
+			return nil, fmt.Errorf("profile %q already exists", to)
+		}
+		profiles[strings.ToLower(to)] = section
+		delete(profiles, strings.ToLower(from))
+		return settings, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	token, err := store.Get(from)
+	switch {
+	case errors.Is(err, ErrTokenNotFound):
+	case err != nil:
+		return fmt.Errorf("load token: %w", err)
+	default:
+		if err := store.Set(to, token); err != nil {
+			return fmt.Errorf("save token: %w", err)
+		}
+		if err := store.Delete(from); err != nil {
+			return fmt.Errorf("delete old token: %w", err)
+		}
+	}
+	return renameProfileSchemas(from, to)
+}
+
+func profileSchemaDir(profile string) (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "schemas", profile), nil
+}
+
+func removeProfileSchemas(profile string) error {
+	dir, err := profileSchemaDir(profile)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("remove schema cache: %w", err)
+	}
+	return nil
+}
+
+func renameProfileSchemas(from, to string) error {
+	src, err := profileSchemaDir(from)
+	if err != nil {
+		return err
+	}
+	dst, err := profileSchemaDir(to)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("move schema cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/filelock"
+)
+
+// Kinds of recently used items.
+const (
+	RecentDataSource = "data_source"
+	RecentPage       = "page"
+)
+
+// maxRecentItems caps how many items of each kind are remembered.
+const maxRecentItems = 50
+
+// RecentItem is a data source or page the profile used recently, offered first by the
+// interactive picker.
+type RecentItem struct {
+	UsedAt time.Time `json:"used_at"`
+	Kind   string    `json:"kind"`
+	ID     string    `json:"id"`
+	Label  string    `json:"label,omitempty"`
+}
+
+// LoadRecent returns the profile's recent items of kind, most recently used first.
+func LoadRecent(profile, kind string) ([]RecentItem, error) {
+	path, err := recentPath(profile)
+	if err != nil {
+		return nil, err
+	}
+	items, err := readRecent(path)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(items, func(item RecentItem) bool { return item.Kind != kind }), nil
+}
+
+// RecordRecent marks item as used now. An empty label keeps the label recorded earlier.
+func RecordRecent(profile string, item RecentItem) error {
+	path, err := recentPath(profile)
+	if err != nil {
+		return err
+	}
+	if item.ID == "" {
+		return errors.New("recent item needs an ID")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), dirPermissions); err != nil {
+		return fmt.Errorf("create config directory: %w", err)
+	}
+	return filelock.With(path, func() error {
+		items, err := readRecent(path)
+		if err != nil {
+			return err
+		}
+		kept := 0
+		items = slices.DeleteFunc(items, func(old RecentItem) bool {
+			same := old.Kind == item.Kind && strings.EqualFold(old.ID, item.ID)
+			if same && item.Label == "" {
+				item.Label = old.Label
+			}
+			return same
+		})
+		if item.UsedAt.IsZero() {
+			item.UsedAt = time.Now().UTC()
+		}
+		items = append([]RecentItem{item}, items...)
+		items = slices.DeleteFunc(items, func(other RecentItem) bool {
+			if other.Kind != item.Kind {
+				return false
+			}
+			kept++
+			return kept > maxRecentItems
+		})
+		data, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode recent items: %w", err)
+		}
+		return filelock.WriteFile(path, append(data, '\n'), filePermissions)
+	})
+}
+
+func readRecent(path string) ([]RecentItem, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is built from the config directory
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read recent items: %w", err)
+	}
+	var items []RecentItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("decode recent items %s: %w", path, err)
+	}
+	slices.SortStableFunc(items, func(a, b RecentItem) int { return cmp.Compare(b.UsedAt.UnixNano(), a.UsedAt.UnixNano()) })
+	return items, nil
+}
+
+// recentPath keeps each profile's history in recent/<profile>.json.
+func recentPath(profile string) (string, error) {
+	if profile == "" || strings.ContainsAny(profile, `/\`) {
+		return "", fmt.Errorf("invalid profile name %q", profile)
+	}
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "recent", profile+".json"), nil
+}
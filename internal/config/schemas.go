@@ -0,0 +1,72 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourorg/notionctl/internal/filelock"
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+// SaveSchema caches a data source schema for the profile so offline helpers can use real
+// property names. Each schema lives in schemas/<profile>/<data-source-id>.json.
+func SaveSchema(profile string, ds notion.DataSource) error {
+	path, err := schemaPath(profile, ds.ID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), dirPermissions); err != nil {
+		return fmt.Errorf("create schema cache directory: %w", err)
+	}
+	data, err := json.MarshalIndent(ds, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode schema: %w", err)
+	}
+	if err := filelock.With(path, func() error {
+		return filelock.WriteFile(path, append(data, '\n'), filePermissions)
+	}); err != nil {
+		return fmt.Errorf("write schema cache: %w", err)
+	}
+	return nil
+}
+
+// LoadSchema returns the cached schema for a data source, reporting false when none has been
+// cached yet.
+func LoadSchema(profile, dataSourceID string) (notion.DataSource, bool, error) {
+	path, err := schemaPath(profile, dataSourceID)
+	if err != nil {
+		return notion.DataSource{}, false, err
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- path is built from the config directory
+	if errors.Is(err, fs.ErrNotExist) {
+		return notion.DataSource{}, false, nil
+	}
+	if err != nil {
+		return notion.DataSource{}, false, fmt.Errorf("read schema cache: %w", err)
+	}
+	var ds notion.DataSource
+	if err := json.Unmarshal(data, &ds); err != nil {
+		return notion.DataSource{}, false, fmt.Errorf("decode schema cache %s: %w", path, err)
+	}
+	return ds, true, nil
+}
+
+func schemaPath(profile, dataSourceID string) (string, error) {
+	if profile == "" {
+		return "", errors.New("profile name cannot be empty")
+	}
+	dataSourceID = strings.ToLower(strings.TrimSpace(dataSourceID))
+	if dataSourceID == "" || strings.ContainsAny(dataSourceID+profile, `/\`) {
+		return "", fmt.Errorf("invalid schema cache key %q/%q", profile, dataSourceID)
+	}
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "schemas", profile, dataSourceID+".json"), nil
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"path/filepath"
+
+	"github.com/spf13/viper"
+)
+
+const usageLogKey = "usage_log.enabled"
+
+// UsageLogEnabled reports whether the opt-in local usage log is switched on.
+func UsageLogEnabled() (bool, error) {
+	cfg, err := readConfig()
+	if err != nil || cfg == nil {
+		return false, err
+	}
+	return cfg.GetBool(usageLogKey), nil
+}
+
+// SetUsageLog switches the local usage log on or off for every profile.
+func SetUsageLog(enabled bool) error {
+	return updateConfig(func(cfg *viper.Viper) {
+		cfg.Set(usageLogKey, enabled)
+	})
+}
+
+// UsageLogPath returns where usage entries are appended. Nothing is ever sent anywhere.
+func UsageLogPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "usage.jsonl"), nil
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// Workspace describes the workspace an OAuth login was granted for.
+type Workspace struct {
+	ID    string
+	Name  string
+	BotID string
+}
+
+// SaveWorkspace records the workspace behind a profile's OAuth token.
+func SaveWorkspace(profile string, ws Workspace) error {
+	if profile == "" {
+		return errors.New("profile name cannot be empty")
+	}
+	prefix := fmt.Sprintf("profiles.%s.workspace.", profile)
+	return updateConfig(func(cfg *viper.Viper) {
+		cfg.Set(prefix+"id", ws.ID)
+		cfg.Set(prefix+"name", ws.Name)
+		cfg.Set(prefix+"bot_id", ws.BotID)
+	})
+}
+
+// LoadWorkspace returns the workspace recorded for a profile, reporting false for profiles
+// that were set up with a manual token.
+func LoadWorkspace(profile string) (Workspace, bool, error) {
+	if profile == "" {
+		return Workspace{}, false, errors.New("profile name cannot be empty")
+	}
+	cfg, err := readConfig()
+	if err != nil || cfg == nil {
+		return Workspace{}, false, err
+	}
+	prefix := fmt.Sprintf("profiles.%s.workspace.", profile)
+	ws := Workspace{
+		ID:    cfg.GetString(prefix + "id"),
+		Name:  cfg.GetString(prefix + "name"),
+		BotID: cfg.GetString(prefix + "bot_id"),
+	}
+	return ws, ws.ID != "" || ws.Name != "", nil
+}
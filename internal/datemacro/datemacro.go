@@ -0,0 +1,174 @@
+// Package datemacro expands relative date macros such as @today, @start-of-week, or
+// @now-7d into RFC3339 timestamps, so scheduled jobs can filter on moving windows without
+// doing date arithmetic in the shell.
+//
+// A macro is an anchor optionally followed by offsets:
+//
+//	@now  @today  @yesterday  @tomorrow
+//	@start-of-week  @start-of-month  @start-of-year
+//	@end-of-week    @end-of-month    @end-of-year
+//
+// Offsets are signed integers with a unit: m (minutes), h, d, w, mo (months), or y, for
+// example @now-7d, @today+1w, or @start-of-month-1mo. Weeks start on Monday.
+package datemacro
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const prefix = "@"
+
+var offsetPattern = regexp.MustCompile(`^([+-]\d+)(mo|m|h|d|w|y)`)
+
+// anchors are sorted longest first so that "@today" never shadows "@today-ish" variants.
+var anchors = []struct {
+	name string
+	at   func(now time.Time) time.Time
+}{
+	{"start-of-month", func(now time.Time) time.Time { return startOfMonth(now) }},
+	{"start-of-week", func(now time.Time) time.Time { return startOfWeek(now) }},
+	{"start-of-year", func(now time.Time) time.Time { return startOfYear(now) }},
+	{"end-of-month", func(now time.Time) time.Time { return startOfMonth(now).AddDate(0, 1, 0).Add(-time.Second) }},
+	{"end-of-week", func(now time.Time) time.Time { return startOfWeek(now).AddDate(0, 0, 7).Add(-time.Second) }},
+	{"end-of-year", func(now time.Time) time.Time { return startOfYear(now).AddDate(1, 0, 0).Add(-time.Second) }},
+	{"yesterday", func(now time.Time) time.Time { return startOfDay(now).AddDate(0, 0, -1) }},
+	{"tomorrow", func(now time.Time) time.Time { return startOfDay(now).AddDate(0, 0, 1) }},
+	{"today", startOfDay},
+	{"now", func(now time.Time) time.Time { return now }},
+}
+
+// IsMacro reports whether value looks like a date macro.
+func IsMacro(value string) bool {
+	return strings.HasPrefix(strings.TrimSpace(value), prefix)
+}
+
+// Expand resolves a macro relative to now and returns it as an RFC3339 timestamp. Values
+// that are not macros are returned unchanged.
+func Expand(value string, now time.Time) (string, error) {
+	text := strings.TrimSpace(value)
+	if !IsMacro(text) {
+		return value, nil
+	}
+	body := strings.ToLower(strings.TrimPrefix(text, prefix))
+
+	for _, anchor := range anchors {
+		rest, ok := strings.CutPrefix(body, anchor.name)
+		if !ok {
+			continue
+		}
+		t, err := applyOffsets(anchor.at(now), rest)
+		if err != nil {
+			return "", fmt.Errorf("date macro %s: %w", text, err)
+		}
+		return t.Format(time.RFC3339), nil
+	}
+	return "", fmt.Errorf("unknown date macro %s", text)
+}
+
+// dateConditionKeys are the filter keys whose object holds date comparisons.
+var dateConditionKeys = map[string]bool{
+	"date":             true,
+	"created_time":     true,
+	"last_edited_time": true,
+}
+
+// ExpandFilter walks a decoded Notion filter and expands macros in date conditions, e.g.
+// {"date": {"on_or_after": "@today"}}. Strings elsewhere, such as a rich_text "contains"
+// value that happens to start with "@", are left alone.
+func ExpandFilter(filter any, now time.Time) (any, error) {
+	switch v := filter.(type) {
+	case map[string]any:
+		for key, item := range v {
+			var (
+				expanded any
+				err      error
+			)
+			if cond, ok := item.(map[string]any); ok && dateConditionKeys[key] {
+				expanded, err = expandCondition(cond, now)
+			} else {
+				expanded, err = ExpandFilter(item, now)
+			}
+			if err != nil {
+				return nil, err
+			}
+			v[key] = expanded
+		}
+		return v, nil
+	case []any:
+		for i, item := range v {
+			expanded, err := ExpandFilter(item, now)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = expanded
+		}
+		return v, nil
+	default:
+		return filter, nil
+	}
+}
+
+func expandCondition(cond map[string]any, now time.Time) (map[string]any, error) {
+	for op, value := range cond {
+		text, ok := value.(string)
+		if !ok {
+			continue
+		}
+		expanded, err := Expand(text, now)
+		if err != nil {
+			return nil, err
+		}
+		cond[op] = expanded
+	}
+	return cond, nil
+}
+
+func applyOffsets(t time.Time, rest string) (time.Time, error) {
+	for rest != "" {
+		match := offsetPattern.FindStringSubmatch(rest)
+		if match == nil {
+			return time.Time{}, fmt.Errorf("invalid offset %q", rest)
+		}
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid offset %q: %w", match[0], err)
+		}
+		switch match[2] {
+		case "m":
+			t = t.Add(time.Duration(n) * time.Minute)
+		case "h":
+			t = t.Add(time.Duration(n) * time.Hour)
+		case "d":
+			t = t.AddDate(0, 0, n)
+		case "w":
+			t = t.AddDate(0, 0, 7*n)
+		case "mo":
+			t = t.AddDate(0, n, 0)
+		case "y":
+			t = t.AddDate(n, 0, 0)
+		}
+		rest = rest[len(match[0]):]
+	}
+	return t, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7 // days since Monday
+	return startOfDay(t).AddDate(0, 0, -offset)
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+func startOfYear(t time.Time) time.Time {
+	return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+}
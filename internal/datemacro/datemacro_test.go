@@ -0,0 +1,65 @@
+package datemacro_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/datemacro"
+)
+
+func TestExpand(t *testing.T) {
+	// Wednesday.
+	now := time.Date(2025, time.March, 12, 15, 30, 0, 0, time.UTC)
+
+	cases := map[string]string{
+		"@now":                "2025-03-12T15:30:00Z",
+		"@now-7d":             "2025-03-05T15:30:00Z",
+		"@today":              "2025-03-12T00:00:00Z",
+		"@Today+1w":           "2025-03-19T00:00:00Z",
+		"@yesterday":          "2025-03-11T00:00:00Z",
+		"@start-of-week":      "2025-03-10T00:00:00Z",
+		"@end-of-week":        "2025-03-16T23:59:59Z",
+		"@start-of-month-1mo": "2025-02-01T00:00:00Z",
+		"@start-of-year":      "2025-01-01T00:00:00Z",
+		"@now-1d-2h":          "2025-03-11T13:30:00Z",
+		"2025-01-01":          "2025-01-01",
+	}
+	for input, want := range cases {
+		got, err := datemacro.Expand(input, now)
+		if err != nil {
+			t.Fatalf("Expand(%q) returned error: %v", input, err)
+		}
+		if got != want {
+			t.Fatalf("Expand(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	for _, bad := range []string{"@later", "@now-7", "@today+1q"} {
+		if _, err := datemacro.Expand(bad, now); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestExpandFilter(t *testing.T) {
+	now := time.Date(2025, time.March, 12, 0, 0, 0, 0, time.UTC)
+	payload := map[string]any{
+		"and": []any{
+			map[string]any{"property": "Due", "date": map[string]any{"on_or_after": "@today-1d"}},
+			map[string]any{"property": "Owner", "rich_text": map[string]any{"contains": "@alice"}},
+		},
+	}
+	expanded, err := datemacro.ExpandFilter(payload, now)
+	if err != nil {
+		t.Fatalf("ExpandFilter returned error: %v", err)
+	}
+	and := expanded.(map[string]any)["and"].([]any)
+	date := and[0].(map[string]any)["date"].(map[string]any)
+	if date["on_or_after"] != "2025-03-11T00:00:00Z" {
+		t.Fatalf("unexpected expansion: %#v", date)
+	}
+	text := and[1].(map[string]any)["rich_text"].(map[string]any)
+	if text["contains"] != "@alice" {
+		t.Fatalf("non-date value should be untouched: %#v", text)
+	}
+}
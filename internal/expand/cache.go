@@ -0,0 +1,76 @@
+package expand
+
+import (
+	"context"
+	"sync"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+type cacheKey struct{}
+
+// Cache holds related pages fetched during a run, so every expansion that shares it
+// retrieves each page once however many rows, properties, or calls refer to it.
+type Cache struct {
+	pages map[string]notion.Page
+	users map[string]notion.UserReference
+	mu    sync.Mutex
+}
+
+// NewCache returns an empty Cache.
+func NewCache() *Cache {
+	return &Cache{pages: map[string]notion.Page{}, users: map[string]notion.UserReference{}}
+}
+
+// WithCache returns a context whose FirstLevel expansions share cache.
+func WithCache(ctx context.Context, cache *Cache) context.Context {
+	return context.WithValue(ctx, cacheKey{}, cache)
+}
+
+func cacheFromContext(ctx context.Context) *Cache {
+	cache, _ := ctx.Value(cacheKey{}).(*Cache)
+	return cache
+}
+
+// Put stores page unless the cache already holds a version edited at the same time or later.
+func (c *Cache) Put(page notion.Page) {
+	if c == nil || page.ID == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.pages[page.ID]; ok && !page.LastEditedTime.After(cached.LastEditedTime) {
+		return
+	}
+	c.pages[page.ID] = page
+}
+
+// Get returns the cached page with the given ID.
+func (c *Cache) Get(id string) (notion.Page, bool) {
+	if c == nil {
+		return notion.Page{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	page, ok := c.pages[id]
+	return page, ok
+}
+
+func (c *Cache) user(id string) (notion.UserReference, bool) {
+	if c == nil {
+		return notion.UserReference{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	user, ok := c.users[id]
+	return user, ok
+}
+
+func (c *Cache) putUser(user notion.UserReference) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.users[user.ID] = user
+}
@@ -1,10 +1,10 @@
-// Package expand implements helpers for expanding Notion relation properties.
+// Package expand implements helpers for expanding Notion relation and rollup properties.
 package expand
 
 import (
 	"context"
 	"fmt"
-	"sync"
+	"strings"
 
 	"golang.org/x/sync/errgroup"
 
@@ -12,8 +12,8 @@ import (
 )
 
 const (
-	defaultConcurrency = 3
-	relationType       = "relation"
+	relationType = "relation"
+	rollupType   = "rollup"
 )
 
 // PageFetcher represents the subset of the Notion client used for relation expansion.
@@ -21,44 +21,99 @@ type PageFetcher interface {
 	RetrievePage(ctx context.Context, pageID string) (notion.Page, error)
 }
 
+// PropertyItemFetcher is implemented by clients that can page through a property's items.
+// FirstLevel uses it, when available, to complete relations truncated in page payloads.
+type PropertyItemFetcher interface {
+	RetrievePageProperty(
+		ctx context.Context,
+		pageID string,
+		propertyID string,
+		startCursor string,
+	) (notion.PropertyItemResponse, error)
+}
+
 type relationRef struct {
 	relationID string
 	propertyID string
 	pageIdx    int
 }
 
-// FirstLevel expands relation properties on the supplied pages using the provided property metadata.
+// FirstLevel expands relation properties on the supplied pages using the provided property
+// metadata, fetching at most concurrency related pages at a time. Rollup properties are
+// expanded too when their array holds relation values.
 func FirstLevel(
 	ctx context.Context,
 	client PageFetcher,
 	pages []notion.Page,
 	properties []notion.PropertyReference,
+	concurrency int,
 ) error {
 	if len(pages) == 0 || len(properties) == 0 {
 		return nil
 	}
 
+	if items, ok := client.(PropertyItemFetcher); ok {
+		if err := CompleteRelations(ctx, items, pages, properties, concurrency); err != nil {
+			return fmt.Errorf("expand relations: %w", err)
+		}
+	}
+
 	refs, ids, propByID := prepareRelationRefs(pages, properties)
 	if len(refs) == 0 {
 		return nil
 	}
 
-	relatedPages, err := fetchRelatedPages(ctx, client, ids)
+	relatedPages, err := retrieveRelated(ctx, client, ids, concurrency)
 	if err != nil {
-		return err
+		return fmt.Errorf("expand relations: %w", err)
 	}
 
 	applyExpandedRelations(pages, refs, propByID, relatedPages)
 	return nil
 }
 
+// retrieveRelated fetches ids, serving and recording pages through the context's Cache.
+func retrieveRelated(
+	ctx context.Context,
+	client PageFetcher,
+	ids []string,
+	concurrency int,
+) (map[string]notion.Page, error) {
+	cache := cacheFromContext(ctx)
+	if cache == nil {
+		return notion.RetrievePagesWith(ctx, client.RetrievePage, ids, concurrency)
+	}
+
+	related := make(map[string]notion.Page, len(ids))
+	missing := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if page, ok := cache.Get(id); ok {
+			related[id] = page
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return related, nil
+	}
+	fetched, err := notion.RetrievePagesWith(ctx, client.RetrievePage, missing, concurrency)
+	if err != nil {
+		return nil, err
+	}
+	for id, page := range fetched {
+		cache.Put(page)
+		related[id] = page
+	}
+	return related, nil
+}
+
 func prepareRelationRefs(
 	pages []notion.Page,
 	properties []notion.PropertyReference,
 ) ([]relationRef, []string, map[string]notion.PropertyReference) {
 	propByID := make(map[string]notion.PropertyReference, len(properties))
 	for _, ref := range properties {
-		if ref.Type == relationType {
+		if Expandable(ref.Type) {
 			propByID[ref.ID] = ref
 		}
 	}
@@ -85,14 +140,14 @@ func collectPageRelations(
 	unique map[string]struct{},
 ) {
 	for _, ref := range properties {
-		if ref.Type != relationType {
+		if !Expandable(ref.Type) {
 			continue
 		}
 		value, ok := page.Properties[ref.Name]
-		if !ok || value.Type != relationType || len(value.Relation) == 0 {
+		if !ok {
 			continue
 		}
-		for _, rel := range value.Relation {
+		for _, rel := range relatedRefs(value) {
 			*refs = append(*refs, relationRef{
 				relationID: rel.ID,
 				propertyID: ref.ID,
@@ -103,48 +158,119 @@ func collectPageRelations(
 	}
 }
 
-func fetchRelatedPages(
+type truncatedRelation struct {
+	name       string
+	propertyID string
+	pageIdx    int
+	relations  []notion.RelationReference
+}
+
+// CompleteRelations replaces relation values that Notion truncated (has_more) with the full
+// list read from the property item endpoint.
+func CompleteRelations(
 	ctx context.Context,
-	client PageFetcher,
-	ids []string,
-) (map[string]notion.Page, error) {
-	if len(ids) == 0 {
-		return map[string]notion.Page{}, nil
+	client PropertyItemFetcher,
+	pages []notion.Page,
+	properties []notion.PropertyReference,
+	concurrency int,
+) error {
+	var truncated []*truncatedRelation
+	for pageIdx, page := range pages {
+		for _, ref := range properties {
+			value, ok := page.Properties[ref.Name]
+			if !ok || value.Type != relationType || !value.HasMore {
+				continue
+			}
+			propertyID := value.ID
+			if propertyID == "" {
+				propertyID = ref.ID
+			}
+			truncated = append(truncated, &truncatedRelation{
+				name:       ref.Name,
+				propertyID: propertyID,
+				pageIdx:    pageIdx,
+			})
+		}
+	}
+	if len(truncated) == 0 {
+		return nil
 	}
 
-	result := make(map[string]notion.Page, len(ids))
-	var mu sync.Mutex
-
-	sem := make(chan struct{}, defaultConcurrency)
 	g, groupCtx := errgroup.WithContext(ctx)
-
-	for _, id := range ids {
-		relationID := id
+	g.SetLimit(max(1, concurrency))
+	for _, rel := range truncated {
+		pageID := pages[rel.pageIdx].ID
 		g.Go(func() error {
-			select {
-			case sem <- struct{}{}:
-			case <-groupCtx.Done():
-				return groupCtx.Err()
-			}
-			defer func() { <-sem }()
-
-			page, err := client.RetrievePage(groupCtx, relationID)
+			relations, err := relationItems(groupCtx, client, pageID, rel.propertyID)
 			if err != nil {
-				return fmt.Errorf("retrieve related page %s: %w", relationID, err)
+				return fmt.Errorf("retrieve %s of page %s: %w", rel.name, pageID, err)
 			}
-
-			mu.Lock()
-			result[relationID] = page
-			mu.Unlock()
+			rel.relations = relations
 			return nil
 		})
 	}
-
 	if err := g.Wait(); err != nil {
-		return nil, fmt.Errorf("expand relations: %w", err)
+		return err
+	}
+
+	for _, rel := range truncated {
+		value := pages[rel.pageIdx].Properties[rel.name]
+		value.Relation, value.HasMore = rel.relations, false
+		pages[rel.pageIdx].Properties[rel.name] = value
 	}
+	return nil
+}
 
-	return result, nil
+func relationItems(
+	ctx context.Context,
+	client PropertyItemFetcher,
+	pageID string,
+	propertyID string,
+) ([]notion.RelationReference, error) {
+	var relations []notion.RelationReference
+	cursor := ""
+	for {
+		resp, err := client.RetrievePageProperty(ctx, pageID, propertyID, cursor)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range resp.Results {
+			if item.Relation != nil {
+				relations = append(relations, *item.Relation)
+			}
+		}
+		if !resp.HasMore || resp.NextCursor == "" {
+			return relations, nil
+		}
+		cursor = resp.NextCursor
+	}
+}
+
+// Expandable reports whether properties of typ can be expanded into related pages.
+func Expandable(typ string) bool {
+	return typ == relationType || typ == rollupType
+}
+
+// relatedRefs returns the page references held by a relation value, or by the relation
+// items of a rollup array.
+func relatedRefs(value notion.PropertyValue) []notion.RelationReference {
+	switch value.Type {
+	case relationType:
+		return value.Relation
+	case rollupType:
+		if value.Rollup == nil {
+			return nil
+		}
+		var refs []notion.RelationReference
+		for _, item := range value.Rollup.Array {
+			if item.Type == relationType {
+				refs = append(refs, item.Relation...)
+			}
+		}
+		return refs
+	default:
+		return nil
+	}
 }
 
 func applyExpandedRelations(
@@ -172,6 +298,81 @@ func applyExpandedRelations(
 	}
 }
 
+// Project trims the expanded pages on pages down to the named properties. A field matches a
+// property name case-insensitively; "title" also matches the title property under any name.
+func Project(pages []notion.Page, fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+	for i := range pages {
+		for name, related := range pages[i].ExpandedRelations {
+			projected := make([]notion.Page, len(related))
+			for j, page := range related {
+				projected[j] = projectPage(page, fields)
+			}
+			pages[i].ExpandedRelations[name] = projected
+		}
+	}
+}
+
+func projectPage(page notion.Page, fields []string) notion.Page {
+	kept := make(map[string]notion.PropertyValue, len(fields))
+	for name, value := range page.Properties {
+		for _, field := range fields {
+			field = strings.TrimSpace(field)
+			if strings.EqualFold(name, field) || (strings.EqualFold(field, "title") && value.Type == "title") {
+				kept[name] = value
+				break
+			}
+		}
+	}
+	page.Properties = kept
+	return page
+}
+
+// Inline moves each page's expanded relations into the relation references they resolve, so
+// JSON consumers find a related page under its property instead of in a separate map.
+func Inline(pages []notion.Page) {
+	for i := range pages {
+		for name, related := range pages[i].ExpandedRelations {
+			value, ok := pages[i].Properties[name]
+			if !ok {
+				continue
+			}
+			byID := make(map[string]*notion.Page, len(related))
+			for j := range related {
+				byID[related[j].ID] = &related[j]
+			}
+			value.Relation = inlineRefs(value.Relation, byID)
+			if value.Rollup != nil {
+				rollup := *value.Rollup
+				rollup.Array = append([]notion.PropertyValue(nil), rollup.Array...)
+				for k := range rollup.Array {
+					rollup.Array[k].Relation = inlineRefs(rollup.Array[k].Relation, byID)
+				}
+				value.Rollup = &rollup
+			}
+			pages[i].Properties[name] = value
+			delete(pages[i].ExpandedRelations, name)
+		}
+		if len(pages[i].ExpandedRelations) == 0 {
+			pages[i].ExpandedRelations = nil
+		}
+	}
+}
+
+func inlineRefs(refs []notion.RelationReference, byID map[string]*notion.Page) []notion.RelationReference {
+	if len(refs) == 0 {
+		return refs
+	}
+	inlined := make([]notion.RelationReference, len(refs))
+	for i, ref := range refs {
+		ref.Page = byID[ref.ID]
+		inlined[i] = ref
+	}
+	return inlined
+}
+
 func containsPage(pages []notion.Page, id string) bool {
 	for _, p := range pages {
 		if p.ID == id {
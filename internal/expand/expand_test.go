@@ -2,9 +2,12 @@ package expand_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/yourorg/notionctl/internal/expand"
 	"github.com/yourorg/notionctl/internal/notion"
@@ -57,7 +60,7 @@ func TestFirstLevel(t *testing.T) {
 
 	refs := []notion.PropertyReference{{ID: "prop-assignee", Name: "Assignee", Type: "relation"}}
 
-	if err := expand.FirstLevel(context.Background(), client, pages, refs); err != nil {
+	if err := expand.FirstLevel(context.Background(), client, pages, refs, 2); err != nil {
 		t.Fatalf("FirstLevel returned error: %v", err)
 	}
 
@@ -72,3 +75,181 @@ func TestFirstLevel(t *testing.T) {
 		}
 	}
 }
+
+func TestFirstLevelExpandsRollupRelations(t *testing.T) {
+	client := &stubFetcher{
+		pages: map[string]notion.Page{
+			"proj-1": {ID: "proj-1"},
+			"proj-2": {ID: "proj-2"},
+		},
+	}
+	pages := []notion.Page{{
+		ID: "task-1",
+		Properties: map[string]notion.PropertyValue{
+			"Projects": {
+				Type: "rollup",
+				Rollup: &notion.RollupValue{Type: "array", Array: []notion.PropertyValue{
+					{Type: "relation", Relation: []notion.RelationReference{{ID: "proj-1"}, {ID: "proj-2"}}},
+					{Type: "number"},
+				}},
+			},
+		},
+	}}
+	refs := []notion.PropertyReference{{ID: "prop-projects", Name: "Projects", Type: "rollup"}}
+
+	if err := expand.FirstLevel(context.Background(), client, pages, refs, 2); err != nil {
+		t.Fatalf("FirstLevel returned error: %v", err)
+	}
+	expanded := pages[0].ExpandedRelations["Projects"]
+	if len(expanded) != 2 || len(client.requests) != 2 {
+		t.Fatalf("expected both rolled-up pages to be expanded, got %#v", expanded)
+	}
+}
+
+type pagedPropertyFetcher struct {
+	stubFetcher
+	items []string
+}
+
+func (s *pagedPropertyFetcher) RetrievePageProperty(
+	_ context.Context,
+	_ string,
+	_ string,
+	cursor string,
+) (notion.PropertyItemResponse, error) {
+	start := 0
+	if cursor != "" {
+		start = len(s.items) / 2
+	}
+	end := len(s.items)
+	if cursor == "" {
+		end = len(s.items) / 2
+	}
+	resp := notion.PropertyItemResponse{HasMore: cursor == "", NextCursor: "next"}
+	for _, id := range s.items[start:end] {
+		resp.Results = append(resp.Results, notion.PropertyItem{
+			Type:     "relation",
+			Relation: &notion.RelationReference{ID: id},
+		})
+	}
+	return resp, nil
+}
+
+func TestFirstLevelCompletesTruncatedRelations(t *testing.T) {
+	client := &pagedPropertyFetcher{stubFetcher: stubFetcher{pages: map[string]notion.Page{}}}
+	var truncated []notion.RelationReference
+	for i := range 30 {
+		id := fmt.Sprintf("rel-%d", i)
+		client.items = append(client.items, id)
+		client.pages[id] = notion.Page{ID: id}
+		if i < 25 {
+			truncated = append(truncated, notion.RelationReference{ID: id})
+		}
+	}
+	pages := []notion.Page{{
+		ID: "page-1",
+		Properties: map[string]notion.PropertyValue{
+			"Tasks": {ID: "prop-tasks", Type: "relation", Relation: truncated, HasMore: true},
+		},
+	}}
+	refs := []notion.PropertyReference{{ID: "prop-tasks", Name: "Tasks", Type: "relation"}}
+
+	if err := expand.FirstLevel(context.Background(), client, pages, refs, 3); err != nil {
+		t.Fatalf("FirstLevel returned error: %v", err)
+	}
+	value := pages[0].Properties["Tasks"]
+	if len(value.Relation) != 30 || value.HasMore {
+		t.Fatalf("expected the full relation list, got %d (has_more=%v)", len(value.Relation), value.HasMore)
+	}
+	if got := len(pages[0].ExpandedRelations["Tasks"]); got != 30 {
+		t.Fatalf("expected 30 expanded pages, got %d", got)
+	}
+}
+
+func TestProjectKeepsRequestedFields(t *testing.T) {
+	pages := []notion.Page{{
+		ID: "task-1",
+		ExpandedRelations: map[string][]notion.Page{
+			"Project": {{
+				ID: "proj-1",
+				Properties: map[string]notion.PropertyValue{
+					"Project name": {Type: "title"},
+					"Status":       {Type: "status"},
+					"Notes":        {Type: "rich_text"},
+				},
+			}},
+		},
+	}}
+
+	expand.Project(pages, []string{"title", " status"})
+
+	props := pages[0].ExpandedRelations["Project"][0].Properties
+	if len(props) != 2 {
+		t.Fatalf("expected title and Status to be kept, got %v", props)
+	}
+	if _, ok := props["Project name"]; !ok {
+		t.Fatalf("expected the title property to match \"title\", got %v", props)
+	}
+}
+
+func TestFirstLevelSharesCacheAcrossCalls(t *testing.T) {
+	client := &stubFetcher{pages: map[string]notion.Page{"proj-1": {ID: "proj-1"}}}
+	cache := expand.NewCache()
+	ctx := expand.WithCache(context.Background(), cache)
+	refs := []notion.PropertyReference{
+		{ID: "prop-project", Name: "Project", Type: "relation"},
+		{ID: "prop-parent", Name: "Parent project", Type: "relation"},
+	}
+
+	for range 2 {
+		pages := make([]notion.Page, 200)
+		for i := range pages {
+			pages[i] = notion.Page{ID: fmt.Sprintf("task-%d", i), Properties: map[string]notion.PropertyValue{
+				"Project":        {Type: "relation", Relation: []notion.RelationReference{{ID: "proj-1"}}},
+				"Parent project": {Type: "relation", Relation: []notion.RelationReference{{ID: "proj-1"}}},
+			}}
+		}
+		if err := expand.FirstLevel(ctx, client, pages, refs, 3); err != nil {
+			t.Fatalf("FirstLevel returned error: %v", err)
+		}
+		if got := pages[199].ExpandedRelations["Parent project"]; len(got) != 1 || got[0].ID != "proj-1" {
+			t.Fatalf("expected cached expansion, got %#v", got)
+		}
+	}
+	if len(client.requests) != 1 {
+		t.Fatalf("expected one fetch across calls, got %v", client.requests)
+	}
+}
+
+func TestCacheKeepsNewestEdit(t *testing.T) {
+	cache := expand.NewCache()
+	newer := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	cache.Put(notion.Page{ID: "p", LastEditedTime: newer, URL: "new"})
+	cache.Put(notion.Page{ID: "p", LastEditedTime: newer.Add(-time.Hour), URL: "old"})
+	if page, ok := cache.Get("p"); !ok || page.URL != "new" {
+		t.Fatalf("expected the newer edit to be kept, got %+v", page)
+	}
+}
+
+func TestInlineMovesExpandedPagesIntoRelations(t *testing.T) {
+	pages := []notion.Page{{
+		ID: "task-1",
+		Properties: map[string]notion.PropertyValue{
+			"Project": {Type: "relation", Relation: []notion.RelationReference{{ID: "proj-1"}, {ID: "proj-2"}}},
+		},
+		ExpandedRelations: map[string][]notion.Page{"Project": {{ID: "proj-1", URL: "https://notion.so/proj-1"}}},
+	}}
+
+	expand.Inline(pages)
+
+	if pages[0].ExpandedRelations != nil {
+		t.Fatalf("expected the side map to be emptied, got %v", pages[0].ExpandedRelations)
+	}
+	data, err := json.Marshal(pages[0].Properties["Project"])
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"relation":[{"page":{`) || !strings.Contains(string(data), `{"id":"proj-2"}`) {
+		t.Fatalf("expected proj-1 inlined and proj-2 left bare, got %s", data)
+	}
+}
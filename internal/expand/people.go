@@ -0,0 +1,110 @@
+package expand
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+// UserFetcher represents the subset of the Notion client used for people expansion.
+type UserFetcher interface {
+	RetrieveUser(ctx context.Context, userID string) (notion.UserReference, error)
+}
+
+// Fetcher is the subset of the Notion client used by relation and people expansion.
+type Fetcher interface {
+	PageFetcher
+	UserFetcher
+}
+
+// People replaces the user references in people, created_by, and last_edited_by properties
+// with full user objects, so names and emails show even when the payload only carries IDs.
+// Each user is fetched once, at most concurrency at a time, and shared through the
+// context's Cache. A user that cannot be retrieved, such as one the integration cannot see,
+// is reported to log and keeps its bare reference.
+func People(ctx context.Context, client UserFetcher, pages []notion.Page, concurrency int, log io.Writer) error {
+	cache := cacheFromContext(ctx)
+	users := map[string]notion.UserReference{}
+	var missing []string
+	for _, page := range pages {
+		for _, value := range page.Properties {
+			for _, ref := range userRefs(value) {
+				if _, seen := users[ref.ID]; seen || ref.ID == "" {
+					continue
+				}
+				user, ok := cache.user(ref.ID)
+				users[ref.ID] = user
+				if !ok {
+					missing = append(missing, ref.ID)
+				}
+			}
+		}
+	}
+
+	var mu sync.Mutex
+	var g errgroup.Group
+	g.SetLimit(max(1, concurrency))
+	for _, id := range missing {
+		g.Go(func() error {
+			user, err := client.RetrieveUser(ctx, id)
+			if err != nil {
+				if log != nil && ctx.Err() == nil {
+					mu.Lock()
+					_, _ = fmt.Fprintf(log, "warning: retrieve user %s: %v; showing its ID only\n", id, err)
+					mu.Unlock()
+				}
+				return nil
+			}
+			cache.putUser(user)
+			mu.Lock()
+			users[id] = user
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("expand people: %w", err)
+	}
+
+	for i := range pages {
+		for name, value := range pages[i].Properties {
+			for j, ref := range value.People {
+				value.People[j] = resolvedUser(users, ref)
+			}
+			if value.CreatedBy != nil {
+				user := resolvedUser(users, *value.CreatedBy)
+				value.CreatedBy = &user
+			}
+			if value.LastEditedBy != nil {
+				user := resolvedUser(users, *value.LastEditedBy)
+				value.LastEditedBy = &user
+			}
+			pages[i].Properties[name] = value
+		}
+	}
+	return nil
+}
+
+func userRefs(value notion.PropertyValue) []notion.UserReference {
+	refs := append([]notion.UserReference(nil), value.People...)
+	if value.CreatedBy != nil {
+		refs = append(refs, *value.CreatedBy)
+	}
+	if value.LastEditedBy != nil {
+		refs = append(refs, *value.LastEditedBy)
+	}
+	return refs
+}
+
+func resolvedUser(users map[string]notion.UserReference, ref notion.UserReference) notion.UserReference {
+	if user, ok := users[ref.ID]; ok && user.ID != "" {
+		return user
+	}
+	return ref
+}
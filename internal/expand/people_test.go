@@ -0,0 +1,87 @@
+package expand_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/expand"
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+type stubUsers struct {
+	hidden   map[string]bool
+	requests []string
+}
+
+func (s *stubUsers) RetrieveUser(_ context.Context, id string) (notion.UserReference, error) {
+	s.requests = append(s.requests, id)
+	if s.hidden[id] {
+		return notion.UserReference{}, errors.New("object_not_found")
+	}
+	return notion.UserReference{
+		Object: "user",
+		ID:     id,
+		Name:   "User " + id,
+		Type:   "person",
+		Person: &notion.PersonDetails{Email: id + "@example.com"},
+	}, nil
+}
+
+func TestPeopleResolvesUserReferences(t *testing.T) {
+	client := &stubUsers{}
+	ctx := expand.WithCache(context.Background(), expand.NewCache())
+	newPages := func() []notion.Page {
+		return []notion.Page{{
+			ID: "task-1",
+			Properties: map[string]notion.PropertyValue{
+				"Owners":  {Type: "people", People: []notion.UserReference{{Object: "user", ID: "u1"}, {ID: "u2"}}},
+				"Creator": {Type: "created_by", CreatedBy: &notion.UserReference{Object: "user", ID: "u1"}},
+			},
+		}}
+	}
+
+	pages := newPages()
+	if err := expand.People(ctx, client, pages, 1, io.Discard); err != nil {
+		t.Fatalf("People returned error: %v", err)
+	}
+	owners := pages[0].Properties["Owners"].People
+	if owners[0].Name != "User u1" || owners[1].Person == nil || owners[1].Person.Email != "u2@example.com" {
+		t.Fatalf("expected people to be resolved, got %+v", owners)
+	}
+	if creator := pages[0].Properties["Creator"].CreatedBy; creator.Name != "User u1" {
+		t.Fatalf("expected created_by to be resolved, got %+v", creator)
+	}
+
+	if err := expand.People(ctx, client, newPages(), 1, io.Discard); err != nil {
+		t.Fatalf("People returned error: %v", err)
+	}
+	if len(client.requests) != 2 {
+		t.Fatalf("expected each user to be fetched once, got %v", client.requests)
+	}
+}
+
+func TestPeopleKeepsUsersThatCannotBeRetrieved(t *testing.T) {
+	client := &stubUsers{hidden: map[string]bool{"guest": true}}
+	pages := []notion.Page{{
+		ID: "task-1",
+		Properties: map[string]notion.PropertyValue{
+			"Owners": {Type: "people", People: []notion.UserReference{{Object: "user", ID: "guest"}, {ID: "u1"}}},
+		},
+	}}
+
+	var log bytes.Buffer
+	if err := expand.People(context.Background(), client, pages, 1, &log); err != nil {
+		t.Fatalf("People returned error: %v", err)
+	}
+	owners := pages[0].Properties["Owners"].People
+	if owners[0].ID != "guest" || owners[0].Name != "" || owners[1].Name != "User u1" {
+		t.Fatalf("expected the guest to keep its bare reference, got %+v", owners)
+	}
+	if !strings.Contains(log.String(), "retrieve user guest") {
+		t.Fatalf("expected a warning for the guest, got %q", log.String())
+	}
+}
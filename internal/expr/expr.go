@@ -0,0 +1,256 @@
+// Package expr evaluates small value expressions used to derive property values from other
+// properties of the same row, for example:
+//
+//	quarter(Due)
+//	concat(year(Due), "-", upper(prop("Team Name")))
+//
+// Bare identifiers refer to properties by name; prop("...") reaches names with spaces or
+// punctuation. Every value is text, and an empty string means "no value".
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Lookup returns the text value of the named property for the row being evaluated.
+type Lookup func(name string) (string, bool)
+
+// Expr is a parsed expression.
+type Expr interface {
+	Eval(lookup Lookup) (string, error)
+	// Properties reports the property names the expression reads.
+	Properties() []string
+}
+
+// Parse compiles source into an expression.
+func Parse(source string) (Expr, error) {
+	p := &parser{src: []rune(source)}
+	node, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, fmt.Errorf("unexpected %q at offset %d", string(p.src[p.pos]), p.pos)
+	}
+	return node, nil
+}
+
+type literal string
+
+func (l literal) Eval(Lookup) (string, error) { return string(l), nil }
+
+func (l literal) Properties() []string { return nil }
+
+type property string
+
+func (p property) Eval(lookup Lookup) (string, error) {
+	value, ok := lookup(string(p))
+	if !ok {
+		return "", fmt.Errorf("unknown property %q", string(p))
+	}
+	return value, nil
+}
+
+func (p property) Properties() []string { return []string{string(p)} }
+
+type call struct {
+	fn   function
+	name string
+	args []Expr
+}
+
+func (c call) Eval(lookup Lookup) (string, error) {
+	values := make([]string, 0, len(c.args))
+	for _, arg := range c.args {
+		value, err := arg.Eval(lookup)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, value)
+	}
+	out, err := c.fn.eval(values)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", c.name, err)
+	}
+	return out, nil
+}
+
+func (c call) Properties() []string {
+	var names []string
+	for _, arg := range c.args {
+		names = append(names, arg.Properties()...)
+	}
+	return names
+}
+
+// function describes a built-in; arity -1 accepts one or more arguments.
+type function struct {
+	eval  func(args []string) (string, error)
+	arity int
+}
+
+var functions = map[string]function{
+	"quarter": {arity: 1, eval: dateFunc(func(t time.Time) string { return fmt.Sprintf("Q%d", (int(t.Month())-1)/3+1) })},
+	"year":    {arity: 1, eval: dateFunc(func(t time.Time) string { return t.Format("2006") })},
+	"month":   {arity: 1, eval: dateFunc(func(t time.Time) string { return t.Format("01") })},
+	"weekday": {arity: 1, eval: dateFunc(func(t time.Time) string { return t.Weekday().String() })},
+	"upper":   {arity: 1, eval: textFunc(strings.ToUpper)},
+	"lower":   {arity: 1, eval: textFunc(strings.ToLower)},
+	"trim":    {arity: 1, eval: textFunc(strings.TrimSpace)},
+	"concat":  {arity: -1, eval: func(args []string) (string, error) { return strings.Join(args, ""), nil }},
+	"coalesce": {arity: -1, eval: func(args []string) (string, error) {
+		for _, arg := range args {
+			if arg != "" {
+				return arg, nil
+			}
+		}
+		return "", nil
+	}},
+}
+
+func textFunc(fn func(string) string) func([]string) (string, error) {
+	return func(args []string) (string, error) { return fn(args[0]), nil }
+}
+
+// dateFunc parses the leading YYYY-MM-DD of its argument, so date ranges and timestamps
+// use their start date. Empty input yields empty output.
+func dateFunc(fn func(time.Time) string) func([]string) (string, error) {
+	return func(args []string) (string, error) {
+		raw := strings.TrimSpace(args[0])
+		if raw == "" {
+			return "", nil
+		}
+		if len(raw) > len(time.DateOnly) {
+			raw = raw[:len(time.DateOnly)]
+		}
+		t, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return "", fmt.Errorf("expected a date, got %q", args[0])
+		}
+		return fn(t), nil
+	}
+}
+
+type parser struct {
+	src []rune
+	pos int
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *parser) parseExpr() (Expr, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, errors.New("unexpected end of expression")
+	}
+	r := p.src[p.pos]
+	switch {
+	case r == '"' || r == '\'':
+		text, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return literal(text), nil
+	case unicode.IsDigit(r) || r == '-':
+		return literal(p.scan(func(r rune) bool { return unicode.IsDigit(r) || r == '.' || r == '-' })), nil
+	case isIdentRune(r):
+		name := p.scan(isIdentRune)
+		p.skipSpace()
+		if p.pos < len(p.src) && p.src[p.pos] == '(' {
+			return p.parseCall(name)
+		}
+		return property(name), nil
+	default:
+		return nil, fmt.Errorf("unexpected %q at offset %d", string(r), p.pos)
+	}
+}
+
+func (p *parser) parseCall(name string) (Expr, error) {
+	p.pos++ // consume "("
+	var args []Expr
+	p.skipSpace()
+	if p.pos < len(p.src) && p.src[p.pos] == ')' {
+		p.pos++
+	} else {
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			p.skipSpace()
+			if p.pos >= len(p.src) {
+				return nil, fmt.Errorf("%s: missing closing parenthesis", name)
+			}
+			if p.src[p.pos] == ')' {
+				p.pos++
+				break
+			}
+			if p.src[p.pos] != ',' {
+				return nil, fmt.Errorf("%s: expected , or ) at offset %d", name, p.pos)
+			}
+			p.pos++
+		}
+	}
+
+	lower := strings.ToLower(name)
+	if lower == "prop" {
+		if len(args) != 1 {
+			return nil, errors.New("prop takes exactly one argument")
+		}
+		lit, ok := args[0].(literal)
+		if !ok {
+			return nil, errors.New("prop takes a quoted property name")
+		}
+		return property(lit), nil
+	}
+	fn, ok := functions[lower]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q", name)
+	}
+	if (fn.arity >= 0 && len(args) != fn.arity) || (fn.arity < 0 && len(args) == 0) {
+		return nil, fmt.Errorf("%s: wrong number of arguments (%d)", lower, len(args))
+	}
+	return call{fn: fn, name: lower, args: args}, nil
+}
+
+func (p *parser) parseString() (string, error) {
+	quote := p.src[p.pos]
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		r := p.src[p.pos]
+		p.pos++
+		switch {
+		case r == '\\' && p.pos < len(p.src):
+			b.WriteRune(p.src[p.pos])
+			p.pos++
+		case r == quote:
+			return b.String(), nil
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return "", errors.New("unterminated string")
+}
+
+func (p *parser) scan(accept func(rune) bool) string {
+	start := p.pos
+	for p.pos < len(p.src) && accept(p.src[p.pos]) {
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
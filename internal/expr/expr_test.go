@@ -0,0 +1,61 @@
+package expr_test
+
+import (
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/expr"
+)
+
+func TestEval(t *testing.T) {
+	row := map[string]string{
+		"Due":       "2025-05-14 → 2025-05-20",
+		"Team Name": "infra",
+		"Empty":     "",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := row[name]
+		return value, ok
+	}
+
+	cases := map[string]string{
+		`quarter(Due)`: "Q2",
+		`concat(year(Due), "-", upper(prop("Team Name")))`: "2025-INFRA",
+		`coalesce(Empty, 'fallback')`:                      "fallback",
+		`quarter(Empty)`:                                   "",
+		`Due`:                                              "2025-05-14 → 2025-05-20",
+		`month( Due )`:                                     "05",
+	}
+	for source, want := range cases {
+		e, err := expr.Parse(source)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", source, err)
+		}
+		got, err := e.Eval(lookup)
+		if err != nil {
+			t.Fatalf("Eval(%q) returned error: %v", source, err)
+		}
+		if got != want {
+			t.Fatalf("Eval(%q) = %q, want %q", source, got, want)
+		}
+	}
+
+	e, err := expr.Parse(`concat(Due, prop("Team Name"))`)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if props := e.Properties(); len(props) != 2 || props[1] != "Team Name" {
+		t.Fatalf("unexpected properties: %#v", props)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, source := range []string{``, `nope(Due)`, `quarter(Due`, `upper(A, B)`, `prop(Due)`, `"open`, `Due )`} {
+		if _, err := expr.Parse(source); err == nil {
+			t.Fatalf("expected error for %q", source)
+		}
+	}
+	e, _ := expr.Parse(`year(Name)`)
+	if _, err := e.Eval(func(string) (string, bool) { return "not a date", true }); err == nil {
+		t.Fatalf("expected date error")
+	}
+}
@@ -0,0 +1,189 @@
+// Package filelock serializes writes to files shared by concurrent notionctl invocations
+// (for example a cron job and an interactive shell) and replaces files atomically.
+package filelock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	retryInterval  = 50 * time.Millisecond
+	defaultTimeout = 10 * time.Second
+	// staleAfter is how old a lock file must be before it is assumed to belong to a crashed
+	// process. Locks are only held around single writes, so this is generous.
+	staleAfter = 2 * time.Minute
+
+	lockPermissions = 0o600
+)
+
+// ErrTimeout is returned when another process keeps holding the lock.
+var ErrTimeout = errors.New("timed out waiting for lock")
+
+// Acquire takes the lock for path, waiting up to ten seconds for another holder to release
+// it. The returned function releases the lock.
+func Acquire(path string) (func() error, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	return AcquireContext(ctx, path)
+}
+
+// AcquireContext takes the lock for path, retrying until ctx is done. The lock is a sibling
+// file named path+".lock" created exclusively, so it works on every platform and across
+// processes; lock files left behind by crashed processes are removed once they go stale.
+func AcquireContext(ctx context.Context, path string) (func() error, error) {
+	lockPath := path + ".lock"
+	for {
+		// #nosec G304 -- lock files sit next to files notionctl already writes
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, lockPermissions)
+		if err == nil {
+			_, _ = f.WriteString(strconv.Itoa(os.Getpid()) + "\n")
+			if err := f.Close(); err != nil {
+				_ = os.Remove(lockPath)
+				return nil, fmt.Errorf("lock %s: %w", path, err)
+			}
+			return func() error {
+				if err := os.Remove(lockPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+					return fmt.Errorf("unlock %s: %w", path, err)
+				}
+				return nil
+			}, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("lock %s: %w", path, err)
+		}
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > staleAfter {
+			removeStale(lockPath, info)
+			continue
+		}
+
+		timer := time.NewTimer(retryInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("lock %s: %w (remove %s if no other notionctl is running)", path, ErrTimeout, lockPath)
+		case <-timer.C:
+		}
+	}
+}
+
+// removeStale removes the stale lock file seen at lockPath, unless another process already
+// replaced it. Removals are serialized through a second exclusive file, so a process that
+// saw the old lock cannot remove the fresh lock of one that took it over.
+func removeStale(lockPath string, seen fs.FileInfo) {
+	guardPath := lockPath + ".stale"
+	// #nosec G304 -- lock files sit next to files notionctl already writes
+	guard, err := os.OpenFile(guardPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, lockPermissions)
+	if err != nil {
+		// Another process is clearing the lock; a guard left by a crash goes stale too.
+		if info, statErr := os.Stat(guardPath); statErr == nil && time.Since(info.ModTime()) > staleAfter {
+			_ = os.Remove(guardPath)
+		}
+		return
+	}
+	_ = guard.Close()
+	defer os.Remove(guardPath) //nolint:errcheck // a leftover guard goes stale like a lock
+
+	current, err := os.Stat(lockPath)
+	if err == nil && os.SameFile(current, seen) && time.Since(current.ModTime()) > staleAfter {
+		_ = os.Remove(lockPath)
+	}
+}
+
+// With runs fn while holding the lock for path.
+func With(path string, fn func() error) (err error) {
+	unlock, err := Acquire(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if unlockErr := unlock(); err == nil {
+			err = unlockErr
+		}
+	}()
+	return fn()
+}
+
+// WriteFile replaces path with data via a temporary file in the same directory and a rename,
+// so readers never see partial content. When path is a symlink, the file it points to is
+// replaced and the link is kept.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	return WriteFileFunc(path, perm, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// WriteFileFunc replaces path like WriteFile with whatever write streams into the temporary
+// file, so large content never has to be held in memory. path is left untouched when write
+// fails.
+func WriteFileFunc(path string, perm os.FileMode, write func(w io.Writer) error) error {
+	path, err := resolveTarget(path)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".notionctl-*")
+	if err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // best effort after a successful rename
+	if err := write(tmp); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+// resolveTarget follows symlinks so a rename replaces the file a link points to, such as a
+// config file managed from a dotfiles repository, instead of the link. A path that does not
+// exist yet is returned as is.
+func resolveTarget(path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return path, nil
+	}
+	return resolved, err
+}
+
+// Append adds data to the end of path under the lock, creating the file if needed.
+func Append(path string, data []byte, perm os.FileMode) error {
+	return With(path, func() error {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, perm) // #nosec G304 -- caller-chosen path
+		if err != nil {
+			return fmt.Errorf("open %s: %w", path, err)
+		}
+		if _, err := f.Write(data); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("write %s: %w", path, err)
+		}
+		if err := f.Sync(); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("sync %s: %w", path, err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("close %s: %w", path, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,209 @@
+package filelock_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/filelock"
+)
+
+func TestAcquireContextWaitsForHolder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	unlock, err := filelock.Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := filelock.AcquireContext(ctx, path); !errors.Is(err, filelock.ErrTimeout) {
+		t.Fatalf("expected ErrTimeout while the lock is held, got %v", err)
+	}
+
+	if err := unlock(); err != nil {
+		t.Fatalf("unlock returned error: %v", err)
+	}
+	unlock, err = filelock.Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire after unlock returned error: %v", err)
+	}
+	if err := unlock(); err != nil {
+		t.Fatalf("unlock returned error: %v", err)
+	}
+}
+
+func TestAcquireRemovesStaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path+".lock", []byte("12345\n"), 0o600); err != nil {
+		t.Fatalf("write lock file: %v", err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path+".lock", old, old); err != nil {
+		t.Fatalf("age lock file: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	unlock, err := filelock.AcquireContext(ctx, path)
+	if err != nil {
+		t.Fatalf("expected stale lock to be taken over, got %v", err)
+	}
+	if err := unlock(); err != nil {
+		t.Fatalf("unlock returned error: %v", err)
+	}
+}
+
+func TestAppendSerializesWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.jsonl")
+	line := strings.Repeat("x", 4096) + "\n"
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := filelock.Append(path, []byte(line), 0o600); err != nil {
+				t.Errorf("Append returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	if got := strings.Count(string(data), line); got != 20 {
+		t.Fatalf("expected 20 intact lines, got %d", got)
+	}
+	if _, err := os.Stat(path + ".lock"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected lock file to be released, got %v", err)
+	}
+}
+
+func TestConcurrentStaleLockTakeoverIsExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path+".lock", []byte("12345\n"), 0o600); err != nil {
+		t.Fatalf("write lock file: %v", err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path+".lock", old, old); err != nil {
+		t.Fatalf("age lock file: %v", err)
+	}
+
+	var mu sync.Mutex
+	holders, most := 0, 0
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			unlock, err := filelock.AcquireContext(ctx, path)
+			if err != nil {
+				t.Errorf("AcquireContext returned error: %v", err)
+				return
+			}
+			mu.Lock()
+			holders++
+			most = max(most, holders)
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			holders--
+			mu.Unlock()
+			if err := unlock(); err != nil {
+				t.Errorf("unlock returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if most != 1 {
+		t.Fatalf("expected the stale lock to be taken over by one process at a time, %d held it at once", most)
+	}
+}
+
+func TestWriteFileKeepsSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(target, []byte("old"), 0o600); err != nil {
+		t.Fatalf("seed file: %v", err)
+	}
+	link := filepath.Join(dir, "config.yaml")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+
+	if err := filelock.WriteFile(link, []byte("new"), 0o600); err != nil {
+		t.Fatalf("WriteFile returned error: %v", err)
+	}
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("expected the symlink to be kept, got %v, %v", info, err)
+	}
+	if data, err := os.ReadFile(target); err != nil || string(data) != "new" {
+		t.Fatalf("expected the link target to be rewritten, got %q (%v)", data, err)
+	}
+}
+
+func TestWriteFileReplacesAtomically(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+	if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
+		t.Fatalf("seed file: %v", err)
+	}
+	if err := filelock.WriteFile(path, []byte("new"), 0o600); err != nil {
+		t.Fatalf("WriteFile returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "new" {
+		t.Fatalf("expected new content, got %q (%v)", data, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("expected 0600 permissions, got %o", perm)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected only the target file to remain, got %d entries", len(entries))
+	}
+}
+
+func TestWriteFileFuncKeepsOldContentOnError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "backup.tar.gz")
+	if err := os.WriteFile(path, []byte("old"), 0o600); err != nil {
+		t.Fatalf("seed file: %v", err)
+	}
+	err := filelock.WriteFileFunc(path, 0o600, func(w io.Writer) error {
+		_, _ = io.WriteString(w, "partial")
+		return errors.New("read failed")
+	})
+	if err == nil {
+		t.Fatalf("expected the write error to be returned")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "old" {
+		t.Fatalf("expected the old content to remain, got %q (%v)", data, err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Fatalf("expected the temporary file to be removed, got %d entries", len(entries))
+	}
+}
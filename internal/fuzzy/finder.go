@@ -0,0 +1,367 @@
+package fuzzy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// ErrCancelled is returned by Find when the user leaves without choosing an item.
+var ErrCancelled = errors.New("selection cancelled")
+
+const defaultHeight = 12
+
+// Item is one candidate. Only Label is matched; Detail is shown dimmed beside it.
+type Item struct {
+	Label  string
+	Detail string
+	Value  string
+}
+
+// FinderOptions configures Find.
+type FinderOptions struct {
+	Prompt string
+	Query  string
+	Height int
+}
+
+// Find runs an interactive finder on tty, which must be a terminal. Items may keep arriving
+// on items while the user types; close the channel once all have been sent.
+func Find(ctx context.Context, tty *os.File, items <-chan Item, opts FinderOptions) (Item, error) {
+	fd := int(tty.Fd())
+	if !term.IsTerminal(fd) {
+		return Item{}, errors.New("fuzzy finder needs a terminal")
+	}
+	saved, err := term.MakeRaw(fd)
+	if err != nil {
+		return Item{}, fmt.Errorf("enter raw mode: %w", err)
+	}
+	defer term.Restore(fd, saved) //nolint:errcheck // nothing useful to do if restoring fails
+	// Unblock the key reader once we are done with the terminal.
+	defer tty.SetReadDeadline(time.Now()) //nolint:errcheck // best effort
+
+	return Run(ctx, tty, tty, items, opts)
+}
+
+// Run drives the finder with raw key presses read from in, drawing on out. Find sets up the
+// terminal and calls Run; tests and other front ends can call it directly.
+func Run(ctx context.Context, in io.Reader, out io.Writer, items <-chan Item, opts FinderOptions) (Item, error) {
+	keys := make(chan []key)
+	go readKeys(in, keys)
+
+	m := newModel(opts.Query)
+	m.loading = items != nil
+	screen := &screen{out: out, prompt: opts.Prompt, height: opts.Height}
+	if screen.prompt == "" {
+		screen.prompt = "> "
+	}
+	if screen.height <= 0 {
+		screen.height = defaultHeight
+	}
+	defer screen.clear()
+
+	for {
+		items = m.drain(items)
+		width, rows := terminalSize(out)
+		screen.draw(m, width, rows)
+
+		select {
+		case <-ctx.Done():
+			return Item{}, ctx.Err()
+		case item, ok := <-items:
+			if !ok {
+				items, m.loading = nil, false
+				m.refilter()
+				continue
+			}
+			m.add(item)
+		case pressed, ok := <-keys:
+			if !ok {
+				return Item{}, ErrCancelled
+			}
+			for _, k := range pressed {
+				switch m.handle(k) {
+				case actionAccept:
+					if selected, ok := m.selected(); ok {
+						return selected, nil
+					}
+				case actionCancel:
+					return Item{}, ErrCancelled
+				}
+			}
+		}
+	}
+}
+
+func terminalSize(out io.Writer) (int, int) {
+	if f, ok := out.(*os.File); ok {
+		if width, rows, err := term.GetSize(int(f.Fd())); err == nil {
+			return width, rows
+		}
+	}
+	return 80, 24
+}
+
+type keyKind int
+
+const (
+	keyRune keyKind = iota
+	keyEnter
+	keyBackspace
+	keyUp
+	keyDown
+	keyClear
+	keyCancel
+	keyIgnore
+)
+
+type key struct {
+	kind keyKind
+	r    rune
+}
+
+func readKeys(r io.Reader, out chan<- []key) {
+	defer close(out)
+	buf := make([]byte, 256)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			out <- parseKeys(buf[:n])
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// parseKeys decodes one read from a raw-mode terminal into key presses.
+func parseKeys(buf []byte) []key {
+	var keys []key
+	for len(buf) > 0 {
+		switch b := buf[0]; {
+		case b == 0x1b:
+			if len(buf) >= 3 && (buf[1] == '[' || buf[1] == 'O') {
+				switch buf[2] {
+				case 'A':
+					keys = append(keys, key{kind: keyUp})
+				case 'B':
+					keys = append(keys, key{kind: keyDown})
+				default:
+					keys = append(keys, key{kind: keyIgnore})
+				}
+				buf = buf[3:]
+				continue
+			}
+			keys = append(keys, key{kind: keyCancel})
+			buf = buf[1:]
+		case b == '\r' || b == '\n':
+			keys = append(keys, key{kind: keyEnter})
+			buf = buf[1:]
+		case b == 0x7f || b == 0x08:
+			keys = append(keys, key{kind: keyBackspace})
+			buf = buf[1:]
+		case b == 0x10 || b == 0x0b: // Ctrl-P, Ctrl-K
+			keys = append(keys, key{kind: keyUp})
+			buf = buf[1:]
+		case b == 0x0e: // Ctrl-N
+			keys = append(keys, key{kind: keyDown})
+			buf = buf[1:]
+		case b == 0x15: // Ctrl-U
+			keys = append(keys, key{kind: keyClear})
+			buf = buf[1:]
+		case b == 0x03 || b == 0x04 || b == 0x07: // Ctrl-C, Ctrl-D, Ctrl-G
+			keys = append(keys, key{kind: keyCancel})
+			buf = buf[1:]
+		case b < 0x20:
+			keys = append(keys, key{kind: keyIgnore})
+			buf = buf[1:]
+		default:
+			r, size := utf8.DecodeRune(buf)
+			keys = append(keys, key{kind: keyRune, r: r})
+			buf = buf[size:]
+		}
+	}
+	return keys
+}
+
+type action int
+
+const (
+	actionNone action = iota
+	actionAccept
+	actionCancel
+)
+
+// model is the finder state, kept free of terminal I/O so it can be tested directly.
+type model struct {
+	items   []Item
+	labels  []string
+	matches []int
+	query   []rune
+	cursor  int
+	loading bool
+}
+
+func newModel(query string) *model {
+	return &model{query: []rune(query)}
+}
+
+func (m *model) add(items ...Item) {
+	if len(items) == 0 {
+		return
+	}
+	for _, item := range items {
+		m.items = append(m.items, item)
+		m.labels = append(m.labels, item.Label)
+	}
+	m.refilter()
+}
+
+// drain adds every item already waiting on items without blocking, returning nil once the
+// channel is closed.
+func (m *model) drain(items <-chan Item) <-chan Item {
+	var batch []Item
+	defer func() { m.add(batch...) }()
+	for items != nil {
+		select {
+		case item, ok := <-items:
+			if !ok {
+				m.loading = false
+				return nil
+			}
+			batch = append(batch, item)
+		default:
+			return items
+		}
+	}
+	return nil
+}
+
+func (m *model) refilter() {
+	m.matches = Rank(string(m.query), m.labels)
+	m.cursor = min(m.cursor, max(0, len(m.matches)-1))
+}
+
+func (m *model) handle(k key) action {
+	switch k.kind {
+	case keyRune:
+		if unicode.IsPrint(k.r) {
+			m.query = append(m.query, k.r)
+			m.cursor = 0
+			m.refilter()
+		}
+	case keyBackspace:
+		if len(m.query) > 0 {
+			m.query = m.query[:len(m.query)-1]
+			m.cursor = 0
+			m.refilter()
+		}
+	case keyClear:
+		m.query, m.cursor = nil, 0
+		m.refilter()
+	case keyUp:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case keyDown:
+		if m.cursor < len(m.matches)-1 {
+			m.cursor++
+		}
+	case keyEnter:
+		return actionAccept
+	case keyCancel:
+		return actionCancel
+	}
+	return actionNone
+}
+
+func (m *model) selected() (Item, bool) {
+	if len(m.matches) == 0 {
+		return Item{}, false
+	}
+	return m.items[m.matches[m.cursor]], true
+}
+
+// view renders the prompt line followed by up to height result lines, each cut to width.
+func (m *model) view(prompt string, height, width int) []string {
+	status := fmt.Sprintf("%d/%d", len(m.matches), len(m.items))
+	if m.loading {
+		status += " loading…"
+	}
+	lines := []string{fit(prompt+string(m.query), width-len(status)-2) + "  \x1b[2m" + status + "\x1b[0m"}
+
+	start := 0
+	if m.cursor >= height {
+		start = m.cursor - height + 1
+	}
+	for i := start; i < len(m.matches) && i < start+height; i++ {
+		item := m.items[m.matches[i]]
+		marker := "  "
+		if i == m.cursor {
+			marker = "\x1b[7m>\x1b[0m "
+		}
+		line := fit(item.Label, width-2)
+		if room := width - 2 - utf8.RuneCountInString(line) - 2; item.Detail != "" && room > 0 {
+			line += "  \x1b[2m" + fit(item.Detail, room) + "\x1b[0m"
+		}
+		lines = append(lines, marker+line)
+	}
+	return lines
+}
+
+func fit(text string, width int) string {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, text)
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	if width == 1 {
+		return "…"
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// screen draws the finder below the cursor and returns the cursor to the prompt line, so
+// each redraw starts from the same place.
+type screen struct {
+	out    io.Writer
+	prompt string
+	height int
+}
+
+func (s *screen) draw(m *model, width, rows int) {
+	height := min(s.height, max(1, rows-1))
+	lines := m.view(s.prompt, height, width)
+
+	var b strings.Builder
+	b.WriteString("\r\x1b[J")
+	b.WriteString(strings.Join(lines, "\r\n"))
+	if len(lines) > 1 {
+		fmt.Fprintf(&b, "\x1b[%dA", len(lines)-1)
+	}
+	b.WriteString("\r")
+	if col := utf8.RuneCountInString(s.prompt) + len(m.query); col > 0 {
+		fmt.Fprintf(&b, "\x1b[%dC", min(col, width-1))
+	}
+	_, _ = io.WriteString(s.out, b.String())
+}
+
+func (s *screen) clear() {
+	_, _ = io.WriteString(s.out, "\r\x1b[J")
+}
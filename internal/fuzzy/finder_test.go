@@ -0,0 +1,61 @@
+package fuzzy_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/fuzzy"
+)
+
+func preloaded(labels ...string) <-chan fuzzy.Item {
+	items := make(chan fuzzy.Item, len(labels))
+	for _, label := range labels {
+		items <- fuzzy.Item{Label: label, Value: "id-" + label}
+	}
+	close(items)
+	return items
+}
+
+func TestRunSelectsBestMatch(t *testing.T) {
+	var screen bytes.Buffer
+	items := preloaded("Payments backlog", "Platform: API gateway", "API")
+
+	got, err := fuzzy.Run(context.Background(), strings.NewReader("api\r"), &screen, items, fuzzy.FinderOptions{})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if got.Value != "id-API" {
+		t.Fatalf("selected %+v, want the exact match", got)
+	}
+	if !strings.Contains(screen.String(), "Platform: API gateway") {
+		t.Fatalf("expected candidates to be drawn, got %q", screen.String())
+	}
+}
+
+func TestRunKeys(t *testing.T) {
+	items := []string{"alpha", "beta", "gamma"}
+
+	// Arrow down, then Ctrl-P back up, then down twice.
+	keys := "\x1b[B\x10\x0e\x0e\r"
+	got, err := fuzzy.Run(context.Background(), strings.NewReader(keys), &bytes.Buffer{}, preloaded(items...), fuzzy.FinderOptions{})
+	if err != nil || got.Label != "gamma" {
+		t.Fatalf("navigation selected %+v, %v", got, err)
+	}
+
+	// Typing, backspacing, and Ctrl-U clearing the query.
+	keys = "zz\x7f\x15b\r"
+	got, err = fuzzy.Run(context.Background(), strings.NewReader(keys), &bytes.Buffer{}, preloaded(items...), fuzzy.FinderOptions{})
+	if err != nil || got.Label != "beta" {
+		t.Fatalf("editing selected %+v, %v", got, err)
+	}
+
+	for _, keys := range []string{"\x1b", "\x03", "nomatch\r"} {
+		_, err := fuzzy.Run(context.Background(), strings.NewReader(keys), &bytes.Buffer{}, preloaded(items...), fuzzy.FinderOptions{})
+		if !errors.Is(err, fuzzy.ErrCancelled) {
+			t.Fatalf("keys %q: expected ErrCancelled, got %v", keys, err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Package fuzzy ranks candidate strings against a typed query the way fuzzy finders do:
+// every query character must appear in order, and consecutive or word-start matches score
+// higher. Find wraps the ranking in an interactive terminal finder.
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	matchScore       = 1
+	consecutiveBonus = 4
+	wordStartBonus   = 3
+	prefixBonus      = 6
+)
+
+// Score reports whether query matches text as a case-insensitive subsequence and, if so,
+// how well. An empty query matches everything with score 0.
+func Score(query, text string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return 0, true
+	}
+
+	score, qi := 0, 0
+	prev := ' '
+	lastMatch := -2
+	for i, r := range []rune(text) {
+		if qi < len(q) && unicode.ToLower(r) == q[qi] {
+			score += matchScore
+			if i == lastMatch+1 {
+				score += consecutiveBonus
+			}
+			if i == 0 {
+				score += prefixBonus
+			} else if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+				score += wordStartBonus
+			}
+			lastMatch = i
+			qi++
+		}
+		prev = r
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	// Prefer shorter candidates when scores tie on the matched characters.
+	return score*100 - utf8.RuneCountInString(text), true
+}
+
+// Rank returns the indexes of candidates matching query, best first. Ties keep the input
+// order.
+func Rank(query string, candidates []string) []int {
+	type scored struct {
+		index int
+		score int
+	}
+	var matches []scored
+	for i, candidate := range candidates {
+		if score, ok := Score(query, candidate); ok {
+			matches = append(matches, scored{index: i, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	out := make([]int, len(matches))
+	for i, m := range matches {
+		out[i] = m.index
+	}
+	return out
+}
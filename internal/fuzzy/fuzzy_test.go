@@ -0,0 +1,27 @@
+package fuzzy_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/fuzzy"
+)
+
+func TestRank(t *testing.T) {
+	candidates := []string{
+		"Payments backlog",
+		"Q3 planning",
+		"Platform: API gateway",
+		"Onboarding",
+		"API",
+	}
+	if got, want := fuzzy.Rank("api", candidates), []int{4, 2}; !slices.Equal(got, want) {
+		t.Fatalf("Rank(api) = %v, want %v", got, want)
+	}
+	if got := fuzzy.Rank("zzz", candidates); len(got) != 0 {
+		t.Fatalf("expected no matches, got %v", got)
+	}
+	if got := fuzzy.Rank("", candidates); len(got) != len(candidates) || got[0] != 0 {
+		t.Fatalf("empty query should keep every candidate in order, got %v", got)
+	}
+}
@@ -0,0 +1,237 @@
+// Package github reads issues and pull requests from the GitHub REST API.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the public GitHub REST API.
+const DefaultBaseURL = "https://api.github.com"
+
+const (
+	defaultHTTPTimeout = 30 * time.Second
+	pageSize           = 100
+	errorBodyLimit     = 512
+	apiVersion         = "2022-11-28"
+)
+
+// Client lists repository issues. The zero value talks to api.github.com without a token.
+type Client struct {
+	HTTPClient *http.Client
+	BaseURL    string
+	Token      string
+}
+
+// User is the subset of a GitHub account used on issues.
+type User struct {
+	Login string `json:"login"`
+}
+
+// Label is an issue label.
+type Label struct {
+	Name string `json:"name"`
+}
+
+// Milestone is the milestone an issue belongs to.
+type Milestone struct {
+	Title string `json:"title"`
+}
+
+// Issue is an issue or pull request as returned by the issues endpoint.
+type Issue struct {
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	ClosedAt    *time.Time      `json:"closed_at"`
+	Milestone   *Milestone      `json:"milestone"`
+	PullRequest json.RawMessage `json:"pull_request,omitempty"`
+	Title       string          `json:"title"`
+	Body        string          `json:"body"`
+	State       string          `json:"state"`
+	HTMLURL     string          `json:"html_url"`
+	User        User            `json:"user"`
+	Labels      []Label         `json:"labels"`
+	Assignees   []User          `json:"assignees"`
+	Number      int             `json:"number"`
+}
+
+// IsPullRequest reports whether the issue is a pull request.
+func (i Issue) IsPullRequest() bool {
+	return len(i.PullRequest) > 0 && string(i.PullRequest) != "null"
+}
+
+// ParseRepo splits an "owner/name" repository reference.
+func ParseRepo(repo string) (string, string, error) {
+	owner, name, ok := strings.Cut(strings.TrimSpace(repo), "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("repository %q must be owner/name", repo)
+	}
+	return owner, name, nil
+}
+
+// Issues yields every issue and pull request in repo, open or closed, oldest update first.
+// A non-zero since limits the results to those updated at or after it.
+func (c *Client) Issues(ctx context.Context, repo string, since time.Time) iter.Seq2[Issue, error] {
+	return func(yield func(Issue, error) bool) {
+		owner, name, err := ParseRepo(repo)
+		if err != nil {
+			yield(Issue{}, err)
+			return
+		}
+		query := url.Values{}
+		query.Set("state", "all")
+		query.Set("sort", "updated")
+		query.Set("direction", "asc")
+		query.Set("per_page", fmt.Sprint(pageSize))
+		if !since.IsZero() {
+			query.Set("since", since.UTC().Format(time.RFC3339))
+		}
+		next := fmt.Sprintf("%s/repos/%s/%s/issues?%s",
+			c.baseURL(), url.PathEscape(owner), url.PathEscape(name), query.Encode())
+
+		for next != "" {
+			var page []Issue
+			next, err = c.get(ctx, next, &page)
+			if err != nil {
+				yield(Issue{}, err)
+				return
+			}
+			for _, issue := range page {
+				if !yield(issue, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// get decodes one response into out and returns the rel="next" link, if any.
+func (c *Client) get(ctx context.Context, target string, out any) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return "", fmt.Errorf("build github request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("github request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, errorBodyLimit))
+		return "", fmt.Errorf("github request: %s: %s", resp.Status, bytes.TrimSpace(snippet))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return "", fmt.Errorf("decode github response: %w", err)
+	}
+	return nextLink(resp.Header.Get("Link")), nil
+}
+
+func (c *Client) baseURL() string {
+	if c.BaseURL == "" {
+		return DefaultBaseURL
+	}
+	return strings.TrimRight(c.BaseURL, "/")
+}
+
+// nextLink extracts the rel="next" target from a Link header.
+func nextLink(header string) string {
+	for part := range strings.SplitSeq(header, ",") {
+		target, params, ok := strings.Cut(part, ";")
+		if !ok {
+			continue
+		}
+		for param := range strings.SplitSeq(params, ";") {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(target), "<>")
+			}
+		}
+	}
+	return ""
+}
+
+// ErrUnknownField is returned by Field for names it does not recognise.
+var ErrUnknownField = errors.New("unknown issue field")
+
+// Fields lists the names accepted by Field.
+var Fields = []string{
+	"number", "title", "body", "state", "type", "url", "author", "labels", "assignees",
+	"milestone", "created_at", "updated_at", "closed_at",
+}
+
+// Field renders one issue field as text. Lists are comma-separated and unset values are empty.
+func (i Issue) Field(name string) (string, error) {
+	switch name {
+	case "number":
+		return fmt.Sprint(i.Number), nil
+	case "title":
+		return i.Title, nil
+	case "body":
+		return i.Body, nil
+	case "state":
+		return i.State, nil
+	case "type":
+		if i.IsPullRequest() {
+			return "pull_request", nil
+		}
+		return "issue", nil
+	case "url":
+		return i.HTMLURL, nil
+	case "author":
+		return i.User.Login, nil
+	case "labels":
+		names := make([]string, 0, len(i.Labels))
+		for _, label := range i.Labels {
+			names = append(names, label.Name)
+		}
+		return strings.Join(names, ","), nil
+	case "assignees":
+		logins := make([]string, 0, len(i.Assignees))
+		for _, user := range i.Assignees {
+			logins = append(logins, user.Login)
+		}
+		return strings.Join(logins, ","), nil
+	case "milestone":
+		if i.Milestone == nil {
+			return "", nil
+		}
+		return i.Milestone.Title, nil
+	case "created_at":
+		return formatTime(i.CreatedAt), nil
+	case "updated_at":
+		return formatTime(i.UpdatedAt), nil
+	case "closed_at":
+		if i.ClosedAt == nil {
+			return "", nil
+		}
+		return formatTime(*i.ClosedAt), nil
+	default:
+		return "", fmt.Errorf("%w %q", ErrUnknownField, name)
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
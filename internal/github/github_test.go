@@ -0,0 +1,99 @@
+package github_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/github"
+)
+
+func TestIssuesFollowsPagesAndSendsSince(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/app/issues" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		query := r.URL.Query()
+		if query.Get("since") != "2025-06-01T00:00:00Z" || query.Get("state") != "all" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if query.Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?%s&page=2>; rel="next", <%s/last>; rel="last"`,
+				server.URL, r.URL.Path, r.URL.RawQuery, server.URL))
+			_, _ = w.Write([]byte(`[{"number":1,"title":"Bug","state":"open","labels":[{"name":"bug"},{"name":"p1"}]}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"number":2,"title":"Fix","state":"closed","pull_request":{"url":"x"},` +
+			`"closed_at":"2025-06-03T10:00:00Z","milestone":{"title":"v1"}}]`))
+	}))
+	defer server.Close()
+
+	client := &github.Client{BaseURL: server.URL, Token: "secret"}
+	var issues []github.Issue
+	for issue, err := range client.Issues(context.Background(), "acme/app", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		if err != nil {
+			t.Fatalf("Issues returned error: %v", err)
+		}
+		issues = append(issues, issue)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(issues))
+	}
+
+	checks := []struct {
+		issue github.Issue
+		field string
+		want  string
+	}{
+		{issues[0], "labels", "bug,p1"},
+		{issues[0], "type", "issue"},
+		{issues[0], "closed_at", ""},
+		{issues[1], "type", "pull_request"},
+		{issues[1], "milestone", "v1"},
+		{issues[1], "closed_at", "2025-06-03T10:00:00Z"},
+		{issues[1], "number", "2"},
+	}
+	for _, check := range checks {
+		got, err := check.issue.Field(check.field)
+		if err != nil || got != check.want {
+			t.Errorf("Field(%q) = %q, %v; want %q", check.field, got, err, check.want)
+		}
+	}
+	if _, err := issues[0].Field("nope"); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+}
+
+func TestIssuesReportsHTTPErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := &github.Client{BaseURL: server.URL}
+	for _, err := range client.Issues(context.Background(), "acme/missing", time.Time{}) {
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		return
+	}
+	t.Fatal("expected the iterator to yield an error")
+}
+
+func TestParseRepo(t *testing.T) {
+	if owner, name, err := github.ParseRepo("acme/app"); err != nil || owner != "acme" || name != "app" {
+		t.Fatalf("ParseRepo = %q, %q, %v", owner, name, err)
+	}
+	for _, bad := range []string{"", "acme", "acme/", "/app", "a/b/c"} {
+		if _, _, err := github.ParseRepo(bad); err == nil {
+			t.Errorf("ParseRepo(%q) succeeded", bad)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Package ical writes iCalendar (RFC 5545) feeds that calendar apps can import or subscribe to.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405Z"
+	// maxLineOctets is the longest content line RFC 5545 allows before folding.
+	maxLineOctets = 75
+)
+
+// Calendar is a named list of events.
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Event is a single VEVENT. All-day events use the date of Start and End, with End
+// exclusive; a zero End makes an all-day event one day long and a timed event instantaneous.
+type Event struct {
+	Start       time.Time
+	End         time.Time
+	Modified    time.Time
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	AllDay      bool
+}
+
+// Write encodes the calendar with CRLF line endings and folded long lines.
+func Write(w io.Writer, cal Calendar) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//notionctl//calendar//EN")
+	line("CALSCALE", "GREGORIAN")
+	if cal.Name != "" {
+		line("X-WR-CALNAME", escapeText(cal.Name))
+	}
+	for _, event := range cal.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", escapeText(event.UID))
+		stamp := event.Modified
+		if stamp.IsZero() {
+			stamp = time.Now()
+		}
+		line("DTSTAMP", stamp.UTC().Format(dateTimeLayout))
+		if !event.Modified.IsZero() {
+			line("LAST-MODIFIED", event.Modified.UTC().Format(dateTimeLayout))
+		}
+		if event.AllDay {
+			end := event.End
+			if end.IsZero() || !end.After(event.Start) {
+				end = event.Start.AddDate(0, 0, 1)
+			}
+			line("DTSTART;VALUE=DATE", event.Start.Format(dateLayout))
+			line("DTEND;VALUE=DATE", end.Format(dateLayout))
+		} else {
+			line("DTSTART", event.Start.UTC().Format(dateTimeLayout))
+			if !event.End.IsZero() {
+				line("DTEND", event.End.UTC().Format(dateTimeLayout))
+			}
+		}
+		line("SUMMARY", escapeText(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION", escapeText(event.Description))
+		}
+		if event.URL != "" {
+			line("URL", event.URL)
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("write calendar: %w", err)
+	}
+	return nil
+}
+
+// escapeText escapes a TEXT value: backslashes, semicolons, commas, and newlines.
+func escapeText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// writeFolded writes a content line, folding it onto continuation lines that start with a
+// space so no line exceeds 75 octets. Folds never split a UTF-8 sequence.
+func writeFolded(w *bufio.Writer, content string) {
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		_, _ = w.WriteString(content[:cut] + "\r\n ")
+		content = content[cut:]
+		// Continuation lines spend one octet on the leading space.
+		limit = maxLineOctets - 1
+	}
+	_, _ = w.WriteString(content + "\r\n")
+}
@@ -0,0 +1,79 @@
+package ical_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/ical"
+)
+
+func TestWrite(t *testing.T) {
+	edited := time.Date(2025, 5, 30, 12, 0, 0, 0, time.UTC)
+	start := time.Date(2025, 6, 2, 9, 30, 0, 0, time.FixedZone("EDT", -4*3600))
+	cal := ical.Calendar{Name: "Tasks", Events: []ical.Event{
+		{
+			UID:      "p1@notionctl",
+			Summary:  "Ship v2; then, celebrate",
+			Start:    time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			AllDay:   true,
+			Modified: edited,
+			URL:      "https://www.notion.so/p1",
+		},
+		{
+			UID:         "p2@notionctl",
+			Summary:     "Review",
+			Description: "line one\nline two",
+			Start:       start,
+			End:         start.Add(time.Hour),
+			Modified:    edited,
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := ical.Write(&buf, cal); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"X-WR-CALNAME:Tasks\r\n",
+		"DTSTAMP:20250530T120000Z\r\n",
+		"DTSTART;VALUE=DATE:20250601\r\nDTEND;VALUE=DATE:20250602\r\n",
+		`SUMMARY:Ship v2\; then\, celebrate` + "\r\n",
+		"DTSTART:20250602T133000Z\r\nDTEND:20250602T143000Z\r\n",
+		`DESCRIPTION:line one\nline two` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "BEGIN:VEVENT") != 2 {
+		t.Fatalf("expected two events:\n%s", out)
+	}
+}
+
+func TestWriteFoldsLongLines(t *testing.T) {
+	summary := strings.Repeat("é", 60)
+	var buf bytes.Buffer
+	if err := ical.Write(&buf, ical.Calendar{Events: []ical.Event{{UID: "x", Summary: summary, Start: time.Now()}}}); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	lines := strings.Split(buf.String(), "\r\n")
+	var unfolded strings.Builder
+	for _, line := range lines {
+		if len(line) > 75 {
+			t.Fatalf("line of %d octets: %q", len(line), line)
+		}
+		if strings.HasPrefix(line, " ") {
+			unfolded.WriteString(line[1:])
+		} else if strings.HasPrefix(line, "SUMMARY:") {
+			unfolded.WriteString(line)
+		}
+	}
+	if unfolded.String() != "SUMMARY:"+summary {
+		t.Fatalf("unfolded summary = %q", unfolded.String())
+	}
+}
@@ -0,0 +1,142 @@
+// Package jsondiff compares decoded JSON documents structurally and reports the paths that
+// differ.
+package jsondiff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Kinds of Change.
+const (
+	Removed     = "removed"
+	Added       = "added"
+	TypeChanged = "type_changed"
+	Changed     = "changed"
+)
+
+// Change is one difference between two documents. Path uses dots for object keys and "[]"
+// for array elements, so the same difference in every element of an array is reported once.
+type Change struct {
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+}
+
+// Diff compares documents decoded with encoding/json into any. Arrays are compared element
+// by element up to the shorter length.
+func Diff(before, after any) []Change {
+	d := differ{seen: map[string]bool{}}
+	d.walk("", before, after)
+	return d.changes
+}
+
+type differ struct {
+	seen    map[string]bool
+	changes []Change
+}
+
+func (d *differ) add(change Change) {
+	key := change.Path + "\x00" + change.Kind
+	if d.seen[key] {
+		return
+	}
+	d.seen[key] = true
+	d.changes = append(d.changes, change)
+}
+
+func (d *differ) walk(path string, before, after any) {
+	if kind(before) != kind(after) {
+		d.add(Change{Path: display(path), Kind: TypeChanged, Old: kind(before), New: kind(after)})
+		return
+	}
+	switch b := before.(type) {
+	case map[string]any:
+		a, _ := after.(map[string]any)
+		keys := make([]string, 0, len(b)+len(a))
+		for key := range b {
+			keys = append(keys, key)
+		}
+		for key := range a {
+			if _, ok := b[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			child := join(path, key)
+			bv, inBefore := b[key]
+			av, inAfter := a[key]
+			switch {
+			case !inAfter:
+				d.add(Change{Path: display(child), Kind: Removed, Old: summary(bv)})
+			case !inBefore:
+				d.add(Change{Path: display(child), Kind: Added, New: summary(av)})
+			default:
+				d.walk(child, bv, av)
+			}
+		}
+	case []any:
+		a, _ := after.([]any)
+		for i := range min(len(b), len(a)) {
+			d.walk(path+"[]", b[i], a[i])
+		}
+	default:
+		if before != after {
+			d.add(Change{Path: display(path), Kind: Changed, Old: before, New: after})
+		}
+	}
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func display(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
+
+// kind names the JSON type of a decoded value.
+func kind(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// summary keeps scalar values and describes containers by type, so reports stay short.
+func summary(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		return "object{" + strings.Join(keys, ",") + "}"
+	case []any:
+		return fmt.Sprintf("array[%d]", len(v))
+	default:
+		return v
+	}
+}
@@ -0,0 +1,61 @@
+package jsondiff_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/jsondiff"
+)
+
+func decode(t *testing.T, raw string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		t.Fatalf("decode %s: %v", raw, err)
+	}
+	return v
+}
+
+func TestDiff(t *testing.T) {
+	before := decode(t, `{
+		"object": "page",
+		"parent": {"type": "database_id", "database_id": "d1"},
+		"results": [{"id": "a", "n": 1}, {"id": "b", "n": 2}],
+		"archived": false,
+		"count": 3
+	}`)
+	after := decode(t, `{
+		"object": "page",
+		"parent": {"type": "data_source_id", "data_source_id": "s1"},
+		"results": [{"id": "a", "n": "1"}, {"id": "b", "n": "2"}],
+		"in_trash": false,
+		"count": 3
+	}`)
+
+	want := []jsondiff.Change{
+		{Path: "archived", Kind: jsondiff.Removed, Old: false},
+		{Path: "in_trash", Kind: jsondiff.Added, New: false},
+		{Path: "parent.data_source_id", Kind: jsondiff.Added, New: "s1"},
+		{Path: "parent.database_id", Kind: jsondiff.Removed, Old: "d1"},
+		{Path: "parent.type", Kind: jsondiff.Changed, Old: "database_id", New: "data_source_id"},
+		{Path: "results[].n", Kind: jsondiff.TypeChanged, Old: "number", New: "string"},
+	}
+	if got := jsondiff.Diff(before, after); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Diff mismatch:\n got  %#v\n want %#v", got, want)
+	}
+}
+
+func TestDiffSummarizesContainers(t *testing.T) {
+	got := jsondiff.Diff(decode(t, `{"a": {"y": 1, "x": 2}}`), decode(t, `{"b": [1, 2]}`))
+	want := []jsondiff.Change{
+		{Path: "a", Kind: jsondiff.Removed, Old: "object{x,y}"},
+		{Path: "b", Kind: jsondiff.Added, New: "array[2]"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Diff mismatch:\n got  %#v\n want %#v", got, want)
+	}
+	if got := jsondiff.Diff(decode(t, `{"a": 1}`), decode(t, `{"a": 1}`)); len(got) != 0 {
+		t.Fatalf("expected no changes for equal documents, got %#v", got)
+	}
+}
@@ -0,0 +1,58 @@
+package markdown
+
+import "strings"
+
+// plainTextLanguage is Notion's language for code without highlighting.
+const plainTextLanguage = "plain text"
+
+// notionLanguages are the code block languages Notion accepts.
+//
+// https://developers.notion.com/reference/block#code
+var notionLanguages = map[string]bool{
+	"abap": true, "arduino": true, "bash": true, "basic": true, "c": true, "clojure": true,
+	"coffeescript": true, "c++": true, "c#": true, "css": true, "dart": true, "diff": true,
+	"docker": true, "elixir": true, "elm": true, "erlang": true, "flow": true, "fortran": true,
+	"f#": true, "gherkin": true, "glsl": true, "go": true, "graphql": true, "groovy": true,
+	"haskell": true, "html": true, "java": true, "javascript": true, "json": true, "julia": true,
+	"kotlin": true, "latex": true, "less": true, "lisp": true, "livescript": true, "lua": true,
+	"makefile": true, "markdown": true, "markup": true, "matlab": true, "mermaid": true,
+	"nix": true, "objective-c": true, "ocaml": true, "pascal": true, "perl": true, "php": true,
+	plainTextLanguage: true, "powershell": true, "prolog": true, "protobuf": true, "python": true,
+	"r": true, "reason": true, "ruby": true, "rust": true, "sass": true, "scala": true,
+	"scheme": true, "scss": true, "shell": true, "sql": true, "swift": true, "typescript": true,
+	"vb.net": true, "verilog": true, "vhdl": true, "visual basic": true, "webassembly": true,
+	"xml": true, "yaml": true, "java/c/c++/c#": true,
+}
+
+// languageAliases maps common fence info strings onto Notion language names.
+var languageAliases = map[string]string{
+	"sh": "shell", "zsh": "shell", "console": "shell", "shell-session": "shell",
+	"js": "javascript", "jsx": "javascript", "mjs": "javascript", "cjs": "javascript",
+	"ts": "typescript", "tsx": "typescript",
+	"py": "python", "python3": "python", "rb": "ruby", "rs": "rust", "golang": "go",
+	"kt": "kotlin", "kts": "kotlin", "cpp": "c++", "cc": "c++", "cxx": "c++", "h": "c",
+	"cs": "c#", "csharp": "c#", "fs": "f#", "fsharp": "f#", "objc": "objective-c",
+	"yml": "yaml", "md": "markdown", "tex": "latex", "ps1": "powershell", "pwsh": "powershell",
+	"dockerfile": "docker", "make": "makefile", "proto": "protobuf", "wasm": "webassembly",
+	"vb": "visual basic", "ex": "elixir", "exs": "elixir", "erl": "erlang", "hs": "haskell",
+	"clj": "clojure", "coffee": "coffeescript", "gql": "graphql", "svg": "xml", "htm": "html",
+	"patch": "diff", "jsonc": "json", "json5": "json", "text": plainTextLanguage,
+	"txt": plainTextLanguage, "plain": plainTextLanguage, "plaintext": plainTextLanguage,
+}
+
+// codeLanguage maps the first word of a fence info string onto a Notion language, falling
+// back to plain text for languages Notion does not highlight.
+func codeLanguage(info string) string {
+	fields := strings.Fields(strings.ToLower(info))
+	if len(fields) == 0 {
+		return plainTextLanguage
+	}
+	language := strings.Trim(fields[0], "{}.")
+	if notionLanguages[language] {
+		return language
+	}
+	if alias, ok := languageAliases[language]; ok {
+		return alias
+	}
+	return plainTextLanguage
+}
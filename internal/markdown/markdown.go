@@ -0,0 +1,405 @@
+// Package markdown renders Notion pages as Markdown documents with YAML frontmatter, the
+// layout static site generators and Obsidian vaults expect.
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+const frontmatterDelimiter = "---\n"
+
+// Field is one frontmatter entry. Fields are written in the order given.
+type Field struct {
+	Value any
+	Key   string
+}
+
+// Document renders fields as YAML frontmatter followed by body.
+func Document(fields []Field, body string) ([]byte, error) {
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+	for _, field := range fields {
+		var key, value yaml.Node
+		if err := key.Encode(field.Key); err != nil {
+			return nil, fmt.Errorf("encode frontmatter key %s: %w", field.Key, err)
+		}
+		if err := value.Encode(field.Value); err != nil {
+			return nil, fmt.Errorf("encode frontmatter %s: %w", field.Key, err)
+		}
+		mapping.Content = append(mapping.Content, &key, &value)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(frontmatterDelimiter)
+	if len(fields) > 0 {
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(mapping); err != nil {
+			return nil, fmt.Errorf("encode frontmatter: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return nil, fmt.Errorf("encode frontmatter: %w", err)
+		}
+	}
+	buf.WriteString(frontmatterDelimiter)
+	if body != "" {
+		buf.WriteString("\n")
+		buf.WriteString(body)
+	}
+	return buf.Bytes(), nil
+}
+
+// Parse splits a document written by Document into its frontmatter and body. Files without
+// frontmatter return a nil map and the whole content as body.
+func Parse(data []byte) (map[string]any, string, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	rest, ok := strings.CutPrefix(text, frontmatterDelimiter)
+	if !ok {
+		return nil, text, nil
+	}
+	var head, body string
+	if strings.HasPrefix(rest, frontmatterDelimiter) {
+		body = rest[len(frontmatterDelimiter):]
+	} else {
+		end := strings.Index(rest, "\n"+frontmatterDelimiter)
+		if end < 0 {
+			return nil, "", fmt.Errorf("frontmatter is not closed with %q", strings.TrimSpace(frontmatterDelimiter))
+		}
+		head, body = rest[:end+1], rest[end+1+len(frontmatterDelimiter):]
+	}
+
+	fields := map[string]any{}
+	if err := yaml.Unmarshal([]byte(head), &fields); err != nil {
+		return nil, "", fmt.Errorf("decode frontmatter: %w", err)
+	}
+	return fields, strings.TrimPrefix(body, "\n"), nil
+}
+
+// FromBlocks renders blocks, including attached children, as Markdown. Block types without
+// a Markdown equivalent become HTML comments so the gap is visible in the output.
+func FromBlocks(blocks []notion.Block) string {
+	return renderer{}.render(blocks)
+}
+
+// Terminal renders blocks like FromBlocks, styled with ANSI escape codes for reading in a
+// terminal: emphasis, code, and links are colored instead of marked up, and toggles are
+// shown open.
+func Terminal(blocks []notion.Block) string {
+	return renderer{ansi: true}.render(blocks)
+}
+
+// ANSI select graphic rendition codes used by Terminal.
+const (
+	sgrBold      = "1"
+	sgrDim       = "2"
+	sgrHeading   = "1;35"
+	sgrCode      = "36"
+	sgrMarker    = "33"
+	sgrChecked   = "32"
+	sgrUnderline = "4"
+)
+
+// renderer writes blocks as plain Markdown, or as ANSI-styled Markdown when ansi is set.
+type renderer struct {
+	ansi bool
+}
+
+func (r renderer) render(blocks []notion.Block) string {
+	var b strings.Builder
+	r.writeBlocks(&b, blocks, "")
+	out := strings.TrimRight(b.String(), "\n")
+	if out == "" {
+		return ""
+	}
+	return out + "\n"
+}
+
+// paint wraps text in an SGR code in ANSI mode and returns it unchanged otherwise.
+func (r renderer) paint(code, text string) string {
+	if !r.ansi || text == "" {
+		return text
+	}
+	return "\x1b[" + code + "m" + text + "\x1b[0m"
+}
+
+func (r renderer) writeBlocks(b *strings.Builder, blocks []notion.Block, indent string) {
+	number := 0
+	for i, block := range blocks {
+		if block.Type == "numbered_list_item" {
+			number++
+		} else {
+			number = 0
+		}
+		// List items stay tight; everything else is separated by a blank line.
+		if i > 0 && !(isListItem(blocks[i-1]) && isListItem(block)) {
+			b.WriteString("\n")
+		}
+		r.writeBlock(b, block, indent, number)
+	}
+}
+
+func isListItem(block notion.Block) bool {
+	switch block.Type {
+	case "bulleted_list_item", "numbered_list_item", "to_do":
+		return true
+	}
+	return false
+}
+
+func (r renderer) writeBlock(b *strings.Builder, block notion.Block, indent string, number int) {
+	switch {
+	case block.Paragraph != nil:
+		writeLines(b, r.richText(block.Paragraph.RichText), indent, indent)
+	case block.Heading1 != nil:
+		writeLines(b, r.paint(sgrHeading, "# "+r.richText(block.Heading1.RichText)), indent, indent)
+	case block.Heading2 != nil:
+		writeLines(b, r.paint(sgrHeading, "## "+r.richText(block.Heading2.RichText)), indent, indent)
+	case block.Heading3 != nil:
+		writeLines(b, r.paint(sgrHeading, "### "+r.richText(block.Heading3.RichText)), indent, indent)
+	case block.BulletedListItem != nil:
+		r.writeListItem(b, "- ", r.richText(block.BulletedListItem.RichText), block, indent)
+		return
+	case block.NumberedListItem != nil:
+		r.writeListItem(b, fmt.Sprintf("%d. ", number), r.richText(block.NumberedListItem.RichText), block, indent)
+		return
+	case block.ToDo != nil:
+		// The checkbox is part of the item's text, so children indent to the "- " marker.
+		box := r.paint(sgrMarker, "[ ]") + " "
+		if block.ToDo.Checked {
+			box = r.paint(sgrChecked, "[x]") + " "
+		}
+		r.writeListItem(b, "- ", box+r.richText(block.ToDo.RichText), block, indent)
+		return
+	case block.Quote != nil:
+		r.writeQuote(b, r.richText(block.Quote.RichText), block, indent)
+		return
+	case block.Callout != nil:
+		text := r.richText(block.Callout.RichText)
+		if icon := block.Callout.Icon; icon != nil && icon.Emoji != nil {
+			text = *icon.Emoji + " " + text
+		}
+		r.writeQuote(b, text, block, indent)
+		return
+	case block.Code != nil:
+		language := block.Code.Language
+		if language == plainTextLanguage {
+			language = ""
+		}
+		writeLines(b, r.paint(sgrDim, "```"+language), indent, indent)
+		for _, line := range strings.Split(plainText(block.Code.RichText), "\n") {
+			writeLines(b, r.paint(sgrCode, line), indent, indent)
+		}
+		writeLines(b, r.paint(sgrDim, "```"), indent, indent)
+	case block.Toggle != nil:
+		r.writeToggle(b, block, indent)
+		return
+	case block.Divider != nil:
+		writeLines(b, r.paint(sgrDim, "---"), indent, indent)
+	case block.Table != nil:
+		r.writeTable(b, block, indent)
+		return
+	case block.Image != nil:
+		writeLines(b, r.paint(sgrDim, "!["+plainText(block.Image.Caption)+"]("+block.Image.URL()+")"), indent, indent)
+	case block.Bookmark != nil:
+		writeLines(b, r.paint(sgrUnderline, "<"+block.Bookmark.URL+">"), indent, indent)
+	case block.Embed != nil:
+		writeLines(b, r.paint(sgrUnderline, "<"+block.Embed.URL+">"), indent, indent)
+	default:
+		writeLines(b, r.paint(sgrDim, fmt.Sprintf("<!-- unsupported Notion block: %s -->", block.Type)), indent, indent)
+	}
+
+	if children := block.Children(); len(children) > 0 {
+		b.WriteString("\n")
+		r.writeBlocks(b, children, indent)
+	}
+}
+
+// writeListItem indents continuation lines and children to the item's content column.
+func (r renderer) writeListItem(b *strings.Builder, marker, text string, block notion.Block, indent string) {
+	nested := indent + strings.Repeat(" ", len(marker))
+	writeLines(b, r.paint(sgrMarker, marker)+text, indent, nested)
+	if children := block.Children(); len(children) > 0 {
+		r.writeBlocks(b, children, nested)
+	}
+}
+
+func (r renderer) writeQuote(b *strings.Builder, text string, block notion.Block, indent string) {
+	var inner strings.Builder
+	inner.WriteString(text + "\n")
+	if children := block.Children(); len(children) > 0 {
+		inner.WriteString("\n")
+		r.writeBlocks(&inner, children, "")
+	}
+	bar := r.paint(sgrDim, ">")
+	for _, line := range strings.Split(strings.TrimRight(inner.String(), "\n"), "\n") {
+		if line == "" {
+			b.WriteString(indent + bar + "\n")
+			continue
+		}
+		b.WriteString(indent + bar + " " + line + "\n")
+	}
+}
+
+// writeToggle writes a toggle as a <details> element, or in ANSI mode as its summary
+// followed by its indented content.
+func (r renderer) writeToggle(b *strings.Builder, block notion.Block, indent string) {
+	children := block.Children()
+	if r.ansi {
+		writeLines(b, r.paint(sgrBold, "▾ ")+r.richText(block.Toggle.RichText), indent, indent)
+		if len(children) > 0 {
+			r.writeBlocks(b, children, indent+"  ")
+		}
+		return
+	}
+	writeLines(b, "<details>", indent, indent)
+	writeLines(b, "<summary>"+r.richText(block.Toggle.RichText)+"</summary>", indent, indent)
+	if len(children) > 0 {
+		b.WriteString("\n")
+		r.writeBlocks(b, children, indent)
+		b.WriteString("\n")
+	}
+	writeLines(b, "</details>", indent, indent)
+}
+
+// writeTable renders a table as a GitHub-flavored Markdown table. Markdown tables always
+// have a header row, so the first row becomes one even without a column header in Notion.
+func (r renderer) writeTable(b *strings.Builder, block notion.Block, indent string) {
+	rows := block.Children()
+	if len(rows) == 0 {
+		return
+	}
+	for i, row := range rows {
+		cells := make([]string, block.Table.TableWidth)
+		if row.TableRow != nil {
+			for j, cell := range row.TableRow.Cells {
+				if j < len(cells) {
+					cells[j] = tableCell(r.richText(cell))
+				}
+			}
+		}
+		line := "| " + strings.Join(cells, " | ") + " |"
+		if i == 0 {
+			line = r.paint(sgrBold, line)
+		}
+		writeLines(b, line, indent, indent)
+		if i == 0 {
+			writeLines(b, r.paint(sgrDim, "|"+strings.Repeat(" --- |", len(cells))), indent, indent)
+		}
+	}
+}
+
+// tableCell escapes pipes and line breaks, which would otherwise end the cell or row.
+func tableCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
+
+func writeLines(b *strings.Builder, text, first, rest string) {
+	for i, line := range strings.Split(text, "\n") {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		if line == "" {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(prefix + line + "\n")
+	}
+}
+
+// RichText renders rich text with its annotations and links as inline Markdown.
+func RichText(parts []notion.RichText) string {
+	return renderer{}.richText(parts)
+}
+
+func (r renderer) richText(parts []notion.RichText) string {
+	var b strings.Builder
+	for _, part := range parts {
+		text := part.PlainText
+		if text == "" && part.Text != nil {
+			text = part.Text.Content
+		}
+		if text == "" {
+			continue
+		}
+
+		// Markers must hug the text, so surrounding spaces stay outside them.
+		core := strings.TrimSpace(text)
+		if core == "" {
+			b.WriteString(text)
+			continue
+		}
+		lead := text[:strings.Index(text, core)]
+		trail := text[len(lead)+len(core):]
+
+		if a := part.Annotations; a != nil {
+			core = r.annotate(core, a)
+		}
+		if href := linkOf(part); href != "" {
+			if r.ansi {
+				core = "\x1b[4m" + core + "\x1b[24m " + r.paint(sgrDim, "("+href+")")
+			} else {
+				core = "[" + core + "](" + href + ")"
+			}
+		}
+		b.WriteString(lead + core + trail)
+	}
+	return b.String()
+}
+
+// annotate marks up text with Markdown emphasis, or in ANSI mode with the matching styles.
+// ANSI styles end with their specific reset so an enclosing heading keeps its color.
+func (r renderer) annotate(text string, a *notion.Annotations) string {
+	if a.Code {
+		if r.ansi {
+			text = "\x1b[36m" + text + "\x1b[39m"
+		} else {
+			text = "`" + text + "`"
+		}
+	}
+	if a.Bold {
+		text = r.mark(text, "**", "1", "22")
+	}
+	if a.Italic {
+		text = r.mark(text, "*", "3", "23")
+	}
+	if a.Strikethrough {
+		text = r.mark(text, "~~", "9", "29")
+	}
+	return text
+}
+
+func (r renderer) mark(text, marker, on, off string) string {
+	if r.ansi {
+		return "\x1b[" + on + "m" + text + "\x1b[" + off + "m"
+	}
+	return marker + text + marker
+}
+
+func linkOf(part notion.RichText) string {
+	if part.Href != nil && *part.Href != "" {
+		return *part.Href
+	}
+	if part.Text != nil && part.Text.Link != nil {
+		return part.Text.Link.URL
+	}
+	return ""
+}
+
+func plainText(parts []notion.RichText) string {
+	var b strings.Builder
+	for _, part := range parts {
+		if part.PlainText == "" && part.Text != nil {
+			b.WriteString(part.Text.Content)
+			continue
+		}
+		b.WriteString(part.PlainText)
+	}
+	return b.String()
+}
@@ -0,0 +1,287 @@
+package markdown_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/markdown"
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func text(s string) []notion.RichText {
+	return []notion.RichText{{PlainText: s, Type: "text"}}
+}
+
+func TestFromBlocks(t *testing.T) {
+	link := "https://example.com"
+	blocks := []notion.Block{
+		{Type: "heading_1", Heading1: &notion.HeadingBlock{RichText: text("Plan")}},
+		{Type: "paragraph", Paragraph: &notion.ParagraphBlock{RichText: []notion.RichText{
+			{PlainText: "Ship "},
+			{PlainText: "fast ", Annotations: &notion.Annotations{Bold: true}},
+			{PlainText: "docs", Href: &link},
+		}}},
+		{Type: "bulleted_list_item", BulletedListItem: &notion.ParagraphBlock{
+			RichText: text("One"),
+			Children: []notion.Block{{Type: "to_do", ToDo: &notion.ToDoBlock{RichText: text("Nested"), Checked: true}}},
+		}},
+		{Type: "bulleted_list_item", BulletedListItem: &notion.ParagraphBlock{RichText: text("Two")}},
+		{Type: "numbered_list_item", NumberedListItem: &notion.ParagraphBlock{RichText: text("First")}},
+		{Type: "numbered_list_item", NumberedListItem: &notion.ParagraphBlock{RichText: text("Second")}},
+		{Type: "code", Code: &notion.CodeBlock{RichText: text("go test ./..."), Language: "sh"}},
+		{Type: "quote", Quote: &notion.ParagraphBlock{RichText: text("Quoted")}},
+		{Type: "divider", Divider: &struct{}{}},
+		{Type: "image"},
+	}
+
+	want := strings.Join([]string{
+		"# Plan",
+		"",
+		"Ship **fast** [docs](https://example.com)",
+		"",
+		"- One",
+		"  - [x] Nested",
+		"- Two",
+		"1. First",
+		"2. Second",
+		"",
+		"```sh",
+		"go test ./...",
+		"```",
+		"",
+		"> Quoted",
+		"",
+		"---",
+		"",
+		"<!-- unsupported Notion block: image -->",
+		"",
+	}, "\n")
+	if got := markdown.FromBlocks(blocks); got != want {
+		t.Fatalf("FromBlocks mismatch:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDocument(t *testing.T) {
+	doc, err := markdown.Document([]markdown.Field{
+		{Key: "title", Value: "Launch: v2"},
+		{Key: "Tags", Value: []string{"cli", "go"}},
+		{Key: "Done", Value: true},
+		{Key: "yes", Value: nil},
+	}, "Body\n")
+	if err != nil {
+		t.Fatalf("Document returned error: %v", err)
+	}
+	want := "---\ntitle: 'Launch: v2'\nTags:\n  - cli\n  - go\nDone: true\n\"yes\": null\n---\n\nBody\n"
+	if string(doc) != want {
+		t.Fatalf("Document mismatch:\n%s\nwant:\n%s", doc, want)
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	doc, err := markdown.Document([]markdown.Field{
+		{Key: "title", Value: "Launch"},
+		{Key: "Points", Value: 3.0},
+	}, "# Heading\n\n---\n\nBody\n")
+	if err != nil {
+		t.Fatalf("Document returned error: %v", err)
+	}
+	fields, body, err := markdown.Parse(doc)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if fields["title"] != "Launch" || fields["Points"] != 3 {
+		t.Fatalf("unexpected frontmatter: %#v", fields)
+	}
+	if body != "# Heading\n\n---\n\nBody\n" {
+		t.Fatalf("unexpected body: %q", body)
+	}
+
+	if fields, body, err := markdown.Parse([]byte("plain text\n")); err != nil || fields != nil || body != "plain text\n" {
+		t.Fatalf("Parse without frontmatter = %v, %q, %v", fields, body, err)
+	}
+	if _, _, err := markdown.Parse([]byte("---\ntitle: x\n")); err == nil {
+		t.Fatalf("expected an error for unclosed frontmatter")
+	}
+}
+
+func TestToBlocksTablesImagesDividersAndBookmarks(t *testing.T) {
+	body := strings.Join([]string{
+		"Intro",
+		"",
+		"| Name | Status |",
+		"| --- | :---: |",
+		"| **Docs** | Open \\| blocked |",
+		"| Ship |",
+		"",
+		"---",
+		"",
+		"![Diagram](https://example.com/diagram.png)",
+		"",
+		"https://example.com/spec",
+		"",
+		"```",
+		"---",
+		"```",
+	}, "\n")
+
+	blocks, err := markdown.ToBlocks(body)
+	if err != nil {
+		t.Fatalf("ToBlocks returned error: %v", err)
+	}
+	if len(blocks) != 6 || blocks[0].Paragraph == nil || blocks[1].Table == nil || blocks[2].Divider == nil ||
+		blocks[3].Image == nil || blocks[4].Bookmark == nil || blocks[5].Code == nil {
+		t.Fatalf("expected paragraph, table, divider, image, bookmark, and code blocks, got %+v", blocks)
+	}
+
+	table := blocks[1].Table
+	if table.TableWidth != 2 || !table.HasColumnHeader || len(table.Children) != 3 {
+		t.Fatalf("unexpected table: %+v", table)
+	}
+	docs := table.Children[1].TableRow.Cells
+	if docs[0][0].Annotations == nil || !docs[0][0].Annotations.Bold || markdown.RichText(docs[1]) != "Open | blocked" {
+		t.Fatalf("unexpected row cells: %+v", docs)
+	}
+	if short := table.Children[2].TableRow.Cells; len(short) != 2 || len(short[1]) != 0 {
+		t.Fatalf("expected short rows to be padded: %+v", short)
+	}
+	if image := blocks[3].Image; image.URL() != "https://example.com/diagram.png" || markdown.RichText(image.Caption) != "Diagram" {
+		t.Fatalf("unexpected image: %+v", image)
+	}
+	if blocks[4].Bookmark.URL != "https://example.com/spec" {
+		t.Fatalf("unexpected bookmark: %+v", blocks[4].Bookmark)
+	}
+
+	// Rendering the blocks again gives Markdown that converts to the same blocks.
+	again, err := markdown.ToBlocks(markdown.FromBlocks(blocks))
+	if err != nil || len(again) != len(blocks) || again[1].Table.TableWidth != 2 {
+		t.Fatalf("round trip = %d blocks, %v:\n%s", len(again), err, markdown.FromBlocks(blocks))
+	}
+}
+
+func TestToBlocksNestedListsTasksAndToggles(t *testing.T) {
+	body := strings.Join([]string{
+		"- one",
+		"  - two",
+		"    - three",
+		"- [ ] open task",
+		"  - [x] done task",
+		"",
+		"<details>",
+		"<summary>More</summary>",
+		"",
+		"Inside",
+		"",
+		"<details>",
+		"<summary>Deeper</summary>",
+		"",
+		"1. first",
+		"",
+		"</details>",
+		"",
+		"</details>",
+		"",
+	}, "\n")
+
+	blocks, err := markdown.ToBlocks(body)
+	if err != nil {
+		t.Fatalf("ToBlocks returned error: %v", err)
+	}
+	if len(blocks) != 3 || blocks[1].ToDo == nil || blocks[1].ToDo.Checked || blocks[2].Toggle == nil {
+		t.Fatalf("expected a list, a to-do, and a toggle, got %+v", blocks)
+	}
+	if done := blocks[1].Children(); len(done) != 1 || done[0].ToDo == nil || !done[0].ToDo.Checked {
+		t.Fatalf("expected a checked nested to-do, got %+v", done)
+	}
+	if got := markdown.FromBlocks(blocks); got != body {
+		t.Fatalf("round trip mismatch:\n%s\nwant:\n%s", got, body)
+	}
+}
+
+func TestToBlocksMapsCodeLanguages(t *testing.T) {
+	long := strings.Repeat("x", 2500)
+	body := "```ts\nconst a = 1\n```\n\n```mermaid\ngraph TD\n  A --> B\n```\n\n~~~hcl\nresource {}\n~~~\n\n```\n" +
+		long + "\n```\n\n~~~~Python title=\"x\"\n```\n~~~~"
+
+	blocks, err := markdown.ToBlocks(body)
+	if err != nil {
+		t.Fatalf("ToBlocks returned error: %v", err)
+	}
+	if len(blocks) != 5 {
+		t.Fatalf("expected five code blocks, got %+v", blocks)
+	}
+	for i, want := range []string{"typescript", "mermaid", "plain text", "plain text", "python"} {
+		if code := blocks[i].Code; code == nil || code.Language != want {
+			t.Fatalf("block %d: expected %s code, got %+v", i, want, blocks[i])
+		}
+	}
+	if got := markdown.RichText(blocks[1].Code.RichText); got != "graph TD\n  A --> B" {
+		t.Fatalf("mermaid source changed: %q", got)
+	}
+	if parts := blocks[3].Code.RichText; len(parts) != 2 || markdown.RichText(parts) != long {
+		t.Fatalf("expected long code split into two rich text objects, got %d", len(parts))
+	}
+	if got := markdown.RichText(blocks[4].Code.RichText); got != "```" {
+		t.Fatalf("expected a different fence inside the block to stay content, got %q", got)
+	}
+	if got := markdown.FromBlocks(blocks[2:3]); got != "```\nresource {}\n```\n" {
+		t.Fatalf("plain text code should render without a language, got %q", got)
+	}
+}
+
+func TestTerminalStylesInsteadOfMarkup(t *testing.T) {
+	link := "https://example.com"
+	blocks := []notion.Block{
+		{Type: "heading_2", Heading2: &notion.HeadingBlock{RichText: text("Plan")}},
+		{Type: "paragraph", Paragraph: &notion.ParagraphBlock{RichText: []notion.RichText{
+			{PlainText: "bold", Annotations: &notion.Annotations{Bold: true}},
+			{PlainText: " and "},
+			{PlainText: "docs", Href: &link},
+		}}},
+		{Type: "to_do", ToDo: &notion.ToDoBlock{RichText: text("Ship"), Checked: true}},
+		{Type: "toggle", Toggle: &notion.ToggleBlock{RichText: text("More"), Children: []notion.Block{
+			{Type: "paragraph", Paragraph: &notion.ParagraphBlock{RichText: text("Hidden")}},
+		}}},
+	}
+
+	got := markdown.Terminal(blocks)
+	for _, want := range []string{
+		"\x1b[1;35m## Plan\x1b[0m\n",
+		"\x1b[1mbold\x1b[22m and \x1b[4mdocs\x1b[24m \x1b[2m(https://example.com)\x1b[0m\n",
+		"\x1b[33m- \x1b[0m\x1b[32m[x]\x1b[0m Ship\n",
+		"\x1b[1m▾ \x1b[0mMore\n  Hidden\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("terminal output missing %q:\n%q", want, got)
+		}
+	}
+	if strings.Contains(got, "**") || strings.Contains(got, "<details>") {
+		t.Fatalf("terminal output should not contain Markdown markup:\n%q", got)
+	}
+	if plain := markdown.FromBlocks(blocks); strings.Contains(plain, "\x1b[") {
+		t.Fatalf("FromBlocks must not emit escape codes:\n%q", plain)
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	hosted := &notion.FileBlock{Type: "file"}
+	hosted.File = &struct {
+		URL        string `json:"url"`
+		ExpiryTime string `json:"expiry_time,omitempty"`
+	}{URL: "https://files.example.com/a.png"}
+	blocks := []notion.Block{
+		{Type: "paragraph", Paragraph: &notion.ParagraphBlock{RichText: text("Plan")}},
+		{Type: "image", Image: notion.ExternalFile("https://example.com/a.png")},
+		{Type: "toggle", Toggle: &notion.ToggleBlock{Children: []notion.Block{
+			{Type: "equation"},
+			{Type: "image", Image: hosted},
+		}}},
+		{Type: "column_list"},
+	}
+	got := strings.Join(markdown.Unsupported(blocks), ",")
+	if got != "column_list,equation,image" {
+		t.Fatalf("Unsupported = %q", got)
+	}
+	if got := markdown.Unsupported(blocks[:2]); len(got) != 0 {
+		t.Fatalf("expected supported blocks to round-trip, got %v", got)
+	}
+}
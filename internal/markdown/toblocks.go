@@ -0,0 +1,396 @@
+package markdown
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/brittonhayes/notionmd"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+var (
+	imageLine    = regexp.MustCompile(`^!\[([^\]]*)\]\((\S+?)(?:\s+"[^"]*")?\)$`)
+	bookmarkLine = regexp.MustCompile(`^<?(https?://[^\s<>]+)>?$`)
+	tableDivider = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+	summaryLine  = regexp.MustCompile(`^<summary>(.*)</summary>$`)
+)
+
+// ToBlocks converts Markdown into Notion blocks. Fenced code, tables, dividers, images, and
+// URLs on a line of their own (which become bookmarks) are converted here, since notionmd
+// drops them or their languages; the text between them is handed to notionmd.
+func ToBlocks(body string) ([]notion.Block, error) {
+	var blocks, converted []notion.Block
+	var pending []string
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		text, err := convertText(strings.Join(pending, "\n"))
+		pending = pending[:0]
+		blocks = append(blocks, text...)
+		return err
+	}
+
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	fence := ""
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			pending = append(pending, line)
+			continue
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			if line != trimmed {
+				// Indented fences belong to a list item; leave them to notionmd.
+				fence = trimmed[:3]
+				pending = append(pending, line)
+				continue
+			}
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			var code notion.Block
+			code, i = codeBlock(lines, i)
+			blocks = append(blocks, code)
+			continue
+		}
+
+		// Only unindented lines that start a paragraph can open one of these blocks;
+		// anything else belongs to a list, quote, or the paragraph above.
+		starts := (i == 0 || strings.TrimSpace(lines[i-1]) == "") && line == strings.TrimLeft(line, " \t")
+		ends := i+1 == len(lines) || strings.TrimSpace(lines[i+1]) == ""
+		if starts && trimmed == "<details>" {
+			toggle, last, err := toggleBlock(lines, i)
+			if err != nil {
+				return nil, err
+			}
+			converted, i = []notion.Block{toggle}, last
+		} else {
+			converted, i = special(lines, i, starts, ends)
+		}
+		if converted == nil {
+			pending = append(pending, line)
+			continue
+		}
+		if err := flush(); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, converted...)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// special converts the block starting at lines[i] when it is one notionmd cannot handle,
+// returning the index of its last line.
+func special(lines []string, i int, starts, ends bool) ([]notion.Block, int) {
+	if !starts {
+		return nil, i
+	}
+	trimmed := strings.TrimSpace(lines[i])
+	if isThematicBreak(trimmed) {
+		return []notion.Block{{Type: "divider", Divider: &struct{}{}}}, i
+	}
+	if strings.Contains(trimmed, "|") && i+1 < len(lines) && tableDivider.MatchString(strings.TrimSpace(lines[i+1])) {
+		end := i + 2
+		for end < len(lines) && strings.Contains(lines[end], "|") && strings.TrimSpace(lines[end]) != "" {
+			end++
+		}
+		rows := append([]string{lines[i]}, lines[i+2:end]...)
+		return []notion.Block{tableBlock(rows)}, end - 1
+	}
+	if !ends {
+		return nil, i
+	}
+	if m := imageLine.FindStringSubmatch(trimmed); m != nil && bookmarkLine.MatchString(m[2]) {
+		image := notion.ExternalFile(m[2])
+		if m[1] != "" {
+			image.Caption = plainRichText(m[1])
+		}
+		return []notion.Block{{Type: "image", Image: image}}, i
+	}
+	if m := bookmarkLine.FindStringSubmatch(trimmed); m != nil {
+		return []notion.Block{{Type: "bookmark", Bookmark: &notion.LinkBlock{URL: m[1]}}}, i
+	}
+	return nil, i
+}
+
+// toggleBlock converts the <details> element starting at lines[i], as pages export writes
+// toggles, into a toggle whose children are the converted content. It returns the index of
+// the closing </details>, or of the last line when the element is not closed.
+func toggleBlock(lines []string, i int) (notion.Block, int, error) {
+	end, depth := i+1, 1
+	for ; end < len(lines); end++ {
+		switch strings.TrimSpace(lines[end]) {
+		case "<details>":
+			depth++
+		case "</details>":
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	inner := lines[i+1 : min(end, len(lines))]
+
+	summary := ""
+	for j, line := range inner {
+		if m := summaryLine.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			summary, inner = m[1], inner[j+1:]
+			break
+		}
+		if strings.TrimSpace(line) != "" {
+			break
+		}
+	}
+	children, err := ToBlocks(strings.Join(inner, "\n"))
+	if err != nil {
+		return notion.Block{}, 0, err
+	}
+	toggle := notion.Block{Type: "toggle", Toggle: &notion.ToggleBlock{
+		RichText: inlineRichText(summary),
+		Children: children,
+	}}
+	return toggle, min(end, len(lines)-1), nil
+}
+
+// codeBlock converts the fenced code block opening at lines[i], returning the index of its
+// closing fence, or of the last line when it is not closed.
+func codeBlock(lines []string, i int) (notion.Block, int) {
+	opening := strings.TrimSpace(lines[i])
+	marker := opening[:len(opening)-len(strings.TrimLeft(opening, opening[:1]))]
+	end := i + 1
+	for end < len(lines) {
+		closing := strings.TrimSpace(lines[end])
+		if strings.HasPrefix(closing, marker) && strings.Trim(closing, marker[:1]) == "" {
+			break
+		}
+		end++
+	}
+	content := strings.Join(lines[i+1:min(end, len(lines))], "\n")
+	code := notion.Block{Type: "code", Code: &notion.CodeBlock{
+		RichText: chunkedRichText(content),
+		Language: codeLanguage(opening[len(marker):]),
+	}}
+	return code, min(end, len(lines)-1)
+}
+
+// chunkedRichText splits text into rich text objects within Notion's 2,000 character limit.
+func chunkedRichText(text string) []notion.RichText {
+	chunks := notion.ChunkText(text)
+	parts := make([]notion.RichText, 0, len(chunks))
+	for _, chunk := range chunks {
+		parts = append(parts, plainRichText(chunk)...)
+	}
+	return parts
+}
+
+// isThematicBreak reports whether line is three or more -, *, or _ characters, optionally
+// separated by spaces.
+func isThematicBreak(line string) bool {
+	compact := strings.ReplaceAll(line, " ", "")
+	if len(compact) < 3 {
+		return false
+	}
+	return strings.Count(compact, compact[:1]) == len(compact) && strings.ContainsAny(compact[:1], "-*_")
+}
+
+// tableBlock builds a table from its header row and body rows, padding short rows so every
+// row has a cell per column as Notion requires.
+func tableBlock(lines []string) notion.Block {
+	rows := make([][]string, 0, len(lines))
+	width := 0
+	for _, line := range lines {
+		cells := splitTableRow(line)
+		width = max(width, len(cells))
+		rows = append(rows, cells)
+	}
+	children := make([]notion.Block, 0, len(rows))
+	for _, cells := range rows {
+		row := &notion.TableRowBlock{Cells: make([][]notion.RichText, width)}
+		for j := range width {
+			row.Cells[j] = []notion.RichText{}
+			if j < len(cells) {
+				row.Cells[j] = inlineRichText(cells[j])
+			}
+		}
+		children = append(children, notion.Block{Type: "table_row", TableRow: row})
+	}
+	return notion.Block{Type: "table", Table: &notion.TableBlock{
+		TableWidth:      width,
+		HasColumnHeader: true,
+		Children:        children,
+	}}
+}
+
+// splitTableRow splits a table row on unescaped pipes, dropping the outer ones.
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// inlineRichText converts inline Markdown such as a table cell into rich text, keeping the
+// text as-is when notionmd does not read it as a single paragraph.
+func inlineRichText(text string) []notion.RichText {
+	text = strings.ReplaceAll(text, "<br>", "\n")
+	if text == "" {
+		return []notion.RichText{}
+	}
+	blocks, err := convertText(text)
+	if err == nil && len(blocks) == 1 && blocks[0].Paragraph != nil {
+		return blocks[0].Paragraph.RichText
+	}
+	return plainRichText(text)
+}
+
+func plainRichText(text string) []notion.RichText {
+	return []notion.RichText{{Type: "text", Text: &notion.Text{Content: text}, PlainText: text}}
+}
+
+func convertText(body string) ([]notion.Block, error) {
+	converted, err := notionmd.ConvertToJSON(body)
+	if err != nil {
+		return nil, fmt.Errorf("convert markdown: %w", err)
+	}
+	encoded, err := json.Marshal(converted)
+	if err != nil {
+		return nil, fmt.Errorf("encode blocks: %w", err)
+	}
+	var blocks []notion.Block
+	if err := json.Unmarshal(encoded, &blocks); err != nil {
+		return nil, fmt.Errorf("decode blocks: %w", err)
+	}
+	return normalizeBlocks(blocks), nil
+}
+
+// normalizeBlocks fills in the type notionmd leaves empty and turns bulleted items starting
+// with "[ ]" or "[x]", which notionmd keeps as text, into to-do blocks, at any depth.
+func normalizeBlocks(blocks []notion.Block) []notion.Block {
+	for i, block := range blocks {
+		if children := block.Children(); len(children) > 0 {
+			block.SetChildren(normalizeBlocks(children))
+		}
+		if blocks[i].Type == "" {
+			blocks[i].Type = blockType(block)
+		}
+		item := block.BulletedListItem
+		if item == nil || len(item.RichText) == 0 || item.RichText[0].Text == nil {
+			continue
+		}
+		first := item.RichText[0]
+		rest, checked := "", false
+		switch content := first.Text.Content; {
+		case strings.HasPrefix(content, "[ ] "):
+			rest = content[4:]
+		case strings.HasPrefix(content, "[x] "), strings.HasPrefix(content, "[X] "):
+			rest, checked = content[4:], true
+		default:
+			continue
+		}
+		text := *first.Text
+		text.Content = rest
+		first.Text, first.PlainText = &text, rest
+		richText := append([]notion.RichText{first}, item.RichText[1:]...)
+		blocks[i] = notion.Block{Type: "to_do", ToDo: &notion.ToDoBlock{
+			RichText: richText,
+			Children: item.Children,
+			Checked:  checked,
+		}}
+	}
+	return blocks
+}
+
+func blockType(block notion.Block) string {
+	switch {
+	case block.Paragraph != nil:
+		return "paragraph"
+	case block.Heading1 != nil:
+		return "heading_1"
+	case block.Heading2 != nil:
+		return "heading_2"
+	case block.Heading3 != nil:
+		return "heading_3"
+	case block.BulletedListItem != nil:
+		return "bulleted_list_item"
+	case block.NumberedListItem != nil:
+		return "numbered_list_item"
+	case block.Quote != nil:
+		return "quote"
+	case block.Code != nil:
+		return "code"
+	}
+	return ""
+}
+
+// roundTripTypes are the block types ToBlocks recreates from what FromBlocks writes.
+// Callouts and embeds come back as quotes and bookmarks, so they are not among them.
+var roundTripTypes = map[string]bool{
+	"paragraph":          true,
+	"heading_1":          true,
+	"heading_2":          true,
+	"heading_3":          true,
+	"bulleted_list_item": true,
+	"numbered_list_item": true,
+	"to_do":              true,
+	"quote":              true,
+	"code":               true,
+	"toggle":             true,
+	"divider":            true,
+	"table":              true,
+	"table_row":          true,
+	"bookmark":           true,
+	"image":              true,
+}
+
+// Unsupported returns the sorted types of the blocks, at any depth, that would be lost or
+// changed by writing them as Markdown and converting them back. Images hosted by Notion are
+// among them, since their exported URLs expire.
+func Unsupported(blocks []notion.Block) []string {
+	seen := map[string]bool{}
+	var walk func([]notion.Block)
+	walk = func(blocks []notion.Block) {
+		for _, block := range blocks {
+			hosted := block.Image != nil && block.Image.File != nil
+			if !roundTripTypes[block.Type] || hosted {
+				seen[block.Type] = true
+			}
+			walk(block.Children())
+		}
+	}
+	walk(blocks)
+
+	types := make([]string, 0, len(seen))
+	for name := range seen {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	return types
+}
@@ -0,0 +1,320 @@
+// Package mirror maintains a local SQLite copy of a Notion data source. Statements are run
+// through the sqlite3 command-line shell, so no cgo driver is needed; each sync is applied
+// in a single transaction.
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Column types understood by the mirror.
+const (
+	TypeText    = "TEXT"
+	TypeReal    = "REAL"
+	TypeInteger = "INTEGER"
+)
+
+// stateTable records how far each mirrored table has been synced.
+const stateTable = "_notionctl_mirror"
+
+// KeyColumn is the primary key of every mirrored table: the Notion page ID.
+const KeyColumn = "id"
+
+// Column describes one mirrored column.
+type Column struct {
+	Name string
+	Type string
+}
+
+// Table describes a mirrored table. The KeyColumn is implied and must not be listed.
+type Table struct {
+	Name    string
+	Columns []Column
+}
+
+// Row maps column names to values: nil, string, float64, int64, or bool.
+type Row map[string]any
+
+// DB is a SQLite database file driven through the sqlite3 shell.
+type DB struct {
+	path   string
+	binary string
+}
+
+// Open locates the sqlite3 binary (binary may be a name on PATH or a path) for the
+// database at path. The file is created on first write.
+func Open(path, binary string) (*DB, error) {
+	if binary == "" {
+		binary = "sqlite3"
+	}
+	resolved, err := exec.LookPath(binary)
+	if err != nil {
+		return nil, fmt.Errorf("find sqlite3 shell %q: %w", binary, err)
+	}
+	return &DB{path: path, binary: resolved}, nil
+}
+
+// Ensure creates the table and state table if needed and adds columns missing from an
+// existing table. Columns are never dropped, so renamed properties keep their old data.
+func (db *DB) Ensure(ctx context.Context, t Table) error {
+	var sql strings.Builder
+	fmt.Fprintf(&sql, "CREATE TABLE IF NOT EXISTS %s (table_name TEXT PRIMARY KEY, data_source_id TEXT, synced_until TEXT);\n",
+		quoteIdent(stateTable))
+	fmt.Fprintf(&sql, "CREATE TABLE IF NOT EXISTS %s (%s TEXT PRIMARY KEY);\n", quoteIdent(t.Name), quoteIdent(KeyColumn))
+	if err := db.exec(ctx, sql.String()); err != nil {
+		return err
+	}
+
+	existing, err := db.query(ctx, fmt.Sprintf("SELECT name FROM pragma_table_info(%s);", quoteString(t.Name)))
+	if err != nil {
+		return err
+	}
+	have := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		have[strings.ToLower(name)] = true
+	}
+
+	sql.Reset()
+	for _, col := range t.Columns {
+		if have[strings.ToLower(col.Name)] {
+			continue
+		}
+		fmt.Fprintf(&sql, "ALTER TABLE %s ADD COLUMN %s %s;\n", quoteIdent(t.Name), quoteIdent(col.Name), col.Type)
+	}
+	if sql.Len() == 0 {
+		return nil
+	}
+	return db.exec(ctx, sql.String())
+}
+
+// SyncedUntil returns the end of the last completed sync of table, if any.
+func (db *DB) SyncedUntil(ctx context.Context, table string) (time.Time, bool, error) {
+	lines, err := db.query(ctx, fmt.Sprintf("SELECT synced_until FROM %s WHERE table_name = %s;",
+		quoteIdent(stateTable), quoteString(table)))
+	if err != nil || len(lines) == 0 || lines[0] == "" {
+		return time.Time{}, false, err
+	}
+	t, err := time.Parse(time.RFC3339, lines[0])
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("parse sync state %q: %w", lines[0], err)
+	}
+	return t, true, nil
+}
+
+// Apply upserts rows into t and records syncedUntil in one transaction. With replace set,
+// existing rows are deleted first so the table matches rows exactly.
+func (db *DB) Apply(
+	ctx context.Context,
+	t Table,
+	dataSourceID string,
+	rows []Row,
+	syncedUntil time.Time,
+	replace bool,
+) error {
+	var sql strings.Builder
+	sql.WriteString("BEGIN;\n")
+	if replace {
+		fmt.Fprintf(&sql, "DELETE FROM %s;\n", quoteIdent(t.Name))
+	}
+	for _, row := range rows {
+		if err := writeUpsert(&sql, t, row); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(&sql, "INSERT INTO %s (table_name, data_source_id, synced_until) VALUES (%s, %s, %s) "+
+		"ON CONFLICT(table_name) DO UPDATE SET data_source_id = excluded.data_source_id, synced_until = excluded.synced_until;\n",
+		quoteIdent(stateTable), quoteString(t.Name), quoteString(dataSourceID),
+		quoteString(syncedUntil.UTC().Format(time.RFC3339)))
+	sql.WriteString("COMMIT;\n")
+	return db.exec(ctx, sql.String())
+}
+
+// Replace drops t if it exists and recreates it holding exactly rows, in one transaction.
+// Unlike Apply it records no sync state, so the table is a standalone snapshot.
+func (db *DB) Replace(ctx context.Context, t Table, rows []Row) error {
+	var sql strings.Builder
+	sql.WriteString("BEGIN;\n")
+	columns := []string{quoteIdent(KeyColumn) + " TEXT PRIMARY KEY"}
+	for _, col := range t.Columns {
+		columns = append(columns, quoteIdent(col.Name)+" "+col.Type)
+	}
+	fmt.Fprintf(&sql, "DROP TABLE IF EXISTS %s;\n", quoteIdent(t.Name))
+	fmt.Fprintf(&sql, "CREATE TABLE %s (%s);\n", quoteIdent(t.Name), strings.Join(columns, ", "))
+	for _, row := range rows {
+		if err := writeUpsert(&sql, t, row); err != nil {
+			return err
+		}
+	}
+	sql.WriteString("COMMIT;\n")
+	return db.exec(ctx, sql.String())
+}
+
+// Mirrored returns the table holding dataSourceID and the end of its last sync.
+func (db *DB) Mirrored(ctx context.Context, dataSourceID string) (string, time.Time, bool, error) {
+	exists, err := db.query(ctx, fmt.Sprintf("SELECT name FROM sqlite_master WHERE type = 'table' AND name = %s;",
+		quoteString(stateTable)))
+	if err != nil || len(exists) == 0 {
+		return "", time.Time{}, false, err
+	}
+	sql := fmt.Sprintf("SELECT table_name, synced_until FROM %s WHERE data_source_id = %s ORDER BY synced_until DESC LIMIT 1;",
+		quoteIdent(stateTable), quoteString(dataSourceID))
+	out, err := db.run(ctx, sql, "-noheader", "-list", "-separator", "\x1f")
+	if err != nil {
+		return "", time.Time{}, false, err
+	}
+	table, until, ok := strings.Cut(strings.TrimRight(out, "\n"), "\x1f")
+	if !ok {
+		return "", time.Time{}, false, nil
+	}
+	t, err := time.Parse(time.RFC3339, until)
+	if err != nil {
+		return "", time.Time{}, false, fmt.Errorf("parse sync state %q: %w", until, err)
+	}
+	return table, t, true, nil
+}
+
+// Columns lists the columns of table in order, including KeyColumn.
+func (db *DB) Columns(ctx context.Context, table string) ([]string, error) {
+	return db.query(ctx, fmt.Sprintf("SELECT name FROM pragma_table_info(%s);", quoteString(table)))
+}
+
+// Order is one ORDER BY column for Rows.
+type Order struct {
+	Column string
+	Desc   bool
+}
+
+// Rows reads every row of table in the given order. REAL and INTEGER columns decode as
+// float64 and NULLs as nil.
+func (db *DB) Rows(ctx context.Context, table string, order ...Order) ([]Row, error) {
+	var sql strings.Builder
+	fmt.Fprintf(&sql, "SELECT * FROM %s", quoteIdent(table))
+	for i, o := range order {
+		if i == 0 {
+			sql.WriteString(" ORDER BY ")
+		} else {
+			sql.WriteString(", ")
+		}
+		sql.WriteString(quoteIdent(o.Column))
+		if o.Desc {
+			sql.WriteString(" DESC")
+		}
+	}
+	if len(order) > 0 {
+		fmt.Fprintf(&sql, ", %s", quoteIdent(KeyColumn))
+	}
+	sql.WriteString(";\n")
+
+	out, err := db.run(ctx, sql.String(), "-json")
+	if err != nil {
+		return nil, err
+	}
+	var rows []Row
+	if strings.TrimSpace(out) == "" {
+		return rows, nil
+	}
+	if err := json.Unmarshal([]byte(out), &rows); err != nil {
+		return nil, fmt.Errorf("decode rows of %s: %w", table, err)
+	}
+	return rows, nil
+}
+
+func writeUpsert(sql *strings.Builder, t Table, row Row) error {
+	id, ok := row[KeyColumn].(string)
+	if !ok || id == "" {
+		return errors.New("mirror row is missing its id")
+	}
+	names := []string{quoteIdent(KeyColumn)}
+	values := []string{quoteString(id)}
+	updates := make([]string, 0, len(t.Columns))
+	for _, col := range t.Columns {
+		literal, err := sqlLiteral(row[col.Name])
+		if err != nil {
+			return fmt.Errorf("column %s: %w", col.Name, err)
+		}
+		names = append(names, quoteIdent(col.Name))
+		values = append(values, literal)
+		updates = append(updates, fmt.Sprintf("%s = excluded.%s", quoteIdent(col.Name), quoteIdent(col.Name)))
+	}
+	fmt.Fprintf(sql, "INSERT INTO %s (%s) VALUES (%s)", quoteIdent(t.Name), strings.Join(names, ", "), strings.Join(values, ", "))
+	if len(updates) > 0 {
+		fmt.Fprintf(sql, " ON CONFLICT(%s) DO UPDATE SET %s", quoteIdent(KeyColumn), strings.Join(updates, ", "))
+	}
+	sql.WriteString(";\n")
+	return nil
+}
+
+func (db *DB) exec(ctx context.Context, sql string) error {
+	_, err := db.run(ctx, sql, "-bail")
+	return err
+}
+
+// query returns the first column of each result row.
+func (db *DB) query(ctx context.Context, sql string) ([]string, error) {
+	out, err := db.run(ctx, sql, "-noheader", "-list", "-separator", "\x1f")
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		first, _, _ := strings.Cut(line, "\x1f")
+		values = append(values, first)
+	}
+	return values, nil
+}
+
+func (db *DB) run(ctx context.Context, sql string, flags ...string) (string, error) {
+	args := append([]string{"-batch"}, flags...)
+	args = append(args, db.path)
+	cmd := exec.CommandContext(ctx, db.binary, args...) // #nosec G204 -- binary is chosen by the user
+	cmd.Stdin = strings.NewReader(sql)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("sqlite3: %s: %w", msg, err)
+		}
+		return "", fmt.Errorf("sqlite3: %w", err)
+	}
+	return stdout.String(), nil
+}
+
+func sqlLiteral(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "NULL", nil
+	case string:
+		return quoteString(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+		return "0", nil
+	default:
+		return "", fmt.Errorf("unsupported value type %T", value)
+	}
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func quoteString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,149 @@
+package mirror_test
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/mirror"
+)
+
+func TestMirrorRoundTrip(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 shell not installed")
+	}
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "mirror.sqlite")
+	db, err := mirror.Open(path, "")
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+
+	table := mirror.Table{Name: "Tasks", Columns: []mirror.Column{
+		{Name: "Name", Type: mirror.TypeText},
+		{Name: "Points", Type: mirror.TypeReal},
+	}}
+	if err := db.Ensure(ctx, table); err != nil {
+		t.Fatalf("Ensure returned error: %v", err)
+	}
+	if _, ok, err := db.SyncedUntil(ctx, "Tasks"); err != nil || ok {
+		t.Fatalf("expected no sync state yet, got ok=%v err=%v", ok, err)
+	}
+
+	first := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	rows := []mirror.Row{
+		{"id": "p1", "Name": "It's done", "Points": 3.0},
+		{"id": "p2", "Name": nil, "Points": nil},
+	}
+	if err := db.Apply(ctx, table, "ds", rows, first, true); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+
+	// A new property adds a column; an incremental upsert updates in place.
+	table.Columns = append(table.Columns, mirror.Column{Name: "Done?", Type: mirror.TypeInteger})
+	if err := db.Ensure(ctx, table); err != nil {
+		t.Fatalf("Ensure with new column returned error: %v", err)
+	}
+	second := first.Add(time.Hour)
+	if err := db.Apply(ctx, table, "ds", []mirror.Row{{"id": "p1", "Name": "Renamed", "Points": 5.0, "Done?": true}}, second, false); err != nil {
+		t.Fatalf("incremental Apply returned error: %v", err)
+	}
+
+	until, ok, err := db.SyncedUntil(ctx, "Tasks")
+	if err != nil || !ok || !until.Equal(second) {
+		t.Fatalf("SyncedUntil = %v,%v,%v; want %v", until, ok, err, second)
+	}
+
+	out, err := exec.Command("sqlite3", "-batch", path,
+		`SELECT id, Name, Points, "Done?" FROM Tasks ORDER BY id;`).Output()
+	if err != nil {
+		t.Fatalf("read back: %v", err)
+	}
+	if got, want := strings.TrimSpace(string(out)), "p1|Renamed|5.0|1\np2|||"; got != want {
+		t.Fatalf("table contents = %q, want %q", got, want)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 shell not installed")
+	}
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "export.sqlite")
+	db, err := mirror.Open(path, "")
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+
+	old := mirror.Table{Name: "pages", Columns: []mirror.Column{{Name: "Stale", Type: mirror.TypeText}}}
+	if err := db.Replace(ctx, old, []mirror.Row{{"id": "gone", "Stale": "x"}}); err != nil {
+		t.Fatalf("Replace returned error: %v", err)
+	}
+	table := mirror.Table{Name: "pages", Columns: []mirror.Column{{Name: "Points", Type: mirror.TypeReal}}}
+	if err := db.Replace(ctx, table, []mirror.Row{{"id": "p1", "Points": 2.5}}); err != nil {
+		t.Fatalf("second Replace returned error: %v", err)
+	}
+
+	out, err := exec.Command("sqlite3", "-batch", path,
+		`SELECT id, Points, typeof(Points) FROM pages; SELECT count(*) FROM sqlite_master WHERE name = '_notionctl_mirror';`).Output()
+	if err != nil {
+		t.Fatalf("read back: %v", err)
+	}
+	if got, want := strings.TrimSpace(string(out)), "p1|2.5|real\n0"; got != want {
+		t.Fatalf("contents = %q, want %q", got, want)
+	}
+}
+
+func TestReadBack(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 shell not installed")
+	}
+	ctx := context.Background()
+	db, err := mirror.Open(filepath.Join(t.TempDir(), "mirror.sqlite"), "")
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	if _, _, ok, err := db.Mirrored(ctx, "ds"); err != nil || ok {
+		t.Fatalf("expected nothing mirrored in a new file, got ok=%v err=%v", ok, err)
+	}
+
+	table := mirror.Table{Name: "Tasks", Columns: []mirror.Column{
+		{Name: "Name", Type: mirror.TypeText},
+		{Name: "Points", Type: mirror.TypeReal},
+	}}
+	if err := db.Ensure(ctx, table); err != nil {
+		t.Fatalf("Ensure returned error: %v", err)
+	}
+	synced := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	rows := []mirror.Row{
+		{"id": "p1", "Name": "Alpha", "Points": 3.0},
+		{"id": "p2", "Name": "Beta", "Points": 10.0},
+		{"id": "p3", "Name": nil, "Points": nil},
+	}
+	if err := db.Apply(ctx, table, "ds", rows, synced, true); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+
+	name, until, ok, err := db.Mirrored(ctx, "ds")
+	if err != nil || !ok || name != "Tasks" || !until.Equal(synced) {
+		t.Fatalf("Mirrored = %q, %v, %v, %v", name, until, ok, err)
+	}
+	columns, err := db.Columns(ctx, "Tasks")
+	if err != nil || strings.Join(columns, ",") != "id,Name,Points" {
+		t.Fatalf("Columns = %v, %v", columns, err)
+	}
+
+	got, err := db.Rows(ctx, "Tasks", mirror.Order{Column: "Points", Desc: true})
+	if err != nil {
+		t.Fatalf("Rows returned error: %v", err)
+	}
+	if len(got) != 3 || got[0]["id"] != "p2" || got[0]["Points"] != 10.0 || got[2]["Name"] != nil {
+		t.Fatalf("Rows = %#v", got)
+	}
+	if _, err := db.Rows(ctx, "Missing"); err == nil {
+		t.Fatal("expected error for a missing table")
+	}
+}
@@ -0,0 +1,150 @@
+// Package notify delivers notifications to chat services, webhooks, and the desktop.
+//
+// Every provider consumes the same Message model so features that need to alert someone
+// (watch forwarding, monitors, digests, validation failures) share one sender.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Level signals the severity of a message; providers may use it for colouring.
+type Level string
+
+// Supported message levels.
+const (
+	LevelInfo    Level = "info"
+	LevelWarning Level = "warning"
+	LevelError   Level = "error"
+)
+
+// Provider names accepted by New and ParseTarget.
+const (
+	ProviderSlack   = "slack"
+	ProviderDiscord = "discord"
+	ProviderTeams   = "teams"
+	ProviderWebhook = "webhook"
+	ProviderDesktop = "desktop"
+)
+
+const defaultHTTPTimeout = 10 * time.Second
+
+// Field is a labelled value rendered alongside the message body.
+type Field struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Message is the provider-neutral notification payload.
+type Message struct {
+	Title  string  `json:"title"`
+	Body   string  `json:"body,omitempty"`
+	URL    string  `json:"url,omitempty"`
+	Level  Level   `json:"level,omitempty"`
+	Fields []Field `json:"fields,omitempty"`
+}
+
+// Notifier sends a message to a single destination.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// Config selects and configures a provider.
+type Config struct {
+	HTTPClient *http.Client
+	Provider   string
+	URL        string
+}
+
+// New constructs the notifier described by cfg.
+func New(cfg Config) (Notifier, error) {
+	provider := strings.ToLower(strings.TrimSpace(cfg.Provider))
+	if provider == ProviderDesktop {
+		return desktopNotifier{}, nil
+	}
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("%s notifier requires a URL", provider)
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	poster := jsonPoster{client: httpClient, url: cfg.URL}
+
+	switch provider {
+	case ProviderSlack:
+		return slackNotifier{poster: poster}, nil
+	case ProviderDiscord:
+		return discordNotifier{poster: poster}, nil
+	case ProviderTeams:
+		return teamsNotifier{poster: poster}, nil
+	case ProviderWebhook:
+		return webhookNotifier{poster: poster}, nil
+	default:
+		return nil, fmt.Errorf("unknown notification provider %q", cfg.Provider)
+	}
+}
+
+// ParseTarget parses a "provider=url" specification (or just "desktop"). A bare URL is
+// treated as a generic webhook.
+func ParseTarget(spec string) (Config, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return Config{}, errors.New("notification target is empty")
+	}
+	if strings.EqualFold(spec, ProviderDesktop) {
+		return Config{Provider: ProviderDesktop}, nil
+	}
+	provider, target, found := strings.Cut(spec, "=")
+	if !found {
+		return Config{Provider: ProviderWebhook, URL: spec}, nil
+	}
+	return Config{Provider: strings.ToLower(strings.TrimSpace(provider)), URL: strings.TrimSpace(target)}, nil
+}
+
+// Multi fans a message out to several notifiers, returning the joined delivery errors.
+func Multi(notifiers ...Notifier) Notifier {
+	return multiNotifier(notifiers)
+}
+
+type multiNotifier []Notifier
+
+func (m multiNotifier) Notify(ctx context.Context, msg Message) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// plainText renders the message as a compact text block for providers without rich layouts.
+func (m Message) plainText() string {
+	return m.textWithTitle(m.Title)
+}
+
+// textWithTitle renders the message like plainText but with a provider-formatted title.
+func (m Message) textWithTitle(title string) string {
+	var b strings.Builder
+	b.WriteString(title)
+	if m.Body != "" {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(m.Body)
+	}
+	for _, f := range m.Fields {
+		fmt.Fprintf(&b, "\n%s: %s", f.Name, f.Value)
+	}
+	if m.URL != "" {
+		fmt.Fprintf(&b, "\n%s", m.URL)
+	}
+	return b.String()
+}
@@ -0,0 +1,84 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notify"
+)
+
+func captureServer(t *testing.T, status int) (*httptest.Server, *map[string]any) {
+	t.Helper()
+	var captured map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read body: %v", err)
+		}
+		if err := json.Unmarshal(body, &captured); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &captured
+}
+
+func TestSlackNotifier(t *testing.T) {
+	server, captured := captureServer(t, http.StatusOK)
+
+	n, err := notify.New(notify.Config{Provider: notify.ProviderSlack, URL: server.URL})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	msg := notify.Message{
+		Title:  "Page changed",
+		Body:   "Fix login bug",
+		Fields: []notify.Field{{Name: "Status", Value: "Done"}},
+	}
+	if err := n.Notify(context.Background(), msg); err != nil {
+		t.Fatalf("Notify returned error: %v", err)
+	}
+	if got, want := (*captured)["text"], "*Page changed*\nFix login bug\nStatus: Done"; got != want {
+		t.Fatalf("slack text = %q, want %q", got, want)
+	}
+}
+
+func TestWebhookNotifierErrorStatus(t *testing.T) {
+	server, captured := captureServer(t, http.StatusBadGateway)
+
+	cfg, err := notify.ParseTarget(server.URL)
+	if err != nil {
+		t.Fatalf("ParseTarget returned error: %v", err)
+	}
+	if cfg.Provider != notify.ProviderWebhook {
+		t.Fatalf("provider = %q, want webhook", cfg.Provider)
+	}
+	n, err := notify.New(cfg)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if err := n.Notify(context.Background(), notify.Message{Title: "hello"}); err == nil {
+		t.Fatalf("expected error for 502 response")
+	}
+	if (*captured)["title"] != "hello" {
+		t.Fatalf("unexpected webhook payload: %#v", *captured)
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	cfg, err := notify.ParseTarget("Discord=https://discord.example/hook")
+	if err != nil {
+		t.Fatalf("ParseTarget returned error: %v", err)
+	}
+	if cfg.Provider != notify.ProviderDiscord || cfg.URL != "https://discord.example/hook" {
+		t.Fatalf("unexpected config: %#v", cfg)
+	}
+	if _, err := notify.New(notify.Config{Provider: "pager"}); err == nil {
+		t.Fatalf("expected error for unknown provider")
+	}
+}
@@ -0,0 +1,158 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const (
+	errorBodyLimit = 512
+
+	discordColorInfo    = 0x2f80ed
+	discordColorWarning = 0xf2c94c
+	discordColorError   = 0xeb5757
+)
+
+type jsonPoster struct {
+	client *http.Client
+	url    string
+}
+
+func (p jsonPoster) post(ctx context.Context, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode notification: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send notification: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, errorBodyLimit))
+		return fmt.Errorf("send notification: %s: %s", resp.Status, bytes.TrimSpace(snippet))
+	}
+	return nil
+}
+
+type slackNotifier struct{ poster jsonPoster }
+
+func (n slackNotifier) Notify(ctx context.Context, msg Message) error {
+	title := msg.Title
+	if title != "" {
+		title = "*" + title + "*"
+	}
+	return n.poster.post(ctx, map[string]any{"text": msg.textWithTitle(title)})
+}
+
+type discordNotifier struct{ poster jsonPoster }
+
+func (n discordNotifier) Notify(ctx context.Context, msg Message) error {
+	fields := make([]map[string]any, 0, len(msg.Fields))
+	for _, f := range msg.Fields {
+		fields = append(fields, map[string]any{"name": f.Name, "value": f.Value, "inline": true})
+	}
+	embed := map[string]any{
+		"title":       msg.Title,
+		"description": msg.Body,
+		"color":       discordColor(msg.Level),
+	}
+	if msg.URL != "" {
+		embed["url"] = msg.URL
+	}
+	if len(fields) > 0 {
+		embed["fields"] = fields
+	}
+	return n.poster.post(ctx, map[string]any{"embeds": []any{embed}})
+}
+
+func discordColor(level Level) int {
+	switch level {
+	case LevelWarning:
+		return discordColorWarning
+	case LevelError:
+		return discordColorError
+	default:
+		return discordColorInfo
+	}
+}
+
+type teamsNotifier struct{ poster jsonPoster }
+
+func (n teamsNotifier) Notify(ctx context.Context, msg Message) error {
+	facts := make([]map[string]string, 0, len(msg.Fields))
+	for _, f := range msg.Fields {
+		facts = append(facts, map[string]string{"name": f.Name, "value": f.Value})
+	}
+	card := map[string]any{
+		"@type":    "MessageCard",
+		"@context": "https://schema.org/extensions",
+		"summary":  msg.Title,
+		"title":    msg.Title,
+		"text":     msg.Body,
+	}
+	if len(facts) > 0 {
+		card["sections"] = []any{map[string]any{"facts": facts}}
+	}
+	if msg.URL != "" {
+		card["potentialAction"] = []any{map[string]any{
+			"@type":   "OpenUri",
+			"name":    "Open in Notion",
+			"targets": []any{map[string]string{"os": "default", "uri": msg.URL}},
+		}}
+	}
+	return n.poster.post(ctx, card)
+}
+
+type webhookNotifier struct{ poster jsonPoster }
+
+func (n webhookNotifier) Notify(ctx context.Context, msg Message) error {
+	return n.poster.post(ctx, msg)
+}
+
+type desktopNotifier struct{}
+
+func (desktopNotifier) Notify(ctx context.Context, msg Message) error {
+	body := msg.Body
+	if body == "" {
+		body = msg.plainText()
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := "display notification " + appleScriptString(body) + " with title " + appleScriptString(msg.Title)
+		cmd = exec.CommandContext(ctx, "osascript", "-e", script)
+	case "linux", "freebsd", "openbsd":
+		// -- keeps a title starting with "-" from being read as an option.
+		cmd = exec.CommandContext(ctx, "notify-send", "--", msg.Title, body) // #nosec G204 -- arguments are not shell-interpreted
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("desktop notification: %w: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// appleScriptQuoter escapes the only two characters special inside an AppleScript string
+// literal; everything else, newlines and non-ASCII text included, is taken as written.
+var appleScriptQuoter = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+func appleScriptString(text string) string {
+	return `"` + appleScriptQuoter.Replace(text) + `"`
+}
@@ -35,6 +35,35 @@ func (c *Client) GetDataSource(ctx context.Context, dataSourceID string) (DataSo
 	return ds, nil
 }
 
+// CreateDataSource adds a data source with the requested schema to an existing database.
+func (c *Client) CreateDataSource(ctx context.Context, req CreateDataSourceRequest) (DataSource, error) {
+	if req.Parent.DatabaseID == "" {
+		return DataSource{}, fmt.Errorf("database parent cannot be empty")
+	}
+	var ds DataSource
+	if err := c.do(ctx, httpMethodPost, "data_sources", req, &ds); err != nil {
+		return DataSource{}, err
+	}
+	return ds, nil
+}
+
+// UpdateDataSource changes a data source's schema: adding, renaming, or retyping properties.
+func (c *Client) UpdateDataSource(
+	ctx context.Context,
+	dataSourceID string,
+	req UpdateDataSourceRequest,
+) (DataSource, error) {
+	if dataSourceID == "" {
+		return DataSource{}, fmt.Errorf("dataSourceID cannot be empty")
+	}
+	var ds DataSource
+	endpoint := path.Join("data_sources", dataSourceID)
+	if err := c.do(ctx, httpMethodPatch, endpoint, req, &ds); err != nil {
+		return DataSource{}, err
+	}
+	return ds, nil
+}
+
 // QueryDataSource executes a query against a Notion data source with pagination.
 func (c *Client) QueryDataSource(
 	ctx context.Context,
@@ -52,6 +81,32 @@ func (c *Client) QueryDataSource(
 	return resp, nil
 }
 
+// RetrieveView fetches a saved view definition, including its filter and sorts.
+func (c *Client) RetrieveView(ctx context.Context, viewID string) (View, error) {
+	if viewID == "" {
+		return View{}, fmt.Errorf("viewID cannot be empty")
+	}
+	var view View
+	if err := c.do(ctx, httpMethodGet, path.Join("views", viewID), nil, &view); err != nil {
+		return View{}, err
+	}
+	return view, nil
+}
+
+// ListViews lists the saved views defined on a data source.
+func (c *Client) ListViews(ctx context.Context, dataSourceID string) ([]View, error) {
+	if dataSourceID == "" {
+		return nil, fmt.Errorf("dataSourceID cannot be empty")
+	}
+	var resp struct {
+		Results []View `json:"results"`
+	}
+	if err := c.do(ctx, httpMethodGet, path.Join("data_sources", dataSourceID, "views"), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Results, nil
+}
+
 // RetrievePage fetches a page by ID.
 func (c *Client) RetrievePage(ctx context.Context, pageID string) (Page, error) {
 	if pageID == "" {
@@ -64,6 +119,28 @@ func (c *Client) RetrievePage(ctx context.Context, pageID string) (Page, error)
 	return page, nil
 }
 
+// RetrieveUser fetches a user or bot by ID.
+func (c *Client) RetrieveUser(ctx context.Context, userID string) (UserReference, error) {
+	if userID == "" {
+		return UserReference{}, fmt.Errorf("userID cannot be empty")
+	}
+	var user UserReference
+	if err := c.do(ctx, httpMethodGet, path.Join("users", userID), nil, &user); err != nil {
+		return UserReference{}, err
+	}
+	return user, nil
+}
+
+// RetrieveBotUser fetches the bot user the client's token belongs to, which also names its
+// workspace. It is the cheapest way to check that a token works.
+func (c *Client) RetrieveBotUser(ctx context.Context) (BotUser, error) {
+	var user BotUser
+	if err := c.do(ctx, httpMethodGet, "users/me", nil, &user); err != nil {
+		return BotUser{}, err
+	}
+	return user, nil
+}
+
 // UpdatePage applies changes to a page's properties or metadata.
 func (c *Client) UpdatePage(ctx context.Context, pageID string, req UpdatePageRequest) (Page, error) {
 	if pageID == "" {
@@ -76,16 +153,50 @@ func (c *Client) UpdatePage(ctx context.Context, pageID string, req UpdatePageRe
 	return page, nil
 }
 
-// AppendBlockChildren appends blocks to the specified block or page.
-func (c *Client) AppendBlockChildren(ctx context.Context, blockID string, blocks []Block) error {
+// CreatePage creates a page, typically a row within a data source.
+func (c *Client) CreatePage(ctx context.Context, req CreatePageRequest) (Page, error) {
+	if req.Parent.Type == "" {
+		return Page{}, fmt.Errorf("page parent cannot be empty")
+	}
+	var page Page
+	if err := c.do(ctx, httpMethodPost, "pages", req, &page); err != nil {
+		return Page{}, err
+	}
+	return page, nil
+}
+
+// AppendBlockChildren appends blocks to the specified block or page and returns the new
+// first-level blocks.
+func (c *Client) AppendBlockChildren(ctx context.Context, blockID string, blocks []Block) ([]Block, error) {
 	if blockID == "" {
-		return fmt.Errorf("blockID cannot be empty")
+		return nil, fmt.Errorf("blockID cannot be empty")
 	}
 	if len(blocks) == 0 {
-		return fmt.Errorf("no blocks supplied")
+		return nil, fmt.Errorf("no blocks supplied")
 	}
 	req := AppendBlockChildrenRequest{Children: blocks}
-	return c.do(ctx, httpMethodPatch, path.Join("blocks", blockID, "children"), req, nil)
+	var resp BlockChildrenResponse
+	if err := c.do(ctx, httpMethodPatch, path.Join("blocks", blockID, "children"), req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Results, nil
+}
+
+// DeleteBlock archives a block, removing it from its parent's content.
+func (c *Client) DeleteBlock(ctx context.Context, blockID string) error {
+	if blockID == "" {
+		return fmt.Errorf("blockID cannot be empty")
+	}
+	return c.do(ctx, httpMethodDelete, path.Join("blocks", blockID), nil, nil)
+}
+
+// RestoreBlock unarchives a deleted block, returning it to its parent's content.
+func (c *Client) RestoreBlock(ctx context.Context, blockID string) error {
+	if blockID == "" {
+		return fmt.Errorf("blockID cannot be empty")
+	}
+	req := map[string]any{"archived": false}
+	return c.do(ctx, httpMethodPatch, path.Join("blocks", blockID), req, nil)
 }
 
 // RetrieveBlockChildren fetches children blocks for a page/block.
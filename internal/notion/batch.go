@@ -0,0 +1,51 @@
+package notion
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// RetrievePages fetches pages by ID with at most concurrency requests in flight, all paced
+// by the client's rate limiter. Duplicate IDs are fetched once. The first failure cancels
+// the remaining requests and is returned.
+func (c *Client) RetrievePages(ctx context.Context, ids []string, concurrency int) (map[string]Page, error) {
+	return RetrievePagesWith(ctx, c.RetrievePage, ids, concurrency)
+}
+
+// RetrievePagesWith is RetrievePages for any single-page fetcher, such as a test fake.
+func RetrievePagesWith(
+	ctx context.Context,
+	retrieve func(ctx context.Context, pageID string) (Page, error),
+	ids []string,
+	concurrency int,
+) (map[string]Page, error) {
+	pages := make(map[string]Page, len(ids))
+	var mu sync.Mutex
+
+	g, groupCtx := errgroup.WithContext(ctx)
+	g.SetLimit(max(1, concurrency))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		g.Go(func() error {
+			page, err := retrieve(groupCtx, id)
+			if err != nil {
+				return fmt.Errorf("retrieve page %s: %w", id, err)
+			}
+			mu.Lock()
+			pages[id] = page
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
@@ -0,0 +1,51 @@
+package notion_test
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRetrievePagesFansOutWithinConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	inFlight, peak := 0, 0
+
+	client, cleanup := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		id := path.Base(r.URL.Path)
+		mu.Lock()
+		requests[id]++
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		if id == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"object":"error","status":404,"code":"object_not_found","message":"gone"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"object":"page","id":"` + id + `"}`))
+	})
+	defer cleanup()
+
+	pages, err := client.RetrievePages(context.Background(), []string{"a", "b", "a", "c", "d"}, 2)
+	if err != nil {
+		t.Fatalf("RetrievePages returned error: %v", err)
+	}
+	if len(pages) != 4 || pages["c"].ID != "c" {
+		t.Fatalf("unexpected pages %+v", pages)
+	}
+	if requests["a"] != 1 || peak > 2 {
+		t.Fatalf("expected deduplicated requests with at most 2 in flight, got %v (peak %d)", requests, peak)
+	}
+
+	_, err = client.RetrievePages(context.Background(), []string{"a", "missing"}, 2)
+	if err == nil || !strings.Contains(err.Error(), "retrieve page missing") {
+		t.Fatalf("expected the failing page to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package notion
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBreakerThreshold is how many consecutive 429 or 5xx responses open the breaker.
+	DefaultBreakerThreshold = 10
+	defaultBreakerCooldown  = 30 * time.Second
+)
+
+// ErrAPIDegraded is returned without contacting Notion while the circuit breaker is open.
+var ErrAPIDegraded = errors.New("Notion API appears degraded") //nolint:staticcheck // Notion is a proper noun
+
+// breaker fails requests fast after a run of 429 or 5xx responses across all of a client's
+// requests, so a bulk operation stops instead of retrying every page in full. After the
+// cooldown the breaker is half-open: exactly one request is let through as a probe while the
+// others keep failing fast. A success closes the breaker and another failure reopens it. A
+// probe that never gets a response (a network error or a canceled request) is replaced by a
+// new one after another cooldown.
+type breaker struct {
+	now        func() time.Time
+	openUntil  time.Time
+	probeUntil time.Time
+	mu         sync.Mutex
+	cooldown   time.Duration
+	threshold  int
+	failures   int
+	open       bool
+	probing    bool
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	if threshold < 0 {
+		return nil
+	}
+	if threshold == 0 {
+		threshold = DefaultBreakerThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = defaultBreakerCooldown
+	}
+	return &breaker{now: time.Now, threshold: threshold, cooldown: cooldown}
+}
+
+// allow reports whether a request may be sent. Once the cooldown is over, the first caller
+// becomes the probe.
+func (b *breaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.openErr(); err != nil {
+		return err
+	}
+	if !b.open {
+		return nil
+	}
+	now := b.now()
+	if b.probing && now.Before(b.probeUntil) {
+		return fmt.Errorf("%w: %d consecutive rate-limited or 5xx responses; waiting for a trial request",
+			ErrAPIDegraded, b.failures)
+	}
+	b.probing, b.probeUntil = true, now.Add(b.cooldown)
+	return nil
+}
+
+// check reports whether the breaker is open and cooling down, without claiming the probe. A
+// request uses it to stop retrying.
+func (b *breaker) check() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.openErr()
+}
+
+func (b *breaker) openErr() error {
+	if wait := b.openUntil.Sub(b.now()); wait > 0 {
+		return fmt.Errorf("%w: %d consecutive rate-limited or 5xx responses; failing fast for another %s",
+			ErrAPIDegraded, b.failures, wait.Round(time.Second))
+	}
+	return nil
+}
+
+func (b *breaker) record(resp *http.Response) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if resp == nil {
+		// No answer says nothing about Notion's health; let another request probe.
+		b.probing = false
+		return
+	}
+	if !isRetryableStatus(resp.StatusCode) {
+		b.failures, b.open, b.probing = 0, false, false
+		b.openUntil = time.Time{}
+		return
+	}
+	b.failures++
+	if b.probing || b.failures >= b.threshold {
+		b.open, b.probing = true, false
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
@@ -0,0 +1,154 @@
+package notion_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestBreakerFailsFastAfterConsecutiveFailures(t *testing.T) {
+	hits := 0
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits++
+		if failing {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := notion.NewClient(notion.ClientConfig{
+		Token:            "secret",
+		BaseURL:          server.URL,
+		MaxRetries:       -1,
+		BreakerThreshold: 3,
+		BreakerCooldown:  time.Hour,
+	})
+	client.WithLimiter(rate.NewLimiter(rate.Inf, 0))
+	get := func() error {
+		return client.Do(context.Background(), http.MethodGet, "users/me", nil, nil)
+	}
+
+	// A success in between resets the count.
+	_, _ = get(), get()
+	failing = false
+	if err := get(); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	failing = true
+	for range 2 {
+		if err := get(); errors.Is(err, notion.ErrAPIDegraded) {
+			t.Fatalf("breaker opened early after %d hits: %v", hits, err)
+		}
+	}
+	for range 2 {
+		if err := get(); !errors.Is(err, notion.ErrAPIDegraded) {
+			t.Fatalf("expected ErrAPIDegraded, got %v", err)
+		}
+	}
+	if hits != 6 {
+		t.Fatalf("expected the open breaker to skip the request, got %d hits", hits)
+	}
+}
+
+func TestBreakerStopsRetriesOfTheCurrentRequest(t *testing.T) {
+	hits := 0
+	client, cleanup := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer cleanup()
+
+	err := client.Do(context.Background(), http.MethodGet, "users/me", nil, nil)
+	if hits != 6 || errors.Is(err, notion.ErrAPIDegraded) {
+		t.Fatalf("expected one request's 6 attempts to stay under the default threshold, got %d: %v", hits, err)
+	}
+	err = client.Do(context.Background(), http.MethodGet, "users/me", nil, nil)
+	if !errors.Is(err, notion.ErrAPIDegraded) || hits != notion.DefaultBreakerThreshold {
+		t.Fatalf("expected the breaker to stop the second request at %d hits, got %d: %v",
+			notion.DefaultBreakerThreshold, hits, err)
+	}
+}
+
+func TestBreakerLetsOneProbeThroughAfterCooldown(t *testing.T) {
+	var hits atomic.Int32
+	var healthy atomic.Bool
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if hits.Add(1) == 2 {
+			<-release // hold the probe until every other caller has been turned away
+		}
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	const cooldown = 50 * time.Millisecond
+	client := notion.NewClient(notion.ClientConfig{
+		Token:            "secret",
+		BaseURL:          server.URL,
+		MaxRetries:       -1,
+		BreakerThreshold: 1,
+		BreakerCooldown:  cooldown,
+	})
+	client.WithLimiter(rate.NewLimiter(rate.Inf, 0))
+	get := func() error {
+		return client.Do(context.Background(), http.MethodGet, "users/me", nil, nil)
+	}
+
+	if err := get(); err == nil || hits.Load() != 1 {
+		t.Fatalf("expected the first request to reach Notion and fail, got %v", err)
+	}
+	time.Sleep(2 * cooldown)
+
+	const callers = 8
+	errs := make(chan error, callers)
+	var wg sync.WaitGroup
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- get()
+		}()
+	}
+	for range callers - 1 {
+		if err := <-errs; !errors.Is(err, notion.ErrAPIDegraded) {
+			t.Fatalf("expected callers other than the probe to fail fast, got %v", err)
+		}
+	}
+	close(release)
+	wg.Wait()
+	if err := <-errs; err == nil {
+		t.Fatal("expected the probe to reach Notion and fail")
+	}
+	if got := hits.Load(); got != 2 {
+		t.Fatalf("expected exactly one probe after the cooldown, got %d requests", got)
+	}
+
+	// The failed probe reopened the breaker.
+	if err := get(); !errors.Is(err, notion.ErrAPIDegraded) {
+		t.Fatalf("expected the failed probe to reopen the breaker, got %v", err)
+	}
+
+	healthy.Store(true)
+	time.Sleep(2 * cooldown)
+	for range 2 {
+		if err := get(); err != nil {
+			t.Fatalf("expected a successful probe to close the breaker, got %v", err)
+		}
+	}
+}
@@ -26,8 +26,9 @@ const (
 	defaultBackoffInitialDelay = 500 * time.Millisecond
 	defaultNotionVersion       = "2025-09-03"
 
-	limiterRatePerSecond = 3
-	limiterBurstTokens   = 6
+	// DefaultRequestsPerSecond is the sustained request rate allowed by Notion's API.
+	DefaultRequestsPerSecond = 3.0
+	limiterBurstFactor       = 2
 
 	backoffFactor       = 2.0
 	maxBackoffDelay     = 30 * time.Second
@@ -39,12 +40,33 @@ const (
 
 // ClientConfig configures the Notion client.
 type ClientConfig struct {
-	HTTPClient    *http.Client
+	HTTPClient *http.Client
+	// Transport replaces the default transport when HTTPClient is nil; see NewTransport.
+	Transport http.RoundTripper
+	// DebugLog receives a line per HTTP attempt with its method, path, status, attempt
+	// number, and latency.
+	DebugLog io.Writer
+	// Headers are added to every request, e.g. for an API gateway. They cannot replace
+	// Authorization or Notion-Version, which come from Token and NotionVersion.
+	Headers       map[string]string
 	Token         string
 	BaseURL       string
 	NotionVersion string
-	BackoffBase   time.Duration
-	MaxRetries    int
+	// BackoffBase is the first retry delay; it doubles on each further attempt.
+	BackoffBase time.Duration
+	// RequestsPerSecond is the sustained request rate; zero uses DefaultRequestsPerSecond.
+	RequestsPerSecond float64
+	// MaxRetries bounds retries of 429 and 5xx responses; zero uses the default of 5 and a
+	// negative value disables retries.
+	MaxRetries int
+	// BreakerThreshold is how many consecutive 429 or 5xx responses, across all requests,
+	// make the client fail fast with ErrAPIDegraded; zero uses DefaultBreakerThreshold and a
+	// negative value disables the breaker.
+	BreakerThreshold int
+	// BreakerCooldown is how long the breaker stays open; zero uses 30 seconds.
+	BreakerCooldown time.Duration
+	// DebugBodies adds headers, with credentials redacted, and bodies to DebugLog.
+	DebugBodies bool
 }
 
 // Client performs authenticated requests to the Notion REST API with retries.
@@ -52,6 +74,8 @@ type Client struct {
 	http    *http.Client
 	baseURL *url.URL
 	limiter *rate.Limiter
+	breaker *breaker
+	debug   *debugLog
 	jitter  func() float64
 	sleep   func(time.Duration)
 	cfg     ClientConfig
@@ -62,16 +86,23 @@ func NewClient(cfg ClientConfig) *Client {
 	httpClient := cfg.HTTPClient
 	if httpClient == nil {
 		httpClient = &http.Client{
-			Timeout: 30 * time.Second, //nolint:mnd // default HTTP client timeout
+			Timeout:   30 * time.Second, //nolint:mnd // default HTTP client timeout
+			Transport: cfg.Transport,
 		}
 	}
 
-	if cfg.MaxRetries <= 0 {
+	switch {
+	case cfg.MaxRetries == 0:
 		cfg.MaxRetries = defaultMaxRetries
+	case cfg.MaxRetries < 0:
+		cfg.MaxRetries = 0
 	}
 	if cfg.BackoffBase <= 0 {
 		cfg.BackoffBase = defaultBackoffInitialDelay
 	}
+	if cfg.RequestsPerSecond <= 0 {
+		cfg.RequestsPerSecond = DefaultRequestsPerSecond
+	}
 	if cfg.NotionVersion == "" {
 		cfg.NotionVersion = defaultNotionVersion
 	}
@@ -88,11 +119,18 @@ func NewClient(cfg ClientConfig) *Client {
 		panic(fmt.Sprintf("invalid Notion base URL %q: %v", base, err))
 	}
 
+	var debug *debugLog
+	if cfg.DebugLog != nil {
+		debug = &debugLog{w: cfg.DebugLog, bodies: cfg.DebugBodies}
+	}
+
 	return &Client{
 		cfg:     cfg,
+		debug:   debug,
+		breaker: newBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
 		http:    httpClient,
 		baseURL: parsed,
-		limiter: rate.NewLimiter(rate.Limit(limiterRatePerSecond), limiterBurstTokens),
+		limiter: NewRateLimiter(cfg.RequestsPerSecond),
 		sleep:   time.Sleep,
 		jitter:  func() float64 { return randomFloat64(jitterLowerBound, jitterUpperBound) },
 	}
@@ -110,12 +148,19 @@ func (c *Client) Do(ctx context.Context, method, path string, body any, out any)
 
 func (c *Client) executeWithRetries(ctx context.Context, req *http.Request, payload []byte, out any) error {
 	var lastErr error
+	stats := statsFromContext(ctx)
 	for attempt := 0; attempt <= c.cfg.MaxRetries; attempt++ {
 		if err := c.beforeAttempt(ctx, attempt, req, payload); err != nil {
 			return err
 		}
+		stats.record(attempt, c.cfg.NotionVersion)
 
+		started := time.Now()
 		resp, reqErr := c.http.Do(req)
+		latency := time.Since(started)
+		stats.response(resp, latency)
+		c.breaker.record(resp)
+		c.debug.attempt(req, payload, resp, reqErr, attempt, latency)
 		decision, closed := c.evaluateResponse(ctx, resp, reqErr, out)
 		decision = c.finalizeDecision(resp, decision, closed)
 		if decision.err != nil {
@@ -124,7 +169,12 @@ func (c *Client) executeWithRetries(ctx context.Context, req *http.Request, payl
 		if !decision.retry {
 			return decision.err
 		}
-		c.backoff(attempt, decision.retryAfter)
+		if err := c.breaker.check(); err != nil {
+			return errors.Join(err, decision.err)
+		}
+		if attempt < c.cfg.MaxRetries {
+			stats.wait(0, c.backoff(attempt, decision.retryAfter))
+		}
 	}
 
 	if lastErr == nil {
@@ -139,9 +189,14 @@ func (c *Client) do(ctx context.Context, method, path string, body any, out any)
 }
 
 func (c *Client) beforeAttempt(ctx context.Context, attempt int, req *http.Request, payload []byte) error {
+	if err := c.breaker.allow(); err != nil {
+		return err
+	}
+	started := time.Now()
 	if err := c.limiter.Wait(ctx); err != nil {
 		return fmt.Errorf("rate limit wait: %w", err)
 	}
+	statsFromContext(ctx).wait(time.Since(started), 0)
 	if attempt == 0 || payload == nil {
 		return nil
 	}
@@ -178,10 +233,13 @@ func (c *Client) prepareRequest(
 		req.ContentLength = int64(len(payload))
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.cfg.Token)
-	req.Header.Set("Notion-Version", c.cfg.NotionVersion)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", userAgent)
+	for name, value := range c.cfg.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.cfg.Token)
+	req.Header.Set("Notion-Version", c.cfg.NotionVersion)
 
 	return req, payload, nil
 }
@@ -262,10 +320,11 @@ func (c *Client) finalizeDecision(resp *http.Response, decision responseDecision
 	return decision
 }
 
-func (c *Client) backoff(attempt int, retryAfter time.Duration) {
+// backoff sleeps before the next attempt and returns how long it slept.
+func (c *Client) backoff(attempt int, retryAfter time.Duration) time.Duration {
 	if retryAfter > 0 {
 		c.sleep(retryAfter)
-		return
+		return retryAfter
 	}
 
 	delay := float64(c.cfg.BackoffBase) * math.Pow(backoffFactor, float64(attempt)) * c.jitter()
@@ -274,6 +333,7 @@ func (c *Client) backoff(attempt int, retryAfter time.Duration) {
 		backoff = maxBackoffDelay
 	}
 	c.sleep(backoff)
+	return backoff
 }
 
 func (c *Client) resolve(requestPath string) (string, error) {
@@ -328,6 +388,18 @@ func randomFloat64(min, max float64) float64 {
 	return min + diff*fraction
 }
 
+// NewRateLimiter returns a limiter allowing requestsPerSecond sustained requests with short
+// bursts of twice that rate.
+func NewRateLimiter(requestsPerSecond float64) *rate.Limiter {
+	burst := max(1, int(math.Ceil(requestsPerSecond*limiterBurstFactor)))
+	return rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+}
+
+// RequestsPerSecond reports the sustained request rate the client's limiter allows.
+func (c *Client) RequestsPerSecond() float64 {
+	return float64(c.limiter.Limit())
+}
+
 // WithLimiter allows overriding the rate limiter (used by tests).
 func (c *Client) WithLimiter(l *rate.Limiter) {
 	if l != nil {
@@ -349,6 +421,28 @@ func (c *Client) WithJitter(j func() float64) {
 	}
 }
 
+// Clone returns a copy sharing the rate limiter and circuit breaker, so one command can
+// wrap its transport without affecting other users of the client.
+func (c *Client) Clone() *Client {
+	clone := *c
+	return &clone
+}
+
+// WrapTransport layers wrap around the HTTP transport, e.g. to cache responses for a
+// long-lived session. The client's http.Client is copied, so shared clients are untouched.
+func (c *Client) WrapTransport(wrap func(http.RoundTripper) http.RoundTripper) {
+	if wrap == nil {
+		return
+	}
+	base := c.http.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	httpClient := *c.http
+	httpClient.Transport = wrap(base)
+	c.http = &httpClient
+}
+
 // SetToken updates the bearer token.
 func (c *Client) SetToken(token string) {
 	c.cfg.Token = token
@@ -1,10 +1,12 @@
 package notion_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -54,6 +56,31 @@ func TestClientSetsHeaders(t *testing.T) {
 	}
 }
 
+func TestClientSendsConfiguredHeaders(t *testing.T) {
+	var captured http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured = r.Header.Clone()
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := notion.NewClient(notion.ClientConfig{
+		Token:   "test-token",
+		BaseURL: server.URL + "/gateway/v1",
+		Headers: map[string]string{"x-gateway-key": "k1", "Authorization": "ignored"},
+	})
+	client.WithLimiter(rate.NewLimiter(rate.Inf, 0))
+	if err := client.Do(context.Background(), "GET", "ping", nil, nil); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if got := captured.Get("X-Gateway-Key"); got != "k1" {
+		t.Fatalf("X-Gateway-Key = %q, want k1", got)
+	}
+	if got := captured.Get("Authorization"); got != "Bearer test-token" {
+		t.Fatalf("Authorization = %q, want the token", got)
+	}
+}
+
 func TestClientRetriesOn429(t *testing.T) {
 	var mu sync.Mutex
 	attempts := 0
@@ -150,3 +177,142 @@ func TestListDataSources(t *testing.T) {
 		t.Fatalf("unexpected data sources: %#v", dataSources)
 	}
 }
+
+func TestRetrieveBotUser(t *testing.T) {
+	client, cleanup := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/me" || r.Method != http.MethodGet {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object":"user","id":"b1","type":"bot","name":"notionctl",` +
+			`"bot":{"owner":{"type":"workspace","workspace":true},"workspace_name":"Acme"}}`))
+	})
+	defer cleanup()
+
+	me, err := client.RetrieveBotUser(context.Background())
+	if err != nil {
+		t.Fatalf("RetrieveBotUser returned error: %v", err)
+	}
+	if me.ID != "b1" || me.Name != "notionctl" || me.Bot.WorkspaceName != "Acme" {
+		t.Fatalf("unexpected bot user: %#v", me)
+	}
+}
+
+func TestClientRecordsStatsFromContext(t *testing.T) {
+	var mu sync.Mutex
+	attempts := 0
+
+	client, cleanup := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write([]byte(`{"ok":true}`)); err != nil {
+			t.Fatalf("write success response: %v", err)
+		}
+	})
+	defer cleanup()
+
+	stats := &notion.Stats{}
+	ctx := notion.WithStats(context.Background(), stats)
+	for range 2 {
+		if err := client.Do(ctx, "GET", "/ping", nil, &struct{ OK bool }{}); err != nil {
+			t.Fatalf("Do returned error: %v", err)
+		}
+	}
+
+	if stats.Requests() != 3 || stats.Retries() != 1 {
+		t.Fatalf("expected 3 requests and 1 retry, got %d and %d", stats.Requests(), stats.Retries())
+	}
+	if stats.NotionVersion() == "" {
+		t.Fatal("expected the Notion-Version to be recorded")
+	}
+}
+
+func TestClientNegativeMaxRetriesDisablesRetries(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := notion.NewClient(notion.ClientConfig{Token: "test-token", BaseURL: server.URL, MaxRetries: -1})
+	client.WithSleeper(func(time.Duration) {})
+	if err := client.Do(context.Background(), http.MethodGet, "/ping", nil, nil); err == nil {
+		t.Fatalf("expected the 503 to be returned")
+	}
+	if attempts != 1 {
+		t.Fatalf("expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestClientDebugLogRedactsCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object":"list"}`))
+	}))
+	defer server.Close()
+
+	var log bytes.Buffer
+	client := notion.NewClient(notion.ClientConfig{
+		Token:       "secret_token",
+		BaseURL:     server.URL,
+		Headers:     map[string]string{"X-Gateway-Key": "gateway_secret", "X-Team": "ops"},
+		DebugLog:    &log,
+		DebugBodies: true,
+	})
+	var out map[string]any
+	if err := client.Do(context.Background(), http.MethodPost, "/search", map[string]any{"query": "roadmap"}, &out); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if out["object"] != "list" {
+		t.Fatalf("response body was not passed through after logging: %v", out)
+	}
+
+	got := log.String()
+	for _, want := range []string{
+		"POST /search -> 200 OK (attempt 1,",
+		"> Authorization: [redacted]",
+		"> X-Gateway-Key: [redacted]",
+		"> X-Team: ops",
+		`> {"query":"roadmap"}`,
+		`< {"object":"list"}`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("debug log missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "secret_token") || strings.Contains(got, "gateway_secret") {
+		t.Fatalf("debug log leaked a credential:\n%s", got)
+	}
+}
+
+func TestClientStatsTrackRateLimitsAndWaits(t *testing.T) {
+	attempts := 0
+	client, cleanup := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	})
+	defer cleanup()
+
+	stats := &notion.Stats{}
+	if err := client.Do(notion.WithStats(context.Background(), stats), "GET", "/ping", nil, nil); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if stats.RateLimited() != 1 || stats.Backoff() != 2*time.Second || stats.Latency() <= 0 {
+		t.Fatalf("rate limited %d, backoff %s, latency %s", stats.RateLimited(), stats.Backoff(), stats.Latency())
+	}
+	if stats.Requests() != 2 || stats.Errors() != 1 {
+		t.Fatalf("requests %d, errors %d", stats.Requests(), stats.Errors())
+	}
+}
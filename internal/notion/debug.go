@@ -0,0 +1,92 @@
+package notion
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const redacted = "[redacted]"
+
+// debugLog writes one line per HTTP attempt, plus headers and bodies when bodies is set.
+// Concurrent workers share it, so writes are serialized.
+type debugLog struct {
+	w      io.Writer
+	mu     sync.Mutex
+	bodies bool
+}
+
+func (d *debugLog) attempt(
+	req *http.Request,
+	payload []byte,
+	resp *http.Response,
+	reqErr error,
+	attempt int,
+	latency time.Duration,
+) {
+	if d == nil {
+		return
+	}
+	var respBody []byte
+	if d.bodies && resp != nil && resp.Body != nil {
+		respBody, _ = io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	}
+
+	outcome := "error: " + fmt.Sprint(reqErr)
+	if reqErr == nil && resp != nil {
+		outcome = resp.Status
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, _ = fmt.Fprintf(d.w, "notionctl: %s %s -> %s (attempt %d, %s)\n",
+		req.Method, req.URL.RequestURI(), outcome, attempt+1, latency.Round(time.Millisecond))
+	if !d.bodies {
+		return
+	}
+	d.headers("> ", req.Header)
+	d.body("> ", payload)
+	if resp != nil {
+		d.headers("< ", resp.Header)
+		d.body("< ", respBody)
+	}
+}
+
+func (d *debugLog) headers(prefix string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := strings.Join(header[name], ", ")
+		if sensitiveHeader(name) {
+			value = redacted
+		}
+		_, _ = fmt.Fprintf(d.w, "%s%s: %s\n", prefix, name, value)
+	}
+}
+
+func (d *debugLog) body(prefix string, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(d.w, "%s%s\n", prefix, bytes.TrimSpace(body))
+}
+
+// sensitiveHeader reports headers that carry credentials, including gateway keys set
+// through ClientConfig.Headers.
+func sensitiveHeader(name string) bool {
+	switch name = http.CanonicalHeaderKey(name); name {
+	case "Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie":
+		return true
+	}
+	lower := strings.ToLower(name)
+	return strings.Contains(lower, "token") || strings.Contains(lower, "secret") || strings.HasSuffix(lower, "-key")
+}
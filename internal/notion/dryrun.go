@@ -0,0 +1,70 @@
+package notion
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sync"
+)
+
+// readOnlyPost matches the POST endpoints that only read: searches and data source queries.
+var readOnlyPost = regexp.MustCompile(`/(search|(data_sources|databases)/[^/]+/query)$`)
+
+// DryRunRequest is what DryRun prints for each write it holds back.
+type DryRunRequest struct {
+	Body   json.RawMessage `json:"body,omitempty"`
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+}
+
+// DryRun returns a transport wrapper for Client.WrapTransport that prints each write to w
+// as indented JSON instead of sending it, and answers with an empty 200 response. Reads,
+// including queries and searches, still reach Notion so lookups behave as in a real run.
+func DryRun(w io.Writer) func(http.RoundTripper) http.RoundTripper {
+	var mu sync.Mutex
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method == http.MethodGet || req.Method == http.MethodHead ||
+				(req.Method == http.MethodPost && readOnlyPost.MatchString(req.URL.Path)) {
+				return next.RoundTrip(req)
+			}
+
+			planned := DryRunRequest{Method: req.Method, URL: req.URL.String()}
+			if req.Body != nil {
+				body, err := io.ReadAll(req.Body)
+				_ = req.Body.Close()
+				if err != nil {
+					return nil, fmt.Errorf("read request body: %w", err)
+				}
+				if len(body) > 0 {
+					planned.Body = body
+				}
+			}
+			data, err := json.MarshalIndent(planned, "", "  ")
+			if err != nil {
+				return nil, fmt.Errorf("encode dry-run request: %w", err)
+			}
+			mu.Lock()
+			_, err = fmt.Fprintf(w, "%s\n", data)
+			mu.Unlock()
+			if err != nil {
+				return nil, fmt.Errorf("write dry-run request: %w", err)
+			}
+
+			return &http.Response{
+				Status:        "200 OK",
+				StatusCode:    http.StatusOK,
+				Proto:         req.Proto,
+				ProtoMajor:    req.ProtoMajor,
+				ProtoMinor:    req.ProtoMinor,
+				Header:        http.Header{"Content-Type": {"application/json"}},
+				Body:          io.NopCloser(bytes.NewReader([]byte(`{}`))),
+				ContentLength: 2,
+				Request:       req,
+			}, nil
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package notion_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestDryRunPrintsWritesAndSendsReads(t *testing.T) {
+	var sent []string
+	client, cleanup := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Method+" "+r.URL.Path)
+		_, _ = w.Write([]byte(`{"object":"list","results":[]}`))
+	})
+	defer cleanup()
+
+	var out bytes.Buffer
+	client.WrapTransport(notion.DryRun(&out))
+	ctx := context.Background()
+	if _, err := client.QueryDataSource(ctx, "ds1", notion.QueryDataSourceRequest{}); err != nil {
+		t.Fatalf("QueryDataSource returned error: %v", err)
+	}
+	if _, err := client.RetrievePage(ctx, "page1"); err != nil {
+		t.Fatalf("RetrievePage returned error: %v", err)
+	}
+	archived := true
+	if _, err := client.UpdatePage(ctx, "page1", notion.UpdatePageRequest{Archived: &archived}); err != nil {
+		t.Fatalf("UpdatePage returned error: %v", err)
+	}
+
+	if len(sent) != 2 || sent[0] != "POST /data_sources/ds1/query" || sent[1] != "GET /pages/page1" {
+		t.Fatalf("expected only the reads to be sent, got %v", sent)
+	}
+	var planned notion.DryRunRequest
+	if err := json.Unmarshal(out.Bytes(), &planned); err != nil {
+		t.Fatalf("decode dry-run output: %v\n%s", err, out.String())
+	}
+	if planned.Method != http.MethodPatch || !strings.HasSuffix(planned.URL, "/pages/page1") ||
+		string(bytes.Join(bytes.Fields(planned.Body), nil)) != `{"archived":true}` {
+		t.Fatalf("unexpected dry-run request %+v", planned)
+	}
+}
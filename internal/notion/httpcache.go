@@ -0,0 +1,188 @@
+package notion
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/filelock"
+)
+
+// CacheStatusHeader tells callers whether a response came from the ResponseCache: "hit",
+// "revalidated", or "miss".
+const CacheStatusHeader = "X-Notionctl-Cache"
+
+// cacheablePath matches the object reads worth caching: page, data source, and database
+// retrievals. Lists, queries, and block children change too often.
+var cacheablePath = regexp.MustCompile(`/(pages|data_sources|databases)/[^/]+$`)
+
+// ResponseCache keeps GET responses for pages, data sources, and databases on disk, so
+// repeated runs skip re-fetching the same schema. Entries younger than the TTL are served
+// without a request; older ones are revalidated with If-None-Match or If-Modified-Since when
+// Notion supplied a validator, and re-fetched otherwise. Writes through the same client drop
+// the matching entry. Entries are keyed by token, Notion-Version, and URL.
+type ResponseCache struct {
+	now func() time.Time
+	dir string
+	ttl time.Duration
+	mu  sync.Mutex
+}
+
+type cachedResponse struct {
+	Stored time.Time   `json:"stored"`
+	Header http.Header `json:"header"`
+	URL    string      `json:"url"`
+	Body   []byte      `json:"body"`
+}
+
+// NewResponseCache stores entries under dir and serves them for ttl.
+func NewResponseCache(dir string, ttl time.Duration) *ResponseCache {
+	return &ResponseCache{now: time.Now, dir: dir, ttl: ttl}
+}
+
+// SetClock replaces the time source, for tests.
+func (c *ResponseCache) SetClock(now func() time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Clear removes every cached response.
+func (c *ResponseCache) Clear() error {
+	if err := os.RemoveAll(c.dir); err != nil {
+		return fmt.Errorf("clear response cache: %w", err)
+	}
+	return nil
+}
+
+// Wrap layers the cache over next; use it with Client.WrapTransport.
+func (c *ResponseCache) Wrap(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if !cacheablePath.MatchString(req.URL.Path) {
+			return next.RoundTrip(req)
+		}
+		path := c.path(req)
+		if req.Method != http.MethodGet {
+			resp, err := next.RoundTrip(req)
+			if err == nil && resp.StatusCode < http.StatusMultipleChoices {
+				_ = os.Remove(path)
+			}
+			return resp, err
+		}
+		return c.get(next, req, path)
+	})
+}
+
+func (c *ResponseCache) get(next http.RoundTripper, req *http.Request, path string) (*http.Response, error) {
+	cached, ok := c.load(path)
+	if ok && c.clock().Sub(cached.Stored) < c.ttl {
+		return cached.response(req, "hit"), nil
+	}
+
+	if ok {
+		req = req.Clone(req.Context())
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if modified := cached.Header.Get("Last-Modified"); modified != "" {
+			req.Header.Set("If-Modified-Since", modified)
+		}
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		_ = resp.Body.Close()
+		cached.Stored = c.clock()
+		c.store(path, cached)
+		return cached.response(req, "revalidated"), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	c.store(path, cachedResponse{Stored: c.clock(), Header: resp.Header.Clone(), URL: req.URL.String(), Body: body})
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.Header.Set(CacheStatusHeader, "miss")
+	return resp, nil
+}
+
+func (c *ResponseCache) clock() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now()
+}
+
+// path derives the entry file from the request. The token is hashed into the key so
+// profiles never read each other's cached objects.
+func (c *ResponseCache) path(req *http.Request) string {
+	u := *req.URL
+	u.RawQuery = ""
+	sum := sha256.Sum256([]byte(req.Header.Get("Authorization") + "\n" +
+		req.Header.Get("Notion-Version") + "\n" + u.String()))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *ResponseCache) load(path string) (cachedResponse, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cachedResponse{}, false
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return cachedResponse{}, false
+	}
+	return cached, true
+}
+
+// store writes an entry; failures only cost a future cache miss, so they are ignored.
+func (c *ResponseCache) store(path string, cached cachedResponse) {
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return
+	}
+	_ = filelock.WriteFile(path, data, 0o600)
+}
+
+func (r cachedResponse) response(req *http.Request, status string) *http.Response {
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set(CacheStatusHeader, status)
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         req.Proto,
+		ProtoMajor:    req.ProtoMajor,
+		ProtoMinor:    req.ProtoMinor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,88 @@
+package notion_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestResponseCacheServesAndRevalidates(t *testing.T) {
+	var gets, conditional int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		gets++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"object":"data_source","id":"ds1"}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	cache := notion.NewResponseCache(t.TempDir(), time.Minute)
+	cache.SetClock(func() time.Time { return now })
+	newClient := func(token string) *notion.Client {
+		client := notion.NewClient(notion.ClientConfig{Token: token, BaseURL: server.URL})
+		client.WrapTransport(cache.Wrap)
+		return client
+	}
+	client := newClient("secret_a")
+
+	retrieve := func(c *notion.Client) {
+		t.Helper()
+		var out map[string]any
+		if err := c.Do(context.Background(), http.MethodGet, "data_sources/ds1", nil, &out); err != nil {
+			t.Fatalf("Do returned error: %v", err)
+		}
+		if out["id"] != "ds1" {
+			t.Fatalf("unexpected body %v", out)
+		}
+	}
+
+	retrieve(client)
+	retrieve(newClient("secret_a"))
+	if gets != 1 {
+		t.Fatalf("expected a fresh entry to be served from disk, got %d requests", gets)
+	}
+
+	retrieve(newClient("secret_b"))
+	if gets != 2 {
+		t.Fatalf("expected another token to miss the cache, got %d requests", gets)
+	}
+
+	now = now.Add(2 * time.Minute)
+	retrieve(client)
+	if gets != 3 || conditional != 1 {
+		t.Fatalf("expected a conditional revalidation, got %d requests (%d conditional)", gets, conditional)
+	}
+	retrieve(client)
+	if gets != 3 {
+		t.Fatalf("expected the revalidated entry to be fresh again, got %d requests", gets)
+	}
+
+	if err := client.Do(context.Background(), http.MethodPatch, "data_sources/ds1", map[string]any{}, nil); err != nil {
+		t.Fatalf("PATCH returned error: %v", err)
+	}
+	retrieve(client)
+	if gets != 4 || conditional != 1 {
+		t.Fatalf("expected a write to drop the entry, got %d requests (%d conditional)", gets, conditional)
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear returned error: %v", err)
+	}
+	retrieve(client)
+	if gets != 5 {
+		t.Fatalf("expected Clear to empty the cache, got %d requests", gets)
+	}
+}
@@ -0,0 +1,45 @@
+package notion
+
+import (
+	"context"
+	"iter"
+)
+
+// QueryDataSourceIter yields the rows matching req as each result page arrives, following
+// next_cursor until the results run out or the caller stops ranging. Only one result page
+// is held in memory at a time. A failed request ends the sequence with its error.
+func (c *Client) QueryDataSourceIter(
+	ctx context.Context,
+	dataSourceID string,
+	req QueryDataSourceRequest,
+) iter.Seq2[Page, error] {
+	return QueryDataSourceIterWith(ctx, c.QueryDataSource, dataSourceID, req)
+}
+
+// QueryDataSourceIterWith is QueryDataSourceIter for any single-page query function, such
+// as a test fake.
+func QueryDataSourceIterWith(
+	ctx context.Context,
+	query func(ctx context.Context, dataSourceID string, req QueryDataSourceRequest) (QueryDataSourceResponse, error),
+	dataSourceID string,
+	req QueryDataSourceRequest,
+) iter.Seq2[Page, error] {
+	return func(yield func(Page, error) bool) {
+		for {
+			resp, err := query(ctx, dataSourceID, req)
+			if err != nil {
+				yield(Page{}, err)
+				return
+			}
+			for _, page := range resp.Results {
+				if !yield(page, nil) {
+					return
+				}
+			}
+			if !resp.HasMore || resp.NextCursor == "" {
+				return
+			}
+			req.StartCursor = resp.NextCursor
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package notion_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestQueryDataSourceIterFollowsCursors(t *testing.T) {
+	var requests int
+	client, cleanup := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var body struct {
+			StartCursor string `json:"start_cursor"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		switch body.StartCursor {
+		case "":
+			_, _ = w.Write([]byte(`{"results":[{"id":"p1"},{"id":"p2"}],"has_more":true,"next_cursor":"c2"}`))
+		case "c2":
+			_, _ = w.Write([]byte(`{"results":[{"id":"p3"}],"has_more":true,"next_cursor":"c3"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"object":"error","status":400,"code":"validation_error","message":"bad cursor"}`))
+		}
+	})
+	defer cleanup()
+
+	var ids []string
+	var iterErr error
+	for page, err := range client.QueryDataSourceIter(context.Background(), "ds", notion.QueryDataSourceRequest{}) {
+		if err != nil {
+			iterErr = err
+			break
+		}
+		ids = append(ids, page.ID)
+	}
+	if strings.Join(ids, ",") != "p1,p2,p3" {
+		t.Fatalf("unexpected rows %v", ids)
+	}
+	if iterErr == nil || !strings.Contains(iterErr.Error(), "bad cursor") {
+		t.Fatalf("expected the failing request to end the sequence, got %v", iterErr)
+	}
+
+	requests = 0
+	for page, err := range client.QueryDataSourceIter(context.Background(), "ds", notion.QueryDataSourceRequest{}) {
+		if err != nil || page.ID == "p1" {
+			break
+		}
+	}
+	if requests != 1 {
+		t.Fatalf("expected stopping early to skip later pages, got %d requests", requests)
+	}
+}
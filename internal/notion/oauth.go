@@ -0,0 +1,65 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"net/url"
+)
+
+// OAuthToken is the result of exchanging an authorization code for a public integration.
+type OAuthToken struct {
+	AccessToken   string `json:"access_token"`
+	RefreshToken  string `json:"refresh_token,omitempty"`
+	TokenType     string `json:"token_type"`
+	BotID         string `json:"bot_id"`
+	WorkspaceID   string `json:"workspace_id"`
+	WorkspaceName string `json:"workspace_name"`
+	WorkspaceIcon string `json:"workspace_icon,omitempty"`
+}
+
+// OAuthAuthorizeURL returns the page where a user grants a public integration access.
+func (c *Client) OAuthAuthorizeURL(clientID, redirectURI, state string) string {
+	target := c.baseURL.ResolveReference(&url.URL{Path: "oauth/authorize"})
+	query := url.Values{}
+	query.Set("client_id", clientID)
+	query.Set("response_type", "code")
+	query.Set("owner", "user")
+	query.Set("redirect_uri", redirectURI)
+	query.Set("state", state)
+	target.RawQuery = query.Encode()
+	return target.String()
+}
+
+// ExchangeOAuthCode trades the code delivered to redirectURI for an access token. The
+// request authenticates with the integration's client ID and secret rather than a token.
+func (c *Client) ExchangeOAuthCode(
+	ctx context.Context,
+	clientID, clientSecret, code, redirectURI string,
+) (OAuthToken, error) {
+	if clientID == "" || clientSecret == "" {
+		return OAuthToken{}, errors.New("oauth client ID and secret are required")
+	}
+	if code == "" {
+		return OAuthToken{}, errors.New("authorization code cannot be empty")
+	}
+	body := map[string]string{
+		"grant_type":   "authorization_code",
+		"code":         code,
+		"redirect_uri": redirectURI,
+	}
+	req, payload, err := c.prepareRequest(ctx, httpMethodPost, "oauth/token", body)
+	if err != nil {
+		return OAuthToken{}, err
+	}
+	req.Header.Del("Authorization")
+	req.SetBasicAuth(clientID, clientSecret)
+
+	var token OAuthToken
+	if err := c.executeWithRetries(ctx, req, payload, &token); err != nil {
+		return OAuthToken{}, err
+	}
+	if token.AccessToken == "" {
+		return OAuthToken{}, errors.New("oauth token response did not include an access token")
+	}
+	return token, nil
+}
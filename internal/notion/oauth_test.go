@@ -0,0 +1,62 @@
+package notion_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestExchangeOAuthCode(t *testing.T) {
+	client, cleanup := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth/token" || r.Method != http.MethodPost {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "client" || pass != "secret" {
+			t.Fatalf("expected basic auth with client credentials, got %q", r.Header.Get("Authorization"))
+		}
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		if body["grant_type"] != "authorization_code" || body["code"] != "abc" ||
+			body["redirect_uri"] != "http://localhost:8765/callback" {
+			t.Fatalf("unexpected body: %#v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"ntn_1","token_type":"bearer","bot_id":"b1",` +
+			`"workspace_id":"w1","workspace_name":"Acme"}`))
+	})
+	defer cleanup()
+
+	token, err := client.ExchangeOAuthCode(context.Background(), "client", "secret", "abc", "http://localhost:8765/callback")
+	if err != nil {
+		t.Fatalf("ExchangeOAuthCode returned error: %v", err)
+	}
+	if token.AccessToken != "ntn_1" || token.WorkspaceName != "Acme" || token.BotID != "b1" {
+		t.Fatalf("unexpected token: %#v", token)
+	}
+}
+
+func TestOAuthAuthorizeURL(t *testing.T) {
+	client, cleanup := newTestClient(t, func(http.ResponseWriter, *http.Request) {})
+	defer cleanup()
+
+	raw := client.OAuthAuthorizeURL("client", "http://localhost:8765/callback", "xyz")
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("parse authorize URL: %v", err)
+	}
+	if !strings.HasSuffix(parsed.Path, "/oauth/authorize") {
+		t.Fatalf("unexpected path %q", parsed.Path)
+	}
+	query := parsed.Query()
+	if query.Get("client_id") != "client" || query.Get("response_type") != "code" ||
+		query.Get("owner") != "user" || query.Get("state") != "xyz" ||
+		query.Get("redirect_uri") != "http://localhost:8765/callback" {
+		t.Fatalf("unexpected query %v", query)
+	}
+}
@@ -0,0 +1,176 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// DataSourceSchema is a data source's full schema: every property with its type
+// configuration, sorted by name.
+type DataSourceSchema struct {
+	Properties []PropertySchema `json:"properties" yaml:"properties"`
+	ID         string           `json:"id" yaml:"id"`
+	Name       string           `json:"name" yaml:"name"`
+}
+
+// PropertySchema is one property's configuration. Only the fields that apply to Type are set.
+//
+//nolint:govet // fieldalignment: struct keeps the per-type configuration grouped logically.
+type PropertySchema struct {
+	ID   string `json:"id" yaml:"id"`
+	Name string `json:"name" yaml:"name"`
+	Type string `json:"type" yaml:"type"`
+
+	Options      []SelectOption  `json:"options,omitempty" yaml:"options,omitempty"`
+	Groups       []StatusGroup   `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Relation     *RelationSchema `json:"relation,omitempty" yaml:"relation,omitempty"`
+	Rollup       *RollupSchema   `json:"rollup,omitempty" yaml:"rollup,omitempty"`
+	Formula      string          `json:"formula,omitempty" yaml:"formula,omitempty"`
+	NumberFormat string          `json:"number_format,omitempty" yaml:"number_format,omitempty"`
+	Prefix       string          `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+}
+
+// SelectOption is a select, multi-select, or status option.
+type SelectOption struct {
+	ID          string `json:"id,omitempty" yaml:"id,omitempty"`
+	Name        string `json:"name" yaml:"name"`
+	Color       string `json:"color,omitempty" yaml:"color,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// StatusGroup collects status options under To-do, In progress, or Complete.
+//
+//nolint:govet // fieldalignment: name first keeps YAML output readable.
+type StatusGroup struct {
+	ID        string   `json:"id,omitempty" yaml:"id,omitempty"`
+	Name      string   `json:"name" yaml:"name"`
+	Color     string   `json:"color,omitempty" yaml:"color,omitempty"`
+	OptionIDs []string `json:"option_ids" yaml:"option_ids"`
+}
+
+// RelationSchema is a relation's target data source and, for two-way relations, the
+// property that mirrors it there.
+type RelationSchema struct {
+	DataSourceID       string `json:"data_source_id" yaml:"data_source_id"`
+	Type               string `json:"type,omitempty" yaml:"type,omitempty"`
+	SyncedPropertyName string `json:"synced_property_name,omitempty" yaml:"synced_property_name,omitempty"`
+	SyncedPropertyID   string `json:"synced_property_id,omitempty" yaml:"synced_property_id,omitempty"`
+}
+
+// RollupSchema names the relation a rollup follows, the property it reads there, and how
+// the values are combined.
+type RollupSchema struct {
+	RelationProperty string `json:"relation_property" yaml:"relation_property"`
+	RollupProperty   string `json:"rollup_property" yaml:"rollup_property"`
+	Function         string `json:"function" yaml:"function"`
+}
+
+// rawPropertyConfig mirrors the type-keyed configuration objects in a property schema.
+type rawPropertyConfig struct {
+	Select      *rawOptions `json:"select"`
+	MultiSelect *rawOptions `json:"multi_select"`
+	Status      *rawOptions `json:"status"`
+	Relation    *struct {
+		DataSourceID string `json:"data_source_id"`
+		Type         string `json:"type"`
+		DualProperty *struct {
+			SyncedPropertyName string `json:"synced_property_name"`
+			SyncedPropertyID   string `json:"synced_property_id"`
+		} `json:"dual_property"`
+	} `json:"relation"`
+	Formula *struct {
+		Expression string `json:"expression"`
+	} `json:"formula"`
+	Rollup *struct {
+		RelationPropertyName string `json:"relation_property_name"`
+		RollupPropertyName   string `json:"rollup_property_name"`
+		Function             string `json:"function"`
+	} `json:"rollup"`
+	Number *struct {
+		Format string `json:"format"`
+	} `json:"number"`
+	UniqueID *struct {
+		Prefix *string `json:"prefix"`
+	} `json:"unique_id"`
+}
+
+type rawOptions struct {
+	Options []SelectOption `json:"options"`
+	Groups  []StatusGroup  `json:"groups"`
+}
+
+// GetDataSourceSchema fetches a data source and decodes its full property schema.
+func (c *Client) GetDataSourceSchema(ctx context.Context, dataSourceID string) (DataSourceSchema, error) {
+	ds, err := c.GetDataSource(ctx, dataSourceID)
+	if err != nil {
+		return DataSourceSchema{}, err
+	}
+	return ds.Schema()
+}
+
+// Schema decodes the full configuration of every property. Properties without their raw
+// schema, such as those read from the schema cache, keep only their ID, name, and type.
+func (ds DataSource) Schema() (DataSourceSchema, error) {
+	out := DataSourceSchema{ID: ds.ID, Name: ds.Name, Properties: make([]PropertySchema, 0, len(ds.Properties))}
+	for name, ref := range ds.Properties {
+		prop, err := ref.Schema()
+		if err != nil {
+			return DataSourceSchema{}, fmt.Errorf("property %q: %w", name, err)
+		}
+		if prop.Name == "" {
+			prop.Name = name
+		}
+		out.Properties = append(out.Properties, prop)
+	}
+	sort.Slice(out.Properties, func(i, j int) bool {
+		return out.Properties[i].Name < out.Properties[j].Name
+	})
+	return out, nil
+}
+
+// Schema decodes the property's type configuration from its raw schema object.
+func (r PropertyReference) Schema() (PropertySchema, error) {
+	prop := PropertySchema{ID: r.ID, Name: r.Name, Type: r.Type}
+	if len(r.Raw) == 0 {
+		if r.Relation != nil {
+			prop.Relation = &RelationSchema{DataSourceID: r.Relation.DataSourceID}
+		}
+		return prop, nil
+	}
+	var raw rawPropertyConfig
+	if err := json.Unmarshal(r.Raw, &raw); err != nil {
+		return PropertySchema{}, fmt.Errorf("decode property schema: %w", err)
+	}
+	for _, options := range []*rawOptions{raw.Select, raw.MultiSelect, raw.Status} {
+		if options != nil {
+			prop.Options = options.Options
+			prop.Groups = options.Groups
+		}
+	}
+	if rel := raw.Relation; rel != nil {
+		prop.Relation = &RelationSchema{DataSourceID: rel.DataSourceID, Type: rel.Type}
+		if rel.DualProperty != nil {
+			prop.Relation.SyncedPropertyName = rel.DualProperty.SyncedPropertyName
+			prop.Relation.SyncedPropertyID = rel.DualProperty.SyncedPropertyID
+		}
+	}
+	if raw.Formula != nil {
+		prop.Formula = raw.Formula.Expression
+	}
+	if rollup := raw.Rollup; rollup != nil {
+		prop.Rollup = &RollupSchema{
+			RelationProperty: rollup.RelationPropertyName,
+			RollupProperty:   rollup.RollupPropertyName,
+			Function:         rollup.Function,
+		}
+	}
+	if raw.Number != nil {
+		prop.NumberFormat = raw.Number.Format
+	}
+	if raw.UniqueID != nil && raw.UniqueID.Prefix != nil {
+		prop.Prefix = *raw.UniqueID.Prefix
+	}
+	return prop, nil
+}
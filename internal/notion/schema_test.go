@@ -0,0 +1,84 @@
+package notion_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestDataSourceSchema(t *testing.T) {
+	t.Parallel()
+
+	var ds notion.DataSource
+	if err := json.Unmarshal([]byte(`{"id":"ds-1","name":"Tasks","properties":{
+		"Name":{"id":"title","name":"Name","type":"title","title":{}},
+		"Status":{"id":"s%3D","name":"Status","type":"status","status":{
+			"options":[{"id":"o1","name":"Todo","color":"gray"},{"id":"o2","name":"Done","color":"green"}],
+			"groups":[{"id":"g1","name":"To-do","color":"gray","option_ids":["o1"]},{"id":"g2","name":"Complete","color":"green","option_ids":["o2"]}]}},
+		"Tags":{"id":"t1","name":"Tags","type":"multi_select","multi_select":{"options":[{"id":"x","name":"bug","color":"red","description":"Broken"}]}},
+		"Project":{"id":"p1","name":"Project","type":"relation","relation":{"data_source_id":"ds-2","type":"dual_property",
+			"dual_property":{"synced_property_name":"Tasks","synced_property_id":"abc"}}},
+		"Score":{"id":"f1","name":"Score","type":"formula","formula":{"expression":"prop(\"Points\") * 2"}},
+		"Points":{"id":"n1","name":"Points","type":"number","number":{"format":"dollar"}},
+		"Total":{"id":"r1","name":"Total","type":"rollup","rollup":{"relation_property_name":"Project",
+			"rollup_property_name":"Budget","function":"sum"}},
+		"Key":{"id":"u1","name":"Key","type":"unique_id","unique_id":{"prefix":"TASK"}}
+	}}`), &ds); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ds.Schema()
+	if err != nil {
+		t.Fatalf("Schema returned error: %v", err)
+	}
+	names := make([]string, 0, len(got.Properties))
+	byName := map[string]notion.PropertySchema{}
+	for _, prop := range got.Properties {
+		names = append(names, prop.Name)
+		byName[prop.Name] = prop
+	}
+	if want := []string{"Key", "Name", "Points", "Project", "Score", "Status", "Tags", "Total"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("properties = %v, want %v", names, want)
+	}
+
+	status := byName["Status"]
+	if len(status.Options) != 2 || status.Options[1].Name != "Done" || len(status.Groups) != 2 ||
+		!reflect.DeepEqual(status.Groups[1].OptionIDs, []string{"o2"}) {
+		t.Fatalf("status = %#v", status)
+	}
+	if tags := byName["Tags"]; len(tags.Options) != 1 || tags.Options[0].Description != "Broken" || tags.Groups != nil {
+		t.Fatalf("tags = %#v", tags)
+	}
+	wantRelation := &notion.RelationSchema{DataSourceID: "ds-2", Type: "dual_property", SyncedPropertyName: "Tasks", SyncedPropertyID: "abc"}
+	if project := byName["Project"]; !reflect.DeepEqual(project.Relation, wantRelation) {
+		t.Fatalf("relation = %#v", project.Relation)
+	}
+	if score := byName["Score"]; score.Formula != `prop("Points") * 2` {
+		t.Fatalf("formula = %q", score.Formula)
+	}
+	if points := byName["Points"]; points.NumberFormat != "dollar" {
+		t.Fatalf("number format = %q", points.NumberFormat)
+	}
+	wantRollup := &notion.RollupSchema{RelationProperty: "Project", RollupProperty: "Budget", Function: "sum"}
+	if total := byName["Total"]; !reflect.DeepEqual(total.Rollup, wantRollup) {
+		t.Fatalf("rollup = %#v", total.Rollup)
+	}
+	if key := byName["Key"]; key.Prefix != "TASK" {
+		t.Fatalf("prefix = %q", key.Prefix)
+	}
+}
+
+func TestPropertySchemaWithoutRaw(t *testing.T) {
+	t.Parallel()
+
+	ref := notion.PropertyReference{ID: "p1", Name: "Project", Type: "relation", Relation: &notion.RelationConfig{DataSourceID: "ds-2"}}
+	prop, err := ref.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prop.Relation == nil || prop.Relation.DataSourceID != "ds-2" || prop.Type != "relation" {
+		t.Fatalf("schema = %#v", prop)
+	}
+}
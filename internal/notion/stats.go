@@ -0,0 +1,130 @@
+package notion
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type statsKey struct{}
+
+// Stats counts the HTTP requests made by any Client using a context carrying it.
+type Stats struct {
+	notionVersion string
+	mu            sync.Mutex
+	requests      int
+	retries       int
+	rateLimited   int
+	errors        int
+	limiterWait   time.Duration
+	backoff       time.Duration
+	latency       time.Duration
+}
+
+// WithStats returns a context whose requests are counted in stats.
+func WithStats(ctx context.Context, stats *Stats) context.Context {
+	return context.WithValue(ctx, statsKey{}, stats)
+}
+
+func statsFromContext(ctx context.Context) *Stats {
+	stats, _ := ctx.Value(statsKey{}).(*Stats)
+	return stats
+}
+
+func (s *Stats) record(attempt int, notionVersion string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if attempt > 0 {
+		s.retries++
+	}
+	s.notionVersion = notionVersion
+}
+
+// response records one HTTP round trip.
+func (s *Stats) response(resp *http.Response, latency time.Duration) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency += latency
+	if resp == nil || resp.StatusCode >= http.StatusBadRequest {
+		s.errors++
+	}
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		s.rateLimited++
+	}
+}
+
+// wait records time spent in the rate limiter or sleeping before a retry.
+func (s *Stats) wait(limiter, backoff time.Duration) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limiterWait += limiter
+	s.backoff += backoff
+}
+
+// Requests returns the number of HTTP requests sent, including retries.
+func (s *Stats) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// Retries returns how many of the requests were retries.
+func (s *Stats) Retries() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.retries
+}
+
+// NotionVersion returns the Notion-Version header of the most recent request.
+func (s *Stats) NotionVersion() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.notionVersion
+}
+
+// RateLimited returns how many responses were 429 Too Many Requests.
+func (s *Stats) RateLimited() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rateLimited
+}
+
+// Errors returns how many round trips failed, either without a response or with a 4xx or
+// 5xx status, including ones that were retried.
+func (s *Stats) Errors() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.errors
+}
+
+// LimiterWait returns the time requests spent waiting for the client-side rate limiter.
+func (s *Stats) LimiterWait() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.limiterWait
+}
+
+// Backoff returns the time spent sleeping between retries, including Retry-After delays.
+func (s *Stats) Backoff() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.backoff
+}
+
+// Latency returns the summed duration of all HTTP round trips.
+func (s *Stats) Latency() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latency
+}
@@ -0,0 +1,77 @@
+package notion_test
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+func TestTransportTrustsCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, cert, 0o600); err != nil {
+		t.Fatalf("write CA bundle: %v", err)
+	}
+
+	get := func(cfg notion.TransportConfig) error {
+		t.Helper()
+		transport, err := notion.NewTransport(cfg)
+		if err != nil {
+			t.Fatalf("NewTransport returned error: %v", err)
+		}
+		client := notion.NewClient(notion.ClientConfig{BaseURL: server.URL, Transport: transport, MaxRetries: -1})
+		return client.Do(context.Background(), http.MethodGet, "users/me", nil, nil)
+	}
+	if err := get(notion.TransportConfig{}); err == nil {
+		t.Fatalf("expected an untrusted certificate to be rejected")
+	}
+	if err := get(notion.TransportConfig{CABundle: bundle}); err != nil {
+		t.Fatalf("expected the CA bundle to be trusted: %v", err)
+	}
+	if err := get(notion.TransportConfig{InsecureSkipVerify: true}); err != nil {
+		t.Fatalf("expected skip-verify to accept the certificate: %v", err)
+	}
+
+	if err := os.WriteFile(bundle, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("write CA bundle: %v", err)
+	}
+	if _, err := notion.NewTransport(notion.TransportConfig{CABundle: bundle}); err == nil {
+		t.Fatalf("expected a bundle without certificates to be rejected")
+	}
+}
+
+func TestTransportUsesProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer proxy.Close()
+
+	transport, err := notion.NewTransport(notion.TransportConfig{ProxyURL: proxy.URL})
+	if err != nil {
+		t.Fatalf("NewTransport returned error: %v", err)
+	}
+	client := notion.NewClient(notion.ClientConfig{BaseURL: "http://notion.invalid/v1", Transport: transport})
+	if err := client.Do(context.Background(), http.MethodGet, "users/me", nil, nil); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if proxied != "http://notion.invalid/v1/users/me" {
+		t.Fatalf("proxy saw %q", proxied)
+	}
+
+	if _, err := notion.NewTransport(notion.TransportConfig{ProxyURL: "ftp://proxy:21"}); err == nil {
+		t.Fatalf("expected an ftp proxy to be rejected")
+	}
+}
@@ -17,11 +17,47 @@ type DataSource struct {
 	Name        string                       `json:"name"`
 }
 
-// PropertyReference captures schema metadata for a property.
+// PropertyReference captures schema metadata for a property. Raw keeps the full schema
+// object, including type configuration such as select options, when decoded from Notion.
 type PropertyReference struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-	Type string `json:"type"`
+	Relation *RelationConfig `json:"relation,omitempty"`
+	Raw      json.RawMessage `json:"-"`
+	ID       string          `json:"id"`
+	Name     string          `json:"name"`
+	Type     string          `json:"type"`
+}
+
+// UnmarshalJSON keeps the original JSON while decoding known fields.
+func (r *PropertyReference) UnmarshalJSON(data []byte) error {
+	type alias PropertyReference
+	var tmp alias
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return fmt.Errorf("unmarshal property reference: %w", err)
+	}
+	*r = PropertyReference(tmp)
+	r.Raw = append(r.Raw[:0], data...)
+	return nil
+}
+
+// RelationConfig is the schema of a relation property: the data source its pages point to.
+type RelationConfig struct {
+	DataSourceID string `json:"data_source_id"`
+	DatabaseID   string `json:"database_id,omitempty"`
+}
+
+// CreateDataSourceRequest represents the body for POST /v1/data_sources, which adds a data
+// source to an existing database. Property values are property schema objects.
+type CreateDataSourceRequest struct {
+	Properties map[string]any `json:"properties"`
+	Parent     PageParent     `json:"parent"`
+	Title      []RichText     `json:"title,omitempty"`
+}
+
+// UpdateDataSourceRequest represents the body for PATCH /v1/data_sources/{data_source_id}.
+// Property keys are names or IDs; values are property schema objects, or nil to remove.
+type UpdateDataSourceRequest struct {
+	Properties map[string]any `json:"properties,omitempty"`
+	Title      []RichText     `json:"title,omitempty"`
 }
 
 // QueryDataSourceRequest mirrors the Notion query payload for data sources.
@@ -37,6 +73,18 @@ type QueryDataSourceRequest struct {
 	PageSize         int             `json:"page_size,omitempty"`
 }
 
+// View is a saved view of a data source with the filter and sorts it applies.
+//
+//nolint:govet // fieldalignment: keep query configuration ahead of identifying metadata.
+type View struct {
+	Filter       any    `json:"filter,omitempty"`
+	Sorts        []any  `json:"sorts,omitempty"`
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	DataSourceID string `json:"data_source_id"`
+}
+
 // QueryDataSourceResponse captures paginated query results.
 //
 //nolint:govet // fieldalignment: minimal benefit versus semantic ordering of fields.
@@ -49,9 +97,11 @@ type QueryDataSourceResponse struct {
 // Page represents a Notion page (row).
 type Page struct {
 	Properties        map[string]PropertyValue `json:"properties"`
-	ExpandedRelations map[string][]Page        `json:"-"`
+	ExpandedRelations map[string][]Page        `json:"expanded_relations,omitempty"`
 	Parent            PageParent               `json:"parent"`
 	Icon              *Icon                    `json:"icon,omitempty"`
+	CreatedBy         *UserReference           `json:"created_by,omitempty"`
+	LastEditedBy      *UserReference           `json:"last_edited_by,omitempty"`
 	CreatedTime       time.Time                `json:"created_time"`
 	LastEditedTime    time.Time                `json:"last_edited_time"`
 	ID                string                   `json:"id"`
@@ -102,6 +152,9 @@ type PropertyValue struct {
 	UniqueID       *UniqueIDValue      `json:"unique_id,omitempty"`
 	ID             string              `json:"id"`
 	Type           string              `json:"type"`
+	// HasMore is set on relation values truncated to their first 25 entries; the full list
+	// comes from RetrievePageProperty.
+	HasMore bool `json:"has_more,omitempty"`
 }
 
 // UnmarshalJSON keeps the original JSON while decoding known fields.
@@ -116,9 +169,11 @@ func (p *PropertyValue) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// RelationReference references a related page.
+// RelationReference references a related page. Page is set only when an expansion inlines
+// the related page into the reference.
 type RelationReference struct {
-	ID string `json:"id"`
+	Page *Page  `json:"page,omitempty"`
+	ID   string `json:"id"`
 }
 
 // RollupValue captures aggregated relation data.
@@ -139,6 +194,20 @@ type RichText struct {
 	Type        string       `json:"type"`
 }
 
+// MaxRichTextLength is the most characters Notion accepts in one rich text object.
+const MaxRichTextLength = 2000
+
+// ChunkText splits text into pieces of at most MaxRichTextLength characters, so longer text
+// can be sent as several rich text objects.
+func ChunkText(text string) []string {
+	runes := []rune(text)
+	chunks := make([]string, 0, len(runes)/MaxRichTextLength+1)
+	for start := 0; start < len(runes); start += MaxRichTextLength {
+		chunks = append(chunks, string(runes[start:min(start+MaxRichTextLength, len(runes))]))
+	}
+	return chunks
+}
+
 // Text contains the raw textual content.
 type Text struct {
 	Link *struct {
@@ -190,12 +259,31 @@ type FileObject struct {
 	Type string `json:"type"`
 }
 
-// UserReference references a Notion user.
+// UserReference references a Notion user. Page payloads often carry only the ID; the
+// users endpoint fills in the rest.
 type UserReference struct {
-	Object string `json:"object"`
-	ID     string `json:"id"`
-	Name   string `json:"name"`
-	Type   string `json:"type"`
+	Person    *PersonDetails `json:"person,omitempty"`
+	Object    string         `json:"object"`
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Type      string         `json:"type"`
+	AvatarURL string         `json:"avatar_url,omitempty"`
+}
+
+// BotUser is the bot user behind a token, as returned by GET /v1/users/me.
+type BotUser struct {
+	Bot struct {
+		WorkspaceID   string `json:"workspace_id,omitempty"`
+		WorkspaceName string `json:"workspace_name,omitempty"`
+	} `json:"bot"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// PersonDetails holds the details Notion returns for person users.
+type PersonDetails struct {
+	Email string `json:"email,omitempty"`
 }
 
 // FormulaValue reflects computed formula content.
@@ -223,6 +311,24 @@ type UpdatePageRequest struct {
 	Cover      *FileObject    `json:"cover,omitempty"`
 }
 
+// CreatePageRequest represents the body for POST /v1/pages.
+type CreatePageRequest struct {
+	Properties map[string]any `json:"properties,omitempty"`
+	Icon       *Icon          `json:"icon,omitempty"`
+	Parent     PageParent     `json:"parent"`
+	Children   []Block        `json:"children,omitempty"`
+}
+
+// DataSourceParent returns the parent reference for a row in the given data source.
+func DataSourceParent(dataSourceID string) PageParent {
+	return PageParent{Type: "data_source_id", DataSourceID: dataSourceID}
+}
+
+// DatabaseParent returns the parent reference for a data source in the given database.
+func DatabaseParent(databaseID string) PageParent {
+	return PageParent{Type: "database_id", DatabaseID: databaseID}
+}
+
 // AppendBlockChildrenRequest for PATCH /v1/blocks/{block_id}/children.
 type AppendBlockChildrenRequest struct {
 	Children []Block `json:"children"`
@@ -241,8 +347,132 @@ type Block struct {
 	Quote            *ParagraphBlock `json:"quote,omitempty"`
 	Callout          *CalloutBlock   `json:"callout,omitempty"`
 	Toggle           *ToggleBlock    `json:"toggle,omitempty"`
+	Divider          *struct{}       `json:"divider,omitempty"`
+	Table            *TableBlock     `json:"table,omitempty"`
+	TableRow         *TableRowBlock  `json:"table_row,omitempty"`
+	Image            *FileBlock      `json:"image,omitempty"`
+	Bookmark         *LinkBlock      `json:"bookmark,omitempty"`
+	Embed            *LinkBlock      `json:"embed,omitempty"`
+	ID               string          `json:"id,omitempty"`
 	Object           string          `json:"object,omitempty"`
 	Type             string          `json:"type"`
+	Raw              json.RawMessage `json:"-"`
+	HasChildren      bool            `json:"has_children,omitempty"`
+}
+
+// UnmarshalJSON keeps the raw block in Raw, including the types Block does not model.
+func (b *Block) UnmarshalJSON(data []byte) error {
+	type alias Block
+	var tmp alias
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return fmt.Errorf("unmarshal block: %w", err)
+	}
+	*b = Block(tmp)
+	b.Raw = append(b.Raw[:0], data...)
+	return nil
+}
+
+// Children returns the nested blocks of container block types.
+func (b *Block) Children() []Block {
+	switch {
+	case b.Paragraph != nil:
+		return b.Paragraph.Children
+	case b.BulletedListItem != nil:
+		return b.BulletedListItem.Children
+	case b.NumberedListItem != nil:
+		return b.NumberedListItem.Children
+	case b.Quote != nil:
+		return b.Quote.Children
+	case b.ToDo != nil:
+		return b.ToDo.Children
+	case b.Callout != nil:
+		return b.Callout.Children
+	case b.Toggle != nil:
+		return b.Toggle.Children
+	case b.Heading1 != nil:
+		return b.Heading1.Children
+	case b.Heading2 != nil:
+		return b.Heading2.Children
+	case b.Heading3 != nil:
+		return b.Heading3.Children
+	case b.Table != nil:
+		return b.Table.Children
+	}
+	return nil
+}
+
+// SetChildren attaches nested blocks fetched separately (the API only reports has_children).
+// It reports false for block types that cannot hold children.
+func (b *Block) SetChildren(children []Block) bool {
+	switch {
+	case b.Paragraph != nil:
+		b.Paragraph.Children = children
+	case b.BulletedListItem != nil:
+		b.BulletedListItem.Children = children
+	case b.NumberedListItem != nil:
+		b.NumberedListItem.Children = children
+	case b.Quote != nil:
+		b.Quote.Children = children
+	case b.ToDo != nil:
+		b.ToDo.Children = children
+	case b.Callout != nil:
+		b.Callout.Children = children
+	case b.Toggle != nil:
+		b.Toggle.Children = children
+	case b.Heading1 != nil:
+		b.Heading1.Children = children
+	case b.Heading2 != nil:
+		b.Heading2.Children = children
+	case b.Heading3 != nil:
+		b.Heading3.Children = children
+	case b.Table != nil:
+		b.Table.Children = children
+	default:
+		return false
+	}
+	return true
+}
+
+// WithChildren returns a copy of b holding children, leaving b unchanged. Blocks that cannot
+// hold children are returned as they are.
+func (b Block) WithChildren(children []Block) Block {
+	switch {
+	case b.Paragraph != nil:
+		v := *b.Paragraph
+		b.Paragraph = &v
+	case b.BulletedListItem != nil:
+		v := *b.BulletedListItem
+		b.BulletedListItem = &v
+	case b.NumberedListItem != nil:
+		v := *b.NumberedListItem
+		b.NumberedListItem = &v
+	case b.Quote != nil:
+		v := *b.Quote
+		b.Quote = &v
+	case b.ToDo != nil:
+		v := *b.ToDo
+		b.ToDo = &v
+	case b.Callout != nil:
+		v := *b.Callout
+		b.Callout = &v
+	case b.Toggle != nil:
+		v := *b.Toggle
+		b.Toggle = &v
+	case b.Heading1 != nil:
+		v := *b.Heading1
+		b.Heading1 = &v
+	case b.Heading2 != nil:
+		v := *b.Heading2
+		b.Heading2 = &v
+	case b.Heading3 != nil:
+		v := *b.Heading3
+		b.Heading3 = &v
+	case b.Table != nil:
+		v := *b.Table
+		b.Table = &v
+	}
+	b.SetChildren(children)
+	return b
 }
 
 // ParagraphBlock contains text content shared across multiple block types.
@@ -299,6 +529,60 @@ type ToggleBlock struct {
 	Color    string     `json:"color,omitempty"`
 }
 
+// TableBlock models a table; its rows are table_row children.
+//
+//nolint:govet // fieldalignment: keep field order aligned with the Notion payload.
+type TableBlock struct {
+	Children        []Block `json:"children,omitempty"`
+	TableWidth      int     `json:"table_width"`
+	HasColumnHeader bool    `json:"has_column_header"`
+	HasRowHeader    bool    `json:"has_row_header"`
+}
+
+// TableRowBlock holds one rich text value per table column.
+type TableRowBlock struct {
+	Cells [][]RichText `json:"cells"`
+}
+
+// FileBlock models image and other file blocks, hosted by Notion or external.
+//
+//nolint:govet // fieldalignment: keep field order aligned with the Notion payload.
+type FileBlock struct {
+	File *struct {
+		URL        string `json:"url"`
+		ExpiryTime string `json:"expiry_time,omitempty"`
+	} `json:"file,omitempty"`
+	External *struct {
+		URL string `json:"url"`
+	} `json:"external,omitempty"`
+	Caption []RichText `json:"caption,omitempty"`
+	Type    string     `json:"type"`
+}
+
+// ExternalFile returns a FileBlock pointing at url.
+func ExternalFile(url string) *FileBlock {
+	return &FileBlock{Type: "external", External: &struct {
+		URL string `json:"url"`
+	}{URL: url}}
+}
+
+// URL returns the file's location, whichever way it is hosted.
+func (f *FileBlock) URL() string {
+	switch {
+	case f.External != nil:
+		return f.External.URL
+	case f.File != nil:
+		return f.File.URL
+	}
+	return ""
+}
+
+// LinkBlock models bookmark and embed blocks.
+type LinkBlock struct {
+	URL     string     `json:"url"`
+	Caption []RichText `json:"caption,omitempty"`
+}
+
 // BlockChildrenResponse represents paginated block children.
 //
 //nolint:govet // fieldalignment: keep response metadata grouped with results.
@@ -0,0 +1,77 @@
+// Package notionid normalizes Notion identifiers supplied as raw IDs or notion.so URLs.
+package notionid
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const hexLength = 32
+
+var trailingHexID = regexp.MustCompile(`(?i)[0-9a-f]{32}$`)
+
+// Parse extracts the UUID from raw and returns it in the dashed lowercase form used in
+// Notion API responses. raw may be a dashed or un-dashed ID, a notion.so or notion.site URL,
+// or a collection:// data source URL. The ?p= peek parameter takes precedence over the
+// trailing path segment. URL fragments (block anchors) are ignored, so a link to a block
+// inside a page still names the page; use ParseBlock for the block.
+func Parse(raw string) (string, error) {
+	return parse(raw, false)
+}
+
+// ParseBlock is Parse for arguments that may name a block: a URL fragment (block anchor)
+// takes precedence over the page ID in the rest of the URL.
+func ParseBlock(raw string) (string, error) {
+	return parse(raw, true)
+}
+
+func parse(raw string, block bool) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", fmt.Errorf("empty Notion ID")
+	}
+	if id, ok := fromBare(raw); ok {
+		return id, nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		return "", fmt.Errorf("%q is not a Notion ID or URL", raw)
+	}
+	candidates := []string{u.Query().Get("p"), path.Base(u.Path), u.Host}
+	if block {
+		candidates = append([]string{u.Fragment}, candidates...)
+	}
+	for _, candidate := range candidates {
+		if id, ok := fromSegment(candidate); ok {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("no Notion ID found in %q", raw)
+}
+
+// fromBare accepts a value that is exactly one ID, with or without dashes.
+func fromBare(value string) (string, bool) {
+	compact := strings.ReplaceAll(value, "-", "")
+	if len(compact) != hexLength || !trailingHexID.MatchString(compact) {
+		return "", false
+	}
+	return format(compact), true
+}
+
+// fromSegment accepts a URL component that ends in an ID, such as "Roadmap-1a2b...".
+func fromSegment(value string) (string, bool) {
+	compact := strings.ReplaceAll(value, "-", "")
+	match := trailingHexID.FindString(compact)
+	if match == "" {
+		return "", false
+	}
+	return format(match), true
+}
+
+func format(compact string) string {
+	c := strings.ToLower(compact)
+	return c[0:8] + "-" + c[8:12] + "-" + c[12:16] + "-" + c[16:20] + "-" + c[20:32]
+}
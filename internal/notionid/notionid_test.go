@@ -0,0 +1,57 @@
+package notionid_test
+
+import (
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notionid"
+)
+
+func TestParse(t *testing.T) {
+	const want = "1a2b3c4d-5e6f-7a8b-9c0d-1e2f3a4b5c6d"
+
+	cases := map[string]string{
+		"1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d":                                                                want,
+		"1A2B3C4D-5E6F-7A8B-9C0D-1E2F3A4B5C6D":                                                            want,
+		"https://www.notion.so/acme/Roadmap-1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d":                             want,
+		"https://www.notion.so/1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d?v=ffffffffffffffff":                       want,
+		"https://www.notion.so/acme/Board-0000?p=1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d":                        want,
+		"https://acme.notion.site/Roadmap-1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d":                               want,
+		"collection://1a2b3c4d-5e6f-7a8b-9c0d-1e2f3a4b5c6d":                                               want,
+		"https://www.notion.so/Roadmap-1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d#aaaaaaaabbbbccccddddeeeeeeeeeeee": want,
+	}
+	for input, expected := range cases {
+		got, err := notionid.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", input, err)
+		}
+		if got != expected {
+			t.Fatalf("Parse(%q) = %q, want %q", input, got, expected)
+		}
+	}
+
+	for _, bad := range []string{"", "abc123", "https://www.notion.so/acme/Roadmap", "TASK-12"} {
+		if _, err := notionid.Parse(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestParseBlock(t *testing.T) {
+	const page = "1a2b3c4d-5e6f-7a8b-9c0d-1e2f3a4b5c6d"
+	const block = "aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee"
+	cases := map[string]string{
+		"https://www.notion.so/Roadmap-1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d#aaaaaaaabbbbccccddddeeeeeeeeeeee": block,
+		"https://www.notion.so/Roadmap-1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d#heading":                          page,
+		"https://www.notion.so/Roadmap-1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d":                                  page,
+		"aaaaaaaabbbbccccddddeeeeeeeeeeee":                                                                block,
+	}
+	for input, expected := range cases {
+		got, err := notionid.ParseBlock(input)
+		if err != nil {
+			t.Fatalf("ParseBlock(%q) returned error: %v", input, err)
+		}
+		if got != expected {
+			t.Fatalf("ParseBlock(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Package props converts plain-text values into typed Notion property payloads.
+package props
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+const listSeparator = ","
+
+// Coerce builds the property update payload for ref from a plain-text value, using the
+// property's schema type to pick the right shape. An empty value clears the property.
+func Coerce(ref notion.PropertyReference, raw string) (map[string]any, error) {
+	raw = strings.TrimSpace(raw)
+	build, ok := buildersByType[ref.Type]
+	if !ok {
+		return nil, fmt.Errorf("property %q has type %s, which cannot be set from text", ref.Name, ref.Type)
+	}
+	value, err := build(raw)
+	if err != nil {
+		return nil, fmt.Errorf("property %q: %w", ref.Name, err)
+	}
+	return map[string]any{ref.Type: value}, nil
+}
+
+type valueBuilder func(raw string) (any, error)
+
+var buildersByType = map[string]valueBuilder{
+	"title":        richTextValue,
+	"rich_text":    richTextValue,
+	"number":       numberValue,
+	"checkbox":     checkboxValue,
+	"select":       namedOptionValue,
+	"status":       namedOptionValue,
+	"multi_select": multiSelectValue,
+	"date":         dateValue,
+	"url":          nullableString,
+	"email":        nullableString,
+	"phone_number": nullableString,
+	"relation":     relationValue,
+	"people":       peopleValue,
+}
+
+// richTextValue splits text longer than Notion's per-object limit across several text
+// objects.
+func richTextValue(raw string) (any, error) {
+	chunks := notion.ChunkText(raw)
+	out := make([]any, 0, len(chunks))
+	for _, chunk := range chunks {
+		out = append(out, map[string]any{
+			"type": "text",
+			"text": map[string]any{"content": chunk},
+		})
+	}
+	return out, nil
+}
+
+func numberValue(raw string) (any, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("expected a number, got %q", raw)
+	}
+	return n, nil
+}
+
+func checkboxValue(raw string) (any, error) {
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, fmt.Errorf("expected true or false, got %q", raw)
+	}
+	return b, nil
+}
+
+func namedOptionValue(raw string) (any, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	return map[string]any{"name": raw}, nil
+}
+
+func multiSelectValue(raw string) (any, error) {
+	items := splitList(raw)
+	out := make([]any, 0, len(items))
+	for _, item := range items {
+		out = append(out, map[string]any{"name": item})
+	}
+	return out, nil
+}
+
+func dateValue(raw string) (any, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	return map[string]any{"start": raw}, nil
+}
+
+func nullableString(raw string) (any, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	return raw, nil
+}
+
+func relationValue(raw string) (any, error) {
+	items := splitList(raw)
+	out := make([]any, 0, len(items))
+	for _, id := range items {
+		out = append(out, map[string]any{"id": id})
+	}
+	return out, nil
+}
+
+func peopleValue(raw string) (any, error) {
+	items := splitList(raw)
+	out := make([]any, 0, len(items))
+	for _, id := range items {
+		out = append(out, map[string]any{"object": "user", "id": id})
+	}
+	return out, nil
+}
+
+func splitList(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	parts := strings.Split(raw, listSeparator)
+	out := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			out = append(out, trimmed)
+		}
+	}
+	return out
+}
+
+// FromJSON converts a decoded JSON value into a property payload. Objects are assumed to
+// already be Notion property payloads and pass through unchanged; scalars and arrays are
+// rendered as text and coerced with Coerce.
+func FromJSON(ref notion.PropertyReference, value any) (map[string]any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		return v, nil
+	case nil:
+		return Coerce(ref, "")
+	case string:
+		return Coerce(ref, v)
+	case float64:
+		return Coerce(ref, strconv.FormatFloat(v, 'f', -1, 64))
+	case bool:
+		return Coerce(ref, strconv.FormatBool(v))
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			text, ok := scalarText(item)
+			if !ok {
+				return nil, fmt.Errorf("property %q: list items must be strings, numbers, or booleans", ref.Name)
+			}
+			items = append(items, text)
+		}
+		return Coerce(ref, strings.Join(items, listSeparator))
+	default:
+		return nil, fmt.Errorf("property %q: unsupported value of type %T", ref.Name, value)
+	}
+}
+
+func scalarText(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
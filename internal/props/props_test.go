@@ -0,0 +1,71 @@
+package props_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/props"
+)
+
+func TestCoerce(t *testing.T) {
+	cases := []struct {
+		ref  notion.PropertyReference
+		raw  string
+		want string
+	}{
+		{notion.PropertyReference{Name: "Team", Type: "select"}, "Infra", `{"select":{"name":"Infra"}}`},
+		{notion.PropertyReference{Name: "Team", Type: "select"}, "", `{"select":null}`},
+		{notion.PropertyReference{Name: "Points", Type: "number"}, "3.5", `{"number":3.5}`},
+		{notion.PropertyReference{Name: "Tags", Type: "multi_select"}, "a, b", `{"multi_select":[{"name":"a"},{"name":"b"}]}`},
+		{notion.PropertyReference{Name: "Done", Type: "checkbox"}, "true", `{"checkbox":true}`},
+		{
+			notion.PropertyReference{Name: "Name", Type: "title"},
+			"Hello",
+			`{"title":[{"text":{"content":"Hello"},"type":"text"}]}`,
+		},
+		{notion.PropertyReference{Name: "Epic", Type: "relation"}, "p1,p2", `{"relation":[{"id":"p1"},{"id":"p2"}]}`},
+	}
+
+	for _, tc := range cases {
+		got, err := props.Coerce(tc.ref, tc.raw)
+		if err != nil {
+			t.Fatalf("Coerce(%s, %q) returned error: %v", tc.ref.Type, tc.raw, err)
+		}
+		encoded, err := json.Marshal(got)
+		if err != nil {
+			t.Fatalf("marshal payload: %v", err)
+		}
+		if string(encoded) != tc.want {
+			t.Fatalf("Coerce(%s, %q) = %s, want %s", tc.ref.Type, tc.raw, encoded, tc.want)
+		}
+	}
+}
+
+func TestCoerceChunksLongText(t *testing.T) {
+	got, err := props.Coerce(notion.PropertyReference{Name: "Body", Type: "rich_text"}, strings.Repeat("é", 4500))
+	if err != nil {
+		t.Fatalf("Coerce returned error: %v", err)
+	}
+	parts := got["rich_text"].([]any)
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 text objects, got %d", len(parts))
+	}
+	for i, want := range []int{2000, 2000, 500} {
+		content := parts[i].(map[string]any)["text"].(map[string]any)["content"].(string)
+		if n := utf8.RuneCountInString(content); n != want {
+			t.Fatalf("text object %d has %d characters, want %d", i, n, want)
+		}
+	}
+}
+
+func TestCoerceErrors(t *testing.T) {
+	if _, err := props.Coerce(notion.PropertyReference{Name: "Points", Type: "number"}, "many"); err == nil {
+		t.Fatalf("expected number parse error")
+	}
+	if _, err := props.Coerce(notion.PropertyReference{Name: "Total", Type: "formula"}, "1"); err == nil {
+		t.Fatalf("expected unsupported type error")
+	}
+}
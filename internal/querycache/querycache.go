@@ -0,0 +1,167 @@
+// Package querycache keeps data source query results in memory for a short time, so
+// long-running servers can answer repeated queries without calling the Notion API.
+package querycache
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+// Cache maps a data source and query request to the response Notion returned. Entries
+// expire after the TTL and are dropped early by Invalidate. It is safe for concurrent use.
+type Cache struct {
+	now       func() time.Time
+	entries   map[string]map[string]entry
+	lastSweep time.Time
+	ttl       time.Duration
+	mu        sync.Mutex
+	stats     Stats
+}
+
+// Stats counts cache lookups since the cache was created.
+type Stats struct {
+	Hits          int `json:"hits"`
+	Misses        int `json:"misses"`
+	Invalidations int `json:"invalidations"`
+	Entries       int `json:"entries"`
+}
+
+type entry struct {
+	stored  time.Time
+	expires time.Time
+	resp    notion.QueryDataSourceResponse
+}
+
+// New returns a cache whose entries live for ttl.
+func New(ttl time.Duration) *Cache {
+	return &Cache{now: time.Now, ttl: ttl, entries: map[string]map[string]entry{}}
+}
+
+// SetClock replaces the time source, for tests.
+func (c *Cache) SetClock(now func() time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Key normalizes req so equivalent requests share an entry: JSON encoding sorts map keys
+// and drops empty fields.
+func Key(req notion.QueryDataSourceRequest) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("encode query: %w", err)
+	}
+	return string(data), nil
+}
+
+// Get returns the cached response for req against dataSourceID, if it has not expired.
+func (c *Cache) Get(dataSourceID string, req notion.QueryDataSourceRequest) (notion.QueryDataSourceResponse, bool) {
+	key, err := Key(req)
+	if err != nil {
+		return notion.QueryDataSourceResponse{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[dataSourceID][key]
+	if !ok || !c.now().Before(e.expires) {
+		if ok {
+			delete(c.entries[dataSourceID], key)
+		}
+		c.stats.Misses++
+		return notion.QueryDataSourceResponse{}, false
+	}
+	c.stats.Hits++
+	return e.resp, true
+}
+
+// Put stores resp as the result of req against dataSourceID.
+func (c *Cache) Put(dataSourceID string, req notion.QueryDataSourceRequest, resp notion.QueryDataSourceResponse) {
+	key, err := Key(req)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if now.Sub(c.lastSweep) >= c.ttl {
+		c.sweep(now)
+	}
+	if c.entries[dataSourceID] == nil {
+		c.entries[dataSourceID] = map[string]entry{}
+	}
+	c.entries[dataSourceID][key] = entry{stored: now, expires: now.Add(c.ttl), resp: resp}
+}
+
+// sweep drops expired entries, which Get only removes when the same query comes back. Put
+// runs it at most once per TTL so a long-running server does not grow without bound.
+func (c *Cache) sweep(now time.Time) {
+	c.lastSweep = now
+	for id, queries := range c.entries {
+		for key, e := range queries {
+			if !now.Before(e.expires) {
+				delete(queries, key)
+			}
+		}
+		if len(queries) == 0 {
+			delete(c.entries, id)
+		}
+	}
+}
+
+// Invalidate drops every cached query of dataSourceID and reports how many were dropped.
+func (c *Cache) Invalidate(dataSourceID string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := len(c.entries[dataSourceID])
+	delete(c.entries, dataSourceID)
+	if n > 0 {
+		c.stats.Invalidations++
+	}
+	return n
+}
+
+// InvalidateBefore drops the cached queries of dataSourceID stored before t, keeping the
+// ones that already reflect a change made at t, and reports how many were dropped.
+func (c *Cache) InvalidateBefore(dataSourceID string, t time.Time) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for key, e := range c.entries[dataSourceID] {
+		if e.stored.Before(t) {
+			delete(c.entries[dataSourceID], key)
+			n++
+		}
+	}
+	if n > 0 {
+		c.stats.Invalidations++
+	}
+	return n
+}
+
+// DataSources lists the data sources that currently have cached queries.
+func (c *Cache) DataSources() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ids := make([]string, 0, len(c.entries))
+	for id, queries := range c.entries {
+		if len(queries) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Stats returns the lookup counters and the number of cached entries.
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	for _, queries := range c.entries {
+		stats.Entries += len(queries)
+	}
+	return stats
+}
@@ -0,0 +1,100 @@
+package querycache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/querycache"
+)
+
+func TestCacheExpiresAndInvalidates(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	cache := querycache.New(time.Minute)
+	cache.SetClock(func() time.Time { return now })
+
+	req := notion.QueryDataSourceRequest{
+		Filter:   map[string]any{"property": "Status", "status": map[string]any{"equals": "Done"}},
+		PageSize: 10,
+	}
+	resp := notion.QueryDataSourceResponse{Results: []notion.Page{{ID: "p1"}}}
+	cache.Put("ds-1", req, resp)
+
+	same := notion.QueryDataSourceRequest{
+		Filter:   map[string]any{"status": map[string]any{"equals": "Done"}, "property": "Status"},
+		PageSize: 10,
+	}
+	if got, ok := cache.Get("ds-1", same); !ok || len(got.Results) != 1 {
+		t.Fatalf("expected a hit for an equivalent request, got %v %v", got, ok)
+	}
+	if _, ok := cache.Get("ds-1", notion.QueryDataSourceRequest{PageSize: 10}); ok {
+		t.Fatalf("expected a miss for a different request")
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := cache.Get("ds-1", req); ok {
+		t.Fatalf("expected the entry to expire after the TTL")
+	}
+
+	cache.Put("ds-1", req, resp)
+	cache.Put("ds-2", req, resp)
+	if n := cache.Invalidate("ds-1"); n != 1 {
+		t.Fatalf("Invalidate dropped %d entries, want 1", n)
+	}
+	if _, ok := cache.Get("ds-1", req); ok {
+		t.Fatalf("expected a miss after invalidation")
+	}
+	if _, ok := cache.Get("ds-2", req); !ok {
+		t.Fatalf("expected other data sources to stay cached")
+	}
+
+	stats := cache.Stats()
+	want := querycache.Stats{Hits: 2, Misses: 3, Invalidations: 1, Entries: 1}
+	if stats != want {
+		t.Fatalf("Stats = %+v, want %+v", stats, want)
+	}
+}
+
+func TestCacheSweepsExpiredEntries(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	cache := querycache.New(time.Minute)
+	cache.SetClock(func() time.Time { return now })
+
+	for i := range 3 {
+		cache.Put("ds-1", notion.QueryDataSourceRequest{PageSize: i + 1}, notion.QueryDataSourceResponse{})
+	}
+	now = now.Add(2 * time.Minute)
+	cache.Put("ds-2", notion.QueryDataSourceRequest{}, notion.QueryDataSourceResponse{})
+
+	if stats := cache.Stats(); stats.Entries != 1 {
+		t.Fatalf("Entries = %d after expiry, want only the new one", stats.Entries)
+	}
+	if ids := cache.DataSources(); len(ids) != 1 || ids[0] != "ds-2" {
+		t.Fatalf("DataSources = %v, want [ds-2]", ids)
+	}
+}
+
+func TestCacheInvalidateBeforeKeepsNewerEntries(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	cache := querycache.New(time.Hour)
+	cache.SetClock(func() time.Time { return now })
+
+	older := notion.QueryDataSourceRequest{PageSize: 1}
+	newer := notion.QueryDataSourceRequest{PageSize: 2}
+	cache.Put("ds-1", older, notion.QueryDataSourceResponse{})
+	now = now.Add(2 * time.Minute)
+	cache.Put("ds-1", newer, notion.QueryDataSourceResponse{})
+
+	if n := cache.InvalidateBefore("ds-1", now.Add(-time.Minute)); n != 1 {
+		t.Fatalf("InvalidateBefore dropped %d entries, want 1", n)
+	}
+	if _, ok := cache.Get("ds-1", older); ok {
+		t.Fatalf("expected the entry stored before the cutoff to be dropped")
+	}
+	if _, ok := cache.Get("ds-1", newer); !ok {
+		t.Fatalf("expected the entry stored after the cutoff to stay")
+	}
+	if n := cache.InvalidateBefore("ds-1", now.Add(-time.Minute)); n != 0 {
+		t.Fatalf("a repeated InvalidateBefore dropped %d entries, want 0", n)
+	}
+}
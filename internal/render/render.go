@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"strings"
 	"text/tabwriter"
 )
@@ -26,6 +28,12 @@ func JSON(w io.Writer, v any) error {
 	return nil
 }
 
+// SortedKeys returns the keys of m in ascending order. Outputs built from maps iterate these
+// keys so consecutive runs over the same data render identically.
+func SortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
 // Table renders the provided headers and rows via a tabwriter.
 func Table(w io.Writer, headers []string, rows [][]string) error {
 	tw := tabwriter.NewWriter(w, tabWriterMinWidth, tabWriterTabWidth, tabWriterPadding, ' ', tabWriterFlags)
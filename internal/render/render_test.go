@@ -0,0 +1,29 @@
+package render_test
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/render"
+)
+
+func TestSortedKeys(t *testing.T) {
+	m := map[string]int{"b": 2, "a": 1, "C": 3, "c": 4}
+	for range 20 {
+		if got := render.SortedKeys(m); !slices.Equal(got, []string{"C", "a", "b", "c"}) {
+			t.Fatalf("unexpected order %v", got)
+		}
+	}
+}
+
+func TestJSONSortsMapKeys(t *testing.T) {
+	var out bytes.Buffer
+	if err := render.JSON(&out, map[string]any{"z": 1, "a": map[string]int{"y": 2, "b": 3}}); err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+	want := "{\n  \"a\": {\n    \"b\": 3,\n    \"y\": 2\n  },\n  \"z\": 1\n}\n"
+	if out.String() != want {
+		t.Fatalf("expected %q, got %q", want, out.String())
+	}
+}
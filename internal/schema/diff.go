@@ -0,0 +1,134 @@
+package schema
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+// Kinds of schema change reported by Diff.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeRenamed = "renamed"
+	ChangeChanged = "changed"
+)
+
+// Change is one difference between two schemas. Field says what changed for ChangeChanged:
+// type, options, formula, relation, rollup, number_format, or prefix. For options, Old lists
+// the options only the old schema has and New those only the new one has.
+type Change struct {
+	Kind     string `json:"change"`
+	Property string `json:"property"`
+	Field    string `json:"field,omitempty"`
+	Old      string `json:"old,omitempty"`
+	New      string `json:"new,omitempty"`
+}
+
+// Diff reports how current differs from old. Within one data source, properties are paired
+// by ID first, so a rename shows up as one, and then by name. Property IDs are only unique
+// within a data source, so across data sources properties are paired by name alone.
+func Diff(old, current notion.DataSourceSchema) []Change {
+	var changes []Change
+	paired := make(map[int]bool, len(old.Properties))
+	matchers := []func(o, prop notion.PropertySchema) bool{
+		func(o, prop notion.PropertySchema) bool { return o.Name == prop.Name },
+	}
+	if old.ID != "" && old.ID == current.ID {
+		matchers = slices.Insert(matchers, 0, func(o, prop notion.PropertySchema) bool { return o.ID != "" && o.ID == prop.ID })
+	}
+	match := func(prop notion.PropertySchema) (notion.PropertySchema, bool) {
+		for _, same := range matchers {
+			for i, candidate := range old.Properties {
+				if !paired[i] && same(candidate, prop) {
+					paired[i] = true
+					return candidate, true
+				}
+			}
+		}
+		return notion.PropertySchema{}, false
+	}
+
+	for _, prop := range current.Properties {
+		before, ok := match(prop)
+		if !ok {
+			changes = append(changes, Change{Kind: ChangeAdded, Property: prop.Name, New: prop.Type})
+			continue
+		}
+		if before.Name != prop.Name {
+			changes = append(changes, Change{Kind: ChangeRenamed, Property: prop.Name, Old: before.Name, New: prop.Name})
+		}
+		changes = append(changes, diffProperty(before, prop)...)
+	}
+	for i, prop := range old.Properties {
+		if !paired[i] {
+			changes = append(changes, Change{Kind: ChangeRemoved, Property: prop.Name, Old: prop.Type})
+		}
+	}
+	slices.SortStableFunc(changes, func(a, b Change) int { return cmp.Compare(a.Property, b.Property) })
+	return changes
+}
+
+func diffProperty(old, current notion.PropertySchema) []Change {
+	changed := func(field, before, after string) Change {
+		return Change{Kind: ChangeChanged, Property: current.Name, Field: field, Old: before, New: after}
+	}
+	if old.Type != current.Type {
+		// The rest of the configuration belongs to the old type, so comparing it says nothing.
+		return []Change{changed("type", old.Type, current.Type)}
+	}
+
+	var changes []Change
+	if removed, added := optionDelta(old.Options, current.Options); removed != "" || added != "" {
+		changes = append(changes, changed("options", removed, added))
+	}
+	for _, field := range []struct {
+		name          string
+		before, after string
+	}{
+		{"formula", old.Formula, current.Formula},
+		{"relation", relationText(old.Relation), relationText(current.Relation)},
+		{"rollup", rollupText(old.Rollup), rollupText(current.Rollup)},
+		{"number_format", old.NumberFormat, current.NumberFormat},
+		{"prefix", old.Prefix, current.Prefix},
+	} {
+		if field.before != field.after {
+			changes = append(changes, changed(field.name, field.before, field.after))
+		}
+	}
+	return changes
+}
+
+// optionDelta lists, by name, the options only old has and those only current has.
+func optionDelta(old, current []notion.SelectOption) (string, string) {
+	only := func(options, other []notion.SelectOption) string {
+		var names []string
+		for _, option := range options {
+			if !slices.ContainsFunc(other, func(o notion.SelectOption) bool { return o.Name == option.Name }) {
+				names = append(names, option.Name)
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+	return only(old, current), only(current, old)
+}
+
+func relationText(rel *notion.RelationSchema) string {
+	if rel == nil {
+		return ""
+	}
+	if rel.SyncedPropertyName != "" {
+		return fmt.Sprintf("%s (synced with %s)", rel.DataSourceID, rel.SyncedPropertyName)
+	}
+	return rel.DataSourceID
+}
+
+func rollupText(rollup *notion.RollupSchema) string {
+	if rollup == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s of %s via %s", rollup.Function, rollup.RollupProperty, rollup.RelationProperty)
+}
@@ -0,0 +1,70 @@
+package schema_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	old := notion.DataSourceSchema{ID: "ds-1", Properties: []notion.PropertySchema{
+		{ID: "title", Name: "Name", Type: "title"},
+		{ID: "a", Name: "Due", Type: "date"},
+		{ID: "b", Name: "Status", Type: "select", Options: []notion.SelectOption{{Name: "Todo"}, {Name: "Blocked"}}},
+		{ID: "c", Name: "Points", Type: "number", NumberFormat: "number"},
+		{ID: "d", Name: "Notes", Type: "rich_text"},
+		{ID: "e", Name: "Estimate", Type: "number"},
+	}}
+	current := notion.DataSourceSchema{ID: "ds-1", Properties: []notion.PropertySchema{
+		{ID: "title", Name: "Task", Type: "title"},
+		{ID: "a", Name: "Due", Type: "date"},
+		{ID: "b", Name: "Status", Type: "select", Options: []notion.SelectOption{{Name: "Todo"}, {Name: "Review"}}},
+		{ID: "c", Name: "Points", Type: "number", NumberFormat: "dollar"},
+		{ID: "x", Name: "Owner", Type: "people"},
+		// Created separately, so only the name pairs it with the old property.
+		{ID: "y", Name: "Estimate", Type: "rich_text"},
+	}}
+
+	want := []schema.Change{
+		{Kind: schema.ChangeChanged, Property: "Estimate", Field: "type", Old: "number", New: "rich_text"},
+		{Kind: schema.ChangeRemoved, Property: "Notes", Old: "rich_text"},
+		{Kind: schema.ChangeAdded, Property: "Owner", New: "people"},
+		{Kind: schema.ChangeChanged, Property: "Points", Field: "number_format", Old: "number", New: "dollar"},
+		{Kind: schema.ChangeChanged, Property: "Status", Field: "options", Old: "Blocked", New: "Review"},
+		{Kind: schema.ChangeRenamed, Property: "Task", Old: "Name", New: "Task"},
+	}
+	if got := schema.Diff(old, current); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Diff() =\n%+v\nwant\n%+v", got, want)
+	}
+	if got := schema.Diff(old, old); len(got) != 0 {
+		t.Fatalf("Diff of identical schemas = %+v", got)
+	}
+}
+
+func TestDiffAcrossDataSourcesPairsByName(t *testing.T) {
+	t.Parallel()
+
+	// Property IDs are short and only unique within a data source, so they collide.
+	staging := notion.DataSourceSchema{ID: "ds-staging", Properties: []notion.PropertySchema{
+		{ID: "title", Name: "Name", Type: "title"},
+		{ID: "a", Name: "Due", Type: "date"},
+	}}
+	prod := notion.DataSourceSchema{ID: "ds-prod", Properties: []notion.PropertySchema{
+		{ID: "title", Name: "Task", Type: "title"},
+		{ID: "a", Name: "Priority", Type: "select"},
+	}}
+
+	want := []schema.Change{
+		{Kind: schema.ChangeRemoved, Property: "Due", Old: "date"},
+		{Kind: schema.ChangeRemoved, Property: "Name", Old: "title"},
+		{Kind: schema.ChangeAdded, Property: "Priority", New: "select"},
+		{Kind: schema.ChangeAdded, Property: "Task", New: "title"},
+	}
+	if got := schema.Diff(staging, prod); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Diff() =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -2,14 +2,20 @@
 package schema
 
 import (
+	"net/url"
 	"sort"
 	"strings"
 
 	"github.com/yourorg/notionctl/internal/notion"
 )
 
+// PropertyIDPrefix marks a property reference as a raw property ID rather than a name, for
+// scripts that must keep working after properties are renamed.
+const PropertyIDPrefix = "property_id:"
+
 // Index accelerates lookups between property names and IDs.
 type Index struct {
+	exact  map[string]notion.PropertyReference
 	byName map[string]notion.PropertyReference
 	byID   map[string]notion.PropertyReference
 	order  []string
@@ -20,29 +26,39 @@ func NewIndex(ds notion.DataSource) *Index {
 	byName := make(map[string]notion.PropertyReference, len(ds.Properties))
 	byID := make(map[string]notion.PropertyReference, len(ds.Properties))
 	names := make([]string, 0, len(ds.Properties))
-
-	for name, ref := range ds.Properties {
-		byID[ref.ID] = ref
-		key := normalize(name)
-		byName[key] = ref
+	for name := range ds.Properties {
 		names = append(names, name)
 	}
-
 	sort.Strings(names)
 
+	// Walk names in order so that names differing only in case resolve the same way on
+	// every run: the first in sorted order owns the case-insensitive key.
+	for _, name := range names {
+		ref := ds.Properties[name]
+		byID[ref.ID] = ref
+		// The API returns some IDs URL-encoded (e.g. "abc%3D"); accept either spelling.
+		if decoded, err := url.PathUnescape(ref.ID); err == nil {
+			if _, taken := byID[decoded]; !taken {
+				byID[decoded] = ref
+			}
+		}
+		if _, taken := byName[normalize(name)]; !taken {
+			byName[normalize(name)] = ref
+		}
+	}
+
 	return &Index{
+		exact:  ds.Properties,
 		byName: byName,
 		byID:   byID,
 		order:  names,
 	}
 }
 
-// IDForName resolves a property name (case-insensitive) to its property ID.
+// IDForName resolves a property reference to its property ID. See ReferenceForName for the
+// accepted forms.
 func (i *Index) IDForName(name string) (string, bool) {
-	if i == nil {
-		return "", false
-	}
-	ref, ok := i.byName[normalize(name)]
+	ref, ok := i.ReferenceForName(name)
 	if !ok {
 		return "", false
 	}
@@ -61,15 +77,33 @@ func (i *Index) NameForID(id string) (string, bool) {
 	return ref.Name, true
 }
 
-// ReferenceForName returns the full property reference.
+// ReferenceForName returns the full property reference for a name (case-insensitive, exact
+// case first), a raw property ID, or an ID with the property_id: prefix. Names win over IDs
+// when both match.
 func (i *Index) ReferenceForName(name string) (notion.PropertyReference, bool) {
 	if i == nil {
 		return notion.PropertyReference{}, false
 	}
-	ref, ok := i.byName[normalize(name)]
+	if id, ok := ParsePropertyID(name); ok {
+		ref, found := i.byID[id]
+		return ref, found
+	}
+	if ref, ok := i.exact[strings.TrimSpace(name)]; ok {
+		return ref, true
+	}
+	if ref, ok := i.byName[normalize(name)]; ok {
+		return ref, true
+	}
+	ref, ok := i.byID[strings.TrimSpace(name)]
 	return ref, ok
 }
 
+// ParsePropertyID strips the property_id: prefix, reporting whether it was present.
+func ParsePropertyID(ref string) (string, bool) {
+	id, ok := strings.CutPrefix(strings.TrimSpace(ref), PropertyIDPrefix)
+	return strings.TrimSpace(id), ok
+}
+
 // ReferenceForID returns the full property reference.
 func (i *Index) ReferenceForID(id string) (notion.PropertyReference, bool) {
 	if i == nil {
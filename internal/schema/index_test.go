@@ -35,3 +35,46 @@ func TestIndexLookups(t *testing.T) {
 		t.Fatalf("unexpected property names: %#v", names)
 	}
 }
+
+func TestIndexAcceptsPropertyIDs(t *testing.T) {
+	idx := schema.NewIndex(notion.DataSource{
+		Properties: map[string]notion.PropertyReference{
+			"Due":  {ID: "a%3Dbc", Name: "Due", Type: "date"},
+			"Name": {ID: "title", Name: "Name", Type: "title"},
+		},
+	})
+
+	for _, ref := range []string{"Due", "a%3Dbc", "a=bc", "property_id:a%3Dbc", " property_id: a=bc"} {
+		if got, ok := idx.ReferenceForName(ref); !ok || got.Name != "Due" {
+			t.Fatalf("ReferenceForName(%q) = %+v,%v", ref, got, ok)
+		}
+	}
+	if id, ok := idx.IDForName("property_id:title"); !ok || id != "title" {
+		t.Fatalf("IDForName(property_id:title) = %q,%v", id, ok)
+	}
+	if _, ok := idx.ReferenceForName("property_id:Due"); ok {
+		t.Fatalf("property_id: prefix must not fall back to names")
+	}
+}
+
+func TestIndexNamesDifferingOnlyInCase(t *testing.T) {
+	ds := notion.DataSource{
+		Properties: map[string]notion.PropertyReference{
+			"status": {ID: "lower-id", Name: "status", Type: "rich_text"},
+			"Status": {ID: "upper-id", Name: "Status", Type: "status"},
+		},
+	}
+
+	for range 20 {
+		idx := schema.NewIndex(ds)
+		if id, _ := idx.IDForName("status"); id != "lower-id" {
+			t.Fatalf("exact name should win, got %q", id)
+		}
+		if id, _ := idx.IDForName("Status"); id != "upper-id" {
+			t.Fatalf("exact name should win, got %q", id)
+		}
+		if id, _ := idx.IDForName("STATUS"); id != "upper-id" {
+			t.Fatalf("first name in sorted order should own the case-insensitive key, got %q", id)
+		}
+	}
+}
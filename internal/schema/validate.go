@@ -0,0 +1,259 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+// computedTypes are filled in by Notion and cannot be written.
+var computedTypes = map[string]bool{
+	"formula": true, "rollup": true, "unique_id": true, "button": true, "verification": true,
+	"created_time": true, "created_by": true, "last_edited_time": true, "last_edited_by": true,
+}
+
+// valueKinds describes the JSON value each writable type expects, and an example.
+var valueKinds = map[string]struct {
+	kind    string
+	example string
+}{
+	"title":        {"array", `[{"text": {"content": "..."}}]`},
+	"rich_text":    {"array", `[{"text": {"content": "..."}}]`},
+	"number":       {"number", `3`},
+	"checkbox":     {"boolean", `true`},
+	"select":       {"object", `{"name": "..."}`},
+	"status":       {"object", `{"name": "..."}`},
+	"multi_select": {"array", `[{"name": "..."}]`},
+	"date":         {"object", `{"start": "2025-01-31"}`},
+	"people":       {"array", `[{"id": "..."}]`},
+	"relation":     {"array", `[{"id": "..."}]`},
+	"files":        {"array", `[{"name": "...", "external": {"url": "..."}}]`},
+	"url":          {"string", `"https://..."`},
+	"email":        {"string", `"..."`},
+	"phone_number": {"string", `"..."`},
+}
+
+// Validate checks a properties payload, as sent to create or update a page, against the
+// data source schema. It reports every unknown property, value of the wrong shape, and
+// status option the property lacks, so a bad payload fails with all its problems at once
+// instead of Notion's first 400. Unknown select and multi-select options are not errors:
+// Notion creates them on write, and NewOptions lists them.
+func Validate(ds notion.DataSource, properties map[string]any) error {
+	idx := NewIndex(ds)
+	var problems []error
+	for _, key := range sortedKeys(properties) {
+		ref, ok := idx.ReferenceForName(key)
+		if !ok {
+			problem := fmt.Sprintf("unknown property %q", key)
+			if suggestion := closestName(key, idx.PropertyNames()); suggestion != "" {
+				problem += fmt.Sprintf(" (did you mean %q?)", suggestion)
+			}
+			problems = append(problems, errors.New(problem))
+			continue
+		}
+		prop, err := ref.Schema()
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		// Without the raw schema (a cached one) the options are unknown, so only shapes are checked.
+		if err := validateValue(prop, properties[key], len(ref.Raw) > 0); err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", prop.Name, err))
+		}
+	}
+	return errors.Join(problems...)
+}
+
+func validateValue(prop notion.PropertySchema, raw any, checkOptions bool) error {
+	if computedTypes[prop.Type] {
+		return fmt.Errorf("%s properties are computed by Notion and cannot be set", prop.Type)
+	}
+	expected, known := valueKinds[prop.Type]
+	if !known {
+		return nil
+	}
+	object, ok := raw.(map[string]any)
+	if !ok {
+		return fmt.Errorf("expected an object such as {%q: %s}, got %s", prop.Type, expected.example, jsonKind(raw))
+	}
+	value, ok := object[prop.Type]
+	if !ok {
+		for key := range object {
+			if _, isType := valueKinds[key]; isType {
+				return fmt.Errorf("this is a %s property, but the value is given as %s; use {%q: %s}",
+					prop.Type, key, prop.Type, expected.example)
+			}
+		}
+		return fmt.Errorf("missing %q in the value; expected {%q: %s}", prop.Type, prop.Type, expected.example)
+	}
+	if value == nil {
+		// null clears the value.
+		return nil
+	}
+	if kind := jsonKind(value); kind != expected.kind {
+		return fmt.Errorf("%s properties take %s such as %s, got %s", prop.Type, withArticle(expected.kind), expected.example, kind)
+	}
+	if !checkOptions {
+		return nil
+	}
+	return validateOptions(prop, value)
+}
+
+// validateOptions checks status option names against the schema. Status options cannot be
+// created through the API; select and multi-select options can, so those are not checked.
+// Options given by ID are left to Notion.
+func validateOptions(prop notion.PropertySchema, value any) error {
+	if prop.Type != "status" {
+		return nil
+	}
+	missing := missingOptions(prop, value)
+	if len(missing) == 0 {
+		return nil
+	}
+	available := make([]string, 0, len(prop.Options))
+	for _, option := range prop.Options {
+		available = append(available, option.Name)
+	}
+	return fmt.Errorf("no option %s (options: %s)", strings.Join(missing, ", "), strings.Join(available, ", "))
+}
+
+// NewOptions lists the select and multi-select options in properties that the schema does
+// not have yet, which Notion creates when the payload is sent. It reports nothing for a
+// schema without options, such as a cached one, and for values Validate rejects.
+func NewOptions(ds notion.DataSource, properties map[string]any) []string {
+	idx := NewIndex(ds)
+	var notes []string
+	for _, key := range sortedKeys(properties) {
+		ref, ok := idx.ReferenceForName(key)
+		if !ok || len(ref.Raw) == 0 || (ref.Type != "select" && ref.Type != "multi_select") {
+			continue
+		}
+		prop, err := ref.Schema()
+		if err != nil {
+			continue
+		}
+		object, _ := properties[key].(map[string]any)
+		value := object[prop.Type]
+		if value == nil || jsonKind(value) != valueKinds[prop.Type].kind {
+			continue
+		}
+		if missing := missingOptions(prop, value); len(missing) > 0 {
+			notes = append(notes, fmt.Sprintf("%s: Notion will create the new option %s", prop.Name, strings.Join(missing, ", ")))
+		}
+	}
+	return notes
+}
+
+// missingOptions returns the quoted option names in a select, multi-select, or status value
+// that prop does not have.
+func missingOptions(prop notion.PropertySchema, value any) []string {
+	var names []string
+	switch prop.Type {
+	case "select", "status":
+		if name, ok := value.(map[string]any)["name"].(string); ok {
+			names = append(names, name)
+		}
+	case "multi_select":
+		for _, item := range value.([]any) {
+			if option, ok := item.(map[string]any); ok {
+				if name, ok := option["name"].(string); ok {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+
+	var missing []string
+	for _, name := range names {
+		found := false
+		for _, option := range prop.Options {
+			if option.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, fmt.Sprintf("%q", name))
+		}
+	}
+	return missing
+}
+
+func sortedKeys(properties map[string]any) []string {
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func jsonKind(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func withArticle(kind string) string {
+	switch kind {
+	case "array", "object":
+		return "an " + kind
+	default:
+		return "a " + kind
+	}
+}
+
+// closestName returns the candidate nearest to name by edit distance, if it is close
+// enough to be a plausible typo.
+func closestName(name string, candidates []string) string {
+	best, bestDistance := "", 0
+	target := strings.ToLower(name)
+	for _, candidate := range candidates {
+		d := editDistance(target, strings.ToLower(candidate))
+		if best == "" || d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	if best == "" || bestDistance > max(2, len([]rune(name))/3) {
+		return ""
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b, counted in runes.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,75 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	var ds notion.DataSource
+	if err := json.Unmarshal([]byte(`{"object":"data_source","id":"ds","properties":{
+		"Name":{"id":"title","name":"Name","type":"title","title":{}},
+		"Points":{"id":"p","name":"Points","type":"number","number":{"format":"number"}},
+		"Priority":{"id":"s","name":"Priority","type":"select","select":{"options":[{"name":"High"},{"name":"Low"}]}},
+		"Tags":{"id":"t","name":"Tags","type":"multi_select","multi_select":{"options":[{"name":"bug"}]}},
+		"Stage":{"id":"g","name":"Stage","type":"status","status":{"options":[{"name":"Open"},{"name":"Done"}]}},
+		"Total":{"id":"f","name":"Total","type":"formula","formula":{"expression":"1"}}}}`), &ds); err != nil {
+		t.Fatalf("decode data source: %v", err)
+	}
+
+	valid := map[string]any{
+		"Name":     map[string]any{"title": []any{map[string]any{"text": map[string]any{"content": "x"}}}},
+		"Points":   map[string]any{"number": nil},
+		"Priority": map[string]any{"select": map[string]any{"name": "High"}},
+		"Tags":     map[string]any{"multi_select": []any{map[string]any{"name": "bug"}}},
+		"Stage":    map[string]any{"status": map[string]any{"name": "Done"}},
+	}
+	if err := schema.Validate(ds, valid); err != nil {
+		t.Fatalf("Validate(valid) = %v", err)
+	}
+	if notes := schema.NewOptions(ds, valid); len(notes) != 0 {
+		t.Fatalf("NewOptions(valid) = %v", notes)
+	}
+
+	// Notion creates select and multi-select options on write, so new ones are only noted.
+	created := map[string]any{
+		"Priority": map[string]any{"select": map[string]any{"name": "Urgent"}},
+		"Tags":     map[string]any{"multi_select": []any{map[string]any{"name": "bug"}, map[string]any{"name": "feature"}}},
+	}
+	if err := schema.Validate(ds, created); err != nil {
+		t.Fatalf("Validate(new options) = %v", err)
+	}
+	notes := schema.NewOptions(ds, created)
+	if len(notes) != 2 || notes[0] != `Priority: Notion will create the new option "Urgent"` ||
+		notes[1] != `Tags: Notion will create the new option "feature"` {
+		t.Fatalf("NewOptions = %q", notes)
+	}
+
+	err := schema.Validate(ds, map[string]any{
+		"Pionts":   map[string]any{"number": 3.0},
+		"Points":   map[string]any{"number": "3"},
+		"Priority": map[string]any{"multi_select": []any{}},
+		"Stage":    map[string]any{"status": map[string]any{"name": "Blocked"}},
+		"Total":    map[string]any{"formula": map[string]any{}},
+	})
+	if err == nil {
+		t.Fatalf("expected Validate to fail")
+	}
+	for _, want := range []string{
+		`unknown property "Pionts" (did you mean "Points"?)`,
+		"Points: number properties take a number",
+		"Priority: this is a select property, but the value is given as multi_select",
+		`Stage: no option "Blocked" (options: Open, Done)`,
+		"Total: formula properties are computed by Notion",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error missing %q:\n%v", want, err)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package sqlquery
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Row returns a column's values for the row being evaluated. Multi-valued properties such
+// as multi-selects return one value per item; an empty result is NULL.
+type Row func(column string) []string
+
+// Expr is a parsed WHERE condition.
+type Expr interface {
+	// Eval reports whether row satisfies the condition. As in SQL, comparisons with NULL
+	// are false, so NOT only matches rows whose columns have values.
+	Eval(row Row) bool
+	// Columns reports the columns the condition reads.
+	Columns() []string
+}
+
+// And matches rows satisfying both sides.
+type And struct{ Left, Right Expr }
+
+// Eval implements Expr.
+func (e And) Eval(row Row) bool { return e.Left.Eval(row) && e.Right.Eval(row) }
+
+// Columns implements Expr.
+func (e And) Columns() []string { return append(e.Left.Columns(), e.Right.Columns()...) }
+
+// Or matches rows satisfying either side.
+type Or struct{ Left, Right Expr }
+
+// Eval implements Expr.
+func (e Or) Eval(row Row) bool { return e.Left.Eval(row) || e.Right.Eval(row) }
+
+// Columns implements Expr.
+func (e Or) Columns() []string { return append(e.Left.Columns(), e.Right.Columns()...) }
+
+// Not negates a condition.
+type Not struct{ Expr Expr }
+
+// Eval implements Expr.
+func (e Not) Eval(row Row) bool { return !e.Expr.Eval(row) }
+
+// Columns implements Expr.
+func (e Not) Columns() []string { return e.Expr.Columns() }
+
+// Comparison compares a column with a literal using =, !=, <, <=, >, or >=. Values that both
+// parse as numbers compare numerically, others as text. A multi-valued column matches when
+// any value does, except != which requires that no value is equal.
+type Comparison struct {
+	Column string
+	Op     string
+	Value  string
+}
+
+// Eval implements Expr.
+func (e Comparison) Eval(row Row) bool {
+	values := row(e.Column)
+	if len(values) == 0 {
+		return false
+	}
+	if e.Op == "!=" {
+		for _, value := range values {
+			if compareValues(value, e.Value) == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	for _, value := range values {
+		cmp := compareValues(value, e.Value)
+		switch e.Op {
+		case "=":
+			if cmp == 0 {
+				return true
+			}
+		case "<":
+			if cmp < 0 {
+				return true
+			}
+		case "<=":
+			if cmp <= 0 {
+				return true
+			}
+		case ">":
+			if cmp > 0 {
+				return true
+			}
+		case ">=":
+			if cmp >= 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Columns implements Expr.
+func (e Comparison) Columns() []string { return []string{e.Column} }
+
+func compareValues(a, b string) int {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// Like matches a column against a pattern where % matches any run of characters and _ any
+// single character. Matching ignores case.
+type Like struct {
+	Column  string
+	Pattern string
+	Negate  bool
+}
+
+// Eval implements Expr.
+func (e Like) Eval(row Row) bool {
+	values := row(e.Column)
+	if len(values) == 0 {
+		return false
+	}
+	pattern := []rune(strings.ToLower(e.Pattern))
+	for _, value := range values {
+		if likeMatch(pattern, []rune(strings.ToLower(value))) {
+			return !e.Negate
+		}
+	}
+	return e.Negate
+}
+
+// Columns implements Expr.
+func (e Like) Columns() []string { return []string{e.Column} }
+
+// Simple describes the pattern as one text test when its only wildcards are a leading or
+// trailing %: "equals", "starts_with", "ends_with", or "contains", with the literal text.
+func (e Like) Simple() (string, string, bool) {
+	text := e.Pattern
+	leading := strings.HasPrefix(text, "%")
+	trailing := len(text) > 1 && strings.HasSuffix(text, "%")
+	text = strings.TrimPrefix(text, "%")
+	if trailing {
+		text = strings.TrimSuffix(text, "%")
+	}
+	if text == "" || strings.ContainsAny(text, "%_") {
+		return "", "", false
+	}
+	switch {
+	case leading && trailing:
+		return "contains", text, true
+	case leading:
+		return "ends_with", text, true
+	case trailing:
+		return "starts_with", text, true
+	default:
+		return "equals", text, true
+	}
+}
+
+// likeMatch is wildcard matching with backtracking to the most recent %.
+func likeMatch(pattern, text []rune) bool {
+	p, t := 0, 0
+	star, mark := -1, 0
+	for t < len(text) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '_' || pattern[p] == text[t] ||
+			unicode.ToLower(pattern[p]) == unicode.ToLower(text[t])):
+			p++
+			t++
+		case p < len(pattern) && pattern[p] == '%':
+			star, mark = p, t
+			p++
+		case star >= 0:
+			mark++
+			p, t = star+1, mark
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '%' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// In matches a column whose value is one of Values.
+type In struct {
+	Column string
+	Values []string
+	Negate bool
+}
+
+// Eval implements Expr.
+func (e In) Eval(row Row) bool {
+	values := row(e.Column)
+	if len(values) == 0 {
+		return false
+	}
+	for _, value := range values {
+		for _, candidate := range e.Values {
+			if compareValues(value, candidate) == 0 {
+				return !e.Negate
+			}
+		}
+	}
+	return e.Negate
+}
+
+// Columns implements Expr.
+func (e In) Columns() []string { return []string{e.Column} }
+
+// IsNull matches a column without values, or with Negate one that has any.
+type IsNull struct {
+	Column string
+	Negate bool
+}
+
+// Eval implements Expr.
+func (e IsNull) Eval(row Row) bool { return (len(row(e.Column)) == 0) != e.Negate }
+
+// Columns implements Expr.
+func (e IsNull) Columns() []string { return []string{e.Column} }
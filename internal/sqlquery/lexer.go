@@ -0,0 +1,117 @@
+package sqlquery
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenIdent
+	tokenSymbol
+)
+
+type token struct {
+	text string
+	kind tokenKind
+	pos  int
+}
+
+func (t token) describe() string {
+	if t.kind == tokenEOF {
+		return "end of query"
+	}
+	return fmt.Sprintf("%q at offset %d", t.text, t.pos)
+}
+
+// isKeyword reports whether the token is a bare word matching keyword (case-insensitive).
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+func (t token) isSymbol(symbol string) bool {
+	return t.kind == tokenSymbol && t.text == symbol
+}
+
+// tokenize splits a query into words, quoted strings ('...' or "..."), quoted identifiers
+// (`...` or [...]), and symbols.
+func tokenize(input string) ([]token, error) {
+	runes := []rune(input)
+	var tokens []token
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			text, next, err := readQuoted(runes, i, r)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: i})
+			i = next
+		case r == '`' || r == '[':
+			closing := r
+			if r == '[' {
+				closing = ']'
+			}
+			text, next, err := readQuoted(runes, i, closing)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: text, pos: i})
+			i = next
+		case r == ',' || r == '(' || r == ')' || r == '*' || r == ';':
+			tokens = append(tokens, token{kind: tokenSymbol, text: string(r), pos: i})
+			i++
+		case isOperatorRune(r):
+			start := i
+			for i < len(runes) && isOperatorRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenSymbol, text: string(runes[start:i]), pos: start})
+		default:
+			start := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[start:i]), pos: start})
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+// readQuoted reads up to the closing rune; a doubled closing rune or a backslash escapes it.
+func readQuoted(runes []rune, start int, closing rune) (string, int, error) {
+	var b strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\\' && i+1 < len(runes):
+			i++
+			b.WriteRune(runes[i])
+		case runes[i] == closing && i+1 < len(runes) && runes[i+1] == closing:
+			i++
+			b.WriteRune(closing)
+		case runes[i] == closing:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteRune(runes[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated quote starting at offset %d", start)
+}
+
+func isOperatorRune(r rune) bool {
+	return r == '=' || r == '!' || r == '<' || r == '>'
+}
+
+func isWordRune(r rune) bool {
+	return !unicode.IsSpace(r) && !isOperatorRune(r) && !strings.ContainsRune(",()*;'\"`[", r)
+}
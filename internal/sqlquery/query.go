@@ -0,0 +1,364 @@
+// Package sqlquery parses the SQL subset accepted by ds sql, for example:
+//
+//	SELECT Name, Status FROM @tasks WHERE Status = "Done" AND Due Date < 2025-07-01 ORDER BY Due Date DESC LIMIT 10
+//
+// Columns are property names. Names may span several words, or be quoted with backticks or
+// brackets; a double-quoted name is a column in the select list and ORDER BY, and a string
+// elsewhere. Conditions compare a column with a literal and combine with AND, OR, NOT, and
+// parentheses.
+package sqlquery
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Query is a parsed SELECT statement.
+type Query struct {
+	// Where is nil when the query has no WHERE clause.
+	Where Expr
+	From  string
+	// Columns is empty for SELECT *.
+	Columns []string
+	OrderBy []Order
+	// Limit is zero when the query has no LIMIT clause.
+	Limit int
+}
+
+// Order is one ORDER BY item.
+type Order struct {
+	Column string
+	Desc   bool
+}
+
+var keywords = map[string]bool{
+	"select": true, "from": true, "where": true, "order": true, "by": true, "asc": true, "desc": true,
+	"limit": true, "and": true, "or": true, "not": true, "like": true, "in": true, "is": true, "null": true,
+}
+
+// Parse parses a single SELECT statement.
+func Parse(source string) (*Query, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	query, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().isSymbol(";") {
+		p.next()
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s", tok.describe())
+	}
+	return query, nil
+}
+
+// ParseCondition parses a bare WHERE condition, such as Status = "Done" AND Points > 3.
+func ParseCondition(source string) (Expr, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s", tok.describe())
+	}
+	return expr, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) expectKeyword(keyword string) error {
+	if tok := p.next(); !tok.isKeyword(keyword) {
+		return fmt.Errorf("expected %s but found %s", strings.ToUpper(keyword), tok.describe())
+	}
+	return nil
+}
+
+func (p *parser) parseQuery() (*Query, error) {
+	if err := p.expectKeyword("select"); err != nil {
+		return nil, err
+	}
+	query := &Query{}
+	if p.peek().isSymbol("*") {
+		p.next()
+	} else {
+		for {
+			column, err := p.parseColumn(true)
+			if err != nil {
+				return nil, err
+			}
+			query.Columns = append(query.Columns, column)
+			if !p.peek().isSymbol(",") {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if err := p.expectKeyword("from"); err != nil {
+		return nil, err
+	}
+	from := p.next()
+	if from.kind != tokenWord && from.kind != tokenString && from.kind != tokenIdent {
+		return nil, fmt.Errorf("expected a data source after FROM but found %s", from.describe())
+	}
+	query.From = from.text
+
+	if p.peek().isKeyword("where") {
+		p.next()
+		where, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		query.Where = where
+	}
+	if p.peek().isKeyword("order") {
+		p.next()
+		if err := p.expectKeyword("by"); err != nil {
+			return nil, err
+		}
+		order, err := p.parseOrderBy()
+		if err != nil {
+			return nil, err
+		}
+		query.OrderBy = order
+	}
+	if p.peek().isKeyword("limit") {
+		p.next()
+		tok := p.next()
+		limit, err := strconv.Atoi(tok.text)
+		if tok.kind != tokenWord || err != nil || limit <= 0 {
+			return nil, fmt.Errorf("LIMIT expects a positive number but found %s", tok.describe())
+		}
+		query.Limit = limit
+	}
+	return query, nil
+}
+
+// parseColumn reads a column name: a quoted identifier, consecutive non-keyword words joined
+// by single spaces, or, where quotedStrings is set, a double- or single-quoted string.
+func (p *parser) parseColumn(quotedStrings bool) (string, error) {
+	tok := p.peek()
+	switch {
+	case tok.kind == tokenIdent, quotedStrings && tok.kind == tokenString:
+		p.next()
+		return tok.text, nil
+	case tok.kind != tokenWord || keywords[strings.ToLower(tok.text)]:
+		return "", fmt.Errorf("expected a column name but found %s", tok.describe())
+	}
+	var words []string
+	for tok := p.peek(); tok.kind == tokenWord && !keywords[strings.ToLower(tok.text)]; tok = p.peek() {
+		words = append(words, p.next().text)
+	}
+	return strings.Join(words, " "), nil
+}
+
+func (p *parser) parseOrderBy() ([]Order, error) {
+	var order []Order
+	for {
+		column, err := p.parseColumn(true)
+		if err != nil {
+			return nil, err
+		}
+		item := Order{Column: column}
+		switch {
+		case p.peek().isKeyword("desc"):
+			p.next()
+			item.Desc = true
+		case p.peek().isKeyword("asc"):
+			p.next()
+		}
+		order = append(order, item)
+		if !p.peek().isSymbol(",") {
+			return order, nil
+		}
+		p.next()
+	}
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	switch tok := p.peek(); {
+	case tok.isKeyword("not"):
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Expr: inner}, nil
+	case tok.isSymbol("("):
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); !tok.isSymbol(")") {
+			return nil, fmt.Errorf("expected ) but found %s", tok.describe())
+		}
+		return inner, nil
+	}
+	return p.parsePredicate()
+}
+
+var comparisonOperators = map[string]string{
+	"=": "=", "==": "=", "!=": "!=", "<>": "!=", "<": "<", "<=": "<=", ">": ">", ">=": ">=",
+}
+
+func (p *parser) parsePredicate() (Expr, error) {
+	column, err := p.parseColumn(false)
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.next()
+	if tok.kind == tokenSymbol {
+		op, ok := comparisonOperators[tok.text]
+		if !ok {
+			return nil, fmt.Errorf("unknown operator %s", tok.describe())
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return Comparison{Column: column, Op: op, Value: value}, nil
+	}
+
+	negate := false
+	if tok.isKeyword("is") {
+		if p.peek().isKeyword("not") {
+			p.next()
+			negate = true
+		}
+		if err := p.expectKeyword("null"); err != nil {
+			return nil, err
+		}
+		return IsNull{Column: column, Negate: negate}, nil
+	}
+	if tok.isKeyword("not") {
+		negate = true
+		tok = p.next()
+	}
+	switch {
+	case tok.isKeyword("like"):
+		pattern, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return Like{Column: column, Pattern: pattern, Negate: negate}, nil
+	case tok.isKeyword("in"):
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return In{Column: column, Values: values, Negate: negate}, nil
+	}
+	return nil, fmt.Errorf("column %q: expected an operator but found %s", column, tok.describe())
+}
+
+func (p *parser) parseValue() (string, error) {
+	tok := p.next()
+	if tok.kind != tokenString && (tok.kind != tokenWord || keywords[strings.ToLower(tok.text)]) {
+		return "", fmt.Errorf("expected a value but found %s", tok.describe())
+	}
+	return tok.text, nil
+}
+
+func (p *parser) parseList() ([]string, error) {
+	if tok := p.next(); !tok.isSymbol("(") {
+		return nil, fmt.Errorf("IN expects ( but found %s", tok.describe())
+	}
+	var values []string
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		tok := p.next()
+		if tok.isSymbol(")") {
+			return values, nil
+		}
+		if !tok.isSymbol(",") {
+			return nil, fmt.Errorf("expected , or ) but found %s", tok.describe())
+		}
+	}
+}
+
+// ReferencedColumns reports every column the query reads, in order of first use.
+func (q *Query) ReferencedColumns() []string {
+	var columns []string
+	seen := map[string]bool{}
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			columns = append(columns, name)
+		}
+	}
+	for _, column := range q.Columns {
+		add(column)
+	}
+	if q.Where != nil {
+		for _, column := range q.Where.Columns() {
+			add(column)
+		}
+	}
+	for _, order := range q.OrderBy {
+		add(order.Column)
+	}
+	return columns
+}
@@ -0,0 +1,131 @@
+package sqlquery_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yourorg/notionctl/internal/sqlquery"
+)
+
+func TestParse(t *testing.T) {
+	query, err := sqlquery.Parse(
+		`select Name, "Due Date" from @tasks where Status = 'Done' and [Due Date] < 2025-07-01 ` +
+			`order by Due Date desc, Name limit 5;`,
+	)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	want := &sqlquery.Query{
+		Columns: []string{"Name", "Due Date"},
+		From:    "@tasks",
+		Where: sqlquery.And{
+			Left:  sqlquery.Comparison{Column: "Status", Op: "=", Value: "Done"},
+			Right: sqlquery.Comparison{Column: "Due Date", Op: "<", Value: "2025-07-01"},
+		},
+		OrderBy: []sqlquery.Order{{Column: "Due Date", Desc: true}, {Column: "Name"}},
+		Limit:   5,
+	}
+	if !reflect.DeepEqual(query, want) {
+		t.Fatalf("Parse = %+v, want %+v", query, want)
+	}
+	if got := query.ReferencedColumns(); !reflect.DeepEqual(got, []string{"Name", "Due Date", "Status"}) {
+		t.Fatalf("ReferencedColumns = %v", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, source := range []string{
+		`SELECT FROM tasks`,
+		`SELECT * tasks`,
+		`SELECT * FROM tasks WHERE Status`,
+		`SELECT * FROM tasks WHERE Status ~ 1`,
+		`SELECT * FROM tasks WHERE Status IN Done`,
+		`SELECT * FROM tasks WHERE (Status = Done`,
+		`SELECT * FROM tasks LIMIT 0`,
+		`SELECT * FROM tasks WHERE Name = 'open`,
+		`SELECT * FROM tasks extra`,
+	} {
+		if _, err := sqlquery.Parse(source); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", source)
+		}
+	}
+}
+
+func TestEval(t *testing.T) {
+	values := map[string][]string{
+		"Name":   {"Fix login bug"},
+		"Points": {"8"},
+		"Tags":   {"backend", "urgent"},
+	}
+	row := sqlquery.Row(func(column string) []string { return values[column] })
+
+	cases := map[string]bool{
+		`Points > 10`:                                       false,
+		`Points >= 8 AND Points < 10`:                       true,
+		`Tags = urgent`:                                     true,
+		`Tags != urgent`:                                    false,
+		`Tags IN ('frontend', 'backend')`:                   true,
+		`Tags NOT IN ('frontend')`:                          true,
+		`Name LIKE 'fix%'`:                                  true,
+		`Name LIKE '%LOGIN_bug'`:                            true,
+		`Name NOT LIKE '%bug'`:                              false,
+		`Owner IS NULL`:                                     true,
+		`Owner IS NOT NULL OR Points = 8`:                   true,
+		`Owner = someone OR NOT (Points = 8)`:               false,
+		`NOT Owner = someone`:                               true,
+		`Owner != someone`:                                  false,
+		`(Tags = backend OR Tags = web) AND NOT Points < 5`: true,
+	}
+	for where, want := range cases {
+		query, err := sqlquery.Parse("SELECT * FROM tasks WHERE " + where)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", where, err)
+		}
+		if got := query.Where.Eval(row); got != want {
+			t.Errorf("%s = %v, want %v", where, got, want)
+		}
+	}
+}
+
+func TestLikeSimple(t *testing.T) {
+	cases := map[string][2]string{
+		"fix%":    {"starts_with", "fix"},
+		"%bug":    {"ends_with", "bug"},
+		"%login%": {"contains", "login"},
+		"Done":    {"equals", "Done"},
+		"f_x%":    {"", ""},
+		"%":       {"", ""},
+	}
+	for pattern, want := range cases {
+		op, text, ok := sqlquery.Like{Pattern: pattern}.Simple()
+		if ok != (want[0] != "") || op != want[0] || text != want[1] {
+			t.Errorf("Simple(%q) = %q, %q, %v, want %q, %q", pattern, op, text, ok, want[0], want[1])
+		}
+	}
+}
+
+func TestParseCondition(t *testing.T) {
+	expr, err := sqlquery.ParseCondition(`Status = "Done" AND Points > 3`)
+	if err != nil {
+		t.Fatalf("ParseCondition returned error: %v", err)
+	}
+	row := func(values map[string]string) sqlquery.Row {
+		return func(column string) []string {
+			if v, ok := values[column]; ok {
+				return []string{v}
+			}
+			return nil
+		}
+	}
+	if !expr.Eval(row(map[string]string{"Status": "Done", "Points": "5"})) {
+		t.Fatal("expected match")
+	}
+	if expr.Eval(row(map[string]string{"Status": "Done", "Points": "2"})) {
+		t.Fatal("expected no match")
+	}
+	for _, source := range []string{``, `Status =`, `Status = Done LIMIT 3`} {
+		if _, err := sqlquery.ParseCondition(source); err == nil {
+			t.Errorf("ParseCondition(%q) succeeded, want error", source)
+		}
+	}
+}
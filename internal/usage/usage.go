@@ -0,0 +1,138 @@
+// Package usage records which notionctl commands run and how long they take in a local JSON
+// Lines file, and summarizes that log. It never sends data anywhere.
+package usage
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/filelock"
+)
+
+const (
+	dirPermissions  = 0o700
+	filePermissions = 0o600
+)
+
+// Entry is one command invocation. Only flag names are kept; values may hold filters,
+// page content, or secrets.
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Command    string    `json:"command"`
+	Flags      []string  `json:"flags,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	Failed     bool      `json:"failed,omitempty"`
+}
+
+// Summary aggregates the entries for one command.
+type Summary struct {
+	Flags    map[string]int `json:"flags,omitempty"`
+	Command  string         `json:"command"`
+	Runs     int            `json:"runs"`
+	Failures int            `json:"failures"`
+	MedianMS int64          `json:"median_ms"`
+	P90MS    int64          `json:"p90_ms"`
+	MaxMS    int64          `json:"max_ms"`
+	TotalMS  int64          `json:"total_ms"`
+}
+
+// Append adds entry to the log at path, creating it if needed.
+func Append(path string, entry Entry) error {
+	if err := os.MkdirAll(filepath.Dir(path), dirPermissions); err != nil {
+		return fmt.Errorf("create usage log directory: %w", err)
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encode usage entry: %w", err)
+	}
+	if err := filelock.Append(path, append(line, '\n'), filePermissions); err != nil {
+		return fmt.Errorf("append usage log: %w", err)
+	}
+	return nil
+}
+
+// Load reads the entries recorded at or after since. A missing log has no entries, and
+// lines that fail to decode are skipped.
+func Load(path string, since time.Time) ([]Entry, error) {
+	f, err := os.Open(path) // #nosec G304 -- path is in the config directory
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open usage log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if entry.Time.Before(since) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read usage log: %w", err)
+	}
+	return entries, nil
+}
+
+// Summarize groups entries by command, most-run first (ties by name).
+func Summarize(entries []Entry) []Summary {
+	byCommand := map[string]*Summary{}
+	durations := map[string][]int64{}
+	for _, entry := range entries {
+		s := byCommand[entry.Command]
+		if s == nil {
+			s = &Summary{Command: entry.Command}
+			byCommand[entry.Command] = s
+		}
+		s.Runs++
+		if entry.Failed {
+			s.Failures++
+		}
+		s.TotalMS += entry.DurationMS
+		durations[entry.Command] = append(durations[entry.Command], entry.DurationMS)
+		for _, flag := range entry.Flags {
+			if s.Flags == nil {
+				s.Flags = map[string]int{}
+			}
+			s.Flags[flag]++
+		}
+	}
+
+	out := make([]Summary, 0, len(byCommand))
+	for command, s := range byCommand {
+		d := durations[command]
+		slices.Sort(d)
+		s.MedianMS = percentile(d, 50)
+		s.P90MS = percentile(d, 90)
+		s.MaxMS = d[len(d)-1]
+		out = append(out, *s)
+	}
+	slices.SortFunc(out, func(a, b Summary) int {
+		if a.Runs != b.Runs {
+			return b.Runs - a.Runs
+		}
+		return cmp.Compare(a.Command, b.Command)
+	})
+	return out
+}
+
+// percentile uses the nearest-rank method on sorted values.
+func percentile(sorted []int64, p int) int64 {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
@@ -0,0 +1,71 @@
+package usage_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/usage"
+)
+
+func TestAppendLoadAndSummarize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "usage.jsonl")
+	start := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	if entries, err := usage.Load(path, time.Time{}); err != nil || len(entries) != 0 {
+		t.Fatalf("expected an empty log, got %v %v", entries, err)
+	}
+
+	records := []usage.Entry{
+		{Time: start, Command: "pages get", DurationMS: 50},
+		{Time: start.Add(time.Hour), Command: "ds query", Flags: []string{"where", "format"}, DurationMS: 300},
+		{Time: start.Add(2 * time.Hour), Command: "ds query", Flags: []string{"where"}, DurationMS: 100},
+		{Time: start.Add(3 * time.Hour), Command: "ds query", DurationMS: 900, Failed: true},
+	}
+	for _, entry := range records {
+		if err := usage.Append(path, entry); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat log: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("expected 0600 permissions, got %o", perm)
+	}
+
+	entries, err := usage.Load(path, start.Add(30*time.Minute))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected entries after since only, got %d", len(entries))
+	}
+
+	summaries := usage.Summarize(append(entries, records[0]))
+	if len(summaries) != 2 || summaries[0].Command != "ds query" || summaries[1].Command != "pages get" {
+		t.Fatalf("expected most-run first, got %+v", summaries)
+	}
+	query := summaries[0]
+	if query.Runs != 3 || query.Failures != 1 || query.MedianMS != 300 || query.P90MS != 900 || query.MaxMS != 900 {
+		t.Fatalf("unexpected ds query summary: %+v", query)
+	}
+	if query.Flags["where"] != 2 || query.Flags["format"] != 1 {
+		t.Fatalf("unexpected flag counts: %v", query.Flags)
+	}
+}
+
+func TestLoadSkipsCorruptLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.jsonl")
+	data := "{\"command\":\"ds list\",\"duration_ms\":5}\nnot json\n{\"command\":\"ds list\",\"duration_ms\":7}\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+	entries, err := usage.Load(path, time.Time{})
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d (%v)", len(entries), err)
+	}
+}
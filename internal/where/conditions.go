@@ -0,0 +1,231 @@
+package where
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/datemacro"
+	"github.com/yourorg/notionctl/internal/notion"
+)
+
+type operator string
+
+const (
+	opEquals      operator = "equals"
+	opNotEquals   operator = "does_not_equal"
+	opGreater     operator = "greater_than"
+	opGreaterEq   operator = "greater_than_or_equal_to"
+	opLess        operator = "less_than"
+	opLessEq      operator = "less_than_or_equal_to"
+	opContains    operator = "contains"
+	opNotContains operator = "does_not_contain"
+	opStartsWith  operator = "starts_with"
+	opEndsWith    operator = "ends_with"
+	opBefore      operator = "before"
+	opAfter       operator = "after"
+	opOnOrBefore  operator = "on_or_before"
+	opOnOrAfter   operator = "on_or_after"
+	opIsEmpty     operator = "is_empty"
+	opIsNotEmpty  operator = "is_not_empty"
+)
+
+func (op operator) unary() bool {
+	return op == opIsEmpty || op == opIsNotEmpty
+}
+
+var symbolOperators = map[string]operator{
+	"=":  opEquals,
+	"==": opEquals,
+	"!=": opNotEquals,
+	">":  opGreater,
+	">=": opGreaterEq,
+	"<":  opLess,
+	"<=": opLessEq,
+}
+
+var wordOperators = map[string]operator{
+	"equals":           opEquals,
+	"does_not_equal":   opNotEquals,
+	"contains":         opContains,
+	"does_not_contain": opNotContains,
+	"starts_with":      opStartsWith,
+	"ends_with":        opEndsWith,
+	"before":           opBefore,
+	"after":            opAfter,
+	"on_or_before":     opOnOrBefore,
+	"on_or_after":      opOnOrAfter,
+	"is_empty":         opIsEmpty,
+	"is_not_empty":     opIsNotEmpty,
+}
+
+type valueKind int
+
+const (
+	valueString valueKind = iota
+	valueNumber
+	valueBool
+	valueDate
+)
+
+// typeRule describes how a property type accepts operators and values.
+type typeRule struct {
+	// aliases rewrites generic operators into the type's native vocabulary.
+	aliases map[operator]operator
+	allowed map[operator]bool
+	kind    valueKind
+}
+
+func ops(list ...operator) map[operator]bool {
+	out := make(map[operator]bool, len(list))
+	for _, op := range list {
+		out[op] = true
+	}
+	return out
+}
+
+var (
+	textRule = typeRule{
+		kind: valueString,
+		allowed: ops(opEquals, opNotEquals, opContains, opNotContains, opStartsWith, opEndsWith,
+			opIsEmpty, opIsNotEmpty),
+	}
+	numberRule = typeRule{
+		kind:    valueNumber,
+		allowed: ops(opEquals, opNotEquals, opGreater, opGreaterEq, opLess, opLessEq, opIsEmpty, opIsNotEmpty),
+	}
+	checkboxRule = typeRule{kind: valueBool, allowed: ops(opEquals, opNotEquals)}
+	selectRule   = typeRule{kind: valueString, allowed: ops(opEquals, opNotEquals, opIsEmpty, opIsNotEmpty)}
+	listRule     = typeRule{
+		kind:    valueString,
+		aliases: map[operator]operator{opEquals: opContains, opNotEquals: opNotContains},
+		allowed: ops(opContains, opNotContains, opIsEmpty, opIsNotEmpty),
+	}
+	dateRule = typeRule{
+		kind: valueDate,
+		aliases: map[operator]operator{
+			opGreater:   opAfter,
+			opGreaterEq: opOnOrAfter,
+			opLess:      opBefore,
+			opLessEq:    opOnOrBefore,
+		},
+		allowed: ops(opEquals, opBefore, opAfter, opOnOrBefore, opOnOrAfter, opIsEmpty, opIsNotEmpty),
+	}
+	filesRule = typeRule{allowed: ops(opIsEmpty, opIsNotEmpty)}
+)
+
+var rulesByType = map[string]typeRule{
+	"title":            textRule,
+	"rich_text":        textRule,
+	"url":              textRule,
+	"email":            textRule,
+	"phone_number":     textRule,
+	"number":           numberRule,
+	"unique_id":        numberRule,
+	"checkbox":         checkboxRule,
+	"select":           selectRule,
+	"status":           selectRule,
+	"multi_select":     listRule,
+	"people":           listRule,
+	"relation":         listRule,
+	"created_by":       listRule,
+	"last_edited_by":   listRule,
+	"date":             dateRule,
+	"created_time":     dateRule,
+	"last_edited_time": dateRule,
+	"files":            filesRule,
+}
+
+// filterKey returns the condition key Notion expects for a property type.
+func filterKey(propType string) string {
+	switch propType {
+	case "created_by", "last_edited_by":
+		return "people"
+	default:
+		return propType
+	}
+}
+
+func buildCondition(ref notion.PropertyReference, op operator, raw string) (map[string]any, error) {
+	rule, ok := rulesByType[ref.Type]
+	if !ok {
+		return nil, fmt.Errorf("property %q has type %s, which --where does not support", ref.Name, ref.Type)
+	}
+	if alias, ok := rule.aliases[op]; ok {
+		op = alias
+	}
+	if !rule.allowed[op] {
+		return nil, fmt.Errorf("operator %s is not supported for %s property %q", op, ref.Type, ref.Name)
+	}
+
+	var value any = true
+	if !op.unary() {
+		coerced, err := coerceValue(rule.kind, ref, raw)
+		if err != nil {
+			return nil, err
+		}
+		value = coerced
+	}
+
+	condition := map[string]any{filterKey(ref.Type): map[string]any{string(op): value}}
+	if isTimestamp(ref.Type) {
+		condition["timestamp"] = ref.Type
+	} else {
+		condition["property"] = ref.ID
+	}
+	return condition, nil
+}
+
+// isTimestamp reports whether a property type is a page timestamp, which Notion filters by
+// timestamp rather than by property: {"timestamp": "created_time", "created_time": {...}}.
+func isTimestamp(propType string) bool {
+	return propType == "created_time" || propType == "last_edited_time"
+}
+
+func coerceValue(kind valueKind, ref notion.PropertyReference, raw string) (any, error) {
+	switch kind {
+	case valueNumber:
+		return coerceNumber(ref, raw)
+	case valueBool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("property %q expects true or false, got %q", ref.Name, raw)
+		}
+		return b, nil
+	case valueDate:
+		return coerceDate(ref, raw)
+	default:
+		return raw, nil
+	}
+}
+
+func coerceNumber(ref notion.PropertyReference, raw string) (any, error) {
+	text := raw
+	if ref.Type == "unique_id" {
+		if idx := strings.LastIndex(text, "-"); idx >= 0 {
+			text = text[idx+1:]
+		}
+	}
+	n, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, fmt.Errorf("property %q expects a number, got %q", ref.Name, raw)
+	}
+	return n, nil
+}
+
+func coerceDate(ref notion.PropertyReference, raw string) (any, error) {
+	if datemacro.IsMacro(raw) {
+		expanded, err := datemacro.Expand(raw, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("property %q: %w", ref.Name, err)
+		}
+		return expanded, nil
+	}
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if _, err := time.Parse(layout, raw); err == nil {
+			return raw, nil
+		}
+	}
+	return nil, fmt.Errorf("property %q expects a date (YYYY-MM-DD, RFC3339, or a macro like @today), got %q", ref.Name, raw)
+}
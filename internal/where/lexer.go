@@ -0,0 +1,103 @@
+package where
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenSymbol
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	text string
+	kind tokenKind
+	pos  int
+}
+
+func (t token) describe() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q at offset %d", t.text, t.pos)
+}
+
+// isKeyword reports whether the token is a bare word matching keyword (case-insensitive).
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+func tokenize(input string) ([]token, error) {
+	runes := []rune(input)
+	tokens := make([]token, 0, len(runes)/2)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case r == '"' || r == '\'':
+			text, next, err := readQuoted(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: i})
+			i = next
+		case isSymbolRune(r):
+			start := i
+			for i < len(runes) && isSymbolRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenSymbol, text: string(runes[start:i]), pos: start})
+		default:
+			start := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[start:i]), pos: start})
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+func readQuoted(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	var b strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				b.WriteRune(runes[i])
+			}
+		case quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteRune(runes[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string starting at offset %d", start)
+}
+
+func isSymbolRune(r rune) bool {
+	return r == '=' || r == '!' || r == '<' || r == '>'
+}
+
+func isWordRune(r rune) bool {
+	return !unicode.IsSpace(r) && r != '(' && r != ')' && r != '"' && r != '\'' && !isSymbolRune(r)
+}
@@ -0,0 +1,226 @@
+// Package where compiles human-friendly filter expressions into Notion filter payloads.
+//
+// Expressions combine conditions with AND/OR and parentheses, for example:
+//
+//	Status = "Done" AND (Due before 2025-01-01 OR Priority >= 2)
+//
+// Property names are resolved through the data source schema so each condition can be
+// translated into the type-specific filter object the Notion API expects.
+package where
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+)
+
+// Compile parses expr and returns the equivalent Notion filter object.
+func Compile(expr string, idx *schema.Index) (map[string]any, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, errors.New("where expression is empty")
+	}
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, idx: idx}
+	filter, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s", tok.describe())
+	}
+	return filter, nil
+}
+
+type parser struct {
+	idx    *schema.Index
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) parseOr() (map[string]any, error) {
+	return p.parseChain("or", p.parseAnd)
+}
+
+func (p *parser) parseAnd() (map[string]any, error) {
+	return p.parseChain("and", p.parsePrimary)
+}
+
+// parseChain collects operands joined by keyword into a single compound filter.
+func (p *parser) parseChain(keyword string, operand func() (map[string]any, error)) (map[string]any, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	items := []any{first}
+	for p.peek().isKeyword(keyword) {
+		p.next()
+		item, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		if nested, ok := item[keyword].([]any); ok && len(item) == 1 {
+			items = append(items, nested...)
+			continue
+		}
+		items = append(items, item)
+	}
+	if len(items) == 1 {
+		return first, nil
+	}
+	return map[string]any{keyword: items}, nil
+}
+
+func (p *parser) parsePrimary() (map[string]any, error) {
+	if p.peek().kind == tokenLParen {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokenRParen {
+			return nil, fmt.Errorf("expected ) but found %s", tok.describe())
+		}
+		return inner, nil
+	}
+	return p.parseCondition()
+}
+
+func (p *parser) parseCondition() (map[string]any, error) {
+	propTok := p.next()
+	if propTok.kind != tokenWord && propTok.kind != tokenString {
+		return nil, fmt.Errorf("expected property name but found %s", propTok.describe())
+	}
+	ref, ok := p.idx.ReferenceForName(propTok.text)
+	if !ok {
+		return nil, fmt.Errorf("unknown property %q", propTok.text)
+	}
+
+	op, err := p.parseOperator()
+	if err != nil {
+		return nil, fmt.Errorf("property %q: %w", ref.Name, err)
+	}
+
+	var raw string
+	if !op.unary() {
+		valueTok := p.next()
+		if valueTok.kind != tokenWord && valueTok.kind != tokenString {
+			return nil, fmt.Errorf("property %q: expected value but found %s", ref.Name, valueTok.describe())
+		}
+		raw = valueTok.text
+	}
+
+	return buildCondition(ref, op, raw)
+}
+
+func (p *parser) parseOperator() (operator, error) {
+	tok := p.next()
+	if tok.kind == tokenSymbol {
+		op, ok := symbolOperators[tok.text]
+		if !ok {
+			return "", fmt.Errorf("unknown operator %s", tok.describe())
+		}
+		return op, nil
+	}
+	if tok.kind != tokenWord {
+		return "", fmt.Errorf("expected operator but found %s", tok.describe())
+	}
+
+	word := strings.ToLower(tok.text)
+	switch word {
+	case "is":
+		return p.parseIsOperator()
+	case "not":
+		if err := p.expectKeyword("contains"); err != nil {
+			return "", err
+		}
+		return opNotContains, nil
+	case "does":
+		if err := p.expectKeyword("not"); err != nil {
+			return "", err
+		}
+		if err := p.expectKeyword("contain"); err != nil {
+			return "", err
+		}
+		return opNotContains, nil
+	case "starts", "ends":
+		if err := p.expectKeyword("with"); err != nil {
+			return "", err
+		}
+		if word == "starts" {
+			return opStartsWith, nil
+		}
+		return opEndsWith, nil
+	}
+
+	op, ok := wordOperators[word]
+	if !ok {
+		return "", fmt.Errorf("unknown operator %s", tok.describe())
+	}
+	return op, nil
+}
+
+func (p *parser) parseIsOperator() (operator, error) {
+	negate := false
+	if p.peek().isKeyword("not") {
+		p.next()
+		negate = true
+	}
+	if err := p.expectKeyword("empty"); err != nil {
+		return "", err
+	}
+	if negate {
+		return opIsNotEmpty, nil
+	}
+	return opIsEmpty, nil
+}
+
+func (p *parser) expectKeyword(keyword string) error {
+	tok := p.next()
+	if !tok.isKeyword(keyword) {
+		return fmt.Errorf("expected %q but found %s", keyword, tok.describe())
+	}
+	return nil
+}
+
+// Equals builds a single equality condition for ref, matching how a --where "Prop = value"
+// clause would compile. Callers use it to look up rows by a key property.
+func Equals(ref notion.PropertyReference, value string) (map[string]any, error) {
+	return buildCondition(ref, opEquals, value)
+}
+
+// Condition builds a single condition for ref from an operator as an expression would spell
+// it, such as "=", ">=", "contains", or "is_empty". Unary operators ignore value.
+func Condition(ref notion.PropertyReference, op, value string) (map[string]any, error) {
+	parsed, ok := symbolOperators[op]
+	if !ok {
+		parsed, ok = wordOperators[strings.ToLower(op)]
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown operator %q", op)
+	}
+	return buildCondition(ref, parsed, value)
+}
+
+// IsEmpty builds a single "is empty" condition for ref.
+func IsEmpty(ref notion.PropertyReference) (map[string]any, error) {
+	return buildCondition(ref, opIsEmpty, "")
+}
@@ -0,0 +1,139 @@
+package where_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/yourorg/notionctl/internal/notion"
+	"github.com/yourorg/notionctl/internal/schema"
+	"github.com/yourorg/notionctl/internal/where"
+)
+
+func testIndex() *schema.Index {
+	return schema.NewIndex(notion.DataSource{
+		Properties: map[string]notion.PropertyReference{
+			"Name":     {ID: "title", Name: "Name", Type: "title"},
+			"Status":   {ID: "st", Name: "Status", Type: "status"},
+			"Due Date": {ID: "due", Name: "Due Date", Type: "date"},
+			"Priority": {ID: "pri", Name: "Priority", Type: "number"},
+			"Tags":     {ID: "tags", Name: "Tags", Type: "multi_select"},
+			"Done":     {ID: "done", Name: "Done", Type: "checkbox"},
+			"Created":  {ID: "ctm", Name: "Created", Type: "created_time"},
+			"Edited":   {ID: "etm", Name: "Edited", Type: "last_edited_time"},
+		},
+	})
+}
+
+func TestCompile(t *testing.T) {
+	cases := map[string]string{
+		`Status = "Done"`: `{"property":"st","status":{"equals":"Done"}}`,
+		`Status = "Done" AND "Due Date" before 2025-01-01`: `{"and":[` +
+			`{"property":"st","status":{"equals":"Done"}},` +
+			`{"date":{"before":"2025-01-01"},"property":"due"}]}`,
+		`Priority >= 2 or (Tags = CLI and Done = true)`: `{"or":[` +
+			`{"number":{"greater_than_or_equal_to":2},"property":"pri"},` +
+			`{"and":[{"multi_select":{"contains":"CLI"},"property":"tags"},` +
+			`{"checkbox":{"equals":true},"property":"done"}]}]}`,
+		`Name is not empty AND Name starts with 'Fix' AND Name does not contain wip`: `{"and":[` +
+			`{"property":"title","title":{"is_not_empty":true}},` +
+			`{"property":"title","title":{"starts_with":"Fix"}},` +
+			`{"property":"title","title":{"does_not_contain":"wip"}}]}`,
+		`"Due Date" < 2025-02-01T10:00:00Z`: `{"date":{"before":"2025-02-01T10:00:00Z"},"property":"due"}`,
+		`Created >= 2025-01-01 AND Edited is not empty`: `{"and":[` +
+			`{"created_time":{"on_or_after":"2025-01-01"},"timestamp":"created_time"},` +
+			`{"last_edited_time":{"is_not_empty":true},"timestamp":"last_edited_time"}]}`,
+	}
+
+	for expr, want := range cases {
+		got, err := where.Compile(expr, testIndex())
+		if err != nil {
+			t.Fatalf("Compile(%q) returned error: %v", expr, err)
+		}
+		encoded, err := json.Marshal(got)
+		if err != nil {
+			t.Fatalf("marshal filter: %v", err)
+		}
+		if string(encoded) != want {
+			t.Fatalf("Compile(%q) = %s, want %s", expr, encoded, want)
+		}
+	}
+}
+
+func TestCompileDateMacro(t *testing.T) {
+	before := time.Now().Add(-7*24*time.Hour - time.Second)
+	got, err := where.Compile(`"Due Date" on_or_after @now-7d`, testIndex())
+	if err != nil {
+		t.Fatalf("Compile returned error: %v", err)
+	}
+	cond := got["date"].(map[string]any)
+	value, ok := cond["on_or_after"].(string)
+	if !ok {
+		t.Fatalf("expected expanded string, got %#v", cond)
+	}
+	ts, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatalf("macro not expanded to RFC3339: %q", value)
+	}
+	if ts.Before(before) || ts.After(time.Now()) {
+		t.Fatalf("@now-7d expanded to %s", value)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`Missing = 1`,
+		`Priority = high`,
+		`Status contains "x"`,
+		`"Due Date" before soon`,
+		`(Status = Done`,
+		`Status = "Done`,
+		`Status = Done Priority`,
+		`"Due Date" after @later`,
+	} {
+		if _, err := where.Compile(expr, testIndex()); err == nil {
+			t.Fatalf("Compile(%q) expected error", expr)
+		}
+	}
+}
+
+func TestCompileAcceptsPropertyIDs(t *testing.T) {
+	got, err := where.Compile(`property_id:pri >= 2 AND "property_id:st" = Done`, testIndex())
+	if err != nil {
+		t.Fatalf("Compile returned error: %v", err)
+	}
+	encoded, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("marshal filter: %v", err)
+	}
+	want := `{"and":[{"number":{"greater_than_or_equal_to":2},"property":"pri"},` +
+		`{"property":"st","status":{"equals":"Done"}}]}`
+	if string(encoded) != want {
+		t.Fatalf("Compile = %s, want %s", encoded, want)
+	}
+}
+
+func TestCondition(t *testing.T) {
+	idx := testIndex()
+	tags, _ := idx.ReferenceForName("Tags")
+	got, err := where.Condition(tags, "!=", "CLI")
+	if err != nil {
+		t.Fatalf("Condition returned error: %v", err)
+	}
+	encoded, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("marshal filter: %v", err)
+	}
+	if want := `{"multi_select":{"does_not_contain":"CLI"},"property":"tags"}`; string(encoded) != want {
+		t.Fatalf("Condition = %s, want %s", encoded, want)
+	}
+
+	status, _ := idx.ReferenceForName("Status")
+	if _, err := where.Condition(status, "starts_with", "D"); err == nil {
+		t.Fatal("expected starts_with to be rejected for a status property")
+	}
+	if _, err := where.Condition(status, "~", "D"); err == nil {
+		t.Fatal("expected an unknown operator to be rejected")
+	}
+}